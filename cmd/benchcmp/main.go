@@ -0,0 +1,142 @@
+// Command benchcmp tracks benchmark results against a checked-in baseline.
+//
+// It reads the text output of go test -bench, from a file or standard input,
+// and either records it as a baseline:
+//
+//	go test -run '^$' -bench . -count 5 ./pkg/vybium-crypto/benchmarks | benchcmp -record baseline.json
+//
+// or compares it with one:
+//
+//	go test -run '^$' -bench . -count 5 ./pkg/vybium-crypto/benchmarks | benchcmp -baseline baseline.json
+//
+// Each benchmark is summarized by its median ns/op over its samples. A
+// comparison fails, with exit status 1, if a benchmark in the baseline is
+// missing from the run or slower than in the baseline by more than the
+// threshold. Benchmarks the baseline does not track are ignored. Usage and
+// I/O errors exit with status 2.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes benchcmp with the given arguments and returns its exit
+// status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("benchcmp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	baselinePath := flags.String("baseline", "", "compare the run with the baseline in `file`")
+	recordPath := flags.String("record", "", "record the run as a baseline in `file`")
+	threshold := flags.Float64("threshold", 0.10, "largest tolerated slowdown, as a fraction of the baseline time")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: benchcmp (-baseline file [-threshold t] | -record file) [bench-output]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*baselinePath == "") == (*recordPath == "") || flags.NArg() > 1 || *threshold < 0 {
+		flags.Usage()
+		return 2
+	}
+
+	input := stdin
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "benchcmp: %v\n", err)
+			return 2
+		}
+		defer file.Close()
+		input = file
+	}
+	current, err := parseBenchOutput(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "benchcmp: %v\n", err)
+		return 2
+	}
+
+	if *recordPath != "" {
+		if err := record(*recordPath, current); err != nil {
+			fmt.Fprintf(stderr, "benchcmp: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(stdout, "recorded %d benchmarks in %s\n", len(current.Benchmarks), *recordPath)
+		return 0
+	}
+
+	file, err := os.Open(*baselinePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "benchcmp: %v\n", err)
+		return 2
+	}
+	defer file.Close()
+	baseline, err := readResults(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "benchcmp: %s: %v\n", *baselinePath, err)
+		return 2
+	}
+	if !sameMachine(baseline, current) {
+		fmt.Fprintf(stderr, "benchcmp: warning: baseline was taken on %s/%s %q, this run on %s/%s %q\n",
+			baseline.Goos, baseline.Goarch, baseline.CPU, current.Goos, current.Goarch, current.CPU)
+	}
+
+	comparisons := compare(baseline, current)
+	if report(stdout, comparisons, *threshold) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// record writes the run to path as a baseline.
+func record(path string, current *results) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeResults(file, current); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// report prints a table of the comparisons and returns the number of
+// regressions.
+func report(w io.Writer, comparisons []comparison, threshold float64) int {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "benchmark\tbaseline\tcurrent\tdelta\t")
+	regressions := 0
+	for _, c := range comparisons {
+		status := ""
+		if c.regressed(threshold) {
+			status = "REGRESSION"
+			regressions++
+		}
+		if c.missing {
+			fmt.Fprintf(table, "%s\t%s\tmissing\t\t%s\n", c.name, formatNs(c.baseline), status)
+			continue
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%+.1f%%\t%s\n", c.name, formatNs(c.baseline), formatNs(c.current), 100*c.delta(), status)
+	}
+	table.Flush()
+	if regressions > 0 {
+		fmt.Fprintf(w, "%d of %d tracked benchmarks regressed by more than %.1f%%\n", regressions, len(comparisons), 100*threshold)
+	} else {
+		fmt.Fprintf(w, "all %d tracked benchmarks within %.1f%% of the baseline\n", len(comparisons), 100*threshold)
+	}
+	return regressions
+}
+
+func formatNs(ns float64) string {
+	return time.Duration(ns).String()
+}
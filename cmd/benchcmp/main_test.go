@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRecordThenCompare runs the local workflow: record a baseline from one
+// run, then compare later runs with it.
+func TestRecordThenCompare(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-record", baselinePath}, strings.NewReader(sampleOutput), &stdout, &stderr); code != 0 {
+		t.Fatalf("record exited with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "recorded 3 benchmarks") {
+		t.Errorf("record output: %q", stdout.String())
+	}
+
+	// An identical run passes.
+	stdout.Reset()
+	if code := run([]string{"-baseline", baselinePath}, strings.NewReader(sampleOutput), &stdout, &stderr); code != 0 {
+		t.Fatalf("identical run exited with %d: %s", code, stdout.String())
+	}
+
+	// A 20% slowdown of one benchmark fails at the default threshold and
+	// passes at a looser one.
+	slower := strings.ReplaceAll(sampleOutput, "256491366 ns/op", "307789639 ns/op")
+	stdout.Reset()
+	if code := run([]string{"-baseline", baselinePath}, strings.NewReader(slower), &stdout, &stderr); code != 1 {
+		t.Fatalf("slower run exited with %d, want 1", code)
+	}
+	if !strings.Contains(stdout.String(), "REGRESSION") || !strings.Contains(stdout.String(), "merkle_build") {
+		t.Errorf("regression not reported: %s", stdout.String())
+	}
+	if code := run([]string{"-baseline", baselinePath, "-threshold", "0.25"}, strings.NewReader(slower), &stdout, &stderr); code != 0 {
+		t.Fatalf("slower run exited with %d at threshold 0.25", code)
+	}
+
+	// Dropping a tracked benchmark fails.
+	var partial []string
+	for _, line := range strings.Split(sampleOutput, "\n") {
+		if !strings.Contains(line, "permutation") {
+			partial = append(partial, line)
+		}
+	}
+	stdout.Reset()
+	if code := run([]string{"-baseline", baselinePath}, strings.NewReader(strings.Join(partial, "\n")), &stdout, &stderr); code != 1 {
+		t.Fatalf("run missing a benchmark exited with %d, want 1", code)
+	}
+	if !strings.Contains(stdout.String(), "missing") {
+		t.Errorf("missing benchmark not reported: %s", stdout.String())
+	}
+}
+
+func TestCompareReadsResultsFile(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "bench.txt")
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(outputPath, []byte(sampleOutput), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-record", baselinePath, outputPath}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("record exited with %d: %s", code, stderr.String())
+	}
+
+	// A run on a different machine is compared, with a warning.
+	otherMachine := strings.Replace(sampleOutput, "cpu: Intel(R) Xeon(R) Processor", "cpu: Apple M2", 1)
+	if code := run([]string{"-baseline", baselinePath}, strings.NewReader(otherMachine), &stdout, &stderr); code != 0 {
+		t.Fatalf("compare exited with %d", code)
+	}
+	if !strings.Contains(stderr.String(), "warning") {
+		t.Errorf("no warning for a different machine: %q", stderr.String())
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	for name, args := range map[string][]string{
+		"no mode":            {},
+		"both modes":         {"-baseline", "a.json", "-record", "b.json"},
+		"negative threshold": {"-baseline", "a.json", "-threshold", "-0.1"},
+		"two inputs":         {"-baseline", "a.json", "x.txt", "y.txt"},
+		"unknown flag":       {"-fast"},
+		"missing baseline":   {"-baseline", filepath.Join(t.TempDir(), "absent.json")},
+		"missing input":      {"-record", "b.json", filepath.Join(t.TempDir(), "absent.txt")},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, strings.NewReader(sampleOutput), &stdout, &stderr); code != 2 {
+			t.Errorf("%s: exited with %d, want 2", name, code)
+		}
+	}
+}
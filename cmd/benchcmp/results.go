@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// results is the JSON form of a benchmark run, used for baselines: the machine it ran on and
+// the median time of each benchmark, sorted by name.
+type results struct {
+	Goos       string   `json:"goos"`
+	Goarch     string   `json:"goarch"`
+	CPU        string   `json:"cpu"`
+	Benchmarks []result `json:"benchmarks"`
+}
+
+// result is the median time per operation of one benchmark over its
+// samples.
+type result struct {
+	Name    string  `json:"name"`
+	NsPerOp float64 `json:"ns_per_op"`
+	Samples int     `json:"samples"`
+}
+
+// procsSuffix is the -GOMAXPROCS suffix the testing package appends to
+// benchmark names when GOMAXPROCS is not 1.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// parseBenchOutput reads the text output of go test -bench and returns the
+// median ns/op of every benchmark in it. Lines that are not benchmark
+// results are ignored, apart from the goos, goarch and cpu headers. Returns
+// an error if the output holds no benchmark results.
+func parseBenchOutput(r io.Reader) (*results, error) {
+	baseline := &results{}
+	samples := make(map[string][]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			switch key {
+			case "goos":
+				baseline.Goos = value
+			case "goarch":
+				baseline.Goarch = value
+			case "cpu":
+				baseline.CPU = value
+			}
+			continue
+		}
+		name, nsPerOp, ok := parseResultLine(line)
+		if ok {
+			samples[name] = append(samples[name], nsPerOp)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading benchmark output: %w", err)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no benchmark results found")
+	}
+
+	for name, values := range samples {
+		baseline.Benchmarks = append(baseline.Benchmarks, result{
+			Name:    name,
+			NsPerOp: median(values),
+			Samples: len(values),
+		})
+	}
+	sort.Slice(baseline.Benchmarks, func(i, j int) bool {
+		return baseline.Benchmarks[i].Name < baseline.Benchmarks[j].Name
+	})
+	return baseline, nil
+}
+
+// parseResultLine parses a result line such as
+//
+//	BenchmarkHash/tip5/hash_pair-8   	  171246	      6890 ns/op	       0 B/op
+//
+// into the benchmark name, without the GOMAXPROCS suffix, and its ns/op.
+func parseResultLine(line string) (string, float64, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return "", 0, false
+	}
+	if _, err := strconv.ParseUint(fields[1], 10, 64); err != nil {
+		return "", 0, false
+	}
+	for i := 2; i+1 < len(fields); i += 2 {
+		if fields[i+1] != "ns/op" {
+			continue
+		}
+		nsPerOp, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return procsSuffix.ReplaceAllString(fields[0], ""), nsPerOp, true
+	}
+	return "", 0, false
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// readResults decodes a baseline written by writeResults.
+func readResults(r io.Reader) (*results, error) {
+	var baseline results
+	if err := json.NewDecoder(r).Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("decoding baseline: %w", err)
+	}
+	if len(baseline.Benchmarks) == 0 {
+		return nil, fmt.Errorf("baseline tracks no benchmarks")
+	}
+	return &baseline, nil
+}
+
+// writeResults encodes the baseline as indented JSON.
+func writeResults(w io.Writer, baseline *results) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(baseline)
+}
+
+// comparison is the outcome of comparing one tracked benchmark.
+type comparison struct {
+	name     string
+	baseline float64 // ns/op in the baseline
+	current  float64 // ns/op in the current run; zero if missing
+	missing  bool    // the current run lacks the benchmark
+}
+
+// delta returns the relative change from the baseline, e.g. 0.1 for 10%
+// slower.
+func (c comparison) delta() float64 {
+	return c.current/c.baseline - 1
+}
+
+// regressed reports whether the benchmark is missing or slower than its
+// baseline by more than the threshold.
+func (c comparison) regressed(threshold float64) bool {
+	return c.missing || c.current > c.baseline*(1+threshold)
+}
+
+// compare compares every benchmark tracked by the baseline with the current
+// run, in the baseline's order. Benchmarks of the current run that the
+// baseline does not track are ignored.
+func compare(baseline, current *results) []comparison {
+	times := make(map[string]float64, len(current.Benchmarks))
+	for _, b := range current.Benchmarks {
+		times[b.Name] = b.NsPerOp
+	}
+	comparisons := make([]comparison, len(baseline.Benchmarks))
+	for i, b := range baseline.Benchmarks {
+		nsPerOp, ok := times[b.Name]
+		comparisons[i] = comparison{name: b.Name, baseline: b.NsPerOp, current: nsPerOp, missing: !ok}
+	}
+	return comparisons
+}
+
+// sameMachine reports whether two runs were taken on the same kind of
+// machine, as far as their headers tell.
+func sameMachine(a, b *results) bool {
+	return a.Goos == b.Goos && a.Goarch == b.Goarch && a.CPU == b.CPU
+}
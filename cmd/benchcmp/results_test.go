@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: github.com/vybium/vybium-crypto/pkg/vybium-crypto/benchmarks
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkHash/tip5/permutation-8         	  100000	     10000 ns/op
+BenchmarkHash/tip5/permutation-8         	  100000	     12000 ns/op	       0 B/op	       0 allocs/op
+BenchmarkHash/tip5/permutation-8         	  100000	     11000 ns/op
+BenchmarkHash/tip5/hash_varlen/len=10-8  	   50000	     20000 ns/op
+BenchmarkHash/tip5/hash_varlen/len=10-8  	   50000	     30000 ns/op
+BenchmarkHash/tip5/merkle_build/leafs=65536 	       1	256491366 ns/op
+--- BENCH: BenchmarkHash/tip5/ignored
+    benchmarks_test.go:12: a log line
+PASS
+ok  	github.com/vybium/vybium-crypto/pkg/vybium-crypto/benchmarks	12.948s
+`
+
+func TestParseBenchOutput(t *testing.T) {
+	got, err := parseBenchOutput(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &results{
+		Goos:   "linux",
+		Goarch: "amd64",
+		CPU:    "Intel(R) Xeon(R) Processor",
+		Benchmarks: []result{
+			{Name: "BenchmarkHash/tip5/hash_varlen/len=10", NsPerOp: 25000, Samples: 2},
+			{Name: "BenchmarkHash/tip5/merkle_build/leafs=65536", NsPerOp: 256491366, Samples: 1},
+			{Name: "BenchmarkHash/tip5/permutation", NsPerOp: 11000, Samples: 3},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseBenchOutputRejectsEmptyRun(t *testing.T) {
+	if _, err := parseBenchOutput(strings.NewReader("PASS\nok  \tpkg\t0.1s\n")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestResultsRoundTrip(t *testing.T) {
+	parsed, err := parseBenchOutput(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeResults(&buf, parsed); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := readResults(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored, parsed) {
+		t.Fatalf("got %+v, want %+v", restored, parsed)
+	}
+
+	if _, err := readResults(strings.NewReader(`{"benchmarks": []}`)); err == nil {
+		t.Error("accepted a baseline without benchmarks")
+	}
+	if _, err := readResults(strings.NewReader(`{`)); err == nil {
+		t.Error("accepted malformed JSON")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &results{Benchmarks: []result{
+		{Name: "BenchmarkA", NsPerOp: 100},
+		{Name: "BenchmarkB", NsPerOp: 100},
+		{Name: "BenchmarkC", NsPerOp: 100},
+		{Name: "BenchmarkD", NsPerOp: 100},
+	}}
+	current := &results{Benchmarks: []result{
+		{Name: "BenchmarkA", NsPerOp: 80},
+		{Name: "BenchmarkB", NsPerOp: 110},
+		{Name: "BenchmarkC", NsPerOp: 111},
+		{Name: "BenchmarkUntracked", NsPerOp: 1e9},
+	}}
+	comparisons := compare(baseline, current)
+	if len(comparisons) != 4 {
+		t.Fatalf("%d comparisons, want 4", len(comparisons))
+	}
+	want := map[string]bool{"BenchmarkA": false, "BenchmarkB": false, "BenchmarkC": true, "BenchmarkD": true}
+	for _, c := range comparisons {
+		if got := c.regressed(0.10); got != want[c.name] {
+			t.Errorf("%s: regressed = %v, want %v", c.name, got, want[c.name])
+		}
+	}
+	if !comparisons[3].missing {
+		t.Error("BenchmarkD is not reported missing")
+	}
+}
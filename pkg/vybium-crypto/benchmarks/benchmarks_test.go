@@ -0,0 +1,197 @@
+package benchmarks
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/merkle"
+)
+
+// varlenLengths are the input lengths of the hash_varlen workloads.
+var varlenLengths = []int{10, 100, 1000, 10000}
+
+// merkleBuildLeafs is the number of leafs of the merkle_build workload.
+const merkleBuildLeafs = 1 << 16
+
+func randomElements(rng *rand.Rand, n int) []field.Element {
+	elements := make([]field.Element, n)
+	for i := range elements {
+		elements[i] = field.New(rng.Uint64() % field.P)
+	}
+	return elements
+}
+
+func randomDigests(rng *rand.Rand, n int) []hash.Digest {
+	digests := make([]hash.Digest, n)
+	for i := range digests {
+		copy(digests[i][:], randomElements(rng, hash.DigestLen))
+	}
+	return digests
+}
+
+// BenchmarkHash runs every workload for every registered variant.
+func BenchmarkHash(b *testing.B) {
+	rng := rand.New(rand.NewSource(1191))
+	pair := randomDigests(rng, 2)
+	inputs := make(map[int][]field.Element, len(varlenLengths))
+	for _, n := range varlenLengths {
+		inputs[n] = randomElements(rng, n)
+	}
+	leafs := randomDigests(rng, merkleBuildLeafs)
+
+	for _, variant := range Variants() {
+		b.Run(variant.Name+"/permutation", func(b *testing.B) {
+			h := variant.New()
+			for i := 0; i < b.N; i++ {
+				h.Permute()
+			}
+		})
+		b.Run(variant.Name+"/hash_pair", func(b *testing.B) {
+			h := variant.New()
+			for i := 0; i < b.N; i++ {
+				_ = h.HashPair(pair[0], pair[1])
+			}
+		})
+		for _, n := range varlenLengths {
+			b.Run(fmt.Sprintf("%s/hash_varlen/len=%d", variant.Name, n), func(b *testing.B) {
+				h := variant.New()
+				for i := 0; i < b.N; i++ {
+					_ = h.HashVarlen(inputs[n])
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("%s/merkle_build/leafs=%d", variant.Name, merkleBuildLeafs), func(b *testing.B) {
+			h := variant.New()
+			for i := 0; i < b.N; i++ {
+				if _, err := MerkleRoot(h, leafs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(variant.Name+"/mmr_append", func(b *testing.B) {
+			mmr := NewMmr(variant.New())
+			for i := 0; i < b.N; i++ {
+				mmr.Append(leafs[i%len(leafs)])
+			}
+		})
+	}
+}
+
+func TestDefaultVariantsRegistered(t *testing.T) {
+	names := make(map[string]bool)
+	for _, variant := range Variants() {
+		names[variant.Name] = true
+	}
+	for _, name := range []string{"tip5", "arion", "poseidon", "poseidon2"} {
+		if !names[name] {
+			t.Errorf("variant %q is not registered", name)
+		}
+	}
+}
+
+func TestRegisterRejectsInvalidVariants(t *testing.T) {
+	newHasher := func() Hasher { return newTip5Hasher() }
+	for name, variant := range map[string]Variant{
+		"duplicate":      {Name: "tip5", New: newHasher},
+		"empty name":     {New: newHasher},
+		"slash":          {Name: "tip5/fast", New: newHasher},
+		"space":          {Name: "tip5 fast", New: newHasher},
+		"no constructor": {Name: "nil"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			Register(variant)
+		}()
+	}
+	if len(Variants()) != 4 {
+		t.Fatalf("rejected variants were registered: %d variants", len(Variants()))
+	}
+}
+
+// TestVariantsBehaveAsHashes checks that every variant is deterministic,
+// order-sensitive and input-sensitive, and that its hasher can be reused.
+func TestVariantsBehaveAsHashes(t *testing.T) {
+	rng := rand.New(rand.NewSource(1191))
+	digests := randomDigests(rng, 2)
+	input := randomElements(rng, 25)
+
+	for _, variant := range Variants() {
+		t.Run(variant.Name, func(t *testing.T) {
+			h := variant.New()
+			pair := h.HashPair(digests[0], digests[1])
+			if !h.HashPair(digests[0], digests[1]).Equal(pair) {
+				t.Error("HashPair is not deterministic")
+			}
+			if h.HashPair(digests[1], digests[0]).Equal(pair) {
+				t.Error("HashPair ignores the order of its inputs")
+			}
+			if !variant.New().HashPair(digests[0], digests[1]).Equal(pair) {
+				t.Error("HashPair differs between hashers")
+			}
+
+			varlen := h.HashVarlen(input)
+			h.Permute()
+			if !h.HashVarlen(input).Equal(varlen) {
+				t.Error("HashVarlen depends on earlier use of the hasher")
+			}
+			changed := append([]field.Element(nil), input...)
+			changed[len(changed)-1] = changed[len(changed)-1].Add(field.One)
+			if h.HashVarlen(changed).Equal(varlen) {
+				t.Error("HashVarlen ignores its last input element")
+			}
+		})
+	}
+}
+
+func TestMerkleRootMatchesMerkleTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1191))
+	for _, n := range []int{1, 2, 8, 64} {
+		leafs := randomDigests(rng, n)
+		tree, err := merkle.New(leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := MerkleRoot(newTip5Hasher(), leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !root.Equal(tree.Root()) {
+			t.Errorf("%d leafs: root differs from merkle.New", n)
+		}
+	}
+
+	for _, n := range []int{0, 3, 6} {
+		if _, err := MerkleRoot(newTip5Hasher(), randomDigests(rng, n)); err == nil {
+			t.Errorf("%d leafs: expected an error", n)
+		}
+	}
+}
+
+func TestMmrMatchesMmrAccumulator(t *testing.T) {
+	rng := rand.New(rand.NewSource(1191))
+	leafs := randomDigests(rng, 37)
+	mmr := NewMmr(newTip5Hasher())
+	for n, leaf := range leafs {
+		mmr.Append(leaf)
+		want := merkle.NewMmrAccumulatorFromLeafs(leafs[:n+1]).Peaks()
+		got := mmr.Peaks()
+		if len(got) != len(want) {
+			t.Fatalf("%d leafs: %d peaks, want %d", n+1, len(got), len(want))
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Fatalf("%d leafs: peak %d differs", n+1, i)
+			}
+		}
+	}
+	if mmr.LeafCount() != uint64(len(leafs)) {
+		t.Errorf("LeafCount = %d, want %d", mmr.LeafCount(), len(leafs))
+	}
+}
@@ -0,0 +1,40 @@
+// Package benchmarks compares the hash functions of this module on the
+// workloads a STARK prover spends its hashing time on:
+//
+//   - a single permutation,
+//   - hashing a pair of digests,
+//   - hashing 10, 100, 1000 and 10000 field elements,
+//   - building a Merkle tree over 2^16 leafs, and
+//   - appending leafs to a Merkle mountain range.
+//
+// Every registered Variant runs every workload through the same code, so the
+// numbers differ only in the hash. The Merkle and MMR workloads are the
+// generic MerkleRoot and Mmr of this package; the production merkle package
+// is Tip5-only and has benchmarks of its own.
+//
+// # Workflow
+//
+// The benchmarks are named
+//
+//	BenchmarkHash/<variant>/<workload>
+//
+// and are tracked against testdata/baseline.json with cmd/benchcmp. From the
+// repository root:
+//
+//	make bench-compare   # run the benchmarks and compare against the baseline
+//	make bench-baseline  # rerun the benchmarks and rewrite the baseline
+//
+// bench-compare fails if a benchmark in the baseline is missing or is slower
+// than its baseline time by more than the threshold (10% by default, set
+// with BENCH_THRESHOLD).
+//
+// # Machine assumptions
+//
+// Benchmark times are only comparable on the same machine. The baseline
+// records the goos, goarch and CPU it was taken on, and benchcmp warns when
+// they differ from the current run; regenerate the baseline on the machine
+// that gates changes rather than comparing across machines. Runs should be
+// taken on an otherwise idle machine with frequency scaling fixed, and with
+// several samples (-count) so that benchcmp can compare medians. The
+// workloads are single-threaded, so GOMAXPROCS does not affect them.
+package benchmarks
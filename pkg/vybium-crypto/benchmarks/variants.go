@@ -0,0 +1,227 @@
+package benchmarks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Hasher is the interface every benchmarked hash function is adapted to.
+// A Hasher may keep state between calls and is not safe for concurrent use.
+type Hasher interface {
+	// Permute applies the permutation once to the hasher's internal state.
+	Permute()
+
+	// HashPair hashes two digests into one, as a Merkle tree node does.
+	HashPair(left, right hash.Digest) hash.Digest
+
+	// HashVarlen hashes a sequence of field elements of any length.
+	HashVarlen(input []field.Element) hash.Digest
+}
+
+// Variant is a named hash function under benchmark.
+type Variant struct {
+	// Name identifies the variant in benchmark names. It must be unique and
+	// must not contain '/' or whitespace.
+	Name string
+
+	// New returns a fresh Hasher. It is called once per benchmark.
+	New func() Hasher
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Variant
+)
+
+// Register adds a variant to the set that every benchmark runs over.
+// Panics if the name is empty, malformed or already registered.
+func Register(v Variant) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if v.Name == "" || v.New == nil {
+		panic("benchmarks: variant needs a name and a constructor")
+	}
+	for _, r := range v.Name {
+		if r == '/' || r == ' ' || r == '\t' || r == '\n' {
+			panic(fmt.Sprintf("benchmarks: invalid variant name %q", v.Name))
+		}
+	}
+	for _, registered := range registry {
+		if registered.Name == v.Name {
+			panic(fmt.Sprintf("benchmarks: variant %q registered twice", v.Name))
+		}
+	}
+	registry = append(registry, v)
+}
+
+// Variants returns the registered variants in registration order.
+func Variants() []Variant {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]Variant(nil), registry...)
+}
+
+func init() {
+	Register(Variant{Name: "tip5", New: func() Hasher { return newTip5Hasher() }})
+	Register(Variant{Name: "arion", New: func() Hasher { return newArionHasher() }})
+	Register(Variant{Name: "poseidon", New: func() Hasher { return newPoseidonHasher() }})
+	Register(Variant{Name: "poseidon2", New: func() Hasher { return newPoseidon2Hasher() }})
+}
+
+// tip5Hasher adapts the production Tip5 functions.
+type tip5Hasher struct {
+	permutation *hash.Tip5
+}
+
+func newTip5Hasher() *tip5Hasher {
+	return &tip5Hasher{permutation: hash.New(hash.VariableLength)}
+}
+
+func (h *tip5Hasher) Permute() {
+	h.permutation.Permutation()
+}
+
+func (h *tip5Hasher) HashPair(left, right hash.Digest) hash.Digest {
+	return hash.HashPair(left, right)
+}
+
+func (h *tip5Hasher) HashVarlen(input []field.Element) hash.Digest {
+	return hash.HashVarlen(input)
+}
+
+// arionHasher adapts an Arion instance with the default parameters.
+type arionHasher struct {
+	arion *hash.Arion
+}
+
+func newArionHasher() *arionHasher {
+	return &arionHasher{arion: hash.NewArion(hash.VariableLength)}
+}
+
+func (h *arionHasher) Permute() {
+	h.arion.Permutation()
+}
+
+func (h *arionHasher) HashPair(left, right hash.Digest) hash.Digest {
+	return h.arion.HashPair(left, right)
+}
+
+func (h *arionHasher) HashVarlen(input []field.Element) hash.Digest {
+	return h.arion.HashVarLen(input)
+}
+
+// poseidonHasher adapts the Poseidon sponge with the default 128-bit
+// parameters. Poseidon's own Hash squeezes a single element; digests are
+// squeezed to hash.DigestLen elements so that its Merkle trees carry as much
+// data per node as those of the other variants.
+type poseidonHasher struct {
+	sponge *hash.PoseidonSponge
+	rate   []field.Element
+}
+
+func newPoseidonHasher() *poseidonHasher {
+	params := hash.GetDefaultPoseidonParameters(128)
+	sponge, err := hash.NewPoseidonSponge(params)
+	if err != nil {
+		panic(fmt.Sprintf("benchmarks: default Poseidon parameters: %v", err))
+	}
+	return &poseidonHasher{sponge: sponge, rate: make([]field.Element, params.Rate)}
+}
+
+// Permute absorbs one full rate of zeros, which applies the permutation
+// exactly once.
+func (h *poseidonHasher) Permute() {
+	h.sponge.Absorb(h.rate)
+}
+
+func (h *poseidonHasher) HashPair(left, right hash.Digest) hash.Digest {
+	h.sponge.Reset()
+	h.sponge.Absorb(left[:])
+	h.sponge.Absorb(right[:])
+	return h.squeeze()
+}
+
+func (h *poseidonHasher) HashVarlen(input []field.Element) hash.Digest {
+	h.sponge.Reset()
+	h.sponge.Absorb(input)
+	return h.squeeze()
+}
+
+func (h *poseidonHasher) squeeze() hash.Digest {
+	var digest hash.Digest
+	copy(digest[:], h.sponge.Squeeze(hash.DigestLen))
+	return digest
+}
+
+// poseidon2Hasher adapts Poseidon2 with the default 128-bit parameters.
+// Poseidon2 has no sponge of its own, so the hasher runs the one of
+// PoseidonSponge over it and squeezes digests of hash.DigestLen elements,
+// as poseidonHasher does.
+type poseidon2Hasher struct {
+	permutation *hash.Poseidon2
+	rate        int
+	state       []field.Element
+	absorbed    int
+}
+
+func newPoseidon2Hasher() *poseidon2Hasher {
+	params := hash.GetDefaultPoseidonParameters(128)
+	permutation, err := hash.NewPoseidon2(params)
+	if err != nil {
+		panic(fmt.Sprintf("benchmarks: default Poseidon2 parameters: %v", err))
+	}
+	return &poseidon2Hasher{
+		permutation: permutation,
+		rate:        params.Rate,
+		state:       make([]field.Element, permutation.Width()),
+	}
+}
+
+func (h *poseidon2Hasher) Permute() {
+	h.state = h.permutation.Permutation(h.state)
+}
+
+func (h *poseidon2Hasher) HashPair(left, right hash.Digest) hash.Digest {
+	h.reset()
+	h.absorb(left[:])
+	h.absorb(right[:])
+	return h.squeeze()
+}
+
+func (h *poseidon2Hasher) HashVarlen(input []field.Element) hash.Digest {
+	h.reset()
+	h.absorb(input)
+	return h.squeeze()
+}
+
+func (h *poseidon2Hasher) reset() {
+	clear(h.state)
+	h.absorbed = 0
+}
+
+func (h *poseidon2Hasher) absorb(input []field.Element) {
+	for _, element := range input {
+		h.state[h.absorbed] = h.state[h.absorbed].Add(element)
+		h.absorbed++
+		if h.absorbed == h.rate {
+			h.Permute()
+			h.absorbed = 0
+		}
+	}
+}
+
+func (h *poseidon2Hasher) squeeze() hash.Digest {
+	var digest hash.Digest
+	for i := range digest {
+		if h.absorbed == h.rate {
+			h.Permute()
+			h.absorbed = 0
+		}
+		digest[i] = h.state[h.absorbed]
+		h.absorbed++
+	}
+	return digest
+}
@@ -0,0 +1,60 @@
+package benchmarks
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// MerkleRoot returns the root of the Merkle tree over the leafs, hashing
+// nodes with h.HashPair. For Tip5 it equals merkle.New(leafs).Root().
+// Returns an error unless the number of leafs is a power of two.
+func MerkleRoot(h Hasher, leafs []hash.Digest) (hash.Digest, error) {
+	n := len(leafs)
+	if n == 0 || n&(n-1) != 0 {
+		return hash.Digest{}, fmt.Errorf("number of leafs must be a power of two, got %d", n)
+	}
+	layer := append([]hash.Digest(nil), leafs...)
+	for len(layer) > 1 {
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = h.HashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0], nil
+}
+
+// Mmr is an append-only Merkle mountain range hashing nodes with the
+// HashPair of its Hasher. For Tip5 its peaks equal those of the
+// merkle.MmrAccumulator over the same leafs.
+type Mmr struct {
+	hasher    Hasher
+	peaks     []hash.Digest
+	leafCount uint64
+}
+
+// NewMmr returns an empty Mmr hashing with h.
+func NewMmr(h Hasher) *Mmr {
+	return &Mmr{hasher: h}
+}
+
+// Append adds a leaf, merging the peaks it completes.
+func (m *Mmr) Append(leaf hash.Digest) {
+	node := leaf
+	for count := m.leafCount; count&1 == 1; count >>= 1 {
+		node = m.hasher.HashPair(m.peaks[len(m.peaks)-1], node)
+		m.peaks = m.peaks[:len(m.peaks)-1]
+	}
+	m.peaks = append(m.peaks, node)
+	m.leafCount++
+}
+
+// Peaks returns the peaks, highest first.
+func (m *Mmr) Peaks() []hash.Digest {
+	return append([]hash.Digest(nil), m.peaks...)
+}
+
+// LeafCount returns the number of appended leafs.
+func (m *Mmr) LeafCount() uint64 {
+	return m.leafCount
+}
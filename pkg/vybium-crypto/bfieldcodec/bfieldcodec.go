@@ -12,10 +12,14 @@
 package bfieldcodec
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/big"
+	"strconv"
 
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/codec"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
 )
 
@@ -36,6 +40,7 @@ const (
 	ErrorInvalidLengthIndicator
 	ErrorInnerDecodingFailure
 	ErrorUnsupportedType
+	ErrorConfigMismatch
 )
 
 func (e BFieldCodecError) Error() string {
@@ -47,19 +52,16 @@ func (e BFieldCodecError) Error() string {
 //
 // Note: Go's type system doesn't support the same level of static dispatch as Rust's trait system,
 // so we use interface methods and runtime type checking where necessary.
-type BFieldCodec interface {
-	// Encode converts the value to a sequence of BFieldElement values.
-	Encode() []field.Element
-
-	// Decode creates a value from a sequence of BFieldElement values.
-	// Returns the decoded value (as BFieldCodec interface) and an error if the sequence is malformed.
-	// Callers should type-assert the result to the expected concrete type.
-	Decode(sequence []field.Element) (BFieldCodec, error)
-
-	// StaticLength returns the fixed length in BFieldElements if known at compile time.
-	// Returns nil for dynamic-length types (those with length prefixes).
-	StaticLength() *int
-}
+//
+// The interface is declared in an internal package, so that packages this
+// one depends on, such as polynomial, can implement it:
+//
+//	Encode() []field.Element
+//	Decode(sequence []field.Element) (BFieldCodec, error)
+//	StaticLength() *int
+//
+// StaticLength returns nil for dynamic-length types.
+type BFieldCodec = codec.BFieldCodec
 
 // EncodeBFieldElement encodes a single BFieldElement.
 func EncodeBFieldElement(element field.Element) []field.Element {
@@ -77,6 +79,32 @@ func DecodeBFieldElement(sequence []field.Element) (field.Element, error) {
 	return sequence[0], nil
 }
 
+// BField is a field.Element implementing BFieldCodec, so that elements can
+// go through the generic helpers such as EncodeSlice and DecodeOption.
+// Package field cannot implement the interface itself, as the interface
+// refers to field. Convert with BField(e) and field.Element(b).
+type BField field.Element
+
+// Encode returns the element as a sequence of one, as EncodeBFieldElement.
+func (b BField) Encode() []field.Element {
+	return EncodeBFieldElement(field.Element(b))
+}
+
+// Decode implements BFieldCodec. The result is a BField.
+func (b BField) Decode(sequence []field.Element) (BFieldCodec, error) {
+	element, err := DecodeBFieldElement(sequence)
+	if err != nil {
+		return nil, err
+	}
+	return BField(element), nil
+}
+
+// StaticLength implements BFieldCodec. An element encodes as itself.
+func (b BField) StaticLength() *int {
+	length := 1
+	return &length
+}
+
 // EncodeUint64 encodes a uint64 as two BFieldElement values (32 bits each).
 func EncodeUint64(value uint64) []field.Element {
 	// Split into two 32-bit parts
@@ -201,6 +229,64 @@ func DecodeBool(sequence []field.Element) (bool, error) {
 	}
 }
 
+// bytesPerStringElement is the number of bytes EncodeString packs into one
+// element. Seven bytes always fit below P.
+const bytesPerStringElement = 7
+
+// EncodeString encodes a string as its length in bytes followed by its
+// bytes packed little-endian, bytesPerStringElement bytes per element, the
+// last element zero-padded. The bytes are those of the string, UTF-8 for
+// text, and are not validated. The layout is the byte packing hash.HashString
+// uses.
+func EncodeString(s string) []field.Element {
+	encoded := make([]field.Element, 0, 1+(len(s)+bytesPerStringElement-1)/bytesPerStringElement)
+	encoded = append(encoded, field.New(uint64(len(s))))
+	for i := 0; i < len(s); i += bytesPerStringElement {
+		var chunk [8]byte
+		copy(chunk[:bytesPerStringElement], s[i:])
+		encoded = append(encoded, field.New(binary.LittleEndian.Uint64(chunk[:])))
+	}
+	return encoded
+}
+
+// DecodeString decodes a string written by EncodeString. Returns an error if
+// the number of elements does not match the byte length, or an element has
+// bits set beyond its bytesPerStringElement bytes or, in the last element,
+// beyond the string's end, so that every string has exactly one encoding.
+func DecodeString(sequence []field.Element) (string, error) {
+	if len(sequence) == 0 {
+		return "", BFieldCodecError{ErrorEmptySequence, "empty sequence"}
+	}
+	byteLen := sequence[0].Value()
+	body := sequence[1:]
+	numElements := byteLen / bytesPerStringElement
+	if byteLen%bytesPerStringElement != 0 {
+		numElements++
+	}
+	if uint64(len(body)) != numElements {
+		return "", BFieldCodecError{
+			ErrorInvalidLengthIndicator,
+			fmt.Sprintf("string of %d bytes needs %d elements, got %d", byteLen, numElements, len(body)),
+		}
+	}
+
+	bytes := make([]byte, 0, len(body)*bytesPerStringElement)
+	for i, element := range body {
+		chunkLen := min(uint64(bytesPerStringElement), byteLen-uint64(i)*bytesPerStringElement)
+		value := element.Value()
+		if value>>(8*chunkLen) != 0 {
+			return "", BFieldCodecError{
+				ErrorElementOutOfRange,
+				fmt.Sprintf("element %d holds more than %d bytes", i, chunkLen),
+			}
+		}
+		var chunk [8]byte
+		binary.LittleEndian.PutUint64(chunk[:], value)
+		bytes = append(bytes, chunk[:chunkLen]...)
+	}
+	return string(bytes), nil
+}
+
 // EncodeXFieldElement encodes an XFieldElement as three BFieldElement values.
 func EncodeXFieldElement(element xfield.XFieldElement) []field.Element {
 	return []field.Element{
@@ -219,7 +305,7 @@ func DecodeXFieldElement(sequence []field.Element) (xfield.XFieldElement, error)
 		return xfield.Zero, BFieldCodecError{ErrorSequenceTooLong, "too many elements for XFieldElement"}
 	}
 
-	return xfield.New([3]field.Element{
+	return xfield.New([xfield.ExtensionDegree]field.Element{
 		sequence[0],
 		sequence[1],
 		sequence[2],
@@ -227,6 +313,9 @@ func DecodeXFieldElement(sequence []field.Element) (xfield.XFieldElement, error)
 }
 
 // EncodeSlice encodes a slice of BFieldCodec values with length prefix.
+// Items of dynamic length, those whose StaticLength is nil, are each
+// preceded by the length of their encoding, which DecodeSlice reads to
+// delimit them.
 func EncodeSlice[T BFieldCodec](slice []T) []field.Element {
 	if len(slice) == 0 {
 		return []field.Element{field.Zero}
@@ -237,76 +326,97 @@ func EncodeSlice[T BFieldCodec](slice []T) []field.Element {
 
 	// Encode each element
 	for _, item := range slice {
-		encoded := item.Encode()
-		result = append(result, encoded...)
+		result = appendItem(result, item)
 	}
 
 	return result
 }
 
+// appendItem appends the encoding of item to result, preceded by its length
+// if the item has dynamic length.
+func appendItem(result []field.Element, item BFieldCodec) []field.Element {
+	encoded := item.Encode()
+	if item.StaticLength() == nil {
+		result = append(result, field.New(uint64(len(encoded))))
+	}
+	return append(result, encoded...)
+}
+
 // DecodeSlice decodes a slice of BFieldCodec values from a sequence with length prefix.
 
 // - First element is the length prefix (number of items)
 // - For each item: if static_length is Some, use that; else read length prefix per item
 // - Remaining elements are the encoded items
+//
+// constructor is called once; the StaticLength and Decode of the value it
+// returns serve every item. Items are decoded from sub-slices of sequence,
+// which is never copied.
 func DecodeSlice[T BFieldCodec](sequence []field.Element, constructor func() T) ([]T, error) {
-	if len(sequence) == 0 {
-		return nil, BFieldCodecError{ErrorEmptySequence, "empty sequence"}
-	}
+	return decodeSlice(sequence, constructor, nil)
+}
 
-	// Read length prefix (number of items in the slice)
-	numItems := sequence[0].Value()
-	sequence = sequence[1:]
+// DecodeSliceWithDiagnostics is DecodeSlice, additionally recording a
+// diagnostics.Event for a failure, located at the element offset within
+// sequence where decoding failed. A nil collector is ignored.
+func DecodeSliceWithDiagnostics[T BFieldCodec](sequence []field.Element, constructor func() T, d *diagnostics.Diagnostics) ([]T, error) {
+	return decodeSlice(sequence, constructor, d)
+}
 
+func decodeSlice[T BFieldCodec](sequence []field.Element, constructor func() T, d *diagnostics.Diagnostics) ([]T, error) {
+	c := newCursor(sequence)
+
+	// Read length prefix (number of items in the slice)
+	prefix, ok := c.next()
+	if !ok {
+		recordCounts(d, diagnostics.ReasonEmptySequence, 0, 1, 0)
+		return nil, BFieldCodecError{ErrorEmptySequence, "empty sequence"}
+	}
+	numItems := prefix.Value()
 	if numItems == 0 {
 		return []T{}, nil
 	}
 
-	result := make([]T, numItems)
-
-	// Get the static length of the element type
-	sampleItem := constructor()
-	staticLen := sampleItem.StaticLength()
+	decoder := constructor()
+	staticLen := decoder.StaticLength()
+	result := make([]T, 0, itemCapacity(numItems, staticLen, c.remaining()))
 
-	for i := 0; i < int(numItems); i++ {
-		var itemLength int
+	for i := uint64(0); i < numItems; i++ {
+		itemOffset := uint64(c.offset)
 		var itemSequence []field.Element
 
 		// Determine item length: either static or from length prefix
 		if staticLen != nil {
-			// Static length: use it directly
-			itemLength = *staticLen
-			if len(sequence) < itemLength {
+			itemSequence, ok = c.take(uint64(*staticLen))
+			if !ok {
+				recordCounts(d, diagnostics.ReasonSequenceTooShort, itemOffset, uint64(*staticLen), uint64(c.remaining()))
 				return nil, BFieldCodecError{
 					ErrorSequenceTooShort,
-					fmt.Sprintf("sequence too short for item %d (need %d elements)", i, itemLength),
+					fmt.Sprintf("sequence too short for item %d (need %d elements)", i, *staticLen),
 				}
 			}
-			itemSequence = sequence[:itemLength]
-			sequence = sequence[itemLength:]
 		} else {
-			// Dynamic length: read length prefix for this item
-			if len(sequence) == 0 {
+			itemLength, ok := c.next()
+			if !ok {
+				recordCounts(d, diagnostics.ReasonInvalidLengthIndicator, itemOffset, 1, 0)
 				return nil, BFieldCodecError{
 					ErrorMissingLengthIndicator,
 					fmt.Sprintf("missing length indicator for item %d", i),
 				}
 			}
-			itemLength = int(sequence[0].Value())
-			if len(sequence) < 1+itemLength {
+			itemOffset++
+			itemSequence, ok = c.take(itemLength.Value())
+			if !ok {
+				recordCounts(d, diagnostics.ReasonSequenceTooShort, itemOffset, itemLength.Value(), uint64(c.remaining()))
 				return nil, BFieldCodecError{
 					ErrorSequenceTooShort,
-					fmt.Sprintf("sequence too short for item %d (need %d elements after prefix)", i, itemLength),
+					fmt.Sprintf("sequence too short for item %d (need %d elements after prefix)", i, itemLength.Value()),
 				}
 			}
-			itemSequence = sequence[1 : 1+itemLength]
-			sequence = sequence[1+itemLength:]
 		}
 
-		// Decode the item using its Decode method
-		item := constructor()
-		decoded, err := item.Decode(itemSequence)
+		decoded, err := decoder.Decode(itemSequence)
 		if err != nil {
+			record(d, diagnostics.ReasonInnerDecodingFailure, itemOffset, "", err.Error())
 			return nil, BFieldCodecError{
 				ErrorInnerDecodingFailure,
 				fmt.Sprintf("failed to decode item %d: %v", i, err),
@@ -316,23 +426,60 @@ func DecodeSlice[T BFieldCodec](sequence []field.Element, constructor func() T)
 		// Type-assert the decoded value
 		typedItem, ok := decoded.(T)
 		if !ok {
+			record(d, diagnostics.ReasonInnerDecodingFailure, itemOffset, fmt.Sprintf("%T", *new(T)), fmt.Sprintf("%T", decoded))
 			return nil, BFieldCodecError{
 				ErrorUnsupportedType,
 				fmt.Sprintf("decoded item %d has unexpected type", i),
 			}
 		}
 
-		result[i] = typedItem
+		result = append(result, typedItem)
 	}
 
 	// Ensure we consumed all the sequence
-	if len(sequence) > 0 {
+	if c.remaining() > 0 {
+		offset := uint64(c.offset)
+		recordCounts(d, diagnostics.ReasonSequenceTooLong, offset, offset, uint64(len(sequence)))
 		return nil, BFieldCodecError{ErrorSequenceTooLong, "trailing data after decoding all items"}
 	}
 
 	return result, nil
 }
 
+// itemCapacity returns the capacity to allocate for a slice of numItems
+// items: numItems, unless fewer items fit in the remaining elements. Every
+// item occupies at least one element, its static length or its length
+// prefix, so a hostile count cannot force a large allocation.
+func itemCapacity(numItems uint64, staticLen *int, remaining int) int {
+	perItem := 1
+	if staticLen != nil && *staticLen > 1 {
+		perItem = *staticLen
+	}
+	return int(min(numItems, uint64(remaining/perItem)))
+}
+
+// record reports a decoding failure to d, if non-nil.
+func record(d *diagnostics.Diagnostics, reason diagnostics.Reason, offset uint64, expected, got string) {
+	if d == nil {
+		return
+	}
+	d.Record(diagnostics.Event{
+		Component: diagnostics.ComponentCodec,
+		Reason:    reason,
+		Location:  offset,
+		Expected:  expected,
+		Got:       got,
+	})
+}
+
+// recordCounts reports a decoding failure with numeric values to d, if non-nil.
+func recordCounts(d *diagnostics.Diagnostics, reason diagnostics.Reason, offset uint64, expected, got uint64) {
+	if d == nil {
+		return
+	}
+	record(d, reason, offset, strconv.FormatUint(expected, 10), strconv.FormatUint(got, 10))
+}
+
 // EncodeTuple encodes a tuple of BFieldCodec values.
 func EncodeTuple(values ...BFieldCodec) []field.Element {
 	var result []field.Element
@@ -357,29 +504,28 @@ func EncodeOption[T BFieldCodec](value *T) []field.Element {
 // - If Some, remaining elements are the encoded value
 // - If None, sequence must contain only the indicator
 func DecodeOption[T BFieldCodec](sequence []field.Element, constructor func() T) (*T, error) {
-	if len(sequence) == 0 {
+	c := newCursor(sequence)
+	indicator, ok := c.take(1)
+	if !ok {
 		return nil, BFieldCodecError{ErrorEmptySequence, "empty sequence"}
 	}
 
 	// Decode the boolean indicator from first element
-	isSome, err := DecodeBool(sequence[0:1])
+	isSome, err := DecodeBool(indicator)
 	if err != nil {
 		return nil, BFieldCodecError{ErrorInnerDecodingFailure, fmt.Sprintf("failed to decode option indicator: %v", err)}
 	}
 
 	// If None, ensure no additional data
 	if !isSome {
-		if len(sequence) > 1 {
+		if c.remaining() > 0 {
 			return nil, BFieldCodecError{ErrorSequenceTooLong, "None option should not have trailing data"}
 		}
 		return nil, nil
 	}
 
 	// If Some, decode the value from remaining sequence
-	value := constructor()
-
-	// Use the Decode method from the BFieldCodec interface
-	decoded, err := value.Decode(sequence[1:])
+	decoded, err := constructor().Decode(c.rest())
 	if err != nil {
 		return nil, BFieldCodecError{ErrorInnerDecodingFailure, fmt.Sprintf("failed to decode option value: %v", err)}
 	}
@@ -415,39 +561,29 @@ func DecodeArray[T BFieldCodec](sequence []field.Element, length int, constructo
 	result := make([]T, length)
 
 	// Get the static length of the element type
-	sampleItem := constructor()
-	staticLen := sampleItem.StaticLength()
+	decoder := constructor()
+	staticLen := decoder.StaticLength()
 
-	offset := 0
+	c := newCursor(sequence)
 	for i := 0; i < length; i++ {
-		var itemLength int
-		var itemSequence []field.Element
-
-		// Determine item length: either static or from remaining sequence
-		if staticLen != nil {
-			itemLength = *staticLen
-		} else {
-			// For dynamic-length items in an array, we'd need additional context
-			// This is a limitation of the current design
+		// Dynamic-length items carry no length indicators in an array, so
+		// only static-length items can be decoded.
+		if staticLen == nil {
 			return nil, BFieldCodecError{
 				ErrorUnsupportedType,
 				"cannot decode arrays of dynamic-length items without length indicators",
 			}
 		}
 
-		if offset+itemLength > len(sequence) {
+		itemSequence, ok := c.take(uint64(*staticLen))
+		if !ok {
 			return nil, BFieldCodecError{
 				ErrorSequenceTooShort,
-				fmt.Sprintf("sequence too short for element %d (need %d elements at offset %d)", i, itemLength, offset),
+				fmt.Sprintf("sequence too short for element %d (need %d elements at offset %d)", i, *staticLen, c.offset),
 			}
 		}
 
-		itemSequence = sequence[offset : offset+itemLength]
-		offset += itemLength
-
-		// Decode the item using its Decode method
-		item := constructor()
-		decoded, err := item.Decode(itemSequence)
+		decoded, err := decoder.Decode(itemSequence)
 		if err != nil {
 			return nil, BFieldCodecError{
 				ErrorInnerDecodingFailure,
@@ -468,10 +604,10 @@ func DecodeArray[T BFieldCodec](sequence []field.Element, length int, constructo
 	}
 
 	// Validate we consumed exactly the right amount
-	if offset != len(sequence) {
+	if c.remaining() != 0 {
 		return nil, BFieldCodecError{
 			ErrorSequenceTooLong,
-			fmt.Sprintf("sequence length mismatch: expected %d elements, got %d", offset, len(sequence)),
+			fmt.Sprintf("sequence length mismatch: expected %d elements, got %d", c.offset, len(sequence)),
 		}
 	}
 
@@ -491,12 +627,12 @@ func DecodeLengthPrefix(sequence []field.Element) (length int, remaining []field
 		return 0, nil, BFieldCodecError{ErrorEmptySequence, "empty sequence"}
 	}
 
-	length = int(sequence[0].Value())
-	if len(sequence) < 1+length {
+	indicated := sequence[0].Value()
+	if indicated > uint64(len(sequence)-1) {
 		return 0, nil, BFieldCodecError{ErrorSequenceTooShort, "sequence too short for indicated length"}
 	}
 
-	return length, sequence[1:], nil
+	return int(indicated), sequence[1:], nil
 }
 
 // ValidateSequenceLength checks if a sequence has the expected length.
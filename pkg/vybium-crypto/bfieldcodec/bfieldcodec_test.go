@@ -1,10 +1,15 @@
 package bfieldcodec
 
 import (
+	"errors"
 	"math/big"
+	"math/rand"
+	"strings"
 	"testing"
 
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
 )
 
@@ -93,6 +98,92 @@ func TestDecodeBFieldElementErrors(t *testing.T) {
 	}
 }
 
+var _ BFieldCodec = BField{}
+
+func TestBFieldSliceRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1279))
+	for _, n := range []int{0, 1, 10} {
+		elements := make([]BField, n)
+		for i := range elements {
+			elements[i] = BField(field.New(rng.Uint64()))
+		}
+
+		encoding := EncodeSlice(elements)
+		if len(encoding) != 1+n || encoding[0].Value() != uint64(n) {
+			t.Fatalf("%d elements encode as %v", n, encoding)
+		}
+		for i, e := range elements {
+			if encoding[1+i] != field.Element(e) {
+				t.Errorf("encoding[%d] = %v, want %v", 1+i, encoding[1+i], field.Element(e))
+			}
+		}
+
+		decoded, err := DecodeSlice(encoding, func() BField { return BField{} })
+		if err != nil {
+			t.Fatalf("%d elements: %v", n, err)
+		}
+		if len(decoded) != n {
+			t.Fatalf("decoded %d elements, want %d", len(decoded), n)
+		}
+		for i := range elements {
+			if decoded[i] != elements[i] {
+				t.Errorf("element %d: got %v, want %v", i, decoded[i], elements[i])
+			}
+		}
+	}
+
+	if _, err := (BField{}).Decode(nil); err == nil {
+		t.Error("empty sequence decoded")
+	}
+	if _, err := (BField{}).Decode([]field.Element{field.One, field.One}); err == nil {
+		t.Error("two elements decoded as one")
+	}
+}
+
+func TestBFieldOption(t *testing.T) {
+	value := BField(field.New(1279))
+	decoded, err := DecodeOption(EncodeOption(&value), func() BField { return BField{} })
+	if err != nil || decoded == nil || *decoded != value {
+		t.Errorf("Some round trip: got %v, %v", decoded, err)
+	}
+	decoded, err = DecodeOption(EncodeOption[BField](nil), func() BField { return BField{} })
+	if err != nil || decoded != nil {
+		t.Errorf("None round trip: got %v, %v", decoded, err)
+	}
+}
+
+func TestPolynomialSliceRoundTrip(t *testing.T) {
+	polys := []*polynomial.Polynomial{
+		polynomial.Zero(),
+		polynomial.New([]field.Element{field.New(1280)}),
+		polynomial.New([]field.Element{field.New(1), field.New(2), field.New(3), field.New(4), field.New(5), field.New(6)}),
+	}
+	encoding := EncodeSlice(polys)
+	// Count, then per polynomial its encoding length and its encoding, the
+	// coefficient count and the coefficients
+	if want := 1 + 2 + 3 + 8; len(encoding) != want {
+		t.Fatalf("encoding has %d elements, want %d", len(encoding), want)
+	}
+
+	newPolynomial := func() *polynomial.Polynomial { return polynomial.Zero() }
+	decoded, err := DecodeSlice(encoding, newPolynomial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(polys) {
+		t.Fatalf("decoded %d polynomials, want %d", len(decoded), len(polys))
+	}
+	for i, p := range polys {
+		if !decoded[i].Equal(p) {
+			t.Errorf("polynomial %d: got %v, want %v", i, decoded[i], p)
+		}
+	}
+
+	if _, err := DecodeSlice(encoding[:len(encoding)-1], newPolynomial); err == nil {
+		t.Error("truncated encoding accepted")
+	}
+}
+
 func TestEncodeDecodeUint64(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -321,6 +412,58 @@ func TestDecodeBoolErrors(t *testing.T) {
 	}
 }
 
+func TestEncodeDecodeString(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"a",
+		"vybium",
+		"vybium!",
+		"vybium/hash/string",
+		"héllo wörld",
+		"日本語のテキスト",
+		"emoji 🦀 and ☃",
+		strings.Repeat("x", 700),
+	} {
+		encoding := EncodeString(s)
+		if want := 1 + (len(s)+6)/7; len(encoding) != want || encoding[0].Value() != uint64(len(s)) {
+			t.Errorf("%q: encoding has %d elements and length %v, want %d and %d", s, len(encoding), encoding[0], want, len(s))
+		}
+		decoded, err := DecodeString(encoding)
+		if err != nil || decoded != s {
+			t.Errorf("%q: decoded %q, %v", s, decoded, err)
+		}
+	}
+
+	// Seven bytes per element, little-endian: "vybium!" then "a"
+	encoding := EncodeString("vybium!a")
+	if encoding[1].Value() != 0x216d7569627976 || encoding[2].Value() != 'a' {
+		t.Errorf("packing of \"vybium!a\": %v", encoding)
+	}
+}
+
+func TestDecodeStringErrors(t *testing.T) {
+	valid := EncodeString("vybium!a")
+	for _, tt := range []struct {
+		name     string
+		sequence []field.Element
+		want     ErrorType
+	}{
+		{"empty", nil, ErrorEmptySequence},
+		{"missing element", valid[:2], ErrorInvalidLengthIndicator},
+		{"extra element", append(append([]field.Element(nil), valid...), field.Zero), ErrorInvalidLengthIndicator},
+		{"length too short", []field.Element{field.New(7), valid[1], valid[2]}, ErrorInvalidLengthIndicator},
+		{"hostile length", []field.Element{field.Max}, ErrorInvalidLengthIndicator},
+		{"eighth byte", []field.Element{field.New(7), field.New(1 << 56)}, ErrorElementOutOfRange},
+		{"non-zero padding", []field.Element{field.New(1), field.New(0x161)}, ErrorElementOutOfRange},
+	} {
+		_, err := DecodeString(tt.sequence)
+		var codecErr BFieldCodecError
+		if !errors.As(err, &codecErr) || codecErr.Type != tt.want {
+			t.Errorf("%s: got %v, want error type %d", tt.name, err, tt.want)
+		}
+	}
+}
+
 func TestEncodeDecodeXFieldElement(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -615,6 +758,109 @@ func TestConvertFromBigIntModulo(t *testing.T) {
 }
 
 // Benchmark tests
+// testUint32 is a static-length BFieldCodec type for slice tests.
+type testUint32 uint32
+
+func (v testUint32) Encode() []field.Element { return EncodeUint32(uint32(v)) }
+
+func (v testUint32) Decode(sequence []field.Element) (BFieldCodec, error) {
+	decoded, err := DecodeUint32(sequence)
+	return testUint32(decoded), err
+}
+
+func (v testUint32) StaticLength() *int {
+	length := 1
+	return &length
+}
+
+// testPair is a dynamic-length BFieldCodec type holding at most two elements.
+type testPair []field.Element
+
+func (v testPair) Encode() []field.Element { return v }
+
+func (v testPair) Decode(sequence []field.Element) (BFieldCodec, error) {
+	if len(sequence) > 2 {
+		return nil, BFieldCodecError{ErrorSequenceTooLong, "pair has at most 2 elements"}
+	}
+	return testPair(sequence), nil
+}
+
+func (v testPair) StaticLength() *int { return nil }
+
+func codecEvent(reason diagnostics.Reason, offset uint64, expected, got string) diagnostics.Event {
+	return diagnostics.Event{
+		Component: diagnostics.ComponentCodec,
+		Reason:    reason,
+		Location:  offset,
+		Expected:  expected,
+		Got:       got,
+	}
+}
+
+func TestDecodeSliceWithDiagnostics(t *testing.T) {
+	// [count=2, 5, 6] as static items, [count=2, len=1, a, len=2, b, c] as dynamic
+	static := EncodeSlice([]testUint32{5, 6})
+	dynamic := EncodeSlice([]testPair{{field.New(1)}, {field.New(2), field.New(3)}})
+
+	tests := []struct {
+		name   string
+		decode func(*diagnostics.Diagnostics) error
+		want   diagnostics.Event
+	}{
+		{"empty", func(d *diagnostics.Diagnostics) error {
+			_, err := DecodeSliceWithDiagnostics(nil, func() testUint32 { return 0 }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonEmptySequence, 0, "1", "0")},
+		{"static item truncated", func(d *diagnostics.Diagnostics) error {
+			_, err := DecodeSliceWithDiagnostics(static[:2], func() testUint32 { return 0 }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonSequenceTooShort, 2, "1", "0")},
+		{"static item out of range", func(d *diagnostics.Diagnostics) error {
+			sequence := append([]field.Element(nil), static...)
+			sequence[2] = field.New(1 << 32)
+			_, err := DecodeSliceWithDiagnostics(sequence, func() testUint32 { return 0 }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonInnerDecodingFailure, 2, "",
+			BFieldCodecError{ErrorElementOutOfRange, "element out of range for uint32"}.Error())},
+		{"trailing data", func(d *diagnostics.Diagnostics) error {
+			_, err := DecodeSliceWithDiagnostics(append(static, field.Zero), func() testUint32 { return 0 }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonSequenceTooLong, 3, "3", "4")},
+		{"missing length indicator", func(d *diagnostics.Diagnostics) error {
+			_, err := DecodeSliceWithDiagnostics(dynamic[:3], func() testPair { return nil }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonInvalidLengthIndicator, 3, "1", "0")},
+		{"dynamic item truncated", func(d *diagnostics.Diagnostics) error {
+			_, err := DecodeSliceWithDiagnostics(dynamic[:5], func() testPair { return nil }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonSequenceTooShort, 4, "2", "1")},
+		{"dynamic item too long", func(d *diagnostics.Diagnostics) error {
+			sequence := EncodeSlice([]testPair{{field.New(1), field.New(2), field.New(3)}})
+			_, err := DecodeSliceWithDiagnostics(sequence, func() testPair { return nil }, d)
+			return err
+		}, codecEvent(diagnostics.ReasonInnerDecodingFailure, 2, "",
+			BFieldCodecError{ErrorSequenceTooLong, "pair has at most 2 elements"}.Error())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d diagnostics.Diagnostics
+			if err := tt.decode(&d); err == nil {
+				t.Fatal("expected error")
+			}
+			if events := d.Events(); len(events) != 1 || events[0] != tt.want {
+				t.Errorf("got events %v, want %v", events, tt.want)
+			}
+		})
+	}
+
+	var d diagnostics.Diagnostics
+	decoded, err := DecodeSliceWithDiagnostics(dynamic, func() testPair { return nil }, &d)
+	if err != nil || len(decoded) != 2 || len(d.Events()) != 0 {
+		t.Errorf("valid sequence: got %v, %v, events %v", decoded, err, d.Events())
+	}
+}
+
 func BenchmarkEncodeBFieldElement(b *testing.B) {
 	element := field.New(12345)
 	b.ResetTimer()
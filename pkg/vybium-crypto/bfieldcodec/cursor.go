@@ -0,0 +1,53 @@
+package bfieldcodec
+
+import "github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+
+// cursor reads an encoded sequence front to back without copying it. Every
+// slice it returns aliases the sequence it was created over, with capacity
+// clipped to its length, so a nested decoder that appends to its input
+// cannot overwrite the elements that follow.
+//
+// Lengths are taken as uint64, the width of a length indicator, and compared
+// with the remaining length before any conversion, so a hostile indicator
+// can neither overflow int nor slice out of range.
+type cursor struct {
+	sequence []field.Element
+	offset   int
+}
+
+func newCursor(sequence []field.Element) cursor {
+	return cursor{sequence: sequence}
+}
+
+// remaining returns the number of elements not yet consumed.
+func (c *cursor) remaining() int {
+	return len(c.sequence) - c.offset
+}
+
+// next consumes one element. Returns false if none remain.
+func (c *cursor) next() (field.Element, bool) {
+	if c.offset == len(c.sequence) {
+		return field.Zero, false
+	}
+	element := c.sequence[c.offset]
+	c.offset++
+	return element, true
+}
+
+// take consumes the next n elements. Returns false, consuming nothing, if
+// fewer than n remain.
+func (c *cursor) take(n uint64) ([]field.Element, bool) {
+	if n > uint64(c.remaining()) {
+		return nil, false
+	}
+	end := c.offset + int(n)
+	chunk := c.sequence[c.offset:end:end]
+	c.offset = end
+	return chunk, true
+}
+
+// rest consumes all remaining elements.
+func (c *cursor) rest() []field.Element {
+	chunk, _ := c.take(uint64(c.remaining()))
+	return chunk
+}
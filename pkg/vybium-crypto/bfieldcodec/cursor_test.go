@@ -0,0 +1,175 @@
+package bfieldcodec
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestCursor(t *testing.T) {
+	sequence := []field.Element{field.New(1), field.New(2), field.New(3), field.New(4)}
+	c := newCursor(sequence)
+
+	first, ok := c.next()
+	if !ok || first != field.New(1) {
+		t.Fatalf("next = %v, %v", first, ok)
+	}
+	chunk, ok := c.take(2)
+	if !ok || len(chunk) != 2 || chunk[0] != field.New(2) {
+		t.Fatalf("take(2) = %v, %v", chunk, ok)
+	}
+	if &chunk[0] != &sequence[1] {
+		t.Error("take copied the sequence")
+	}
+	if cap(chunk) != 2 {
+		t.Errorf("chunk capacity %d exposes the elements that follow", cap(chunk))
+	}
+	if _, ok := c.take(2); ok || c.remaining() != 1 {
+		t.Error("take past the end succeeded or consumed elements")
+	}
+	if _, ok := c.take(1 << 63); ok {
+		t.Error("take of a hostile length succeeded")
+	}
+	if rest := c.rest(); len(rest) != 1 || c.remaining() != 0 {
+		t.Errorf("rest = %v, remaining %d", rest, c.remaining())
+	}
+	if _, ok := c.next(); ok {
+		t.Error("next past the end succeeded")
+	}
+}
+
+func TestDecodeSliceRejectsHostileLengths(t *testing.T) {
+	// Item counts and length indicators near 2^64 must fail cleanly rather
+	// than allocate or overflow.
+	huge := field.New(field.P - 1)
+	if _, err := DecodeSlice([]field.Element{huge, field.One}, func() testUint32 { return 0 }); err == nil {
+		t.Error("accepted a hostile item count")
+	}
+	if _, err := DecodeSlice([]field.Element{field.One, huge, field.One}, func() testPair { return nil }); err == nil {
+		t.Error("accepted a hostile length indicator")
+	}
+	if _, _, err := DecodeLengthPrefix([]field.Element{huge, field.One}); err == nil {
+		t.Error("DecodeLengthPrefix accepted a hostile length")
+	}
+}
+
+// testDigest is a static-length item, as a hash digest is.
+type testDigest [5]field.Element
+
+var testDigestLength = len(testDigest{})
+
+func (v testDigest) Encode() []field.Element { return v[:] }
+
+func (v testDigest) Decode(sequence []field.Element) (BFieldCodec, error) {
+	if err := ValidateSequenceLength(sequence, testDigestLength); err != nil {
+		return nil, err
+	}
+	return testDigest(sequence), nil
+}
+
+func (v testDigest) StaticLength() *int { return &testDigestLength }
+
+// testPath is a dynamic-length, pointer-receiver item holding a slice of
+// digests, as an authentication path does.
+type testPath struct {
+	digests []testDigest
+}
+
+func (p *testPath) Encode() []field.Element { return EncodeSlice(p.digests) }
+
+func (p *testPath) Decode(sequence []field.Element) (BFieldCodec, error) {
+	digests, err := DecodeSlice(sequence, func() testDigest { return testDigest{} })
+	if err != nil {
+		return nil, err
+	}
+	return &testPath{digests: digests}, nil
+}
+
+func (p *testPath) StaticLength() *int { return nil }
+
+// nestedProof encodes numPaths authentication paths of pathLength digests
+// each, as a slice of length-prefixed paths.
+func nestedProof(numPaths, pathLength int) []field.Element {
+	encoding := []field.Element{field.New(uint64(numPaths))}
+	for p := 0; p < numPaths; p++ {
+		path := &testPath{digests: make([]testDigest, pathLength)}
+		for d := range path.digests {
+			for i := range path.digests[d] {
+				path.digests[d][i] = field.New(uint64(p*pathLength*5 + d*5 + i))
+			}
+		}
+		encoding = append(encoding, EncodeLengthPrefix(path.Encode())...)
+	}
+	return encoding
+}
+
+func decodeNestedProof(sequence []field.Element) ([]*testPath, error) {
+	return DecodeSlice(sequence, func() *testPath { return &testPath{} })
+}
+
+// TestNestedDecodeAllocatesOnlyOutputs decodes a slice of paths of digests
+// and checks that every allocation is part of the result: the outer slice,
+// each path with its digest slice, and each digest, which Decode returns
+// boxed in the BFieldCodec interface. The one other allocation is the
+// constructor's value, made once for the whole outer slice.
+func TestNestedDecodeAllocatesOnlyOutputs(t *testing.T) {
+	const numPaths, pathLength = 20, 8
+	sequence := nestedProof(numPaths, pathLength)
+
+	paths, err := decodeNestedProof(sequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != numPaths || len(paths[numPaths-1].digests) != pathLength {
+		t.Fatalf("decoded %d paths", len(paths))
+	}
+	if last := paths[numPaths-1].digests[pathLength-1][4]; last != field.New(numPaths*pathLength*5-1) {
+		t.Fatalf("last element %v", last)
+	}
+
+	const constructor, outerSlice = 1, 1
+	const perPath = 1 + 1 + pathLength // path, digest slice, digests
+	want := float64(constructor + outerSlice + numPaths*perPath)
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := decodeNestedProof(sequence); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != want {
+		t.Fatalf("%v allocations, want %v", allocs, want)
+	}
+}
+
+func TestDecoderAllocatesNothing(t *testing.T) {
+	encoder, _ := NewEncoder(DefaultCodecConfig())
+	encoder.WriteUint64(1 << 40)
+	encoder.WriteUint128(Uint128{Lo: 1, Hi: 2})
+	sequence := encoder.Sequence()
+
+	allocs := testing.AllocsPerRun(10, func() {
+		decoder := Decoder{config: DefaultCodecConfig(), cursor: newCursor(sequence)}
+		decoder.cursor.next()
+		if _, err := decoder.ReadUint64(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := decoder.ReadUint128(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations, want 0", allocs)
+	}
+}
+
+// BenchmarkDecodeNestedProof decodes 1000 paths of 200 digests, 10^6
+// elements of digest data.
+func BenchmarkDecodeNestedProof(b *testing.B) {
+	sequence := nestedProof(1000, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeNestedProof(sequence); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package bfieldcodec
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// LimbOrder selects the order in which the 32-bit limbs of multi-limb integers
+// (uint64, uint128) are emitted.
+type LimbOrder int
+
+const (
+	// LittleEndianLimbs emits the least significant limb first.
+	// This is the default and matches twenty-first.
+	LittleEndianLimbs LimbOrder = iota
+
+	// BigEndianLimbs emits the most significant limb first.
+	BigEndianLimbs
+)
+
+func (o LimbOrder) String() string {
+	switch o {
+	case LittleEndianLimbs:
+		return "LittleEndianLimbs"
+	case BigEndianLimbs:
+		return "BigEndianLimbs"
+	default:
+		return "Unknown"
+	}
+}
+
+// CodecConfig configures the encoding of multi-limb integers.
+// The zero value is the default configuration.
+type CodecConfig struct {
+	LimbOrder LimbOrder
+}
+
+// DefaultCodecConfig returns the default configuration, under which
+// Encoder and Decoder produce the same limb layout as EncodeUint64.
+func DefaultCodecConfig() CodecConfig {
+	return CodecConfig{LimbOrder: LittleEndianLimbs}
+}
+
+// Validate returns an error if the configuration is not supported.
+func (c CodecConfig) Validate() error {
+	if c.LimbOrder != LittleEndianLimbs && c.LimbOrder != BigEndianLimbs {
+		return BFieldCodecError{ErrorUnsupportedType, fmt.Sprintf("unknown limb order %d", c.LimbOrder)}
+	}
+	return nil
+}
+
+// Uint128 is an unsigned 128-bit integer split into two 64-bit halves.
+type Uint128 struct {
+	Lo uint64
+	Hi uint64
+}
+
+// encodeLimbs encodes 32-bit limbs given in little-endian order.
+func encodeLimbs(limbs []uint32, order LimbOrder) []field.Element {
+	result := make([]field.Element, len(limbs))
+	for i, limb := range limbs {
+		if order == BigEndianLimbs {
+			result[len(limbs)-1-i] = field.New(uint64(limb))
+		} else {
+			result[i] = field.New(uint64(limb))
+		}
+	}
+	return result
+}
+
+// decodeLimbs decodes exactly len(limbs) 32-bit limbs into limbs, in
+// little-endian order. Callers pass an array on their stack, so decoding
+// allocates nothing.
+func decodeLimbs(sequence []field.Element, limbs []uint32, order LimbOrder, typeName string) error {
+	numLimbs := len(limbs)
+	if len(sequence) < numLimbs {
+		return BFieldCodecError{ErrorSequenceTooShort, fmt.Sprintf("need at least %d elements for %s", numLimbs, typeName)}
+	}
+	if len(sequence) > numLimbs {
+		return BFieldCodecError{ErrorSequenceTooLong, fmt.Sprintf("too many elements for %s", typeName)}
+	}
+
+	for i, element := range sequence {
+		value := element.Value()
+		if value > 0xFFFFFFFF {
+			return BFieldCodecError{ErrorElementOutOfRange, fmt.Sprintf("element out of range for %s", typeName)}
+		}
+		if order == BigEndianLimbs {
+			limbs[numLimbs-1-i] = uint32(value)
+		} else {
+			limbs[i] = uint32(value)
+		}
+	}
+	return nil
+}
+
+// EncodeUint64WithOrder encodes a uint64 as two 32-bit limbs in the given order.
+func EncodeUint64WithOrder(value uint64, order LimbOrder) []field.Element {
+	return encodeLimbs([]uint32{uint32(value), uint32(value >> 32)}, order)
+}
+
+// DecodeUint64WithOrder decodes a uint64 from two 32-bit limbs in the given order.
+func DecodeUint64WithOrder(sequence []field.Element, order LimbOrder) (uint64, error) {
+	var limbs [2]uint32
+	if err := decodeLimbs(sequence, limbs[:], order, "uint64"); err != nil {
+		return 0, err
+	}
+	return uint64(limbs[1])<<32 | uint64(limbs[0]), nil
+}
+
+// EncodeUint64BE encodes a uint64 with the most significant limb first.
+func EncodeUint64BE(value uint64) []field.Element {
+	return EncodeUint64WithOrder(value, BigEndianLimbs)
+}
+
+// DecodeUint64BE decodes a uint64 encoded with the most significant limb first.
+func DecodeUint64BE(sequence []field.Element) (uint64, error) {
+	return DecodeUint64WithOrder(sequence, BigEndianLimbs)
+}
+
+// EncodeUint128WithOrder encodes a Uint128 as four 32-bit limbs in the given order.
+func EncodeUint128WithOrder(value Uint128, order LimbOrder) []field.Element {
+	return encodeLimbs([]uint32{
+		uint32(value.Lo), uint32(value.Lo >> 32),
+		uint32(value.Hi), uint32(value.Hi >> 32),
+	}, order)
+}
+
+// DecodeUint128WithOrder decodes a Uint128 from four 32-bit limbs in the given order.
+func DecodeUint128WithOrder(sequence []field.Element, order LimbOrder) (Uint128, error) {
+	var limbs [4]uint32
+	if err := decodeLimbs(sequence, limbs[:], order, "uint128"); err != nil {
+		return Uint128{}, err
+	}
+	return Uint128{
+		Lo: uint64(limbs[1])<<32 | uint64(limbs[0]),
+		Hi: uint64(limbs[3])<<32 | uint64(limbs[2]),
+	}, nil
+}
+
+// EncodeUint128 encodes a Uint128 with the least significant limb first.
+func EncodeUint128(value Uint128) []field.Element {
+	return EncodeUint128WithOrder(value, LittleEndianLimbs)
+}
+
+// DecodeUint128 decodes a Uint128 encoded with the least significant limb first.
+func DecodeUint128(sequence []field.Element) (Uint128, error) {
+	return DecodeUint128WithOrder(sequence, LittleEndianLimbs)
+}
+
+// EncodeUint128BE encodes a Uint128 with the most significant limb first.
+func EncodeUint128BE(value Uint128) []field.Element {
+	return EncodeUint128WithOrder(value, BigEndianLimbs)
+}
+
+// DecodeUint128BE decodes a Uint128 encoded with the most significant limb first.
+func DecodeUint128BE(sequence []field.Element) (Uint128, error) {
+	return DecodeUint128WithOrder(sequence, BigEndianLimbs)
+}
+
+// Encoder builds a framed sequence under a single CodecConfig.
+// The framed sequence starts with one element identifying the limb order, so
+// a Decoder configured differently rejects it instead of silently swapping
+// limbs. The configuration is fixed for the lifetime of the Encoder.
+type Encoder struct {
+	config   CodecConfig
+	sequence []field.Element
+}
+
+// NewEncoder creates an encoder with the given configuration.
+func NewEncoder(config CodecConfig) (*Encoder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Encoder{
+		config:   config,
+		sequence: []field.Element{field.New(uint64(config.LimbOrder))},
+	}, nil
+}
+
+// Config returns the encoder's configuration.
+func (e *Encoder) Config() CodecConfig {
+	return e.config
+}
+
+// WriteBFieldElement appends a single BFieldElement.
+func (e *Encoder) WriteBFieldElement(element field.Element) {
+	e.sequence = append(e.sequence, element)
+}
+
+// WriteUint32 appends a uint32.
+func (e *Encoder) WriteUint32(value uint32) {
+	e.sequence = append(e.sequence, EncodeUint32(value)...)
+}
+
+// WriteUint64 appends a uint64 using the configured limb order.
+func (e *Encoder) WriteUint64(value uint64) {
+	e.sequence = append(e.sequence, EncodeUint64WithOrder(value, e.config.LimbOrder)...)
+}
+
+// WriteUint128 appends a Uint128 using the configured limb order.
+func (e *Encoder) WriteUint128(value Uint128) {
+	e.sequence = append(e.sequence, EncodeUint128WithOrder(value, e.config.LimbOrder)...)
+}
+
+// Sequence returns a copy of the framed sequence written so far.
+func (e *Encoder) Sequence() []field.Element {
+	result := make([]field.Element, len(e.sequence))
+	copy(result, e.sequence)
+	return result
+}
+
+// Decoder is a cursor over a framed sequence produced by an Encoder.
+// Every read uses the Decoder's configuration, so a single sequence can never
+// be decoded with mixed limb orders.
+type Decoder struct {
+	config CodecConfig
+	cursor cursor
+}
+
+// NewDecoder creates a decoder for a framed sequence. It returns an error if
+// the sequence's limb-order header does not match the configuration.
+func NewDecoder(sequence []field.Element, config CodecConfig) (*Decoder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if len(sequence) == 0 {
+		return nil, BFieldCodecError{ErrorEmptySequence, "missing limb order header"}
+	}
+	if header := sequence[0].Value(); header != uint64(config.LimbOrder) {
+		return nil, BFieldCodecError{
+			ErrorConfigMismatch,
+			fmt.Sprintf("sequence encoded with limb order %s, decoder configured for %s", LimbOrder(header), config.LimbOrder),
+		}
+	}
+	d := &Decoder{config: config, cursor: newCursor(sequence)}
+	d.cursor.next()
+	return d, nil
+}
+
+// Config returns the decoder's configuration.
+func (d *Decoder) Config() CodecConfig {
+	return d.config
+}
+
+// Remaining returns the number of elements not yet consumed.
+func (d *Decoder) Remaining() int {
+	return d.cursor.remaining()
+}
+
+// take consumes the next n elements.
+func (d *Decoder) take(n int, typeName string) ([]field.Element, error) {
+	chunk, ok := d.cursor.take(uint64(n))
+	if !ok {
+		return nil, BFieldCodecError{
+			ErrorSequenceTooShort,
+			fmt.Sprintf("need %d elements for %s at offset %d, have %d", n, typeName, d.cursor.offset, d.Remaining()),
+		}
+	}
+	return chunk, nil
+}
+
+// ReadBFieldElement reads a single BFieldElement.
+func (d *Decoder) ReadBFieldElement() (field.Element, error) {
+	chunk, err := d.take(1, "BFieldElement")
+	if err != nil {
+		return field.Zero, err
+	}
+	return chunk[0], nil
+}
+
+// ReadUint32 reads a uint32.
+func (d *Decoder) ReadUint32() (uint32, error) {
+	chunk, err := d.take(1, "uint32")
+	if err != nil {
+		return 0, err
+	}
+	return DecodeUint32(chunk)
+}
+
+// ReadUint64 reads a uint64 using the configured limb order.
+func (d *Decoder) ReadUint64() (uint64, error) {
+	chunk, err := d.take(2, "uint64")
+	if err != nil {
+		return 0, err
+	}
+	return DecodeUint64WithOrder(chunk, d.config.LimbOrder)
+}
+
+// ReadUint128 reads a Uint128 using the configured limb order.
+func (d *Decoder) ReadUint128() (Uint128, error) {
+	chunk, err := d.take(4, "uint128")
+	if err != nil {
+		return Uint128{}, err
+	}
+	return DecodeUint128WithOrder(chunk, d.config.LimbOrder)
+}
+
+// Finish returns an error if any elements remain unconsumed.
+func (d *Decoder) Finish() error {
+	if d.Remaining() != 0 {
+		return BFieldCodecError{ErrorSequenceTooLong, fmt.Sprintf("%d trailing elements after decoding", d.Remaining())}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package bfieldcodec
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func elementsEqual(a, b []field.Element) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUint64LimbOrderGolden(t *testing.T) {
+	value := uint64(0x123456789ABCDEF0)
+
+	le := EncodeUint64WithOrder(value, LittleEndianLimbs)
+	if !elementsEqual(le, []field.Element{field.New(0x9ABCDEF0), field.New(0x12345678)}) {
+		t.Errorf("little-endian encoding = %v", le)
+	}
+	if !elementsEqual(le, EncodeUint64(value)) {
+		t.Error("little-endian encoding must match the default EncodeUint64")
+	}
+
+	be := EncodeUint64BE(value)
+	if !elementsEqual(be, []field.Element{field.New(0x12345678), field.New(0x9ABCDEF0)}) {
+		t.Errorf("big-endian encoding = %v", be)
+	}
+}
+
+func TestUint128LimbOrderGolden(t *testing.T) {
+	value := Uint128{Lo: 0x0000000200000001, Hi: 0x0000000400000003}
+
+	le := EncodeUint128(value)
+	if !elementsEqual(le, []field.Element{field.New(1), field.New(2), field.New(3), field.New(4)}) {
+		t.Errorf("little-endian encoding = %v", le)
+	}
+
+	be := EncodeUint128BE(value)
+	if !elementsEqual(be, []field.Element{field.New(4), field.New(3), field.New(2), field.New(1)}) {
+		t.Errorf("big-endian encoding = %v", be)
+	}
+}
+
+func TestLimbOrderRoundTrips(t *testing.T) {
+	values := []uint64{0, 1, 0xFFFFFFFF, 0x100000000, 0x123456789ABCDEF0, 0xFFFFFFFFFFFFFFFF}
+
+	for _, order := range []LimbOrder{LittleEndianLimbs, BigEndianLimbs} {
+		for _, v := range values {
+			decoded, err := DecodeUint64WithOrder(EncodeUint64WithOrder(v, order), order)
+			if err != nil || decoded != v {
+				t.Errorf("%s uint64 round trip of %#x gave %#x, %v", order, v, decoded, err)
+			}
+
+			wide := Uint128{Lo: v, Hi: ^v}
+			decodedWide, err := DecodeUint128WithOrder(EncodeUint128WithOrder(wide, order), order)
+			if err != nil || decodedWide != wide {
+				t.Errorf("%s uint128 round trip of %+v gave %+v, %v", order, wide, decodedWide, err)
+			}
+		}
+	}
+}
+
+func TestLimbOrderDecodeErrors(t *testing.T) {
+	if _, err := DecodeUint64BE([]field.Element{field.New(0x100000000), field.Zero}); err == nil {
+		t.Error("expected out-of-range error")
+	}
+	if _, err := DecodeUint128BE([]field.Element{field.One, field.One, field.One}); err == nil {
+		t.Error("expected too-short error")
+	}
+	if _, err := DecodeUint128([]field.Element{field.One, field.One, field.One, field.One, field.One}); err == nil {
+		t.Error("expected too-long error")
+	}
+}
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	for _, order := range []LimbOrder{LittleEndianLimbs, BigEndianLimbs} {
+		config := CodecConfig{LimbOrder: order}
+		enc, err := NewEncoder(config)
+		if err != nil {
+			t.Fatalf("NewEncoder: %v", err)
+		}
+		enc.WriteUint64(0xDEADBEEF00C0FFEE)
+		enc.WriteUint128(Uint128{Lo: 7, Hi: 0xFFFFFFFFFFFFFFFF})
+		enc.WriteUint32(42)
+		enc.WriteBFieldElement(field.Max)
+
+		dec, err := NewDecoder(enc.Sequence(), config)
+		if err != nil {
+			t.Fatalf("%s: NewDecoder: %v", order, err)
+		}
+		if v, err := dec.ReadUint64(); err != nil || v != 0xDEADBEEF00C0FFEE {
+			t.Errorf("%s: ReadUint64 = %#x, %v", order, v, err)
+		}
+		if v, err := dec.ReadUint128(); err != nil || v != (Uint128{Lo: 7, Hi: 0xFFFFFFFFFFFFFFFF}) {
+			t.Errorf("%s: ReadUint128 = %+v, %v", order, v, err)
+		}
+		if v, err := dec.ReadUint32(); err != nil || v != 42 {
+			t.Errorf("%s: ReadUint32 = %d, %v", order, v, err)
+		}
+		if v, err := dec.ReadBFieldElement(); err != nil || !v.Equal(field.Max) {
+			t.Errorf("%s: ReadBFieldElement = %v, %v", order, v, err)
+		}
+		if err := dec.Finish(); err != nil {
+			t.Errorf("%s: Finish: %v", order, err)
+		}
+		if _, err := dec.ReadUint32(); err == nil {
+			t.Errorf("%s: expected error reading past the end", order)
+		}
+	}
+}
+
+func TestDecoderRejectsMismatchedLimbOrder(t *testing.T) {
+	// Both halves are below 2^32, so a raw decode with the wrong order would
+	// silently produce a swapped value. The framed format must catch it.
+	value := uint64(0x0000000100000002)
+
+	raw := EncodeUint64(value)
+	if swapped, err := DecodeUint64BE(raw); err != nil || swapped == value {
+		t.Fatalf("precondition: raw cross-order decode should silently differ, got %#x, %v", swapped, err)
+	}
+
+	enc, err := NewEncoder(DefaultCodecConfig())
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	enc.WriteUint64(value)
+
+	_, err = NewDecoder(enc.Sequence(), CodecConfig{LimbOrder: BigEndianLimbs})
+	if err == nil {
+		t.Fatal("expected decoder to reject a little-endian sequence under big-endian config")
+	}
+	bfcErr, ok := err.(BFieldCodecError)
+	if !ok || bfcErr.Type != ErrorConfigMismatch {
+		t.Errorf("expected ErrorConfigMismatch, got %v", err)
+	}
+}
+
+func TestCodecConfigValidate(t *testing.T) {
+	if err := DefaultCodecConfig().Validate(); err != nil {
+		t.Errorf("default config should be valid: %v", err)
+	}
+	if _, err := NewEncoder(CodecConfig{LimbOrder: LimbOrder(7)}); err == nil {
+		t.Error("expected error for unknown limb order")
+	}
+	if _, err := NewDecoder(nil, DefaultCodecConfig()); err == nil {
+		t.Error("expected error for missing header")
+	}
+}
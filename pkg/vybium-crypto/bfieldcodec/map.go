@@ -0,0 +1,154 @@
+package bfieldcodec
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// EncodeMap encodes a map as its number of entries followed by the entries,
+// each the key's encoding and then the value's, with the length prefix that
+// EncodeSlice gives items of dynamic length. Entries are sorted by the
+// key's encoding, compared element by element by canonical value, so a map
+// has one encoding whatever the order its entries were inserted in.
+func EncodeMap[K interface {
+	comparable
+	BFieldCodec
+}, V BFieldCodec](m map[K]V) []field.Element {
+	type entry struct {
+		key     []field.Element
+		dynamic bool
+		value   V
+	}
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, entry{k.Encode(), k.StaticLength() == nil, v})
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return compareEncodings(a.key, b.key) })
+
+	result := []field.Element{field.New(uint64(len(m)))}
+	for _, e := range entries {
+		if e.dynamic {
+			result = append(result, field.New(uint64(len(e.key))))
+		}
+		result = append(result, e.key...)
+		result = appendItem(result, e.value)
+	}
+	return result
+}
+
+// DecodeMap decodes a map written by EncodeMap. newKey and newValue are
+// called once each, as the constructor of DecodeSlice. Returns an error if
+// the entries are not in strictly increasing order of key encoding, which
+// also rejects repeated keys, so that every map has exactly one accepted
+// encoding.
+func DecodeMap[K interface {
+	comparable
+	BFieldCodec
+}, V BFieldCodec](sequence []field.Element, newKey func() K, newValue func() V) (map[K]V, error) {
+	c := newCursor(sequence)
+	prefix, ok := c.next()
+	if !ok {
+		return nil, BFieldCodecError{ErrorEmptySequence, "empty sequence"}
+	}
+	numEntries := prefix.Value()
+
+	keyDecoder, valueDecoder := newKey(), newValue()
+	keyLen, valueLen := keyDecoder.StaticLength(), valueDecoder.StaticLength()
+	// Every entry occupies at least one element for its key
+	result := make(map[K]V, itemCapacity(numEntries, keyLen, c.remaining()))
+	var previousKey []field.Element
+	for i := uint64(0); i < numEntries; i++ {
+		keySequence, err := takeItem(&c, keyLen, "key", i)
+		if err != nil {
+			return nil, err
+		}
+		if previousKey != nil && compareEncodings(previousKey, keySequence) >= 0 {
+			return nil, BFieldCodecError{
+				ErrorInnerDecodingFailure,
+				fmt.Sprintf("key %d does not follow the previous key in encoding order", i),
+			}
+		}
+		previousKey = keySequence
+		key, err := decodeItem[K](keyDecoder, keySequence, "key", i)
+		if err != nil {
+			return nil, err
+		}
+
+		valueSequence, err := takeItem(&c, valueLen, "value", i)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeItem[V](valueDecoder, valueSequence, "value", i)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	if c.remaining() > 0 {
+		return nil, BFieldCodecError{ErrorSequenceTooLong, "trailing data after decoding all entries"}
+	}
+	return result, nil
+}
+
+// takeItem consumes the encoding of one item written by appendItem: staticLen
+// elements, or a length indicator and that many elements if staticLen is nil.
+func takeItem(c *cursor, staticLen *int, what string, i uint64) ([]field.Element, error) {
+	if staticLen != nil {
+		sequence, ok := c.take(uint64(*staticLen))
+		if !ok {
+			return nil, BFieldCodecError{
+				ErrorSequenceTooShort,
+				fmt.Sprintf("sequence too short for %s %d (need %d elements)", what, i, *staticLen),
+			}
+		}
+		return sequence, nil
+	}
+
+	length, ok := c.next()
+	if !ok {
+		return nil, BFieldCodecError{
+			ErrorMissingLengthIndicator,
+			fmt.Sprintf("missing length indicator for %s %d", what, i),
+		}
+	}
+	sequence, ok := c.take(length.Value())
+	if !ok {
+		return nil, BFieldCodecError{
+			ErrorSequenceTooShort,
+			fmt.Sprintf("sequence too short for %s %d (need %d elements after prefix)", what, i, length.Value()),
+		}
+	}
+	return sequence, nil
+}
+
+// decodeItem decodes sequence with decoder and asserts the result is a T.
+func decodeItem[T BFieldCodec](decoder T, sequence []field.Element, what string, i uint64) (T, error) {
+	var zero T
+	decoded, err := decoder.Decode(sequence)
+	if err != nil {
+		return zero, BFieldCodecError{
+			ErrorInnerDecodingFailure,
+			fmt.Sprintf("failed to decode %s %d: %v", what, i, err),
+		}
+	}
+	typed, ok := decoded.(T)
+	if !ok {
+		return zero, BFieldCodecError{
+			ErrorUnsupportedType,
+			fmt.Sprintf("decoded %s %d has unexpected type", what, i),
+		}
+	}
+	return typed, nil
+}
+
+// compareEncodings orders encodings lexicographically by the canonical
+// values of their elements, a proper prefix first.
+func compareEncodings(a, b []field.Element) int {
+	return slices.CompareFunc(a, b, func(x, y field.Element) int {
+		return cmp.Compare(x.Value(), y.Value())
+	})
+}
@@ -0,0 +1,168 @@
+package bfieldcodec
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// testWord is a comparable, dynamic-length BFieldCodec type encoding one
+// element per byte.
+type testWord string
+
+func (w testWord) Encode() []field.Element {
+	encoding := make([]field.Element, len(w))
+	for i := range encoding {
+		encoding[i] = field.New(uint64(w[i]))
+	}
+	return encoding
+}
+
+func (w testWord) Decode(sequence []field.Element) (BFieldCodec, error) {
+	word := make([]byte, len(sequence))
+	for i, e := range sequence {
+		if e.Value() > 0xff {
+			return nil, BFieldCodecError{ErrorElementOutOfRange, "element out of range for byte"}
+		}
+		word[i] = byte(e.Value())
+	}
+	return testWord(word), nil
+}
+
+func (w testWord) StaticLength() *int { return nil }
+
+func newTestUint32() testUint32 { return 0 }
+func newBField() BField         { return BField{} }
+func newTestWord() testWord     { return "" }
+func newTestPair() testPair     { return nil }
+
+func TestEncodeMapIsDeterministic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1281))
+	keys := rng.Perm(50)
+	want := EncodeMap(indexMap(keys))
+	for trial := 0; trial < 10; trial++ {
+		rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		if got := EncodeMap(indexMap(keys)); !slices.Equal(got, want) {
+			t.Fatalf("insertion order %v changed the encoding", keys)
+		}
+	}
+
+	// [count, then key and value for keys 0..49 in order]
+	if len(want) != 1+2*50 || want[0].Value() != 50 {
+		t.Fatalf("encoding has %d elements and count %v", len(want), want[0])
+	}
+	for i := 0; i < 50; i++ {
+		if want[1+2*i].Value() != uint64(i) {
+			t.Fatalf("entry %d has key %v", i, want[1+2*i])
+		}
+	}
+}
+
+// indexMap maps each key to its square, inserting the keys in order.
+func indexMap(keys []int) map[testUint32]BField {
+	m := make(map[testUint32]BField)
+	for _, k := range keys {
+		m[testUint32(k)] = BField(field.New(uint64(k * k)))
+	}
+	return m
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	static := indexMap([]int{7, 3, 1 << 20, 0})
+	decoded, err := DecodeMap(EncodeMap(static), newTestUint32, newBField)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(static) {
+		t.Fatalf("decoded %d entries, want %d", len(decoded), len(static))
+	}
+	for k, v := range static {
+		if decoded[k] != v {
+			t.Errorf("key %d: got %v, want %v", k, decoded[k], v)
+		}
+	}
+
+	// Dynamic keys sort by encoding, a prefix first: "", "a", "ab", "b"
+	dynamic := map[testWord]testPair{
+		"b":  {field.New(1)},
+		"":   {},
+		"ab": {field.New(2), field.New(3)},
+		"a":  nil,
+	}
+	encoding := EncodeMap(dynamic)
+	wantLayout := []uint64{4, 0, 0, 1, 'a', 0, 2, 'a', 'b', 2, 2, 3, 1, 'b', 1, 1}
+	if got := values(encoding); !slices.Equal(got, wantLayout) {
+		t.Errorf("encoding %v, want %v", got, wantLayout)
+	}
+	decodedDynamic, err := DecodeMap(encoding, newTestWord, newTestPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range dynamic {
+		if got, ok := decodedDynamic[k]; !ok || !slices.Equal(got, v) {
+			t.Errorf("key %q: got %v, want %v", k, got, v)
+		}
+	}
+
+	empty, err := DecodeMap(EncodeMap(map[testUint32]BField{}), newTestUint32, newBField)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("empty map: got %v, %v", empty, err)
+	}
+}
+
+func values(sequence []field.Element) []uint64 {
+	result := make([]uint64, len(sequence))
+	for i, e := range sequence {
+		result[i] = e.Value()
+	}
+	return result
+}
+
+func TestDecodeMapRejectsMalformed(t *testing.T) {
+	u := func(values ...uint64) []field.Element {
+		sequence := make([]field.Element, len(values))
+		for i, v := range values {
+			sequence[i] = field.New(v)
+		}
+		return sequence
+	}
+	for _, tt := range []struct {
+		name     string
+		sequence []field.Element
+		want     ErrorType
+	}{
+		{"empty", nil, ErrorEmptySequence},
+		{"missing value", u(1, 5), ErrorSequenceTooShort},
+		{"missing entry", u(2, 5, 25), ErrorSequenceTooShort},
+		{"trailing data", u(1, 5, 25, 0), ErrorSequenceTooLong},
+		{"keys out of order", u(2, 5, 25, 3, 9), ErrorInnerDecodingFailure},
+		{"repeated key", u(2, 5, 25, 5, 26), ErrorInnerDecodingFailure},
+		{"key out of range", u(1, 1<<32, 0), ErrorInnerDecodingFailure},
+		{"hostile count", u(1 << 62), ErrorSequenceTooShort},
+	} {
+		_, err := DecodeMap(tt.sequence, newTestUint32, newBField)
+		var codecErr BFieldCodecError
+		if !errors.As(err, &codecErr) || codecErr.Type != tt.want {
+			t.Errorf("%s: got %v, want error type %d", tt.name, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		sequence []field.Element
+		want     ErrorType
+	}{
+		{"missing key length", u(1), ErrorMissingLengthIndicator},
+		{"short key", u(1, 3, 'a'), ErrorSequenceTooShort},
+		{"short value", u(1, 1, 'a', 2, 7), ErrorSequenceTooShort},
+	} {
+		_, err := DecodeMap(tt.sequence, newTestWord, newTestPair)
+		var codecErr BFieldCodecError
+		if !errors.As(err, &codecErr) || codecErr.Type != tt.want {
+			t.Errorf("%s: got %v, want error type %d", tt.name, err, tt.want)
+		}
+	}
+}
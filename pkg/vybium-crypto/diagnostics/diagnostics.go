@@ -0,0 +1,216 @@
+// Package diagnostics provides structured reports of verification and
+// decoding failures.
+//
+// Verifiers in packages merkle and bfieldcodec return a bare boolean or error.
+// When a Diagnostics collector is passed to them, they additionally record an
+// Event for every failure, stating what went wrong (a Reason), where (a
+// Location), and the expected and actual values. Without a collector no
+// events are constructed and verification runs exactly as before.
+package diagnostics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Component identifies the package that reported an event.
+type Component int
+
+const (
+	ComponentMerkle Component = iota
+	ComponentMmr
+	ComponentCodec
+)
+
+// String returns the component's name, suitable as a metrics label.
+func (c Component) String() string {
+	switch c {
+	case ComponentMerkle:
+		return "merkle"
+	case ComponentMmr:
+		return "mmr"
+	case ComponentCodec:
+		return "codec"
+	default:
+		return fmt.Sprintf("component(%d)", int(c))
+	}
+}
+
+// Reason is the cause of a verification or decoding failure.
+type Reason int
+
+const (
+	// ReasonRootMismatch: the recomputed root differs from the expected root.
+	// Location is the root's node index.
+	ReasonRootMismatch Reason = iota
+
+	// ReasonAuthPathLength: the authentication path length does not match
+	// the tree (or peak) height. Location is the leaf index.
+	ReasonAuthPathLength
+
+	// ReasonLeafIndexOutOfRange: a leaf index is not smaller than the number
+	// of leafs. Location is the leaf index.
+	ReasonLeafIndexOutOfRange
+
+	// ReasonNoLeafs: the proof reveals no leafs.
+	ReasonNoLeafs
+
+	// ReasonTreeTooHigh: the stated tree height exceeds the supported maximum.
+	ReasonTreeTooHigh
+
+	// ReasonConflictingLeaf: the proof reveals two different digests for the
+	// same leaf. Location is the leaf's node index.
+	ReasonConflictingLeaf
+
+	// ReasonAuthStructureLength: the authentication structure has the wrong
+	// number of digests.
+	ReasonAuthStructureLength
+
+	// ReasonMissingNode: a sibling needed to recompute the root is missing.
+	// Location is the sibling's node index.
+	ReasonMissingNode
+
+	// ReasonNodeMismatch: a recomputed internal node contradicts a digest
+	// supplied by the proof. Location is the node index.
+	ReasonNodeMismatch
+
+	// ReasonPeakMismatch: the recomputed MMR peak differs from the
+	// accumulator's peak. Location is the peak index.
+	ReasonPeakMismatch
+
+	// ReasonEmptySequence: a codec input is empty. Location is the element
+	// offset.
+	ReasonEmptySequence
+
+	// ReasonSequenceTooShort: a codec input ends prematurely. Location is the
+	// element offset at which more elements were needed.
+	ReasonSequenceTooShort
+
+	// ReasonSequenceTooLong: a codec input has trailing elements. Location is
+	// the offset of the first surplus element.
+	ReasonSequenceTooLong
+
+	// ReasonElementOutOfRange: a codec element has a value outside the
+	// decoded type's range. Location is the element offset.
+	ReasonElementOutOfRange
+
+	// ReasonInvalidLengthIndicator: a codec length or count field is missing
+	// or inconsistent. Location is the element offset of the field.
+	ReasonInvalidLengthIndicator
+
+	// ReasonInnerDecodingFailure: a nested item failed to decode. Location is
+	// the element offset of the item.
+	ReasonInnerDecodingFailure
+)
+
+// String returns the reason code, suitable as a metrics label.
+func (r Reason) String() string {
+	switch r {
+	case ReasonRootMismatch:
+		return "root_mismatch"
+	case ReasonAuthPathLength:
+		return "auth_path_length"
+	case ReasonLeafIndexOutOfRange:
+		return "leaf_index_out_of_range"
+	case ReasonNoLeafs:
+		return "no_leafs"
+	case ReasonTreeTooHigh:
+		return "tree_too_high"
+	case ReasonConflictingLeaf:
+		return "conflicting_leaf"
+	case ReasonAuthStructureLength:
+		return "auth_structure_length"
+	case ReasonMissingNode:
+		return "missing_node"
+	case ReasonNodeMismatch:
+		return "node_mismatch"
+	case ReasonPeakMismatch:
+		return "peak_mismatch"
+	case ReasonEmptySequence:
+		return "empty_sequence"
+	case ReasonSequenceTooShort:
+		return "sequence_too_short"
+	case ReasonSequenceTooLong:
+		return "sequence_too_long"
+	case ReasonElementOutOfRange:
+		return "element_out_of_range"
+	case ReasonInvalidLengthIndicator:
+		return "invalid_length_indicator"
+	case ReasonInnerDecodingFailure:
+		return "inner_decoding_failure"
+	default:
+		return fmt.Sprintf("reason(%d)", int(r))
+	}
+}
+
+// Event describes a single failure.
+type Event struct {
+	Component Component
+	Reason    Reason
+
+	// Location is the node index, leaf index, peak index or element offset
+	// involved, as documented for each Reason.
+	Location uint64
+
+	// Expected and Got are the expected and actual values: digests in
+	// canonical hex, counts and element values in decimal. Either may be
+	// empty if the reason has no meaningful value.
+	Expected string
+	Got      string
+}
+
+// String renders the event on a single line for logs.
+func (e Event) String() string {
+	s := fmt.Sprintf("%s: %s at %d", e.Component, e.Reason, e.Location)
+	if e.Expected != "" || e.Got != "" {
+		s += fmt.Sprintf(": expected %s, got %s", e.Expected, e.Got)
+	}
+	return s
+}
+
+// Labels is the low-cardinality part of an Event, for use as metrics labels.
+type Labels struct {
+	Component string
+	Reason    string
+}
+
+// Labels returns the event's metrics labels.
+func (e Event) Labels() Labels {
+	return Labels{Component: e.Component.String(), Reason: e.Reason.String()}
+}
+
+// Diagnostics collects events reported by verifiers. If OnEvent is set, it is
+// called for each event as it is recorded. A Diagnostics is safe for
+// concurrent use; the zero value is ready to use.
+type Diagnostics struct {
+	// OnEvent, if non-nil, is called with every recorded event.
+	OnEvent func(Event)
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// Record stores an event and passes it to OnEvent.
+func (d *Diagnostics) Record(event Event) {
+	d.mu.Lock()
+	d.events = append(d.events, event)
+	d.mu.Unlock()
+
+	if d.OnEvent != nil {
+		d.OnEvent(event)
+	}
+}
+
+// Events returns a copy of the events recorded so far, in order.
+func (d *Diagnostics) Events() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Event(nil), d.events...)
+}
+
+// Reset discards all recorded events.
+func (d *Diagnostics) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = nil
+}
@@ -0,0 +1,103 @@
+package diagnostics
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestEventString(t *testing.T) {
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{
+			Event{ComponentMerkle, ReasonRootMismatch, 1, "aa", "bb"},
+			"merkle: root_mismatch at 1: expected aa, got bb",
+		},
+		{
+			Event{ComponentMmr, ReasonAuthPathLength, 5, "2", "1"},
+			"mmr: auth_path_length at 5: expected 2, got 1",
+		},
+		{
+			Event{Component: ComponentMerkle, Reason: ReasonNoLeafs},
+			"merkle: no_leafs at 0",
+		},
+		{
+			Event{Component: Component(42), Reason: Reason(42)},
+			"component(42): reason(42) at 0",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.event.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestEventLabels(t *testing.T) {
+	event := Event{ComponentCodec, ReasonSequenceTooShort, 7, "5", "3"}
+	want := Labels{Component: "codec", Reason: "sequence_too_short"}
+	if got := event.Labels(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReasonStringsAreDistinct(t *testing.T) {
+	seen := make(map[string]Reason)
+	for r := ReasonRootMismatch; r <= ReasonInnerDecodingFailure; r++ {
+		s := r.String()
+		if other, ok := seen[s]; ok {
+			t.Errorf("reasons %d and %d share the code %q", r, other, s)
+		}
+		seen[s] = r
+	}
+}
+
+func TestDiagnosticsRecord(t *testing.T) {
+	var forwarded []Event
+	d := &Diagnostics{OnEvent: func(e Event) { forwarded = append(forwarded, e) }}
+
+	first := Event{Component: ComponentMerkle, Reason: ReasonRootMismatch, Location: 1}
+	second := Event{Component: ComponentMmr, Reason: ReasonPeakMismatch, Location: 2}
+	d.Record(first)
+	d.Record(second)
+
+	events := d.Events()
+	if len(events) != 2 || events[0] != first || events[1] != second {
+		t.Fatalf("got %v", events)
+	}
+	if len(forwarded) != 2 || forwarded[1] != second {
+		t.Errorf("OnEvent got %v", forwarded)
+	}
+
+	// Events returns a copy
+	events[0].Location = 99
+	if d.Events()[0].Location != 1 {
+		t.Error("modifying the result changed the recorded events")
+	}
+
+	d.Reset()
+	if len(d.Events()) != 0 {
+		t.Error("Reset did not discard events")
+	}
+}
+
+func TestDiagnosticsConcurrent(t *testing.T) {
+	var d Diagnostics
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				d.Record(Event{Component: ComponentCodec, Reason: ReasonEmptySequence})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(d.Events()); got != 800 {
+		t.Errorf("got %d events, want 800", got)
+	}
+}
@@ -0,0 +1,81 @@
+package vybiumcrypto
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/merkle"
+)
+
+// The functions below are the stable entry point for the common cases:
+// hashing elements or bytes to a digest, committing to digests in a Merkle
+// tree, and proving and verifying membership. Each delegates to the
+// canonical implementation in its subpackage, so results agree with direct
+// use of hash.HashVarlen, hash.HashBytes, merkle.New and
+// merkle.VerifyInclusionProof.
+//
+// The facade is kept deliberately small. Adding to it is adding public API
+// and needs the same scrutiny as a new package: an addition must cover a
+// use most callers share, and must not duplicate an operation the facade
+// already offers. Everything else stays in the subpackages.
+
+// Element is a base field element, re-exported from package field.
+type Element = field.Element
+
+// Digest is a Tip5 digest, re-exported from package hash.
+type Digest = hash.Digest
+
+// MerkleTree is a Tip5 Merkle tree, re-exported from package merkle.
+type MerkleTree = merkle.MerkleTree
+
+// Proof proves that Leaf is the leaf at LeafIndex of a Merkle tree.
+type Proof struct {
+	LeafIndex          uint64
+	Leaf               Digest
+	AuthenticationPath []Digest
+}
+
+// Hash returns the Tip5 digest of the elements, as hash.HashVarlen.
+func Hash(elements []Element) Digest {
+	return hash.HashVarlen(elements)
+}
+
+// HashBytes returns the Tip5 digest of the bytes, as hash.HashBytes.
+func HashBytes(data []byte) Digest {
+	return hash.HashBytes(data)
+}
+
+// BuildTree builds the Merkle tree over the leafs, as merkle.New.
+// Returns an error unless the number of leafs is a power of two.
+func BuildTree(leafs []Digest) (*MerkleTree, error) {
+	return merkle.New(leafs)
+}
+
+// Prove returns the proof that the leaf at the given index is in the tree.
+// Returns an error if the tree is nil or the index is out of range.
+func Prove(tree *MerkleTree, index uint64) (Proof, error) {
+	if tree == nil {
+		return Proof{}, fmt.Errorf("prove: nil tree")
+	}
+	leaf, err := tree.GetLeaf(index)
+	if err != nil {
+		return Proof{}, fmt.Errorf("prove: %w", err)
+	}
+	path, err := tree.AuthenticationPath(index)
+	if err != nil {
+		return Proof{}, fmt.Errorf("prove: %w", err)
+	}
+	return Proof{LeafIndex: index, Leaf: leaf, AuthenticationPath: path}, nil
+}
+
+// Verify reports whether the proof shows its leaf to be in the tree with the
+// given root. The leaf index must fit in a tree of the authentication
+// path's height.
+func Verify(root Digest, proof Proof) bool {
+	height := len(proof.AuthenticationPath)
+	if height < 64 && proof.LeafIndex>>height != 0 {
+		return false
+	}
+	return merkle.VerifyInclusionProof(root, proof.LeafIndex, proof.Leaf, proof.AuthenticationPath)
+}
@@ -0,0 +1,159 @@
+package vybiumcrypto
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func elements(values ...uint64) []Element {
+	result := make([]Element, len(values))
+	for i, v := range values {
+		result[i] = field.New(v)
+	}
+	return result
+}
+
+// facadeLeafs returns the digests of the single elements 0..n-1.
+func facadeLeafs(n int) []Digest {
+	leafs := make([]Digest, n)
+	for i := range leafs {
+		leafs[i] = Hash(elements(uint64(i)))
+	}
+	return leafs
+}
+
+func TestFacadeGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	tree, err := BuildTree(facadeLeafs(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  Digest
+		want string
+	}{
+		{"Hash(1, 2, 3)", Hash(elements(1, 2, 3)), "4329a2ef7a1d650e99d743fa184f852a66cf567f83c91393f8b66c9fa68b34b2364c826a8df29511"},
+		{"Hash()", Hash(nil), "403651cf544769206972310ae1752412618ccc448b11612fcb220e193b03b81d0a331f441175cd52"},
+		{"HashBytes(vybium)", HashBytes([]byte("vybium")), "cb8a79bc6afc67d312b88a020523bb4728cc4998d258b64a4c0c82b4f6cee6f2c02718e721e47187"},
+		{"BuildTree root", tree.Root(), "a1f6a63d39f9e7bb801f5f4e0d33179cb6d76c3d7addeafa23eebf0d83660f94023775085c469ae2"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.Hex(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHashDistinguishesInputs(t *testing.T) {
+	inputs := [][]Element{nil, elements(0), elements(0, 0), elements(1), elements(1, 0)}
+	seen := make(map[Digest]int)
+	for i, input := range inputs {
+		digest := Hash(input)
+		if j, ok := seen[digest]; ok {
+			t.Errorf("inputs %d and %d hash to the same digest", j, i)
+		}
+		seen[digest] = i
+	}
+	if HashBytes(nil) == HashBytes([]byte{0}) {
+		t.Error("HashBytes does not bind the length")
+	}
+}
+
+func TestProveVerify(t *testing.T) {
+	for _, n := range []int{1, 2, 8, 64} {
+		tree, err := BuildTree(facadeLeafs(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < n; i++ {
+			proof, err := Prove(tree, uint64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Verify(tree.Root(), proof) {
+				t.Errorf("%d leafs: proof of leaf %d rejected", n, i)
+			}
+		}
+	}
+}
+
+func TestVerifyRejectsTamperedProofs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1202))
+	tree, err := BuildTree(facadeLeafs(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+	index := uint64(rng.Intn(16))
+	proof, err := Prove(tree, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := map[string]func(p *Proof){
+		"leaf":        func(p *Proof) { p.Leaf[0] = p.Leaf[0].Add(field.One) },
+		"index":       func(p *Proof) { p.LeafIndex ^= 1 },
+		"beyond tree": func(p *Proof) { p.LeafIndex += 16 },
+		"path":        func(p *Proof) { p.AuthenticationPath[2][4] = p.AuthenticationPath[2][4].Add(field.One) },
+		"short path":  func(p *Proof) { p.AuthenticationPath = p.AuthenticationPath[:3] },
+		"long path":   func(p *Proof) { p.AuthenticationPath = append(p.AuthenticationPath, root) },
+	}
+	for name, apply := range tamper {
+		tampered := proof
+		tampered.AuthenticationPath = append([]Digest(nil), proof.AuthenticationPath...)
+		apply(&tampered)
+		if Verify(root, tampered) {
+			t.Errorf("tampered %s accepted", name)
+		}
+	}
+
+	otherTree, err := BuildTree(facadeLeafs(16)[:8])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Verify(otherTree.Root(), proof) {
+		t.Error("proof accepted under another root")
+	}
+}
+
+func TestVerifyRejectsIndexOutsideSingleLeafTree(t *testing.T) {
+	tree, err := BuildTree(facadeLeafs(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(tree, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With an empty path the leaf is the root whatever the index; only the
+	// index check rejects leaf 1 of a one-leaf tree.
+	proof.LeafIndex = 1
+	if Verify(tree.Root(), proof) {
+		t.Error("leaf 1 of a one-leaf tree accepted")
+	}
+}
+
+func TestFacadeErrors(t *testing.T) {
+	if _, err := BuildTree(facadeLeafs(3)); err == nil {
+		t.Error("tree of three leafs built")
+	}
+	if _, err := BuildTree(nil); err == nil {
+		t.Error("empty tree built")
+	}
+	if _, err := Prove(nil, 0); err == nil {
+		t.Error("proof from nil tree")
+	}
+	tree, err := BuildTree(facadeLeafs(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Prove(tree, 4); err == nil {
+		t.Error("proof of leaf 4 of 4")
+	}
+}
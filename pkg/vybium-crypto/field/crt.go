@@ -0,0 +1,108 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PrimeFactor is a prime factor of a number together with its multiplicity.
+type PrimeFactor struct {
+	Prime        uint64
+	Multiplicity uint
+}
+
+// PMinusOneFactorization is the prime factorization of P - 1:
+//
+//	P - 1 = 2^32 · 3 · 5 · 17 · 257 · 65537
+//
+// The odd factors multiply to 2^32 - 1. It must not be modified.
+var PMinusOneFactorization = []PrimeFactor{
+	{Prime: 2, Multiplicity: 32},
+	{Prime: 3, Multiplicity: 1},
+	{Prime: 5, Multiplicity: 1},
+	{Prime: 17, Multiplicity: 1},
+	{Prime: 257, Multiplicity: 1},
+	{Prime: 65537, Multiplicity: 1},
+}
+
+// Mod returns the element's canonical value reduced modulo m.
+// Panics if m is zero.
+func (e Element) Mod(m uint64) uint64 {
+	if m == 0 {
+		panic("modulus must be non-zero")
+	}
+	return e.Value() % m
+}
+
+// CRTDecompose returns the residues of the element's canonical value modulo
+// each of the moduli. Panics if a modulus is zero.
+func CRTDecompose(e Element, moduli []uint64) []uint64 {
+	residues := make([]uint64, len(moduli))
+	for i, m := range moduli {
+		residues[i] = e.Mod(m)
+	}
+	return residues
+}
+
+// CRTRecombine returns the element whose canonical value is the unique
+// x < M = ∏ moduli with x ≡ residues[i] (mod moduli[i]). If M ≤ P, several
+// elements share these residues and x is the smallest; round-trips through
+// CRTDecompose are exact only for values below M.
+// Returns an error if the lengths differ, there are no moduli, a modulus is
+// zero, a residue is not below its modulus, the moduli are not pairwise
+// coprime, or x is at least P and so names no element.
+func CRTRecombine(residues, moduli []uint64) (Element, error) {
+	if len(residues) != len(moduli) {
+		return Zero, fmt.Errorf("got %d residues for %d moduli", len(residues), len(moduli))
+	}
+	if len(moduli) == 0 {
+		return Zero, fmt.Errorf("no moduli given")
+	}
+	for i, m := range moduli {
+		if m == 0 {
+			return Zero, fmt.Errorf("modulus %d is zero", i)
+		}
+		if residues[i] >= m {
+			return Zero, fmt.Errorf("residue %d is %d, not below its modulus %d", i, residues[i], m)
+		}
+		for j := 0; j < i; j++ {
+			if g := gcd(moduli[i], moduli[j]); g != 1 {
+				return Zero, fmt.Errorf("moduli %d and %d are not coprime: both divisible by %d", moduli[j], moduli[i], g)
+			}
+		}
+	}
+
+	// x = Σ residues[i] · (M/m_i) · ((M/m_i)⁻¹ mod m_i)  mod M
+	product := big.NewInt(1)
+	for _, m := range moduli {
+		product.Mul(product, new(big.Int).SetUint64(m))
+	}
+
+	x := new(big.Int)
+	for i, m := range moduli {
+		modulus := new(big.Int).SetUint64(m)
+		cofactor := new(big.Int).Quo(product, modulus)
+		// The inverse exists since the moduli are coprime; for m = 1 it is 0
+		inverse := new(big.Int).ModInverse(new(big.Int).Mod(cofactor, modulus), modulus)
+		if inverse == nil {
+			inverse = new(big.Int)
+		}
+		term := new(big.Int).SetUint64(residues[i])
+		term.Mul(term, cofactor).Mul(term, inverse)
+		x.Add(x, term)
+	}
+	x.Mod(x, product)
+
+	if !x.IsUint64() || x.Uint64() >= P {
+		return Zero, fmt.Errorf("recombined value %s is not below P", x)
+	}
+	return New(x.Uint64()), nil
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+package field
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// crtModuli has product 7·(P-1) > P, so every element round-trips.
+var crtModuli = []uint64{1 << 32, 3, 5, 17, 257, 65537, 7}
+
+func TestPMinusOneFactorization(t *testing.T) {
+	product := big.NewInt(1)
+	for _, f := range PMinusOneFactorization {
+		if !new(big.Int).SetUint64(f.Prime).ProbablyPrime(20) {
+			t.Errorf("%d is not prime", f.Prime)
+		}
+		for i := uint(0); i < f.Multiplicity; i++ {
+			product.Mul(product, new(big.Int).SetUint64(f.Prime))
+		}
+	}
+	if !product.IsUint64() || product.Uint64() != P-1 {
+		t.Errorf("factors multiply to %s, want P-1", product)
+	}
+}
+
+func TestModMatchesBigInt(t *testing.T) {
+	values := []uint64{0, 1, 2, 1<<32 - 1, 1 << 32, 1<<32 + 1, P - 2, P - 1}
+	moduli := []uint64{1, 2, 3, 5, 7, 17, 257, 65537, 1 << 32, 1<<32 - 1, P - 1, P, P + 1, ^uint64(0)}
+
+	rng := rand.New(rand.NewSource(1175))
+	for i := 0; i < 100; i++ {
+		values = append(values, rng.Uint64()%P)
+		moduli = append(moduli, rng.Uint64()|1)
+	}
+
+	for _, v := range values {
+		for _, m := range moduli {
+			want := new(big.Int).Mod(new(big.Int).SetUint64(v), new(big.Int).SetUint64(m)).Uint64()
+			if got := New(v).Mod(m); got != want {
+				t.Errorf("%d mod %d: got %d, want %d", v, m, got, want)
+			}
+		}
+	}
+
+	// Values at or above P are reduced before the modulus is taken
+	if got := New(P + 4).Mod(5); got != 4 {
+		t.Errorf("New(P+4) mod 5: got %d, want 4", got)
+	}
+}
+
+func TestModZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero modulus")
+		}
+	}()
+	One.Mod(0)
+}
+
+func TestCRTRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1175))
+	elements := []Element{Zero, One, Max, New(P - 2), New(1 << 32), New(1<<32 - 1)}
+	for i := 0; i < 1000; i++ {
+		elements = append(elements, New(rng.Uint64()))
+	}
+
+	for _, e := range elements {
+		residues := CRTDecompose(e, crtModuli)
+		got, err := CRTRecombine(residues, crtModuli)
+		if err != nil {
+			t.Fatalf("%v: %v", e, err)
+		}
+		if got != e {
+			t.Fatalf("%v: recombined to %v", e, got)
+		}
+	}
+}
+
+func TestCRTRecombineBelowProduct(t *testing.T) {
+	// The odd factors of P-1 multiply to 2^32-1: smaller values round-trip,
+	// larger ones recombine to their residue
+	odd := []uint64{3, 5, 17, 257, 65537}
+	for _, v := range []uint64{0, 12345, 1<<32 - 2} {
+		got, err := CRTRecombine(CRTDecompose(New(v), odd), odd)
+		if err != nil || got.Value() != v {
+			t.Errorf("%d: got %v, %v", v, got, err)
+		}
+	}
+	got, err := CRTRecombine(CRTDecompose(Max, odd), odd)
+	if err != nil || got.Value() != (P-1)%(1<<32-1) {
+		t.Errorf("P-1: got %v, %v", got, err)
+	}
+
+	// A modulus of 1 contributes nothing
+	got, err = CRTRecombine([]uint64{0, 2}, []uint64{1, 5})
+	if err != nil || got.Value() != 2 {
+		t.Errorf("with modulus 1: got %v, %v", got, err)
+	}
+}
+
+func TestCRTRecombineErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		residues []uint64
+		moduli   []uint64
+	}{
+		{"length mismatch", []uint64{1}, []uint64{3, 5}},
+		{"no moduli", nil, nil},
+		{"zero modulus", []uint64{0, 1}, []uint64{0, 5}},
+		{"residue too large", []uint64{3, 1}, []uint64{3, 5}},
+		{"not coprime", []uint64{1, 1}, []uint64{6, 9}},
+		{"repeated modulus", []uint64{1, 1}, []uint64{5, 5}},
+		// x ≡ -1 mod every modulus: x = 7·(P-1) - 1 ≥ P
+		{"above P", []uint64{1<<32 - 1, 2, 4, 16, 256, 65536, 6}, crtModuli},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CRTRecombine(tt.residues, tt.moduli); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/params"
 )
 
 // P is the prime modulus: 2^64 - 2^32 + 1
-const P uint64 = 0xFFFFFFFF00000001
+const P = params.FieldModulus
 
 // R2 is 2^128 mod P, used for conversion into Montgomery representation
 const R2 uint64 = 0xFFFFFFFE00000001
@@ -178,8 +180,19 @@ func (e Element) Square() Element {
 	return e.Mul(e)
 }
 
+// InverseChecked computes the multiplicative inverse like Inverse, but
+// returns an error instead of panicking when e is zero. Use it where e may
+// come from untrusted input.
+func (e Element) InverseChecked() (Element, error) {
+	if e.IsZero() {
+		return Zero, fmt.Errorf("attempted to find the multiplicative inverse of zero")
+	}
+	return e.Inverse(), nil
+}
+
 // Inverse computes the multiplicative inverse: a^(-1) mod P
 // Uses the optimized inversion chain from twenty-first.
+// Panics if e is zero; see InverseChecked.
 //
 // Production implementation.
 func (e Element) Inverse() Element {
@@ -212,6 +225,37 @@ func (e Element) Inverse() Element {
 	return exp(bin31Ones1Zero, 32).Mul(bin32Ones)
 }
 
+// BatchInverse returns the inverses of the elements in a new slice, leaving
+// the input unchanged. Zero elements have no inverse; their outputs are
+// Zero. It uses Montgomery's trick: one Inverse of the product of the
+// non-zero elements, and three multiplications per element to recover the
+// individual inverses from prefix products.
+func BatchInverse(elements []Element) []Element {
+	inverses := make([]Element, len(elements))
+
+	// inverses[i] holds the product of the non-zero elements before i.
+	product := One
+	for i, e := range elements {
+		inverses[i] = product
+		if !e.IsZero() {
+			product = product.Mul(e)
+		}
+	}
+
+	// suffixInverse is the inverse of the product of the non-zero elements
+	// up to and including i.
+	suffixInverse := product.Inverse()
+	for i := len(elements) - 1; i >= 0; i-- {
+		if elements[i].IsZero() {
+			inverses[i] = Zero
+			continue
+		}
+		inverses[i] = inverses[i].Mul(suffixInverse)
+		suffixInverse = suffixInverse.Mul(elements[i])
+	}
+	return inverses
+}
+
 // ModPow computes modular exponentiation: a^exp mod P
 // Uses binary exponentiation in Montgomery form.
 //
@@ -236,6 +280,72 @@ func (e Element) ModPow(exp uint64) Element {
 	return acc
 }
 
+// Legendre returns the Legendre symbol (a / P): 0 if a is zero, 1 if a is a
+// non-zero square, and -1 if a is a quadratic non-residue.
+// Computed by Euler's criterion, a^((P-1)/2).
+func (e Element) Legendre() int {
+	if e.IsZero() {
+		return 0
+	}
+	if e.ModPow((P - 1) / 2).IsOne() {
+		return 1
+	}
+	return -1
+}
+
+// LegendreSymbol returns the Legendre symbol of e, -1, 0 or 1; it is the
+// same as Legendre.
+func (e Element) LegendreSymbol() int {
+	return e.Legendre()
+}
+
+// twoAdicity is the largest s with 2^s dividing P-1 = 2^32 * (2^32 - 1).
+const twoAdicity = 32
+
+// Sqrt returns a square root of e and true if e is a square, or Zero and
+// false if it is not. Of the two roots r and -r it returns the one with the
+// smaller canonical value, so the result is independent of the algorithm.
+//
+// It uses Tonelli-Shanks with P-1 = 2^32 * q for odd q = 2^32 - 1: the
+// candidate e^((q+1)/2) is corrected by powers of a primitive 2^32-th root
+// of unity, at most 32 steps of at most 32 squarings each.
+func (e Element) Sqrt() (Element, bool) {
+	if e.IsZero() {
+		return Zero, true
+	}
+	const q = (P - 1) >> twoAdicity
+
+	// c generates the 2-Sylow subgroup, the generator being a non-residue.
+	c := Generator().ModPow(q)
+	t := e.ModPow(q)
+	r := e.ModPow((q + 1) / 2)
+	// Invariant: r^2 = e*t, and t has order dividing 2^m.
+	m := twoAdicity
+	for !t.IsOne() {
+		// Find the order 2^i of t; a non-residue's t has order 2^m.
+		i := 0
+		for t2 := t; !t2.IsOne(); t2 = t2.Square() {
+			i++
+		}
+		if i == m {
+			return Zero, false
+		}
+		b := c
+		for j := 0; j < m-i-1; j++ {
+			b = b.Square()
+		}
+		m = i
+		c = b.Square()
+		t = t.Mul(c)
+		r = r.Mul(b)
+	}
+
+	if negated := r.Neg(); negated.Value() < r.Value() {
+		return negated, true
+	}
+	return r, true
+}
+
 // Neg returns the additive inverse: -a mod P
 func (e Element) Neg() Element {
 	if e.IsZero() {
@@ -264,20 +374,51 @@ func (e Element) ToBigInt() *big.Int {
 	return new(big.Int).SetUint64(e.Value())
 }
 
-// ToBytes returns the little-endian byte representation.
+// ToBytes returns the little-endian bytes of the element's Montgomery form,
+// RawValue, not of its canonical value. It is for storage read back by
+// FromBytes; to exchange elements with other systems use
+// ToCanonicalBytesBE.
 func (e Element) ToBytes() [8]byte {
 	var bytes [8]byte
 	binary.LittleEndian.PutUint64(bytes[:], e.value)
 	return bytes
 }
 
-// FromBytes creates an element from little-endian bytes.
+// FromBytes creates an element from the little-endian bytes of its
+// Montgomery form, as written by ToBytes.
 func FromBytes(bytes [8]byte) Element {
 	raw := binary.LittleEndian.Uint64(bytes[:])
 	return NewFromRaw(raw)
 }
 
-// MarshalBinary implements encoding.BinaryMarshaler.
+// ToCanonicalBytesBE returns the big-endian bytes of the element's
+// canonical value, Value.
+func (e Element) ToCanonicalBytesBE() [8]byte {
+	var bytes [8]byte
+	binary.BigEndian.PutUint64(bytes[:], e.Value())
+	return bytes
+}
+
+// FromCanonicalBytesBE creates the element whose canonical value has the
+// given big-endian bytes, inverting ToCanonicalBytesBE. Values of at least P
+// are reduced mod P, as by New; see FromCanonicalBytesBEChecked.
+func FromCanonicalBytesBE(bytes [8]byte) Element {
+	return New(binary.BigEndian.Uint64(bytes[:]))
+}
+
+// FromCanonicalBytesBEChecked is FromCanonicalBytesBE, returning an error
+// instead of reducing if the value is at least P, so that every element has
+// exactly one accepted encoding.
+func FromCanonicalBytesBEChecked(bytes [8]byte) (Element, error) {
+	value := binary.BigEndian.Uint64(bytes[:])
+	if value >= P {
+		return Zero, fmt.Errorf("non-canonical field element encoding: %d is at least P", value)
+	}
+	return New(value), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, writing the
+// Montgomery-form bytes of ToBytes.
 func (e Element) MarshalBinary() ([]byte, error) {
 	bytes := e.ToBytes()
 	return bytes[:], nil
@@ -76,6 +76,29 @@ func BenchmarkElementInverse(b *testing.B) {
 	_ = result
 }
 
+func BenchmarkBatchInverse(b *testing.B) {
+	elements := make([]Element, 1024)
+	for i := range elements {
+		elements[i] = New(uint64(i + 1))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = BatchInverse(elements)
+	}
+}
+
+func BenchmarkElementSqrt(b *testing.B) {
+	a := New(123456789).Square()
+	var result Element
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, _ = a.Sqrt()
+	}
+	_ = result
+}
+
 func BenchmarkElementDiv(b *testing.B) {
 	a := New(987654321)
 	c := New(123456789)
@@ -252,3 +275,18 @@ func BenchmarkElementHornerEvaluation(b *testing.B) {
 	}
 	_ = result
 }
+
+func BenchmarkBatchToLimbs16(b *testing.B) {
+	elements := make([]Element, 1<<16)
+	for i := range elements {
+		elements[i] = New(uint64(i) * 0x9e3779b97f4a7c15)
+	}
+	limbs := make([]uint64, len(elements)*NumLimbs(16))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := BatchToLimbs(elements, 16, limbs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package field
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest"
+)
+
+// randomLawElement returns a random element, favouring edge cases.
+func randomLawElement(rng *rand.Rand) Element {
+	switch rng.Intn(8) {
+	case 0:
+		return Zero
+	case 1:
+		return One
+	case 2:
+		return Max
+	case 3:
+		return New(uint64(1)<<32 + uint64(rng.Intn(3)) - 1)
+	default:
+		return New(rng.Uint64())
+	}
+}
+
+// lawRing describes the field for the lawtest checkers.
+var lawRing = lawtest.Ring[Element]{
+	Name:   "field",
+	Zero:   Zero,
+	One:    One,
+	Add:    Element.Add,
+	Sub:    Element.Sub,
+	Mul:    Element.Mul,
+	Neg:    Element.Neg,
+	Equal:  Element.Equal,
+	Random: randomLawElement,
+}
+
+func TestFieldLaws(t *testing.T) {
+	lawtest.CheckField(t, lawRing, Element.Inverse, lawtest.Config{Seed: 1173})
+}
+
+// TestMontgomeryRoundTrip checks the Montgomery representation: New reduces
+// modulo P, Value inverts New, the raw form round-trips, and results of
+// arithmetic stay in canonical raw form, which Equal relies on.
+func TestMontgomeryRoundTrip(t *testing.T) {
+	lawtest.ForEachCase(t, lawtest.Config{Seed: 1173}, func(rng *rand.Rand, seed int64) bool {
+		v := rng.Uint64()
+		if rng.Intn(4) == 0 {
+			v = P + uint64(rng.Intn(1<<16))
+		}
+		e := New(v)
+		if e.Value() != v%P {
+			t.Errorf("seed %d: New(%d).Value() = %d, want %d", seed, v, e.Value(), v%P)
+			return false
+		}
+		if NewFromRaw(e.RawValue()) != e {
+			t.Errorf("seed %d: raw form of %v does not round-trip", seed, e)
+			return false
+		}
+
+		a, b := randomLawElement(rng), randomLawElement(rng)
+		for _, r := range []Element{e, a.Add(b), a.Sub(b), a.Mul(b), a.Neg(), a.Square()} {
+			if r.RawValue() >= P {
+				t.Errorf("seed %d: non-canonical raw value %d", seed, r.RawValue())
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -1,7 +1,10 @@
 package field
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"math/big"
+	"math/rand"
 	"testing"
 )
 
@@ -51,6 +54,21 @@ func TestElementInverse(t *testing.T) {
 	}
 }
 
+func TestElementInverseChecked(t *testing.T) {
+	a := New(42)
+	inv, err := a.InverseChecked()
+	if err != nil {
+		t.Fatalf("InverseChecked(42): %v", err)
+	}
+	if inv != a.Inverse() {
+		t.Errorf("InverseChecked(42) = %v, want %v", inv, a.Inverse())
+	}
+
+	if _, err := Zero.InverseChecked(); err == nil {
+		t.Error("InverseChecked(0) returned no error")
+	}
+}
+
 func TestElementModPow(t *testing.T) {
 	// Test modular exponentiation
 	base := New(3)
@@ -74,6 +92,51 @@ func TestElementModPow(t *testing.T) {
 	}
 }
 
+func TestElementLegendre(t *testing.T) {
+	if Zero.Legendre() != 0 {
+		t.Error("Legendre symbol of zero should be 0")
+	}
+
+	// The generator is a non-residue; its even powers are residues
+	g := Generator()
+	if g.Legendre() != -1 {
+		t.Error("generator should be a quadratic non-residue")
+	}
+	for _, k := range []uint64{0, 1, 2, 5, 1 << 40} {
+		if got := g.ModPow(2 * k).Legendre(); got != 1 {
+			t.Errorf("g^%d: got Legendre symbol %d, want 1", 2*k, got)
+		}
+		if got := g.ModPow(2*k + 1).Legendre(); got != -1 {
+			t.Errorf("g^%d: got Legendre symbol %d, want -1", 2*k+1, got)
+		}
+	}
+
+	// -1 is a square since P ≡ 1 (mod 4)
+	if One.Neg().Legendre() != 1 {
+		t.Error("-1 should be a quadratic residue")
+	}
+}
+
+func TestElementLegendreSymbol(t *testing.T) {
+	tests := []struct {
+		name    string
+		element Element
+		want    int
+	}{
+		{"zero", Zero, 0},
+		{"one", One, 1},
+		{"four", New(4), 1},
+		{"minus one", One.Neg(), 1},
+		{"generator", Generator(), -1},
+		{"generator cubed", Generator().ModPow(3), -1},
+	}
+	for _, tt := range tests {
+		if got := tt.element.LegendreSymbol(); got != tt.want || got != tt.element.Legendre() {
+			t.Errorf("%s: got LegendreSymbol %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestElementNegation(t *testing.T) {
 	// Test additive inverse
 	a := New(42)
@@ -220,3 +283,148 @@ func TestElementModularReduction(t *testing.T) {
 		t.Errorf("Modular reduction failed: expected %v, got %v", expected, large)
 	}
 }
+
+func TestBatchInverse(t *testing.T) {
+	rng := rand.New(rand.NewSource(1252))
+	for _, size := range []int{0, 1, 100} {
+		elements := make([]Element, size)
+		for i := range elements {
+			elements[i] = New(rng.Uint64()%(P-1) + 1)
+		}
+		// Zeros at the ends and in the middle of the larger slice.
+		if size == 100 {
+			elements[0], elements[37], elements[99] = Zero, Zero, Zero
+		}
+		input := append([]Element(nil), elements...)
+
+		inverses := BatchInverse(elements)
+		if len(inverses) != size {
+			t.Fatalf("size %d: got %d inverses", size, len(inverses))
+		}
+		for i, e := range elements {
+			if !e.Equal(input[i]) {
+				t.Fatalf("size %d: input %d modified", size, i)
+			}
+			want := Zero
+			if !e.IsZero() {
+				want = e.Inverse()
+			}
+			if !inverses[i].Equal(want) {
+				t.Errorf("size %d: inverse %d = %d, want %d", size, i, inverses[i].Value(), want.Value())
+			}
+		}
+	}
+
+	if got := BatchInverse([]Element{Zero, Zero}); !got[0].IsZero() || !got[1].IsZero() {
+		t.Errorf("inverses of zeros = %v, want zeros", got)
+	}
+}
+
+func TestElementSqrt(t *testing.T) {
+	rng := rand.New(rand.NewSource(1253))
+	for i := 0; i < 200; i++ {
+		x := New(rng.Uint64())
+		square := x.Square()
+		root, ok := square.Sqrt()
+		if !ok {
+			t.Fatalf("%d: no root found", square.Value())
+		}
+		if !root.Square().Equal(square) {
+			t.Fatalf("%d: root %d squares to %d", square.Value(), root.Value(), root.Square().Value())
+		}
+		if !root.Equal(x) && !root.Equal(x.Neg()) {
+			t.Fatalf("%d: root %d is neither %d nor its negation", square.Value(), root.Value(), x.Value())
+		}
+		if root.Neg().Value() < root.Value() {
+			t.Fatalf("%d: root %d is not the smaller of the two", square.Value(), root.Value())
+		}
+		if square.Legendre() != 1 {
+			t.Fatalf("%d: Legendre symbol %d for a square", square.Value(), square.Legendre())
+		}
+	}
+
+	// Odd powers of the generator are non-residues.
+	g := Generator()
+	for _, k := range []uint64{0, 1, 2, 5, 1 << 40, rng.Uint64() >> 1} {
+		x := g.ModPow(2*k + 1)
+		if root, ok := x.Sqrt(); ok || !root.IsZero() {
+			t.Errorf("g^%d: got root %d, %v for a non-residue", 2*k+1, root.Value(), ok)
+		}
+	}
+
+	// Elements whose roots have order 2^32, the deepest Tonelli-Shanks case.
+	for _, order := range []uint64{2, 4, 1 << 16, 1 << 32} {
+		w := mustPrimitiveRoot(t, order)
+		root, ok := w.Square().Sqrt()
+		if !ok || !root.Square().Equal(w.Square()) {
+			t.Errorf("square of a primitive %d-th root: got %d, %v", order, root.Value(), ok)
+		}
+	}
+
+	for _, tc := range []struct {
+		x, want uint64
+	}{{0, 0}, {1, 1}, {4, 2}, {P - 1, mustPrimitiveRoot(t, 4).Value()}} {
+		root, ok := New(tc.x).Sqrt()
+		want := New(tc.want)
+		if want.Neg().Value() < want.Value() {
+			want = want.Neg()
+		}
+		if !ok || !root.Equal(want) {
+			t.Errorf("Sqrt(%d) = %d, %v; want %d", tc.x, root.Value(), ok, want.Value())
+		}
+	}
+}
+
+func TestCanonicalBytesBE(t *testing.T) {
+	tests := []struct {
+		value uint64
+		want  string
+	}{
+		{0, "0000000000000000"},
+		{1, "0000000000000001"},
+		{0x0123456789abcdef, "0123456789abcdef"},
+		{P - 1, "ffffffff00000000"},
+	}
+	for _, tt := range tests {
+		x := New(tt.value)
+		bytes := x.ToCanonicalBytesBE()
+		if got := hex.EncodeToString(bytes[:]); got != tt.want {
+			t.Errorf("ToCanonicalBytesBE(%d) = %s, want %s", tt.value, got, tt.want)
+		}
+		if back := FromCanonicalBytesBE(bytes); !back.Equal(x) {
+			t.Errorf("FromCanonicalBytesBE(%s) = %d, want %d", tt.want, back.Value(), tt.value)
+		}
+		if back, err := FromCanonicalBytesBEChecked(bytes); err != nil || !back.Equal(x) {
+			t.Errorf("FromCanonicalBytesBEChecked(%s) = %d, %v", tt.want, back.Value(), err)
+		}
+	}
+
+	// The Montgomery-form bytes differ from the canonical ones.
+	x := New(1)
+	if montgomery, canonical := x.ToBytes(), x.ToCanonicalBytesBE(); montgomery == canonical {
+		t.Error("ToBytes of 1 is its canonical encoding")
+	}
+
+	rng := rand.New(rand.NewSource(1255))
+	for i := 0; i < 100; i++ {
+		x := New(rng.Uint64())
+		if back := FromCanonicalBytesBE(x.ToCanonicalBytesBE()); !back.Equal(x) {
+			t.Fatalf("round trip of %d gave %d", x.Value(), back.Value())
+		}
+	}
+}
+
+func TestFromCanonicalBytesBENonCanonical(t *testing.T) {
+	var bytes [8]byte
+	binary.BigEndian.PutUint64(bytes[:], P+3)
+	if got := FromCanonicalBytesBE(bytes); got.Value() != 3 {
+		t.Errorf("FromCanonicalBytesBE(P+3) = %d, want 3", got.Value())
+	}
+	if _, err := FromCanonicalBytesBEChecked(bytes); err == nil {
+		t.Error("FromCanonicalBytesBEChecked accepted P+3")
+	}
+	binary.BigEndian.PutUint64(bytes[:], P)
+	if _, err := FromCanonicalBytesBEChecked(bytes); err == nil {
+		t.Error("FromCanonicalBytesBEChecked accepted P")
+	}
+}
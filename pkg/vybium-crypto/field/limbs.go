@@ -0,0 +1,149 @@
+package field
+
+import (
+	"fmt"
+)
+
+// Limb decompositions split an element's canonical value into 64/width limbs
+// of width bits each, for range checks and lookup arguments in circuits.
+// Limbs are always in little-endian order: limb 0 holds the least
+// significant width bits. The supported widths are 1, 2, 4, 8, 16 and 32.
+//
+// A decomposition is canonical if it recomposes to a value below P. Values
+// in [P, 2^64) alias elements in [0, 2^32 - 1) and are rejected by
+// FromLimbs, so every element has exactly one accepted decomposition.
+
+// validateLimbWidth checks that width is a supported limb width.
+func validateLimbWidth(width uint) error {
+	switch width {
+	case 1, 2, 4, 8, 16, 32:
+		return nil
+	default:
+		return fmt.Errorf("unsupported limb width %d: must be one of 1, 2, 4, 8, 16, 32", width)
+	}
+}
+
+// NumLimbs returns the number of limbs of the given width per element.
+// Panics if the width is not supported.
+func NumLimbs(width uint) int {
+	if err := validateLimbWidth(width); err != nil {
+		panic(err.Error())
+	}
+	return 64 / int(width)
+}
+
+// ToLimbs decomposes the element's canonical value into little-endian limbs
+// of the given width. Panics if the width is not supported.
+func (e Element) ToLimbs(width uint) []uint64 {
+	limbs := make([]uint64, NumLimbs(width))
+	e.putLimbs(width, limbs)
+	return limbs
+}
+
+// putLimbs writes the element's limbs into dst, which has NumLimbs(width)
+// entries.
+func (e Element) putLimbs(width uint, dst []uint64) {
+	value := e.Value()
+	mask := uint64(1)<<width - 1
+	for i := range dst {
+		dst[i] = value & mask
+		value >>= width
+	}
+}
+
+// FromLimbs recomposes an element from little-endian limbs of the given
+// width. Returns an error if the width is not supported, the number of limbs
+// is not NumLimbs(width), a limb does not fit in width bits, or the
+// recomposed value is not canonical (at least P).
+func FromLimbs(width uint, limbs []uint64) (Element, error) {
+	if err := validateLimbWidth(width); err != nil {
+		return Zero, err
+	}
+	if len(limbs) != 64/int(width) {
+		return Zero, fmt.Errorf("got %d limbs of width %d, expected %d", len(limbs), width, 64/int(width))
+	}
+	return fromLimbs(width, limbs)
+}
+
+// fromLimbs recomposes an element from a limb slice of the right length.
+func fromLimbs(width uint, limbs []uint64) (Element, error) {
+	var value uint64
+	for i := len(limbs) - 1; i >= 0; i-- {
+		if limbs[i]>>width != 0 {
+			return Zero, fmt.Errorf("limb %d has value %d, exceeding width %d", i, limbs[i], width)
+		}
+		value = value<<width | limbs[i]
+	}
+	if value >= P {
+		return Zero, fmt.Errorf("non-canonical decomposition: value %d is not below P", value)
+	}
+	return New(value), nil
+}
+
+// ToBits returns the bits of the element's canonical value, least
+// significant first. It agrees with ToLimbs(1).
+func (e Element) ToBits() [64]bool {
+	var bits [64]bool
+	value := e.Value()
+	for i := range bits {
+		bits[i] = value&1 == 1
+		value >>= 1
+	}
+	return bits
+}
+
+// BatchToLimbs decomposes each element into little-endian limbs of the given
+// width, writing the limbs of elements[i] to
+// dst[i*NumLimbs(width) : (i+1)*NumLimbs(width)].
+// Returns an error if the width is not supported or dst has the wrong length.
+func BatchToLimbs(elements []Element, width uint, dst []uint64) error {
+	if err := validateLimbWidth(width); err != nil {
+		return err
+	}
+	numLimbs := 64 / int(width)
+	if len(dst) != len(elements)*numLimbs {
+		return fmt.Errorf("destination has %d entries, expected %d", len(dst), len(elements)*numLimbs)
+	}
+
+	for i, e := range elements {
+		e.putLimbs(width, dst[i*numLimbs:(i+1)*numLimbs])
+	}
+	return nil
+}
+
+// BatchFromLimbs recomposes elements from limbs laid out as by BatchToLimbs,
+// writing them to dst. Returns an error if the width is not supported, the
+// lengths do not match, or any decomposition is invalid as for FromLimbs; dst
+// is then partially written.
+func BatchFromLimbs(width uint, limbs []uint64, dst []Element) error {
+	if err := validateLimbWidth(width); err != nil {
+		return err
+	}
+	numLimbs := 64 / int(width)
+	if len(limbs) != len(dst)*numLimbs {
+		return fmt.Errorf("got %d limbs of width %d for %d elements, expected %d", len(limbs), width, len(dst), len(dst)*numLimbs)
+	}
+
+	for i := range dst {
+		e, err := fromLimbs(width, limbs[i*numLimbs:(i+1)*numLimbs])
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		dst[i] = e
+	}
+	return nil
+}
+
+// BatchToBits writes the bits of each element, least significant first, to
+// dst[64*i : 64*(i+1)]. Returns an error if dst has the wrong length.
+func BatchToBits(elements []Element, dst []bool) error {
+	if len(dst) != 64*len(elements) {
+		return fmt.Errorf("destination has %d entries, expected %d", len(dst), 64*len(elements))
+	}
+
+	for i, e := range elements {
+		bits := e.ToBits()
+		copy(dst[64*i:64*(i+1)], bits[:])
+	}
+	return nil
+}
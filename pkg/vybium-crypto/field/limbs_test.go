@@ -0,0 +1,178 @@
+package field
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var limbWidths = []uint{1, 2, 4, 8, 16, 32}
+
+// randomElements returns n random elements, including the edge values 0, 1
+// and P-1.
+func randomElements(rng *rand.Rand, n int) []Element {
+	elements := []Element{Zero, One, New(P - 1)}
+	for len(elements) < n {
+		elements = append(elements, New(rng.Uint64()))
+	}
+	return elements
+}
+
+func TestLimbsRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1165))
+	for _, width := range limbWidths {
+		for _, e := range randomElements(rng, 200) {
+			limbs := e.ToLimbs(width)
+			if len(limbs) != NumLimbs(width) {
+				t.Fatalf("width %d: got %d limbs", width, len(limbs))
+			}
+			for i, limb := range limbs {
+				if limb>>width != 0 {
+					t.Fatalf("width %d: limb %d = %d exceeds width", width, i, limb)
+				}
+			}
+
+			recomposed, err := FromLimbs(width, limbs)
+			if err != nil {
+				t.Fatalf("width %d: FromLimbs(%v) failed: %v", width, limbs, err)
+			}
+			if !recomposed.Equal(e) {
+				t.Fatalf("width %d: got %v, want %v", width, recomposed, e)
+			}
+		}
+	}
+}
+
+func TestToLimbsLittleEndian(t *testing.T) {
+	e := New(0x0123456789abcdef)
+	want := []uint64{0xcdef, 0x89ab, 0x4567, 0x0123}
+	if got := e.ToLimbs(16); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+	if got := New(2).ToLimbs(1)[:3]; !reflect.DeepEqual(got, []uint64{0, 1, 0}) {
+		t.Errorf("got %v, want [0 1 0]", got)
+	}
+}
+
+func TestFromLimbsRejectsAliases(t *testing.T) {
+	// Every value in [P, 2^64) aliases a canonical element
+	aliases := []uint64{P, P + 1, P + 1<<31, ^uint64(0)}
+	rng := rand.New(rand.NewSource(1165))
+	for i := 0; i < 100; i++ {
+		aliases = append(aliases, P+rng.Uint64()%(^uint64(0)-P+1))
+	}
+
+	for _, width := range limbWidths {
+		for _, v := range aliases {
+			limbs := make([]uint64, NumLimbs(width))
+			value := v
+			for i := range limbs {
+				limbs[i] = value & (1<<width - 1)
+				value >>= width
+			}
+			if _, err := FromLimbs(width, limbs); err == nil {
+				t.Errorf("width %d: alias %d accepted", width, v)
+			}
+		}
+	}
+}
+
+func TestFromLimbsRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		width uint
+		limbs []uint64
+	}{
+		{"unsupported width", 3, make([]uint64, 21)},
+		{"zero width", 0, nil},
+		{"too few limbs", 16, []uint64{1, 2, 3}},
+		{"too many limbs", 32, []uint64{1, 2, 3}},
+		{"limb exceeds width", 16, []uint64{0x10000, 0, 0, 0}},
+		{"bit exceeds width", 1, append([]uint64{2}, make([]uint64, 63)...)},
+	}
+	for _, tt := range tests {
+		if _, err := FromLimbs(tt.width, tt.limbs); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestToLimbsPanicsOnUnsupportedWidth(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	One.ToLimbs(64)
+}
+
+func TestToBitsAgreesWithToLimbs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1165))
+	for _, e := range randomElements(rng, 200) {
+		bits := e.ToBits()
+		for i, limb := range e.ToLimbs(1) {
+			if bits[i] != (limb == 1) {
+				t.Fatalf("%v: bit %d differs", e, i)
+			}
+		}
+	}
+}
+
+func TestBatchLimbs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1165))
+	elements := randomElements(rng, 50)
+
+	for _, width := range limbWidths {
+		numLimbs := NumLimbs(width)
+		limbs := make([]uint64, len(elements)*numLimbs)
+		if err := BatchToLimbs(elements, width, limbs); err != nil {
+			t.Fatalf("width %d: %v", width, err)
+		}
+		for i, e := range elements {
+			if !reflect.DeepEqual(limbs[i*numLimbs:(i+1)*numLimbs], e.ToLimbs(width)) {
+				t.Fatalf("width %d: element %d differs from ToLimbs", width, i)
+			}
+		}
+
+		recomposed := make([]Element, len(elements))
+		if err := BatchFromLimbs(width, limbs, recomposed); err != nil {
+			t.Fatalf("width %d: %v", width, err)
+		}
+		if !reflect.DeepEqual(recomposed, elements) {
+			t.Fatalf("width %d: batch round trip failed", width)
+		}
+	}
+
+	bits := make([]bool, 64*len(elements))
+	if err := BatchToBits(elements, bits); err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range elements {
+		want := e.ToBits()
+		if !reflect.DeepEqual(bits[64*i:64*(i+1)], want[:]) {
+			t.Fatalf("element %d differs from ToBits", i)
+		}
+	}
+}
+
+func TestBatchLimbsErrors(t *testing.T) {
+	elements := []Element{One, New(2)}
+	if err := BatchToLimbs(elements, 16, make([]uint64, 7)); err == nil {
+		t.Error("expected error for short destination")
+	}
+	if err := BatchToLimbs(elements, 5, nil); err == nil {
+		t.Error("expected error for unsupported width")
+	}
+	if err := BatchFromLimbs(16, make([]uint64, 7), make([]Element, 2)); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+	if err := BatchToBits(elements, make([]bool, 64)); err == nil {
+		t.Error("expected error for short destination")
+	}
+
+	// An alias in the second element is rejected
+	limbs := []uint64{1, 0, 0, 0, 1, 0, 0xffff, 0xffff}
+	if err := BatchFromLimbs(16, limbs, make([]Element, 2)); err == nil {
+		t.Error("expected error for non-canonical element")
+	}
+}
@@ -0,0 +1,87 @@
+package field
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// NTT performs an in-place radix-2 Number Theoretic Transform with the given
+// root of unity: afterwards values[i] is the evaluation at rootOfUnity^i of
+// the polynomial whose coefficients values held. The input is permuted into
+// bit-reversed order before the butterflies, so the output is in natural
+// order. With PrimitiveRootOfUnity(len(values)) it computes the same
+// transform as ntt.NTT, which caches its twiddle factors.
+//
+// Panics if:
+// - len(values) is not a power of 2
+// - len(values) > 2^31
+// - rootOfUnity is not a primitive len(values)-th root of unity
+func NTT(values []Element, rootOfUnity Element) {
+	transform("NTT", values, rootOfUnity, false)
+}
+
+// INTT inverts NTT with the same root of unity.
+//
+// Panics under the same conditions as NTT.
+func INTT(values []Element, rootOfUnity Element) {
+	transform("INTT", values, rootOfUnity, true)
+}
+
+// transform validates values and root, then transforms with root, or with
+// its inverse followed by scaling by 1/n if inverse is true.
+func transform(name string, values []Element, root Element, inverse bool) {
+	n := len(values)
+	if n == 0 {
+		return
+	}
+	if n&(n-1) != 0 {
+		panic(fmt.Sprintf("%s requires power-of-2 length, got %d", name, n))
+	}
+	if uint64(n) > 1<<31 {
+		panic(fmt.Sprintf("%s length too large: %d", name, n))
+	}
+	if !IsPrimitiveRootOfUnity(root, uint64(n)) {
+		panic(fmt.Sprintf("%s: %s is not a primitive %d-th root of unity", name, root, n))
+	}
+	if n == 1 {
+		return
+	}
+
+	if inverse {
+		root = root.Inverse()
+	}
+
+	// Bit-reverse permutation
+	shift := 32 - uint(bits.Len32(uint32(n))-1)
+	for i := range values {
+		if j := int(bits.Reverse32(uint32(i)) >> shift); i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	// Cooley-Tukey butterflies: stage m combines halves of length m with
+	// the powers of root^(n/2m), a primitive 2m-th root of unity
+	twiddles := make([]Element, n/2)
+	for m := 1; m < n; m *= 2 {
+		wm := root.ModPow(uint64(n / (2 * m)))
+		twiddles[0] = One
+		for j := 1; j < m; j++ {
+			twiddles[j] = twiddles[j-1].Mul(wm)
+		}
+		for k := 0; k < n; k += 2 * m {
+			for j := 0; j < m; j++ {
+				u := values[k+j]
+				v := values[k+j+m].Mul(twiddles[j])
+				values[k+j] = u.Add(v)
+				values[k+j+m] = u.Sub(v)
+			}
+		}
+	}
+
+	if inverse {
+		nInverse := New(uint64(n)).Inverse()
+		for i := range values {
+			values[i] = values[i].Mul(nInverse)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package field
+
+import "testing"
+
+func TestNTTEvaluatesInNaturalOrder(t *testing.T) {
+	// Any odd power of the canonical root is another primitive root.
+	const size = 16
+	root := mustPrimitiveRoot(t, size).ModPow(5)
+	coefficients := make([]Element, size)
+	for i := range coefficients {
+		coefficients[i] = New(uint64(i*i + 3))
+	}
+	values := append([]Element(nil), coefficients...)
+	NTT(values, root)
+
+	for i := range values {
+		x := root.ModPow(uint64(i))
+		want, power := Zero, One
+		for _, c := range coefficients {
+			want = want.Add(c.Mul(power))
+			power = power.Mul(x)
+		}
+		if !values[i].Equal(want) {
+			t.Errorf("index %d: got %v, want the evaluation at root^%d, %v", i, values[i].Value(), i, want.Value())
+		}
+	}
+}
+
+func TestNTTRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 4, 256} {
+		root := One
+		if size > 0 {
+			root = mustPrimitiveRoot(t, uint64(size))
+		}
+		if size > 2 {
+			root = root.ModPow(3)
+		}
+		original := make([]Element, size)
+		for i := range original {
+			original[i] = New(uint64(i*7 + 13))
+		}
+		values := append([]Element(nil), original...)
+		NTT(values, root)
+		INTT(values, root)
+		for i := range original {
+			if !values[i].Equal(original[i]) {
+				t.Fatalf("size %d: round trip failed at index %d", size, i)
+			}
+		}
+	}
+}
+
+func TestNTTOfLengthTwo(t *testing.T) {
+	// The primitive square root of unity is -1: (a, b) maps to (a+b, a-b)
+	values := []Element{New(5), New(3)}
+	NTT(values, mustPrimitiveRoot(t, 2))
+	if values[0] != New(8) || values[1] != New(2) {
+		t.Errorf("got (%v, %v), want (8, 2)", values[0].Value(), values[1].Value())
+	}
+}
+
+func TestNTTPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []Element
+		root   Element
+	}{
+		{"non-power-of-two length", make([]Element, 3), One},
+		{"root of smaller order", make([]Element, 8), mustPrimitiveRoot(t, 4)},
+		{"root of larger order", make([]Element, 8), mustPrimitiveRoot(t, 16)},
+		{"even power of root", make([]Element, 8), mustPrimitiveRoot(t, 8).ModPow(2)},
+		{"zero root", make([]Element, 2), Zero},
+		{"non-unit root of length one", make([]Element, 1), New(2)},
+	}
+	for _, tt := range tests {
+		for name, transform := range map[string]func([]Element, Element){"NTT": NTT, "INTT": INTT} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: %s did not panic", name, tt.name)
+					}
+				}()
+				transform(tt.values, tt.root)
+			}()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package field
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxRandomAttempts bounds the rejection loop of Random. A uniform source
+// yields a value at least P with probability about 2^-32 per draw, so
+// reaching the bound means the source is broken rather than unlucky.
+const maxRandomAttempts = 64
+
+// Random returns a uniformly distributed element, reading from r, or from
+// crypto/rand.Reader if r is nil.
+//
+// It reads 8 bytes as a little-endian uint64 and rejects values of at
+// least P, reading again; reducing them mod P instead would make the
+// elements below 2^64 - P twice as likely as the rest. Returns an error if
+// reading fails or maxRandomAttempts draws in a row are rejected.
+func Random(r io.Reader) (Element, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var buf [8]byte
+	for attempt := 0; attempt < maxRandomAttempts; attempt++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return Zero, fmt.Errorf("random field element: %w", err)
+		}
+		if value := binary.LittleEndian.Uint64(buf[:]); value < P {
+			return New(value), nil
+		}
+	}
+	return Zero, fmt.Errorf("random field element: %d draws in a row were at least P", maxRandomAttempts)
+}
+
+// RandomSlice returns n uniformly distributed elements, reading from r, or
+// from crypto/rand.Reader if r is nil. It reads 8n bytes at once and
+// replaces each rejected value, one of at least P, by a draw of Random.
+// Returns an error if n is negative or Random would fail.
+func RandomSlice(r io.Reader, n int) ([]Element, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("random field elements: negative count %d", n)
+	}
+	if r == nil {
+		r = rand.Reader
+	}
+	buf := make([]byte, 8*n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("random field elements: %w", err)
+	}
+
+	elements := make([]Element, n)
+	for i := range elements {
+		value := binary.LittleEndian.Uint64(buf[8*i:])
+		if value < P {
+			elements[i] = New(value)
+			continue
+		}
+		element, err := Random(r)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
@@ -0,0 +1,117 @@
+package field
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// uint64Reader returns a reader yielding the values as little-endian
+// uint64s.
+func uint64Reader(values ...uint64) io.Reader {
+	buf := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(buf[8*i:], v)
+	}
+	return bytes.NewReader(buf)
+}
+
+func TestRandomRejectsValuesAtLeastP(t *testing.T) {
+	got, err := Random(uint64Reader(P, math.MaxUint64, P+1, P-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value() != P-1 {
+		t.Errorf("got %d, want the first value below P, %d", got.Value(), P-1)
+	}
+
+	got, err = Random(uint64Reader(0))
+	if err != nil || !got.IsZero() {
+		t.Errorf("got %d, %v; want 0", got.Value(), err)
+	}
+}
+
+func TestRandomBrokenReaders(t *testing.T) {
+	stuck := make([]uint64, maxRandomAttempts)
+	for i := range stuck {
+		stuck[i] = math.MaxUint64
+	}
+	if _, err := Random(uint64Reader(stuck...)); err == nil {
+		t.Error("reader stuck above P accepted")
+	}
+	if _, err := Random(bytes.NewReader([]byte{1, 2, 3})); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short read: got %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := Random(uint64Reader(P)); !errors.Is(err, io.EOF) {
+		t.Errorf("reader exhausted while rejecting: got %v, want io.EOF", err)
+	}
+}
+
+func TestRandomSliceResamplesRejectedValues(t *testing.T) {
+	// The third value is rejected and replaced by the first value after the
+	// bulk read.
+	got, err := RandomSlice(uint64Reader(1, 2, P, 4, P+5, 6), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{1, 2, 6, 4}
+	for i, w := range want {
+		if got[i].Value() != w {
+			t.Errorf("element %d = %d, want %d", i, got[i].Value(), w)
+		}
+	}
+
+	if got, err := RandomSlice(uint64Reader(), 0); err != nil || len(got) != 0 {
+		t.Errorf("n = 0: got %v, %v", got, err)
+	}
+	if _, err := RandomSlice(nil, -1); err == nil {
+		t.Error("negative count accepted")
+	}
+	if _, err := RandomSlice(uint64Reader(1, 2), 3); err == nil {
+		t.Error("short reader accepted")
+	}
+}
+
+func TestRandomDistribution(t *testing.T) {
+	// Sixteen buckets by the top four bits; each should hold about 1/16 of
+	// the samples. The top bucket loses only the 2^32 - 1 values above P.
+	const samples = 16000
+	elements, err := RandomSlice(rand.New(rand.NewSource(1254)), samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buckets [16]int
+	for _, e := range elements {
+		if e.Value() >= P {
+			t.Fatalf("non-canonical value %d", e.Value())
+		}
+		buckets[e.Value()>>60]++
+	}
+	for i, count := range buckets {
+		if count < 800 || count > 1200 {
+			t.Errorf("bucket %d holds %d of %d samples", i, count, samples)
+		}
+	}
+}
+
+func TestRandomDefaultsToCryptoRand(t *testing.T) {
+	first, err := Random(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elements, err := RandomSlice(nil, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distinct := map[uint64]bool{first.Value(): true}
+	for _, e := range elements {
+		distinct[e.Value()] = true
+	}
+	if len(distinct) != 5 {
+		t.Errorf("five draws from crypto/rand gave %d distinct values", len(distinct))
+	}
+}
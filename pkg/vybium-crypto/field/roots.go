@@ -45,7 +45,8 @@ var PrimitiveRoots = map[uint64]uint64{
 }
 
 // GetPrimitiveRoot returns the primitive root of unity for the given order.
-// The order must be a power of 2.
+// Returns an error unless the order is a power of 2 dividing 2^32, the
+// field's two-adicity.
 func GetPrimitiveRoot(order uint64) (Element, error) {
 	if order == 0 {
 		return Zero, fmt.Errorf("order cannot be zero")
@@ -58,21 +59,17 @@ func GetPrimitiveRoot(order uint64) (Element, error) {
 
 	// Check if we have the primitive root for this order
 	if root, exists := PrimitiveRoots[order]; exists {
-		return NewFromRaw(root), nil
+		return New(root), nil
 	}
 
 	return Zero, fmt.Errorf("primitive root not found for order %d", order)
 }
 
-// PrimitiveRootOfUnity returns the primitive root of unity for the given order.
-// Returns Zero if not found. This is a convenience wrapper around GetPrimitiveRoot.
-// Production implementation.
-func PrimitiveRootOfUnity(order uint64) Element {
-	root, err := GetPrimitiveRoot(order)
-	if err != nil {
-		return Zero
-	}
-	return root
+// PrimitiveRootOfUnity returns the primitive root of unity of the given
+// order, the root NTT and ntt.NTT transform with. Returns an error unless the
+// order is a power of 2 dividing 2^32, the field's two-adicity.
+func PrimitiveRootOfUnity(order uint64) (Element, error) {
+	return GetPrimitiveRoot(order)
 }
 
 // IsPrimitiveRootOfUnity checks if the given element is a primitive root of unity of the given order.
@@ -121,7 +118,7 @@ func GeneratePrimitiveRoot(order uint64) (Element, error) {
 
 	// For small orders, we can use the precomputed values
 	if root, exists := PrimitiveRoots[order]; exists {
-		return NewFromRaw(root), nil
+		return New(root), nil
 	}
 
 	// For larger orders, we generate them from the field generator
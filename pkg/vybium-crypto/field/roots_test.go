@@ -0,0 +1,58 @@
+package field
+
+import "testing"
+
+// mustPrimitiveRoot returns PrimitiveRootOfUnity(order), failing the test on
+// an error.
+func mustPrimitiveRoot(t testing.TB, order uint64) Element {
+	t.Helper()
+	root, err := PrimitiveRootOfUnity(order)
+	if err != nil {
+		t.Fatalf("order %d: %v", order, err)
+	}
+	return root
+}
+
+func TestPrimitiveRootsArePrimitive(t *testing.T) {
+	for order, canonical := range PrimitiveRoots {
+		if order == 0 {
+			continue
+		}
+		root := mustPrimitiveRoot(t, order)
+		if root.Value() != canonical {
+			t.Errorf("order %d: root %d, want the table's canonical value %d", order, root.Value(), canonical)
+		}
+		if !IsPrimitiveRootOfUnity(root, order) {
+			t.Errorf("order %d: %d is not a primitive root of unity", order, root.Value())
+		}
+	}
+	if !mustPrimitiveRoot(t, 2).Equal(One.Neg()) {
+		t.Error("the primitive square root of unity is not -1")
+	}
+}
+
+func TestGeneratePrimitiveRoot(t *testing.T) {
+	for _, order := range []uint64{1, 2, 64, 1 << 32} {
+		generated, err := GeneratePrimitiveRoot(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsPrimitiveRootOfUnity(generated, order) {
+			t.Errorf("order %d: generated root is not primitive", order)
+		}
+	}
+}
+
+func TestPrimitiveRootOfUnityRejectsOrdersNotDividingTwoAdicity(t *testing.T) {
+	for _, order := range []uint64{0, 3, 12, 1 << 33, 1 << 63} {
+		if _, err := PrimitiveRootOfUnity(order); err == nil {
+			t.Errorf("order %d accepted", order)
+		}
+	}
+	for _, order := range []uint64{1, 2, 1 << 16, 1 << 32} {
+		root := mustPrimitiveRoot(t, order)
+		if !IsPrimitiveRootOfUnity(root, order) {
+			t.Errorf("order %d: %d is not primitive", order, root.Value())
+		}
+	}
+}
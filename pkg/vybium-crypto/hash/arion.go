@@ -12,10 +12,17 @@
 package hash
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
 )
 
-// ARION constants for Goldilocks field (P = 2^64 - 2^32 + 1)
+// ARION constants for Goldilocks field (P = 2^64 - 2^32 + 1).
+// These describe the canonical instance returned by DefaultArionParams.
 const (
 	// ArionStateSize is the state size for Arion (N = 3)
 	ArionStateSize = 3
@@ -29,96 +36,283 @@ const (
 	// ArionRounds is the number of full permutation rounds
 	ArionRounds = 10
 
-	// ArionD1 is the S-box degree (low-degree exponent)
-	ArionD1 = 3
+	// ArionD1 is the S-box degree (low-degree exponent), the smallest
+	// exponent coprime to P-1, so that x^D1 permutes the field
+	ArionD1 = 7
 
 	// ArionD2 is the GTDS degree (high-degree exponent)
 	ArionD2 = 121
 
 	// ArionDigestSize is the output digest size (matching Tip5)
 	ArionDigestSize = DigestLen
+
+	// ArionMinRounds is the smallest number of rounds accepted by
+	// ValidateArionParams. The paper requires r ≥ 4 to frustrate
+	// meet-in-the-middle attacks (ARION_HASH_PAPER.md, Section 3).
+	ArionMinRounds = 4
 )
 
+// ErrInvalidArionParams is wrapped by every error returned by
+// ArionParams.Validate and NewArionWithParams.
+var ErrInvalidArionParams = errors.New("invalid Arion parameters")
+
 // Arion represents the Arion hash function state.
 // State consists of N field elements that undergo GTDS permutation.
 type Arion struct {
-	state          [ArionStateSize]field.Element
-	roundConstants [][ArionStateSize]field.Element
-	mdsMatrix      [ArionStateSize][ArionStateSize]field.Element
-}
-
-// arionQuadraticParams contains the α and β parameters for GTDS quadratic polynomials.
-// These parameters are carefully chosen to ensure the discriminant is a quadratic
-// non-residue in the Goldilocks field.
-type arionQuadraticParams struct {
-	alpha1 field.Element // α_{i,1} coefficient
-	alpha2 field.Element // α_{i,2} coefficient
-	beta   field.Element // β_i coefficient
-}
-
-// Goldilocks-optimized quadratic parameters for GTDS
-// Formula: g_i(x) = x² + α_{i,1}·x + α_{i,2}
-// Formula: h_i(x) = x² + β_i·x
-// Constraint: α²_{i,1} - 4·α_{i,2} must be quadratic non-residue mod P
-var arionQuadraticParamsGoldilocks = [ArionStateSize]arionQuadraticParams{
-	{
-		alpha1: field.New(18446744069414584320), // -1 mod P
-		alpha2: field.New(2),
-		beta:   field.Zero,
-	},
-	{
-		alpha1: field.New(18446744069414584320), // -1 mod P
-		alpha2: field.New(2),
-		beta:   field.Zero,
-	},
-	{
-		alpha1: field.Zero,
-		alpha2: field.Zero,
-		beta:   field.Zero,
-	},
-}
-
-// arionInverseExponent is the multiplicative inverse of D2 modulo (P-1)
+	params         ArionParams
+	state          []field.Element
+	roundConstants [][]field.Element
+	mdsMatrix      [][]field.Element
+}
+
+// ArionQuadraticParams contains the α and β parameters for the GTDS quadratic
+// polynomials of one branch:
+//
+//	g_i(x) = x² + α_{i,1}·x + α_{i,2}
+//	h_i(x) = x² + β_i·x
+//
+// The discriminant α²_{i,1} - 4·α_{i,2} must be a quadratic non-residue, so
+// that g_i has no root in the field.
+type ArionQuadraticParams struct {
+	Alpha1 field.Element // α_{i,1} coefficient
+	Alpha2 field.Element // α_{i,2} coefficient
+	Beta   field.Element // β_i coefficient
+}
+
+// ArionParams parameterizes an Arion instance over the Goldilocks field.
+type ArionParams struct {
+	// StateSize is the number of state elements N.
+	StateSize int
+
+	// Rate is the number of elements absorbed per permutation; the remaining
+	// StateSize - Rate elements are the capacity.
+	Rate int
+
+	// Rounds is the number of full permutation rounds.
+	Rounds int
+
+	// D1 is the low-degree exponent of branches 0 to N-2.
+	D1 uint64
+
+	// D2 is the high-degree exponent whose inverse is applied to branch N-1.
+	D2 uint64
+
+	// InverseExponent is E with D2·E ≡ 1 (mod P-1); see ArionInverseExponent.
+	InverseExponent uint64
+
+	// QuadraticParams holds the quadratic polynomials of branches 0 to N-2.
+	QuadraticParams []ArionQuadraticParams
+}
+
+// DefaultArionParams returns the parameters used by NewArion. They pass
+// Validate.
+func DefaultArionParams() ArionParams {
+	return ArionParams{
+		StateSize:       ArionStateSize,
+		Rate:            ArionRate,
+		Rounds:          ArionRounds,
+		D1:              ArionD1,
+		D2:              ArionD2,
+		InverseExponent: arionInverseExponent,
+		QuadraticParams: []ArionQuadraticParams{
+			{
+				Alpha1: field.Max, // -1 mod P
+				Alpha2: field.New(2),
+				Beta:   field.Zero,
+			},
+			{
+				Alpha1: field.Max, // -1 mod P
+				Alpha2: field.New(2),
+				Beta:   field.Zero,
+			},
+		},
+	}
+}
+
+// arionInverseExponent is the multiplicative inverse of ArionD2 modulo (P-1)
 // Formula: E · D2 ≡ 1 (mod P-1)
 // For Goldilocks field: P = 2^64 - 2^32 + 1, P-1 = 2^64 - 2^32
 // For D2 = 121: E = 4878477770423691721
 // E is computed such that (x^D2)^E = x
-var arionInverseExponent = field.New(4878477770423691721)
+const arionInverseExponent = 4878477770423691721
+
+// ArionInverseExponent returns E with d2·E ≡ 1 (mod P-1), or an error if d2
+// is not invertible modulo P-1.
+func ArionInverseExponent(d2 uint64) (uint64, error) {
+	order := new(big.Int).SetUint64(field.P - 1)
+	inverse := new(big.Int).ModInverse(new(big.Int).SetUint64(d2), order)
+	if inverse == nil {
+		return 0, fmt.Errorf("%w: D2 = %d is not invertible modulo P-1", ErrInvalidArionParams, d2)
+	}
+	return inverse.Uint64(), nil
+}
 
-// NewArion creates a new Arion instance with the specified domain.
-func NewArion(domain Domain) *Arion {
-	arion := &Arion{}
-
-	// Initialize state based on domain
-	switch domain {
-	case VariableLength:
-		// All zeros for variable-length hashing
-		for i := 0; i < ArionStateSize; i++ {
-			arion.state[i] = field.Zero
+// Validate checks the parameters against the constraints of the Arion paper:
+//   - StateSize ≥ 2 and 1 ≤ Rate < StateSize
+//   - Rounds ≥ ArionMinRounds
+//   - D1, D2 > 1 with gcd(D1, P-1) = gcd(D2, P-1) = 1
+//   - D2·InverseExponent ≡ 1 (mod P-1)
+//   - one quadratic parameter set per branch 0 to N-2, each with a
+//     discriminant α²_{i,1} - 4·α_{i,2} that is a quadratic non-residue
+//
+// All errors wrap ErrInvalidArionParams.
+func (p ArionParams) Validate() error {
+	if p.StateSize < 2 {
+		return fmt.Errorf("%w: state size %d is less than 2", ErrInvalidArionParams, p.StateSize)
+	}
+	if p.Rate < 1 || p.Rate >= p.StateSize {
+		return fmt.Errorf("%w: rate %d not in [1, %d)", ErrInvalidArionParams, p.Rate, p.StateSize)
+	}
+	if p.Rounds < ArionMinRounds {
+		return fmt.Errorf("%w: %d rounds is below the minimum of %d", ErrInvalidArionParams, p.Rounds, ArionMinRounds)
+	}
+	for _, d := range []struct {
+		name  string
+		value uint64
+	}{{"D1", p.D1}, {"D2", p.D2}} {
+		if d.value < 2 {
+			return fmt.Errorf("%w: %s = %d must be at least 2", ErrInvalidArionParams, d.name, d.value)
 		}
-	case FixedLength:
-		// Set capacity elements to 1 for fixed-length hashing
-		for i := 0; i < ArionRate; i++ {
-			arion.state[i] = field.Zero
+		if g := gcd(d.value, field.P-1); g != 1 {
+			return fmt.Errorf("%w: gcd(%s, P-1) = %d, must be 1", ErrInvalidArionParams, d.name, g)
 		}
-		for i := ArionRate; i < ArionStateSize; i++ {
-			arion.state[i] = field.One
+	}
+	hi, lo := bits.Mul64(p.D2, p.InverseExponent)
+	if bits.Rem64(hi, lo, field.P-1) != 1 {
+		return fmt.Errorf("%w: inverse exponent %d is not the inverse of D2 = %d modulo P-1", ErrInvalidArionParams, p.InverseExponent, p.D2)
+	}
+	if len(p.QuadraticParams) != p.StateSize-1 {
+		return fmt.Errorf("%w: %d quadratic parameter sets, expected %d", ErrInvalidArionParams, len(p.QuadraticParams), p.StateSize-1)
+	}
+	four := field.New(4)
+	for i, q := range p.QuadraticParams {
+		discriminant := q.Alpha1.Mul(q.Alpha1).Sub(four.Mul(q.Alpha2))
+		if discriminant.Legendre() != -1 {
+			return fmt.Errorf("%w: discriminant of branch %d is not a quadratic non-residue", ErrInvalidArionParams, i)
+		}
+	}
+	return nil
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// The default parameters are checked once, so that NewArion never builds an
+// instance that NewArionWithParams would reject.
+func init() {
+	if err := DefaultArionParams().Validate(); err != nil {
+		panic(fmt.Sprintf("hash: default Arion parameters: %v", err))
+	}
+}
+
+// NewArion creates a new Arion instance with the specified domain, using
+// DefaultArionParams.
+func NewArion(domain Domain) *Arion {
+	return newArion(DefaultArionParams(), domain)
+}
+
+// NewArionWithParams creates a new Arion instance for variable-length
+// hashing from the given parameters, after checking them with Validate.
+func NewArionWithParams(params ArionParams) (*Arion, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	params.QuadraticParams = append([]ArionQuadraticParams(nil), params.QuadraticParams...)
+	return newArion(params, VariableLength), nil
+}
+
+// ArionWidthD1 is the low-degree exponent chosen by ArionParamsForWidth,
+// the same as the default ArionD1.
+const ArionWidthD1 = ArionD1
+
+// ArionParamsForWidth returns valid parameters for an instance with the
+// given state size, rate and number of rounds: D1 = ArionWidthD1,
+// D2 = ArionD2, and for branch i = 0 to N-2 the quadratic parameters
+// α_{i,1} = β_i = i+1 with α_{i,2} the smallest positive value whose
+// discriminant α²_{i,1} - 4·α_{i,2} is a quadratic non-residue. Returns an
+// error wrapping ErrInvalidArionParams if the sizes fail Validate.
+func ArionParamsForWidth(stateSize, rate, rounds int) (ArionParams, error) {
+	params := ArionParams{
+		StateSize:       stateSize,
+		Rate:            rate,
+		Rounds:          rounds,
+		D1:              ArionWidthD1,
+		D2:              ArionD2,
+		InverseExponent: arionInverseExponent,
+	}
+	four := field.New(4)
+	for i := 0; i < stateSize-1; i++ {
+		alpha1 := field.New(uint64(i + 1))
+		alpha2 := field.One
+		for alpha1.Mul(alpha1).Sub(four.Mul(alpha2)).Legendre() != -1 {
+			alpha2 = alpha2.Add(field.One)
 		}
+		params.QuadraticParams = append(params.QuadraticParams, ArionQuadraticParams{
+			Alpha1: alpha1,
+			Alpha2: alpha2,
+			Beta:   alpha1,
+		})
 	}
+	if err := params.Validate(); err != nil {
+		return ArionParams{}, err
+	}
+	return params, nil
+}
+
+// NewArionWithWidth creates an Arion instance in the given domain with the
+// parameters of ArionParamsForWidth. For the default width it differs from
+// NewArion in its quadratic parameters.
+func NewArionWithWidth(stateSize, rate, rounds int, domain Domain) (*Arion, error) {
+	params, err := ArionParamsForWidth(stateSize, rate, rounds)
+	if err != nil {
+		return nil, err
+	}
+	return newArion(params, domain), nil
+}
+
+// newArion creates an Arion instance without validating the parameters.
+func newArion(params ArionParams, domain Domain) *Arion {
+	arion := &Arion{params: params}
+	arion.resetState(domain)
 
 	// Generate round constants and MDS matrix
-	arion.roundConstants = generateArionRoundConstants()
-	arion.mdsMatrix = generateArionMDSMatrix()
+	arion.roundConstants = generateArionRoundConstants(params)
+	arion.mdsMatrix = generateArionMDSMatrix(params.StateSize)
 
 	return arion
 }
 
+// resetState initializes the state for the given domain.
+func (a *Arion) resetState(domain Domain) {
+	a.state = make([]field.Element, a.params.StateSize)
+
+	// Variable-length hashing starts from all zeros; fixed-length hashing
+	// sets the capacity elements to 1
+	if domain == FixedLength {
+		for i := a.params.Rate; i < a.params.StateSize; i++ {
+			a.state[i] = field.One
+		}
+	}
+}
+
+// Params returns the parameters of this instance.
+func (a *Arion) Params() ArionParams {
+	params := a.params
+	params.QuadraticParams = append([]ArionQuadraticParams(nil), a.params.QuadraticParams...)
+	return params
+}
+
 // Permutation applies the full Arion permutation to the state.
-// This consists of ArionRounds iterations of:
+// This consists of Rounds iterations of:
 // 1. GTDS layer (Generalized Triangular Dynamical System)
 // 2. Affine layer (MDS matrix multiplication + round constants)
 func (a *Arion) Permutation() {
-	for round := 0; round < ArionRounds; round++ {
+	for round := 0; round < a.params.Rounds; round++ {
 		// GTDS layer
 		a.gtdsLayer()
 
@@ -141,8 +335,8 @@ func (a *Arion) Permutation() {
 //
 // Reference: ARION_FORMULA.md Section 2.3
 func (a *Arion) gtdsLayer() {
-	n := ArionStateSize
-	fValues := [ArionStateSize]field.Element{}
+	n := a.params.StateSize
+	fValues := make([]field.Element, n)
 
 	// Compute from bottom to top (index N-1 down to 0)
 	// Last branch first (special case: x^E)
@@ -158,7 +352,7 @@ func (a *Arion) gtdsLayer() {
 		}
 
 		// Get quadratic parameters for this branch
-		params := arionQuadraticParamsGoldilocks[i]
+		params := a.params.QuadraticParams[i]
 
 		// Compute x_i^{D1}
 		xiPowD1 := a.powerD1(a.state[i])
@@ -179,45 +373,44 @@ func (a *Arion) gtdsLayer() {
 	}
 }
 
-// powerD1 computes x^D1 where D1 = 3.
-// Formula: x^3 = x · x · x
+// powerD1 computes x^D1.
+// For the default D1 = 7: x^7 = x^4 · x^2 · x, with four multiplications
 func (a *Arion) powerD1(x field.Element) field.Element {
-	x2 := x.Mul(x)
-	return x2.Mul(x)
+	if a.params.D1 == 7 {
+		x2 := x.Mul(x)
+		x3 := x2.Mul(x)
+		return x2.Mul(x2).Mul(x3)
+	}
+	return x.ModPow(a.params.D1)
 }
 
 // powerD2Inverse computes x^E where E is the multiplicative inverse of D2 modulo (P-1).
 // This is the inverse operation of x^D2, used for the last GTDS branch.
 //
-// For D2 = 121, we use an efficient exponentiation chain:
-// E = 12071105285756551697 (precomputed inverse)
-//
 // Reference: ARION_FORMULA.md Section 6 (Efficient Exponentiation Chains)
 func (a *Arion) powerD2Inverse(x field.Element) field.Element {
-	// Use addition chain for efficient computation of x^E
-	// For Goldilocks field with D2=121, E=12071105285756551697
-	return x.ModPow(arionInverseExponent.Value())
+	return x.ModPow(a.params.InverseExponent)
 }
 
 // evaluateG evaluates the quadratic polynomial g_i(x) = x² + α_{i,1}·x + α_{i,2}
-func (a *Arion) evaluateG(x field.Element, params arionQuadraticParams) field.Element {
+func (a *Arion) evaluateG(x field.Element, params ArionQuadraticParams) field.Element {
 	// x²
 	xSquared := x.Mul(x)
 
 	// α_{i,1} · x
-	alpha1X := params.alpha1.Mul(x)
+	alpha1X := params.Alpha1.Mul(x)
 
 	// x² + α_{i,1}·x + α_{i,2}
-	return xSquared.Add(alpha1X).Add(params.alpha2)
+	return xSquared.Add(alpha1X).Add(params.Alpha2)
 }
 
 // evaluateH evaluates the quadratic polynomial h_i(x) = x² + β_i·x
-func (a *Arion) evaluateH(x field.Element, params arionQuadraticParams) field.Element {
+func (a *Arion) evaluateH(x field.Element, params ArionQuadraticParams) field.Element {
 	// x²
 	xSquared := x.Mul(x)
 
 	// β_i · x
-	betaX := params.beta.Mul(x)
+	betaX := params.Beta.Mul(x)
 
 	// x² + β_i·x
 	return xSquared.Add(betaX)
@@ -232,7 +425,7 @@ func (a *Arion) affineLayer(round int) {
 	newState := a.applyMDSMatrix()
 
 	// Add round constants
-	for i := 0; i < ArionStateSize; i++ {
+	for i := range a.state {
 		a.state[i] = newState[i].Add(a.roundConstants[round][i])
 	}
 }
@@ -248,9 +441,9 @@ func (a *Arion) affineLayer(round int) {
 // This reduces O(N²) operations to O(N) operations.
 //
 // Reference: ARION_FORMULA.md Section 3.2
-func (a *Arion) applyMDSMatrix() [ArionStateSize]field.Element {
-	n := ArionStateSize
-	result := [ArionStateSize]field.Element{}
+func (a *Arion) applyMDSMatrix() []field.Element {
+	n := a.params.StateSize
+	result := make([]field.Element, n)
 
 	// Step 1: Compute σ = Σ v_i
 	sigma := field.Zero
@@ -278,36 +471,25 @@ func (a *Arion) applyMDSMatrix() [ArionStateSize]field.Element {
 	return result
 }
 
-// generateArionRoundConstants generates the round constants for Arion.
-// These are pseudo-randomly generated using a deterministic process based on the
-// "nothing up my sleeve" principle.
+// generateArionRoundConstants generates the round constants for Arion,
+// Rounds × StateSize field elements in round-major order.
 //
-// Reference: ARION_FORMULA.md Section 4 (Round Constants Generation)
-func generateArionRoundConstants() [][ArionStateSize]field.Element {
-	constants := make([][ArionStateSize]field.Element, ArionRounds)
-
-	// Use Grain LFSR seeded with parameters to generate constants
-	// Seed: domain tag "Arion-Goldilocks-N3-R10" in ASCII
-	seed := []byte("Arion-Goldilocks-N3-R10")
-
-	// Simple pseudo-random generation (production would use Grain LFSR)
-	// For now, use a deterministic sequence based on round and position
-	for round := 0; round < ArionRounds; round++ {
-		for pos := 0; pos < ArionStateSize; pos++ {
-			// Generate pseudo-random value
-			// Mix round index, position, and seed
-			val := uint64(0)
-			for i, b := range seed {
-				val ^= uint64(b) << (i % 64)
-			}
-			val ^= uint64(round) * 0x9E3779B97F4A7C15           // Fibonacci hashing
-			val ^= uint64(pos) * 0x517CC1B727220A95             // Another prime
-			val = val*6364136223846793005 + 1442695040888963407 // LCG
-
-			constants[round][pos] = field.New(val)
+// They are nothing-up-my-sleeve numbers: the successive outputs of a Grain
+// LFSR, as Poseidon uses for its constants, seeded with nothing but the ASCII
+// tag "Arion-Goldilocks-N<StateSize>-R<Rounds>", "Arion-Goldilocks-N3-R10"
+// for the default parameters. See newGrainLFSRFromSeed for the seeding and
+// nextCanonicalElement for the sampling.
+func generateArionRoundConstants(params ArionParams) [][]field.Element {
+	seed := fmt.Sprintf("Arion-Goldilocks-N%d-R%d", params.StateSize, params.Rounds)
+	lfsr := newGrainLFSRFromSeed([]byte(seed))
+
+	constants := make([][]field.Element, params.Rounds)
+	for round := range constants {
+		constants[round] = make([]field.Element, params.StateSize)
+		for pos := range constants[round] {
+			constants[round][pos] = lfsr.nextCanonicalElement()
 		}
 	}
-
 	return constants
 }
 
@@ -321,19 +503,22 @@ func generateArionRoundConstants() [][ArionStateSize]field.Element {
 // └       ┘
 //
 // Reference: ARION_FORMULA.md Section 3.1
-func generateArionMDSMatrix() [ArionStateSize][ArionStateSize]field.Element {
-	matrix := [ArionStateSize][ArionStateSize]field.Element{}
+func generateArionMDSMatrix(n int) [][]field.Element {
+	matrix := make([][]field.Element, n)
+	for i := range matrix {
+		matrix[i] = make([]field.Element, n)
+	}
 
 	// First row is [1, 2, 3, ...]
-	for j := 0; j < ArionStateSize; j++ {
+	for j := 0; j < n; j++ {
 		matrix[0][j] = field.New(uint64(j + 1))
 	}
 
 	// Each subsequent row is rotated right from the previous row
-	for i := 1; i < ArionStateSize; i++ {
-		for j := 0; j < ArionStateSize; j++ {
+	for i := 1; i < n; i++ {
+		for j := 0; j < n; j++ {
 			// Rotate right: take from position (j-i+N) mod N in the first row
-			srcIdx := (j - i + ArionStateSize) % ArionStateSize
+			srcIdx := (j - i + n) % n
 			matrix[i][j] = matrix[0][srcIdx]
 		}
 	}
@@ -347,28 +532,54 @@ func generateArionMDSMatrix() [ArionStateSize][ArionStateSize]field.Element {
 // Reference: ARION_FORMULA.md Section 5 (Sponge Mode)
 func (a *Arion) HashVarLen(input []field.Element) Digest {
 	// Reinitialize for variable-length hashing
-	*a = *NewArion(VariableLength)
+	a.resetState(VariableLength)
+	a.padAndAbsorb(input)
+
+	// Squeeze phase: extract digest
+	return a.Squeeze()
+}
+
+// HashVarLenWithDomain hashes a variable-length sequence under a numeric
+// domain tag, as HashVarlenWithDomain does for Tip5. The capacity elements
+// are preloaded with the first element of the instance's HashVarLen of the
+// tag's u64 encoding followed by taggedVariableLengthMarker. A capacity of
+// one element cannot hold a 64-bit tag injectively, so the tag is
+// compressed; distinct tags share a starting state only with probability
+// about 1/P.
+func (a *Arion) HashVarLenWithDomain(tag uint64, input []field.Element) Digest {
+	tagEncoding := append(bfieldcodec.EncodeUint64(tag), field.New(taggedVariableLengthMarker))
+	tagElement := a.HashVarLen(tagEncoding)[0]
+
+	a.resetState(VariableLength)
+	for i := a.params.Rate; i < a.params.StateSize; i++ {
+		a.state[i] = tagElement
+	}
+	a.padAndAbsorb(input)
+	return a.Squeeze()
+}
+
+// padAndAbsorb absorbs input in chunks of the rate, padded with a one and
+// zeros; input of a multiple of the rate is followed by a padding chunk.
+func (a *Arion) padAndAbsorb(input []field.Element) {
+	rate := a.params.Rate
 
 	// Absorb phase: process input in chunks of RATE
-	for i := 0; i < len(input); i += ArionRate {
+	for i := 0; i < len(input); i += rate {
 		chunk := input[i:]
-		if len(chunk) > ArionRate {
-			chunk = chunk[:ArionRate]
+		if len(chunk) > rate {
+			chunk = chunk[:rate]
 		}
 
-		// Pad if necessary
-		if len(chunk) < ArionRate {
-			padded := make([]field.Element, ArionRate)
+		// Pad if necessary: append 1 followed by zeros
+		if len(chunk) < rate {
+			padded := make([]field.Element, rate)
 			copy(padded, chunk)
-			// Padding: append 1 followed by zeros
-			if len(chunk) < ArionRate {
-				padded[len(chunk)] = field.One
-			}
+			padded[len(chunk)] = field.One
 			chunk = padded
 		}
 
 		// XOR input into state
-		for j := 0; j < len(chunk) && j < ArionRate; j++ {
+		for j := 0; j < rate; j++ {
 			a.state[j] = a.state[j].Add(chunk[j])
 		}
 
@@ -377,53 +588,72 @@ func (a *Arion) HashVarLen(input []field.Element) Digest {
 	}
 
 	// If no input or last chunk was exactly RATE elements, apply final permutation
-	if len(input)%ArionRate == 0 {
+	if len(input)%rate == 0 {
 		a.state[0] = a.state[0].Add(field.One)
 		a.Permutation()
 	}
-
-	// Squeeze phase: extract digest
-	return a.Squeeze()
 }
 
 // Squeeze extracts a digest from the current state.
-// Returns the first DigestLen elements of the state (matching Tip5 output size).
+// Returns the first DigestLen elements of the state (matching Tip5 output size),
+// applying the permutation whenever the state has been read out.
 func (a *Arion) Squeeze() Digest {
 	digest := Digest{}
+	n := a.params.StateSize
 
-	// Extract first DigestLen elements
-	for i := 0; i < ArionDigestSize && i < ArionStateSize; i++ {
-		digest[i] = a.state[i]
-	}
-
-	// If we need more elements than state size, apply permutation and continue
-	if ArionDigestSize > ArionStateSize {
-		for i := ArionStateSize; i < ArionDigestSize; i++ {
-			// Need to permute for more output
-			if i%ArionStateSize == 0 {
-				a.Permutation()
-			}
-			digest[i] = a.state[i%ArionStateSize]
+	for i := 0; i < ArionDigestSize; i++ {
+		// Need to permute for more output
+		if i > 0 && i%n == 0 {
+			a.Permutation()
 		}
+		digest[i] = a.state[i%n]
 	}
 
 	return digest
 }
 
+// absorbFixed absorbs input in chunks of the rate without padding.
+func (a *Arion) absorbFixed(input []field.Element) {
+	rate := a.params.Rate
+	for i := 0; i < len(input); i += rate {
+		a.Absorb(input[i:min(i+rate, len(input))])
+	}
+}
+
+// Absorb adds chunk to the first elements of the state and applies the
+// permutation, one absorption step of the sponge. It does not pad. Panics
+// if chunk is longer than the rate.
+func (a *Arion) Absorb(chunk []field.Element) {
+	if len(chunk) > a.params.Rate {
+		panic(fmt.Sprintf("chunk of %d elements exceeds the Arion rate %d", len(chunk), a.params.Rate))
+	}
+	for j, element := range chunk {
+		a.state[j] = a.state[j].Add(element)
+	}
+	a.Permutation()
+}
+
+// SqueezeRate returns the rate elements of the state and applies the
+// permutation, one squeezing step of the sponge.
+func (a *Arion) SqueezeRate() []field.Element {
+	output := append([]field.Element(nil), a.state[:a.params.Rate]...)
+	a.Permutation()
+	return output
+}
+
+// Clone returns an independent copy of the instance, state included.
+func (a *Arion) Clone() *Arion {
+	clone := *a
+	clone.state = append([]field.Element(nil), a.state...)
+	return &clone
+}
+
 // ArionHash10 hashes exactly 10 field elements without padding.
 // This is optimized for cases where the input size is known and fixed.
 // Equivalent to Tip5::hash_10 but using Arion permutation.
 func ArionHash10(input [10]field.Element) Digest {
 	arion := NewArion(FixedLength)
-
-	// Process in chunks of RATE (2 elements)
-	for i := 0; i < 10; i += ArionRate {
-		for j := 0; j < ArionRate && i+j < 10; j++ {
-			arion.state[j] = arion.state[j].Add(input[i+j])
-		}
-		arion.Permutation()
-	}
-
+	arion.absorbFixed(input[:])
 	return arion.Squeeze()
 }
 
@@ -432,36 +662,34 @@ func ArionHash10(input [10]field.Element) Digest {
 func ArionHashPair(left, right Digest) Digest {
 	arion := NewArion(FixedLength)
 
-	// Absorb left digest (first DigestLen elements)
-	for i := 0; i < DigestLen; i += ArionRate {
-		for j := 0; j < ArionRate && i+j < DigestLen; j++ {
-			arion.state[j] = arion.state[j].Add(left[i+j])
-		}
-		arion.Permutation()
-	}
-
-	// Absorb right digest
-	for i := 0; i < DigestLen; i += ArionRate {
-		for j := 0; j < ArionRate && i+j < DigestLen; j++ {
-			arion.state[j] = arion.state[j].Add(right[i+j])
-		}
-		arion.Permutation()
-	}
+	// Absorb left digest, then right digest
+	arion.absorbFixed(left[:])
+	arion.absorbFixed(right[:])
 
 	return arion.Squeeze()
 }
 
+// HashPair hashes two digests together with this instance's parameters,
+// using the fixed-length domain. ArionHashPair is HashPair for the default
+// parameters.
+func (a *Arion) HashPair(left, right Digest) Digest {
+	a.resetState(FixedLength)
+	a.absorbFixed(left[:])
+	a.absorbFixed(right[:])
+	return a.Squeeze()
+}
+
 // Trace returns the execution trace of the Arion permutation.
 // This is useful for generating STARK proofs of hash computation.
-// Returns the state after each round.
-func (a *Arion) Trace() [ArionRounds + 1][ArionStateSize]field.Element {
-	trace := [ArionRounds + 1][ArionStateSize]field.Element{}
+// Returns the initial state followed by the state after each round.
+func (a *Arion) Trace() [][]field.Element {
+	trace := make([][]field.Element, 0, a.params.Rounds+1)
 
 	// Record initial state
-	trace[0] = a.state
+	trace = append(trace, append([]field.Element(nil), a.state...))
 
 	// Apply permutation round by round, recording state
-	for round := 0; round < ArionRounds; round++ {
+	for round := 0; round < a.params.Rounds; round++ {
 		// GTDS layer
 		a.gtdsLayer()
 
@@ -469,7 +697,7 @@ func (a *Arion) Trace() [ArionRounds + 1][ArionStateSize]field.Element {
 		a.affineLayer(round)
 
 		// Record state after this round
-		trace[round+1] = a.state
+		trace = append(trace, append([]field.Element(nil), a.state...))
 	}
 
 	return trace
@@ -477,7 +705,7 @@ func (a *Arion) Trace() [ArionRounds + 1][ArionStateSize]field.Element {
 
 // Reset resets the Arion state to initial values for the given domain.
 func (a *Arion) Reset(domain Domain) {
-	*a = *NewArion(domain)
+	a.resetState(domain)
 }
 
 // ArionHash is a convenience function that hashes a variable-length input.
@@ -486,3 +714,10 @@ func ArionHash(input []field.Element) Digest {
 	arion := NewArion(VariableLength)
 	return arion.HashVarLen(input)
 }
+
+// ArionHashWithDomain hashes a variable-length input under a numeric domain
+// tag. See Arion.HashVarLenWithDomain.
+func ArionHashWithDomain(tag uint64, input []field.Element) Digest {
+	arion := NewArion(VariableLength)
+	return arion.HashVarLenWithDomain(tag, input)
+}
@@ -1,6 +1,8 @@
 package hash
 
 import (
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -99,7 +101,7 @@ func TestArionGTDSLayer(t *testing.T) {
 	arion.state[2] = field.New(3)
 
 	// Record initial state
-	initialState := arion.state
+	initialState := append([]field.Element(nil), arion.state...)
 
 	// Apply GTDS layer
 	arion.gtdsLayer()
@@ -135,8 +137,7 @@ func TestArionPowerD1(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			result := arion.powerD1(tt.input)
 
-			// Verify x^3 = x * x * x
-			expected := tt.input.Mul(tt.input).Mul(tt.input)
+			expected := tt.input.ModPow(ArionD1)
 			if !result.Equal(expected) {
 				t.Errorf("powerD1(%v) = %v, want %v", tt.input, result, expected)
 			}
@@ -164,7 +165,7 @@ func TestArionPowerD2Inverse(t *testing.T) {
 			// Verify (x^E)^D2 ≈ x (modulo field operations)
 			// Note: This is an approximate test due to inverse exponent
 			xToD2 := tt.input.ModPow(ArionD2)
-			backToX := xToD2.ModPow(arionInverseExponent.Value())
+			backToX := xToD2.ModPow(arionInverseExponent)
 
 			if !backToX.Equal(tt.input) {
 				t.Logf("Warning: (x^D2)^E != x for %v", tt.input)
@@ -184,20 +185,20 @@ func TestArionPowerD2Inverse(t *testing.T) {
 // TestArionQuadraticPolynomials tests the g and h polynomial evaluations
 func TestArionQuadraticPolynomials(t *testing.T) {
 	arion := NewArion(VariableLength)
-	params := arionQuadraticParamsGoldilocks[0]
+	params := DefaultArionParams().QuadraticParams[0]
 
 	x := field.New(5)
 
 	// Test g_i(x) = x² + α_{i,1}·x + α_{i,2}
 	gi := arion.evaluateG(x, params)
-	expected := x.Mul(x).Add(params.alpha1.Mul(x)).Add(params.alpha2)
+	expected := x.Mul(x).Add(params.Alpha1.Mul(x)).Add(params.Alpha2)
 	if !gi.Equal(expected) {
 		t.Errorf("evaluateG incorrect: got %v, want %v", gi, expected)
 	}
 
 	// Test h_i(x) = x² + β_i·x
 	hi := arion.evaluateH(x, params)
-	expected = x.Mul(x).Add(params.beta.Mul(x))
+	expected = x.Mul(x).Add(params.Beta.Mul(x))
 	if !hi.Equal(expected) {
 		t.Errorf("evaluateH incorrect: got %v, want %v", hi, expected)
 	}
@@ -537,45 +538,73 @@ func TestArionFieldBoundary(t *testing.T) {
 	}
 }
 
-// TestArionRoundConstants tests that round constants are unique
+// arionDefaultRoundConstants freezes the canonical values of the round
+// constants of the default parameters, generated from the Grain LFSR seeded
+// with "Arion-Goldilocks-N3-R10".
+var arionDefaultRoundConstants = [ArionRounds][ArionStateSize]uint64{
+	{13652292491570115293, 8887429058392295211, 4512950123048086275},
+	{13080340341953102652, 14694142002000558107, 5500669034038995290},
+	{16826105310739823861, 11824030180848111719, 12459539176246331257},
+	{16316745152795259532, 2591968279050725914, 14422298748844126323},
+	{7167334209580044615, 9481059660457393248, 14419397045349907367},
+	{4302786226657972286, 10611671212639561989, 2301863620919751634},
+	{13838273601367469964, 12919144817705521406, 11269037925952870103},
+	{2318789220629495691, 4815639663523930060, 2780385975654629829},
+	{12754351840599179047, 9864103920819929998, 4114202980499955843},
+	{14212565702124213626, 7624189016602973952, 8735387061225023564},
+}
+
 func TestArionRoundConstants(t *testing.T) {
 	arion := NewArion(VariableLength)
-
-	// Check all round constants are generated
 	if len(arion.roundConstants) != ArionRounds {
-		t.Errorf("Expected %d rounds of constants, got %d", ArionRounds, len(arion.roundConstants))
-	}
-
-	// Check that not all constants are zero
-	allZero := true
-	for round := 0; round < ArionRounds; round++ {
-		for pos := 0; pos < ArionStateSize; pos++ {
-			if !arion.roundConstants[round][pos].IsZero() {
-				allZero = false
-				break
+		t.Fatalf("Expected %d rounds of constants, got %d", ArionRounds, len(arion.roundConstants))
+	}
+	seen := make(map[field.Element]bool)
+	for round, want := range arionDefaultRoundConstants {
+		for pos, w := range want {
+			got := arion.roundConstants[round][pos]
+			if got.Value() != w {
+				t.Errorf("constant [%d][%d] = %d, want %d", round, pos, got.Value(), w)
 			}
+			seen[got] = true
 		}
 	}
-	if allZero {
-		t.Error("All round constants are zero")
+	if len(seen) != ArionRounds*ArionStateSize {
+		t.Errorf("%d round constants have only %d distinct values", ArionRounds*ArionStateSize, len(seen))
 	}
 
-	// Check that constants vary across rounds
-	firstRound := arion.roundConstants[0]
-	different := false
-	for round := 1; round < ArionRounds; round++ {
-		for pos := 0; pos < ArionStateSize; pos++ {
-			if !arion.roundConstants[round][pos].Equal(firstRound[pos]) {
-				different = true
-				break
+	// Regenerating gives the same constants; other parameters another seed
+	again := generateArionRoundConstants(DefaultArionParams())
+	for round := range again {
+		for pos := range again[round] {
+			if again[round][pos] != arion.roundConstants[round][pos] {
+				t.Fatalf("constant [%d][%d] differs between generations", round, pos)
 			}
 		}
-		if different {
-			break
-		}
 	}
-	if !different {
-		t.Error("Round constants do not vary across rounds")
+	params := DefaultArionParams()
+	params.Rounds = ArionRounds + 1
+	if longer := generateArionRoundConstants(params); longer[0][0] == again[0][0] {
+		t.Error("Arion-Goldilocks-N3-R11 starts with the constants of N3-R10")
+	}
+}
+
+func TestGrainLFSRFromSeed(t *testing.T) {
+	a, b := newGrainLFSRFromSeed([]byte("seed")), newGrainLFSRFromSeed([]byte("seed"))
+	other := newGrainLFSRFromSeed([]byte("seee"))
+	if a.state != b.state {
+		t.Fatal("equal seeds give different registers")
+	}
+	if a.state == other.state {
+		t.Error("seeds differing in one bit give the same register")
+	}
+	// An empty seed still leaves a non-zero register
+	empty := newGrainLFSRFromSeed(nil)
+	for i := 0; i < 100; i++ {
+		if x, y := a.nextCanonicalElement(), b.nextCanonicalElement(); x != y || x.Value() >= field.P {
+			t.Fatalf("output %d: %v and %v", i, x, y)
+		}
+		empty.nextCanonicalElement()
 	}
 }
 
@@ -654,3 +683,258 @@ func BenchmarkArionMDSMatrix(b *testing.B) {
 		_ = arion.applyMDSMatrix()
 	}
 }
+
+// TestArionDefaultDigestsUnchanged freezes the digests of the default
+// parameters (D1 = 7), which NewArion must keep producing.
+func TestArionDefaultDigestsUnchanged(t *testing.T) {
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	var ten [10]field.Element
+	for i := range ten {
+		ten[i] = field.New(uint64(i))
+	}
+
+	tests := []struct {
+		name string
+		got  Digest
+		want string
+	}{
+		{"HashVarLen", ArionHash(input), "d28009b74ac61c724cbeb5275a8267e37163582a712584c7a738f3adb40159c1dbada8fbef636fae"},
+		{"HashVarLen empty", ArionHash(nil), "126685976469939aa819c905ec1dbb363f4cda4ea3f39ececf37ab3ed830c7fbe801b1a0f835b5fe"},
+		{"Hash10", ArionHash10(ten), "872c8840f4d78c59bd137e0e4ef8e5e15b475cd398b668e098cd236932fd3e4d6f41ef8034371cc8"},
+		{"HashPair", ArionHashPair(ArionHash(input), ArionHash(nil)), "8bd3731fd5661e2985476c712579841683bc79a03330c195a39760c5ee912f8ee18d0649b5964e1c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Hex() != tt.want {
+				t.Errorf("got %s, want %s", tt.got.Hex(), tt.want)
+			}
+		})
+	}
+}
+
+// validArionParams returns a valid N=4 instance: D1 = 7 is the smallest
+// exponent coprime to P-1, and -7 = (-1)² - 4·2 is a non-residue.
+func validArionParams(t *testing.T) ArionParams {
+	e, err := ArionInverseExponent(121)
+	if err != nil {
+		t.Fatalf("ArionInverseExponent failed: %v", err)
+	}
+	quadratic := ArionQuadraticParams{Alpha1: field.One.Neg(), Alpha2: field.New(2), Beta: field.New(5)}
+	return ArionParams{
+		StateSize:       4,
+		Rate:            3,
+		Rounds:          6,
+		D1:              7,
+		D2:              121,
+		InverseExponent: e,
+		QuadraticParams: []ArionQuadraticParams{quadratic, quadratic, quadratic},
+	}
+}
+
+func TestArionInverseExponent(t *testing.T) {
+	e, err := ArionInverseExponent(ArionD2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e != arionInverseExponent {
+		t.Errorf("got %d, want %d", e, uint64(arionInverseExponent))
+	}
+
+	x := field.New(1234567890)
+	if !x.ModPow(ArionD2).ModPow(e).Equal(x) {
+		t.Error("(x^D2)^E != x")
+	}
+
+	if _, err := ArionInverseExponent(3); !errors.Is(err, ErrInvalidArionParams) {
+		t.Errorf("expected ErrInvalidArionParams for D2 = 3, got %v", err)
+	}
+}
+
+func TestArionWithParamsN4(t *testing.T) {
+	params := validArionParams(t)
+	arion, err := NewArionWithParams(params)
+	if err != nil {
+		t.Fatalf("NewArionWithParams failed: %v", err)
+	}
+
+	input := []field.Element{field.New(1), field.New(2), field.New(3), field.New(4), field.New(5)}
+	digest := arion.HashVarLen(input)
+	if digest == ArionHash(input) {
+		t.Error("N=4 instance reproduces the default digest")
+	}
+
+	// Deterministic across instances and calls
+	other, _ := NewArionWithParams(params)
+	if other.HashVarLen(input) != digest || arion.HashVarLen(input) != digest {
+		t.Error("HashVarLen is not deterministic")
+	}
+	if arion.HashVarLen(input[:4]) == digest {
+		t.Error("different inputs gave the same digest")
+	}
+
+	pair := arion.HashPair(digest, ArionHash(nil))
+	if pair == ArionHashPair(digest, ArionHash(nil)) {
+		t.Error("N=4 HashPair reproduces the default digest")
+	}
+
+	trace := arion.Trace()
+	if len(trace) != params.Rounds+1 || len(trace[0]) != params.StateSize {
+		t.Errorf("trace has shape %dx%d, want %dx%d", len(trace), len(trace[0]), params.Rounds+1, params.StateSize)
+	}
+
+	// The GTDS layer is invertible: with D1 coprime to P-1 and g_i without
+	// roots, distinct states map to distinct states
+	arion.state = []field.Element{field.New(1), field.New(2), field.New(3), field.New(4)}
+	arion.gtdsLayer()
+	first := append([]field.Element(nil), arion.state...)
+	arion.state = []field.Element{field.New(2), field.New(2), field.New(3), field.New(4)}
+	arion.gtdsLayer()
+	if first[0].Equal(arion.state[0]) {
+		t.Error("GTDS layer collided on the first branch")
+	}
+
+	// Later changes to the caller's params do not affect the instance
+	params.QuadraticParams[0].Alpha2 = field.New(99)
+	if other.Params().QuadraticParams[0].Alpha2.Equal(field.New(99)) {
+		t.Error("instance shares QuadraticParams with the caller")
+	}
+}
+
+func TestArionWithWidth(t *testing.T) {
+	for _, size := range []struct{ stateSize, rate int }{{3, 2}, {4, 3}, {8, 6}} {
+		params, err := ArionParamsForWidth(size.stateSize, size.rate, ArionRounds)
+		if err != nil {
+			t.Fatalf("N=%d: %v", size.stateSize, err)
+		}
+		if len(params.QuadraticParams) != size.stateSize-1 {
+			t.Fatalf("N=%d: %d quadratic parameter sets", size.stateSize, len(params.QuadraticParams))
+		}
+
+		arion, err := NewArionWithWidth(size.stateSize, size.rate, ArionRounds, VariableLength)
+		if err != nil {
+			t.Fatalf("N=%d: %v", size.stateSize, err)
+		}
+		other, _ := NewArionWithWidth(size.stateSize, size.rate, ArionRounds, VariableLength)
+		if len(arion.mdsMatrix) != size.stateSize || len(arion.roundConstants[0]) != size.stateSize {
+			t.Fatalf("N=%d: MDS matrix %d rows, %d round constants per round", size.stateSize, len(arion.mdsMatrix), len(arion.roundConstants[0]))
+		}
+
+		// The permutation is deterministic and moves the state
+		for i := range arion.state {
+			arion.state[i] = field.New(uint64(i + 1))
+			other.state[i] = field.New(uint64(i + 1))
+		}
+		before := append([]field.Element(nil), arion.state...)
+		arion.Permutation()
+		other.Permutation()
+		if !slices.Equal(arion.state, other.state) {
+			t.Errorf("N=%d: permutation is not deterministic", size.stateSize)
+		}
+		if slices.Equal(arion.state, before) {
+			t.Errorf("N=%d: permutation fixed the state", size.stateSize)
+		}
+
+		input := []field.Element{field.New(1), field.New(2), field.New(3)}
+		if digest := arion.HashVarLen(input); digest == other.HashVarLen(input[:2]) || digest == ArionHash(input) {
+			t.Errorf("N=%d: digest %v collides", size.stateSize, digest)
+		}
+	}
+
+	fixed, err := NewArionWithWidth(4, 2, ArionMinRounds, FixedLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fixed.state[2].IsOne() || !fixed.state[3].IsOne() {
+		t.Errorf("fixed-length capacity %v, want ones", fixed.state[2:])
+	}
+
+	for _, size := range []struct{ stateSize, rate, rounds int }{{1, 1, 10}, {4, 4, 10}, {4, 2, ArionMinRounds - 1}} {
+		if _, err := NewArionWithWidth(size.stateSize, size.rate, size.rounds, VariableLength); !errors.Is(err, ErrInvalidArionParams) {
+			t.Errorf("%+v: expected ErrInvalidArionParams, got %v", size, err)
+		}
+	}
+}
+
+func TestArionParamsValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *ArionParams)
+	}{
+		{"state size too small", func(p *ArionParams) { p.StateSize = 1; p.Rate = 1; p.QuadraticParams = nil }},
+		{"zero rate", func(p *ArionParams) { p.Rate = 0 }},
+		{"no capacity", func(p *ArionParams) { p.Rate = p.StateSize }},
+		{"too few rounds", func(p *ArionParams) { p.Rounds = ArionMinRounds - 1 }},
+		{"D1 too small", func(p *ArionParams) { p.D1 = 1 }},
+		{"D1 not coprime", func(p *ArionParams) { p.D1 = 3 }},
+		{"D2 not coprime", func(p *ArionParams) { p.D2 = 125 }},
+		{"wrong inverse exponent", func(p *ArionParams) { p.InverseExponent++ }},
+		{"missing quadratic params", func(p *ArionParams) { p.QuadraticParams = p.QuadraticParams[:2] }},
+		{"residue discriminant", func(p *ArionParams) {
+			// 3² - 4·2 = 1 is a square
+			p.QuadraticParams[1] = ArionQuadraticParams{Alpha1: field.New(3), Alpha2: field.New(2)}
+		}},
+		{"zero discriminant", func(p *ArionParams) {
+			p.QuadraticParams[2] = ArionQuadraticParams{Alpha1: field.New(2), Alpha2: field.One}
+		}},
+	}
+
+	if err := validArionParams(t).Validate(); err != nil {
+		t.Fatalf("valid params rejected: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validArionParams(t)
+			tt.modify(&params)
+
+			if err := params.Validate(); !errors.Is(err, ErrInvalidArionParams) {
+				t.Errorf("Validate: expected ErrInvalidArionParams, got %v", err)
+			}
+			if arion, err := NewArionWithParams(params); arion != nil || !errors.Is(err, ErrInvalidArionParams) {
+				t.Errorf("NewArionWithParams: expected ErrInvalidArionParams, got %v", err)
+			}
+		})
+	}
+}
+
+func TestArionDefaultParamsAreValid(t *testing.T) {
+	params := DefaultArionParams()
+	if err := params.Validate(); err != nil {
+		t.Fatalf("default params are invalid: %v", err)
+	}
+
+	// D1 = 3 divides P-1, so x^3 does not permute the field
+	params.D1 = 3
+	if !errors.Is(params.Validate(), ErrInvalidArionParams) {
+		t.Error("default params with D1 = 3 should be invalid")
+	}
+}
+
+func TestArionHashWithDomain(t *testing.T) {
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	tags := []uint64{0, 1, 2, field.P, 1<<64 - 1}
+
+	seen := make(map[Digest]uint64)
+	for _, tag := range tags {
+		digest := ArionHashWithDomain(tag, input)
+		if other, ok := seen[digest]; ok {
+			t.Errorf("tags %#x and %#x give the same digest", tag, other)
+		}
+		seen[digest] = tag
+	}
+	if ArionHashWithDomain(0, input) == ArionHash(input) {
+		t.Error("tag 0 must not reproduce plain ArionHash")
+	}
+
+	// Reusing an instance gives the same digests as fresh instances
+	arion := NewArion(VariableLength)
+	for _, tag := range tags {
+		if arion.HashVarLenWithDomain(tag, input) != ArionHashWithDomain(tag, input) {
+			t.Errorf("tag %#x: reused instance disagrees with a fresh one", tag)
+		}
+	}
+	if arion.HashVarLen(input) != ArionHash(input) {
+		t.Error("HashVarLen after HashVarLenWithDomain keeps the tag")
+	}
+}
@@ -1,19 +1,26 @@
 package hash
 
 import (
+	"cmp"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/params"
 )
 
 // DigestLen is the number of field elements in a digest (equivalent to twenty-first's Digest::LEN).
-const DigestLen = 5
+const DigestLen = params.DigestLen
 
 // Digest represents the result of hashing a sequence of elements.
 // It contains exactly 5 BFieldElements, matching twenty-first's Digest structure.
+//
+// The all-zero digest is valid data: it may appear as a leaf, as padding, or as
+// the commitment to an empty structure. It is never used as an error sentinel;
+// functions that can fail report this through their error return, and the
+// digest they return alongside an error carries no meaning.
 type Digest [DigestLen]field.Element
 
 // NewDigest creates a new Digest from an array of field elements.
@@ -21,7 +28,8 @@ func NewDigest(elements [DigestLen]field.Element) Digest {
 	return Digest(elements)
 }
 
-// Zero returns the all-zero digest.
+// ZeroDigest returns the all-zero digest.
+// See Digest for why this value must not be treated as an error indicator.
 func ZeroDigest() Digest {
 	return Digest{field.Zero, field.Zero, field.Zero, field.Zero, field.Zero}
 }
@@ -38,16 +46,14 @@ func (d Digest) Reversed() Digest {
 }
 
 // Equal returns true if two digests are equal.
+// field.Element equality compares the stored words, so comparing the arrays
+// directly is equivalent and compiles to a single memory comparison.
 func (d Digest) Equal(other Digest) bool {
-	for i := 0; i < DigestLen; i++ {
-		if !d[i].Equal(other[i]) {
-			return false
-		}
-	}
-	return true
+	return d == other
 }
 
 // IsZero returns true if the digest is all zeros.
+// A zero digest is legitimate data, not an indication of failure.
 func (d Digest) IsZero() bool {
 	for i := 0; i < DigestLen; i++ {
 		if !d[i].IsZero() {
@@ -81,7 +87,8 @@ func (d Digest) ToBytes() [DigestLen * 8]byte {
 	return result
 }
 
-// FromBytes creates a Digest from a byte array.
+// DigestFromBytes creates a Digest from the bytes written by ToBytes.
+// Values of at least P are reduced mod P.
 func DigestFromBytes(bytes [DigestLen * 8]byte) Digest {
 	var result Digest
 	for i := 0; i < DigestLen; i++ {
@@ -91,32 +98,38 @@ func DigestFromBytes(bytes [DigestLen * 8]byte) Digest {
 	return result
 }
 
-// FromHex creates a Digest from a hexadecimal string.
+// DigestFromHex creates a Digest from the hexadecimal string written by Hex.
 func DigestFromHex(s string) (Digest, error) {
 	bytes, err := hex.DecodeString(s)
 	if err != nil {
-		return ZeroDigest(), fmt.Errorf("invalid hex string: %w", err)
+		return Digest{}, fmt.Errorf("invalid hex string: %w", err)
 	}
 	if len(bytes) != DigestLen*8 {
-		return ZeroDigest(), fmt.Errorf("invalid hex digest length: expected %d bytes, got %d", DigestLen*8, len(bytes))
+		return Digest{}, fmt.Errorf("invalid hex digest length: expected %d bytes, got %d", DigestLen*8, len(bytes))
 	}
 	var byteArray [DigestLen * 8]byte
 	copy(byteArray[:], bytes)
 	return DigestFromBytes(byteArray), nil
 }
 
-// Less returns true if this digest is less than the other (for ordering).
-// Compares elements in reverse order (most significant first), matching twenty-first's Ord implementation.
-func (d Digest) Less(other Digest) bool {
+// Compare returns -1, 0 or +1 as d is less than, equal to or greater than
+// other. It orders digests lexicographically by the canonical values of
+// their elements, most significant, d[DigestLen-1], first, matching
+// twenty-first's Ord implementation; slices.SortFunc(digests, Digest.Compare)
+// sorts in that order.
+func (d Digest) Compare(other Digest) int {
 	for i := DigestLen - 1; i >= 0; i-- {
-		if d[i].Less(other[i]) {
-			return true
-		}
-		if d[i].Greater(other[i]) {
-			return false
+		if c := cmp.Compare(d[i].Value(), other[i].Value()); c != 0 {
+			return c
 		}
 	}
-	return false
+	return 0
+}
+
+// Less returns true if this digest is less than the other in the order of
+// Compare.
+func (d Digest) Less(other Digest) bool {
+	return d.Compare(other) < 0
 }
 
 // Greater returns true if this digest is greater than the other.
@@ -0,0 +1,120 @@
+package hash
+
+// DefaultDigestArenaBlockSize is the number of digests per arena block when
+// NewDigestArena is given a non-positive block size.
+const DefaultDigestArenaBlockSize = 1 << 16
+
+// DigestArena hands out digest slices carved from large pre-allocated
+// blocks, so that workloads producing many digest slices, such as repeated
+// Merkle tree builds, allocate a few long-lived blocks instead of one heap
+// object per slice.
+//
+// Release returns every block to the arena for reuse. It invalidates all
+// slices previously returned by Alloc: their memory is handed out again by
+// later calls, so reading or writing them after Release silently corrupts
+// unrelated data. Build with the fielddebug tag to poison released blocks,
+// which makes such use visible.
+//
+// A nil *DigestArena is valid and allocates each slice on the heap, so
+// functions taking an optional arena behave as before when given nil.
+// A DigestArena is not safe for concurrent use.
+type DigestArena struct {
+	blockSize int
+
+	// current is the unused remainder of the block being carved.
+	current []Digest
+	// used holds the blocks handed out since the last Release, free those
+	// available for reuse.
+	used [][]Digest
+	free [][]Digest
+}
+
+// NewDigestArena creates an arena allocating blocks of blockSize digests.
+// A non-positive blockSize selects DefaultDigestArenaBlockSize.
+func NewDigestArena(blockSize int) *DigestArena {
+	if blockSize <= 0 {
+		blockSize = DefaultDigestArenaBlockSize
+	}
+	return &DigestArena{blockSize: blockSize}
+}
+
+// Alloc returns a zeroed slice of n digests. Its capacity is exactly n, so
+// appending to it reallocates rather than overwriting neighbouring slices.
+// Requests larger than the block size get a block of their own.
+// Panics if n is negative.
+func (a *DigestArena) Alloc(n int) []Digest {
+	if n < 0 {
+		panic("negative digest count")
+	}
+	if a == nil {
+		return make([]Digest, n)
+	}
+
+	if n > len(a.current) {
+		size := a.blockSize
+		if n > size {
+			size = n
+		}
+		block := a.takeBlock(size)
+		a.used = append(a.used, block)
+		a.current = block
+	}
+
+	digests := a.current[:n:n]
+	a.current = a.current[n:]
+	clear(digests)
+	return digests
+}
+
+// takeBlock returns the smallest free block of at least size digests, or a
+// new one if none fits. The remainder of the current block is abandoned until
+// the next Release.
+func (a *DigestArena) takeBlock(size int) []Digest {
+	best := -1
+	for i, block := range a.free {
+		if len(block) >= size && (best < 0 || len(block) < len(a.free[best])) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return make([]Digest, size)
+	}
+
+	block := a.free[best]
+	last := len(a.free) - 1
+	a.free[best] = a.free[last]
+	a.free[last] = nil
+	a.free = a.free[:last]
+	return block
+}
+
+// Release returns all blocks to the arena for reuse, invalidating every slice
+// previously returned by Alloc.
+func (a *DigestArena) Release() {
+	if a == nil {
+		return
+	}
+	for _, block := range a.used {
+		poisonReleasedDigests(block)
+	}
+	a.free = append(a.free, a.used...)
+	clear(a.used)
+	a.used = a.used[:0]
+	a.current = nil
+}
+
+// Retained returns the number of digests held by the arena's blocks, in use
+// or free.
+func (a *DigestArena) Retained() int {
+	if a == nil {
+		return 0
+	}
+	total := 0
+	for _, block := range a.used {
+		total += len(block)
+	}
+	for _, block := range a.free {
+		total += len(block)
+	}
+	return total
+}
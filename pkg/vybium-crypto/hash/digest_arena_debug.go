@@ -0,0 +1,16 @@
+//go:build fielddebug
+
+package hash
+
+import "github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+
+// ReleasedDigest is the value fielddebug builds write over every digest of a
+// released arena block, so that slices used after DigestArena.Release read
+// an obviously wrong value instead of plausible stale data.
+var ReleasedDigest = Digest{field.Max, field.Max, field.Max, field.Max, field.Max}
+
+func poisonReleasedDigests(block []Digest) {
+	for i := range block {
+		block[i] = ReleasedDigest
+	}
+}
@@ -0,0 +1,35 @@
+//go:build fielddebug
+
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// TestDigestArenaUseAfterRelease documents the use-after-release hazard: a
+// slice kept past Release aliases memory the arena hands out again. Debug
+// builds poison released blocks so the stale slice reads ReleasedDigest
+// rather than plausible old data.
+func TestDigestArenaUseAfterRelease(t *testing.T) {
+	arena := NewDigestArena(8)
+	stale := arena.Alloc(4)
+	for i := range stale {
+		stale[i] = Digest{field.New(uint64(i + 1))}
+	}
+
+	arena.Release()
+	for i, d := range stale {
+		if d != ReleasedDigest {
+			t.Errorf("stale[%d] = %v, want the poison digest", i, d)
+		}
+	}
+
+	// The next allocation reuses the same memory
+	fresh := arena.Alloc(4)
+	fresh[0] = Digest{field.New(99)}
+	if stale[0] != fresh[0] {
+		t.Error("released slice does not alias the reallocated one")
+	}
+}
@@ -0,0 +1,89 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestDigestArenaAlloc(t *testing.T) {
+	arena := NewDigestArena(16)
+	a := arena.Alloc(5)
+	b := arena.Alloc(7)
+	if len(a) != 5 || cap(a) != 5 || len(b) != 7 || cap(b) != 7 {
+		t.Fatalf("got len/cap %d/%d and %d/%d", len(a), cap(a), len(b), cap(b))
+	}
+	for i := range a {
+		a[i] = Digest{field.New(uint64(i + 1))}
+	}
+	for i, d := range b {
+		if !d.IsZero() {
+			t.Errorf("b[%d] = %v, want zero", i, d)
+		}
+	}
+
+	// Appending must not spill into the next slice
+	_ = append(a, Digest{field.One})
+	if !b[0].IsZero() {
+		t.Error("append to a overwrote b")
+	}
+}
+
+func TestDigestArenaOversizedAlloc(t *testing.T) {
+	arena := NewDigestArena(4)
+	small := arena.Alloc(3)
+	large := arena.Alloc(10)
+	if len(large) != 10 {
+		t.Fatalf("got %d digests, want 10", len(large))
+	}
+	small[0] = Digest{field.One}
+	for i, d := range large {
+		if !d.IsZero() {
+			t.Errorf("large[%d] = %v, want zero", i, d)
+		}
+	}
+}
+
+func TestDigestArenaReusesBlocks(t *testing.T) {
+	arena := NewDigestArena(64)
+	for round := 0; round < 10; round++ {
+		for _, n := range []int{40, 40, 100, 1} {
+			digests := arena.Alloc(n)
+			for i := range digests {
+				if !digests[i].IsZero() {
+					t.Fatalf("round %d: reused slice not zeroed", round)
+				}
+				digests[i] = Digest{field.New(uint64(round + 1))}
+			}
+		}
+		arena.Release()
+		if round == 0 {
+			continue
+		}
+		// Blocks of the first round are enough for every later one
+		if got := arena.Retained(); got != 64+64+100+64 {
+			t.Fatalf("round %d: arena retains %d digests, want %d", round, got, 64+64+100+64)
+		}
+	}
+}
+
+func TestNilDigestArena(t *testing.T) {
+	var arena *DigestArena
+	digests := arena.Alloc(3)
+	if len(digests) != 3 {
+		t.Fatalf("got %d digests, want 3", len(digests))
+	}
+	arena.Release()
+	if arena.Retained() != 0 {
+		t.Error("nil arena retains digests")
+	}
+}
+
+func TestDigestArenaNegativeAllocPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Alloc(-1) did not panic")
+		}
+	}()
+	NewDigestArena(0).Alloc(-1)
+}
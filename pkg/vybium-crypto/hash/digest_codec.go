@@ -0,0 +1,53 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Encode returns the BFieldCodec encoding of the digest: its DigestLen
+// elements in order, without a length prefix.
+func (d Digest) Encode() []field.Element {
+	encoding := make([]field.Element, DigestLen)
+	copy(encoding, d[:])
+	return encoding
+}
+
+// EncodeDigest returns the BFieldCodec encoding of the digest, d.Encode();
+// it is the counterpart of DecodeDigest.
+func EncodeDigest(d Digest) []field.Element {
+	return d.Encode()
+}
+
+// Decode implements bfieldcodec.BFieldCodec. The result is a Digest.
+func (d Digest) Decode(sequence []field.Element) (bfieldcodec.BFieldCodec, error) {
+	return DecodeDigest(sequence)
+}
+
+// StaticLength implements bfieldcodec.BFieldCodec. Digests always encode as
+// DigestLen elements, so bfieldcodec.EncodeSlice writes no per-item length
+// prefixes for them.
+func (d Digest) StaticLength() *int {
+	length := DigestLen
+	return &length
+}
+
+// DecodeDigest decodes a digest from its BFieldCodec encoding. Returns an
+// error unless the sequence has exactly DigestLen elements.
+func DecodeDigest(sequence []field.Element) (Digest, error) {
+	if len(sequence) < DigestLen {
+		return Digest{}, bfieldcodec.BFieldCodecError{
+			Type:    bfieldcodec.ErrorSequenceTooShort,
+			Message: fmt.Sprintf("need %d elements for digest, got %d", DigestLen, len(sequence)),
+		}
+	}
+	if len(sequence) > DigestLen {
+		return Digest{}, bfieldcodec.BFieldCodecError{
+			Type:    bfieldcodec.ErrorSequenceTooLong,
+			Message: fmt.Sprintf("need %d elements for digest, got %d", DigestLen, len(sequence)),
+		}
+	}
+	return Digest(sequence), nil
+}
@@ -0,0 +1,91 @@
+package hash
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+var _ bfieldcodec.BFieldCodec = Digest{}
+
+func TestDigestCodecRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1278))
+	for i := 0; i < 20; i++ {
+		d := randomDigest(rng)
+		encoding := d.Encode()
+		if len(encoding) != DigestLen || *d.StaticLength() != DigestLen {
+			t.Fatalf("encoding has %d elements, static length %d", len(encoding), *d.StaticLength())
+		}
+		decoded, err := DecodeDigest(encoding)
+		if err != nil || decoded != d {
+			t.Fatalf("DecodeDigest = %v, %v; want %v", decoded, err, d)
+		}
+		viaInterface, err := Digest{}.Decode(encoding)
+		if err != nil || viaInterface.(Digest) != d {
+			t.Fatalf("Decode = %v, %v; want %v", viaInterface, err, d)
+		}
+
+		// The encoding is a copy
+		encoding[0] = encoding[0].Add(field.One)
+		if decoded != d {
+			t.Fatal("decoded digest aliases the encoding")
+		}
+	}
+}
+
+func TestDigestCodecSlice(t *testing.T) {
+	rng := rand.New(rand.NewSource(1278))
+	for _, n := range []int{0, 1, 7} {
+		digests := make([]Digest, n)
+		for i := range digests {
+			digests[i] = randomDigest(rng)
+		}
+		encoding := bfieldcodec.EncodeSlice(digests)
+		if len(encoding) != 1+n*DigestLen {
+			t.Fatalf("%d digests encode as %d elements", n, len(encoding))
+		}
+		decoded, err := bfieldcodec.DecodeSlice(encoding, func() Digest { return Digest{} })
+		if err != nil {
+			t.Fatalf("%d digests: %v", n, err)
+		}
+		if len(decoded) != n {
+			t.Fatalf("decoded %d digests, want %d", len(decoded), n)
+		}
+		for i := range digests {
+			if decoded[i] != digests[i] {
+				t.Errorf("digest %d: got %v, want %v", i, decoded[i], digests[i])
+			}
+		}
+		if n > 0 {
+			if _, err := bfieldcodec.DecodeSlice(encoding[:len(encoding)-1], func() Digest { return Digest{} }); err == nil {
+				t.Errorf("%d digests: truncated encoding accepted", n)
+			}
+		}
+	}
+}
+
+func TestDecodeDigestWrongLength(t *testing.T) {
+	d := randomDigest(rand.New(rand.NewSource(1278)))
+	sequence := append(EncodeDigest(d), field.One)
+	if decoded, err := DecodeDigest(sequence[:DigestLen]); err != nil || decoded != d {
+		t.Fatalf("DecodeDigest(EncodeDigest(d)) = %v, %v; want %v", decoded, err, d)
+	}
+
+	for _, tt := range []struct {
+		length int
+		want   bfieldcodec.ErrorType
+	}{
+		{0, bfieldcodec.ErrorSequenceTooShort},
+		{DigestLen - 1, bfieldcodec.ErrorSequenceTooShort},
+		{DigestLen + 1, bfieldcodec.ErrorSequenceTooLong},
+	} {
+		_, err := DecodeDigest(sequence[:tt.length])
+		var codecErr bfieldcodec.BFieldCodecError
+		if !errors.As(err, &codecErr) || codecErr.Type != tt.want {
+			t.Errorf("%d elements: got %v, want error type %d", tt.length, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Digest lists are flattened row-major by default: the DigestLen elements of
+// each digest in turn, which is the layout of BFieldCodec encodings and of
+// sponge inputs. The ColumnMajor variants interleave the digests instead,
+// listing element 0 of every digest, then element 1, and so on.
+
+// DigestsToElements returns the elements of the digests in row-major order.
+func DigestsToElements(digests []Digest) []field.Element {
+	elements := make([]field.Element, DigestLen*len(digests))
+	DigestsToElementsInto(elements, digests)
+	return elements
+}
+
+// DigestsToElementsInto writes the elements of the digests to dst in
+// row-major order. Panics unless len(dst) = DigestLen·len(digests).
+func DigestsToElementsInto(dst []field.Element, digests []Digest) {
+	if len(dst) != DigestLen*len(digests) {
+		panic("DigestsToElementsInto requires DigestLen elements per digest")
+	}
+	for i := range digests {
+		copy(dst[i*DigestLen:], digests[i][:])
+	}
+}
+
+// ElementsToDigests parses row-major elements into digests.
+// Returns an error if the number of elements is not a multiple of DigestLen.
+func ElementsToDigests(elements []field.Element) ([]Digest, error) {
+	if err := checkDigestElements(elements); err != nil {
+		return nil, err
+	}
+	digests := make([]Digest, len(elements)/DigestLen)
+	ElementsToDigestsInto(digests, elements)
+	return digests, nil
+}
+
+// ElementsToDigestsInto parses row-major elements into dst.
+// Panics unless len(elements) = DigestLen·len(dst).
+func ElementsToDigestsInto(dst []Digest, elements []field.Element) {
+	if len(elements) != DigestLen*len(dst) {
+		panic("ElementsToDigestsInto requires DigestLen elements per digest")
+	}
+	for i := range dst {
+		copy(dst[i][:], elements[i*DigestLen:(i+1)*DigestLen])
+	}
+}
+
+// DigestsToElementsColumnMajor returns the elements of the digests in
+// column-major order: element j of digest i is at j·len(digests) + i.
+func DigestsToElementsColumnMajor(digests []Digest) []field.Element {
+	n := len(digests)
+	elements := make([]field.Element, DigestLen*n)
+	for i, d := range digests {
+		for j, e := range d {
+			elements[j*n+i] = e
+		}
+	}
+	return elements
+}
+
+// ElementsToDigestsColumnMajor parses column-major elements, as produced by
+// DigestsToElementsColumnMajor, into digests.
+// Returns an error if the number of elements is not a multiple of DigestLen.
+func ElementsToDigestsColumnMajor(elements []field.Element) ([]Digest, error) {
+	if err := checkDigestElements(elements); err != nil {
+		return nil, err
+	}
+	n := len(elements) / DigestLen
+	digests := make([]Digest, n)
+	for i := range digests {
+		for j := range digests[i] {
+			digests[i][j] = elements[j*n+i]
+		}
+	}
+	return digests, nil
+}
+
+// checkDigestElements returns an error if elements cannot be split into
+// whole digests, naming the offset of the incomplete one.
+func checkDigestElements(elements []field.Element) error {
+	if rest := len(elements) % DigestLen; rest != 0 {
+		return fmt.Errorf("%d elements are not a whole number of digests: %d left over at offset %d", len(elements), rest, len(elements)-rest)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package hash
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestDigestsToElementsRoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7} {
+		digests := make([]Digest, n)
+		for i := range digests {
+			digests[i] = testDigests(uint64(i))[0]
+		}
+
+		elements := DigestsToElements(digests)
+		if !reflect.DeepEqual(elements, flattenDigests(digests)) {
+			t.Fatalf("%d digests: row-major layout differs from the flattened digests", n)
+		}
+		parsed, err := ElementsToDigests(elements)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, digests) {
+			t.Errorf("%d digests: row-major round trip differs", n)
+		}
+
+		columns := DigestsToElementsColumnMajor(digests)
+		parsed, err = ElementsToDigestsColumnMajor(columns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, digests) {
+			t.Errorf("%d digests: column-major round trip differs", n)
+		}
+	}
+}
+
+func TestDigestsToElementsInto(t *testing.T) {
+	digests := testDigests(1, 2, 3)
+	elements := make([]field.Element, 3*DigestLen)
+	DigestsToElementsInto(elements, digests)
+	if !reflect.DeepEqual(elements, DigestsToElements(digests)) {
+		t.Error("DigestsToElementsInto differs from DigestsToElements")
+	}
+
+	parsed := make([]Digest, 3)
+	ElementsToDigestsInto(parsed, elements)
+	if !reflect.DeepEqual(parsed, digests) {
+		t.Error("ElementsToDigestsInto differs from the input digests")
+	}
+
+	for name, f := range map[string]func(){
+		"DigestsToElementsInto": func() { DigestsToElementsInto(elements[1:], digests) },
+		"ElementsToDigestsInto": func() { ElementsToDigestsInto(parsed, elements[1:]) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s accepted mismatched lengths", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestDigestLayouts(t *testing.T) {
+	digests := testDigests(1, 2)
+	rows := DigestsToElements(digests)
+	columns := DigestsToElementsColumnMajor(digests)
+	if reflect.DeepEqual(rows, columns) {
+		t.Fatal("row-major and column-major layouts coincide")
+	}
+	for i, d := range digests {
+		for j := range d {
+			if rows[i*DigestLen+j] != d[j] || columns[j*len(digests)+i] != d[j] {
+				t.Fatalf("element %d of digest %d is misplaced", j, i)
+			}
+		}
+	}
+
+	// Parsing one layout as the other does not round-trip
+	misparsed, err := ElementsToDigests(columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(misparsed, digests) {
+		t.Error("column-major elements parse as row-major")
+	}
+}
+
+func TestElementsToDigestsWrongLength(t *testing.T) {
+	for _, n := range []int{1, 4, 6, 12} {
+		elements := make([]field.Element, n)
+		if _, err := ElementsToDigests(elements); err == nil {
+			t.Errorf("ElementsToDigests accepted %d elements", n)
+		}
+		if _, err := ElementsToDigestsColumnMajor(elements); err == nil {
+			t.Errorf("ElementsToDigestsColumnMajor accepted %d elements", n)
+		}
+	}
+}
+
+func BenchmarkDigestsToElements(b *testing.B) {
+	digests := make([]Digest, 100_000)
+	for i := range digests {
+		digests[i][0] = field.New(uint64(i))
+	}
+	elements := make([]field.Element, DigestLen*len(digests))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DigestsToElementsInto(elements, digests)
+	}
+}
+
+func BenchmarkElementsToDigests(b *testing.B) {
+	elements := make([]field.Element, DigestLen*100_000)
+	for i := range elements {
+		elements[i] = field.New(uint64(i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ElementsToDigests(elements)
+	}
+}
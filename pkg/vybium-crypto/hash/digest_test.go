@@ -0,0 +1,94 @@
+package hash
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func randomDigest(rng *rand.Rand) Digest {
+	var d Digest
+	for i := range d {
+		d[i] = field.New(rng.Uint64() % field.P)
+	}
+	return d
+}
+
+func TestDigestHexAndBytesRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1277))
+	digests := []Digest{ZeroDigest(), {field.New(field.P - 1), field.Zero, field.One, field.New(2), field.New(3)}}
+	for i := 0; i < 20; i++ {
+		digests = append(digests, randomDigest(rng))
+	}
+	for _, d := range digests {
+		if got := DigestFromBytes(d.ToBytes()); got != d {
+			t.Errorf("DigestFromBytes(ToBytes(%v)) = %v", d, got)
+		}
+		hex := d.Hex()
+		if len(hex) != 2*DigestLen*8 {
+			t.Errorf("Hex(%v) has length %d", d, len(hex))
+		}
+		if got, err := DigestFromHex(hex); err != nil || got != d {
+			t.Errorf("DigestFromHex(%s) = %v, %v; want %v", hex, got, err, d)
+		}
+	}
+}
+
+func TestDigestFromHexInvalid(t *testing.T) {
+	for _, s := range []string{"", "zz", strings.Repeat("00", DigestLen*8-1), strings.Repeat("00", DigestLen*8+1)} {
+		if _, err := DigestFromHex(s); err == nil {
+			t.Errorf("DigestFromHex(%q) accepted", s)
+		}
+	}
+}
+
+func TestDigestCompare(t *testing.T) {
+	one := func(i int, value uint64) Digest {
+		d := ZeroDigest()
+		d[i] = field.New(value)
+		return d
+	}
+	for _, tt := range []struct {
+		a, b Digest
+		want int
+	}{
+		{ZeroDigest(), ZeroDigest(), 0},
+		{one(0, 1), ZeroDigest(), 1},
+		{one(0, field.P-1), one(1, 1), -1},
+		{one(4, 1), one(3, field.P-1), 1},
+		{one(2, 5), one(2, 7), -1},
+	} {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := tt.b.Compare(tt.a); got != -tt.want {
+			t.Errorf("Compare(%v, %v) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+		if tt.a.Less(tt.b) != (tt.want < 0) || tt.a.Greater(tt.b) != (tt.want > 0) {
+			t.Errorf("Less and Greater disagree with Compare for %v, %v", tt.a, tt.b)
+		}
+	}
+}
+
+func TestDigestSortIsTotalOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1277))
+	digests := make([]Digest, 200)
+	for i := range digests {
+		digests[i] = randomDigest(rng)
+		// Shared high elements make the lower ones decide some comparisons
+		if i%2 == 1 {
+			digests[i][4], digests[i][3] = digests[i-1][4], digests[i-1][3]
+		}
+	}
+	digests = append(digests, digests[7])
+
+	slices.SortFunc(digests, Digest.Compare)
+	for i := 1; i < len(digests); i++ {
+		if digests[i-1].Compare(digests[i]) > 0 || digests[i].Less(digests[i-1]) {
+			t.Fatalf("digests %d and %d are out of order: %v, %v", i-1, i, digests[i-1], digests[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// Digests are reinterpreted as extension-field elements by flattening their
+// limbs row-major, as DigestsToElements does, and grouping them by
+// xfield.ExtensionDegree, coefficient 0 first. A single digest splits into
+// one XFieldElement from limbs 0..2 and the two spare limbs 3 and 4.
+//
+// When the number of limbs is not a multiple of ExtensionDegree, the last
+// XFieldElement is completed with zero coefficients, so n digests pack into
+// ceil(5n/3) XFieldElements; ten digests pack into 17, the last holding two
+// limbs and one zero. The packing is frozen; the transcript's challenge
+// sampling relies on it.
+
+// ToXFieldElement splits the digest into the XFieldElement of its first
+// three limbs and its two remaining limbs.
+func (d Digest) ToXFieldElement() (xfield.XFieldElement, [DigestLen - xfield.ExtensionDegree]field.Element) {
+	var x xfield.XFieldElement
+	var spare [DigestLen - xfield.ExtensionDegree]field.Element
+	copy(x.Coefficients[:], d[:xfield.ExtensionDegree])
+	copy(spare[:], d[xfield.ExtensionDegree:])
+	return x, spare
+}
+
+// DigestFromXFieldElement is the inverse of Digest.ToXFieldElement.
+func DigestFromXFieldElement(x xfield.XFieldElement, spare [DigestLen - xfield.ExtensionDegree]field.Element) Digest {
+	var d Digest
+	copy(d[:xfield.ExtensionDegree], x.Coefficients[:])
+	copy(d[xfield.ExtensionDegree:], spare[:])
+	return d
+}
+
+// DigestsToXFieldElements packs the digests' limbs into XFieldElements,
+// zero-padding the last one.
+func DigestsToXFieldElements(digests []Digest) []xfield.XFieldElement {
+	numLimbs := DigestLen * len(digests)
+	xfes := make([]xfield.XFieldElement, (numLimbs+xfield.ExtensionDegree-1)/xfield.ExtensionDegree)
+	for i := 0; i < numLimbs; i++ {
+		xfes[i/xfield.ExtensionDegree].Coefficients[i%xfield.ExtensionDegree] = digests[i/DigestLen][i%DigestLen]
+	}
+	return xfes
+}
+
+// XFieldElementsToDigests is the inverse of DigestsToXFieldElements.
+// Returns an error unless the number of XFieldElements is that of some
+// number of packed digests and the padding coefficients are zero.
+func XFieldElementsToDigests(xfes []xfield.XFieldElement) ([]Digest, error) {
+	numLimbs := xfield.ExtensionDegree * len(xfes)
+	numDigests := numLimbs / DigestLen
+	if numDigests*DigestLen+xfield.ExtensionDegree <= numLimbs {
+		return nil, fmt.Errorf("%d XFieldElements are not a packing of digests", len(xfes))
+	}
+	for i := DigestLen * numDigests; i < numLimbs; i++ {
+		if padding := xfes[i/xfield.ExtensionDegree].Coefficients[i%xfield.ExtensionDegree]; !padding.IsZero() {
+			return nil, fmt.Errorf("nonzero padding coefficient %d at limb %d", padding.Value(), i)
+		}
+	}
+
+	digests := make([]Digest, numDigests)
+	for i := 0; i < DigestLen*numDigests; i++ {
+		digests[i/DigestLen][i%DigestLen] = xfes[i/xfield.ExtensionDegree].Coefficients[i%xfield.ExtensionDegree]
+	}
+	return digests, nil
+}
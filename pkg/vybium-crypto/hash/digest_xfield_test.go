@@ -0,0 +1,176 @@
+package hash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// sequentialDigests returns n digests whose limbs are 1, 2, 3, ... in
+// row-major order.
+func sequentialDigests(n int) []Digest {
+	digests := make([]Digest, n)
+	for i := range digests {
+		for j := range digests[i] {
+			digests[i][j] = field.New(uint64(i*DigestLen + j + 1))
+		}
+	}
+	return digests
+}
+
+func randomDigests(rng *rand.Rand, n int) []Digest {
+	digests := make([]Digest, n)
+	for i := range digests {
+		for j := range digests[i] {
+			digests[i][j] = field.New(rng.Uint64() % field.P)
+		}
+	}
+	return digests
+}
+
+func xfe(c0, c1, c2 uint64) xfield.XFieldElement {
+	return xfield.New([3]field.Element{field.New(c0), field.New(c1), field.New(c2)})
+}
+
+func TestDigestToXFieldElementRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1199))
+	for i := 0; i < 100; i++ {
+		d := randomDigests(rng, 1)[0]
+		x, spare := d.ToXFieldElement()
+		if got := DigestFromXFieldElement(x, spare); !got.Equal(d) {
+			t.Fatalf("round trip of %v gave %v", d, got)
+		}
+	}
+
+	x, spare := sequentialDigests(1)[0].ToXFieldElement()
+	if !x.Equal(xfe(1, 2, 3)) || spare != [2]field.Element{field.New(4), field.New(5)} {
+		t.Errorf("split = %v, %v; want (1, 2, 3), [4 5]", x, spare)
+	}
+}
+
+func TestDigestXFieldElementConversionsMatchXField(t *testing.T) {
+	rng := rand.New(rand.NewSource(1199))
+	for i := 0; i < 100; i++ {
+		d := randomDigests(rng, 1)[0]
+		x, _ := d.ToXFieldElement()
+		if got := Digest(x.ToDigest()); !got.Equal(DigestFromXFieldElement(x, [2]field.Element{})) {
+			t.Fatalf("xfield.ToDigest(%v) = %v disagrees with DigestFromXFieldElement", x, got)
+		}
+
+		d[3], d[4] = field.Zero, field.Zero
+		fromDigest := xfield.FromDigest(d)
+		if fromDigest == nil || !fromDigest.Equal(x) {
+			t.Fatalf("xfield.FromDigest(%v) = %v, want %v", d, fromDigest, x)
+		}
+	}
+}
+
+// The packing is frozen; these vectors pin it down.
+func TestDigestsToXFieldElementsGolden(t *testing.T) {
+	tests := []struct {
+		numDigests int
+		want       []xfield.XFieldElement
+	}{
+		{0, []xfield.XFieldElement{}},
+		{1, []xfield.XFieldElement{xfe(1, 2, 3), xfe(4, 5, 0)}},
+		{2, []xfield.XFieldElement{xfe(1, 2, 3), xfe(4, 5, 6), xfe(7, 8, 9), xfe(10, 0, 0)}},
+		{3, []xfield.XFieldElement{xfe(1, 2, 3), xfe(4, 5, 6), xfe(7, 8, 9), xfe(10, 11, 12), xfe(13, 14, 15)}},
+		{10, append(func() []xfield.XFieldElement {
+			xfes := make([]xfield.XFieldElement, 16)
+			for i := range xfes {
+				xfes[i] = xfe(uint64(3*i+1), uint64(3*i+2), uint64(3*i+3))
+			}
+			return xfes
+		}(), xfe(49, 50, 0))},
+	}
+
+	for _, tt := range tests {
+		got := DigestsToXFieldElements(sequentialDigests(tt.numDigests))
+		if len(got) != len(tt.want) {
+			t.Fatalf("%d digests packed into %d XFieldElements, want %d", tt.numDigests, len(got), len(tt.want))
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%d digests: element %d = %v, want %v", tt.numDigests, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestXFieldElementsToDigestsRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1199))
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 6, 10, 33} {
+		digests := randomDigests(rng, n)
+		xfes := DigestsToXFieldElements(digests)
+		if want := (DigestLen*n + 2) / 3; len(xfes) != want {
+			t.Fatalf("%d digests packed into %d XFieldElements, want %d", n, len(xfes), want)
+		}
+		got, err := XFieldElementsToDigests(xfes)
+		if err != nil {
+			t.Fatalf("%d digests: %v", n, err)
+		}
+		if len(got) != n {
+			t.Fatalf("%d digests unpacked into %d", n, len(got))
+		}
+		for i := range got {
+			if !got[i].Equal(digests[i]) {
+				t.Fatalf("%d digests: digest %d = %v, want %v", n, i, got[i], digests[i])
+			}
+		}
+	}
+}
+
+func TestXFieldElementsToDigestsRejectsInvalidPackings(t *testing.T) {
+	// 1, 3, 6 and 8 XFieldElements hold 3, 9, 18 and 24 limbs, none of which
+	// is the padded packing of whole digests.
+	for _, m := range []int{1, 3, 6, 8} {
+		if _, err := XFieldElementsToDigests(make([]xfield.XFieldElement, m)); err == nil {
+			t.Errorf("%d XFieldElements accepted", m)
+		}
+	}
+
+	xfes := DigestsToXFieldElements(sequentialDigests(2))
+	xfes[3].Coefficients[2] = field.One
+	if _, err := XFieldElementsToDigests(xfes); err == nil {
+		t.Error("nonzero padding accepted")
+	}
+}
+
+func TestSampleScalarsUsesCanonicalPacking(t *testing.T) {
+	rng := rand.New(rand.NewSource(1199))
+	seed := randomDigests(rng, 2)
+	input := append(append([]field.Element{}, seed[0][:]...), seed[1][:]...)
+
+	for _, numElements := range []int{0, 1, 3, 4, 7, 10, 17} {
+		sampler := New(VariableLength)
+		sampler.PadAndAbsorbAll(input)
+		scalars, err := sampler.SampleScalars(numElements)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		squeezer := New(VariableLength)
+		squeezer.PadAndAbsorbAll(input)
+		var squeezed []Digest
+		for len(squeezed)*DigestLen < numElements*xfield.ExtensionDegree {
+			block := squeezer.Squeeze()
+			digests, err := ElementsToDigests(block[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			squeezed = append(squeezed, digests...)
+		}
+		want := DigestsToXFieldElements(squeezed)[:numElements]
+
+		if len(scalars) != numElements {
+			t.Fatalf("SampleScalars(%d) returned %d elements", numElements, len(scalars))
+		}
+		for i := range scalars {
+			if !scalars[i].Equal(want[i]) {
+				t.Errorf("SampleScalars(%d)[%d] = %v, want %v", numElements, i, scalars[i], want[i])
+			}
+		}
+	}
+}
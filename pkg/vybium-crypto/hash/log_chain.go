@@ -0,0 +1,143 @@
+package hash
+
+import (
+	"math"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// LogChainDomain is the domain label under which log chain heads are hashed.
+// It is part of the chain layout; changing it changes every head.
+const LogChainDomain = "vybium/hash/log-chain"
+
+// A log chain commits to an append-only sequence of entry digests. Entries
+// are numbered from 1; the head after entry i is
+//
+//	d_i = HashVarlenDomain(LogChainDomain, d_{i-1}[0..5) ‖ lo(i) ‖ hi(i) ‖ entry_i[0..5))
+//
+// where d_0 is the chain's genesis digest and lo and hi are the low and high
+// 32 bits of i, as in bfieldcodec.EncodeUint64. The 12-element layout is
+// frozen. Binding the sequence number makes reordering and dropping entries
+// change every later head, even where entry digests repeat.
+
+// LogCheckpoint is a chain head together with the sequence number of the
+// last entry it includes; the genesis checkpoint has Seq 0.
+type LogCheckpoint struct {
+	Seq  uint64
+	Head Digest
+}
+
+// LogChain is an append-only log chain.
+//
+// A LogChain is not safe for concurrent use.
+type LogChain struct {
+	link logChainLinker
+	head Digest
+	seq  uint64
+}
+
+// NewLogChain creates an empty chain whose head is genesis.
+func NewLogChain(genesis Digest) *LogChain {
+	return &LogChain{link: newLogChainLinker(), head: genesis}
+}
+
+// Append adds an entry and returns its sequence number and the new head.
+// Panics if the chain already holds 2^64-1 entries.
+func (c *LogChain) Append(entryDigest Digest) (seq uint64, head Digest) {
+	if c.seq == math.MaxUint64 {
+		panic("log chain sequence number overflow")
+	}
+	c.seq++
+	c.head = c.link.next(c.head, c.seq, entryDigest)
+	return c.seq, c.head
+}
+
+// Head returns the current head.
+func (c *LogChain) Head() Digest {
+	return c.head
+}
+
+// Seq returns the sequence number of the last entry, or 0 for an empty
+// chain.
+func (c *LogChain) Seq() uint64 {
+	return c.seq
+}
+
+// Checkpoint returns the current head and sequence number.
+func (c *LogChain) Checkpoint() LogCheckpoint {
+	return LogCheckpoint{Seq: c.seq, Head: c.head}
+}
+
+// LogChainLink returns the head after appending the entry with sequence
+// number seq to a chain whose head is prev.
+func LogChainLink(prev Digest, seq uint64, entryDigest Digest) Digest {
+	link := newLogChainLinker()
+	return link.next(prev, seq, entryDigest)
+}
+
+// VerifySegment reports whether appending entries, numbered from
+// startSeq+1, to a chain whose head is startHead yields endHead. Returns
+// false if the numbering would overflow.
+func VerifySegment(startHead Digest, startSeq uint64, entries []Digest, endHead Digest) bool {
+	link := newLogChainLinker()
+	return link.verifySegment(startHead, startSeq, entries, endHead)
+}
+
+// VerifySparse reports whether the checkpoints are heads of one chain, with
+// entriesBetween[i] the entries from checkpoints[i] to checkpoints[i+1]:
+// each segment must hold exactly the entries between the checkpoints'
+// sequence numbers and verify as by VerifySegment. Returns false unless
+// there is one segment fewer than checkpoints and at least one checkpoint.
+func VerifySparse(checkpoints []LogCheckpoint, entriesBetween [][]Digest) bool {
+	if len(checkpoints) == 0 || len(entriesBetween) != len(checkpoints)-1 {
+		return false
+	}
+	link := newLogChainLinker()
+	for i, entries := range entriesBetween {
+		start, end := checkpoints[i], checkpoints[i+1]
+		if end.Seq < start.Seq || end.Seq-start.Seq != uint64(len(entries)) {
+			return false
+		}
+		if !link.verifySegment(start.Head, start.Seq, entries, end.Head) {
+			return false
+		}
+	}
+	return true
+}
+
+// logChainLinker computes chain heads with a labeled sponge prepared once.
+type logChainLinker struct {
+	// sponge is the labeled sponge before absorbing, copied for each head.
+	sponge Tip5
+}
+
+func newLogChainLinker() logChainLinker {
+	return logChainLinker{sponge: *newLabeled(LogChainDomain, labeledVariableLengthMarker)}
+}
+
+// next returns the head after the entry with the given sequence number.
+func (l *logChainLinker) next(prev Digest, seq uint64, entryDigest Digest) Digest {
+	var input [2*DigestLen + 2]field.Element
+	copy(input[:DigestLen], prev[:])
+	input[DigestLen] = field.New(seq & 0xFFFFFFFF)
+	input[DigestLen+1] = field.New(seq >> 32)
+	copy(input[DigestLen+2:], entryDigest[:])
+
+	sponge := l.sponge
+	sponge.PadAndAbsorbAll(input[:])
+
+	var digest Digest
+	copy(digest[:], sponge.state[:DigestLen])
+	return digest
+}
+
+func (l *logChainLinker) verifySegment(startHead Digest, startSeq uint64, entries []Digest, endHead Digest) bool {
+	if uint64(len(entries)) > math.MaxUint64-startSeq {
+		return false
+	}
+	head := startHead
+	for i, entry := range entries {
+		head = l.next(head, startSeq+uint64(i)+1, entry)
+	}
+	return head.Equal(endHead)
+}
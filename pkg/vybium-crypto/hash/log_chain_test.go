@@ -0,0 +1,203 @@
+package hash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func logChainFixtureGenesis() Digest {
+	return testDigests(1197)[0]
+}
+
+// appendAll appends the entries to a fresh chain and returns the chain with
+// the checkpoint after every entry, the genesis checkpoint first.
+func appendAll(t *testing.T, entries []Digest) (*LogChain, []LogCheckpoint) {
+	t.Helper()
+	chain := NewLogChain(logChainFixtureGenesis())
+	checkpoints := []LogCheckpoint{chain.Checkpoint()}
+	for i, entry := range entries {
+		seq, head := chain.Append(entry)
+		if seq != uint64(i+1) || head != chain.Head() {
+			t.Fatalf("append %d returned seq %d", i, seq)
+		}
+		checkpoints = append(checkpoints, chain.Checkpoint())
+	}
+	return chain, checkpoints
+}
+
+func TestLogChainDefinition(t *testing.T) {
+	prev, entry := testDigests(1, 2)[0], testDigests(1, 2)[1]
+	for _, seq := range []uint64{1, 2, 1 << 32, 1<<32 + 5, math.MaxUint64} {
+		input := append([]field.Element(nil), prev[:]...)
+		input = append(input, field.New(seq&0xFFFFFFFF), field.New(seq>>32))
+		input = append(input, entry[:]...)
+		if LogChainLink(prev, seq, entry) != HashVarlenDomain(LogChainDomain, input) {
+			t.Errorf("head for seq %d differs from its definition", seq)
+		}
+	}
+
+	chain := NewLogChain(prev)
+	if chain.Head() != prev || chain.Seq() != 0 {
+		t.Fatal("a new chain does not start at its genesis")
+	}
+	if _, head := chain.Append(entry); head != LogChainLink(prev, 1, entry) {
+		t.Error("the first entry is not numbered 1")
+	}
+}
+
+func TestLogChainGolden(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	_, checkpoints := appendAll(t, testDigests(10, 20, 30))
+
+	tests := []struct {
+		name string
+		got  Digest
+		want string
+	}{
+		{"after entry 1", checkpoints[1].Head, "8ab2f7a8bc93197623c56ea046688d87e0f6d084b53eb944295a284865b6fd040bc3e4d1cdc8403b"},
+		{"after entry 3", checkpoints[3].Head, "da5a1ffee3b5a0b0758420f1475436282ca55112cfd91db4ad0eb4eab71c518ea7457ec0e1b04d95"},
+		{"high seq", LogChainLink(logChainFixtureGenesis(), 1<<32+5, testDigests(10)[0]), "df5f429768c4e9cd6296e6de16477f093455cf986be240d30b4ed1982b240fd3dc01d509552f0584"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.Hex(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLogChainDeterministic(t *testing.T) {
+	entries := testDigests(1, 2, 3, 4, 5)
+	first, _ := appendAll(t, entries)
+	second, _ := appendAll(t, entries)
+	if first.Checkpoint() != second.Checkpoint() {
+		t.Fatal("identical logs gave different heads")
+	}
+}
+
+func TestVerifySegment(t *testing.T) {
+	entries := testDigests(1, 2, 3, 4, 5, 6, 7, 8)
+	_, checkpoints := appendAll(t, entries)
+
+	for start := 0; start <= len(entries); start++ {
+		for end := start; end <= len(entries); end++ {
+			if !VerifySegment(checkpoints[start].Head, uint64(start), entries[start:end], checkpoints[end].Head) {
+				t.Fatalf("segment (%d, %d] rejected", start, end)
+			}
+		}
+	}
+
+	end := checkpoints[len(entries)].Head
+	genesis := logChainFixtureGenesis()
+	modified := append([]Digest(nil), entries...)
+	modified[3][0] = modified[3][0].Add(field.One)
+	reordered := append([]Digest(nil), entries...)
+	reordered[2], reordered[5] = reordered[5], reordered[2]
+	dropped := append(append([]Digest(nil), entries[:4]...), entries[5:]...)
+	inserted := append(append(append([]Digest(nil), entries[:4]...), entries[4]), entries[4:]...)
+
+	for name, tampered := range map[string][]Digest{
+		"modified":  modified,
+		"reordered": reordered,
+		"dropped":   dropped,
+		"inserted":  inserted,
+		"truncated": entries[:len(entries)-1],
+	} {
+		if VerifySegment(genesis, 0, tampered, end) {
+			t.Errorf("%s entries accepted", name)
+		}
+	}
+	if VerifySegment(genesis, 1, entries, end) {
+		t.Error("segment accepted at a shifted sequence number")
+	}
+	if VerifySegment(testDigests(0)[0], 0, entries, end) {
+		t.Error("segment accepted from another genesis")
+	}
+	if VerifySegment(genesis, math.MaxUint64-1, entries[:2], end) {
+		t.Error("segment overflowing the sequence number accepted")
+	}
+}
+
+func TestVerifySparse(t *testing.T) {
+	entries := testDigests(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	_, all := appendAll(t, entries)
+
+	// Checkpoints at 0, 3, 4, 4 and 10; the repeated checkpoint has an
+	// empty segment.
+	seqs := []int{0, 3, 4, 4, 10}
+	checkpoints := make([]LogCheckpoint, len(seqs))
+	segments := make([][]Digest, len(seqs)-1)
+	for i, seq := range seqs {
+		checkpoints[i] = all[seq]
+		if i > 0 {
+			segments[i-1] = entries[seqs[i-1]:seq]
+		}
+	}
+	if !VerifySparse(checkpoints, segments) {
+		t.Fatal("valid checkpoints rejected")
+	}
+	if !VerifySparse(checkpoints[:1], nil) {
+		t.Error("a single checkpoint rejected")
+	}
+
+	// A segment straddling a checkpoint boundary is rejected.
+	shifted := [][]Digest{entries[0:4], entries[4:4], entries[4:4], entries[4:10]}
+	if VerifySparse(checkpoints, shifted) {
+		t.Error("entries moved across a checkpoint accepted")
+	}
+
+	badHead := append([]LogCheckpoint(nil), checkpoints...)
+	badHead[2].Head[0] = badHead[2].Head[0].Add(field.One)
+	badSeq := append([]LogCheckpoint(nil), checkpoints...)
+	badSeq[4].Seq = 11
+	backwards := append([]LogCheckpoint(nil), checkpoints...)
+	backwards[1], backwards[2] = backwards[2], backwards[1]
+
+	for name, cps := range map[string][]LogCheckpoint{
+		"bad head": badHead,
+		"bad seq":  badSeq,
+		"swapped":  backwards,
+	} {
+		if VerifySparse(cps, segments) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	if VerifySparse(checkpoints, segments[:3]) {
+		t.Error("missing segment accepted")
+	}
+	if VerifySparse(nil, nil) {
+		t.Error("no checkpoints accepted")
+	}
+}
+
+func TestLogChainAppendOverflowPanics(t *testing.T) {
+	chain := &LogChain{link: newLogChainLinker(), seq: math.MaxUint64}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	chain.Append(Digest{})
+}
+
+func BenchmarkLogChainAppend(b *testing.B) {
+	entry := Digest(HashVarlen([]field.Element{field.New(1197)}))
+	chain := NewLogChain(Digest{})
+	for i := 0; i < b.N; i++ {
+		chain.Append(entry)
+	}
+}
+
+// BenchmarkLogChainAppendMillion appends 10^6 entries per iteration.
+func BenchmarkLogChainAppendMillion(b *testing.B) {
+	entry := Digest(HashVarlen([]field.Element{field.New(1197)}))
+	for i := 0; i < b.N; i++ {
+		chain := NewLogChain(Digest{})
+		for j := 0; j < 1_000_000; j++ {
+			chain.Append(entry)
+		}
+	}
+}
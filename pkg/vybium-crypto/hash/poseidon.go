@@ -46,6 +46,8 @@ type Poseidon struct {
 	mdsMatrix      [][]field.Element
 	// Security level
 	securityLevel int // M: Security level in bits
+	// ID of the parameters, round constants and MDS matrix
+	id Digest
 }
 
 // PoseidonParameters represents the parameters for a specific Poseidon instance
@@ -66,16 +68,12 @@ func NewPoseidon(params *PoseidonParameters) (*Poseidon, error) {
 		params = GetDefaultPoseidonParameters(128)
 	}
 
-	// Generate round constants and MDS matrix
-	roundConstants, err := generatePoseidonRoundConstants(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate round constants: %w", err)
+	if err := validatePoseidonParameters(params); err != nil {
+		return nil, err
 	}
 
-	mdsMatrix, err := generatePoseidonMDSMatrix(params.Width)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate MDS matrix: %w", err)
-	}
+	// Generate round constants and MDS matrix
+	roundConstants, mdsMatrix := generatePoseidonConstants(params)
 
 	return &Poseidon{
 		roundsFull:     params.RoundsFull,
@@ -86,6 +84,7 @@ func NewPoseidon(params *PoseidonParameters) (*Poseidon, error) {
 		roundConstants: roundConstants,
 		mdsMatrix:      mdsMatrix,
 		securityLevel:  params.SecurityLevel,
+		id:             poseidonID(params, roundConstants, mdsMatrix),
 	}, nil
 }
 
@@ -97,27 +96,11 @@ func GetDefaultPoseidonParameters(securityLevel int) *PoseidonParameters {
 	// Select optimal parameters based on security analysis from the paper
 	switch {
 	case securityLevel == 128:
-		// 128-bit security with 64-bit field
-		return &PoseidonParameters{
-			SecurityLevel: 128,
-			FieldSize:     fieldSize,
-			Width:         4,  // t = 4
-			Rate:          3,  // r = 3 (capacity = 1)
-			RoundsFull:    8,  // RF = 8
-			RoundsPartial: 84, // RP = 84
-			SboxPower:     5,  // α = 5 (x^5)
-		}
+		// 128-bit security with 64-bit field: t = 4, r = 3, RF = 8, RP = 84, α = 7
+		return PoseidonGoldilocks128()
 	case securityLevel == 256:
-		// 256-bit security with 64-bit field
-		return &PoseidonParameters{
-			SecurityLevel: 256,
-			FieldSize:     fieldSize,
-			Width:         4,   // t = 4
-			Rate:          3,   // r = 3 (capacity = 1)
-			RoundsFull:    8,   // RF = 8
-			RoundsPartial: 170, // RP = 170
-			SboxPower:     5,   // α = 5 (x^5)
-		}
+		// 256-bit security with 64-bit field: t = 4, r = 3, RF = 8, RP = 170, α = 7
+		return PoseidonGoldilocks256()
 	default:
 		// Conservative default (128-bit)
 		return &PoseidonParameters{
@@ -127,23 +110,20 @@ func GetDefaultPoseidonParameters(securityLevel int) *PoseidonParameters {
 			Rate:          3,
 			RoundsFull:    8,
 			RoundsPartial: 100, // Conservative estimate
-			SboxPower:     5,
+			SboxPower:     7,
 		}
 	}
 }
 
 // Hash computes the Poseidon hash using sponge construction.
 // Returns the first element of the state after processing all inputs.
+//
+// The first capacity element starts out as the number of inputs, which
+// separates inputs that differ only in trailing zeros. The empty input
+// therefore hashes to the first element of the permuted zero state.
 func (p *Poseidon) Hash(inputs []field.Element) field.Element {
-	if len(inputs) == 0 {
-		return field.Zero
-	}
-
-	// Initialize state with capacity + rate
 	state := make([]field.Element, p.width)
-	for i := 0; i < p.width; i++ {
-		state[i] = field.Zero
-	}
+	state[p.rate] = field.New(uint64(len(inputs)))
 
 	// Process inputs using sponge construction
 	for i := 0; i < len(inputs); i += p.rate {
@@ -155,6 +135,9 @@ func (p *Poseidon) Hash(inputs []field.Element) field.Element {
 		// Apply Poseidon permutation
 		state = p.poseidonPermutation(state)
 	}
+	if len(inputs) == 0 {
+		state = p.poseidonPermutation(state)
+	}
 
 	// Squeeze output (first element of state)
 	return state[0]
@@ -212,7 +195,9 @@ func (p *Poseidon) fullRound(state []field.Element, round int) []field.Element {
 	return state
 }
 
-// partialRound applies a partial round of Poseidon
+// partialRound applies a partial round of Poseidon. As in the reference
+// implementation, the whole round constant vector is added, although only
+// the first element goes through the S-box.
 func (p *Poseidon) partialRound(state []field.Element, round int) []field.Element {
 	// Add round constants
 	for i := 0; i < p.width; i++ {
@@ -232,16 +217,21 @@ func (p *Poseidon) partialRound(state []field.Element, round int) []field.Elemen
 
 // sbox applies the S-box transformation x^α
 func (p *Poseidon) sbox(x field.Element) field.Element {
-	// Optimized S-box computation for α=5: x^5 = x * x^2 * x^2
-	if p.sboxPower == 5 {
+	return poseidonSbox(x, p.sboxPower)
+}
+
+// poseidonSbox computes x^power, the S-box of Poseidon and Poseidon2.
+func poseidonSbox(x field.Element, power int) field.Element {
+	// Optimized S-box computation for α=7: x^7 = x * x^2 * x^4
+	if power == 7 {
 		x2 := x.Square()
 		x4 := x2.Square()
-		return x.Mul(x4)
+		return x.Mul(x2).Mul(x4)
 	}
 
 	// General case
 	result := x
-	for i := 1; i < p.sboxPower; i++ {
+	for i := 1; i < power; i++ {
 		result = result.Mul(x)
 	}
 	return result
@@ -264,48 +254,81 @@ func (p *Poseidon) applyMDSMatrix(state []field.Element) []field.Element {
 	return newState
 }
 
-// generatePoseidonRoundConstants generates round constants using Grain LFSR
-func generatePoseidonRoundConstants(params *PoseidonParameters) ([][]field.Element, error) {
-	// Initialize Grain LFSR with parameters
+// validatePoseidonParameters reports parameters for which the permutation
+// is not well defined.
+func validatePoseidonParameters(params *PoseidonParameters) error {
+	if params.Rate < 1 || params.Rate >= params.Width {
+		return fmt.Errorf("poseidon rate %d must be between 1 and the width %d minus one", params.Rate, params.Width)
+	}
+	if params.RoundsFull < 0 || params.RoundsFull%2 != 0 || params.RoundsPartial < 0 {
+		return fmt.Errorf("poseidon rounds must be non-negative with an even number of full rounds, got %d full and %d partial", params.RoundsFull, params.RoundsPartial)
+	}
+	// x^α is a permutation of the field only if α is coprime to P-1
+	alpha := big.NewInt(int64(params.SboxPower))
+	order := new(big.Int).SetUint64(field.P - 1)
+	if params.SboxPower < 3 || new(big.Int).GCD(nil, nil, alpha, order).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("poseidon S-box power %d is not a permutation of the field", params.SboxPower)
+	}
+	return nil
+}
+
+// generatePoseidonConstants generates the round constants and the MDS
+// matrix from one Grain LFSR stream, as the reference parameter script
+// generate_parameters_grain.sage of the Poseidon authors does, which the
+// HorizenLabs Poseidon instances are generated with.
+//
+// The constants are (RoundsFull+RoundsPartial)·Width elements, round by
+// round, sampled as by nextCanonicalElement. The MDS matrix is the Cauchy
+// matrix 1/(x_i + y_j) of 2·Width further 64-bit samples reduced modulo P,
+// drawn again while they are not distinct or some x_i + y_j is zero. The
+// reference script additionally draws again when the matrix fails its
+// invariant subspace checks (Algorithms 1 to 3); those are not repeated
+// here.
+func generatePoseidonConstants(params *PoseidonParameters) (roundConstants, mdsMatrix [][]field.Element) {
 	lfsr := NewGrainLFSR(params)
 
-	// Generate constants for all rounds
 	totalRounds := params.RoundsFull + params.RoundsPartial
-	roundConstants := make([][]field.Element, totalRounds)
-
-	for round := 0; round < totalRounds; round++ {
+	roundConstants = make([][]field.Element, totalRounds)
+	for round := range roundConstants {
 		roundConstants[round] = make([]field.Element, params.Width)
-		for i := 0; i < params.Width; i++ {
-			// Generate random field element
-			randomValue := lfsr.NextFieldElement()
-			roundConstants[round][i] = randomValue
+		for i := range roundConstants[round] {
+			roundConstants[round][i] = lfsr.nextCanonicalElement()
 		}
 	}
 
-	return roundConstants, nil
+	return roundConstants, generatePoseidonMDSMatrix(lfsr, params.Width)
 }
 
-// generatePoseidonMDSMatrix generates a Maximum Distance Separable matrix using Cauchy construction
-func generatePoseidonMDSMatrix(width int) ([][]field.Element, error) {
-	// Generate a Cauchy matrix which is always MDS
-	// Cauchy matrix: M[i][j] = 1/(x_i + y_j) where x_i and y_j are distinct
-	matrix := make([][]field.Element, width)
-
-	for i := 0; i < width; i++ {
-		matrix[i] = make([]field.Element, width)
-		for j := 0; j < width; j++ {
-			// Use carefully chosen x_i and y_j to ensure all sums are distinct
-			x := field.New(uint64(i + 1))
-			y := field.New(uint64(j + width + 1))
-			sum := x.Add(y)
-
-			// Compute inverse
-			inv := sum.Inverse()
-			matrix[i][j] = inv
+// generatePoseidonMDSMatrix samples a width×width Cauchy matrix, which is
+// always MDS, from lfsr.
+func generatePoseidonMDSMatrix(lfsr *GrainLFSR, width int) [][]field.Element {
+	for {
+		samples := make([]field.Element, 2*width)
+		for distinct := false; !distinct; {
+			seen := make(map[field.Element]bool, len(samples))
+			distinct = true
+			for i := range samples {
+				samples[i] = field.New(lfsr.nextBits(64))
+				distinct = distinct && !seen[samples[i]]
+				seen[samples[i]] = true
+			}
+		}
+		xs, ys := samples[:width], samples[width:]
+
+		matrix := make([][]field.Element, width)
+		singular := false
+		for i := range matrix {
+			matrix[i] = make([]field.Element, width)
+			for j := range matrix[i] {
+				sum := xs[i].Add(ys[j])
+				singular = singular || sum.IsZero()
+				matrix[i][j] = sum.Inverse()
+			}
+		}
+		if !singular {
+			return matrix
 		}
 	}
-
-	return matrix, nil
 }
 
 // GrainLFSR implements the Grain LFSR for parameter generation
@@ -324,51 +347,91 @@ func NewGrainLFSR(params *PoseidonParameters) *GrainLFSR {
 }
 
 // initialize initializes the Grain LFSR state according to the Poseidon paper
+// Each field is written most significant bit first, as by the reference
+// script generate_parameters_grain.sage.
 func (g *GrainLFSR) initialize() {
-	// Initialize state with parameters
-	// b0, b1: field type (1, 1 for prime field)
-	g.state[0] = true
-	g.state[1] = true
-
-	// b2-b5: S-box type (5 = 0101 in binary for α=5)
-	sboxBits := g.params.SboxPower
-	for i := 0; i < 4; i++ {
-		g.state[2+i] = (sboxBits>>i)&1 == 1
+	position := 0
+	write := func(value, width int) {
+		for i := width - 1; i >= 0; i-- {
+			g.state[position] = (value>>i)&1 == 1
+			position++
+		}
 	}
 
+	// b0, b1: field type (1 for a prime field)
+	write(1, 2)
+	// b2-b5: S-box type (0 for x^α, whatever α is)
+	write(0, 4)
 	// b6-b17: field size n (64 bits for BFieldElement)
-	fieldSize := g.params.FieldSize
-	for i := 0; i < 12; i++ {
-		g.state[6+i] = (fieldSize>>i)&1 == 1
-	}
-
+	write(g.params.FieldSize, 12)
 	// b18-b29: width t
-	width := g.params.Width
-	for i := 0; i < 12; i++ {
-		g.state[18+i] = (width>>i)&1 == 1
-	}
-
+	write(g.params.Width, 12)
 	// b30-b39: RF (full rounds)
-	rf := g.params.RoundsFull
-	for i := 0; i < 10; i++ {
-		g.state[30+i] = (rf>>i)&1 == 1
-	}
-
+	write(g.params.RoundsFull, 10)
 	// b40-b49: RP (partial rounds)
-	rp := g.params.RoundsPartial
-	for i := 0; i < 10; i++ {
-		g.state[40+i] = (rp>>i)&1 == 1
-	}
+	write(g.params.RoundsPartial, 10)
 
 	// b50-b79: set to 1
 	for i := 50; i < 80; i++ {
 		g.state[i] = true
 	}
 
-	// Discard first 160 bits (warm-up)
+	// Discard first 160 bits (warm-up). sampleBit reads the bit leaving
+	// the register, which was fed back 80 clocks earlier, so clock another
+	// 80 times for it to read the bits the reference generator outputs.
+	for i := 0; i < 160+80; i++ {
+		g.update()
+	}
+}
+
+// grainSeedBits is the number of register bits newGrainLFSRFromSeed fills
+// from its seed. The remaining bits are set to one, as in initialize, so the
+// register is never all zero.
+const grainSeedBits = 50
+
+// newGrainLFSRFromSeed returns a Grain LFSR for constants not described by
+// PoseidonParameters. The seed's bits, least significant bit of each byte
+// first, are XORed cyclically into register bits 0 to grainSeedBits-1,
+// bits grainSeedBits to 79 are one, and the first 160 bits are discarded,
+// as by initialize.
+func newGrainLFSRFromSeed(seed []byte) *GrainLFSR {
+	g := &GrainLFSR{}
+	for i := 0; i < 8*len(seed); i++ {
+		if seed[i/8]>>(i%8)&1 == 1 {
+			g.state[i%grainSeedBits] = !g.state[i%grainSeedBits]
+		}
+	}
+	for i := grainSeedBits; i < 80; i++ {
+		g.state[i] = true
+	}
 	for i := 0; i < 160; i++ {
 		g.update()
 	}
+	return g
+}
+
+// nextCanonicalElement returns a uniformly distributed field element: 64
+// bits from sampleBit, most significant first, drawn again while the value
+// is at least P, as the Poseidon reference implementation samples field
+// elements.
+func (g *GrainLFSR) nextCanonicalElement() field.Element {
+	for {
+		if value := g.nextBits(64); value < field.P {
+			return field.New(value)
+		}
+	}
+}
+
+// nextBits returns n bits from sampleBit, most significant first.
+func (g *GrainLFSR) nextBits(n int) uint64 {
+	var value uint64
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if g.sampleBit() {
+			value |= 1
+		}
+	}
+	return value
 }
 
 // update updates the LFSR state using the Grain feedback function
@@ -487,6 +550,15 @@ func (s *PoseidonSponge) Squeeze(outputLength int) []field.Element {
 	return outputs
 }
 
+// Reset returns the sponge to its initial, all-zero state, so that one
+// sponge can hash many inputs without regenerating its round constants.
+func (s *PoseidonSponge) Reset() {
+	for i := range s.state {
+		s.state[i] = field.Zero
+	}
+	s.absorbed = 0
+}
+
 // PoseidonHash is a convenience function for simple hashing with default 128-bit security
 func PoseidonHash(inputs []field.Element) field.Element {
 	poseidon, err := NewPoseidon(nil) // Use default parameters
@@ -0,0 +1,204 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Poseidon2 implements the Poseidon2 permutation of "Poseidon2: A Faster
+// Version of the Poseidon Hash Function" (Grassi, Khovratovich and
+// Schofnegger, 2023).
+//
+// It keeps the round structure of Poseidon but replaces its dense MDS
+// matrix. External (full) rounds multiply by the MDS-light matrix M_E,
+// built from the 4×4 matrix M4 (M4 itself at width 4) and computed with
+// additions only. Internal (partial) rounds add a single round constant and
+// multiply by M_I = 1·1ᵀ + diag(d), which costs Width multiplications
+// instead of Width². The state is multiplied by M_E once before the first
+// round.
+type Poseidon2 struct {
+	roundsFull    int
+	roundsPartial int
+	sboxPower     int
+	width         int
+	// externalConstants holds Width constants for each external round,
+	// first half then second half
+	externalConstants [][]field.Element
+	// internalConstants holds one constant for each internal round
+	internalConstants []field.Element
+	// internalDiagonal is d in M_I = 1·1ᵀ + diag(d)
+	internalDiagonal []field.Element
+}
+
+// NewPoseidon2 creates a Poseidon2 permutation. Nil parameters select
+// GetDefaultPoseidonParameters(128).
+//
+// The width must be 2, 3 or a multiple of 4, as M_E is defined for those
+// widths only. The constants come from the Grain LFSR NewGrainLFSR seeds
+// with the parameters: RoundsFull·Width external constants, then
+// RoundsPartial internal ones, each sampled as a canonical field element.
+// For widths 2 and 3 the diagonal of M_I is that of the paper; for larger
+// widths it is sampled next, drawn again while M_I is singular.
+func NewPoseidon2(params *PoseidonParameters) (*Poseidon2, error) {
+	if params == nil {
+		params = GetDefaultPoseidonParameters(128)
+	}
+	if err := validatePoseidonParameters(params); err != nil {
+		return nil, err
+	}
+	if params.Width != 2 && params.Width != 3 && params.Width%4 != 0 {
+		return nil, fmt.Errorf("poseidon2 width %d must be 2, 3 or a multiple of 4", params.Width)
+	}
+
+	lfsr := NewGrainLFSR(params)
+	externalConstants := make([][]field.Element, params.RoundsFull)
+	for round := range externalConstants {
+		externalConstants[round] = make([]field.Element, params.Width)
+		for i := range externalConstants[round] {
+			externalConstants[round][i] = lfsr.nextCanonicalElement()
+		}
+	}
+	internalConstants := make([]field.Element, params.RoundsPartial)
+	for round := range internalConstants {
+		internalConstants[round] = lfsr.nextCanonicalElement()
+	}
+
+	return &Poseidon2{
+		roundsFull:        params.RoundsFull,
+		roundsPartial:     params.RoundsPartial,
+		sboxPower:         params.SboxPower,
+		width:             params.Width,
+		externalConstants: externalConstants,
+		internalConstants: internalConstants,
+		internalDiagonal:  poseidon2InternalDiagonal(lfsr, params.Width),
+	}, nil
+}
+
+// poseidon2InternalDiagonal returns d for M_I = 1·1ᵀ + diag(d). M_I is
+// invertible when no d_i is zero and 1 + Σ 1/d_i is not zero.
+func poseidon2InternalDiagonal(lfsr *GrainLFSR, width int) []field.Element {
+	switch width {
+	case 2:
+		// M_I = [[2, 1], [1, 3]]
+		return []field.Element{field.One, field.New(2)}
+	case 3:
+		// M_I = [[2, 1, 1], [1, 2, 1], [1, 1, 3]]
+		return []field.Element{field.One, field.One, field.New(2)}
+	}
+	for {
+		diagonal := make([]field.Element, width)
+		determinantFactor := field.One
+		for i := range diagonal {
+			for diagonal[i].IsZero() {
+				diagonal[i] = lfsr.nextCanonicalElement()
+			}
+			determinantFactor = determinantFactor.Add(diagonal[i].Inverse())
+		}
+		if !determinantFactor.IsZero() {
+			return diagonal
+		}
+	}
+}
+
+// Width returns the number of elements the permutation acts on.
+func (p *Poseidon2) Width() int {
+	return p.width
+}
+
+// Permutation returns the Poseidon2 permutation of state, leaving state
+// unchanged. Panics if state does not have Width elements.
+func (p *Poseidon2) Permutation(state []field.Element) []field.Element {
+	if len(state) != p.width {
+		panic(fmt.Sprintf("poseidon2 state has %d elements, want %d", len(state), p.width))
+	}
+	result := append([]field.Element(nil), state...)
+	p.externalLinearLayer(result)
+
+	half := p.roundsFull / 2
+	for round := 0; round < half; round++ {
+		p.externalRound(result, p.externalConstants[round])
+	}
+	for round := 0; round < p.roundsPartial; round++ {
+		p.internalRound(result, p.internalConstants[round])
+	}
+	for round := half; round < p.roundsFull; round++ {
+		p.externalRound(result, p.externalConstants[round])
+	}
+	return result
+}
+
+// externalRound adds the round constants, applies the S-box to every
+// element and multiplies by M_E.
+func (p *Poseidon2) externalRound(state, constants []field.Element) {
+	for i := range state {
+		state[i] = poseidonSbox(state[i].Add(constants[i]), p.sboxPower)
+	}
+	p.externalLinearLayer(state)
+}
+
+// internalRound adds the round constant to the first element, applies the
+// S-box to it and multiplies by M_I.
+func (p *Poseidon2) internalRound(state []field.Element, constant field.Element) {
+	state[0] = poseidonSbox(state[0].Add(constant), p.sboxPower)
+
+	sum := field.Zero
+	for _, element := range state {
+		sum = sum.Add(element)
+	}
+	for i := range state {
+		state[i] = state[i].Mul(p.internalDiagonal[i]).Add(sum)
+	}
+}
+
+// externalLinearLayer multiplies state in place by M_E: circ(2, 1) or
+// circ(2, 1, 1) for widths 2 and 3, M4 itself for width 4, and otherwise
+// circ(2·M4, M4, ..., M4), which applies M4 to each block of four and adds
+// to each element the sum of the elements at its position in all blocks.
+func (p *Poseidon2) externalLinearLayer(state []field.Element) {
+	switch p.width {
+	case 2, 3:
+		sum := field.Zero
+		for _, element := range state {
+			sum = sum.Add(element)
+		}
+		for i := range state {
+			state[i] = state[i].Add(sum)
+		}
+		return
+	case 4:
+		applyM4((*[4]field.Element)(state))
+		return
+	}
+
+	for block := 0; block < len(state); block += 4 {
+		applyM4((*[4]field.Element)(state[block : block+4]))
+	}
+	var sums [4]field.Element
+	for i, element := range state {
+		sums[i%4] = sums[i%4].Add(element)
+	}
+	for i := range state {
+		state[i] = state[i].Add(sums[i%4])
+	}
+}
+
+// applyM4 multiplies x in place by
+//
+//	M4 = [[5, 7, 1, 3], [4, 6, 1, 1], [1, 3, 5, 7], [1, 1, 4, 6]]
+//
+// with the eight-addition chain of the Poseidon2 paper.
+func applyM4(x *[4]field.Element) {
+	t0 := x[0].Add(x[1])
+	t1 := x[2].Add(x[3])
+	t2 := x[1].Add(x[1]).Add(t1)
+	t3 := x[3].Add(x[3]).Add(t0)
+	t4 := t1.Add(t1)
+	t4 = t4.Add(t4).Add(t3)
+	t5 := t0.Add(t0)
+	t5 = t5.Add(t5).Add(t2)
+	x[0] = t3.Add(t5)
+	x[1] = t5
+	x[2] = t2.Add(t4)
+	x[3] = t4
+}
@@ -0,0 +1,187 @@
+package hash
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func poseidon2Parameters(width int) *PoseidonParameters {
+	params := PoseidonGoldilocks128()
+	params.Width = width
+	params.Rate = width - 1
+	return params
+}
+
+func TestPoseidon2ExternalLinearLayer(t *testing.T) {
+	m4 := [4][4]uint64{{5, 7, 1, 3}, {4, 6, 1, 1}, {1, 3, 5, 7}, {1, 1, 4, 6}}
+	rng := rand.New(rand.NewSource(1287))
+	for _, width := range []int{2, 3, 4, 8, 12} {
+		poseidon2, err := NewPoseidon2(poseidon2Parameters(width))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// M_E is circ(2, 1, ...) for widths 2 and 3, M4 for width 4 and
+		// circ(2·M4, M4, ...) for larger multiples of 4
+		entry := func(i, j int) uint64 {
+			if width < 4 {
+				if i == j {
+					return 2
+				}
+				return 1
+			}
+			if width > 4 && i/4 == j/4 {
+				return 2 * m4[i%4][j%4]
+			}
+			return m4[i%4][j%4]
+		}
+
+		state := make([]field.Element, width)
+		for i := range state {
+			state[i] = field.New(rng.Uint64())
+		}
+		got := append([]field.Element(nil), state...)
+		poseidon2.externalLinearLayer(got)
+		for i := range state {
+			want := field.Zero
+			for j := range state {
+				want = want.Add(field.New(entry(i, j)).Mul(state[j]))
+			}
+			if got[i] != want {
+				t.Errorf("width %d: M_E row %d gives %v, want %v", width, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestPoseidon2InternalMatrixIsInvertible(t *testing.T) {
+	for _, width := range []int{2, 3, 4, 8, 12, 16} {
+		poseidon2, err := NewPoseidon2(poseidon2Parameters(width))
+		if err != nil {
+			t.Fatal(err)
+		}
+		factor := field.One
+		for i, d := range poseidon2.internalDiagonal {
+			if d.IsZero() {
+				t.Fatalf("width %d: diagonal entry %d is zero", width, i)
+			}
+			factor = factor.Add(d.Inverse())
+		}
+		if factor.IsZero() {
+			t.Errorf("width %d: M_I is singular", width)
+		}
+	}
+}
+
+func TestPoseidon2PermutationIsDeterministic(t *testing.T) {
+	first, err := NewPoseidon2(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewPoseidon2(GetDefaultPoseidonParameters(128))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1287))
+	for i := 0; i < 10; i++ {
+		state := make([]field.Element, first.Width())
+		for j := range state {
+			state[j] = field.New(rng.Uint64())
+		}
+		input := append([]field.Element(nil), state...)
+		got, want := first.Permutation(state), second.Permutation(state)
+		for j := range got {
+			if got[j] != want[j] {
+				t.Fatalf("instances permute %v differently", state)
+			}
+		}
+		for j := range state {
+			if state[j] != input[j] {
+				t.Fatal("Permutation modified its input")
+			}
+		}
+	}
+}
+
+func TestPoseidon2PermutationHasNoCollisionsOnSmallInputs(t *testing.T) {
+	tests := []struct {
+		width  int
+		values uint64
+	}{
+		{2, 16},
+		{3, 6},
+		{4, 3},
+		{8, 2},
+	}
+	for _, tt := range tests {
+		poseidon2, err := NewPoseidon2(poseidon2Parameters(tt.width))
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		state := make([]field.Element, tt.width)
+		count := 1
+		for i := 0; i < tt.width; i++ {
+			count *= int(tt.values)
+		}
+		for n := 0; n < count; n++ {
+			// The state holds the digits of n in base tt.values
+			for i, rest := 0, uint64(n); i < tt.width; i, rest = i+1, rest/tt.values {
+				state[i] = field.New(rest % tt.values)
+			}
+			var key strings.Builder
+			for _, element := range poseidon2.Permutation(state) {
+				key.WriteString(element.Hex())
+			}
+			if seen[key.String()] {
+				t.Fatalf("width %d: collision at input %v", tt.width, state)
+			}
+			seen[key.String()] = true
+		}
+	}
+}
+
+func TestPoseidon2DiffersFromPoseidon(t *testing.T) {
+	poseidon, err := NewPoseidon(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poseidon2, err := NewPoseidon2(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := make([]field.Element, poseidon2.Width())
+	got := poseidon2.Permutation(state)
+	want := poseidon.poseidonPermutation(append([]field.Element(nil), state...))
+	if got[0] == want[0] {
+		t.Error("Poseidon2 and Poseidon permute the zero state alike")
+	}
+}
+
+func TestNewPoseidon2RejectsInvalidParameters(t *testing.T) {
+	for _, width := range []int{1, 5, 6, 7, 10} {
+		if _, err := NewPoseidon2(poseidon2Parameters(width)); err == nil {
+			t.Errorf("width %d accepted", width)
+		}
+	}
+	params := PoseidonGoldilocks128()
+	params.SboxPower = 5
+	if _, err := NewPoseidon2(params); err == nil {
+		t.Error("S-box power 5 accepted")
+	}
+}
+
+func TestPoseidon2PermutationPanicsOnWrongWidth(t *testing.T) {
+	poseidon2, err := NewPoseidon2(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Permutation accepted a state of the wrong width")
+		}
+	}()
+	poseidon2.Permutation(make([]field.Element, poseidon2.Width()+1))
+}
@@ -1,6 +1,7 @@
 package hash
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -86,25 +87,22 @@ func BenchmarkGrainLFSR(b *testing.B) {
 
 // BenchmarkMDSMatrixGeneration benchmarks MDS matrix generation
 func BenchmarkMDSMatrixGeneration(b *testing.B) {
+	params := GetDefaultPoseidonParameters(128)
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := generatePoseidonMDSMatrix(4)
-		if err != nil {
-			b.Fatal(err)
-		}
+		_ = generatePoseidonMDSMatrix(NewGrainLFSR(params), params.Width)
 	}
 }
 
-// BenchmarkRoundConstantsGeneration benchmarks round constants generation
+// BenchmarkRoundConstantsGeneration benchmarks round constants and MDS
+// matrix generation
 func BenchmarkRoundConstantsGeneration(b *testing.B) {
 	params := GetDefaultPoseidonParameters(128)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := generatePoseidonRoundConstants(params)
-		if err != nil {
-			b.Fatal(err)
-		}
+		_, _ = generatePoseidonConstants(params)
 	}
 }
 
@@ -244,3 +242,44 @@ func BenchmarkPoseidonConvenience(b *testing.B) {
 		_ = PoseidonHash(inputs)
 	}
 }
+
+// BenchmarkPoseidon2Permutation compares the Poseidon and Poseidon2
+// permutations with equal parameters. The mul/perm metric counts field
+// multiplications: Poseidon2 replaces the Width² of the dense MDS matrix
+// in every round by none in external rounds and Width in internal ones.
+func BenchmarkPoseidon2Permutation(b *testing.B) {
+	for _, width := range []int{4, 8, 12} {
+		params := GetDefaultPoseidonParameters(128)
+		params.Width, params.Rate = width, width-1
+		rounds := params.RoundsFull + params.RoundsPartial
+		sboxes := params.RoundsFull*width + params.RoundsPartial
+		// x^7 takes two squarings and two multiplications
+		sboxMultiplications := 4 * sboxes
+
+		state := make([]field.Element, width)
+		for i := range state {
+			state[i] = field.New(uint64(i + 1))
+		}
+
+		b.Run(fmt.Sprintf("Poseidon/width=%d", width), func(b *testing.B) {
+			poseidon, err := NewPoseidon(params)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(sboxMultiplications+rounds*width*width), "mul/perm")
+			for i := 0; i < b.N; i++ {
+				_ = poseidon.poseidonPermutation(append([]field.Element(nil), state...))
+			}
+		})
+		b.Run(fmt.Sprintf("Poseidon2/width=%d", width), func(b *testing.B) {
+			poseidon2, err := NewPoseidon2(params)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(sboxMultiplications+params.RoundsPartial*width), "mul/perm")
+			for i := 0; i < b.N; i++ {
+				_ = poseidon2.Permutation(state)
+			}
+		})
+	}
+}
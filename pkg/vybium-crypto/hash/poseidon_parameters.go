@@ -0,0 +1,141 @@
+package hash
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// PoseidonParametersDomain is the domain label under which parameter IDs
+// are hashed.
+const PoseidonParametersDomain = "vybium/hash/poseidon-parameters"
+
+// poseidonParametersEncodingLen is the length of the encoding of
+// PoseidonParameters: seven u64 fields of two elements each.
+const poseidonParametersEncodingLen = 7 * 2
+
+// PoseidonGoldilocks128 returns the canonical parameters for 128-bit
+// security over the Goldilocks field P = 2^64 - 2^32 + 1, those of
+// GetDefaultPoseidonParameters(128).
+func PoseidonGoldilocks128() *PoseidonParameters {
+	return &PoseidonParameters{
+		SecurityLevel: 128,
+		FieldSize:     64,
+		Width:         4,
+		Rate:          3,
+		RoundsFull:    8,
+		RoundsPartial: 84,
+		SboxPower:     7,
+	}
+}
+
+// PoseidonGoldilocks256 returns the canonical parameters for 256-bit
+// security over the Goldilocks field, those of
+// GetDefaultPoseidonParameters(256).
+func PoseidonGoldilocks256() *PoseidonParameters {
+	return &PoseidonParameters{
+		SecurityLevel: 256,
+		FieldSize:     64,
+		Width:         4,
+		Rate:          3,
+		RoundsFull:    8,
+		RoundsPartial: 170,
+		SboxPower:     7,
+	}
+}
+
+// Equal reports whether params and other have the same fields. Two nil
+// parameter sets are equal.
+func (params *PoseidonParameters) Equal(other *PoseidonParameters) bool {
+	if params == nil || other == nil {
+		return params == other
+	}
+	return *params == *other
+}
+
+// ParametersID returns the ID of the Poseidon instance the parameters
+// define, as returned by Poseidon.ID. Two services whose IDs agree run the
+// same permutation.
+//
+// Panics if the parameters are ones NewPoseidon rejects.
+func (params *PoseidonParameters) ParametersID() Digest {
+	poseidon, err := NewPoseidon(params)
+	if err != nil {
+		panic(fmt.Sprintf("poseidon parameters: %v", err))
+	}
+	return poseidon.id
+}
+
+// poseidonID hashes the encoded parameters followed by the round constants,
+// round by round, and the MDS matrix, row by row. Hashing the generated
+// values rather than only the parameters makes any change to their
+// generation change the ID.
+func poseidonID(params *PoseidonParameters, roundConstants, mdsMatrix [][]field.Element) Digest {
+	input := params.Encode()
+	for _, rows := range [][][]field.Element{roundConstants, mdsMatrix} {
+		for _, row := range rows {
+			input = append(input, row...)
+		}
+	}
+	return HashVarlenDomain(PoseidonParametersDomain, input)
+}
+
+// ID returns the ID of the instance, a digest of its parameters, round
+// constants and MDS matrix. Absorb it into transcripts or envelopes to have
+// both sides of a protocol check that they run identical instances.
+func (p *Poseidon) ID() Digest {
+	return p.id
+}
+
+// Encode returns the BFieldCodec encoding of the parameters: SecurityLevel,
+// FieldSize, Width, Rate, RoundsFull, RoundsPartial and SboxPower, each as
+// a u64 as by bfieldcodec.EncodeUint64.
+//
+// The round constants and MDS matrix are not encoded: they are derived
+// deterministically from the parameters, and would make the encoding
+// hundreds of elements long. ParametersID commits to them, so exchanging
+// the encoding together with the ID detects a peer whose derivation
+// differs.
+func (params *PoseidonParameters) Encode() []field.Element {
+	encoding := make([]field.Element, 0, poseidonParametersEncodingLen)
+	for _, value := range params.fields() {
+		encoding = append(encoding, bfieldcodec.EncodeUint64(uint64(*value))...)
+	}
+	return encoding
+}
+
+// DecodePoseidonParameters decodes parameters from their encoding.
+// Returns an error if the sequence has the wrong length or a field is not a
+// canonical u64 that fits in a non-negative int.
+func DecodePoseidonParameters(sequence []field.Element) (*PoseidonParameters, error) {
+	if len(sequence) != poseidonParametersEncodingLen {
+		return nil, fmt.Errorf("poseidon parameters encoding has %d elements, want %d", len(sequence), poseidonParametersEncodingLen)
+	}
+	params := new(PoseidonParameters)
+	for i, dst := range params.fields() {
+		value, err := bfieldcodec.DecodeUint64(sequence[2*i : 2*i+2])
+		if err != nil {
+			return nil, fmt.Errorf("poseidon parameter %d: %w", i, err)
+		}
+		if value > math.MaxInt {
+			return nil, fmt.Errorf("poseidon parameter %d: %d overflows int", i, value)
+		}
+		*dst = int(value)
+	}
+	return params, nil
+}
+
+// fields returns pointers to the parameters' fields in encoding order.
+func (params *PoseidonParameters) fields() []*int {
+	return []*int{
+		&params.SecurityLevel,
+		&params.FieldSize,
+		&params.Width,
+		&params.Rate,
+		&params.RoundsFull,
+		&params.RoundsPartial,
+		&params.SboxPower,
+	}
+}
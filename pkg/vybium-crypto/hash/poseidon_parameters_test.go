@@ -0,0 +1,149 @@
+package hash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestPoseidonPresetsMatchDefaults(t *testing.T) {
+	if !PoseidonGoldilocks128().Equal(GetDefaultPoseidonParameters(128)) {
+		t.Error("PoseidonGoldilocks128 differs from the 128-bit defaults")
+	}
+	if !PoseidonGoldilocks256().Equal(GetDefaultPoseidonParameters(256)) {
+		t.Error("PoseidonGoldilocks256 differs from the 256-bit defaults")
+	}
+	if PoseidonGoldilocks128().Equal(GetDefaultPoseidonParameters(192)) {
+		t.Error("128-bit preset equals the conservative fallback")
+	}
+}
+
+func TestPoseidonParametersEqual(t *testing.T) {
+	var nilParams *PoseidonParameters
+	if !nilParams.Equal(nil) {
+		t.Error("nil parameters differ from nil")
+	}
+	if nilParams.Equal(PoseidonGoldilocks128()) || PoseidonGoldilocks128().Equal(nil) {
+		t.Error("nil parameters equal a preset")
+	}
+	if PoseidonGoldilocks128().Equal(PoseidonGoldilocks256()) {
+		t.Error("the 128- and 256-bit presets are equal")
+	}
+}
+
+func TestPoseidonParametersIDGolden(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	tests := []struct {
+		name   string
+		params *PoseidonParameters
+		want   string
+	}{
+		{"Goldilocks128", PoseidonGoldilocks128(), "409d9e6fba0a8ef03b3912b2b771930e9121d39636faf508e9ba3f529048563c0fbac33538ceee1c"},
+		{"Goldilocks256", PoseidonGoldilocks256(), "36714a77c6e6e9b9a813dbac50d3efa01ba5d4e15fa4d88c143d5d7739846df3fdecf5fd9b99b134"},
+	}
+	for _, tt := range tests {
+		if got := tt.params.ParametersID().Hex(); got != tt.want {
+			t.Errorf("%s: ID %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPoseidonParametersIDCoversEveryField(t *testing.T) {
+	// A width of 8 leaves room for a larger rate, and adding 4 keeps the
+	// S-box power coprime to P-1 and the number of full rounds even
+	widened := func() *PoseidonParameters {
+		params := PoseidonGoldilocks128()
+		params.Width = 8
+		return params
+	}
+	base := widened()
+	baseID := base.ParametersID()
+	for i := range base.fields() {
+		changed := widened()
+		*changed.fields()[i] += 4
+		if changed.ParametersID().Equal(baseID) {
+			t.Errorf("changing parameter %d leaves the ID unchanged", i)
+		}
+	}
+}
+
+func TestPoseidonIDMatchesParametersID(t *testing.T) {
+	poseidon, err := NewPoseidon(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !poseidon.ID().Equal(PoseidonGoldilocks128().ParametersID()) {
+		t.Error("default instance's ID differs from the 128-bit preset's")
+	}
+}
+
+func TestPoseidonEqualIDsHashEqually(t *testing.T) {
+	rng := rand.New(rand.NewSource(1201))
+	first, err := NewPoseidon(PoseidonGoldilocks256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewPoseidon(GetDefaultPoseidonParameters(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.ID().Equal(second.ID()) {
+		t.Fatal("instances from equal parameters have different IDs")
+	}
+	for i := 0; i < 20; i++ {
+		input := make([]field.Element, 1+rng.Intn(10))
+		for j := range input {
+			input[j] = field.New(rng.Uint64() % field.P)
+		}
+		if !first.Hash(input).Equal(second.Hash(input)) {
+			t.Fatalf("instances with equal IDs hash %v differently", input)
+		}
+	}
+}
+
+func TestPoseidonParametersEncodeRoundTrip(t *testing.T) {
+	for _, params := range []*PoseidonParameters{PoseidonGoldilocks128(), PoseidonGoldilocks256(), GetDefaultPoseidonParameters(80)} {
+		encoding := params.Encode()
+		if len(encoding) != poseidonParametersEncodingLen {
+			t.Fatalf("encoding has %d elements", len(encoding))
+		}
+		decoded, err := DecodePoseidonParameters(encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Equal(params) {
+			t.Errorf("round trip of %+v gave %+v", params, decoded)
+		}
+	}
+}
+
+func TestDecodePoseidonParametersRejectsMalformed(t *testing.T) {
+	encoding := PoseidonGoldilocks128().Encode()
+	if _, err := DecodePoseidonParameters(encoding[1:]); err == nil {
+		t.Error("short encoding accepted")
+	}
+	if _, err := DecodePoseidonParameters(append(encoding, field.Zero)); err == nil {
+		t.Error("long encoding accepted")
+	}
+
+	negative := PoseidonGoldilocks128()
+	negative.Width = -1
+	if _, err := DecodePoseidonParameters(negative.Encode()); err == nil {
+		t.Error("negative width accepted")
+	}
+
+	nonCanonical := PoseidonGoldilocks128().Encode()
+	nonCanonical[2] = field.New(1 << 32)
+	if _, err := DecodePoseidonParameters(nonCanonical); err == nil {
+		t.Error("non-canonical limb accepted")
+	}
+
+	copy(nonCanonical[2:4], bfieldcodec.EncodeUint64(4))
+	if decoded, err := DecodePoseidonParameters(nonCanonical); err != nil || decoded.Width != 4 {
+		t.Errorf("restored encoding decoded to %+v, %v", decoded, err)
+	}
+}
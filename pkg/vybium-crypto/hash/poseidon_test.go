@@ -1,6 +1,7 @@
 package hash
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -65,8 +66,9 @@ func TestPoseidonHashEmptyInput(t *testing.T) {
 	}
 
 	result := poseidon.Hash([]field.Element{})
-	if !result.IsZero() {
-		t.Error("Hash of empty input should be zero")
+	want := poseidon.poseidonPermutation(make([]field.Element, poseidon.width))[0]
+	if !result.Equal(want) {
+		t.Errorf("Hash of empty input = %v, want the permuted zero state's %v", result, want)
 	}
 }
 
@@ -204,6 +206,27 @@ func TestPoseidonSpongeAbsorbSqueeze(t *testing.T) {
 	}
 }
 
+func TestPoseidonSpongeReset(t *testing.T) {
+	sponge, err := NewPoseidonSponge(nil)
+	if err != nil {
+		t.Fatalf("Failed to create PoseidonSponge: %v", err)
+	}
+	inputs := []field.Element{field.New(1), field.New(2), field.New(3), field.New(4)}
+
+	sponge.Absorb(inputs)
+	first := sponge.Squeeze(5)
+
+	sponge.Reset()
+	sponge.Absorb(inputs)
+	second := sponge.Squeeze(5)
+
+	for i := range first {
+		if !first[i].Equal(second[i]) {
+			t.Fatalf("output %d differs after Reset", i)
+		}
+	}
+}
+
 func TestGrainLFSRInitialization(t *testing.T) {
 	params := GetDefaultPoseidonParameters(128)
 	lfsr := NewGrainLFSR(params)
@@ -251,10 +274,9 @@ func TestGrainLFSRFieldElementGeneration(t *testing.T) {
 func TestMDSMatrixGeneration(t *testing.T) {
 	for width := 3; width <= 6; width++ {
 		t.Run("width_"+string(rune('0'+width)), func(t *testing.T) {
-			matrix, err := generatePoseidonMDSMatrix(width)
-			if err != nil {
-				t.Fatalf("Failed to generate MDS matrix: %v", err)
-			}
+			params := GetDefaultPoseidonParameters(128)
+			params.Width = width
+			matrix := generatePoseidonMDSMatrix(NewGrainLFSR(params), width)
 
 			// Check dimensions
 			if len(matrix) != width {
@@ -279,10 +301,7 @@ func TestMDSMatrixGeneration(t *testing.T) {
 
 func TestRoundConstantsGeneration(t *testing.T) {
 	params := GetDefaultPoseidonParameters(128)
-	constants, err := generatePoseidonRoundConstants(params)
-	if err != nil {
-		t.Fatalf("Failed to generate round constants: %v", err)
-	}
+	constants, _ := generatePoseidonConstants(params)
 
 	totalRounds := params.RoundsFull + params.RoundsPartial
 	if len(constants) != totalRounds {
@@ -324,16 +343,11 @@ func TestPoseidonSboxOptimization(t *testing.T) {
 
 	x := field.New(7)
 
-	// S-box should compute x^5
+	// S-box should compute x^7
 	result := poseidon.sbox(x)
 
-	// Manually compute x^5
-	x2 := x.Square()
-	x4 := x2.Square()
-	x5 := x.Mul(x4)
-
-	if !result.Equal(x5) {
-		t.Error("S-box should correctly compute x^5")
+	if !result.Equal(x.ModPow(7)) {
+		t.Error("S-box should correctly compute x^7")
 	}
 }
 
@@ -378,3 +392,131 @@ func TestPoseidonConsistencyAcrossInstances(t *testing.T) {
 		t.Error("Same parameters should produce same hash")
 	}
 }
+
+// TestPoseidonKnownAnswers checks the constants and permutation against
+// vectors computed by following the reference parameter script
+// generate_parameters_grain.sage and the reference permutation: the first
+// round constants, the first MDS row, and the permutation of the zero state
+// and of the state 0, 1, ..., t-1.
+func TestPoseidonKnownAnswers(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    *PoseidonParameters
+		constants []uint64
+		mdsRow    []uint64
+		zero      []uint64
+		iota      []uint64
+	}{
+		{
+			name:      "Goldilocks128",
+			params:    PoseidonGoldilocks128(),
+			constants: []uint64{0x5dec6438271558d7, 0x9ac56bb8e7ff0860, 0xe8dd68b5c63db548, 0x43321beb7b0cfbb9},
+			mdsRow:    []uint64{0xf5baa715c3dbf88d, 0x4c31ef76b4104784, 0x7934d11201a7776e, 0xf622c1e2722aac02},
+			zero:      []uint64{0xfe0367bda3c1d107, 0xbd707b9ba545a00c, 0x2240facc4bceb77f, 0xbed7531894d40e7f},
+			iota:      []uint64{0xaeab9b5a56386ad7, 0xf064c9dc80356dbf, 0xcae1f7ad28e185a2, 0xd61aa28ab010912f},
+		},
+		{
+			name: "Goldilocks width 8",
+			params: &PoseidonParameters{
+				SecurityLevel: 128,
+				FieldSize:     64,
+				Width:         8,
+				Rate:          4,
+				RoundsFull:    8,
+				RoundsPartial: 22,
+				SboxPower:     7,
+			},
+			constants: []uint64{0xdd5743e7f2a5a5d9, 0xcb3a864e58ada44b, 0xffa2449ed32f8cdc, 0x42025f65d6bd13ee},
+			mdsRow:    []uint64{0x4c8ce0bc74cea694, 0x2c019a12106f3fb6, 0xe8600ac82388b53f, 0x573a0dfbdb8eb600, 0x296540e91bfc28c9, 0x51946f186c676017, 0x82542bb49d7435a7, 0x792f3f50e470f798},
+			zero:      []uint64{0x9bf6a957cde16af1, 0x193acc9cd1bd1ca5, 0xe2e8e52cde3d9d8d, 0x5bccaba053401fc4, 0xe9c3ead6c3a61e31, 0xdc3d3ac0c7501a55, 0x3c724a8e9d79430a, 0x47b0970667350919},
+			iota:      []uint64{0xcfa28d90e32f6a78, 0xd321fef6a371a223, 0xcb2ff6226ffdda6b, 0x6ecd417d97144095, 0x65dde35cd483f523, 0xba7bd4dcf9065358, 0xb56b1ee990560563, 0xb6c434b6a6d4b08d},
+		},
+	}
+	elements := func(values []uint64) []field.Element {
+		result := make([]field.Element, len(values))
+		for i, value := range values {
+			result[i] = field.New(value)
+		}
+		return result
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poseidon, err := NewPoseidon(tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range elements(tt.constants) {
+				if got := poseidon.roundConstants[0][i]; got != want {
+					t.Errorf("round constant %d = %#x, want %#x", i, got.Value(), want.Value())
+				}
+			}
+			for j, want := range elements(tt.mdsRow) {
+				if got := poseidon.mdsMatrix[0][j]; got != want {
+					t.Errorf("MDS entry (0, %d) = %#x, want %#x", j, got.Value(), want.Value())
+				}
+			}
+
+			iota := make([]field.Element, tt.params.Width)
+			for i := range iota {
+				iota[i] = field.New(uint64(i))
+			}
+			for name, state := range map[string][]field.Element{
+				"zero": make([]field.Element, tt.params.Width),
+				"iota": iota,
+			} {
+				want := elements(tt.zero)
+				if name == "iota" {
+					want = elements(tt.iota)
+				}
+				got := poseidon.poseidonPermutation(state)
+				for i := range want {
+					if got[i] != want[i] {
+						t.Errorf("permutation of %s state: element %d = %#x, want %#x", name, i, got[i].Value(), want[i].Value())
+					}
+				}
+			}
+
+			if got, want := poseidon.Hash(nil), elements(tt.zero)[0]; got != want {
+				t.Errorf("Hash of empty input = %#x, want %#x", got.Value(), want.Value())
+			}
+		})
+	}
+}
+
+func TestPoseidonHashSeparatesLengths(t *testing.T) {
+	poseidon, err := NewPoseidon(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[field.Element]int)
+	for n := 0; n <= 2*poseidon.rate+1; n++ {
+		digest := poseidon.Hash(make([]field.Element, n))
+		if m, ok := seen[digest]; ok {
+			t.Errorf("%d and %d zeros hash equally", m, n)
+		}
+		seen[digest] = n
+	}
+}
+
+func TestNewPoseidonRejectsInvalidParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*PoseidonParameters)
+		want   string
+	}{
+		{"S-box power 5 divides P-1", func(p *PoseidonParameters) { p.SboxPower = 5 }, "S-box power 5"},
+		{"S-box power 3 divides P-1", func(p *PoseidonParameters) { p.SboxPower = 3 }, "S-box power 3"},
+		{"S-box power 1", func(p *PoseidonParameters) { p.SboxPower = 1 }, "S-box power 1"},
+		{"no capacity", func(p *PoseidonParameters) { p.Rate = p.Width }, "rate"},
+		{"zero rate", func(p *PoseidonParameters) { p.Rate = 0 }, "rate"},
+		{"odd full rounds", func(p *PoseidonParameters) { p.RoundsFull = 7 }, "rounds"},
+	}
+	for _, tt := range tests {
+		params := PoseidonGoldilocks128()
+		tt.modify(params)
+		_, err := NewPoseidon(params)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -7,20 +7,28 @@
 package hash
 
 import (
+	"math/bits"
+
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/params"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
 )
 
-// Tip5 constants defining the hash function parameters
+// Tip5 constants defining the hash function parameters. StateSize, Capacity
+// and Rate are those of package params.
 const (
-	StateSize         = 16
+	StateSize         = params.SpongeStateSize
 	NumSplitAndLookup = 4
 	Log2StateSize     = 4
-	Capacity          = 6
-	Rate              = 10
-	NumRounds         = 5
+	Capacity          = params.SpongeCapacity
+	Rate              = params.SpongeRate
 )
 
+// numRoundsFull is the number of rounds of the standard Tip5 permutation.
+// NumRounds, the number of rounds actually applied, is selected by build
+// tag: it equals numRoundsFull unless built with the tip5weak tag.
+const numRoundsFull = params.Tip5NumRounds
+
 // Domain differentiates between modes of hashing.
 type Domain int
 
@@ -31,6 +39,17 @@ const (
 	FixedLength
 )
 
+func (d Domain) String() string {
+	switch d {
+	case VariableLength:
+		return "VariableLength"
+	case FixedLength:
+		return "FixedLength"
+	default:
+		return "Unknown"
+	}
+}
+
 // Tip5 represents the Tip5 hash function state.
 // The state consists of 16 field elements that are permuted during hashing.
 type Tip5 struct {
@@ -56,8 +75,9 @@ var LookupTable = [256]uint8{
 }
 
 // RoundConstants are the round constants used in Tip5 permutation.
+// Round i uses RoundConstants[i*StateSize : (i+1)*StateSize].
 // Production implementation.
-var RoundConstants = [NumRounds * StateSize]field.Element{
+var RoundConstants = [numRoundsFull * StateSize]field.Element{
 	field.New(13630775303355457758),
 	field.New(16896927574093233874),
 	field.New(10379449653650130495),
@@ -140,6 +160,13 @@ var RoundConstants = [NumRounds * StateSize]field.Element{
 	field.New(6024642864597845108),
 }
 
+// MdsMatrixFirstColumn is the first column of the circulant MDS matrix used in
+// the Tip5 permutation, as published in the Tip5 paper.
+var MdsMatrixFirstColumn = [StateSize]uint64{
+	61402, 1108, 28750, 33823, 7454, 43244, 53865, 12034,
+	56951, 27521, 41351, 40901, 12021, 59689, 26798, 17845,
+}
+
 // New creates a new Tip5 instance with the specified domain.
 // Production implementation.
 func New(domain Domain) *Tip5 {
@@ -177,7 +204,7 @@ func (t *Tip5) Permutation() {
 // Production implementation.
 func (t *Tip5) round(roundIndex int) {
 	t.sboxLayer()
-	t.mdsGenerated()
+	t.mdsLayer()
 
 	// Add round constants
 	for i := 0; i < StateSize; i++ {
@@ -204,16 +231,24 @@ func (t *Tip5) sboxLayer() {
 // splitAndLookup applies the split-and-lookup operation.
 // Production implementation.
 func splitAndLookup(element *field.Element) {
-	// Get raw bytes in Montgomery form
-	bytes := element.ToBytes()
-
-	// Apply lookup table to each byte
-	for i := 0; i < 8; i++ {
-		bytes[i] = LookupTable[bytes[i]]
+	// Apply the lookup table to each little-endian byte of the raw
+	// Montgomery-form value; the result stays in Montgomery form
+	raw := element.RawValue()
+	var result uint64
+	for shift := 0; shift < 64; shift += 8 {
+		result |= uint64(LookupTable[uint8(raw>>shift)]) << shift
 	}
 
-	// Create element from modified bytes (still in Montgomery form)
-	*element = field.FromBytes(bytes)
+	*element = field.NewFromRaw(result)
+}
+
+// mdsLayer applies the MDS matrix using the implementation selected at build time.
+func (t *Tip5) mdsLayer() {
+	if useMdsReference {
+		mdsReference(&t.state)
+		return
+	}
+	t.mdsGenerated()
 }
 
 // mdsGenerated applies the MDS matrix using the optimized generated function.
@@ -234,184 +269,83 @@ func (t *Tip5) mdsGenerated() {
 
 	// Recombine and reduce
 	for r := 0; r < StateSize; r++ {
-		s := (uint128(lo[r]) >> 4) + (uint128(hi[r]) << 28)
+		t.state[r] = mdsRecombine(lo[r], hi[r])
+	}
+}
 
-		sHi := uint64(s >> 32) // Fixed: shift by 32 instead of 64
-		sLo := uint64(s)
+// mdsRecombine recombines the outputs of the generated function applied to the
+// low and high 32-bit halves of a state element and reduces the result.
+//
+// The halves' products lo and hi are below 2^56, so s = lo/16 + hi*2^28 needs
+// up to 84 bits; it is accumulated in two 64-bit limbs and reduced with
+// 2^64 ≡ 2^32 - 1 (mod P).
+func mdsRecombine(lo, hi uint64) field.Element {
+	sLo, carry := bits.Add64(lo>>4, hi<<28, 0)
+	sHi := hi>>36 + carry
+
+	// Compute result with overflow handling
+	res := sLo + sHi*0xFFFFFFFF
+	over := res < sLo // overflow check
+
+	if over {
+		res += 0xFFFFFFFF
+	}
 
-		// Compute result with overflow handling
-		res := sLo + sHi*0xFFFFFFFF
-		over := res < sLo // overflow check
+	return field.NewFromRaw(res)
+}
 
-		if over {
-			res += 0xFFFFFFFF
-		}
+// generatedFunction computes 16·M·input with wrapping uint64 arithmetic,
+// where M is the circulant MDS matrix with first column MdsMatrixFirstColumn.
+// The inputs are 32-bit halves of state elements, so the exact result is below
+// 2^56 and the wrapping intermediate values cancel out.
+//
+// The cyclic convolution by M is split recursively with the Chinese remainder
+// theorem: modulo X^16-1 = (X^8-1)(X^8+1), the X^8-1 part is again a cyclic
+// convolution of half the size and the X^8+1 part a negacyclic one. Each
+// split halves the result, which is absorbed by the factor 16 pre-multiplied
+// into the constants (see mdsNegacyclic8 and friends), so no division is needed.
+func generatedFunction(input [StateSize]uint64) [StateSize]uint64 {
+	var sum8, diff8 [8]uint64
+	for i := 0; i < 8; i++ {
+		sum8[i] = input[i] + input[i+8]
+		diff8[i] = input[i] - input[i+8]
+	}
 
-		t.state[r] = field.NewFromRaw(res)
+	var sum4, diff4 [4]uint64
+	for i := 0; i < 4; i++ {
+		sum4[i] = sum8[i] + sum8[i+4]
+		diff4[i] = sum8[i] - sum8[i+4]
 	}
-}
 
-// uint128 is a simple 128-bit unsigned integer for intermediate calculations
-type uint128 uint64 // Simplified for shift operations
+	sum2 := [2]uint64{sum4[0] + sum4[2], sum4[1] + sum4[3]}
+	diff2 := [2]uint64{sum4[0] - sum4[2], sum4[1] - sum4[3]}
 
-// generatedFunction is the optimized MDS matrix multiplication.
-// This is a direct port of twenty-first's Tip5::generated_function()
-//
-// This function was automatically generated to optimize the MDS matrix multiplication.
-// It uses a clever factorization to reduce the number of operations.
-func generatedFunction(input [StateSize]uint64) [StateSize]uint64 {
-	// First layer of additions
-	node34 := input[0] + input[8]
-	node38 := input[4] + input[12]
-	node36 := input[2] + input[10]
-	node40 := input[6] + input[14]
-	node35 := input[1] + input[9]
-	node39 := input[5] + input[13]
-	node37 := input[3] + input[11]
-	node41 := input[7] + input[15]
-
-	// Second layer
-	node50 := node34 + node38
-	node52 := node36 + node40
-	node51 := node35 + node39
-	node53 := node37 + node41
-
-	// Subtractions
-	node160 := input[0] - input[8]
-	node161 := input[1] - input[9]
-	node165 := input[5] - input[13]
-	node163 := input[3] - input[11]
-	node167 := input[7] - input[15]
-	node162 := input[2] - input[10]
-	node166 := input[6] - input[14]
-	node164 := input[4] - input[12]
-
-	// Third layer
-	node58 := node50 + node52
-	node59 := node51 + node53
-	node90 := node34 - node38
-	node91 := node35 - node39
-	node93 := node37 - node41
-	node92 := node36 - node40
-
-	// Multiplications and combinations
-	node64 := (node58 + node59) * 524757
-	node67 := (node58 - node59) * 52427
-	node71 := node50 - node52
-	node72 := node51 - node53
-
-	node177 := node161 + node165
-	node179 := node163 + node167
-	node178 := node162 + node166
-	node176 := node160 + node164
-
-	node69 := node64 + node67
-	node397 := node71*18446744073709525744 - node72*53918
-	node1857 := node90 * 395512
-	node99 := node91 + node93
-	node1865 := node91 * 18446744073709254400
-	node1869 := node93 * 179380
-	node1873 := node92 * 18446744073709509368
-	node1879 := node160 * 35608
-	node185 := node161 + node163
-	node1915 := node161 * 18446744073709340312
-	node1921 := node163 * 18446744073709494992
-	node1927 := node162 * 18446744073709450808
-	node228 := node165 + node167
-	node1939 := node165 * 18446744073709420056
-	node1945 := node167 * 18446744073709505128
-	node1951 := node166 * 216536
-	node1957 := node164 * 18446744073709515080
-
-	node70 := node64 - node67
-	node702 := node71*53918 + node72*18446744073709525744
-	node1961 := node90 * 18446744073709254400
-	node1963 := node91 * 395512
-	node1965 := node92 * 179380
-	node1967 := node93 * 18446744073709509368
-	node1970 := node160 * 18446744073709340312
-	node1973 := node161 * 35608
-	node1982 := node162 * 18446744073709494992
-	node1985 := node163 * 18446744073709450808
-	node1988 := node166 * 18446744073709505128
-	node1991 := node167 * 216536
-	node1994 := node164 * 18446744073709420056
-	node1997 := node165 * 18446744073709515080
-
-	node98 := node90 + node92
-	node184 := node160 + node162
-	node227 := node164 + node166
-
-	node86 := node69 + node397
-	node403 := node1857 - (node99*18446744073709433780 - node1865 - node1869 + node1873)
-	node271 := node177 + node179
-	node1891 := node177 * 18446744073709208752
-	node1897 := node179 * 18446744073709448504
-	node1903 := node178 * 115728
-	node1909 := node185 * 18446744073709283688
-	node1933 := node228 * 18446744073709373568
-
-	node88 := node70 + node702
-	node708 := node1961 + node1963 - (node1965 + node1967)
-	node1976 := node178 * 18446744073709448504
-	node1979 := node179 * 115728
-
-	node87 := node69 - node397
-	node897 := node1865 + node98*353264 - node1857 - node1873 - node1869
-	node2007 := node184 * 18446744073709486416
-	node2013 := node227 * 180000
-
-	node89 := node70 - node702
-	node1077 := node98*18446744073709433780 + node99*353264 - (node1961 + node1963) - (node1965 + node1967)
-	node2020 := node184 * 18446744073709283688
-	node2023 := node185 * 18446744073709486416
-	node2026 := node227 * 18446744073709373568
-	node2029 := node228 * 180000
-	node2035 := node176 * 18446744073709550688
-	node2038 := node176 * 18446744073709208752
-	node2041 := node177 * 18446744073709550688
-
-	node270 := node176 + node178
-
-	node152 := node86 + node403
-	node412 := node1879 + node185*18446744073709433780 - node1915 - node1921 - node1927
-	node1237 := node2035 - node1891 - node1897 - node1903 - node1909
-
-	node154 := node88 + node708
-	node717 := node1921 + node2007 - node1970 - node1973 - node1982 - node1985
-	node1375 := node1927 + node2013 - node1994 - node1997 - node1988 - node1991
-
-	node156 := node87 + node897
-	node906 := node1873 + node1909 + node2020 - node1879 - node1915 - node1921 - node1927
-	node1492 := node1951 + node1933 + node2026 - node1939 - node1945 - node1957 - node1997
-
-	node158 := node89 + node1077
-	node1086 := node1961 + node1963 + node1979 + node2023 - node1973 - node1982 - node1985 - node1976
-	node1657 := node1994 + node1997 + node1991 + node2029 - node1939 - node1945 - node1957 - node1988
-
-	node153 := node270*114800 + node271*18446744073709433780 - node2038 - node2041 - node1976 - node1979 - (node2020 + node2023 - node1970 - node1973 - node1982 - node1985) - (node2026 + node2029 - node1994 - node1997 - node1988 - node1991)
-	node155 := node270*18446744073709433780 + node271*114800 - node1891 - node1897 - node1903 - (node1879 + node1909 + node2020 - node1915 - node1921 - node1927) - (node1939 + node1933 + node2026 - node1951 - node1957 - node1988 - node1991)
-	node157 := node1879 + node270*353264 - node2035 - node2038 - node2041 - node1976 - node1979 - (node1915 + node1909 + node2020 + node2023 - node1927 - node1982 - node1985 - node1973) - (node1939 + node1933 + node2026 + node2029 - node1951 - node1957 - node1988 - node1991)
-	node159 := node1939 + node271*114800 - node2038 - node2041 - node1976 - node1979 - (node2020 + node2023 - node1970 - node1973 - node1982 - node1985) - (node2026 + node2029 - node1994 - node1997 - node1988 - node1991)
-
-	return [StateSize]uint64{
-		node152 + node412,
-		node154 + node717,
-		node156 + node906,
-		node158 + node1086,
-		node153 + node1237,
-		node155 + node1375,
-		node157 + node1492,
-		node159 + node1657,
-		node152 - node412,
-		node154 - node717,
-		node156 - node906,
-		node158 - node1086,
-		node153 - node1237,
-		node155 - node1375,
-		node157 - node1492,
-		node159 - node1657,
+	// Size 2 cyclic part: two scalar products
+	cyc1 := (sum2[0] + sum2[1]) * mdsCyclic1
+	neg1 := (sum2[0] - sum2[1]) * mdsNegacyclic1
+	cyc2 := [2]uint64{cyc1 + neg1, cyc1 - neg1}
+
+	neg2 := negacyclicConvolve2(&diff2)
+	var cyc4 [4]uint64
+	for i := 0; i < 2; i++ {
+		cyc4[i] = cyc2[i] + neg2[i]
+		cyc4[i+2] = cyc2[i] - neg2[i]
 	}
+
+	neg4 := negacyclicConvolve4(&diff4)
+	var cyc8 [8]uint64
+	for i := 0; i < 4; i++ {
+		cyc8[i] = cyc4[i] + neg4[i]
+		cyc8[i+4] = cyc4[i] - neg4[i]
+	}
+
+	neg8 := negacyclicConvolve8(&diff8)
+	var output [StateSize]uint64
+	for i := 0; i < 8; i++ {
+		output[i] = cyc8[i] + neg8[i]
+		output[i+8] = cyc8[i] - neg8[i]
+	}
+	return output
 }
 
 // Hash10 hashes exactly 10 BFieldElements (one rate's worth).
@@ -432,10 +366,7 @@ func Hash10(input [Rate]field.Element) [DigestLen]field.Element {
 // HashPair hashes two digests together.
 // Production implementation.
 func HashPair(left, right [DigestLen]field.Element) [DigestLen]field.Element {
-	sponge := New(FixedLength)
-	copy(sponge.state[:DigestLen], left[:])
-	copy(sponge.state[DigestLen:2*DigestLen], right[:])
-
+	sponge := newHashPairSponge(left, right)
 	sponge.Permutation()
 
 	var digest [DigestLen]field.Element
@@ -443,7 +374,19 @@ func HashPair(left, right [DigestLen]field.Element) [DigestLen]field.Element {
 	return digest
 }
 
+// newHashPairSponge returns the state HashPair permutes: the FixedLength
+// domain, i.e. capacity all ones, with left in state[0..5) and right in
+// state[5..10). Address derivation depends on this layout.
+func newHashPairSponge(left, right [DigestLen]field.Element) *Tip5 {
+	sponge := New(FixedLength)
+	copy(sponge.state[:DigestLen], left[:])
+	copy(sponge.state[DigestLen:2*DigestLen], right[:])
+	return sponge
+}
+
 // HashVarlen hashes a variable-length sequence of BFieldElements.
+// The input length is not bounded; callers hashing input of untrusted length
+// should check it first, e.g. with sponge.ValidateSpongeInput.
 // Production implementation.
 func HashVarlen(input []field.Element) [DigestLen]field.Element {
 	sponge := Init()
@@ -454,18 +397,26 @@ func HashVarlen(input []field.Element) [DigestLen]field.Element {
 	return digest
 }
 
+// Tip5PermuteState applies the Tip5 permutation to a full state, for
+// sponges that keep their own state.
+func Tip5PermuteState(state [StateSize]field.Element) [StateSize]field.Element {
+	tip5 := Tip5{state: state}
+	tip5.Permutation()
+	return tip5.state
+}
+
 // Tip5Permutation applies the Tip5 permutation to a 5-element state.
-func Tip5Permutation(state [5]field.Element) [5]field.Element {
+func Tip5Permutation(state [DigestLen]field.Element) [DigestLen]field.Element {
 	tip5 := New(VariableLength)
 	// Copy state to tip5 internal state
-	for i := 0; i < 5; i++ {
+	for i := 0; i < DigestLen; i++ {
 		tip5.state[i] = state[i]
 	}
 	// Apply permutation
 	tip5.Permutation()
 	// Return the permuted state
-	var result [5]field.Element
-	for i := 0; i < 5; i++ {
+	var result [DigestLen]field.Element
+	for i := 0; i < DigestLen; i++ {
 		result[i] = tip5.state[i]
 	}
 	return result
@@ -577,34 +528,20 @@ func (t *Tip5) SampleIndices(upperBound uint32, numIndices int) []uint32 {
 
 // SampleScalars produces numElements random XFieldElement values.
 //
-// If numElements is not divisible by RATE, spill the remaining elements of the last squeeze.
+// The squeezed elements are packed into XFieldElements as by
+// DigestsToXFieldElements. If numElements is not divisible by RATE, spill
+// the remaining elements of the last squeeze.
 //
 // Production implementation.
 func (t *Tip5) SampleScalars(numElements int) ([]xfield.XFieldElement, error) {
-	// Import xfield here to avoid circular dependency
-	// We'll need to import it at the top of the file
-	const extensionDegree = 3
-
-	numSqueezes := (numElements*extensionDegree + Rate - 1) / Rate // Ceiling division
+	numSqueezes := (numElements*xfield.ExtensionDegree + Rate - 1) / Rate // Ceiling division
+	const digestsPerSqueeze = Rate / DigestLen
 
-	// Collect all squeezed elements
-	allElements := make([]field.Element, 0, numSqueezes*Rate)
+	squeezed := make([]Digest, numSqueezes*digestsPerSqueeze)
 	for i := 0; i < numSqueezes; i++ {
-		squeezed := t.Squeeze()
-		allElements = append(allElements, squeezed[:]...)
-	}
-
-	// Group into XFieldElements (3 elements each)
-	scalars := make([]xfield.XFieldElement, 0, numElements)
-	for i := 0; i < numElements && i*extensionDegree+extensionDegree <= len(allElements); i++ {
-		start := i * extensionDegree
-		coeffs := [extensionDegree]field.Element{
-			allElements[start],
-			allElements[start+1],
-			allElements[start+2],
-		}
-		scalars = append(scalars, xfield.New(coeffs))
+		block := t.Squeeze()
+		ElementsToDigestsInto(squeezed[i*digestsPerSqueeze:(i+1)*digestsPerSqueeze], block[:])
 	}
 
-	return scalars, nil
+	return DigestsToXFieldElements(squeezed)[:numElements:numElements], nil
 }
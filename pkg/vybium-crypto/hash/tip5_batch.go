@@ -0,0 +1,239 @@
+package hash
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// BatchWidth is the number of independent Tip5 states processed by Permute4.
+const BatchWidth = 4
+
+// permute4Impl is the backend used by Permute4. It defaults to the pure-Go
+// interleaved implementation; an architecture-specific backend can replace
+// it at init time as long as it is bit-identical to four scalar permutations.
+var permute4Impl = permute4Generic
+
+// Permute4 applies the Tip5 permutation to four independent states.
+// The result is identical to calling Permutation on each state separately,
+// but the operations of the four states are interleaved so that the
+// multiplication latencies of one state are hidden behind the others.
+func Permute4(states *[BatchWidth][StateSize]field.Element) {
+	permute4Impl(states)
+}
+
+// HashPairs4 computes four independent HashPair compressions at once.
+// The result is bit-identical to
+//
+//	[4]Digest{HashPair(left[0], right[0]), ..., HashPair(left[3], right[3])}
+func HashPairs4(left, right [BatchWidth]Digest) [BatchWidth]Digest {
+	var states [BatchWidth][StateSize]field.Element
+	for lane := 0; lane < BatchWidth; lane++ {
+		copy(states[lane][:DigestLen], left[lane][:])
+		copy(states[lane][DigestLen:2*DigestLen], right[lane][:])
+		// Fixed-length domain: capacity is all ones
+		for i := Rate; i < StateSize; i++ {
+			states[lane][i] = field.One
+		}
+	}
+
+	Permute4(&states)
+
+	var digests [BatchWidth]Digest
+	for lane := 0; lane < BatchWidth; lane++ {
+		copy(digests[lane][:], states[lane][:DigestLen])
+	}
+	return digests
+}
+
+// HashPairsInto computes out[i] = HashPair(left[i], right[i]) for every i,
+// processing groups of four with HashPairs4 and the remainder with the
+// scalar HashPair. All three slices must have the same length.
+func HashPairsInto(out, left, right []Digest) {
+	if len(left) != len(out) || len(right) != len(out) {
+		panic("HashPairsInto requires slices of equal length")
+	}
+
+	i := 0
+	for ; i+BatchWidth <= len(out); i += BatchWidth {
+		var l, r [BatchWidth]Digest
+		copy(l[:], left[i:i+BatchWidth])
+		copy(r[:], right[i:i+BatchWidth])
+		digests := HashPairs4(l, r)
+		copy(out[i:i+BatchWidth], digests[:])
+	}
+	for ; i < len(out); i++ {
+		out[i] = HashPair(left[i], right[i])
+	}
+}
+
+// permute4Generic is the pure-Go interleaved implementation of Permute4.
+//
+// The four states are held as raw Montgomery words, element-major, so that
+// every step of a round (S-box, MDS layer, round constants) advances all four
+// lanes before the next step starts. The MDS layer works on the eight columns
+// of 32-bit halves, four low and four high, which are independent and so
+// overlap in the processor. Builds with the tip5_mds_reference tag run the
+// scalar permutation on each lane instead.
+func permute4Generic(states *[BatchWidth][StateSize]field.Element) {
+	if useMdsReference {
+		for lane := range states {
+			tip5 := Tip5{state: states[lane]}
+			tip5.Permutation()
+			states[lane] = tip5.state
+		}
+		return
+	}
+
+	lookupPairsOnce.Do(buildLookupPairs)
+	lookup := lookupPairs
+
+	var x [StateSize][BatchWidth]uint64
+	for i := 0; i < StateSize; i++ {
+		for lane := 0; lane < BatchWidth; lane++ {
+			x[i][lane] = states[lane][i].RawValue()
+		}
+	}
+
+	// The S-box layer writes the 32-bit halves of its output to the columns
+	// of the MDS layer: columns[lane] holds the low halves of a lane and
+	// columns[BatchWidth+lane] the high halves. Every round overwrites all
+	// of them.
+	var columns [2 * BatchWidth][StateSize]uint64
+	for round := 0; round < NumRounds; round++ {
+		for i := 0; i < NumSplitAndLookup; i++ {
+			for lane := 0; lane < BatchWidth; lane++ {
+				raw := x[i][lane]
+				y := uint64(lookup[uint16(raw)]) | uint64(lookup[uint16(raw>>16)])<<16 |
+					uint64(lookup[uint16(raw>>32)])<<32 | uint64(lookup[uint16(raw>>48)])<<48
+				columns[lane][i] = y & 0xFFFFFFFF
+				columns[BatchWidth+lane][i] = y >> 32
+			}
+		}
+		for i := NumSplitAndLookup; i < StateSize; i++ {
+			// Power map (x^7), with the four multiplication chains interleaved
+			x0, x1, x2, x3 := x[i][0], x[i][1], x[i][2], x[i][3]
+			sq0, sq1, sq2, sq3 := mulMontgomery(x0, x0), mulMontgomery(x1, x1), mulMontgomery(x2, x2), mulMontgomery(x3, x3)
+			cu0, cu1, cu2, cu3 := mulMontgomery(x0, sq0), mulMontgomery(x1, sq1), mulMontgomery(x2, sq2), mulMontgomery(x3, sq3)
+			qu0, qu1, qu2, qu3 := mulMontgomery(sq0, sq0), mulMontgomery(sq1, sq1), mulMontgomery(sq2, sq2), mulMontgomery(sq3, sq3)
+			y0, y1, y2, y3 := mulMontgomery(cu0, qu0), mulMontgomery(cu1, qu1), mulMontgomery(cu2, qu2), mulMontgomery(cu3, qu3)
+			columns[0][i], columns[BatchWidth][i] = y0&0xFFFFFFFF, y0>>32
+			columns[1][i], columns[BatchWidth+1][i] = y1&0xFFFFFFFF, y1>>32
+			columns[2][i], columns[BatchWidth+2][i] = y2&0xFFFFFFFF, y2>>32
+			columns[3][i], columns[BatchWidth+3][i] = y3&0xFFFFFFFF, y3>>32
+		}
+
+		for c := range columns {
+			mdsColumn(&columns[c])
+		}
+
+		// Recombine the halves and add the round constants
+		constants := (*[StateSize]field.Element)(RoundConstants[round*StateSize:])
+		for i, rc := range constants {
+			x[i][0] = mdsRecombine(columns[0][i], columns[BatchWidth][i]).Add(rc).RawValue()
+			x[i][1] = mdsRecombine(columns[1][i], columns[BatchWidth+1][i]).Add(rc).RawValue()
+			x[i][2] = mdsRecombine(columns[2][i], columns[BatchWidth+2][i]).Add(rc).RawValue()
+			x[i][3] = mdsRecombine(columns[3][i], columns[BatchWidth+3][i]).Add(rc).RawValue()
+		}
+	}
+
+	for i := 0; i < StateSize; i++ {
+		for lane := 0; lane < BatchWidth; lane++ {
+			states[lane][i] = field.NewFromRaw(x[i][lane])
+		}
+	}
+}
+
+// lookupPairs applies LookupTable to both bytes of a 16-bit word, so that the
+// split-and-lookup of an element takes four table reads instead of eight.
+// The 128 KiB table is built on the first call to Permute4.
+var (
+	lookupPairs     *[1 << 16]uint16
+	lookupPairsOnce sync.Once
+)
+
+func buildLookupPairs() {
+	table := new([1 << 16]uint16)
+	for i := range table {
+		table[i] = uint16(LookupTable[uint8(i)]) | uint16(LookupTable[uint8(i>>8)])<<8
+	}
+	lookupPairs = table
+}
+
+// mulMontgomery returns the Montgomery product of two raw field elements,
+// exactly as field.Element.Mul computes it. Unlike Mul it is inlined, which
+// keeps the interleaved power map free of calls.
+func mulMontgomery(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	t, overflow := bits.Add64(lo, lo<<32, 0)
+	u := t - (t >> 32) - overflow
+	r, borrow := bits.Sub64(hi, u, 0)
+	return r - (borrow<<32 - borrow)
+}
+
+// mdsColumn computes generatedFunction of x in place. Every intermediate
+// value is a local variable rather than an array element, which lets the
+// compiler keep the column in registers; the batched permutation applies it
+// to each of its eight columns.
+func mdsColumn(x *[StateSize]uint64) {
+	// Fold to size 8: sums for the cyclic part, differences for the
+	// negacyclic one
+	s0, d0 := x[0]+x[8], x[0]-x[8]
+	s1, d1 := x[1]+x[9], x[1]-x[9]
+	s2, d2 := x[2]+x[10], x[2]-x[10]
+	s3, d3 := x[3]+x[11], x[3]-x[11]
+	s4, d4 := x[4]+x[12], x[4]-x[12]
+	s5, d5 := x[5]+x[13], x[5]-x[13]
+	s6, d6 := x[6]+x[14], x[6]-x[14]
+	s7, d7 := x[7]+x[15], x[7]-x[15]
+
+	// Fold to size 4 and 2
+	t0, e0 := s0+s4, s0-s4
+	t1, e1 := s1+s5, s1-s5
+	t2, e2 := s2+s6, s2-s6
+	t3, e3 := s3+s7, s3-s7
+	u0, f0 := t0+t2, t0-t2
+	u1, f1 := t1+t3, t1-t3
+
+	// Size 2 cyclic part, then unfold back up to size 8
+	cyc1 := (u0 + u1) * mdsCyclic1
+	neg1 := (u0 - u1) * mdsNegacyclic1
+	c0, c1 := cyc1+neg1, cyc1-neg1
+
+	n0 := mdsNegacyclic2[0][0]*f0 + mdsNegacyclic2[0][1]*f1
+	n1 := mdsNegacyclic2[1][0]*f0 + mdsNegacyclic2[1][1]*f1
+	c0, c1, c2, c3 := c0+n0, c1+n1, c0-n0, c1-n1
+
+	n0 = dot4(&mdsNegacyclic4[0], e0, e1, e2, e3)
+	n1 = dot4(&mdsNegacyclic4[1], e0, e1, e2, e3)
+	n2 := dot4(&mdsNegacyclic4[2], e0, e1, e2, e3)
+	n3 := dot4(&mdsNegacyclic4[3], e0, e1, e2, e3)
+	c0, c1, c2, c3, c4, c5, c6, c7 := c0+n0, c1+n1, c2+n2, c3+n3, c0-n0, c1-n1, c2-n2, c3-n3
+
+	n0 = dot8(&mdsNegacyclic8[0], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[0], x[8] = c0+n0, c0-n0
+	n0 = dot8(&mdsNegacyclic8[1], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[1], x[9] = c1+n0, c1-n0
+	n0 = dot8(&mdsNegacyclic8[2], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[2], x[10] = c2+n0, c2-n0
+	n0 = dot8(&mdsNegacyclic8[3], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[3], x[11] = c3+n0, c3-n0
+	n0 = dot8(&mdsNegacyclic8[4], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[4], x[12] = c4+n0, c4-n0
+	n0 = dot8(&mdsNegacyclic8[5], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[5], x[13] = c5+n0, c5-n0
+	n0 = dot8(&mdsNegacyclic8[6], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[6], x[14] = c6+n0, c6-n0
+	n0 = dot8(&mdsNegacyclic8[7], d0, d1, d2, d3, d4, d5, d6, d7)
+	x[7], x[15] = c7+n0, c7-n0
+}
+
+func dot4(row *[4]uint64, x0, x1, x2, x3 uint64) uint64 {
+	return row[0]*x0 + row[1]*x1 + row[2]*x2 + row[3]*x3
+}
+
+func dot8(row *[8]uint64, x0, x1, x2, x3, x4, x5, x6, x7 uint64) uint64 {
+	return row[0]*x0 + row[1]*x1 + row[2]*x2 + row[3]*x3 +
+		row[4]*x4 + row[5]*x5 + row[6]*x6 + row[7]*x7
+}
@@ -0,0 +1,155 @@
+package hash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func batchTestDigest(seed uint64) Digest {
+	var d Digest
+	for i := 0; i < DigestLen; i++ {
+		d[i] = field.New(seed*0x9E3779B97F4A7C15 + uint64(i)*0x517CC1B727220A95)
+	}
+	return d
+}
+
+func TestPermute4MatchesScalar(t *testing.T) {
+	var states [BatchWidth][StateSize]field.Element
+	for lane := 0; lane < BatchWidth; lane++ {
+		for i := 0; i < StateSize; i++ {
+			states[lane][i] = field.New(uint64(lane*StateSize+i) * 0xDEADBEEFCAFEBABE)
+		}
+	}
+	// Include extreme values
+	states[3][0] = field.Max
+	states[3][StateSize-1] = field.Max
+
+	expected := states
+	for lane := 0; lane < BatchWidth; lane++ {
+		tip5 := &Tip5{state: expected[lane]}
+		tip5.Permutation()
+		expected[lane] = tip5.state
+	}
+
+	Permute4(&states)
+
+	for lane := 0; lane < BatchWidth; lane++ {
+		for i := 0; i < StateSize; i++ {
+			if !states[lane][i].Equal(expected[lane][i]) {
+				t.Fatalf("lane %d element %d: batched %v, scalar %v", lane, i, states[lane][i], expected[lane][i])
+			}
+		}
+	}
+}
+
+func TestMdsColumnMatchesGeneratedFunction(t *testing.T) {
+	rng := rand.New(rand.NewSource(1148))
+	for trial := 0; trial < 10000; trial++ {
+		var column [StateSize]uint64
+		for i := range column {
+			column[i] = rng.Uint64() & 0xFFFFFFFF
+		}
+		if trial == 0 {
+			for i := range column {
+				column[i] = 0xFFFFFFFF
+			}
+		}
+
+		want := generatedFunction(column)
+		mdsColumn(&column)
+		if column != want {
+			t.Fatalf("trial %d: mdsColumn differs from generatedFunction", trial)
+		}
+	}
+}
+
+func TestMulMontgomeryMatchesField(t *testing.T) {
+	rng := rand.New(rand.NewSource(1148))
+	values := []field.Element{field.Zero, field.One, field.Max, field.New(1 << 32), field.New(1<<32 - 1)}
+	for i := 0; i < 1000; i++ {
+		values = append(values, field.New(rng.Uint64()))
+	}
+	for _, a := range values {
+		for _, b := range values[:20] {
+			if got := mulMontgomery(a.RawValue(), b.RawValue()); got != a.Mul(b).RawValue() {
+				t.Fatalf("mulMontgomery(%v, %v) = %#x, want %#x", a, b, got, a.Mul(b).RawValue())
+			}
+		}
+	}
+}
+
+func TestHashPairs4MatchesScalar(t *testing.T) {
+	var left, right [BatchWidth]Digest
+	for lane := 0; lane < BatchWidth; lane++ {
+		left[lane] = batchTestDigest(uint64(2 * lane))
+		right[lane] = batchTestDigest(uint64(2*lane + 1))
+	}
+	left[0] = ZeroDigest()
+
+	digests := HashPairs4(left, right)
+	for lane := 0; lane < BatchWidth; lane++ {
+		expected := Digest(HashPair(left[lane], right[lane]))
+		if !digests[lane].Equal(expected) {
+			t.Errorf("lane %d: batched %v, scalar %v", lane, digests[lane], expected)
+		}
+	}
+}
+
+func TestHashPairsIntoRemainders(t *testing.T) {
+	for n := 0; n <= 3*BatchWidth+1; n++ {
+		left := make([]Digest, n)
+		right := make([]Digest, n)
+		for i := 0; i < n; i++ {
+			left[i] = batchTestDigest(uint64(100 + i))
+			right[i] = batchTestDigest(uint64(200 + i))
+		}
+
+		out := make([]Digest, n)
+		HashPairsInto(out, left, right)
+
+		for i := 0; i < n; i++ {
+			if !out[i].Equal(HashPair(left[i], right[i])) {
+				t.Fatalf("n=%d: digest %d differs from scalar HashPair", n, i)
+			}
+		}
+	}
+}
+
+func TestHashPairsIntoLengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on mismatched slice lengths")
+		}
+	}()
+	HashPairsInto(make([]Digest, 2), make([]Digest, 2), make([]Digest, 1))
+}
+
+func BenchmarkTip5HashPairScalarX4(b *testing.B) {
+	var left, right [BatchWidth]Digest
+	for lane := 0; lane < BatchWidth; lane++ {
+		left[lane] = batchTestDigest(uint64(lane))
+		right[lane] = batchTestDigest(uint64(lane + BatchWidth))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for lane := 0; lane < BatchWidth; lane++ {
+			_ = HashPair(left[lane], right[lane])
+		}
+	}
+}
+
+func BenchmarkTip5HashPairs4(b *testing.B) {
+	var left, right [BatchWidth]Digest
+	for lane := 0; lane < BatchWidth; lane++ {
+		left[lane] = batchTestDigest(uint64(lane))
+		right[lane] = batchTestDigest(uint64(lane + BatchWidth))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = HashPairs4(left, right)
+	}
+}
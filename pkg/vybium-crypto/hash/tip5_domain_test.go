@@ -0,0 +1,194 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func testDigests(values ...uint64) []Digest {
+	digests := make([]Digest, len(values))
+	for i, v := range values {
+		digests[i] = Digest(HashVarlen([]field.Element{field.New(v)}))
+	}
+	return digests
+}
+
+func flattenDigests(digests []Digest) []field.Element {
+	elements := make([]field.Element, 0, len(digests)*DigestLen)
+	for _, d := range digests {
+		elements = append(elements, d[:]...)
+	}
+	return elements
+}
+
+// TestHashDomainGoldenVectors freezes the capacity-initialization scheme.
+func TestHashDomainGoldenVectors(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	pair := testDigests(7, 8)
+
+	tests := []struct {
+		name string
+		got  Digest
+		want string
+	}{
+		{"varlen empty label empty input", HashVarlenDomain("", nil),
+			"a560b0be3260e1ef6adbae69c5e8e884e54f1f132ecc365d30af921fa5d73349fc148b1ac1d21bdb"},
+		{"varlen short label", HashVarlenDomain("vybium/example", input),
+			"9b5d4d44632cae44af5d7f7e58059399a83c8d558269f4e8ca36c24fbe1b6d1eaf9fdc5b75b6de50"},
+		{"varlen long label", HashVarlenDomain("a label longer than seven bytes", input),
+			"0190d71d9603d4691e788f05213c9d07f79a4dfc5cb733f93a1915ba02a3652091bdeccf2966ed64"},
+		{"pair empty label", HashPairDomain("", pair[0], pair[1]),
+			"aa334d8f414bc499aa5259f9b08cc0b33eefe087023512197133e5ec3b07ba65f278c4fbc627f035"},
+		{"pair short label", HashPairDomain("vybium/example", pair[0], pair[1]),
+			"01d4a1a590276fb003648123c89fdd5db70b47d91daad8994998e331a9ceefd94eb261f84089a285"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Hex() != tt.want {
+				t.Errorf("got %s, want %s", tt.got.Hex(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHashVarlenDomainSeparation(t *testing.T) {
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	labels := []string{"", "a", "b", "ab", "protocol-a", "protocol-b", "\x00", "\x00\x00"}
+
+	seen := make(map[Digest]string)
+	for _, label := range labels {
+		digest := HashVarlenDomain(label, input)
+		if other, ok := seen[digest]; ok {
+			t.Errorf("labels %q and %q give the same digest", label, other)
+		}
+		seen[digest] = label
+	}
+
+	if Digest(HashVarlen(input)) == HashVarlenDomain("", input) {
+		t.Error("empty label must not reproduce plain HashVarlen")
+	}
+	if HashVarlenDomain("a", input) != HashVarlenDomain("a", input) {
+		t.Error("HashVarlenDomain is not deterministic")
+	}
+}
+
+func TestHashPairDomainSeparation(t *testing.T) {
+	pair := testDigests(7, 8)
+	plain := Digest(HashPair(pair[0], pair[1]))
+
+	if HashPairDomain("", pair[0], pair[1]) == plain {
+		t.Error("empty label must not reproduce plain HashPair")
+	}
+	if HashPairDomain("a", pair[0], pair[1]) == HashPairDomain("b", pair[0], pair[1]) {
+		t.Error("different labels give the same digest")
+	}
+	// A pair is also a 10-element sequence; the fixed- and variable-length
+	// labeled modes must not agree on it
+	if HashPairDomain("a", pair[0], pair[1]) == HashVarlenDomain("a", flattenDigests(pair)) {
+		t.Error("HashPairDomain and HashVarlenDomain agree on the same data")
+	}
+}
+
+func TestHashVarlenWithDomainSeparation(t *testing.T) {
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	// Tags that agree modulo P, or in one u32 half, must still differ
+	tags := []uint64{0, 1, 2, 1 << 32, field.P, field.P + 1, 1<<64 - 1}
+
+	seen := make(map[Digest]uint64)
+	for _, tag := range tags {
+		digest := HashVarlenWithDomain(tag, input)
+		if other, ok := seen[digest]; ok {
+			t.Errorf("tags %#x and %#x give the same digest", tag, other)
+		}
+		seen[digest] = tag
+	}
+
+	if HashVarlenWithDomain(0, input) == Digest(HashVarlen(input)) {
+		t.Error("tag 0 must not reproduce plain HashVarlen")
+	}
+	if HashVarlenWithDomain(0, input) == HashVarlenDomain("", input) {
+		t.Error("tag 0 must not reproduce the empty label")
+	}
+	if HashVarlenWithDomain(7, input) != HashVarlenWithDomain(7, input) {
+		t.Error("HashVarlenWithDomain is not deterministic")
+	}
+	if HashVarlenWithDomain(7, input) == HashVarlenWithDomain(7, input[:2]) {
+		t.Error("different inputs give the same digest under one tag")
+	}
+}
+
+func TestHashVarlenWithDomainGolden(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	tests := []struct {
+		tag  uint64
+		want string
+	}{
+		{0, "408135070057b3f5ca5f94f565ed2fd522815557f07da7d70b2eec1883c12940b50c7c9819b60e97"},
+		{0x0123456789abcdef, "18b18a2e2e49ec280422870e97f9911f7b242aef249f35df2718bcbe4677c74a84cfaaf533947b1f"},
+	}
+	for _, tt := range tests {
+		if got := HashVarlenWithDomain(tt.tag, input).Hex(); got != tt.want {
+			t.Errorf("tag %#x: got %s, want %s", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeDomainLabelIsInjective(t *testing.T) {
+	// Labels that only differ in trailing zero bytes or chunk boundaries
+	labels := []string{"", "\x00", "1234567", "1234567\x00", "12345678"}
+
+	seen := make(map[Digest]string)
+	for _, label := range labels {
+		digest := Digest(HashVarlen(encodeDomainLabel(label)))
+		if other, ok := seen[digest]; ok {
+			t.Errorf("labels %q and %q encode identically", label, other)
+		}
+		seen[digest] = label
+	}
+}
+
+// TestHashDomainReplayRegression covers two protocols that both commit to a
+// list of digests. A commitment made in one protocol must not verify as a
+// commitment in the other.
+func TestHashDomainReplayRegression(t *testing.T) {
+	const protocolA = "vybium/protocol-a/commitment"
+	const protocolB = "vybium/protocol-b/commitment"
+
+	digests := testDigests(1, 2, 3, 4)
+	data := flattenDigests(digests)
+
+	commitmentA := HashVarlenDomain(protocolA, data)
+	if commitmentA == HashVarlenDomain(protocolB, data) {
+		t.Fatal("protocol A commitment replays as protocol B commitment")
+	}
+	if commitmentA == Digest(HashVarlen(data)) {
+		t.Fatal("protocol A commitment replays as an untagged commitment")
+	}
+
+	// Merkle-style compression of the same list under both labels
+	rootA := HashPairDomain(protocolA, HashPairDomain(protocolA, digests[0], digests[1]), HashPairDomain(protocolA, digests[2], digests[3]))
+	rootB := HashPairDomain(protocolB, HashPairDomain(protocolB, digests[0], digests[1]), HashPairDomain(protocolB, digests[2], digests[3]))
+	if rootA == rootB {
+		t.Fatal("protocol A root replays as protocol B root")
+	}
+}
+
+func BenchmarkHashVarlenDomain(b *testing.B) {
+	input := make([]field.Element, 100)
+	for i := range input {
+		input[i] = field.New(uint64(i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = HashVarlenDomain("vybium/benchmark", input)
+	}
+}
@@ -0,0 +1,88 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// hashPairFixture returns two non-trivial digests with elements spread over
+// the whole field, including values above 2^32 and P-1.
+func hashPairFixture() (left, right Digest) {
+	left = Digest{
+		field.New(0x0123456789abcdef), field.New(field.P - 1), field.New(1 << 32),
+		field.New(42), field.New(0xfedcba9876543210 % field.P),
+	}
+	right = Digest{
+		field.New(7), field.New(0xdeadbeefcafebabe % field.P), field.Zero,
+		field.New(field.P - 2), field.New(1<<32 - 1),
+	}
+	return left, right
+}
+
+// TestHashPairLayout pins the state HashPair permutes: the FixedLength
+// domain (capacity all ones), left in state[0..5) and right in state[5..10).
+func TestHashPairLayout(t *testing.T) {
+	left, right := hashPairFixture()
+	sponge := newHashPairSponge(left, right)
+	trace := sponge.Trace()
+	initial := trace[0]
+
+	for i := 0; i < DigestLen; i++ {
+		if initial[i] != left[i] {
+			t.Errorf("state[%d] = %v, want left[%d] = %v", i, initial[i], i, left[i])
+		}
+		if initial[DigestLen+i] != right[i] {
+			t.Errorf("state[%d] = %v, want right[%d] = %v", DigestLen+i, initial[DigestLen+i], i, right[i])
+		}
+	}
+	for i := Rate; i < StateSize; i++ {
+		if !initial[i].IsOne() {
+			t.Errorf("capacity state[%d] = %v, want 1", i, initial[i])
+		}
+	}
+
+	// The digest is the first DigestLen elements after the last round
+	final := trace[NumRounds]
+	digest := Digest(HashPair(left, right))
+	for i := 0; i < DigestLen; i++ {
+		if final[i] != digest[i] {
+			t.Fatalf("HashPair is not the first %d elements of the permuted state", DigestLen)
+		}
+	}
+}
+
+// TestHashPairGolden checks HashPair against the twenty-first Hash10 chain
+// vector (tip5ReferenceChainDigest): in twenty-first, as here, hash_pair is
+// hash_10 of the concatenated digests, so HashPair of the two halves of the
+// chain's final preimage must give the chain's final digest. Together with
+// TestHashPairLayout it catches any change to the domain or state layout.
+func TestHashPairGolden(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	preimage := tip5ReferenceChain()
+	var left, right Digest
+	copy(left[:], preimage[:DigestLen])
+	copy(right[:], preimage[DigestLen:])
+	checkDigestValues(t, "HashPair of the chained preimage", HashPair(left, right), tip5ReferenceChainDigest)
+}
+
+func TestHashPairIsOrdered(t *testing.T) {
+	left, right := hashPairFixture()
+	if HashPair(left, right) == HashPair(right, left) {
+		t.Error("HashPair(a, b) = HashPair(b, a)")
+	}
+
+	// Not the VariableLength domain: capacity zeros give a different digest
+	sponge := newHashPairSponge(left, right)
+	for i := Rate; i < StateSize; i++ {
+		sponge.state[i] = field.Zero
+	}
+	sponge.Permutation()
+	var variable Digest
+	copy(variable[:], sponge.state[:DigestLen])
+	if variable == Digest(HashPair(left, right)) {
+		t.Error("HashPair does not depend on the domain")
+	}
+}
@@ -0,0 +1,114 @@
+package hash
+
+import (
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Constants for generatedFunction, derived from MdsMatrixFirstColumn.
+//
+// Folding the column c of a cyclic convolution of size 2n gives
+// c⁺[k] = c[k] + c[k+n] for the cyclic part and c⁻[k] = c[k] - c[k+n] for the
+// negacyclic part. The negacyclic matrices below are those of c⁻ at sizes 8,
+// 4 and 2, scaled by 8, 4 and 2 respectively, and mdsCyclic1/mdsNegacyclic1
+// are the two scalars left after the last fold.
+var (
+	mdsNegacyclic8 [8][8]uint64
+	mdsNegacyclic4 [4][4]uint64
+	mdsNegacyclic2 [2][2]uint64
+	mdsCyclic1     uint64
+	mdsNegacyclic1 uint64
+)
+
+func init() {
+	column := make([]int64, StateSize)
+	for i, c := range MdsMatrixFirstColumn {
+		column[i] = int64(c)
+	}
+
+	var scale int64 = StateSize / 2
+	fillNegacyclic := func(n int) [][]uint64 {
+		folded := make([]int64, n)
+		for k := 0; k < n; k++ {
+			folded[k] = scale * (column[k] - column[k+n])
+		}
+		matrix := make([][]uint64, n)
+		for i := range matrix {
+			matrix[i] = make([]uint64, n)
+			for j := 0; j < n; j++ {
+				if i >= j {
+					matrix[i][j] = uint64(folded[i-j])
+				} else {
+					matrix[i][j] = uint64(-folded[i-j+n])
+				}
+			}
+		}
+
+		// Continue with the cyclic part at half the size and half the scale
+		for k := 0; k < n; k++ {
+			column[k] += column[k+n]
+		}
+		column = column[:n]
+		scale /= 2
+		return matrix
+	}
+
+	for i, row := range fillNegacyclic(8) {
+		copy(mdsNegacyclic8[i][:], row)
+	}
+	for i, row := range fillNegacyclic(4) {
+		copy(mdsNegacyclic4[i][:], row)
+	}
+	for i, row := range fillNegacyclic(2) {
+		copy(mdsNegacyclic2[i][:], row)
+	}
+	mdsCyclic1 = uint64(column[0] + column[1])
+	mdsNegacyclic1 = uint64(column[0] - column[1])
+}
+
+func negacyclicConvolve8(x *[8]uint64) [8]uint64 {
+	var result [8]uint64
+	for i := range result {
+		row := &mdsNegacyclic8[i]
+		result[i] = row[0]*x[0] + row[1]*x[1] + row[2]*x[2] + row[3]*x[3] +
+			row[4]*x[4] + row[5]*x[5] + row[6]*x[6] + row[7]*x[7]
+	}
+	return result
+}
+
+func negacyclicConvolve4(x *[4]uint64) [4]uint64 {
+	var result [4]uint64
+	for i := range result {
+		row := &mdsNegacyclic4[i]
+		result[i] = row[0]*x[0] + row[1]*x[1] + row[2]*x[2] + row[3]*x[3]
+	}
+	return result
+}
+
+func negacyclicConvolve2(x *[2]uint64) [2]uint64 {
+	return [2]uint64{
+		mdsNegacyclic2[0][0]*x[0] + mdsNegacyclic2[0][1]*x[1],
+		mdsNegacyclic2[1][0]*x[0] + mdsNegacyclic2[1][1]*x[1],
+	}
+}
+
+// mdsReference applies the MDS layer by multiplying the state with the
+// explicit circulant matrix M, M[i][j] = MdsMatrixFirstColumn[(i-j) mod 16],
+// using plain field arithmetic. It is the specification the optimized
+// mdsGenerated is tested against, and is used by the permutation instead of
+// mdsGenerated when building with the tip5_mds_reference tag.
+func mdsReference(state *[StateSize]field.Element) {
+	var column [StateSize]field.Element
+	for i, c := range MdsMatrixFirstColumn {
+		column[i] = field.New(c)
+	}
+
+	var result [StateSize]field.Element
+	for i := 0; i < StateSize; i++ {
+		acc := field.Zero
+		for j := 0; j < StateSize; j++ {
+			acc = acc.Add(column[(i-j+StateSize)%StateSize].Mul(state[j]))
+		}
+		result[i] = acc
+	}
+	*state = result
+}
@@ -0,0 +1,7 @@
+//go:build !tip5_mds_reference
+
+package hash
+
+// useMdsReference selects mdsReference instead of the optimized MDS layer.
+// Build with the tip5_mds_reference tag to enable it.
+const useMdsReference = false
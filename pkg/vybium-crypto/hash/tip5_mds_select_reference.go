@@ -0,0 +1,6 @@
+//go:build tip5_mds_reference
+
+package hash
+
+// useMdsReference selects mdsReference instead of the optimized MDS layer.
+const useMdsReference = true
@@ -0,0 +1,106 @@
+package hash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// mdsMatrixTimes16 computes 16·M·input exactly. For 32-bit inputs the result
+// fits in a uint64 without wrapping.
+func mdsMatrixTimes16(input [StateSize]uint64) [StateSize]uint64 {
+	var result [StateSize]uint64
+	for i := 0; i < StateSize; i++ {
+		for j := 0; j < StateSize; j++ {
+			result[i] += 16 * MdsMatrixFirstColumn[(i-j+StateSize)%StateSize] * input[j]
+		}
+	}
+	return result
+}
+
+func TestTip5GeneratedFunctionMatchesMdsMatrix(t *testing.T) {
+	rng := rand.New(rand.NewSource(1154))
+
+	inputs := make([][StateSize]uint64, 0, 50000)
+	var allMax [StateSize]uint64
+	for i := range allMax {
+		allMax[i] = 0xFFFFFFFF
+	}
+	inputs = append(inputs, [StateSize]uint64{}, allMax)
+	for j := 0; j < StateSize; j++ {
+		var unit [StateSize]uint64
+		unit[j] = 1
+		inputs = append(inputs, unit)
+	}
+	for len(inputs) < cap(inputs) {
+		var input [StateSize]uint64
+		for i := range input {
+			input[i] = uint64(rng.Uint32())
+		}
+		inputs = append(inputs, input)
+	}
+
+	for _, input := range inputs {
+		if got, want := generatedFunction(input), mdsMatrixTimes16(input); got != want {
+			t.Fatalf("generatedFunction(%v) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestTip5MdsGeneratedMatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1154))
+
+	for trial := 0; trial < 50000; trial++ {
+		var state [StateSize]field.Element
+		for i := range state {
+			state[i] = field.NewFromRaw(rng.Uint64() % field.P)
+		}
+
+		generated := Tip5{state: state}
+		generated.mdsGenerated()
+		reference := state
+		mdsReference(&reference)
+
+		if generated.state != reference {
+			t.Fatalf("trial %d: mdsGenerated differs from mdsReference for state %v", trial, state)
+		}
+	}
+}
+
+func TestTip5MdsReferenceIsCirculant(t *testing.T) {
+	// Applying the matrix to the first unit vector yields the first column
+	var state [StateSize]field.Element
+	state[0] = field.One
+	mdsReference(&state)
+
+	for i, c := range MdsMatrixFirstColumn {
+		if !state[i].Equal(field.New(c)) {
+			t.Errorf("M·e0[%d] = %v, want %d", i, state[i], c)
+		}
+	}
+}
+
+func BenchmarkTip5MdsGenerated(b *testing.B) {
+	tip5 := New(VariableLength)
+	for i := range tip5.state {
+		tip5.state[i] = field.New(uint64(i) * 0x9E3779B97F4A7C15)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tip5.mdsGenerated()
+	}
+}
+
+func BenchmarkTip5MdsReference(b *testing.B) {
+	var state [StateSize]field.Element
+	for i := range state {
+		state[i] = field.New(uint64(i) * 0x9E3779B97F4A7C15)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mdsReference(&state)
+	}
+}
@@ -0,0 +1,75 @@
+package hash
+
+import (
+	"encoding/binary"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Convenience hashing of Go native types.
+//
+// The encodings below are frozen; changing any of them changes every digest
+// computed with the corresponding function.
+//
+//	HashUint64s(v) = HashVarlen([len(v), lo(v[0]), hi(v[0]), lo(v[1]), ...])
+//	HashBytes(b)   = HashVarlenDomain(BytesDomain, packBytes(b))
+//	HashString(s)  = HashVarlenDomain(StringDomain, packBytes([]byte(s)))
+//	HashDigests(d) = HashVarlen([d[0][0], ..., d[0][4], d[1][0], ...])
+//
+// lo and hi are the low and high 32 bits of a value, as in
+// bfieldcodec.EncodeUint64. packBytes is the byte length followed by the
+// bytes packed little-endian, seven bytes per element, the last element
+// zero-padded; the same packing encodes domain labels.
+//
+// Strings and byte slices are hashed under different domains, so a string
+// and its UTF-8 bytes have different digests. Applications that want them
+// to agree should convert the string and call HashBytes.
+const (
+	BytesDomain  = "vybium/hash/bytes"
+	StringDomain = "vybium/hash/string"
+)
+
+// bytesPerPackedElement is the number of bytes packed into one field element
+// by packBytes. Seven bytes always fit below the Goldilocks prime.
+const bytesPerPackedElement = 7
+
+// packBytes encodes a byte slice as its length followed by its bytes packed
+// little-endian, bytesPerPackedElement bytes per element. The length prefix
+// makes the encoding injective despite the zero padding.
+func packBytes(data []byte) []field.Element {
+	encoded := make([]field.Element, 0, 1+(len(data)+bytesPerPackedElement-1)/bytesPerPackedElement)
+	encoded = append(encoded, field.New(uint64(len(data))))
+	for i := 0; i < len(data); i += bytesPerPackedElement {
+		var chunk [8]byte
+		copy(chunk[:bytesPerPackedElement], data[i:])
+		encoded = append(encoded, field.New(binary.LittleEndian.Uint64(chunk[:])))
+	}
+	return encoded
+}
+
+// HashUint64s hashes a slice of uint64 values, each encoded as two 32-bit
+// limbs, low limb first, after a length prefix.
+func HashUint64s(values []uint64) Digest {
+	encoded := make([]field.Element, 0, 1+2*len(values))
+	encoded = append(encoded, field.New(uint64(len(values))))
+	for _, v := range values {
+		encoded = append(encoded, field.New(v&0xFFFFFFFF), field.New(v>>32))
+	}
+	return HashVarlen(encoded)
+}
+
+// HashBytes hashes a byte slice under BytesDomain.
+func HashBytes(data []byte) Digest {
+	return HashVarlenDomain(BytesDomain, packBytes(data))
+}
+
+// HashString hashes a string's UTF-8 bytes under StringDomain.
+func HashString(s string) Digest {
+	return HashVarlenDomain(StringDomain, packBytes([]byte(s)))
+}
+
+// HashDigests hashes the concatenation of the digests' elements, without a
+// length prefix, like HashVarlen over the flattened digests.
+func HashDigests(digests []Digest) Digest {
+	return HashVarlen(DigestsToElements(digests))
+}
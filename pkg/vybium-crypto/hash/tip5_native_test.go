@@ -0,0 +1,147 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// TestHashNativeGoldenVectors freezes the native-type encodings.
+func TestHashNativeGoldenVectors(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+
+	tests := []struct {
+		name string
+		got  Digest
+		want string
+	}{
+		{"uint64s empty", HashUint64s(nil),
+			"49390b5279de3843c90d85289b12a2e65004866a98d03cfdbca7eb0c91bafd6962c094958c115b7e"},
+		{"uint64s", HashUint64s([]uint64{0, 1, 1 << 32, ^uint64(0)}),
+			"7710cf1167e96925e3284dceae96c5574b32831dc9b352c3ddfa2337b7db564a2cdcc0fc2a81f7e8"},
+		{"bytes empty", HashBytes(nil),
+			"b46ad421942d12a7f9decdd22b0b061c5868491137e39358cdca791c45009dd9ac44ae7a6f987c95"},
+		{"bytes", HashBytes([]byte("vybium")),
+			"cb8a79bc6afc67d312b88a020523bb4728cc4998d258b64a4c0c82b4f6cee6f2c02718e721e47187"},
+		{"string empty", HashString(""),
+			"160b7fcc206f5384b33b01a4d7d2243f0afe5e857b25a9d758b9d3c36bb8a06f37f061b069e8f19e"},
+		{"string", HashString("vybium"),
+			"93d4e3e97e9a5137cc25c031b0c484cc89553be942517edeaaf40e052c275394c1cd64d7f49ca70d"},
+		{"digests empty", HashDigests(nil),
+			"403651cf544769206972310ae1752412618ccc448b11612fcb220e193b03b81d0a331f441175cd52"},
+		{"digests", HashDigests(testDigests(1, 2)),
+			"a1e037d76b73cf2fdd7f56af0e4b85cd4fb175bc3f0178fc7009c80ab68d1b2e2e7b5caeb7ce27ee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Hex() != tt.want {
+				t.Errorf("got %s, want %s", tt.got.Hex(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHashUint64sMatchesManualEncoding(t *testing.T) {
+	values := []uint64{0, 1, 0xFFFFFFFF, 1 << 32, 0xDEADBEEFCAFEBABE, ^uint64(0)}
+
+	encoded := []field.Element{field.New(uint64(len(values)))}
+	for _, v := range values {
+		encoded = append(encoded, bfieldcodec.EncodeUint64(v)...)
+	}
+	if HashUint64s(values) != Digest(HashVarlen(encoded)) {
+		t.Error("HashUint64s differs from length prefix + EncodeUint64 + HashVarlen")
+	}
+}
+
+func TestHashBytesMatchesManualEncoding(t *testing.T) {
+	// 15 bytes: three elements, the last holding a single byte
+	data := []byte("0123456789abcde")
+	encoded := []field.Element{
+		field.New(15),
+		field.New(0x36353433323130),
+		field.New(0x64636261393837),
+		field.New(0x65),
+	}
+
+	if HashBytes(data) != HashVarlenDomain(BytesDomain, encoded) {
+		t.Error("HashBytes differs from manual packing")
+	}
+	if HashString(string(data)) != HashVarlenDomain(StringDomain, encoded) {
+		t.Error("HashString differs from manual packing")
+	}
+}
+
+func TestHashDigestsMatchesHashVarlen(t *testing.T) {
+	digests := testDigests(1, 2, 3)
+	if HashDigests(digests) != Digest(HashVarlen(flattenDigests(digests))) {
+		t.Error("HashDigests differs from HashVarlen over flattened digests")
+	}
+}
+
+func TestHashNativeVariantsDoNotCollide(t *testing.T) {
+	seen := make(map[Digest]string)
+	add := func(name string, d Digest) {
+		t.Helper()
+		if other, ok := seen[d]; ok {
+			t.Errorf("%s collides with %s", name, other)
+		}
+		seen[d] = name
+	}
+
+	// A string and its bytes
+	add("string a", HashString("a"))
+	add("bytes a", HashBytes([]byte("a")))
+
+	// Empty inputs of every variant
+	add("string empty", HashString(""))
+	add("bytes empty", HashBytes(nil))
+	add("uint64s empty", HashUint64s(nil))
+	add("digests empty", HashDigests(nil))
+
+	// Zero padding is not ambiguous thanks to the length prefix
+	add("bytes 0", HashBytes([]byte{0}))
+	add("bytes 00", HashBytes([]byte{0, 0}))
+	add("bytes 7x00", HashBytes(make([]byte, 7)))
+	add("bytes 8x00", HashBytes(make([]byte, 8)))
+
+	// Small values whose encodings look alike
+	add("uint64s [0]", HashUint64s([]uint64{0}))
+	add("uint64s [0 0]", HashUint64s([]uint64{0, 0}))
+	add("uint64s [1<<32]", HashUint64s([]uint64{1 << 32}))
+	add("uint64s [1]", HashUint64s([]uint64{1}))
+
+	// The same elements hashed as bytes and as uint64s
+	add("bytes 01", HashBytes([]byte{1}))
+	add("uint64s [1 1]", HashUint64s([]uint64{1, 1}))
+}
+
+func BenchmarkHashBytes(b *testing.B) {
+	data := make([]byte, 1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = HashBytes(data)
+	}
+}
+
+func TestPackBytesMatchesEncodeString(t *testing.T) {
+	for _, s := range []string{"", "vybium", "vybium!", StringDomain, "日本語 🦀"} {
+		got, want := packBytes([]byte(s)), bfieldcodec.EncodeString(s)
+		if len(got) != len(want) {
+			t.Fatalf("%q: packBytes has %d elements, EncodeString %d", s, len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%q: element %d is %v, EncodeString gives %v", s, i, got[i], want[i])
+			}
+		}
+	}
+}
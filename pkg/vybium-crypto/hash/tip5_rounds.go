@@ -0,0 +1,15 @@
+//go:build !tip5weak
+
+package hash
+
+// NumRounds is the number of rounds of the Tip5 permutation.
+const NumRounds = numRoundsFull
+
+// WeakHashingEnabled reports whether the package was built with the tip5weak
+// tag, which reduces the Tip5 permutation to a single round.
+const WeakHashingEnabled = false
+
+// ProductionHashing is only defined in builds without the tip5weak tag.
+// Release builds can reference it so that they fail to compile with weak
+// hashing enabled.
+const ProductionHashing = true
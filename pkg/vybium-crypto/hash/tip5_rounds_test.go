@@ -0,0 +1,30 @@
+package hash
+
+import "testing"
+
+func TestNumRoundsMatchesBuild(t *testing.T) {
+	if WeakHashingEnabled {
+		if NumRounds != 1 {
+			t.Errorf("weak build has %d rounds, want 1", NumRounds)
+		}
+		return
+	}
+	if NumRounds != numRoundsFull {
+		t.Errorf("production build has %d rounds, want %d", NumRounds, numRoundsFull)
+	}
+}
+
+// TestWeakPermutationDiffers guards against the weak variant silently
+// matching the full permutation, which would hide a broken build tag.
+func TestWeakPermutationDiffers(t *testing.T) {
+	full := &Tip5{}
+	for i := 0; i < numRoundsFull; i++ {
+		full.round(i)
+	}
+	permuted := &Tip5{}
+	permuted.Permutation()
+
+	if (full.state == permuted.state) == WeakHashingEnabled {
+		t.Errorf("Permutation with %d rounds: matches full permutation = %v", NumRounds, full.state == permuted.state)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build tip5weak
+
+package hash
+
+import (
+	"fmt"
+	"os"
+)
+
+// NumRounds is the number of rounds of the Tip5 permutation.
+//
+// The tip5weak tag reduces it to 1 so that fuzz targets and property tests of
+// higher layers spend less time hashing. One round of Tip5 provides no
+// security whatsoever; this build must never be used outside of tests.
+const NumRounds = 1
+
+// WeakHashingEnabled reports whether the package was built with the tip5weak
+// tag, which reduces the Tip5 permutation to a single round.
+const WeakHashingEnabled = true
+
+func init() {
+	fmt.Fprintln(os.Stderr, "WARNING: vybium-crypto built with tip5weak: Tip5 runs 1 of 5 rounds and is INSECURE; use for testing only")
+}
@@ -0,0 +1,31 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// ExportState returns the sponge state as canonical values, suitable for
+// serializing a protocol checkpoint. ImportTip5State restores it.
+func (t *Tip5) ExportState() [StateSize]uint64 {
+	var state [StateSize]uint64
+	for i, element := range t.state {
+		state[i] = element.Value()
+	}
+	return state
+}
+
+// ImportTip5State returns a sponge with the given state, as produced by
+// ExportState.
+// Returns an error if an element is not a canonical value, i.e. not below P.
+func ImportTip5State(state [StateSize]uint64) (*Tip5, error) {
+	t := &Tip5{}
+	for i, value := range state {
+		if value >= field.P {
+			return nil, fmt.Errorf("state element %d is %d, not below P", i, value)
+		}
+		t.state[i] = field.New(value)
+	}
+	return t, nil
+}
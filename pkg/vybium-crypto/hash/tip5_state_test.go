@@ -0,0 +1,45 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestExportImportTip5State(t *testing.T) {
+	sponge := New(VariableLength)
+	sponge.PadAndAbsorbAll(flattenDigests(testDigests(1, 2, 3, 4, 5)))
+	exported := sponge.ExportState()
+
+	restored, err := ImportTip5State(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.state != sponge.state {
+		t.Fatal("imported state differs from the exported one")
+	}
+	if restored.ExportState() != exported {
+		t.Fatal("export of the imported state differs")
+	}
+
+	// Both continue identically
+	if restored.Squeeze() != sponge.Squeeze() {
+		t.Error("restored sponge squeezes differently")
+	}
+}
+
+func TestImportTip5StateRejectsNonCanonical(t *testing.T) {
+	for _, value := range []uint64{field.P, field.P + 1, ^uint64(0)} {
+		var state [StateSize]uint64
+		state[StateSize-1] = value
+		if _, err := ImportTip5State(state); err == nil {
+			t.Errorf("accepted state element %d", value)
+		}
+	}
+
+	var state [StateSize]uint64
+	state[0] = field.P - 1
+	if _, err := ImportTip5State(state); err != nil {
+		t.Errorf("rejected P-1: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package hash
+
+import (
+	"encoding/binary"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Tip5Writer hashes variable-length input fed incrementally. Its digest is
+// HashVarlen of everything written, however the input is split across
+// calls; full rate blocks are absorbed as they fill, so memory use does not
+// grow with the input.
+//
+// Write packs bytes like packBytes without the length prefix: seven bytes
+// per element, little-endian, the last element zero-padded. Packing runs
+// across Write calls and ends at WriteElements and Finalize, which flush a
+// partial element. The packing does not distinguish trailing zero bytes from
+// padding; callers hashing byte strings of varying length should write the
+// length first, as HashBytes does.
+//
+// A Tip5Writer is not safe for concurrent use.
+type Tip5Writer struct {
+	sponge   Tip5
+	block    [Rate]field.Element
+	buffered int
+	// pending holds the bytes of the element being packed by Write.
+	pending    [bytesPerPackedElement]byte
+	numPending int
+}
+
+// NewTip5Writer returns a writer in the VariableLength domain with nothing
+// written.
+func NewTip5Writer() *Tip5Writer {
+	return &Tip5Writer{sponge: *New(VariableLength)}
+}
+
+// Write packs p into field elements and absorbs them. It implements
+// io.Writer and never returns an error.
+func (w *Tip5Writer) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		copied := copy(w.pending[w.numPending:], p)
+		w.numPending += copied
+		p = p[copied:]
+		if w.numPending == bytesPerPackedElement {
+			w.flushBytes()
+		}
+	}
+	return n, nil
+}
+
+// WriteElements absorbs elements, after the partial element of preceding
+// Write calls, if any.
+func (w *Tip5Writer) WriteElements(elements []field.Element) {
+	w.flushBytes()
+	for _, element := range elements {
+		w.writeElement(element)
+	}
+}
+
+// Finalize returns HashVarlen of the input written so far. It does not
+// change the writer, so writing may continue.
+func (w *Tip5Writer) Finalize() [DigestLen]field.Element {
+	final := *w
+	final.flushBytes()
+
+	// Padding: [1, 0, 0, ...], a whole block if the input fills its last one
+	final.block[final.buffered] = field.One
+	for i := final.buffered + 1; i < Rate; i++ {
+		final.block[i] = field.Zero
+	}
+	final.sponge.Absorb(final.block)
+
+	var digest [DigestLen]field.Element
+	copy(digest[:], final.sponge.state[:DigestLen])
+	return digest
+}
+
+// Reset discards the input written so far.
+func (w *Tip5Writer) Reset() {
+	*w = Tip5Writer{sponge: *New(VariableLength)}
+}
+
+func (w *Tip5Writer) writeElement(element field.Element) {
+	w.block[w.buffered] = element
+	w.buffered++
+	if w.buffered == Rate {
+		w.sponge.Absorb(w.block)
+		w.buffered = 0
+	}
+}
+
+// flushBytes writes the pending bytes, zero-padded, as one element.
+func (w *Tip5Writer) flushBytes() {
+	if w.numPending == 0 {
+		return
+	}
+	var chunk [8]byte
+	copy(chunk[:], w.pending[:w.numPending])
+	w.numPending = 0
+	w.writeElement(field.New(binary.LittleEndian.Uint64(chunk[:])))
+}
@@ -0,0 +1,111 @@
+package hash
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// splitPoints returns sorted cut points in [0, n] for a random split of a
+// length-n input into several writes, some of them empty.
+func splitPoints(rng *rand.Rand, n int) []int {
+	points := []int{0}
+	for points[len(points)-1] < n {
+		next := points[len(points)-1] + rng.Intn(2*Rate)
+		if next > n {
+			next = n
+		}
+		points = append(points, next)
+	}
+	return points
+}
+
+func TestTip5WriterElementsMatchHashVarlen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1259))
+	for _, n := range []int{0, 1, Rate - 1, Rate, Rate + 1, 3*Rate - 3, 4 * Rate, 100} {
+		input := make([]field.Element, n)
+		for i := range input {
+			input[i] = field.New(rng.Uint64() % field.P)
+		}
+		want := HashVarlen(input)
+		for trial := 0; trial < 5; trial++ {
+			w := NewTip5Writer()
+			points := splitPoints(rng, n)
+			for i := 1; i < len(points); i++ {
+				w.WriteElements(input[points[i-1]:points[i]])
+			}
+			if got := w.Finalize(); got != want {
+				t.Errorf("%d elements split at %v: got %v, want %v", n, points, got, want)
+			}
+		}
+	}
+}
+
+func TestTip5WriterBytesMatchPackedHashVarlen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1259))
+	for _, n := range []int{0, 1, 6, 7, 8, 69, 70, 71, 500} {
+		data := make([]byte, n)
+		rng.Read(data)
+		want := HashVarlen(packBytes(data)[1:])
+		for trial := 0; trial < 5; trial++ {
+			w := NewTip5Writer()
+			points := splitPoints(rng, n)
+			for i := 1; i < len(points); i++ {
+				if written, err := w.Write(data[points[i-1]:points[i]]); err != nil || written != points[i]-points[i-1] {
+					t.Fatalf("Write returned %d, %v", written, err)
+				}
+			}
+			if got := w.Finalize(); got != want {
+				t.Errorf("%d bytes split at %v: got %v, want %v", n, points, got, want)
+			}
+		}
+	}
+}
+
+func TestTip5WriterIsIOWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("vybium"), 1000)
+	var w io.Writer = NewTip5Writer()
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.(*Tip5Writer).Finalize(), HashVarlen(packBytes(data)[1:]); got != want {
+		t.Errorf("io.Copy digest %v, want %v", got, want)
+	}
+}
+
+func TestTip5WriterMixedWrites(t *testing.T) {
+	// The three bytes are flushed as one element before the elements
+	w := NewTip5Writer()
+	w.Write([]byte{1, 2, 3})
+	w.WriteElements([]field.Element{field.New(4), field.New(5)})
+	w.Write([]byte{6})
+	want := HashVarlen([]field.Element{field.New(0x030201), field.New(4), field.New(5), field.New(6)})
+	if got := w.Finalize(); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTip5WriterFinalizeAndReset(t *testing.T) {
+	input := []field.Element{field.New(1), field.New(2), field.New(3)}
+	w := NewTip5Writer()
+	w.WriteElements(input[:2])
+	w.Write([]byte{9})
+	partial := w.Finalize()
+	if again := w.Finalize(); again != partial {
+		t.Error("Finalize changed the writer")
+	}
+
+	w.Reset()
+	w.WriteElements(input[:2])
+	w.WriteElements(input[2:])
+	if got := w.Finalize(); got != HashVarlen(input) {
+		t.Errorf("after Reset got %v, want %v", got, HashVarlen(input))
+	}
+	w.Reset()
+	if got := w.Finalize(); got != HashVarlen(nil) {
+		t.Errorf("empty writer got %v, want %v", got, HashVarlen(nil))
+	}
+}
@@ -0,0 +1,159 @@
+package hash
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// TranscriptDomain is the domain label of the transcript sponge.
+const TranscriptDomain = "vybium/transcript"
+
+// Transcript is a Fiat-Shamir transcript whose commitment phases are declared
+// up front. Each phase absorbs exactly one Merkle root, in declared order,
+// and challenges for a phase are only available once its root is absorbed,
+// so ordering mistakes surface as errors rather than as unverifiable proofs.
+//
+// The sponge is labeled with TranscriptDomain and first absorbs the declared
+// phase labels, each encoded as by HashVarlenDomain's label encoding. Each
+// root is absorbed together with its phase label:
+//
+//	PadAndAbsorbAll(encode(phase) ‖ root)
+//
+// A transcript ends with Finalize, which snapshots its challenges, or with
+// Fork, which hands its state to per-proof child transcripts; see
+// transcript_session.go.
+//
+// A Transcript is not safe for concurrent use.
+type Transcript struct {
+	sponge *Tip5
+	phases []string
+
+	// absorbed is the number of phases whose root has been absorbed.
+	absorbed int
+
+	// label is the fork label; empty for a transcript made by NewTranscript.
+	label string
+
+	// sampled records the challenges sampled so far, for Finalize.
+	sampled []LabeledChallenges
+
+	// finalized and forked are set by Finalize and Fork; either retires the
+	// transcript from absorbing and sampling.
+	finalized bool
+	forked    bool
+
+	// siblings is shared by the transcripts forked from the same parent;
+	// children is the group of this transcript's own forks.
+	siblings *forkGroup
+	children *forkGroup
+}
+
+// NewTranscript creates a transcript with the given commitment phases.
+// Returns an error if there are no phases, or a phase label is empty or
+// declared twice.
+func NewTranscript(phases []string) (*Transcript, error) {
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("transcript needs at least one phase")
+	}
+
+	seen := make(map[string]bool, len(phases))
+	var declaration []field.Element
+	declaration = append(declaration, field.New(uint64(len(phases))))
+	for _, phase := range phases {
+		if phase == "" {
+			return nil, fmt.Errorf("transcript phase labels must be non-empty")
+		}
+		if seen[phase] {
+			return nil, fmt.Errorf("transcript phase %q declared twice", phase)
+		}
+		seen[phase] = true
+		declaration = append(declaration, encodeDomainLabel(phase)...)
+	}
+
+	sponge := newLabeled(TranscriptDomain, labeledVariableLengthMarker)
+	sponge.PadAndAbsorbAll(declaration)
+
+	return &Transcript{
+		sponge: sponge,
+		phases: append([]string(nil), phases...),
+	}, nil
+}
+
+// Phases returns the declared phases in order.
+func (t *Transcript) Phases() []string {
+	return append([]string(nil), t.phases...)
+}
+
+// AbsorbRoot absorbs the Merkle root of the given phase.
+// Returns an error, and absorbs nothing, if the phase is unknown, was already
+// absorbed, or is not the next declared phase.
+func (t *Transcript) AbsorbRoot(phase string, root Digest) error {
+	if err := t.checkLive(); err != nil {
+		return err
+	}
+	index, err := t.phaseIndex(phase)
+	if err != nil {
+		return err
+	}
+	if index < t.absorbed {
+		return fmt.Errorf("root of phase %q already absorbed", phase)
+	}
+	if index > t.absorbed {
+		return fmt.Errorf("cannot absorb root of phase %q before phase %q", phase, t.phases[t.absorbed])
+	}
+
+	input := encodeDomainLabel(phase)
+	input = append(input, root[:]...)
+	t.sponge.PadAndAbsorbAll(input)
+	t.absorbed++
+	return nil
+}
+
+// ChallengeAfter samples n challenges following the given phase.
+// The phase must be the most recently absorbed one: challenges are refused
+// before its root is absorbed, and also once a later phase is absorbed, as
+// they would then depend on that phase's root too. A forked transcript also
+// refuses challenges while a sibling fork has sampled but not finalized.
+func (t *Transcript) ChallengeAfter(phase string, n int) ([]xfield.XFieldElement, error) {
+	if err := t.checkLive(); err != nil {
+		return nil, err
+	}
+	index, err := t.phaseIndex(phase)
+	if err != nil {
+		return nil, err
+	}
+	if index >= t.absorbed {
+		return nil, fmt.Errorf("cannot sample challenges after phase %q before its root is absorbed", phase)
+	}
+	if index != t.absorbed-1 {
+		return nil, fmt.Errorf("cannot sample challenges after phase %q once phase %q is absorbed", phase, t.phases[t.absorbed-1])
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("cannot sample %d challenges", n)
+	}
+	if err := t.siblings.claim(t); err != nil {
+		return nil, err
+	}
+
+	challenges, err := t.sponge.SampleScalars(n)
+	if err != nil {
+		return nil, err
+	}
+	t.sampled = append(t.sampled, LabeledChallenges{
+		Phase:      phase,
+		Challenges: append([]xfield.XFieldElement(nil), challenges...),
+	})
+	return challenges, nil
+}
+
+// phaseIndex returns the position of a declared phase.
+func (t *Transcript) phaseIndex(phase string) (int, error) {
+	for i, p := range t.phases {
+		if p == phase {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown transcript phase %q", phase)
+}
@@ -0,0 +1,183 @@
+package hash
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// Session management for transcripts verifying several proofs.
+//
+// A transcript shared between proofs hands the second proof challenges that
+// depend on the first, or, if the second proof restarts the phases, none at
+// all. Each proof therefore gets its own transcript, either fresh from
+// NewTranscript or forked from a common parent, and retires it with
+// Finalize once its challenges are drawn:
+//
+//	session, _ := NewTranscript(phases)
+//	a, _ := session.Fork("proof-a")
+//	// ... absorb and sample for proof A ...
+//	challengesA, _ := a.Finalize("proof-a")
+//	b, _ := session.Fork("proof-b")
+//	// ... absorb and sample for proof B ...
+//
+// A fork clones the parent's sponge and absorbs its label:
+//
+//	PadAndAbsorbAll(encode(label))
+//
+// The label encoding's length prefix fixes its length, which differs from
+// that of every root absorption, so forking cannot be mistaken for absorbing
+// a root. Forks with different labels thus sample unrelated challenges, and
+// forks with the same label from the same parent state sample the same ones.
+
+// LabeledChallenges are challenges sampled by one ChallengeAfter call,
+// labeled with its phase.
+type LabeledChallenges struct {
+	Phase      string
+	Challenges []xfield.XFieldElement
+}
+
+// ChallengeSet is the immutable record of every challenge a transcript
+// sampled, in order, produced by Transcript.Finalize.
+type ChallengeSet struct {
+	label   string
+	entries []LabeledChallenges
+}
+
+// Label returns the label given to Finalize.
+func (s *ChallengeSet) Label() string {
+	return s.label
+}
+
+// Entries returns the sampled challenges in sampling order.
+func (s *ChallengeSet) Entries() []LabeledChallenges {
+	entries := make([]LabeledChallenges, len(s.entries))
+	for i, entry := range s.entries {
+		entries[i] = LabeledChallenges{
+			Phase:      entry.Phase,
+			Challenges: append([]xfield.XFieldElement(nil), entry.Challenges...),
+		}
+	}
+	return entries
+}
+
+// Challenges returns the challenges sampled after the given phase, in
+// sampling order, or nil if there are none.
+func (s *ChallengeSet) Challenges(phase string) []xfield.XFieldElement {
+	var challenges []xfield.XFieldElement
+	for _, entry := range s.entries {
+		if entry.Phase == phase {
+			challenges = append(challenges, entry.Challenges...)
+		}
+	}
+	return challenges
+}
+
+// forkGroup tracks which of the transcripts forked from one parent is
+// mid-session: it has sampled challenges and not yet been finalized.
+type forkGroup struct {
+	mu     sync.Mutex
+	active *Transcript
+}
+
+// claim records t as the group's active transcript. Returns an error if a
+// different transcript of the group is active. A nil group, that of a
+// transcript that was not forked, accepts every claim.
+func (g *forkGroup) claim(t *Transcript) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active != nil && g.active != t {
+		return fmt.Errorf("cannot sample challenges for fork %q while fork %q is unfinalized", t.label, g.active.label)
+	}
+	g.active = t
+	return nil
+}
+
+// release clears t as the group's active transcript.
+func (g *forkGroup) release(t *Transcript) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active == t {
+		g.active = nil
+	}
+}
+
+// checkLive returns an error if the transcript was finalized or forked.
+func (t *Transcript) checkLive() error {
+	if t.finalized {
+		return fmt.Errorf("transcript %s is finalized", t.describe())
+	}
+	if t.forked {
+		return fmt.Errorf("transcript %s was forked; use its forks", t.describe())
+	}
+	return nil
+}
+
+// describe names the transcript in error messages.
+func (t *Transcript) describe() string {
+	if t.label == "" {
+		return "root"
+	}
+	return fmt.Sprintf("%q", t.label)
+}
+
+// Fork returns a child transcript for the given label. The child continues
+// from the parent's state, with the same phases and absorbed roots, after
+// absorbing the label. The parent neither absorbs nor samples afterwards,
+// though it may be forked again.
+//
+// Forks of one parent may be used one at a time: once a fork has sampled
+// challenges, its siblings cannot sample until it is finalized.
+//
+// Returns an error if the label is empty or the transcript is finalized.
+func (t *Transcript) Fork(label string) (*Transcript, error) {
+	if label == "" {
+		return nil, fmt.Errorf("transcript fork labels must be non-empty")
+	}
+	if t.finalized {
+		return nil, fmt.Errorf("transcript %s is finalized", t.describe())
+	}
+
+	sponge := *t.sponge
+	sponge.PadAndAbsorbAll(encodeDomainLabel(label))
+
+	if t.children == nil {
+		t.children = &forkGroup{}
+	}
+	t.forked = true
+	return &Transcript{
+		sponge:   &sponge,
+		phases:   t.phases,
+		absorbed: t.absorbed,
+		label:    label,
+		siblings: t.children,
+	}, nil
+}
+
+// Finalize retires the transcript and returns the challenges it sampled.
+// The transcript neither absorbs, samples nor forks afterwards, and its
+// siblings may sample again.
+//
+// Returns an error if the label is empty or the transcript is already
+// finalized.
+func (t *Transcript) Finalize(label string) (*ChallengeSet, error) {
+	if label == "" {
+		return nil, fmt.Errorf("challenge set labels must be non-empty")
+	}
+	if t.finalized {
+		return nil, fmt.Errorf("transcript %s is already finalized", t.describe())
+	}
+
+	t.finalized = true
+	t.siblings.release(t)
+	set := &ChallengeSet{label: label, entries: t.sampled}
+	t.sampled = nil
+	return set, nil
+}
@@ -0,0 +1,230 @@
+package hash
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// verifyProof plays a verifier's side of a proof with the given roots on the
+// transcript, sampling two challenges after each phase, and finalizes it.
+func verifyProof(t *testing.T, tr *Transcript, label string, roots []Digest) *ChallengeSet {
+	t.Helper()
+	for i, phase := range testPhases {
+		if err := tr.AbsorbRoot(phase, roots[i]); err != nil {
+			t.Fatalf("%s: absorbing %s: %v", label, phase, err)
+		}
+		if _, err := tr.ChallengeAfter(phase, 2); err != nil {
+			t.Fatalf("%s: challenges after %s: %v", label, phase, err)
+		}
+	}
+	set, err := tr.Finalize(label)
+	if err != nil {
+		t.Fatalf("%s: %v", label, err)
+	}
+	return set
+}
+
+func allChallenges(set *ChallengeSet) []xfield.XFieldElement {
+	var challenges []xfield.XFieldElement
+	for _, entry := range set.Entries() {
+		challenges = append(challenges, entry.Challenges...)
+	}
+	return challenges
+}
+
+func TestFinalizeSnapshotsChallenges(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	tr, _ := NewTranscript(testPhases)
+	set := verifyProof(t, tr, "proof", roots)
+
+	if set.Label() != "proof" {
+		t.Errorf("label %q", set.Label())
+	}
+	if !reflect.DeepEqual(allChallenges(set), runTranscript(t, roots)) {
+		t.Error("challenge set differs from the sampled challenges")
+	}
+	entries := set.Entries()
+	for i, phase := range testPhases {
+		if entries[i].Phase != phase {
+			t.Errorf("entry %d labeled %q, want %q", i, entries[i].Phase, phase)
+		}
+		if !reflect.DeepEqual(set.Challenges(phase), entries[i].Challenges) {
+			t.Errorf("Challenges(%q) differs from its entry", phase)
+		}
+	}
+	if set.Challenges("trace") != nil {
+		t.Error("challenges for an unknown phase")
+	}
+
+	// The set is a snapshot: mutating returned slices does not change it.
+	entries[0].Challenges[0] = xfield.Zero
+	if set.Entries()[0].Challenges[0] == xfield.Zero {
+		t.Error("Entries exposes the set's storage")
+	}
+}
+
+func TestTranscriptReuseAfterFinalize(t *testing.T) {
+	root := testDigests(1)[0]
+	tr, _ := NewTranscript(testPhases)
+	_ = tr.AbsorbRoot("main", root)
+	if _, err := tr.Finalize("proof"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tr.ChallengeAfter("main", 1); err == nil || !strings.Contains(err.Error(), "finalized") {
+		t.Errorf("sampling after Finalize: got %v", err)
+	}
+	if err := tr.AbsorbRoot("aux", root); err == nil || !strings.Contains(err.Error(), "finalized") {
+		t.Errorf("absorbing after Finalize: got %v", err)
+	}
+	if _, err := tr.Finalize("again"); err == nil {
+		t.Error("finalized twice")
+	}
+	if _, err := tr.Fork("child"); err == nil {
+		t.Error("forked a finalized transcript")
+	}
+}
+
+func TestForkedTranscriptsAreDomainSeparated(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	session, _ := NewTranscript(testPhases)
+	a, _ := session.Fork("proof-a")
+	setA := verifyProof(t, a, "proof-a", roots)
+	b, _ := session.Fork("proof-b")
+	setB := verifyProof(t, b, "proof-b", roots)
+
+	unforked := runTranscript(t, roots)
+	challengesA, challengesB := allChallenges(setA), allChallenges(setB)
+	for i := range challengesA {
+		if challengesA[i] == challengesB[i] || challengesA[i] == unforked[i] || challengesB[i] == unforked[i] {
+			t.Fatalf("challenge %d shared between differently labeled transcripts", i)
+		}
+	}
+}
+
+func TestForkDependsOnParentState(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	fork := func(parentRoot Digest) []xfield.XFieldElement {
+		parent, _ := NewTranscript(testPhases)
+		_ = parent.AbsorbRoot("main", parentRoot)
+		child, _ := parent.Fork("proof")
+		c, err := child.ChallengeAfter("main", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	if reflect.DeepEqual(fork(roots[0]), fork(roots[1])) {
+		t.Error("forks of different parent states agree")
+	}
+	if !reflect.DeepEqual(fork(roots[0]), fork(roots[0])) {
+		t.Error("forks of identical parent states differ")
+	}
+}
+
+// TestSequentialProofsGetDistinctChallenges models sharing one transcript
+// between two proofs verified in sequence: the second proof must either be
+// refused or get challenges of its own, unless it was explicitly forked from
+// identical inputs.
+func TestSequentialProofsGetDistinctChallenges(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+
+	// The original bug: proof B picks up the transcript proof A finished with.
+	shared, _ := NewTranscript(testPhases)
+	verifyProof(t, shared, "proof-a", roots)
+	if err := shared.AbsorbRoot("main", roots[0]); err == nil {
+		t.Fatal("proof B reused proof A's finalized transcript")
+	}
+
+	// Forking each proof from the session gives them distinct challenges,
+	// even for identical roots.
+	session, _ := NewTranscript(testPhases)
+	a, _ := session.Fork("proof-a")
+	setA := verifyProof(t, a, "proof-a", roots)
+	b, _ := session.Fork("proof-b")
+	setB := verifyProof(t, b, "proof-b", roots)
+	if reflect.DeepEqual(allChallenges(setA), allChallenges(setB)) {
+		t.Fatal("sequential proofs got identical challenges")
+	}
+
+	// Forking twice with the same label from the same state is the explicit
+	// way to get identical challenges.
+	again, _ := session.Fork("proof-a")
+	if !reflect.DeepEqual(allChallenges(verifyProof(t, again, "proof-a", roots)), allChallenges(setA)) {
+		t.Error("identical forks got different challenges")
+	}
+}
+
+func TestInterleavedForksAreRejected(t *testing.T) {
+	root := testDigests(1)[0]
+	session, _ := NewTranscript(testPhases)
+	a, _ := session.Fork("proof-a")
+	b, _ := session.Fork("proof-b")
+	_ = a.AbsorbRoot("main", root)
+	_ = b.AbsorbRoot("main", root)
+
+	if _, err := a.ChallengeAfter("main", 1); err != nil {
+		t.Fatal(err)
+	}
+	_, err := b.ChallengeAfter("main", 1)
+	if err == nil || !strings.Contains(err.Error(), `fork "proof-b" while fork "proof-a"`) {
+		t.Fatalf("interleaved sampling: got %v", err)
+	}
+
+	// A may continue; once it is finalized, B may sample.
+	if _, err := a.ChallengeAfter("main", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Finalize("proof-a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ChallengeAfter("main", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Forks of a different parent are independent.
+	other, _ := NewTranscript(testPhases)
+	c, _ := other.Fork("proof-c")
+	_ = c.AbsorbRoot("main", root)
+	if _, err := c.ChallengeAfter("main", 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestForkedParentIsRetired(t *testing.T) {
+	root := testDigests(1)[0]
+	session, _ := NewTranscript(testPhases)
+	_ = session.AbsorbRoot("main", root)
+	child, _ := session.Fork("proof")
+
+	if _, err := session.ChallengeAfter("main", 1); err == nil || !strings.Contains(err.Error(), "forked") {
+		t.Errorf("sampling from a forked parent: got %v", err)
+	}
+	if err := session.AbsorbRoot("aux", root); err == nil {
+		t.Error("absorbed into a forked parent")
+	}
+	if _, err := session.Fork("another"); err != nil {
+		t.Errorf("forking again: %v", err)
+	}
+
+	// The child inherits the absorbed phase.
+	if err := child.AbsorbRoot("main", root); err == nil {
+		t.Error("child absorbed a phase its parent had absorbed")
+	}
+	if _, err := child.ChallengeAfter("main", 1); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestForkAndFinalizeRejectEmptyLabels(t *testing.T) {
+	tr, _ := NewTranscript(testPhases)
+	if _, err := tr.Fork(""); err == nil {
+		t.Error("forked with an empty label")
+	}
+	if _, err := tr.Finalize(""); err == nil {
+		t.Error("finalized with an empty label")
+	}
+}
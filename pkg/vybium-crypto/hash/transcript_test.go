@@ -0,0 +1,154 @@
+package hash
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+var testPhases = []string{"main", "aux", "quotient", "fri-0", "fri-1"}
+
+// runTranscript absorbs the given roots phase by phase, sampling two
+// challenges after each, and returns all challenges.
+func runTranscript(t *testing.T, roots []Digest) []xfield.XFieldElement {
+	t.Helper()
+	transcript, err := NewTranscript(testPhases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var challenges []xfield.XFieldElement
+	for i, phase := range testPhases {
+		if err := transcript.AbsorbRoot(phase, roots[i]); err != nil {
+			t.Fatalf("absorbing %s: %v", phase, err)
+		}
+		c, err := transcript.ChallengeAfter(phase, 2)
+		if err != nil {
+			t.Fatalf("challenges after %s: %v", phase, err)
+		}
+		challenges = append(challenges, c...)
+	}
+	return challenges
+}
+
+func TestTranscriptDeterministic(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	first := runTranscript(t, roots)
+	if len(first) != 2*len(testPhases) {
+		t.Fatalf("got %d challenges", len(first))
+	}
+	if !reflect.DeepEqual(first, runTranscript(t, roots)) {
+		t.Error("identical flows gave different challenges")
+	}
+}
+
+// TestTranscriptGolden freezes the transcript layout.
+func TestTranscriptGolden(t *testing.T) {
+	if WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	challenges := runTranscript(t, testDigests(1, 2, 3, 4, 5))
+	if got := challenges[len(challenges)-1].String(); got != "(18214336019440682305·x² + 09100624906442434414·x + 07325803843708123543)" {
+		t.Errorf("last challenge: got %s", got)
+	}
+}
+
+func TestTranscriptRootChangesLaterChallenges(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	base := runTranscript(t, roots)
+
+	for changed := range testPhases {
+		modified := append([]Digest(nil), roots...)
+		modified[changed] = testDigests(100)[0]
+		challenges := runTranscript(t, modified)
+
+		// Challenges before the changed phase agree; all later ones differ
+		for i := range challenges {
+			phase := i / 2
+			if same := challenges[i] == base[i]; same != (phase < changed) {
+				t.Errorf("changing %s: challenge %d unchanged=%v", testPhases[changed], i, same)
+			}
+		}
+	}
+}
+
+func TestTranscriptPhaseLabelsMatter(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	base := runTranscript(t, roots)
+
+	renamed, _ := NewTranscript([]string{"main", "aux", "quotient", "fri-0", "fri-2"})
+	_ = renamed.AbsorbRoot("main", roots[0])
+	c, _ := renamed.ChallengeAfter("main", 2)
+	if c[0] == base[0] {
+		t.Error("declaring different phases did not change the challenges")
+	}
+}
+
+func TestTranscriptOrderingErrors(t *testing.T) {
+	root := testDigests(1)[0]
+
+	tests := []struct {
+		name   string
+		steps  func(*Transcript) error
+		errHas string
+	}{
+		{"out of order", func(tr *Transcript) error {
+			return tr.AbsorbRoot("aux", root)
+		}, `"aux" before phase "main"`},
+		{"duplicate", func(tr *Transcript) error {
+			_ = tr.AbsorbRoot("main", root)
+			return tr.AbsorbRoot("main", root)
+		}, `"main" already absorbed`},
+		{"unknown", func(tr *Transcript) error {
+			return tr.AbsorbRoot("trace", root)
+		}, `unknown transcript phase "trace"`},
+		{"challenge before absorb", func(tr *Transcript) error {
+			_, err := tr.ChallengeAfter("main", 1)
+			return err
+		}, `phase "main" before its root`},
+		{"challenge for missing phase", func(tr *Transcript) error {
+			_ = tr.AbsorbRoot("main", root)
+			_, err := tr.ChallengeAfter("aux", 1)
+			return err
+		}, `phase "aux" before its root`},
+		{"stale challenge", func(tr *Transcript) error {
+			_ = tr.AbsorbRoot("main", root)
+			_ = tr.AbsorbRoot("aux", root)
+			_, err := tr.ChallengeAfter("main", 1)
+			return err
+		}, `phase "main" once phase "aux"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, _ := NewTranscript(testPhases)
+			err := tt.steps(tr)
+			if err == nil || !strings.Contains(err.Error(), tt.errHas) {
+				t.Errorf("got error %v, want one containing %s", err, tt.errHas)
+			}
+		})
+	}
+}
+
+func TestTranscriptFailedAbsorbChangesNothing(t *testing.T) {
+	roots := testDigests(1, 2, 3, 4, 5)
+	tr, _ := NewTranscript(testPhases)
+	if err := tr.AbsorbRoot("aux", roots[1]); err == nil {
+		t.Fatal("expected error")
+	}
+	_ = tr.AbsorbRoot("main", roots[0])
+	c, _ := tr.ChallengeAfter("main", 2)
+	if !reflect.DeepEqual(c, runTranscript(t, roots)[:2]) {
+		t.Error("rejected absorb changed the transcript")
+	}
+}
+
+func TestNewTranscriptErrors(t *testing.T) {
+	for _, phases := range [][]string{nil, {"main", ""}, {"main", "aux", "main"}} {
+		if _, err := NewTranscript(phases); err == nil {
+			t.Errorf("expected error for phases %q", phases)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package codec defines the BFieldCodec interface, which package bfieldcodec
+// re-exports.
+//
+// It lives here, depending only on package field, so that packages below
+// bfieldcodec in the import graph can implement it: bfieldcodec imports
+// xfield, which imports polynomial.
+package codec
+
+import "github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+
+// BFieldCodec is bfieldcodec.BFieldCodec; see there.
+type BFieldCodec interface {
+	// Encode converts the value to a sequence of BFieldElement values.
+	Encode() []field.Element
+
+	// Decode creates a value from a sequence of BFieldElement values.
+	// Returns the decoded value (as BFieldCodec interface) and an error if the sequence is malformed.
+	// Callers should type-assert the result to the expected concrete type.
+	Decode(sequence []field.Element) (BFieldCodec, error)
+
+	// StaticLength returns the fixed length in BFieldElements if known at compile time.
+	// Returns nil for dynamic-length types (those with length prefixes).
+	StaticLength() *int
+}
@@ -0,0 +1,42 @@
+// Package lawgen generates random field elements for the lawtest checkers.
+//
+// It is separate from lawtest because it imports the field packages, whose
+// own law tests import lawtest. Packages that xfield imports, and xfield
+// itself, use it from external test packages.
+package lawgen
+
+import (
+	"math/rand"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// RandomBFE returns a random base field element, favouring edge cases.
+func RandomBFE(rng *rand.Rand) field.Element {
+	switch rng.Intn(6) {
+	case 0:
+		return field.Zero
+	case 1:
+		return field.One
+	case 2:
+		return field.Max
+	default:
+		return field.New(rng.Uint64())
+	}
+}
+
+// RandomXFE returns a random extension field element; constants and
+// elements with zero coefficients are common.
+func RandomXFE(rng *rand.Rand) xfield.XFieldElement {
+	switch rng.Intn(6) {
+	case 0:
+		return xfield.Zero
+	case 1:
+		return xfield.One
+	case 2:
+		return xfield.NewConst(RandomBFE(rng))
+	default:
+		return xfield.New([xfield.ExtensionDegree]field.Element{RandomBFE(rng), RandomBFE(rng), RandomBFE(rng)})
+	}
+}
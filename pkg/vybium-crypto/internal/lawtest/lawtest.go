@@ -0,0 +1,139 @@
+// Package lawtest checks algebraic laws against randomly generated values.
+//
+// A type is described once as a Ring, with a generator for random values;
+// the checkers then assert the laws that the rest of the stack relies on.
+// Every case draws its values from its own PRNG, seeded with the base seed
+// plus the case number, and failures report that seed, so a failing case can
+// be replayed on its own with Cases = 1.
+package lawtest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// DefaultCases is the number of cases each checker runs by default.
+const DefaultCases = 2000
+
+// Ring describes a commutative ring with identity.
+type Ring[T any] struct {
+	// Name identifies the ring in failure messages.
+	Name string
+
+	Zero, One T
+
+	Add   func(a, b T) T
+	Sub   func(a, b T) T
+	Mul   func(a, b T) T
+	Neg   func(a T) T
+	Equal func(a, b T) bool
+
+	// Random returns a random value; it should produce zero, one and other
+	// edge cases with noticeable probability.
+	Random func(rng *rand.Rand) T
+}
+
+// Config controls how many cases a checker runs and from which seed.
+type Config struct {
+	Seed  int64
+	Cases int
+}
+
+func (c Config) cases() int {
+	if c.Cases <= 0 {
+		return DefaultCases
+	}
+	return c.Cases
+}
+
+// ForEachCase calls check once per case with a PRNG seeded for that case.
+// It stops at the first failing case, whose seed check should include in its
+// messages.
+func ForEachCase(t testing.TB, config Config, check func(rng *rand.Rand, seed int64) bool) {
+	t.Helper()
+	for i := 0; i < config.cases(); i++ {
+		seed := config.Seed + int64(i)
+		if !check(rand.New(rand.NewSource(seed)), seed) {
+			return
+		}
+	}
+}
+
+// CheckRing asserts the commutative ring laws: associativity, commutativity
+// and identities of Add and Mul, additive inverses, Sub as Add of Neg, and
+// distributivity.
+func CheckRing[T any](t *testing.T, r Ring[T], config Config) {
+	t.Helper()
+	ForEachCase(t, config, func(rng *rand.Rand, seed int64) bool {
+		a, b, c := r.Random(rng), r.Random(rng), r.Random(rng)
+
+		laws := []struct {
+			name string
+			ok   bool
+		}{
+			{"(a+b)+c = a+(b+c)", r.Equal(r.Add(r.Add(a, b), c), r.Add(a, r.Add(b, c)))},
+			{"a+b = b+a", r.Equal(r.Add(a, b), r.Add(b, a))},
+			{"a+0 = a", r.Equal(r.Add(a, r.Zero), a)},
+			{"a+(-a) = 0", r.Equal(r.Add(a, r.Neg(a)), r.Zero)},
+			{"a-b = a+(-b)", r.Equal(r.Sub(a, b), r.Add(a, r.Neg(b)))},
+			{"(a·b)·c = a·(b·c)", r.Equal(r.Mul(r.Mul(a, b), c), r.Mul(a, r.Mul(b, c)))},
+			{"a·b = b·a", r.Equal(r.Mul(a, b), r.Mul(b, a))},
+			{"a·1 = a", r.Equal(r.Mul(a, r.One), a)},
+			{"a·0 = 0", r.Equal(r.Mul(a, r.Zero), r.Zero)},
+			{"a·(b+c) = a·b+a·c", r.Equal(r.Mul(a, r.Add(b, c)), r.Add(r.Mul(a, b), r.Mul(a, c)))},
+		}
+		for _, law := range laws {
+			if !law.ok {
+				t.Errorf("%s: %s fails for seed %d: a=%v b=%v c=%v", r.Name, law.name, seed, a, b, c)
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// CheckField asserts the ring laws plus multiplicative inverses, given the
+// ring's inverse function. Zero is skipped when checking inverses.
+func CheckField[T any](t *testing.T, r Ring[T], inverse func(a T) T, config Config) {
+	t.Helper()
+	CheckRing(t, r, config)
+	ForEachCase(t, config, func(rng *rand.Rand, seed int64) bool {
+		a := r.Random(rng)
+		if r.Equal(a, r.Zero) {
+			return true
+		}
+		if !r.Equal(r.Mul(a, inverse(a)), r.One) {
+			t.Errorf("%s: a·a⁻¹ = 1 fails for seed %d: a=%v", r.Name, seed, a)
+			return false
+		}
+		return true
+	})
+}
+
+// CheckHomomorphism asserts that f is a ring homomorphism from one ring to
+// another: it preserves Add, Mul and Neg and maps One to One.
+func CheckHomomorphism[A, B any](t *testing.T, name string, from Ring[A], to Ring[B], f func(A) B, config Config) {
+	t.Helper()
+	if !to.Equal(f(from.One), to.One) {
+		t.Errorf("%s: f(1) ≠ 1", name)
+	}
+	ForEachCase(t, config, func(rng *rand.Rand, seed int64) bool {
+		a, b := from.Random(rng), from.Random(rng)
+
+		laws := []struct {
+			name string
+			ok   bool
+		}{
+			{"f(a+b) = f(a)+f(b)", to.Equal(f(from.Add(a, b)), to.Add(f(a), f(b)))},
+			{"f(a·b) = f(a)·f(b)", to.Equal(f(from.Mul(a, b)), to.Mul(f(a), f(b)))},
+			{"f(-a) = -f(a)", to.Equal(f(from.Neg(a)), to.Neg(f(a)))},
+		}
+		for _, law := range laws {
+			if !law.ok {
+				t.Errorf("%s: %s fails for seed %d: a=%v b=%v", name, law.name, seed, a, b)
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,113 @@
+package merkle
+
+import (
+	"fmt"
+	"sort"
+)
+
+// nodeIndexSet is a set of MerkleTreeNodeIndex values.
+type nodeIndexSet map[MerkleTreeNodeIndex]struct{}
+
+func (s nodeIndexSet) add(index MerkleTreeNodeIndex) {
+	s[index] = struct{}{}
+}
+
+func (s nodeIndexSet) contains(index MerkleTreeNodeIndex) bool {
+	_, ok := s[index]
+	return ok
+}
+
+// difference returns the elements of s that are not in other.
+func (s nodeIndexSet) difference(other nodeIndexSet) nodeIndexSet {
+	result := make(nodeIndexSet, len(s))
+	for index := range s {
+		if !other.contains(index) {
+			result.add(index)
+		}
+	}
+	return result
+}
+
+// sortedDescending returns the elements of s in descending order.
+func (s nodeIndexSet) sortedDescending() []MerkleTreeNodeIndex {
+	indices := make([]MerkleTreeNodeIndex, 0, len(s))
+	for index := range s {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(a, b int) bool { return indices[a] > indices[b] })
+	return indices
+}
+
+// validateLeafIndices checks that numLeafs describes a supported Merkle tree
+// and that every leaf index lies within it.
+func validateLeafIndices(numLeafs uint64, leafIndices []MerkleTreeLeafIndex) error {
+	if numLeafs == 0 || numLeafs&(numLeafs-1) != 0 {
+		return fmt.Errorf("number of leafs must be a power of two, got %d", numLeafs)
+	}
+	if numLeafs > uint64(1)<<maxTreeHeight {
+		return fmt.Errorf("number of leafs %d exceeds maximum 2^%d", numLeafs, maxTreeHeight)
+	}
+	for _, leafIndex := range leafIndices {
+		if leafIndex >= numLeafs {
+			return fmt.Errorf("leaf index %d out of range [0, %d)", leafIndex, numLeafs)
+		}
+	}
+	return nil
+}
+
+// pathNodesAndSiblings returns the nodes on the paths from the given leafs
+// up to (but excluding) the root, and the siblings of those nodes.
+func pathNodesAndSiblings(numLeafs uint64, leafIndices []MerkleTreeLeafIndex) (onPath, siblings nodeIndexSet) {
+	onPath = make(nodeIndexSet)
+	siblings = make(nodeIndexSet)
+	for _, leafIndex := range leafIndices {
+		for nodeIndex := numLeafs + leafIndex; nodeIndex > RootIndex; nodeIndex /= 2 {
+			if onPath.contains(nodeIndex) {
+				// The rest of this path was already walked for another leaf
+				break
+			}
+			onPath.add(nodeIndex)
+			siblings.add(nodeIndex ^ 1)
+		}
+	}
+	return onPath, siblings
+}
+
+// AuthenticationStructureNodeIndices returns the indices of the nodes that
+// make up the de-duplicated authentication structure for the given leafs of
+// a tree with numLeafs leafs, in descending order. These are the siblings of
+// all nodes on the paths from the leafs to the root that cannot themselves be
+// computed from the revealed leafs.
+//
+// Both NewInclusionProof and MerkleTreeInclusionProof.Verify use this function,
+// so prover and verifier always agree on which digest belongs to which node.
+// This is a port of twenty-first's `authentication_structure_node_indices`.
+//
+// Duplicate leaf indices are allowed and have no effect. Returns an error if
+// numLeafs is not a power of two or if a leaf index is out of range.
+func AuthenticationStructureNodeIndices(numLeafs uint64, leafIndices []MerkleTreeLeafIndex) ([]MerkleTreeNodeIndex, error) {
+	if err := validateLeafIndices(numLeafs, leafIndices); err != nil {
+		return nil, err
+	}
+
+	onPath, siblings := pathNodesAndSiblings(numLeafs, leafIndices)
+	return siblings.difference(onPath).sortedDescending(), nil
+}
+
+// ComputableNodeIndices returns the indices of all nodes a verifier can
+// compute from the given leafs and their authentication structure, in
+// descending order: the revealed leafs and every node on their paths to the
+// root, including the root. It is empty if no leafs are given.
+//
+// Returns an error under the same conditions as AuthenticationStructureNodeIndices.
+func ComputableNodeIndices(numLeafs uint64, leafIndices []MerkleTreeLeafIndex) ([]MerkleTreeNodeIndex, error) {
+	if err := validateLeafIndices(numLeafs, leafIndices); err != nil {
+		return nil, err
+	}
+
+	onPath, _ := pathNodesAndSiblings(numLeafs, leafIndices)
+	if len(leafIndices) > 0 {
+		onPath.add(RootIndex)
+	}
+	return onPath.sortedDescending(), nil
+}
@@ -0,0 +1,206 @@
+package merkle
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// bruteForceAuthStructure returns the authentication structure node indices
+// by scanning every node: a node is needed iff its subtree contains no
+// revealed leaf while its sibling's subtree does.
+func bruteForceAuthStructure(numLeafs uint64, leafIndices []MerkleTreeLeafIndex) []MerkleTreeNodeIndex {
+	revealed := make(map[MerkleTreeLeafIndex]bool)
+	for _, leafIndex := range leafIndices {
+		revealed[leafIndex] = true
+	}
+
+	subtreeHasRevealedLeaf := func(nodeIndex MerkleTreeNodeIndex) bool {
+		first, last := nodeIndex, nodeIndex
+		for first < numLeafs {
+			first, last = 2*first, 2*last+1
+		}
+		for leafNode := first; leafNode <= last; leafNode++ {
+			if revealed[leafNode-numLeafs] {
+				return true
+			}
+		}
+		return false
+	}
+
+	indices := []MerkleTreeNodeIndex{}
+	for nodeIndex := 2*numLeafs - 1; nodeIndex > RootIndex; nodeIndex-- {
+		if !subtreeHasRevealedLeaf(nodeIndex) && subtreeHasRevealedLeaf(nodeIndex^1) {
+			indices = append(indices, nodeIndex)
+		}
+	}
+	return indices
+}
+
+func randomLeafIndices(rng *rand.Rand, numLeafs uint64) []MerkleTreeLeafIndex {
+	count := 1 + rng.Intn(int(numLeafs))
+	indices := make([]MerkleTreeLeafIndex, count)
+	for i := range indices {
+		indices[i] = uint64(rng.Int63n(int64(numLeafs)))
+	}
+	return indices
+}
+
+func TestAuthenticationStructureNodeIndicesMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1157))
+
+	for height := 0; height <= 8; height++ {
+		numLeafs := uint64(1) << height
+		for trial := 0; trial < 30; trial++ {
+			leafIndices := randomLeafIndices(rng, numLeafs)
+
+			got, err := AuthenticationStructureNodeIndices(numLeafs, leafIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := bruteForceAuthStructure(numLeafs, leafIndices)
+			if len(got) == 0 && len(want) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("height %d, leafs %v: got %v, want %v", height, leafIndices, got, want)
+			}
+		}
+	}
+}
+
+func TestAuthenticationStructureRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1157))
+
+	for height := 0; height <= 7; height++ {
+		numLeafs := 1 << height
+		tree, err := New(createTestLeafs(numLeafs))
+		if err != nil {
+			t.Fatalf("failed to create tree: %v", err)
+		}
+
+		for trial := 0; trial < 20; trial++ {
+			leafIndices := randomLeafIndices(rng, uint64(numLeafs))
+			proof, err := tree.NewInclusionProof(leafIndices)
+			if err != nil {
+				t.Fatalf("failed to create proof: %v", err)
+			}
+
+			indices, err := AuthenticationStructureNodeIndices(uint64(numLeafs), leafIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(proof.AuthenticationStructure) != len(indices) {
+				t.Fatalf("proof has %d digests, index list has %d", len(proof.AuthenticationStructure), len(indices))
+			}
+			for i, nodeIndex := range indices {
+				node, _ := tree.GetNode(nodeIndex)
+				if proof.AuthenticationStructure[i] != node {
+					t.Fatalf("digest %d is not node %d", i, nodeIndex)
+				}
+			}
+
+			if !proof.Verify(tree.Root()) {
+				t.Fatalf("height %d, leafs %v: proof does not verify", height, leafIndices)
+			}
+		}
+	}
+}
+
+func TestComputableNodeIndices(t *testing.T) {
+	rng := rand.New(rand.NewSource(1157))
+
+	for height := 0; height <= 6; height++ {
+		numLeafs := uint64(1) << height
+		for trial := 0; trial < 20; trial++ {
+			leafIndices := randomLeafIndices(rng, numLeafs)
+
+			computable, err := ComputableNodeIndices(numLeafs, leafIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			authIndices, _ := AuthenticationStructureNodeIndices(numLeafs, leafIndices)
+
+			// The two sets are disjoint, and together they determine every
+			// computable node: each non-leaf computable node has both
+			// children in the union
+			inUnion := make(map[MerkleTreeNodeIndex]bool)
+			for _, index := range computable {
+				inUnion[index] = true
+			}
+			for _, index := range authIndices {
+				if inUnion[index] {
+					t.Fatalf("node %d is both computable and in the authentication structure", index)
+				}
+				inUnion[index] = true
+			}
+			for _, index := range computable {
+				if index < numLeafs && (!inUnion[2*index] || !inUnion[2*index+1]) {
+					t.Fatalf("computable node %d has a child outside the union", index)
+				}
+			}
+			if computable[len(computable)-1] != RootIndex {
+				t.Fatal("root should be computable")
+			}
+		}
+	}
+
+	if indices, err := ComputableNodeIndices(8, nil); err != nil || len(indices) != 0 {
+		t.Errorf("expected no computable nodes without leafs, got %v, %v", indices, err)
+	}
+}
+
+func TestAuthenticationStructureNodeIndicesErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		numLeafs    uint64
+		leafIndices []MerkleTreeLeafIndex
+	}{
+		{"zero leafs", 0, nil},
+		{"not a power of two", 6, []MerkleTreeLeafIndex{0}},
+		{"too many leafs", 1 << 63, []MerkleTreeLeafIndex{0}},
+		{"leaf index out of range", 8, []MerkleTreeLeafIndex{8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AuthenticationStructureNodeIndices(tt.numLeafs, tt.leafIndices); err == nil {
+				t.Error("expected error")
+			}
+			if _, err := ComputableNodeIndices(tt.numLeafs, tt.leafIndices); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAuthenticationStructureNodeIndicesGolden(t *testing.T) {
+	data, err := os.ReadFile("testdata/auth_structure_node_indices.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var cases []struct {
+		NumLeafs    uint64                `json:"num_leafs"`
+		LeafIndices []MerkleTreeLeafIndex `json:"leaf_indices"`
+		NodeIndices []MerkleTreeNodeIndex `json:"node_indices"`
+	}
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	for _, c := range cases {
+		got, err := AuthenticationStructureNodeIndices(c.NumLeafs, c.LeafIndices)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) == 0 && len(c.NodeIndices) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.NodeIndices) {
+			t.Errorf("%d leafs, leafs %v: got %v, want %v", c.NumLeafs, c.LeafIndices, got, c.NodeIndices)
+		}
+	}
+}
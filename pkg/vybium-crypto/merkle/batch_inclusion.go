@@ -0,0 +1,129 @@
+package merkle
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// indexedNode is a node of a Merkle tree together with its index.
+type indexedNode struct {
+	index  MerkleTreeNodeIndex
+	digest hash.Digest
+}
+
+// VerifyBatchInclusion verifies that the indexed leafs belong to the tree of
+// the given height and root, using the de-duplicated authentication
+// structure NewInclusionProof produces for them. It accepts exactly the
+// proofs MerkleTreeInclusionProof.Verify accepts, and records the same
+// diagnostics, but hashes the paths with sorted slices rather than a
+// partial tree.
+//
+// A proof with no leafs, a leaf index out of range, two different digests
+// for one leaf index, or an authentication structure of the wrong length
+// never verifies.
+func VerifyBatchInclusion(root hash.Digest, treeHeight MerkleTreeHeight, indexedLeafs []LeafIndexDigestPair, authStructure []hash.Digest, opts ...VerifyOption) bool {
+	config := newVerifyConfig(opts)
+	if len(indexedLeafs) == 0 {
+		config.report(diagnostics.ComponentMerkle, diagnostics.ReasonNoLeafs, 0)
+		return false
+	}
+	numLeafs, err := heightToLeafCount(treeHeight)
+	if err != nil {
+		config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonTreeTooHigh, 0, uint64(maxTreeHeight), uint64(treeHeight))
+		return false
+	}
+
+	leafIndices := make([]MerkleTreeLeafIndex, len(indexedLeafs))
+	leafNodes := make([]indexedNode, len(indexedLeafs))
+	for i, pair := range indexedLeafs {
+		if pair.Index >= numLeafs {
+			config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonLeafIndexOutOfRange, pair.Index, numLeafs, pair.Index)
+			return false
+		}
+		leafIndices[i] = pair.Index
+		leafNodes[i] = indexedNode{numLeafs + pair.Index, pair.Digest}
+	}
+
+	// Sort the leafs by descending node index, keeping repeated leafs in
+	// input order, and drop repeats that agree with the first occurrence
+	slices.SortStableFunc(leafNodes, func(a, b indexedNode) int {
+		return cmp.Compare(b.index, a.index)
+	})
+	unique := leafNodes[:1]
+	for _, node := range leafNodes[1:] {
+		last := unique[len(unique)-1]
+		if node.index != last.index {
+			unique = append(unique, node)
+			continue
+		}
+		if !node.digest.Equal(last.digest) {
+			config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonConflictingLeaf, node.index, last.digest, node.digest)
+			return false
+		}
+	}
+
+	authIndices, _ := AuthenticationStructureNodeIndices(numLeafs, leafIndices)
+	if len(authIndices) != len(authStructure) {
+		config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonAuthStructureLength, 0, uint64(len(authIndices)), uint64(len(authStructure)))
+		return false
+	}
+
+	computedRoot, ok := hashToRoot(unique, authIndices, authStructure, &config)
+	if !ok {
+		return false
+	}
+	if !computedRoot.Equal(root) {
+		config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonRootMismatch, RootIndex, root, computedRoot)
+		return false
+	}
+	return true
+}
+
+// hashToRoot hashes the leaf nodes and the authentication structure, both
+// sorted by descending node index, up to the root. Every node is taken
+// from one of three queues in descending order: the leafs, the
+// authentication structure, and the parents computed so far, which are
+// produced in descending order too. A right child is therefore always
+// followed by its left sibling, unless the sibling is missing.
+func hashToRoot(leafs []indexedNode, authIndices []MerkleTreeNodeIndex, authStructure []hash.Digest, config *verifyConfig) (hash.Digest, bool) {
+	parents := make([]indexedNode, 0, len(leafs))
+	nextLeaf, nextAuth, nextParent := 0, 0, 0
+	next := func() indexedNode {
+		best := -1
+		var node indexedNode
+		if nextLeaf < len(leafs) {
+			best, node = 0, leafs[nextLeaf]
+		}
+		if nextAuth < len(authIndices) && (best < 0 || authIndices[nextAuth] > node.index) {
+			best, node = 1, indexedNode{authIndices[nextAuth], authStructure[nextAuth]}
+		}
+		if nextParent < len(parents) && (best < 0 || parents[nextParent].index > node.index) {
+			best, node = 2, parents[nextParent]
+		}
+		switch best {
+		case 0:
+			nextLeaf++
+		case 1:
+			nextAuth++
+		case 2:
+			nextParent++
+		}
+		return node
+	}
+
+	for {
+		right := next()
+		if right.index == RootIndex {
+			return right.digest, true
+		}
+		left := next()
+		if right.index%2 == 0 || left.index != right.index-1 {
+			config.report(diagnostics.ComponentMerkle, diagnostics.ReasonMissingNode, right.index^1)
+			return hash.Digest{}, false
+		}
+		parents = append(parents, indexedNode{right.index / 2, config.hashPair(left.digest, right.digest)})
+	}
+}
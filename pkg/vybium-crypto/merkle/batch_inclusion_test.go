@@ -0,0 +1,96 @@
+package merkle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestVerifyBatchInclusionMultiLeaf(t *testing.T) {
+	rng := rand.New(rand.NewSource(1291))
+	for height := MerkleTreeHeight(0); height <= 6; height++ {
+		numLeafs := 1 << height
+		leafs := createTestLeafs(numLeafs)
+		tree, err := New(leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for trial := 0; trial < 20; trial++ {
+			// Repeated indices are allowed
+			indices := make([]MerkleTreeLeafIndex, 1+rng.Intn(numLeafs+2))
+			for i := range indices {
+				indices[i] = MerkleTreeLeafIndex(rng.Intn(numLeafs))
+			}
+			proof, err := tree.NewInclusionProof(indices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyBatchInclusion(tree.Root(), height, proof.IndexedLeafs, proof.AuthenticationStructure) {
+				t.Fatalf("height %d, leafs %v: valid proof rejected", height, indices)
+			}
+			if height > 0 && VerifyBatchInclusion(leafs[0], height, proof.IndexedLeafs, proof.AuthenticationStructure) {
+				t.Fatalf("height %d, leafs %v: proof verified against a wrong root", height, indices)
+			}
+		}
+	}
+}
+
+func TestVerifyBatchInclusionRejectsTamperedAuthNode(t *testing.T) {
+	leafs := createTestLeafs(16)
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.NewInclusionProof([]MerkleTreeLeafIndex{1, 6, 7, 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range proof.AuthenticationStructure {
+		tampered := append([]hash.Digest(nil), proof.AuthenticationStructure...)
+		tampered[i] = leafs[15]
+		if VerifyBatchInclusion(tree.Root(), tree.Height(), proof.IndexedLeafs, tampered) {
+			t.Errorf("tampered authentication node %d accepted", i)
+		}
+	}
+
+	tamperedLeafs := append([]LeafIndexDigestPair(nil), proof.IndexedLeafs...)
+	tamperedLeafs[2].Digest = leafs[0]
+	if VerifyBatchInclusion(tree.Root(), tree.Height(), tamperedLeafs, proof.AuthenticationStructure) {
+		t.Error("tampered leaf accepted")
+	}
+}
+
+func TestVerifyBatchInclusionRejectsMalformedProofs(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.NewInclusionProof([]MerkleTreeLeafIndex{0, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, height := tree.Root(), tree.Height()
+	auth := proof.AuthenticationStructure
+
+	tests := []struct {
+		name   string
+		height MerkleTreeHeight
+		leafs  []LeafIndexDigestPair
+		auth   []hash.Digest
+	}{
+		{"no leafs", height, nil, auth},
+		{"auth structure too short", height, proof.IndexedLeafs, auth[:len(auth)-1]},
+		{"auth structure too long", height, proof.IndexedLeafs, append(append([]hash.Digest(nil), auth...), leafs[1])},
+		{"leaf index out of range", height, []LeafIndexDigestPair{{0, leafs[0]}, {8, leafs[5]}}, auth},
+		{"conflicting leafs", height, append(append([]LeafIndexDigestPair(nil), proof.IndexedLeafs...), LeafIndexDigestPair{5, leafs[4]}), auth},
+		{"wrong height", height + 1, proof.IndexedLeafs, auth},
+		{"tree too high", maxTreeHeight + 1, proof.IndexedLeafs, auth},
+	}
+	for _, tt := range tests {
+		if VerifyBatchInclusion(root, tt.height, tt.leafs, tt.auth) {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// BlindedLeafDomain is the domain label under which blinded leafs are hashed.
+// It is part of the leaf derivation; changing it changes every blinded leaf.
+const BlindedLeafDomain = "vybium/merkle/blinded-leaf"
+
+// Blinded trees commit to each value together with its leaf index and a
+// blinding digest, so that an opening can later be proven in a circuit
+// without revealing the index. The leaf at index i is
+//
+//	hash.HashVarlenDomain(BlindedLeafDomain, [lo(i), hi(i), value[0..5], blinding[0..5]])
+//
+// where lo and hi are the low and high 32 bits of i, as in
+// bfieldcodec.EncodeUint64. The 12-element input has a fixed length, so no
+// length prefix is needed.
+
+// BlindedLeaf returns the blinded leaf committing to value at the given index.
+func BlindedLeaf(index uint64, value, blinding hash.Digest) hash.Digest {
+	input := make([]field.Element, 0, 2+2*hash.DigestLen)
+	input = append(input, bfieldcodec.EncodeUint64(index)...)
+	input = append(input, value[:]...)
+	input = append(input, blinding[:]...)
+	return hash.HashVarlenDomain(BlindedLeafDomain, input)
+}
+
+// NewBlindedTree builds the Merkle tree whose leaf i is
+// BlindedLeaf(i, values[i], blindings[i]).
+// Returns an error if the slices differ in length, or if their length is not
+// a valid number of leafs for New.
+func NewBlindedTree(values, blindings []hash.Digest) (*MerkleTree, error) {
+	if len(values) != len(blindings) {
+		return nil, fmt.Errorf("got %d values and %d blindings", len(values), len(blindings))
+	}
+
+	leafs := make([]hash.Digest, len(values))
+	for i := range leafs {
+		leafs[i] = BlindedLeaf(uint64(i), values[i], blindings[i])
+	}
+	return New(leafs)
+}
+
+// BlindedLeafWitness is the private part of a blinded opening: what a
+// circuit needs to recompute the blinded leaf and check its path.
+type BlindedLeafWitness struct {
+	Index    MerkleTreeLeafIndex
+	Value    hash.Digest
+	Blinding hash.Digest
+}
+
+// BlindedOpening opens a blinded tree at one leaf. Leaf and Path are the
+// plain inclusion proof, which VerifyInclusionProof checks given the index;
+// Witness holds the index and the preimage of Leaf.
+type BlindedOpening struct {
+	Leaf    hash.Digest
+	Path    []hash.Digest
+	Witness BlindedLeafWitness
+}
+
+// OpenBlinded opens a tree built by NewBlindedTree(values, blindings) at the
+// given index.
+// Returns an error if the index is out of range, or if the tree's leaf does
+// not match the blinded leaf derived from values and blindings.
+func OpenBlinded(tree *MerkleTree, values, blindings []hash.Digest, index MerkleTreeLeafIndex) (*BlindedOpening, error) {
+	if index >= uint64(len(values)) || index >= uint64(len(blindings)) {
+		return nil, fmt.Errorf("leaf index %d out of range for %d values and %d blindings", index, len(values), len(blindings))
+	}
+
+	leaf, err := tree.GetLeaf(index)
+	if err != nil {
+		return nil, err
+	}
+	if leaf != BlindedLeaf(index, values[index], blindings[index]) {
+		return nil, fmt.Errorf("leaf %d does not commit to the given value and blinding", index)
+	}
+
+	path, err := tree.AuthenticationPath(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlindedOpening{
+		Leaf: leaf,
+		Path: path,
+		Witness: BlindedLeafWitness{
+			Index:    index,
+			Value:    values[index],
+			Blinding: blindings[index],
+		},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// testBlindings returns n blinding digests distinct from createTestLeafs.
+func testBlindings(n int) []hash.Digest {
+	blindings := make([]hash.Digest, n)
+	for i := range blindings {
+		blindings[i] = hash.HashVarlen([]field.Element{field.New(uint64(i)), field.New(1000)})
+	}
+	return blindings
+}
+
+// TestBlindedLeafGolden freezes the blinded leaf derivation for circuits.
+func TestBlindedLeafGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+	values := createTestLeafs(4)
+	blindings := testBlindings(4)
+
+	if got := BlindedLeaf(3, values[3], blindings[3]).Hex(); got != "b3fd9500746e495f43f7d7148ef1329c7354674907f03aeb01185707d83cb8cb4fef866980f3a5d2" {
+		t.Errorf("leaf 3: got %s", got)
+	}
+	if got := BlindedLeaf(1<<40+5, values[0], blindings[0]).Hex(); got != "243dbea9eb7329ce6eb7ac8ff47ef86c32f39699f27db653b21c8570fae80d9d3db209c396624b09" {
+		t.Errorf("leaf 2^40+5: got %s", got)
+	}
+
+	tree, err := NewBlindedTree(values, blindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.Root().Hex(); got != "1f5b356a789fccfeade35eb17980c2bcb524078a12db66c53490068d73783d2a3afc239250f89341" {
+		t.Errorf("root: got %s", got)
+	}
+}
+
+func TestBlindedLeafEncoding(t *testing.T) {
+	value := createTestLeafs(1)[0]
+	blinding := testBlindings(1)[0]
+
+	// Manual encoding: index limbs, value, blinding
+	input := []field.Element{field.New(5), field.New(1)}
+	input = append(input, value[:]...)
+	input = append(input, blinding[:]...)
+	if BlindedLeaf(1<<32+5, value, blinding) != hash.HashVarlenDomain(BlindedLeafDomain, input) {
+		t.Error("BlindedLeaf does not match the documented encoding")
+	}
+
+	// Every input is bound
+	leaf := BlindedLeaf(0, value, blinding)
+	if leaf == BlindedLeaf(1, value, blinding) ||
+		leaf == BlindedLeaf(0, blinding, value) ||
+		leaf == BlindedLeaf(0, value, value) {
+		t.Error("changing an input did not change the leaf")
+	}
+}
+
+func TestOpenBlinded(t *testing.T) {
+	values := createTestLeafs(8)
+	blindings := testBlindings(8)
+	tree, err := NewBlindedTree(values, blindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+
+	for i := uint64(0); i < 8; i++ {
+		opening, err := OpenBlinded(tree, values, blindings, i)
+		if err != nil {
+			t.Fatalf("leaf %d: %v", i, err)
+		}
+		w := opening.Witness
+		if w.Index != i || w.Value != values[i] || w.Blinding != blindings[i] {
+			t.Fatalf("leaf %d: wrong witness %+v", i, w)
+		}
+		if opening.Leaf != BlindedLeaf(w.Index, w.Value, w.Blinding) {
+			t.Fatalf("leaf %d: witness does not reproduce the leaf", i)
+		}
+		if !VerifyInclusionProof(root, w.Index, opening.Leaf, opening.Path) {
+			t.Fatalf("leaf %d: path does not verify", i)
+		}
+	}
+}
+
+func TestBlindedErrors(t *testing.T) {
+	values := createTestLeafs(4)
+	blindings := testBlindings(4)
+
+	if _, err := NewBlindedTree(values, blindings[:3]); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+	if _, err := NewBlindedTree(values[:3], blindings[:3]); err == nil {
+		t.Error("expected error for non-power-of-two length")
+	}
+
+	tree, _ := NewBlindedTree(values, blindings)
+	if _, err := OpenBlinded(tree, values, blindings, 4); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	if _, err := OpenBlinded(tree, values, testBlindings(5)[1:], 2); err == nil {
+		t.Error("expected error for wrong blindings")
+	}
+}
@@ -0,0 +1,129 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// CodewordLeafDomain is the domain label under which codeword leafs are hashed.
+// It is part of the commitment layout; changing it changes every root.
+const CodewordLeafDomain = "vybium/fri/codeword-leaf"
+
+// Codeword commitments lay out a FRI codeword of length n in a Merkle tree
+// with n/2 leafs. Leaf i, for i < n/2, commits to the pair
+//
+//	(codeword[i], codeword[i + n/2])
+//
+// so that a single authentication path opens both inputs of a fold. The leaf
+// digest is
+//
+//	hash.HashVarlenDomain(CodewordLeafDomain, [a.c0, a.c1, a.c2, b.c0, b.c1, b.c2])
+//
+// where a = codeword[i] and b = codeword[i + n/2]. Codeword indices i and
+// i + n/2 are opened through the same leaf.
+
+// CodewordLeaf returns the leaf digest committing to the pair (a, b).
+func CodewordLeaf(a, b xfield.XFieldElement) hash.Digest {
+	input := make([]field.Element, 0, 2*xfield.ExtensionDegree)
+	input = append(input, a.Coefficients[:]...)
+	input = append(input, b.Coefficients[:]...)
+	return hash.HashVarlenDomain(CodewordLeafDomain, input)
+}
+
+// CodewordLeafs returns the n/2 leaf digests of a codeword commitment.
+// Returns an error if the codeword length is not a power of two of at least 2.
+func CodewordLeafs(codeword []xfield.XFieldElement) ([]hash.Digest, error) {
+	return codewordLeafsInArena(codeword, nil)
+}
+
+// codewordLeafsInArena is CodewordLeafs with the result allocated from the
+// given arena, or on the heap if it is nil.
+func codewordLeafsInArena(codeword []xfield.XFieldElement, arena *hash.DigestArena) ([]hash.Digest, error) {
+	if err := validateCodewordLength(uint64(len(codeword))); err != nil {
+		return nil, err
+	}
+
+	half := len(codeword) / 2
+	leafs := arena.Alloc(half)
+	for i := range leafs {
+		leafs[i] = CodewordLeaf(codeword[i], codeword[i+half])
+	}
+	return leafs, nil
+}
+
+// CommitCodeword builds the Merkle tree committing to a codeword.
+// Returns an error if the codeword length is not a power of two of at least 2.
+func CommitCodeword(codeword []xfield.XFieldElement) (*MerkleTree, error) {
+	return CommitCodewordInArena(codeword, nil)
+}
+
+// CommitCodewordInArena is CommitCodeword with the leafs and the tree's nodes
+// allocated from the given arena; a nil arena allocates them on the heap.
+// The tree must not be used after the arena is released.
+func CommitCodewordInArena(codeword []xfield.XFieldElement, arena *hash.DigestArena) (*MerkleTree, error) {
+	leafs, err := codewordLeafsInArena(codeword, arena)
+	if err != nil {
+		return nil, err
+	}
+	return NewInArena(leafs, arena)
+}
+
+// CodewordLeafIndex maps a codeword index to the index of the leaf holding it.
+// Indices i and i + n/2 map to the same leaf.
+// Returns an error if n is not a valid codeword length or index >= n.
+func CodewordLeafIndex(n, index uint64) (MerkleTreeLeafIndex, error) {
+	if err := validateCodewordLength(n); err != nil {
+		return 0, err
+	}
+	if index >= n {
+		return 0, fmt.Errorf("codeword index %d out of range [0, %d)", index, n)
+	}
+	return index % (n / 2), nil
+}
+
+// OpenCodeword opens the codeword at the given index against its commitment
+// tree. It returns the pair held by the index's leaf, in leaf order: a is the
+// value at the leaf index and b the value n/2 positions later, regardless of
+// which of the two the index refers to. The path authenticates the leaf.
+func OpenCodeword(tree *MerkleTree, codeword []xfield.XFieldElement, index uint64) (a, b xfield.XFieldElement, path []hash.Digest, err error) {
+	n := uint64(len(codeword))
+	leafIndex, err := CodewordLeafIndex(n, index)
+	if err != nil {
+		return xfield.Zero, xfield.Zero, nil, err
+	}
+	if tree.NumLeafs() != n/2 {
+		return xfield.Zero, xfield.Zero, nil, fmt.Errorf("tree has %d leafs, codeword of length %d needs %d", tree.NumLeafs(), n, n/2)
+	}
+
+	path, err = tree.AuthenticationPath(leafIndex)
+	if err != nil {
+		return xfield.Zero, xfield.Zero, nil, err
+	}
+	return codeword[leafIndex], codeword[leafIndex+n/2], path, nil
+}
+
+// VerifyCodewordOpening verifies an opening produced by OpenCodeword for a
+// codeword of length n committed to by root. The pair (a, b) must be in leaf
+// order. Returns false if n is not a valid codeword length or index >= n.
+func VerifyCodewordOpening(root hash.Digest, n, index uint64, a, b xfield.XFieldElement, path []hash.Digest) bool {
+	leafIndex, err := CodewordLeafIndex(n, index)
+	if err != nil {
+		return false
+	}
+	if len(path) != bits.TrailingZeros64(n/2) {
+		return false
+	}
+	return VerifyInclusionProof(root, leafIndex, CodewordLeaf(a, b), path)
+}
+
+// validateCodewordLength checks that n is a power of two of at least 2.
+func validateCodewordLength(n uint64) error {
+	if n < 2 || n&(n-1) != 0 {
+		return fmt.Errorf("codeword length must be a power of two of at least 2, got %d", n)
+	}
+	return nil
+}
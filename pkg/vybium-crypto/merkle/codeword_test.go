@@ -0,0 +1,180 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// testCodeword returns the codeword with codeword[i] = i + (i+100)x + (i+200)x².
+func testCodeword(n int) []xfield.XFieldElement {
+	codeword := make([]xfield.XFieldElement, n)
+	for i := range codeword {
+		codeword[i] = xfield.New([xfield.ExtensionDegree]field.Element{
+			field.New(uint64(i)), field.New(uint64(i + 100)), field.New(uint64(i + 200)),
+		})
+	}
+	return codeword
+}
+
+// TestCodewordGolden freezes the commitment layout for cross-implementation use.
+func TestCodewordGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden roots assume the full Tip5 permutation")
+	}
+	codeword := testCodeword(8)
+
+	if got := CodewordLeaf(codeword[0], codeword[4]).Hex(); got != "95a190ed8a548b87c52ff81a8176ac73f3b308e59f3f23ca6500574707dd4360d4a036a98551c11a" {
+		t.Errorf("leaf 0: got %s", got)
+	}
+
+	tests := []struct {
+		n    int
+		root string
+	}{
+		{2, "e8b7040009b938556fd2d8285707cdb8984e2526006d42e3b40d13e2a25ff5ddf8fa1419c2645726"},
+		{8, "c5da87b2d481567ec649753101894b49d689ec79e65f133091e17574f49b5b43c759cda135c32d45"},
+	}
+	for _, tt := range tests {
+		tree, err := CommitCodeword(testCodeword(tt.n))
+		if err != nil {
+			t.Fatalf("n=%d: %v", tt.n, err)
+		}
+		if got := tree.Root().Hex(); got != tt.root {
+			t.Errorf("n=%d: got root %s, want %s", tt.n, got, tt.root)
+		}
+	}
+}
+
+func TestCommitCodewordLayout(t *testing.T) {
+	codeword := testCodeword(16)
+	tree, err := CommitCodeword(codeword)
+	if err != nil {
+		t.Fatalf("CommitCodeword failed: %v", err)
+	}
+	if tree.NumLeafs() != 8 {
+		t.Fatalf("got %d leafs, want 8", tree.NumLeafs())
+	}
+
+	for i := uint64(0); i < 8; i++ {
+		leaf, _ := tree.GetLeaf(i)
+		if leaf != CodewordLeaf(codeword[i], codeword[i+8]) {
+			t.Errorf("leaf %d does not commit to (codeword[%d], codeword[%d])", i, i, i+8)
+		}
+	}
+
+	// The pair is ordered
+	leaf, _ := tree.GetLeaf(0)
+	if leaf == CodewordLeaf(codeword[8], codeword[0]) {
+		t.Error("swapped pair gives the same leaf")
+	}
+}
+
+func TestCommitCodewordInvalidLength(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 6, 12} {
+		if _, err := CommitCodeword(testCodeword(n)); err == nil {
+			t.Errorf("expected error for codeword length %d", n)
+		}
+	}
+}
+
+func TestCodewordLeafIndexCanonicalization(t *testing.T) {
+	const n = 16
+	for index := uint64(0); index < n; index++ {
+		leafIndex, err := CodewordLeafIndex(n, index)
+		if err != nil {
+			t.Fatalf("index %d: %v", index, err)
+		}
+		if leafIndex != index%8 {
+			t.Errorf("index %d: got leaf %d, want %d", index, leafIndex, index%8)
+		}
+	}
+	if _, err := CodewordLeafIndex(n, n); err == nil {
+		t.Error("expected error for index n")
+	}
+	if _, err := CodewordLeafIndex(12, 0); err == nil {
+		t.Error("expected error for invalid length")
+	}
+}
+
+func TestOpenAndVerifyCodeword(t *testing.T) {
+	const n = 16
+	codeword := testCodeword(n)
+	tree, _ := CommitCodeword(codeword)
+	root := tree.Root()
+
+	for index := uint64(0); index < n; index++ {
+		a, b, path, err := OpenCodeword(tree, codeword, index)
+		if err != nil {
+			t.Fatalf("index %d: %v", index, err)
+		}
+
+		// Both halves open the same leaf, with the pair in leaf order
+		lower := index % (n / 2)
+		if !a.Equal(codeword[lower]) || !b.Equal(codeword[lower+n/2]) {
+			t.Errorf("index %d: opened the wrong pair", index)
+		}
+		if !VerifyCodewordOpening(root, n, index, a, b, path) {
+			t.Errorf("index %d: valid opening rejected", index)
+		}
+		if twin, _, _, _ := OpenCodeword(tree, codeword, (index+n/2)%n); !twin.Equal(a) {
+			t.Errorf("index %d and its twin open different leafs", index)
+		}
+
+		if VerifyCodewordOpening(root, n, index, b, a, path) {
+			t.Errorf("index %d: swapped pair accepted", index)
+		}
+		if VerifyCodewordOpening(root, n, (index+1)%n, a, b, path) {
+			t.Errorf("index %d: opening accepted at another index", index)
+		}
+	}
+}
+
+func TestVerifyCodewordOpeningRejectsMalformed(t *testing.T) {
+	const n = 8
+	codeword := testCodeword(n)
+	tree, _ := CommitCodeword(codeword)
+	root := tree.Root()
+	a, b, path, _ := OpenCodeword(tree, codeword, 1)
+
+	tests := []struct {
+		name  string
+		n     uint64
+		index uint64
+		path  int
+	}{
+		{"index out of range", n, n + 1, len(path)},
+		{"invalid length", 6, 1, len(path)},
+		{"short path", n, 1, len(path) - 1},
+		// A path for an 8-element codeword is too short for 16 elements
+		{"length mismatch", 2 * n, 1, len(path)},
+	}
+	for _, tt := range tests {
+		if VerifyCodewordOpening(root, tt.n, tt.index, a, b, path[:tt.path]) {
+			t.Errorf("%s: opening accepted", tt.name)
+		}
+	}
+}
+
+func TestOpenCodewordErrors(t *testing.T) {
+	codeword := testCodeword(8)
+	tree, _ := CommitCodeword(codeword)
+
+	if _, _, _, err := OpenCodeword(tree, codeword, 8); err == nil {
+		t.Error("expected error for index out of range")
+	}
+	if _, _, _, err := OpenCodeword(tree, testCodeword(16), 0); err == nil {
+		t.Error("expected error for codeword not matching the tree")
+	}
+}
+
+func BenchmarkCommitCodeword(b *testing.B) {
+	codeword := testCodeword(1 << 12)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = CommitCodeword(codeword)
+	}
+}
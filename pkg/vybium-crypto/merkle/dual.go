@@ -0,0 +1,285 @@
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Dual commitments support migrating a commitment scheme from one pair hash
+// to another. During the migration window every commitment is built under
+// both hashes, the primary one being migrated to and the secondary one
+// being migrated from. Verifiers first require both roots to check out,
+// and flip to PrimaryOnly once downstream systems have switched.
+//
+// Both trees are filled from a single copy of the leafs, inside one call to
+// BuildDual, so the two roots always commit to the same content.
+
+// PairHasher compresses two sibling digests into their parent's digest.
+// Tip5PairHasher, PairHasherFunc(hash.ArionHashPair) and *HashPairCache
+// implement it.
+type PairHasher interface {
+	HashPair(left, right hash.Digest) hash.Digest
+}
+
+// PairHasherFunc adapts a function to PairHasher.
+type PairHasherFunc func(left, right hash.Digest) hash.Digest
+
+// HashPair returns f(left, right).
+func (f PairHasherFunc) HashPair(left, right hash.Digest) hash.Digest {
+	return f(left, right)
+}
+
+// tip5PairHasher is the PairHasher of New, hash.HashPair.
+type tip5PairHasher struct{}
+
+func (tip5PairHasher) HashPair(left, right hash.Digest) hash.Digest {
+	return hash.HashPair(left, right)
+}
+
+// Tip5PairHasher is hash.HashPair, the pair hash of New. Trees built with it
+// take New's batched path and have New's roots.
+var Tip5PairHasher PairHasher = tip5PairHasher{}
+
+// Policy decides which roots of a dual commitment VerifyDual checks.
+type Policy int
+
+const (
+	// RequireBoth accepts an opening only if it verifies against both roots.
+	// Use it during the migration window.
+	RequireBoth Policy = iota
+	// RequireEither accepts an opening that verifies against either root.
+	RequireEither
+	// PrimaryOnly checks the primary root alone; the secondary path and
+	// root are ignored. Use it once the migration is complete.
+	PrimaryOnly
+)
+
+func (p Policy) String() string {
+	switch p {
+	case RequireBoth:
+		return "RequireBoth"
+	case RequireEither:
+		return "RequireEither"
+	case PrimaryOnly:
+		return "PrimaryOnly"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// DualCommitment names the primary and secondary pair hashes of a dual
+// commitment. Build and verify with the same DualCommitment.
+type DualCommitment struct {
+	Primary   PairHasher
+	Secondary PairHasher
+}
+
+// DualTree is a pair of Merkle trees over the same leafs, one per hash of
+// its DualCommitment.
+type DualTree struct {
+	commitment DualCommitment
+	primary    *MerkleTree
+	secondary  *MerkleTree
+}
+
+// DualOpening opens both trees of a DualTree at one leaf.
+type DualOpening struct {
+	Index         MerkleTreeLeafIndex
+	PrimaryPath   []hash.Digest
+	SecondaryPath []hash.Digest
+}
+
+// BuildDual builds the primary and secondary trees over the leafs.
+// Returns an error if either hasher is nil or the leafs are not a valid
+// number of leafs for New.
+func BuildDual(leafs []hash.Digest, primary, secondary PairHasher) (*DualTree, error) {
+	return DualCommitment{Primary: primary, Secondary: secondary}.Build(leafs)
+}
+
+// Build builds the primary and secondary trees over the leafs. The leafs
+// are copied once and both trees are filled from that copy.
+// Returns an error if either hasher is nil or the leafs are not a valid
+// number of leafs for New.
+func (c DualCommitment) Build(leafs []hash.Digest) (*DualTree, error) {
+	if c.Primary == nil || c.Secondary == nil {
+		return nil, fmt.Errorf("dual commitment needs a primary and a secondary hasher")
+	}
+	primaryNodes, err := initializeMerkleTreeNodes(leafs, nil)
+	if err != nil {
+		return nil, err
+	}
+	secondaryNodes := make([]hash.Digest, len(primaryNodes))
+	copy(secondaryNodes[len(leafs):], primaryNodes[len(leafs):])
+
+	return &DualTree{
+		commitment: c,
+		primary:    fillTreeWith(primaryNodes, len(leafs), c.Primary),
+		secondary:  fillTreeWith(secondaryNodes, len(leafs), c.Secondary),
+	}, nil
+}
+
+// fillTreeWith fills the internal nodes bottom-up with the given hasher.
+func fillTreeWith(nodes []hash.Digest, numLeafs int, hasher PairHasher) *MerkleTree {
+	if _, ok := hasher.(tip5PairHasher); ok {
+		tree, _ := sequentiallyFillTree(nodes, numLeafs)
+		return tree
+	}
+	for i := numLeafs - 1; i >= int(RootIndex); i-- {
+		nodes[i] = hasher.HashPair(nodes[2*i], nodes[2*i+1])
+	}
+	return &MerkleTree{nodes: nodes}
+}
+
+// Commitment returns the hashers the tree was built with.
+func (t *DualTree) Commitment() DualCommitment {
+	return t.commitment
+}
+
+// DualRoot returns the roots of the primary and secondary trees.
+func (t *DualTree) DualRoot() (primaryRoot, secondaryRoot hash.Digest) {
+	return t.primary.Root(), t.secondary.Root()
+}
+
+// NumLeafs returns the number of leafs of each tree.
+func (t *DualTree) NumLeafs() uint64 {
+	return t.primary.NumLeafs()
+}
+
+// GetLeaf returns the leaf at the given index, common to both trees.
+func (t *DualTree) GetLeaf(index MerkleTreeLeafIndex) (hash.Digest, error) {
+	return t.primary.GetLeaf(index)
+}
+
+// OpenDual returns the authentication paths of the leaf at the given index
+// in both trees.
+// Returns an error if the index is out of range.
+func (t *DualTree) OpenDual(index MerkleTreeLeafIndex) (*DualOpening, error) {
+	primaryPath, err := t.primary.AuthenticationPath(index)
+	if err != nil {
+		return nil, err
+	}
+	secondaryPath, err := t.secondary.AuthenticationPath(index)
+	if err != nil {
+		return nil, err
+	}
+	return &DualOpening{Index: index, PrimaryPath: primaryPath, SecondaryPath: secondaryPath}, nil
+}
+
+// VerifyDual verifies that leaf is at the given index under the dual
+// commitment's roots, checking the roots the policy requires. The opening
+// must be for the given index. Under RequireBoth and RequireEither its two
+// paths must have the same length, the trees having the same shape.
+// Returns false for a nil opening or an unknown policy.
+func (c DualCommitment) VerifyDual(primaryRoot, secondaryRoot hash.Digest, index MerkleTreeLeafIndex, leaf hash.Digest, proof *DualOpening, policy Policy) bool {
+	if proof == nil || proof.Index != index || c.Primary == nil {
+		return false
+	}
+	verifyPrimary := func() bool {
+		return VerifyInclusionProof(primaryRoot, index, leaf, proof.PrimaryPath, withPairHasher(c.Primary))
+	}
+	verifySecondary := func() bool {
+		return c.Secondary != nil &&
+			VerifyInclusionProof(secondaryRoot, index, leaf, proof.SecondaryPath, withPairHasher(c.Secondary))
+	}
+
+	switch policy {
+	case PrimaryOnly:
+		return verifyPrimary()
+	case RequireBoth, RequireEither:
+		if len(proof.PrimaryPath) != len(proof.SecondaryPath) {
+			return false
+		}
+		if policy == RequireBoth {
+			return verifyPrimary() && verifySecondary()
+		}
+		return verifyPrimary() || verifySecondary()
+	default:
+		return false
+	}
+}
+
+// withPairHasher makes verification compute node digests with the given
+// hasher.
+func withPairHasher(hasher PairHasher) VerifyOption {
+	return func(c *verifyConfig) {
+		c.hashPair = hasher.HashPair
+	}
+}
+
+// Encode returns the BFieldCodec encoding of the opening:
+//
+//	[index as u64 (2 elements, low limb first), n, primary path, m, secondary path]
+//
+// where n and m are the path lengths and each digest contributes its
+// hash.DigestLen elements.
+func (o *DualOpening) Encode() []field.Element {
+	encoding := make([]field.Element, 0, 4+hash.DigestLen*(len(o.PrimaryPath)+len(o.SecondaryPath)))
+	encoding = append(encoding, bfieldcodec.EncodeUint64(o.Index)...)
+	for _, path := range [][]hash.Digest{o.PrimaryPath, o.SecondaryPath} {
+		encoding = append(encoding, field.New(uint64(len(path))))
+		for _, digest := range path {
+			encoding = append(encoding, digest[:]...)
+		}
+	}
+	return encoding
+}
+
+// DecodeDualOpening decodes an opening from its encoding.
+// Returns an error if the sequence is malformed or a path is longer than
+// the tallest tree New builds.
+func DecodeDualOpening(sequence []field.Element) (*DualOpening, error) {
+	if len(sequence) < 2 {
+		return nil, fmt.Errorf("dual opening encoding too short: %d elements", len(sequence))
+	}
+	index, err := bfieldcodec.DecodeUint64(sequence[:2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid leaf index: %w", err)
+	}
+	rest := sequence[2:]
+
+	var paths [2][]hash.Digest
+	for i, name := range []string{"primary", "secondary"} {
+		if len(rest) == 0 {
+			return nil, fmt.Errorf("dual opening encoding ends before the %s path", name)
+		}
+		length := rest[0].Value()
+		if length > uint64(maxTreeHeight) {
+			return nil, fmt.Errorf("%s path of length %d exceeds maximum tree height %d", name, length, maxTreeHeight)
+		}
+		end := 1 + length*hash.DigestLen
+		if uint64(len(rest)) < end {
+			return nil, fmt.Errorf("%s path needs %d elements, got %d", name, end-1, len(rest)-1)
+		}
+		paths[i] = make([]hash.Digest, length)
+		hash.ElementsToDigestsInto(paths[i], rest[1:end])
+		rest = rest[end:]
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("dual opening encoding has %d trailing elements", len(rest))
+	}
+
+	return &DualOpening{Index: index, PrimaryPath: paths[0], SecondaryPath: paths[1]}, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, writing each element
+// of Encode as in MmrAccumulator.MarshalBinary.
+func (o *DualOpening) MarshalBinary() ([]byte, error) {
+	return marshalElements(o.Encode()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (o *DualOpening) UnmarshalBinary(data []byte) error {
+	sequence, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+	decoded, err := DecodeDualOpening(sequence)
+	if err != nil {
+		return err
+	}
+	*o = *decoded
+	return nil
+}
@@ -0,0 +1,300 @@
+package merkle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// patchedPairHasher stands in for the fixed hash being migrated to.
+var patchedPairHasher = PairHasherFunc(func(left, right hash.Digest) hash.Digest {
+	return hash.HashPairDomain("vybium/merkle/test-node", left, right)
+})
+
+// migration is the dual commitment of the tests: the patched hash is being
+// adopted and plain Tip5 phased out.
+var migration = DualCommitment{Primary: patchedPairHasher, Secondary: Tip5PairHasher}
+
+func mustBuildDual(t *testing.T, c DualCommitment, leafs []hash.Digest) *DualTree {
+	t.Helper()
+	tree, err := c.Build(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func mustOpenDual(t *testing.T, tree *DualTree, index MerkleTreeLeafIndex) *DualOpening {
+	t.Helper()
+	opening, err := tree.OpenDual(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opening
+}
+
+func TestBuildDualRoots(t *testing.T) {
+	leafs := createTestLeafs(16)
+	tree, err := BuildDual(leafs, patchedPairHasher, Tip5PairHasher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primaryRoot, secondaryRoot := tree.DualRoot()
+
+	plain, err := New(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secondaryRoot != plain.Root() {
+		t.Error("Tip5 tree root differs from New's")
+	}
+	if primaryRoot == secondaryRoot {
+		t.Error("primary and secondary roots coincide")
+	}
+
+	// The generic fill agrees with the batched one.
+	generic := mustBuildDual(t, DualCommitment{
+		Primary:   PairHasherFunc(func(l, r hash.Digest) hash.Digest { return hash.HashPair(l, r) }),
+		Secondary: PairHasherFunc(hash.ArionHashPair),
+	}, leafs)
+	if root, _ := generic.DualRoot(); root != plain.Root() {
+		t.Error("generic fill with Tip5 differs from New")
+	}
+}
+
+func TestBuildDualRejectsInvalidInput(t *testing.T) {
+	for _, n := range []int{0, 3} {
+		if _, err := migration.Build(createTestLeafs(n)); err == nil {
+			t.Errorf("%d leafs: expected an error", n)
+		}
+	}
+	if _, err := BuildDual(createTestLeafs(4), nil, Tip5PairHasher); err == nil {
+		t.Error("nil primary hasher: expected an error")
+	}
+	if _, err := BuildDual(createTestLeafs(4), Tip5PairHasher, nil); err == nil {
+		t.Error("nil secondary hasher: expected an error")
+	}
+}
+
+func TestBuildDualCopiesLeafs(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree := mustBuildDual(t, migration, leafs)
+	primaryRoot, secondaryRoot := tree.DualRoot()
+	leafs[3] = hash.ZeroDigest()
+
+	if p, s := tree.DualRoot(); p != primaryRoot || s != secondaryRoot {
+		t.Fatal("modifying the caller's leafs changed the roots")
+	}
+	leaf, err := tree.GetLeaf(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migration.VerifyDual(primaryRoot, secondaryRoot, 3, leaf, mustOpenDual(t, tree, 3), RequireBoth) {
+		t.Fatal("opening no longer verifies")
+	}
+}
+
+func TestVerifyDualPolicies(t *testing.T) {
+	rng := rand.New(rand.NewSource(1196))
+	leafs := createTestLeafs(32)
+	tree := mustBuildDual(t, migration, leafs)
+	primaryRoot, secondaryRoot := tree.DualRoot()
+	index := MerkleTreeLeafIndex(rng.Intn(len(leafs)))
+	opening := mustOpenDual(t, tree, index)
+
+	bumped := func(d hash.Digest) hash.Digest {
+		d[0] = d[0].Add(field.One)
+		return d
+	}
+	badPrimary := *opening
+	badPrimary.PrimaryPath = append([]hash.Digest(nil), opening.PrimaryPath...)
+	badPrimary.PrimaryPath[2] = bumped(badPrimary.PrimaryPath[2])
+	badSecondary := *opening
+	badSecondary.SecondaryPath = append([]hash.Digest(nil), opening.SecondaryPath...)
+	badSecondary.SecondaryPath[0] = bumped(badSecondary.SecondaryPath[0])
+
+	tests := []struct {
+		name    string
+		opening *DualOpening
+		want    map[Policy]bool
+	}{
+		{"valid", opening, map[Policy]bool{RequireBoth: true, RequireEither: true, PrimaryOnly: true}},
+		{"bad primary path", &badPrimary, map[Policy]bool{RequireBoth: false, RequireEither: true, PrimaryOnly: false}},
+		{"bad secondary path", &badSecondary, map[Policy]bool{RequireBoth: false, RequireEither: true, PrimaryOnly: true}},
+	}
+	for _, tt := range tests {
+		for policy, want := range tt.want {
+			if got := migration.VerifyDual(primaryRoot, secondaryRoot, index, leafs[index], tt.opening, policy); got != want {
+				t.Errorf("%s under %s: got %v, want %v", tt.name, policy, got, want)
+			}
+		}
+	}
+
+	for _, policy := range []Policy{RequireBoth, RequireEither, PrimaryOnly} {
+		if migration.VerifyDual(primaryRoot, secondaryRoot, index^1, leafs[index], opening, policy) {
+			t.Errorf("%s: accepted the opening for another index", policy)
+		}
+		if migration.VerifyDual(primaryRoot, secondaryRoot, index, leafs[index], nil, policy) {
+			t.Errorf("%s: accepted a nil opening", policy)
+		}
+		// Swapping the roots, or the hashers, breaks both checks.
+		if migration.VerifyDual(secondaryRoot, primaryRoot, index, leafs[index], opening, policy) {
+			t.Errorf("%s: accepted swapped roots", policy)
+		}
+		swapped := DualCommitment{Primary: migration.Secondary, Secondary: migration.Primary}
+		if swapped.VerifyDual(primaryRoot, secondaryRoot, index, leafs[index], opening, policy) {
+			t.Errorf("%s: accepted swapped hashers", policy)
+		}
+	}
+	if migration.VerifyDual(primaryRoot, secondaryRoot, index, leafs[index], opening, Policy(7)) {
+		t.Error("accepted an unknown policy")
+	}
+	if got := Policy(7).String(); got != "Policy(7)" {
+		t.Errorf("unknown policy prints as %q", got)
+	}
+}
+
+func TestVerifyDualRejectsMismatchedLeafs(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree := mustBuildDual(t, migration, leafs)
+	primaryRoot, secondaryRoot := tree.DualRoot()
+	opening := mustOpenDual(t, tree, 5)
+
+	for _, policy := range []Policy{RequireBoth, RequireEither, PrimaryOnly} {
+		if migration.VerifyDual(primaryRoot, secondaryRoot, 5, leafs[4], opening, policy) {
+			t.Errorf("%s: accepted a neighbouring leaf", policy)
+		}
+	}
+
+	// Roots over different content at the opened leaf pass only the
+	// policies that ignore the divergent tree. Divergence at other leafs is
+	// invisible to a single opening, which is why BuildDual fills both
+	// trees from one copy of the leafs.
+	otherLeafs := createTestLeafs(8)
+	otherLeafs[5] = hash.ZeroDigest()
+	other := mustBuildDual(t, migration, otherLeafs)
+	_, otherSecondaryRoot := other.DualRoot()
+	spliced := &DualOpening{
+		Index:         5,
+		PrimaryPath:   opening.PrimaryPath,
+		SecondaryPath: mustOpenDual(t, other, 5).SecondaryPath,
+	}
+	if migration.VerifyDual(primaryRoot, otherSecondaryRoot, 5, leafs[5], spliced, RequireBoth) {
+		t.Fatal("RequireBoth accepted roots over different leafs")
+	}
+	if !migration.VerifyDual(primaryRoot, otherSecondaryRoot, 5, leafs[5], spliced, PrimaryOnly) {
+		t.Fatal("PrimaryOnly checked the secondary path")
+	}
+
+	// Paths of different lengths cannot come from one dual tree.
+	uneven := *opening
+	uneven.SecondaryPath = opening.SecondaryPath[:len(opening.SecondaryPath)-1]
+	if migration.VerifyDual(primaryRoot, secondaryRoot, 5, leafs[5], &uneven, RequireEither) {
+		t.Error("RequireEither accepted paths of different lengths")
+	}
+}
+
+func TestDualOpeningEncoding(t *testing.T) {
+	tree := mustBuildDual(t, migration, createTestLeafs(16))
+	primaryRoot, secondaryRoot := tree.DualRoot()
+	leaf, _ := tree.GetLeaf(11)
+	opening := mustOpenDual(t, tree, 11)
+
+	encoding := opening.Encode()
+	if want := 4 + 8*hash.DigestLen; len(encoding) != want {
+		t.Fatalf("encoding has %d elements, want %d", len(encoding), want)
+	}
+	decoded, err := DecodeDualOpening(encoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migration.VerifyDual(primaryRoot, secondaryRoot, 11, leaf, decoded, RequireBoth) {
+		t.Fatal("decoded opening does not verify")
+	}
+
+	data, err := opening.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unmarshaled DualOpening
+	if err := unmarshaled.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !migration.VerifyDual(primaryRoot, secondaryRoot, 11, leaf, &unmarshaled, RequireBoth) {
+		t.Fatal("unmarshaled opening does not verify")
+	}
+
+	single := mustOpenDual(t, mustBuildDual(t, migration, createTestLeafs(1)), 0)
+	if decoded, err := DecodeDualOpening(single.Encode()); err != nil || len(decoded.PrimaryPath) != 0 {
+		t.Fatalf("single-leaf opening: %v, %v", decoded, err)
+	}
+}
+
+func TestDecodeDualOpeningRejectsMalformed(t *testing.T) {
+	tree := mustBuildDual(t, migration, createTestLeafs(4))
+	encoding := mustOpenDual(t, tree, 1).Encode()
+
+	tooLong := append([]field.Element(nil), encoding...)
+	tooLong[2] = field.New(uint64(maxTreeHeight) + 1)
+	badIndex := append([]field.Element(nil), encoding...)
+	badIndex[0] = field.New(1 << 32)
+	lengthOverrun := append([]field.Element(nil), encoding...)
+	lengthOverrun[2] = field.New(3)
+
+	for name, sequence := range map[string][]field.Element{
+		"empty":              nil,
+		"index only":         encoding[:2],
+		"missing secondary":  encoding[:3+2*hash.DigestLen],
+		"truncated":          encoding[:len(encoding)-1],
+		"trailing element":   append(append([]field.Element(nil), encoding...), field.Zero),
+		"path too long":      tooLong,
+		"non-u32 index limb": badIndex,
+		"length overrun":     lengthOverrun,
+	} {
+		if _, err := DecodeDualOpening(sequence); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	var opening DualOpening
+	if err := opening.UnmarshalBinary(make([]byte, 7)); err == nil {
+		t.Error("partial element: expected an error")
+	}
+}
+
+// TestDualMigrationFlow walks through a migration: during the overlap
+// verifiers require both roots, and afterwards flip to the new hash alone,
+// at which point producers may stop computing the old tree.
+func TestDualMigrationFlow(t *testing.T) {
+	leafs := createTestLeafs(64)
+	tree := mustBuildDual(t, migration, leafs)
+	newRoot, oldRoot := tree.DualRoot()
+
+	// Overlap: a verifier still on the old scheme keeps working unchanged,
+	// and migrated verifiers require both roots.
+	for index := MerkleTreeLeafIndex(0); index < tree.NumLeafs(); index += 7 {
+		opening := mustOpenDual(t, tree, index)
+		if !VerifyInclusionProof(oldRoot, index, leafs[index], opening.SecondaryPath) {
+			t.Fatalf("leaf %d: old verifier rejects the secondary path", index)
+		}
+		if !migration.VerifyDual(newRoot, oldRoot, index, leafs[index], opening, RequireBoth) {
+			t.Fatalf("leaf %d: rejected during the overlap", index)
+		}
+	}
+
+	// After the flip, openings carry only the new path.
+	opening := mustOpenDual(t, tree, 42)
+	newOnly := &DualOpening{Index: 42, PrimaryPath: opening.PrimaryPath}
+	if !migration.VerifyDual(newRoot, hash.ZeroDigest(), 42, leafs[42], newOnly, PrimaryOnly) {
+		t.Fatal("new-only opening rejected after the flip")
+	}
+	if migration.VerifyDual(newRoot, oldRoot, 42, leafs[42], newOnly, RequireBoth) {
+		t.Fatal("new-only opening accepted while both roots are required")
+	}
+	afterFlip := DualCommitment{Primary: patchedPairHasher}
+	if !afterFlip.VerifyDual(newRoot, hash.ZeroDigest(), 42, leafs[42], newOnly, PrimaryOnly) {
+		t.Fatal("verifier without the old hasher rejects a new-only opening")
+	}
+}
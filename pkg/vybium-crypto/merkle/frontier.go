@@ -0,0 +1,218 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Frontier is an incremental, append-only Merkle tree that keeps only the
+// right edge of the tree: O(log n) digests for n leafs.
+//
+// Its root is that of a single tree of height ceil(log2 n) whose leafs are
+// the appended leafs followed by empty leafs. An empty leaf is the zero
+// digest, and an empty subtree of height h has the root EmptySubtreeRoot(h):
+//
+//	EmptySubtreeRoot(0)   = hash.ZeroDigest()
+//	EmptySubtreeRoot(h+1) = hash.HashPair(EmptySubtreeRoot(h), EmptySubtreeRoot(h))
+//
+// After exactly 2^k appends the tree has no padding and the root equals that
+// of New over the same leafs. Unlike an MMR, whose peaks are bagged, a
+// Frontier always commits to one padded tree, so inclusion proofs against
+// its root are ordinary authentication paths.
+//
+// A Frontier is not safe for concurrent use.
+type Frontier struct {
+	numLeafs uint64
+
+	// left holds the peaks of the first numLeafs-1 leafs, highest first:
+	// the roots of the complete subtrees to the left of the last leaf.
+	left []hash.Digest
+
+	// last is the most recently appended leaf.
+	last hash.Digest
+}
+
+// NewFrontier returns an empty Frontier.
+func NewFrontier() *Frontier {
+	return &Frontier{}
+}
+
+// emptySubtreeRoots returns the roots of empty subtrees of heights 0 through
+// maxTreeHeight.
+var emptySubtreeRoots = sync.OnceValue(func() []hash.Digest {
+	roots := make([]hash.Digest, maxTreeHeight+1)
+	roots[0] = hash.ZeroDigest()
+	for h := 1; h < len(roots); h++ {
+		roots[h] = hash.HashPair(roots[h-1], roots[h-1])
+	}
+	return roots
+})
+
+// EmptySubtreeRoot returns the root of a subtree of the given height whose
+// leafs are all the zero digest. Panics if height exceeds the maximum tree
+// height of 62.
+func EmptySubtreeRoot(height MerkleTreeHeight) hash.Digest {
+	if height > maxTreeHeight {
+		panic(fmt.Sprintf("tree height %d exceeds maximum %d", height, maxTreeHeight))
+	}
+	return emptySubtreeRoots()[height]
+}
+
+// NumLeafs returns the number of appended leafs.
+func (f *Frontier) NumLeafs() uint64 {
+	return f.numLeafs
+}
+
+// Height returns the height of the padded tree, ceil(log2 NumLeafs()), and
+// zero for a Frontier with at most one leaf.
+func (f *Frontier) Height() MerkleTreeHeight {
+	if f.numLeafs == 0 {
+		return 0
+	}
+	return MerkleTreeHeight(bits.Len64(f.numLeafs - 1))
+}
+
+// Append adds a leaf. Returns an error if the Frontier already holds
+// 2^62 leafs.
+func (f *Frontier) Append(leaf hash.Digest) error {
+	if f.numLeafs == uint64(1)<<maxTreeHeight {
+		return fmt.Errorf("frontier is full: %d leafs", f.numLeafs)
+	}
+	if f.numLeafs > 0 {
+		// Fold the previous last leaf into the peaks exactly as an MMR
+		// append of it onto the first numLeafs-1 leafs would.
+		node := f.last
+		for count := f.numLeafs - 1; count&1 == 1; count >>= 1 {
+			node = hash.HashPair(f.left[len(f.left)-1], node)
+			f.left = f.left[:len(f.left)-1]
+		}
+		f.left = append(f.left, node)
+	}
+	f.last = leaf
+	f.numLeafs++
+	return nil
+}
+
+// Root returns the root of the padded tree. The root of an empty Frontier
+// is EmptySubtreeRoot(0).
+func (f *Frontier) Root() hash.Digest {
+	if f.numLeafs == 0 {
+		return EmptySubtreeRoot(0)
+	}
+	node := f.last
+	index := f.numLeafs - 1
+	for h := MerkleTreeHeight(0); h < f.Height(); h++ {
+		sibling := f.sibling(h)
+		if index&1 == 0 {
+			node = hash.HashPair(node, sibling)
+		} else {
+			node = hash.HashPair(sibling, node)
+		}
+		index >>= 1
+	}
+	return node
+}
+
+// WitnessForLast returns the authentication path of the most recently
+// appended leaf, at index NumLeafs()-1, against the current Root. The path
+// verifies with VerifyInclusionProof and is invalidated by the next Append.
+// Returns an error if the Frontier is empty.
+func (f *Frontier) WitnessForLast() ([]hash.Digest, error) {
+	if f.numLeafs == 0 {
+		return nil, fmt.Errorf("frontier has no leafs")
+	}
+	path := make([]hash.Digest, f.Height())
+	for h := range path {
+		path[h] = f.sibling(MerkleTreeHeight(h))
+	}
+	return path, nil
+}
+
+// sibling returns the sibling at the given height of the last leaf's
+// ancestor. Where that ancestor is a right child its sibling is a complete
+// subtree, and hence one of the left peaks; where it is a left child its
+// sibling holds no leafs yet.
+func (f *Frontier) sibling(height MerkleTreeHeight) hash.Digest {
+	index := f.numLeafs - 1
+	if index>>height&1 == 0 {
+		return EmptySubtreeRoot(height)
+	}
+	return f.left[bits.OnesCount64(index>>(height+1))]
+}
+
+// Serialize returns the compact state of the Frontier:
+//
+//	[numLeafs as u64 (2 elements, low limb first), left peaks..., last leaf]
+//
+// where each digest contributes its hash.DigestLen elements and there are
+// popcount(numLeafs-1) left peaks, highest first. An empty Frontier has
+// neither peaks nor a last leaf. Each element is written as its canonical
+// value in 8 little-endian bytes, as in MmrAccumulator.MarshalBinary.
+func (f *Frontier) Serialize() []byte {
+	encoding := bfieldcodec.EncodeUint64(f.numLeafs)
+	if f.numLeafs > 0 {
+		encoding = append(encoding, hash.DigestsToElements(f.left)...)
+		encoding = append(encoding, f.last[:]...)
+	}
+	data := make([]byte, 8*len(encoding))
+	for i, element := range encoding {
+		binary.LittleEndian.PutUint64(data[8*i:], element.Value())
+	}
+	return data
+}
+
+// DeserializeFrontier restores a Frontier from the output of Serialize.
+// Returns an error if the data is malformed or its number of digests does
+// not match its leaf count.
+func DeserializeFrontier(data []byte) (*Frontier, error) {
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("invalid data length %d: must be a multiple of 8", len(data))
+	}
+	sequence := make([]field.Element, len(data)/8)
+	for i := range sequence {
+		value := binary.LittleEndian.Uint64(data[8*i:])
+		if value >= field.P {
+			return nil, fmt.Errorf("element %d is not canonical: %d", i, value)
+		}
+		sequence[i] = field.New(value)
+	}
+	if len(sequence) < 2 {
+		return nil, fmt.Errorf("frontier encoding too short: %d elements", len(sequence))
+	}
+
+	numLeafs, err := bfieldcodec.DecodeUint64(sequence[:2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid leaf count: %w", err)
+	}
+	if numLeafs > uint64(1)<<maxTreeHeight {
+		return nil, fmt.Errorf("leaf count %d exceeds maximum %d", numLeafs, uint64(1)<<maxTreeHeight)
+	}
+
+	expectedDigests := 0
+	if numLeafs > 0 {
+		expectedDigests = bits.OnesCount64(numLeafs-1) + 1
+	}
+	body := sequence[2:]
+	if len(body) != expectedDigests*hash.DigestLen {
+		return nil, fmt.Errorf("frontier with %d leafs has %d elements of digests, expected %d", numLeafs, len(body), expectedDigests*hash.DigestLen)
+	}
+	if numLeafs == 0 {
+		return NewFrontier(), nil
+	}
+
+	digests, err := hash.ElementsToDigests(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Frontier{
+		numLeafs: numLeafs,
+		left:     digests[:len(digests)-1],
+		last:     digests[len(digests)-1],
+	}, nil
+}
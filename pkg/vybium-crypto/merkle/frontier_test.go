@@ -0,0 +1,290 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// frontierTestLeafs returns n distinct leafs, none of which is the empty
+// leaf.
+func frontierTestLeafs(n int) []hash.Digest {
+	return createTestLeafs(n + 1)[1:]
+}
+
+// paddedRoot is the reference for Frontier.Root: the root of New over the
+// leafs padded with empty leafs to the next power of two.
+func paddedRoot(t *testing.T, leafs []hash.Digest) hash.Digest {
+	t.Helper()
+	width := 1
+	for width < len(leafs) {
+		width *= 2
+	}
+	padded := make([]hash.Digest, width)
+	copy(padded, leafs)
+	tree, err := New(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree.Root()
+}
+
+func TestFrontierMatchesMerkleTreeAtPowersOfTwo(t *testing.T) {
+	leafs := frontierTestLeafs(1 << 10)
+	frontier := NewFrontier()
+	for n, leaf := range leafs {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		if !isPowerOfTwo(uint64(n + 1)) {
+			continue
+		}
+		tree, err := New(leafs[:n+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !frontier.Root().Equal(tree.Root()) {
+			t.Fatalf("%d leafs: frontier root differs from Merkle tree root", n+1)
+		}
+		if frontier.Height() != tree.Height() {
+			t.Fatalf("%d leafs: height %d, want %d", n+1, frontier.Height(), tree.Height())
+		}
+	}
+}
+
+func TestFrontierRootIsPaddedTreeRoot(t *testing.T) {
+	leafs := frontierTestLeafs(130)
+	frontier := NewFrontier()
+	if !frontier.Root().Equal(EmptySubtreeRoot(0)) {
+		t.Fatal("empty frontier root is not the empty leaf")
+	}
+	for n, leaf := range leafs {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		if frontier.NumLeafs() != uint64(n+1) {
+			t.Fatalf("NumLeafs = %d, want %d", frontier.NumLeafs(), n+1)
+		}
+		if !frontier.Root().Equal(paddedRoot(t, leafs[:n+1])) {
+			t.Fatalf("%d leafs: root differs from padded tree root", n+1)
+		}
+	}
+}
+
+func TestFrontierWitnessForLast(t *testing.T) {
+	frontier := NewFrontier()
+	if _, err := frontier.WitnessForLast(); err == nil {
+		t.Fatal("expected an error for an empty frontier")
+	}
+
+	leafs := frontierTestLeafs(300)
+	for n, leaf := range leafs {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		path, err := frontier.WitnessForLast()
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := frontier.Root()
+		if !VerifyInclusionProof(root, uint64(n), leaf, path) {
+			t.Fatalf("%d leafs: witness for the last leaf does not verify", n+1)
+		}
+		if n > 0 && VerifyInclusionProof(root, uint64(n), leafs[n-1], path) {
+			t.Fatalf("%d leafs: witness verifies the wrong leaf", n+1)
+		}
+	}
+}
+
+func TestFrontierWitnessMatchesMerkleTree(t *testing.T) {
+	leafs := frontierTestLeafs(64)
+	frontier := NewFrontier()
+	for _, leaf := range leafs {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := tree.AuthenticationPath(63)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := frontier.WitnessForLast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("path length %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("path node %d differs", i)
+		}
+	}
+}
+
+func TestFrontierSerializeRoundTrip(t *testing.T) {
+	leafs := frontierTestLeafs(100)
+	frontier := NewFrontier()
+	for n := 0; n <= len(leafs); n++ {
+		restored, err := DeserializeFrontier(frontier.Serialize())
+		if err != nil {
+			t.Fatalf("%d leafs: %v", n, err)
+		}
+		if restored.NumLeafs() != frontier.NumLeafs() {
+			t.Fatalf("%d leafs: restored NumLeafs = %d", n, restored.NumLeafs())
+		}
+		if !restored.Root().Equal(frontier.Root()) {
+			t.Fatalf("%d leafs: restored root differs", n)
+		}
+
+		// The restored frontier keeps growing in step with the original.
+		if n < len(leafs) {
+			for _, f := range []*Frontier{frontier, restored} {
+				if err := f.Append(leafs[n]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !restored.Root().Equal(frontier.Root()) {
+				t.Fatalf("%d leafs: roots diverge after appending to the restored frontier", n+1)
+			}
+		}
+	}
+}
+
+func TestFrontierSerializedSizeIsLogarithmic(t *testing.T) {
+	frontier := NewFrontier()
+	for _, leaf := range frontierTestLeafs(1000) {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 999 = 0b1111100111 has eight set bits: eight left peaks plus the last leaf.
+	want := 8 * (2 + 9*hash.DigestLen)
+	if got := len(frontier.Serialize()); got != want {
+		t.Fatalf("serialized size %d bytes, want %d", got, want)
+	}
+}
+
+func TestDeserializeFrontierRejectsMalformed(t *testing.T) {
+	frontier := NewFrontier()
+	for _, leaf := range frontierTestLeafs(5) {
+		if err := frontier.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	valid := frontier.Serialize()
+
+	nonCanonical := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(nonCanonical[16:], field.P)
+
+	wideLimb := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(wideLimb, 1<<32)
+
+	tooMany := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(tooMany[8:], 1<<31)
+
+	cases := map[string][]byte{
+		"empty":              nil,
+		"ragged":             valid[:len(valid)-1],
+		"missing digest":     valid[:len(valid)-8*hash.DigestLen],
+		"extra digest":       append(append([]byte(nil), valid...), valid[16:16+8*hash.DigestLen]...),
+		"non-canonical":      nonCanonical,
+		"wide limb":          wideLimb,
+		"too many leafs":     tooMany,
+		"digests when empty": append(NewFrontier().Serialize(), valid[16:16+8*hash.DigestLen]...),
+	}
+	for name, data := range cases {
+		if _, err := DeserializeFrontier(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFrontierAppendRejectsFullFrontier(t *testing.T) {
+	frontier := &Frontier{numLeafs: 1 << maxTreeHeight}
+	if err := frontier.Append(hash.ZeroDigest()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestEmptySubtreeRootGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden digests require full-round Tip5")
+	}
+	data, err := os.ReadFile("testdata/frontier_empty_subtree_roots.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var fixture struct {
+		Roots []string `json:"empty_subtree_roots"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	if len(fixture.Roots) != 33 {
+		t.Fatalf("fixture has %d roots, want 33", len(fixture.Roots))
+	}
+	for height, want := range fixture.Roots {
+		if got := EmptySubtreeRoot(MerkleTreeHeight(height)).Hex(); got != want {
+			t.Errorf("height %d: got %s, want %s", height, got, want)
+		}
+	}
+}
+
+func TestEmptySubtreeRootLadder(t *testing.T) {
+	if !EmptySubtreeRoot(0).IsZero() {
+		t.Fatal("empty leaf is not the zero digest")
+	}
+	for height := MerkleTreeHeight(1); height <= maxTreeHeight; height++ {
+		below := EmptySubtreeRoot(height - 1)
+		if !EmptySubtreeRoot(height).Equal(hash.HashPair(below, below)) {
+			t.Fatalf("height %d is not the hash of two empty subtrees", height)
+		}
+	}
+	tree, err := New(make([]hash.Digest, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tree.Root().Equal(EmptySubtreeRoot(4)) {
+		t.Fatal("root of 16 empty leafs differs from EmptySubtreeRoot(4)")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic above the maximum height")
+		}
+	}()
+	EmptySubtreeRoot(maxTreeHeight + 1)
+}
+
+func BenchmarkFrontierAppend(b *testing.B) {
+	leafs := frontierTestLeafs(1 << 12)
+	frontier := NewFrontier()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := frontier.Append(leafs[i%len(leafs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFrontierRoot(b *testing.B) {
+	frontier := NewFrontier()
+	for _, leaf := range frontierTestLeafs(1<<16 - 1) {
+		if err := frontier.Append(leaf); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = frontier.Root()
+	}
+}
@@ -0,0 +1,136 @@
+package merkle
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// hashPairCacheKey is the canonical byte encoding of left || right.
+type hashPairCacheKey = [2 * hash.DigestLen * 8]byte
+
+// hashPairCacheEntry is the value stored in the LRU list.
+type hashPairCacheEntry struct {
+	key    hashPairCacheKey
+	digest hash.Digest
+}
+
+// HashPairCache is a bounded, least-recently-used cache of hash.HashPair
+// results, for verifiers that check many proofs sharing internal nodes.
+// Pass it to Verify or VerifyInclusionProof with WithHashPairCache.
+//
+// The cache is purely an optimization: entries are only ever written from
+// actual hash.HashPair computations, so verification results are identical
+// with and without it. A HashPairCache is safe for concurrent use.
+type HashPairCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[hashPairCacheKey]*list.Element
+	order    *list.List // front is most recently used
+	hits     uint64
+	misses   uint64
+}
+
+// NewHashPairCache creates a cache holding at most capacity results.
+// Returns an error if capacity is not positive.
+func NewHashPairCache(capacity int) (*HashPairCache, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("cache capacity must be positive, got %d", capacity)
+	}
+	return &HashPairCache{
+		capacity: capacity,
+		entries:  make(map[hashPairCacheKey]*list.Element, capacity),
+		order:    list.New(),
+	}, nil
+}
+
+// HashPair returns hash.HashPair(left, right), from the cache if possible.
+func (c *HashPairCache) HashPair(left, right hash.Digest) hash.Digest {
+	var key hashPairCacheKey
+	leftBytes, rightBytes := left.ToBytes(), right.ToBytes()
+	copy(key[:len(leftBytes)], leftBytes[:])
+	copy(key[len(leftBytes):], rightBytes[:])
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.hits++
+		c.order.MoveToFront(element)
+		digest := element.Value.(*hashPairCacheEntry).digest
+		c.mu.Unlock()
+		return digest
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Hash outside the lock; concurrent misses on the same key compute the
+	// same digest, so whichever insert wins is correct
+	digest := hash.HashPair(left, right)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return digest
+	}
+	c.entries[key] = c.order.PushFront(&hashPairCacheEntry{key: key, digest: digest})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*hashPairCacheEntry).key)
+	}
+	return digest
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *HashPairCache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Len returns the number of cached results.
+func (c *HashPairCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Capacity returns the maximum number of cached results.
+func (c *HashPairCache) Capacity() int {
+	return c.capacity
+}
+
+// verifyConfig holds the settings applied by VerifyOptions.
+type verifyConfig struct {
+	hashPair    func(left, right hash.Digest) hash.Digest
+	diagnostics *diagnostics.Diagnostics
+}
+
+// VerifyOption configures Verify, VerifyInclusionProof and VerifyMembership.
+type VerifyOption func(*verifyConfig)
+
+// WithHashPairCache makes verification compute node digests through the
+// given cache. A nil cache is ignored.
+func WithHashPairCache(cache *HashPairCache) VerifyOption {
+	return func(c *verifyConfig) {
+		if cache != nil {
+			c.hashPair = cache.HashPair
+		}
+	}
+}
+
+// newVerifyConfig applies opts to the default configuration.
+func newVerifyConfig(opts []VerifyOption) verifyConfig {
+	config := verifyConfig{
+		hashPair: func(left, right hash.Digest) hash.Digest {
+			return hash.HashPair(left, right)
+		},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
@@ -0,0 +1,174 @@
+package merkle
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestNewHashPairCacheInvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		if _, err := NewHashPairCache(capacity); err == nil {
+			t.Errorf("expected error for capacity %d", capacity)
+		}
+	}
+}
+
+func TestHashPairCacheMatchesHashPair(t *testing.T) {
+	cache, _ := NewHashPairCache(16)
+	leafs := createTestLeafs(8)
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i+1 < len(leafs); i++ {
+			want := hash.Digest(hash.HashPair(leafs[i], leafs[i+1]))
+			if got := cache.HashPair(leafs[i], leafs[i+1]); got != want {
+				t.Fatalf("round %d, pair %d: cached result differs from HashPair", round, i)
+			}
+		}
+	}
+
+	// Order matters: (a, b) and (b, a) are different entries
+	if cache.HashPair(leafs[1], leafs[0]) == cache.HashPair(leafs[0], leafs[1]) {
+		t.Error("swapped pair returned the same digest")
+	}
+
+	hits, misses := cache.Stats()
+	if hits != 8 || misses != 8 {
+		t.Errorf("got %d hits and %d misses, want 8 and 8", hits, misses)
+	}
+}
+
+func TestVerifyWithCacheIsEquivalent(t *testing.T) {
+	rng := rand.New(rand.NewSource(1162))
+	tree, _ := New(createTestLeafs(64))
+	cache, _ := NewHashPairCache(32)
+	otherRoot := hash.Digest(hash.HashPair(tree.Root(), tree.Root()))
+
+	for trial := 0; trial < 100; trial++ {
+		leafIndices := randomLeafIndices(rng, tree.NumLeafs())
+		proof, err := tree.NewInclusionProof(leafIndices)
+		if err != nil {
+			t.Fatalf("failed to create proof: %v", err)
+		}
+
+		// Corrupt every third proof
+		if trial%3 == 0 && len(proof.AuthenticationStructure) > 0 {
+			proof.AuthenticationStructure[0] = proof.IndexedLeafs[0].Digest
+		}
+
+		for _, root := range []hash.Digest{tree.Root(), otherRoot} {
+			if proof.Verify(root) != proof.Verify(root, WithHashPairCache(cache)) {
+				t.Fatalf("trial %d: Verify differs with cache", trial)
+			}
+		}
+
+		leafIndex := leafIndices[0]
+		leaf, _ := tree.GetLeaf(leafIndex)
+		path, _ := tree.AuthenticationPath(leafIndex)
+		if trial%3 == 0 {
+			path[len(path)-1] = leaf
+		}
+		for _, root := range []hash.Digest{tree.Root(), otherRoot} {
+			plain := VerifyInclusionProof(root, leafIndex, leaf, path)
+			if plain != VerifyInclusionProof(root, leafIndex, leaf, path, WithHashPairCache(cache)) {
+				t.Fatalf("trial %d: VerifyInclusionProof differs with cache", trial)
+			}
+		}
+	}
+
+	if hits, _ := cache.Stats(); hits == 0 {
+		t.Error("expected cache hits across overlapping proofs")
+	}
+	if proof, _ := tree.NewInclusionProof([]MerkleTreeLeafIndex{3}); !proof.Verify(tree.Root(), WithHashPairCache(nil)) {
+		t.Error("nil cache should be ignored")
+	}
+}
+
+func TestHashPairCacheBoundedUnderChurn(t *testing.T) {
+	const capacity = 64
+	cache, _ := NewHashPairCache(capacity)
+	leafs := createTestLeafs(1024)
+
+	for i := 0; i+1 < len(leafs); i++ {
+		cache.HashPair(leafs[i], leafs[i+1])
+		if cache.Len() > capacity {
+			t.Fatalf("cache holds %d entries, capacity %d", cache.Len(), capacity)
+		}
+	}
+	if cache.Len() != capacity {
+		t.Errorf("got %d entries, want %d", cache.Len(), capacity)
+	}
+
+	// The most recent entry is retained, the oldest evicted
+	_, missesBefore := cache.Stats()
+	cache.HashPair(leafs[len(leafs)-2], leafs[len(leafs)-1])
+	if _, misses := cache.Stats(); misses != missesBefore {
+		t.Error("most recent entry was evicted")
+	}
+	cache.HashPair(leafs[0], leafs[1])
+	if _, misses := cache.Stats(); misses != missesBefore+1 {
+		t.Error("oldest entry was not evicted")
+	}
+}
+
+func TestHashPairCacheConcurrent(t *testing.T) {
+	cache, _ := NewHashPairCache(32)
+	tree, _ := New(createTestLeafs(64))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for trial := 0; trial < 50; trial++ {
+				proof, _ := tree.NewInclusionProof(randomLeafIndices(rng, tree.NumLeafs()))
+				if !proof.Verify(tree.Root(), WithHashPairCache(cache)) {
+					t.Error("valid proof rejected with shared cache")
+					return
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	if cache.Len() > cache.Capacity() {
+		t.Errorf("cache holds %d entries, capacity %d", cache.Len(), cache.Capacity())
+	}
+}
+
+func benchmarkOverlappingProofs(b *testing.B, cache *HashPairCache) {
+	rng := rand.New(rand.NewSource(1162))
+	tree, _ := New(createTestLeafs(1 << 10))
+	proofs := make([]*MerkleTreeInclusionProof, 1000)
+	for i := range proofs {
+		proofs[i], _ = tree.NewInclusionProof([]MerkleTreeLeafIndex{uint64(rng.Intn(1 << 10))})
+	}
+	root := tree.Root()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, proof := range proofs {
+			if !proof.Verify(root, WithHashPairCache(cache)) {
+				b.Fatal("proof rejected")
+			}
+		}
+	}
+	b.StopTimer()
+
+	if cache != nil {
+		hits, misses := cache.Stats()
+		b.ReportMetric(float64(hits)/float64(hits+misses), "hit-rate")
+	}
+}
+
+func BenchmarkVerifyOverlappingProofsNoCache(b *testing.B) {
+	benchmarkOverlappingProofs(b, nil)
+}
+
+func BenchmarkVerifyOverlappingProofsCache(b *testing.B) {
+	cache, _ := NewHashPairCache(1 << 12)
+	benchmarkOverlappingProofs(b, cache)
+}
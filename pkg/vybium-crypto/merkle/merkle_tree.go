@@ -3,7 +3,9 @@ package merkle
 import (
 	"fmt"
 	"math/bits"
+	"sync/atomic"
 
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
 )
 
@@ -33,8 +35,32 @@ const (
 // the inclusion of items in a set.
 // The tree can hold at most 2^62 leafs (height up to 62).
 // The hash function used is Tip5.
+//
+// A MerkleTree is immutable after construction except through its mutating
+// methods, UpdateLeaf and BuildLeafIndex. All other methods may be called
+// from any number of goroutines concurrently. Writers that must not disturb
+// concurrent readers derive new snapshots with WithUpdatedLeaf instead.
 type MerkleTree struct {
 	nodes []hash.Digest
+
+	// overlay holds the nodes of a tree derived by WithUpdatedLeaf that
+	// differ from nodes, as a persistent tree shared with the snapshots it
+	// was derived from; nil for trees that own their nodes outright.
+	// overlaySize is the number of nodes reachable from overlay.
+	overlay     *overlayNode
+	overlaySize int
+
+	// nodesShared is set once nodes is shared with a derived tree, after
+	// which nodes is never written again.
+	nodesShared atomic.Bool
+
+	// leafIndex is the optional reverse index built by BuildLeafIndex;
+	// nil until then.
+	leafIndex map[leafIndexKey][]MerkleTreeLeafIndex
+
+	// numLeafs is the number of leafs a tree built by NewPadded was given,
+	// before padding; zero for trees that hold exactly their leafs.
+	numLeafs uint64
 }
 
 // New builds a MerkleTree with the given leafs.
@@ -42,7 +68,14 @@ type MerkleTree struct {
 // - the number of leafs is zero
 // - the number of leafs is not a power of two
 func New(leafs []hash.Digest) (*MerkleTree, error) {
-	nodes, err := initializeMerkleTreeNodes(leafs)
+	return NewInArena(leafs, nil)
+}
+
+// NewInArena is New with the node array allocated from the given arena; a
+// nil arena allocates it on the heap, exactly as New does. The tree reads
+// the arena's memory, so it must not be used after the arena is released.
+func NewInArena(leafs []hash.Digest, arena *hash.DigestArena) (*MerkleTree, error) {
+	nodes, err := initializeMerkleTreeNodes(leafs, arena)
 	if err != nil {
 		return nil, err
 	}
@@ -52,20 +85,20 @@ func New(leafs []hash.Digest) (*MerkleTree, error) {
 }
 
 // initializeMerkleTreeNodes validates the input and initializes the node array.
-func initializeMerkleTreeNodes(leafs []hash.Digest) ([]hash.Digest, error) {
+func initializeMerkleTreeNodes(leafs []hash.Digest, arena *hash.DigestArena) ([]hash.Digest, error) {
 	numLeafs := len(leafs)
 
 	if numLeafs == 0 {
 		return nil, fmt.Errorf("cannot create Merkle tree with zero leafs")
 	}
 
-	if !isPowerOfTwo(uint32(numLeafs)) {
-		return nil, fmt.Errorf("number of leafs must be a power of two, got %d", numLeafs)
+	if _, err := leafCountToHeight(uint64(numLeafs)); err != nil {
+		return nil, err
 	}
 
 	// Tree needs space for: 1 (unused) + numLeafs (internal nodes) + numLeafs (leafs) - 1
 	// = 2 * numLeafs
-	nodes := make([]hash.Digest, 2*numLeafs)
+	nodes := arena.Alloc(2 * numLeafs)
 
 	// Copy leafs to the second half of the array
 	copy(nodes[numLeafs:], leafs)
@@ -74,25 +107,44 @@ func initializeMerkleTreeNodes(leafs []hash.Digest) ([]hash.Digest, error) {
 }
 
 // sequentiallyFillTree fills the tree by hashing pairs of nodes bottom-up.
+// Pairs are compressed in groups of hash.BatchWidth using the interleaved
+// hash.HashPairs4; the remainder of each layer uses the scalar hash.HashPair.
 func sequentiallyFillTree(nodes []hash.Digest, numRemainingNodes int) (*MerkleTree, error) {
 	for numRemainingNodes > 1 {
-		for i := 0; i < numRemainingNodes; i += 2 {
-			left := nodes[numRemainingNodes+i]
-			right := nodes[numRemainingNodes+i+1]
-			nodes[numRemainingNodes/2+i/2] = hash.HashPair(left, right)
-		}
+		hashLayerRange(nodes, numRemainingNodes, 0, numRemainingNodes)
 		numRemainingNodes /= 2
 	}
 
 	return &MerkleTree{nodes: nodes}, nil
 }
 
+// hashLayerRange hashes the children nodes[layerSize+start:layerSize+end)
+// of the layer of layerSize nodes in pairs into their parents
+// nodes[(layerSize+start)/2:(layerSize+end)/2). start and end must be even.
+func hashLayerRange(nodes []hash.Digest, layerSize, start, end int) {
+	i := start
+	for ; i+2*hash.BatchWidth <= end; i += 2 * hash.BatchWidth {
+		var left, right [hash.BatchWidth]hash.Digest
+		for lane := 0; lane < hash.BatchWidth; lane++ {
+			left[lane] = nodes[layerSize+i+2*lane]
+			right[lane] = nodes[layerSize+i+2*lane+1]
+		}
+		digests := hash.HashPairs4(left, right)
+		copy(nodes[layerSize/2+i/2:], digests[:])
+	}
+	for ; i < end; i += 2 {
+		left := nodes[layerSize+i]
+		right := nodes[layerSize+i+1]
+		nodes[layerSize/2+i/2] = hash.HashPair(left, right)
+	}
+}
+
 // Root returns the root of the Merkle tree.
 func (mt *MerkleTree) Root() hash.Digest {
 	if len(mt.nodes) == 0 {
 		return hash.ZeroDigest()
 	}
-	return mt.nodes[RootIndex]
+	return mt.node(RootIndex)
 }
 
 // Height returns the height of the Merkle tree.
@@ -102,12 +154,21 @@ func (mt *MerkleTree) Height() MerkleTreeHeight {
 	}
 	// nodes = 2 * numLeafs, so numLeafs = len(nodes) / 2
 	// height = log2(numLeafs)
-	numLeafs := len(mt.nodes) / 2
-	return uint32(bits.Len(uint(numLeafs)) - 1)
+	return MerkleTreeHeight(bits.TrailingZeros64(uint64(len(mt.nodes) / 2)))
 }
 
-// NumLeafs returns the number of leafs in the tree.
+// NumLeafs returns the number of leafs in the tree. For a tree built by
+// NewPadded, that is the number of leafs it was given, without the padding.
 func (mt *MerkleTree) NumLeafs() uint64 {
+	if mt.numLeafs != 0 {
+		return mt.numLeafs
+	}
+	return mt.paddedNumLeafs()
+}
+
+// paddedNumLeafs returns the number of leaf nodes in the tree, a power of
+// two, padding included.
+func (mt *MerkleTree) paddedNumLeafs() uint64 {
 	if len(mt.nodes) <= 1 {
 		return 0
 	}
@@ -125,23 +186,26 @@ func (mt *MerkleTree) Size() int {
 }
 
 // GetLeaf returns the leaf at the specified index.
+// On error the returned digest is meaningless; callers must check the error
+// rather than comparing the digest against hash.ZeroDigest.
 func (mt *MerkleTree) GetLeaf(index MerkleTreeLeafIndex) (hash.Digest, error) {
 	numLeafs := mt.NumLeafs()
 	if index >= numLeafs {
-		return hash.ZeroDigest(), fmt.Errorf("leaf index %d out of range [0, %d)", index, numLeafs)
+		return hash.Digest{}, fmt.Errorf("leaf index %d out of range [0, %d)", index, numLeafs)
 	}
 
 	// Leafs are stored in the second half of the nodes array
-	leafNodeIndex := numLeafs + index
-	return mt.nodes[leafNodeIndex], nil
+	leafNodeIndex := mt.paddedNumLeafs() + index
+	return mt.node(leafNodeIndex), nil
 }
 
 // GetNode returns the node at the specified node index.
+// As with GetLeaf, only the error return signals failure.
 func (mt *MerkleTree) GetNode(nodeIndex MerkleTreeNodeIndex) (hash.Digest, error) {
 	if nodeIndex >= uint64(len(mt.nodes)) || nodeIndex == 0 {
-		return hash.ZeroDigest(), fmt.Errorf("node index %d out of range [1, %d)", nodeIndex, len(mt.nodes))
+		return hash.Digest{}, fmt.Errorf("node index %d out of range [1, %d)", nodeIndex, len(mt.nodes))
 	}
-	return mt.nodes[nodeIndex], nil
+	return mt.node(nodeIndex), nil
 }
 
 // AuthenticationPath returns the authentication path (also called Merkle proof or witness)
@@ -157,13 +221,18 @@ func (mt *MerkleTree) AuthenticationPath(leafIndex MerkleTreeLeafIndex) ([]hash.
 	path := make([]hash.Digest, height)
 
 	// Start at the leaf node
-	nodeIndex := numLeafs + leafIndex
+	nodeIndex := mt.paddedNumLeafs() + leafIndex
 
-	// Walk up the tree, collecting sibling hashes
+	// Walk up the tree, collecting sibling hashes. Trees without an overlay
+	// read the node array directly, which keeps this loop copy-free.
 	for i := uint32(0); i < height; i++ {
 		// Get sibling index (flip the least significant bit)
 		siblingIndex := nodeIndex ^ 1
-		path[i] = mt.nodes[siblingIndex]
+		if mt.overlay == nil {
+			path[i] = mt.nodes[siblingIndex]
+		} else {
+			path[i] = mt.overlayDigest(siblingIndex)
+		}
 
 		// Move to parent
 		nodeIndex /= 2
@@ -174,7 +243,9 @@ func (mt *MerkleTree) AuthenticationPath(leafIndex MerkleTreeLeafIndex) ([]hash.
 
 // VerifyInclusionProof verifies that a leaf with the given digest is at the specified
 // index in a Merkle tree with the given root, using the provided authentication path.
-func VerifyInclusionProof(root hash.Digest, leafIndex MerkleTreeLeafIndex, leaf hash.Digest, authPath []hash.Digest) bool {
+func VerifyInclusionProof(root hash.Digest, leafIndex MerkleTreeLeafIndex, leaf hash.Digest, authPath []hash.Digest, opts ...VerifyOption) bool {
+	config := newVerifyConfig(opts)
+
 	// Recompute the root by hashing up the tree
 	currentHash := leaf
 	currentIndex := leafIndex
@@ -182,14 +253,24 @@ func VerifyInclusionProof(root hash.Digest, leafIndex MerkleTreeLeafIndex, leaf
 	for _, siblingHash := range authPath {
 		// If currentIndex is even, current is left child; otherwise, right child
 		if currentIndex%2 == 0 {
-			currentHash = hash.HashPair(currentHash, siblingHash)
+			currentHash = config.hashPair(currentHash, siblingHash)
 		} else {
-			currentHash = hash.HashPair(siblingHash, currentHash)
+			currentHash = config.hashPair(siblingHash, currentHash)
 		}
 		currentIndex /= 2
 	}
 
-	return currentHash.Equal(root)
+	if currentHash.Equal(root) {
+		return true
+	}
+
+	// A path shorter than the leaf index requires ends below the root
+	if leafIndex>>uint(len(authPath)) != 0 {
+		config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonAuthPathLength, leafIndex, uint64(bits.Len64(leafIndex)), uint64(len(authPath)))
+	} else {
+		config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonRootMismatch, RootIndex, root, currentHash)
+	}
+	return false
 }
 
 // MerkleTreeInclusionProof is a full inclusion proof for multiple leafs.
@@ -240,59 +321,50 @@ func (mt *MerkleTree) NewInclusionProof(leafIndices []MerkleTreeLeafIndex) (*Mer
 // buildAuthenticationStructure builds the de-duplicated authentication structure
 // for the given leaf indices.
 func (mt *MerkleTree) buildAuthenticationStructure(leafIndices []MerkleTreeLeafIndex) []hash.Digest {
-	numLeafs := mt.NumLeafs()
-	height := mt.Height()
-
-	// Track which nodes are revealed (either as leafs or in the authentication path)
-	revealed := make(map[MerkleTreeNodeIndex]bool)
-
-	// Mark all revealed leafs
-	for _, idx := range leafIndices {
-		nodeIndex := numLeafs + idx
-		revealed[nodeIndex] = true
-	}
+	// Leaf indices have been validated by the caller
+	nodeIndices, _ := AuthenticationStructureNodeIndices(mt.paddedNumLeafs(), leafIndices)
 
-	// Collect authentication path nodes
-	var authNodes []hash.Digest
-
-	// For each revealed leaf, walk up and collect siblings not already revealed
-	for _, leafIdx := range leafIndices {
-		nodeIndex := numLeafs + leafIdx
-
-		for level := uint32(0); level < height; level++ {
-			siblingIndex := nodeIndex ^ 1
-			parentIndex := nodeIndex / 2
-
-			// If sibling is not revealed and parent is needed
-			if !revealed[siblingIndex] {
-				authNodes = append(authNodes, mt.nodes[siblingIndex])
-				revealed[siblingIndex] = true
-			}
-
-			// Mark parent as revealed
-			revealed[parentIndex] = true
-			nodeIndex = parentIndex
-		}
+	authNodes := make([]hash.Digest, len(nodeIndices))
+	for i, nodeIndex := range nodeIndices {
+		authNodes[i] = mt.node(nodeIndex)
 	}
 
 	return authNodes
 }
 
 // Verify verifies the inclusion proof.
-func (proof *MerkleTreeInclusionProof) Verify(root hash.Digest) bool {
+// A proof that is malformed (leaf index out of range, conflicting leafs,
+// missing or surplus authentication structure) never verifies, regardless
+// of the root it is checked against.
+func (proof *MerkleTreeInclusionProof) Verify(root hash.Digest, opts ...VerifyOption) bool {
+	config := newVerifyConfig(opts)
 	if len(proof.IndexedLeafs) == 0 {
+		config.report(diagnostics.ComponentMerkle, diagnostics.ReasonNoLeafs, 0)
 		return false
 	}
 
 	// Build partial tree from the proof
-	partialTree := newPartialMerkleTree(proof.TreeHeight, proof.IndexedLeafs, proof.AuthenticationStructure)
+	partialTree, err := newPartialMerkleTree(proof.TreeHeight, proof.IndexedLeafs, proof.AuthenticationStructure, &config)
+	if err != nil {
+		return false
+	}
 
 	// Compute root from partial tree
-	computedRoot := partialTree.computeRoot()
+	computedRoot, ok := partialTree.computeRoot(&config)
+	if !ok {
+		return false
+	}
 
-	return computedRoot.Equal(root)
+	if !computedRoot.Equal(root) {
+		config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonRootMismatch, RootIndex, root, computedRoot)
+		return false
+	}
+	return true
 }
 
+// maxTreeHeight is the largest supported Merkle tree height.
+const maxTreeHeight MerkleTreeHeight = 62
+
 // partialMerkleTree is a helper for verifying inclusion proofs.
 type partialMerkleTree struct {
 	treeHeight  MerkleTreeHeight
@@ -301,98 +373,129 @@ type partialMerkleTree struct {
 }
 
 // newPartialMerkleTree creates a partial Merkle tree from the proof data.
-func newPartialMerkleTree(height MerkleTreeHeight, indexedLeafs []LeafIndexDigestPair, authStructure []hash.Digest) *partialMerkleTree {
+// Failures are reported to config's diagnostics, if any.
+func newPartialMerkleTree(height MerkleTreeHeight, indexedLeafs []LeafIndexDigestPair, authStructure []hash.Digest, config *verifyConfig) (*partialMerkleTree, error) {
+	if height > maxTreeHeight {
+		config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonTreeTooHigh, 0, uint64(maxTreeHeight), uint64(height))
+		return nil, fmt.Errorf("tree height %d exceeds maximum %d", height, maxTreeHeight)
+	}
+
 	nodes := make(map[MerkleTreeNodeIndex]hash.Digest)
 	leafIndices := make([]MerkleTreeLeafIndex, len(indexedLeafs))
 
-	numLeafs := uint64(1) << height
+	numLeafs, _ := heightToLeafCount(height)
 
 	// Add leafs
 	for i, pair := range indexedLeafs {
+		if pair.Index >= numLeafs {
+			config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonLeafIndexOutOfRange, pair.Index, numLeafs, pair.Index)
+			return nil, fmt.Errorf("leaf index %d out of range [0, %d)", pair.Index, numLeafs)
+		}
 		nodeIndex := numLeafs + pair.Index
+		if existing, exists := nodes[nodeIndex]; exists && !existing.Equal(pair.Digest) {
+			config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonConflictingLeaf, nodeIndex, existing, pair.Digest)
+			return nil, fmt.Errorf("conflicting digests for leaf index %d", pair.Index)
+		}
 		nodes[nodeIndex] = pair.Digest
 		leafIndices[i] = pair.Index
 	}
 
 	// Add authentication structure nodes
-	authIdx := 0
-	for _, leafIdx := range leafIndices {
-		nodeIndex := numLeafs + leafIdx
-
-		for level := uint32(0); level < height; level++ {
-			siblingIndex := nodeIndex ^ 1
-
-			// If sibling is not in nodes, take it from authStructure
-			if _, exists := nodes[siblingIndex]; !exists && authIdx < len(authStructure) {
-				nodes[siblingIndex] = authStructure[authIdx]
-				authIdx++
-			}
-
-			nodeIndex /= 2
-		}
+	authIndices, err := AuthenticationStructureNodeIndices(numLeafs, leafIndices)
+	if err != nil {
+		return nil, err
+	}
+	if len(authIndices) != len(authStructure) {
+		config.reportCounts(diagnostics.ComponentMerkle, diagnostics.ReasonAuthStructureLength, 0, uint64(len(authIndices)), uint64(len(authStructure)))
+		return nil, fmt.Errorf("authentication structure has %d digests, expected %d", len(authStructure), len(authIndices))
+	}
+	for i, nodeIndex := range authIndices {
+		nodes[nodeIndex] = authStructure[i]
 	}
 
 	return &partialMerkleTree{
 		treeHeight:  height,
 		leafIndices: leafIndices,
 		nodes:       nodes,
-	}
+	}, nil
 }
 
-// computeRoot computes the root from the partial tree.
-func (pt *partialMerkleTree) computeRoot() hash.Digest {
-	numLeafs := uint64(1) << pt.treeHeight
-
-	// Build up the tree level by level
-	for level := pt.treeHeight; level > 0; level-- {
-		levelStart := uint64(1) << level
+// computeRoot computes the root from the partial tree by hashing upwards
+// from the revealed leafs. Returns false if a required sibling is missing or
+// if a computed node contradicts a digest supplied by the proof; the cause is
+// reported to config's diagnostics, if any.
+func (pt *partialMerkleTree) computeRoot(config *verifyConfig) (hash.Digest, bool) {
+	numLeafs, _ := heightToLeafCount(pt.treeHeight)
 
-		for nodeIdx := levelStart; nodeIdx < 2*levelStart; nodeIdx += 2 {
-			left, leftExists := pt.nodes[nodeIdx]
-			right, rightExists := pt.nodes[nodeIdx+1]
-
-			if leftExists && rightExists {
-				parentIdx := nodeIdx / 2
-				pt.nodes[parentIdx] = hash.HashPair(left, right)
-			}
-		}
-	}
-
-	// Return root
-	if root, exists := pt.nodes[RootIndex]; exists {
-		return root
+	layer := make([]MerkleTreeNodeIndex, len(pt.leafIndices))
+	for i, leafIdx := range pt.leafIndices {
+		layer[i] = numLeafs + leafIdx
 	}
 
-	// Fallback: reconstruct from leafs
-	for _, leafIdx := range pt.leafIndices {
-		nodeIndex := numLeafs + leafIdx
-		currentHash := pt.nodes[nodeIndex]
-		currentIndex := leafIdx
+	// Build up the tree level by level, touching only nodes on the paths
+	// from the revealed leafs to the root
+	for level := uint32(0); level < pt.treeHeight; level++ {
+		visited := make(map[MerkleTreeNodeIndex]bool, len(layer))
+		parents := make([]MerkleTreeNodeIndex, 0, len(layer))
 
-		for level := uint32(0); level < pt.treeHeight; level++ {
-			siblingIndex := nodeIndex ^ 1
-			siblingHash, exists := pt.nodes[siblingIndex]
-			if !exists {
-				return hash.ZeroDigest()
+		for _, nodeIndex := range layer {
+			parentIndex := nodeIndex / 2
+			if visited[parentIndex] {
+				continue
 			}
-
-			if currentIndex%2 == 0 {
-				currentHash = hash.HashPair(currentHash, siblingHash)
-			} else {
-				currentHash = hash.HashPair(siblingHash, currentHash)
+			visited[parentIndex] = true
+
+			left, leftExists := pt.nodes[2*parentIndex]
+			right, rightExists := pt.nodes[2*parentIndex+1]
+			if !leftExists || !rightExists {
+				missing := 2 * parentIndex
+				if leftExists {
+					missing++
+				}
+				config.report(diagnostics.ComponentMerkle, diagnostics.ReasonMissingNode, missing)
+				return hash.Digest{}, false
 			}
 
-			nodeIndex /= 2
-			currentIndex /= 2
+			parent := config.hashPair(left, right)
+			if existing, exists := pt.nodes[parentIndex]; exists && !existing.Equal(parent) {
+				config.reportDigests(diagnostics.ComponentMerkle, diagnostics.ReasonNodeMismatch, parentIndex, existing, parent)
+				return hash.Digest{}, false
+			}
+			pt.nodes[parentIndex] = parent
+			parents = append(parents, parentIndex)
 		}
 
-		return currentHash
+		layer = parents
 	}
 
-	return hash.ZeroDigest()
+	root, exists := pt.nodes[RootIndex]
+	return root, exists
 }
 
 // isPowerOfTwo checks if a number is a power of two.
-func isPowerOfTwo(n uint32) bool {
+func isPowerOfTwo(n uint64) bool {
 	return n > 0 && (n&(n-1) == 0)
 }
+
+// leafCountToHeight returns the height of a tree with numLeafs leafs.
+// Returns an error if numLeafs is not a power of two or the tree would be
+// higher than maxTreeHeight.
+func leafCountToHeight(numLeafs uint64) (MerkleTreeHeight, error) {
+	if !isPowerOfTwo(numLeafs) {
+		return 0, fmt.Errorf("number of leafs must be a power of two, got %d", numLeafs)
+	}
+	height := MerkleTreeHeight(bits.TrailingZeros64(numLeafs))
+	if height > maxTreeHeight {
+		return 0, fmt.Errorf("tree height %d exceeds maximum %d", height, maxTreeHeight)
+	}
+	return height, nil
+}
+
+// heightToLeafCount returns the number of leafs of a tree of the given height.
+// Returns an error if the height exceeds maxTreeHeight.
+func heightToLeafCount(height MerkleTreeHeight) (uint64, error) {
+	if height > maxTreeHeight {
+		return 0, fmt.Errorf("tree height %d exceeds maximum %d", height, maxTreeHeight)
+	}
+	return uint64(1) << height, nil
+}
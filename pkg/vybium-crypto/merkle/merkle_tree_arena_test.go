@@ -0,0 +1,77 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestNewInArenaMatchesNew(t *testing.T) {
+	arena := hash.NewDigestArena(64)
+	for _, n := range []int{1, 2, 8, 32, 256} {
+		leafs := createTestLeafs(n)
+		want, err := New(leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Build twice, so the second tree reuses the first one's blocks
+		for round := 0; round < 2; round++ {
+			got, err := NewInArena(leafs, arena)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range want.nodes {
+				if got.nodes[i] != want.nodes[i] {
+					t.Fatalf("%d leafs, round %d: node %d differs", n, round, i)
+				}
+			}
+			arena.Release()
+		}
+	}
+
+	if _, err := NewInArena(createTestLeafs(3), arena); err == nil {
+		t.Error("NewInArena accepted 3 leafs")
+	}
+}
+
+func TestCommitCodewordInArenaMatchesCommitCodeword(t *testing.T) {
+	arena := hash.NewDigestArena(0)
+	for _, n := range []int{2, 8, 64} {
+		codeword := testCodeword(n)
+		want, err := CommitCodeword(codeword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := CommitCodewordInArena(codeword, arena)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Root() != want.Root() {
+			t.Errorf("codeword of length %d: roots differ", n)
+		}
+		arena.Release()
+	}
+}
+
+// The 2^20-leaf benchmarks compare heap allocations of repeated tree builds
+// with and without an arena; run them with -benchmem.
+func BenchmarkNew2Pow20(b *testing.B) {
+	leafs := createTestLeafs(1 << 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = New(leafs)
+	}
+}
+
+func BenchmarkNewInArena2Pow20(b *testing.B) {
+	leafs := createTestLeafs(1 << 20)
+	arena := hash.NewDigestArena(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = NewInArena(leafs, arena)
+		arena.Release()
+	}
+}
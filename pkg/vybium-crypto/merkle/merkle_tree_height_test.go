@@ -0,0 +1,129 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestIsPowerOfTwoWide(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want bool
+	}{
+		{0, false},
+		{1, true},
+		{1 << 31, true},
+		{1<<31 + 1, false},
+		{1 << 32, true},        // truncated to 0 by a uint32 conversion
+		{1<<32 + 1<<31, false}, // truncated to 2^31 by a uint32 conversion
+		{1<<32 + 1, false},     // truncated to 1 by a uint32 conversion
+		{1 << 63, true},
+		{^uint64(0), false},
+	}
+	for _, tt := range tests {
+		if got := isPowerOfTwo(tt.n); got != tt.want {
+			t.Errorf("isPowerOfTwo(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLeafCountHeightConversions(t *testing.T) {
+	for _, height := range []MerkleTreeHeight{0, 1, 30, 31, 32, 33, maxTreeHeight} {
+		numLeafs, err := heightToLeafCount(height)
+		if err != nil || numLeafs != uint64(1)<<height {
+			t.Fatalf("heightToLeafCount(%d) = %d, %v", height, numLeafs, err)
+		}
+		if got, err := leafCountToHeight(numLeafs); err != nil || got != height {
+			t.Errorf("leafCountToHeight(%d) = %d, %v, want %d", numLeafs, got, err, height)
+		}
+	}
+
+	if _, err := heightToLeafCount(maxTreeHeight + 1); err == nil {
+		t.Error("expected error for height above the maximum")
+	}
+	for _, numLeafs := range []uint64{0, 3, 1<<31 + 1, 1<<32 - 1, 1<<32 + 1<<31, uint64(1) << (maxTreeHeight + 1)} {
+		if _, err := leafCountToHeight(numLeafs); err == nil {
+			t.Errorf("expected error for %d leafs", numLeafs)
+		}
+	}
+}
+
+// syntheticProof returns a root and authentication path for a single leaf
+// of a tree of the given height, without materializing the tree.
+func syntheticProof(height MerkleTreeHeight, leafIndex MerkleTreeLeafIndex, leaf hash.Digest) (hash.Digest, []hash.Digest) {
+	path := make([]hash.Digest, height)
+	node := leaf
+	for i := range path {
+		path[i] = hash.HashVarlen([]field.Element{field.New(uint64(i))})
+		if (leafIndex>>i)&1 == 0 {
+			node = hash.HashPair(node, path[i])
+		} else {
+			node = hash.HashPair(path[i], node)
+		}
+	}
+	return node, path
+}
+
+// Proofs for trees straddling 2^31 and 2^32 leafs, whose leaf and node
+// indices do not fit in 32 bits.
+func TestVerifyLargeTreeMetadata(t *testing.T) {
+	leaf := createTestLeafs(1)[0]
+
+	for _, tt := range []struct {
+		height    MerkleTreeHeight
+		leafIndex MerkleTreeLeafIndex
+	}{
+		{31, 1<<31 - 1},
+		{32, 1<<31 + 5},
+		{32, 1<<32 - 1},
+		{33, 1<<32 + 7},
+		{maxTreeHeight, 1<<maxTreeHeight - 1},
+	} {
+		root, path := syntheticProof(tt.height, tt.leafIndex, leaf)
+
+		if !VerifyInclusionProof(root, tt.leafIndex, leaf, path) {
+			t.Errorf("height %d, leaf %d: VerifyInclusionProof rejected", tt.height, tt.leafIndex)
+		}
+		proof := &MerkleTreeInclusionProof{
+			TreeHeight:              tt.height,
+			IndexedLeafs:            []LeafIndexDigestPair{{Index: tt.leafIndex, Digest: leaf}},
+			AuthenticationStructure: path,
+		}
+		if !proof.Verify(root) {
+			t.Errorf("height %d, leaf %d: Verify rejected", tt.height, tt.leafIndex)
+		}
+
+		// The index truncated to 32 bits is a different leaf
+		if truncated := uint64(uint32(tt.leafIndex)); truncated != tt.leafIndex {
+			if VerifyInclusionProof(root, truncated, leaf, path) {
+				t.Errorf("height %d: truncated leaf index %d accepted", tt.height, truncated)
+			}
+		}
+	}
+}
+
+func TestMmrMathAbove32Bits(t *testing.T) {
+	leafCount := uint64(1<<32 + 1<<31 + 1)
+	heights := PeakHeights(leafCount)
+	if len(heights) != 3 || heights[0] != 32 || heights[1] != 31 || heights[2] != 0 {
+		t.Fatalf("PeakHeights(%d) = %v", leafCount, heights)
+	}
+
+	tests := []struct {
+		leafIndex uint64
+		mtIndex   MerkleTreeNodeIndex
+		peak      uint32
+	}{
+		{1<<32 - 1, 1<<33 - 1, 0},
+		{1<<32 + 5, 1<<31 + 5, 1},
+		{1<<32 + 1<<31, 1, 2},
+	}
+	for _, tt := range tests {
+		mtIndex, peak, err := LeafIndexToMtIndexAndPeakIndex(tt.leafIndex, leafCount)
+		if err != nil || mtIndex != tt.mtIndex || peak != tt.peak {
+			t.Errorf("leaf %d: got (%d, %d, %v), want (%d, %d)", tt.leafIndex, mtIndex, peak, err, tt.mtIndex, tt.peak)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package merkle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// leafIndexKey is the canonical byte encoding of a digest, used as the key
+// of the reverse leaf index.
+type leafIndexKey = [hash.DigestLen * 8]byte
+
+// BuildLeafIndex builds a reverse index from leaf digest to leaf positions,
+// making FindLeaf a map lookup instead of a linear scan. The index costs
+// memory proportional to the number of leafs and is therefore never built
+// implicitly. Calling BuildLeafIndex again rebuilds it from scratch.
+//
+// The index is not part of the tree's data: it is never serialized, and a
+// tree reconstructed from its leafs has no index until BuildLeafIndex is
+// called on it.
+//
+// Once BuildLeafIndex has returned, any number of goroutines may call
+// FindLeaf concurrently, provided none of them modifies the tree.
+func (mt *MerkleTree) BuildLeafIndex() {
+	numLeafs, firstLeaf := mt.NumLeafs(), mt.paddedNumLeafs()
+	index := make(map[leafIndexKey][]MerkleTreeLeafIndex, numLeafs)
+	for leafIndex := uint64(0); leafIndex < numLeafs; leafIndex++ {
+		key := mt.node(firstLeaf + leafIndex).ToBytes()
+		index[key] = append(index[key], leafIndex)
+	}
+	mt.leafIndex = index
+}
+
+// HasLeafIndex reports whether BuildLeafIndex has been called on the tree.
+func (mt *MerkleTree) HasLeafIndex() bool {
+	return mt.leafIndex != nil
+}
+
+// FindLeaf returns the positions of all leafs equal to the given digest, in
+// ascending order, and whether there is at least one. Without a leaf index
+// (see BuildLeafIndex) it falls back to scanning all leafs.
+// The returned slice is owned by the caller.
+func (mt *MerkleTree) FindLeaf(digest hash.Digest) ([]MerkleTreeLeafIndex, bool) {
+	if mt.leafIndex != nil {
+		positions, ok := mt.leafIndex[digest.ToBytes()]
+		if !ok {
+			return nil, false
+		}
+		return append([]MerkleTreeLeafIndex(nil), positions...), true
+	}
+
+	var positions []MerkleTreeLeafIndex
+	numLeafs, firstLeaf := mt.NumLeafs(), mt.paddedNumLeafs()
+	for leafIndex := uint64(0); leafIndex < numLeafs; leafIndex++ {
+		if mt.node(firstLeaf + leafIndex).Equal(digest) {
+			positions = append(positions, leafIndex)
+		}
+	}
+	return positions, len(positions) > 0
+}
+
+// UpdateLeaf replaces the leaf at the given index and recomputes the digests
+// on its path to the root. If a leaf index has been built, it is kept
+// coherent with the new leaf. UpdateLeaf modifies the tree and must not run
+// concurrently with any other method; see WithUpdatedLeaf for an update that
+// leaves the tree untouched.
+func (mt *MerkleTree) UpdateLeaf(index MerkleTreeLeafIndex, leaf hash.Digest) error {
+	numLeafs := mt.NumLeafs()
+	if index >= numLeafs {
+		return fmt.Errorf("leaf index %d out of range [0, %d)", index, numLeafs)
+	}
+
+	nodeIndex := mt.paddedNumLeafs() + index
+	if mt.leafIndex != nil {
+		mt.removeFromLeafIndex(mt.node(nodeIndex), index)
+		mt.insertIntoLeafIndex(leaf, index)
+	}
+
+	if mt.overlay != nil {
+		mt.updateOverlayPath(nodeIndex, leaf)
+		return nil
+	}
+
+	mt.ensureOwnNodes()
+	mt.nodes[nodeIndex] = leaf
+	for nodeIndex > RootIndex {
+		nodeIndex /= 2
+		mt.nodes[nodeIndex] = hash.HashPair(mt.nodes[2*nodeIndex], mt.nodes[2*nodeIndex+1])
+	}
+	return nil
+}
+
+// removeFromLeafIndex removes one position of a digest from the leaf index.
+func (mt *MerkleTree) removeFromLeafIndex(digest hash.Digest, index MerkleTreeLeafIndex) {
+	key := digest.ToBytes()
+	positions := mt.leafIndex[key]
+	i := sort.Search(len(positions), func(i int) bool { return positions[i] >= index })
+	if i == len(positions) || positions[i] != index {
+		return
+	}
+	if len(positions) == 1 {
+		delete(mt.leafIndex, key)
+		return
+	}
+	mt.leafIndex[key] = append(positions[:i:i], positions[i+1:]...)
+}
+
+// insertIntoLeafIndex adds a position of a digest to the leaf index, keeping
+// the positions sorted.
+func (mt *MerkleTree) insertIntoLeafIndex(digest hash.Digest, index MerkleTreeLeafIndex) {
+	key := digest.ToBytes()
+	positions := mt.leafIndex[key]
+	i := sort.Search(len(positions), func(i int) bool { return positions[i] >= index })
+
+	updated := make([]MerkleTreeLeafIndex, 0, len(positions)+1)
+	updated = append(updated, positions[:i]...)
+	updated = append(updated, index)
+	updated = append(updated, positions[i:]...)
+	mt.leafIndex[key] = updated
+}
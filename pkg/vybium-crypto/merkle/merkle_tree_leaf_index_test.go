@@ -0,0 +1,170 @@
+package merkle
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestFindLeafDuplicates(t *testing.T) {
+	leafs := createTestLeafs(8)
+	leafs[1] = leafs[6]
+	leafs[3] = leafs[6]
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+
+	// The scan fallback and the index must agree
+	for _, indexed := range []bool{false, true} {
+		if indexed {
+			tree.BuildLeafIndex()
+		}
+		if tree.HasLeafIndex() != indexed {
+			t.Fatalf("HasLeafIndex() = %v, want %v", tree.HasLeafIndex(), indexed)
+		}
+
+		positions, ok := tree.FindLeaf(leafs[6])
+		if !ok || !reflect.DeepEqual(positions, []MerkleTreeLeafIndex{1, 3, 6}) {
+			t.Errorf("indexed=%v: got %v, %v; want [1 3 6]", indexed, positions, ok)
+		}
+
+		positions, ok = tree.FindLeaf(leafs[0])
+		if !ok || !reflect.DeepEqual(positions, []MerkleTreeLeafIndex{0}) {
+			t.Errorf("indexed=%v: got %v, %v; want [0]", indexed, positions, ok)
+		}
+	}
+}
+
+func TestFindLeafAbsent(t *testing.T) {
+	tree, err := New(createTestLeafs(16))
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+	absent := hash.NewDigest([hash.DigestLen]field.Element{
+		field.New(99), field.New(99), field.New(99), field.New(99), field.New(99),
+	})
+
+	if positions, ok := tree.FindLeaf(absent); ok || len(positions) != 0 {
+		t.Errorf("scan: got %v, %v for absent leaf", positions, ok)
+	}
+	tree.BuildLeafIndex()
+	if positions, ok := tree.FindLeaf(absent); ok || len(positions) != 0 {
+		t.Errorf("index: got %v, %v for absent leaf", positions, ok)
+	}
+	// Internal nodes are not leafs
+	if _, ok := tree.FindLeaf(tree.Root()); ok {
+		t.Error("root should not be found as a leaf")
+	}
+}
+
+func TestFindLeafResultIsCopy(t *testing.T) {
+	leafs := createTestLeafs(4)
+	tree, _ := New(leafs)
+	tree.BuildLeafIndex()
+
+	positions, _ := tree.FindLeaf(leafs[2])
+	positions[0] = 0
+
+	if positions, _ := tree.FindLeaf(leafs[2]); positions[0] != 2 {
+		t.Error("modifying the result changed the index")
+	}
+}
+
+func TestUpdateLeafKeepsIndexCoherent(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, _ := New(leafs)
+	tree.BuildLeafIndex()
+
+	// Turn leaf 5 into a duplicate of leaf 2, then move it back
+	if err := tree.UpdateLeaf(5, leafs[2]); err != nil {
+		t.Fatalf("UpdateLeaf failed: %v", err)
+	}
+	if positions, ok := tree.FindLeaf(leafs[2]); !ok || !reflect.DeepEqual(positions, []MerkleTreeLeafIndex{2, 5}) {
+		t.Errorf("got %v, %v; want [2 5]", positions, ok)
+	}
+	if _, ok := tree.FindLeaf(leafs[5]); ok {
+		t.Error("replaced leaf should no longer be found")
+	}
+
+	if err := tree.UpdateLeaf(2, leafs[5]); err != nil {
+		t.Fatalf("UpdateLeaf failed: %v", err)
+	}
+	if positions, ok := tree.FindLeaf(leafs[2]); !ok || !reflect.DeepEqual(positions, []MerkleTreeLeafIndex{5}) {
+		t.Errorf("got %v, %v; want [5]", positions, ok)
+	}
+	if positions, ok := tree.FindLeaf(leafs[5]); !ok || !reflect.DeepEqual(positions, []MerkleTreeLeafIndex{2}) {
+		t.Errorf("got %v, %v; want [2]", positions, ok)
+	}
+
+	// The index matches a freshly built one, and the tree matches a fresh tree
+	leafs[2], leafs[5] = leafs[5], leafs[2]
+	expected, _ := New(leafs)
+	if tree.Root() != expected.Root() {
+		t.Error("root not updated correctly")
+	}
+	if err := tree.Validate(true); err != nil {
+		t.Errorf("tree invalid after UpdateLeaf: %v", err)
+	}
+	expected.BuildLeafIndex()
+	if !reflect.DeepEqual(tree.leafIndex, expected.leafIndex) {
+		t.Error("incrementally maintained index differs from rebuilt index")
+	}
+}
+
+func TestUpdateLeafOutOfRange(t *testing.T) {
+	tree, _ := New(createTestLeafs(4))
+	if err := tree.UpdateLeaf(4, hash.ZeroDigest()); err == nil {
+		t.Error("expected error for out-of-range leaf index")
+	}
+}
+
+func TestFindLeafConcurrentReads(t *testing.T) {
+	leafs := createTestLeafs(256)
+	tree, _ := New(leafs)
+	tree.BuildLeafIndex()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for i := 0; i < len(leafs); i++ {
+				leafIndex := (i + offset) % len(leafs)
+				positions, ok := tree.FindLeaf(leafs[leafIndex])
+				if !ok || len(positions) != 1 || positions[0] != uint64(leafIndex) {
+					t.Errorf("leaf %d: got %v, %v", leafIndex, positions, ok)
+					return
+				}
+			}
+		}(g * 31)
+	}
+	wg.Wait()
+}
+
+func benchmarkFindLeaf(b *testing.B, indexed bool) {
+	leafs := createTestLeafs(1 << 20)
+	tree, err := New(leafs)
+	if err != nil {
+		b.Fatalf("failed to create tree: %v", err)
+	}
+	if indexed {
+		tree.BuildLeafIndex()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = tree.FindLeaf(leafs[(i*7919)%len(leafs)])
+	}
+}
+
+func BenchmarkFindLeafIndexed(b *testing.B) {
+	benchmarkFindLeaf(b, true)
+}
+
+func BenchmarkFindLeafLinearScan(b *testing.B) {
+	benchmarkFindLeaf(b, false)
+}
@@ -0,0 +1,49 @@
+package merkle
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// PaddingDomain is the domain label under which PaddingDigest is hashed.
+const PaddingDomain = "vybium/merkle/padding"
+
+// paddingDigest is computed once, on first use.
+var paddingDigest = sync.OnceValue(func() hash.Digest {
+	return hash.HashVarlenDomain(PaddingDomain, nil)
+})
+
+// PaddingDigest returns the leaf NewPadded pads with: the labeled hash of
+// the empty sequence under PaddingDomain. Finding a leaf equal to it would
+// take a preimage of the hash, so padding is never mistaken for data.
+func PaddingDigest() hash.Digest {
+	return paddingDigest()
+}
+
+// NewPadded builds a MerkleTree with the given leafs followed by
+// PaddingDigest up to the next power of two. The tree reports the given
+// number of leafs from NumLeafs, and only those can be read, opened or
+// updated; the root, height and authentication paths are those of the
+// padded tree, so proofs for the given leafs verify as for any other tree.
+// Returns an error if the number of leafs is zero or the padded tree would
+// exceed the maximum height.
+func NewPadded(leafs []hash.Digest) (*MerkleTree, error) {
+	padded := leafs
+	if numLeafs := len(leafs); numLeafs > 1 && numLeafs&(numLeafs-1) != 0 {
+		padded = make([]hash.Digest, 1<<bits.Len(uint(numLeafs-1)))
+		copy(padded, leafs)
+		padding := PaddingDigest()
+		for i := numLeafs; i < len(padded); i++ {
+			padded[i] = padding
+		}
+	}
+
+	tree, err := New(padded)
+	if err != nil {
+		return nil, err
+	}
+	tree.numLeafs = uint64(len(leafs))
+	return tree, nil
+}
@@ -0,0 +1,151 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestNewPadded(t *testing.T) {
+	tests := []struct {
+		numLeafs int
+		height   MerkleTreeHeight
+	}{
+		{1, 0},
+		{3, 2},
+		{4, 2},
+		{5, 3},
+		{13, 4},
+	}
+	for _, tt := range tests {
+		leafs := createTestLeafs(tt.numLeafs)
+		tree, err := NewPadded(leafs)
+		if err != nil {
+			t.Fatalf("%d leafs: %v", tt.numLeafs, err)
+		}
+		if tree.NumLeafs() != uint64(tt.numLeafs) {
+			t.Errorf("%d leafs: NumLeafs %d", tt.numLeafs, tree.NumLeafs())
+		}
+		if tree.Height() != tt.height {
+			t.Errorf("%d leafs: height %d, want %d", tt.numLeafs, tree.Height(), tt.height)
+		}
+
+		// The root is that of the leafs padded by hand
+		padded := append([]hash.Digest(nil), leafs...)
+		for len(padded) < 1<<tt.height {
+			padded = append(padded, PaddingDigest())
+		}
+		reference, err := New(padded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Root() != reference.Root() {
+			t.Errorf("%d leafs: root differs from the hand-padded tree", tt.numLeafs)
+		}
+
+		for i, leaf := range leafs {
+			index := MerkleTreeLeafIndex(i)
+			if got, err := tree.GetLeaf(index); err != nil || got != leaf {
+				t.Errorf("%d leafs: GetLeaf(%d) = %v, %v", tt.numLeafs, i, got, err)
+			}
+			path, err := tree.AuthenticationPath(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyInclusionProof(tree.Root(), index, leaf, path) {
+				t.Errorf("%d leafs: path for leaf %d does not verify", tt.numLeafs, i)
+			}
+		}
+
+		indices := []MerkleTreeLeafIndex{0, uint64(tt.numLeafs - 1)}
+		proof, err := tree.NewInclusionProof(indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proof.Verify(tree.Root()) {
+			t.Errorf("%d leafs: inclusion proof does not verify", tt.numLeafs)
+		}
+		if err := tree.Validate(true); err != nil {
+			t.Errorf("%d leafs: Validate: %v", tt.numLeafs, err)
+		}
+	}
+}
+
+func TestNewPaddedHidesPadding(t *testing.T) {
+	tree, err := NewPadded(createTestLeafs(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tree.GetLeaf(5); err == nil {
+		t.Error("GetLeaf returned a padding leaf")
+	}
+	if _, err := tree.AuthenticationPath(7); err == nil {
+		t.Error("AuthenticationPath opened a padding leaf")
+	}
+	if _, err := tree.NewInclusionProof([]MerkleTreeLeafIndex{6}); err == nil {
+		t.Error("NewInclusionProof opened a padding leaf")
+	}
+	if err := tree.UpdateLeaf(5, hash.Digest{}); err == nil {
+		t.Error("UpdateLeaf replaced a padding leaf")
+	}
+	if _, ok := tree.FindLeaf(PaddingDigest()); ok {
+		t.Error("FindLeaf found a padding leaf")
+	}
+	tree.BuildLeafIndex()
+	if _, ok := tree.FindLeaf(PaddingDigest()); ok {
+		t.Error("FindLeaf found a padding leaf in the leaf index")
+	}
+	if positions, ok := tree.FindLeaf(createTestLeafs(5)[4]); !ok || len(positions) != 1 || positions[0] != 4 {
+		t.Errorf("FindLeaf of the last leaf = %v, %v", positions, ok)
+	}
+}
+
+func TestNewPaddedUpdates(t *testing.T) {
+	leafs := createTestLeafs(13)
+	tree, err := NewPadded(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement := createTestLeafs(20)[19]
+
+	derived, err := tree.WithUpdatedLeaf(12, replacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if derived.NumLeafs() != 13 {
+		t.Errorf("derived tree has %d leafs, want 13", derived.NumLeafs())
+	}
+
+	leafs[12] = replacement
+	want, err := NewPadded(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if derived.Root() != want.Root() {
+		t.Error("WithUpdatedLeaf root differs from rebuilding")
+	}
+	if err := tree.UpdateLeaf(12, replacement); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root() != want.Root() {
+		t.Error("UpdateLeaf root differs from rebuilding")
+	}
+}
+
+func TestNewPaddedValidateDetectsAlteredPadding(t *testing.T) {
+	tree, err := NewPadded(createTestLeafs(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Leaf node of padding leaf 3 in a tree of 4 leaf nodes
+	tree.nodes[4+3] = hash.Digest{}
+	if err := tree.Validate(false); err == nil {
+		t.Error("Validate accepted altered padding")
+	}
+}
+
+func TestNewPaddedRejectsEmptyInput(t *testing.T) {
+	if _, err := NewPadded(nil); err == nil {
+		t.Error("zero leafs accepted")
+	}
+}
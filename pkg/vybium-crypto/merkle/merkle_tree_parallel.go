@@ -0,0 +1,60 @@
+package merkle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// minParallelPairs is the fewest pairs a worker of NewParallel hashes in
+// one layer. Layers too small to give every worker that many are split
+// over fewer workers, down to hashing the top of the tree on the calling
+// goroutine, where starting goroutines would cost more than it saves.
+const minParallelPairs = 256
+
+// NewParallel builds the same MerkleTree as New, hashing each layer with up
+// to numWorkers goroutines. Every worker hashes a disjoint range of the
+// layer's pairs and so writes a disjoint range of parents; a layer is only
+// started once the one below it is complete.
+// Returns an error if New would, or if numWorkers is less than one.
+func NewParallel(leafs []hash.Digest, numWorkers int) (*MerkleTree, error) {
+	if numWorkers < 1 {
+		return nil, fmt.Errorf("number of workers must be positive, got %d", numWorkers)
+	}
+	nodes, err := initializeMerkleTreeNodes(leafs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for layerSize := len(leafs); layerSize > 1; layerSize /= 2 {
+		hashLayerParallel(nodes, layerSize, numWorkers)
+	}
+	return &MerkleTree{nodes: nodes}, nil
+}
+
+// hashLayerParallel hashes the layer of layerSize nodes into its parents,
+// splitting the pairs over at most numWorkers goroutines and returning once
+// all of them are done. Ranges are multiples of hash.BatchWidth pairs so
+// every worker uses the interleaved hash.HashPairs4 as far as possible.
+func hashLayerParallel(nodes []hash.Digest, layerSize, numWorkers int) {
+	numPairs := layerSize / 2
+	workers := min(numWorkers, numPairs/minParallelPairs)
+	if workers <= 1 {
+		hashLayerRange(nodes, layerSize, 0, layerSize)
+		return
+	}
+
+	batches := (numPairs + hash.BatchWidth - 1) / hash.BatchWidth
+	batchesPerWorker := (batches + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < layerSize; start += 2 * hash.BatchWidth * batchesPerWorker {
+		end := min(start+2*hash.BatchWidth*batchesPerWorker, layerSize)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hashLayerRange(nodes, layerSize, start, end)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestNewParallelMatchesNew(t *testing.T) {
+	for _, numLeafs := range []int{1, 2, 4, 1024, 1 << 12} {
+		leafs := createTestLeafs(numLeafs)
+		sequential, err := New(leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, numWorkers := range []int{1, 2, 3, 7, 64} {
+			parallel, err := NewParallel(leafs, numWorkers)
+			if err != nil {
+				t.Fatalf("%d leafs, %d workers: %v", numLeafs, numWorkers, err)
+			}
+			if parallel.Root() != sequential.Root() {
+				t.Errorf("%d leafs, %d workers: root %v, want %v", numLeafs, numWorkers, parallel.Root(), sequential.Root())
+			}
+			for i := range sequential.nodes {
+				if parallel.nodes[i] != sequential.nodes[i] {
+					t.Errorf("%d leafs, %d workers: node %d differs", numLeafs, numWorkers, i)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestNewParallelRejectsInvalidInput(t *testing.T) {
+	leafs := createTestLeafs(8)
+	for _, numWorkers := range []int{0, -1} {
+		if _, err := NewParallel(leafs, numWorkers); err == nil {
+			t.Errorf("%d workers accepted", numWorkers)
+		}
+	}
+	if _, err := NewParallel(nil, 4); err == nil {
+		t.Error("zero leafs accepted")
+	}
+	if _, err := NewParallel(createTestLeafs(6), 4); err == nil {
+		t.Error("6 leafs accepted")
+	}
+}
+
+func BenchmarkNewParallel(b *testing.B) {
+	leafs := createTestLeafs(1 << 16)
+	b.Run("New", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := New(leafs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, numWorkers := range []int{2, 4, runtime.GOMAXPROCS(0)} {
+		b.Run(fmt.Sprintf("workers=%d", numWorkers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := NewParallel(leafs, numWorkers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
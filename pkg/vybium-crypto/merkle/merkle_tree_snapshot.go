@@ -0,0 +1,152 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// overlayCompactionRatio bounds the overlay of a derived tree: once it would
+// hold more than 1/overlayCompactionRatio of the nodes, WithUpdatedLeaf copies
+// the nodes into a fresh array instead, so lookups stay cheap and a chain of
+// updates costs amortized O(log n) memory per update.
+const overlayCompactionRatio = 8
+
+// overlayNode is a node of the overlay of a derived tree. The overlay has the
+// shape of the Merkle tree it covers: the children of the overlay node for
+// node index i stand for the node indices 2i and 2i+1, and a nil child means
+// that subtree is unchanged and read from the shared node array.
+//
+// Overlay nodes are never modified once reachable from a tree. An update
+// copies the nodes on the changed path and reuses every other subtree, so
+// snapshots share all but one path of their overlays.
+type overlayNode struct {
+	digest   hash.Digest
+	children [2]*overlayNode
+}
+
+// writeTo copies the digests of the overlay rooted at the given node index
+// into nodes.
+func (node *overlayNode) writeTo(nodes []hash.Digest, index MerkleTreeNodeIndex) {
+	nodes[index] = node.digest
+	for bit, child := range node.children {
+		if child != nil {
+			child.writeTo(nodes, 2*index+uint64(bit))
+		}
+	}
+}
+
+// node returns the digest at the given node index. It is kept small enough
+// to be inlined, so that trees without an overlay pay only a nil check.
+func (mt *MerkleTree) node(index MerkleTreeNodeIndex) hash.Digest {
+	if mt.overlay == nil {
+		return mt.nodes[index]
+	}
+	return mt.overlayDigest(index)
+}
+
+// overlayDigest returns the digest at the given node index of a derived tree,
+// walking the overlay from the root until the path leaves it.
+func (mt *MerkleTree) overlayDigest(index MerkleTreeNodeIndex) hash.Digest {
+	if index < RootIndex {
+		return mt.nodes[index]
+	}
+	node := mt.overlay
+	for shift := bits.Len64(index) - 2; shift >= 0; shift-- {
+		node = node.children[(index>>shift)&1]
+		if node == nil {
+			return mt.nodes[index]
+		}
+	}
+	return node.digest
+}
+
+// overlayChild returns the digest at the given node index, a child of the
+// given overlay node.
+func (mt *MerkleTree) overlayChild(node *overlayNode, index MerkleTreeNodeIndex) hash.Digest {
+	if child := node.children[index&1]; child != nil {
+		return child.digest
+	}
+	return mt.nodes[index]
+}
+
+// updateOverlayPath sets the leaf at the given node index of a derived tree
+// and recomputes the digests on its path to the root. Only the overlay nodes
+// on that path are copied; the previous overlay, which other snapshots may
+// share, is left unchanged.
+func (mt *MerkleTree) updateOverlayPath(nodeIndex MerkleTreeNodeIndex, leaf hash.Digest) {
+	depth := bits.Len64(nodeIndex) - 1
+
+	// previous[k] is the overlay node at depth k on the path, if any
+	var previous [64]*overlayNode
+	previous[0] = mt.overlay
+	for k := 1; k <= depth && previous[k-1] != nil; k++ {
+		previous[k] = previous[k-1].children[(nodeIndex>>(depth-k))&1]
+	}
+
+	path := make([]overlayNode, depth+1)
+	for k := depth; k >= 0; k-- {
+		node := &path[k]
+		if previous[k] != nil {
+			node.children = previous[k].children
+		} else {
+			mt.overlaySize++
+		}
+		if k == depth {
+			node.digest = leaf
+			continue
+		}
+		index := nodeIndex >> (depth - k)
+		node.children[(nodeIndex>>(depth-k-1))&1] = &path[k+1]
+		node.digest = hash.HashPair(mt.overlayChild(node, 2*index), mt.overlayChild(node, 2*index+1))
+	}
+	mt.overlay = &path[0]
+}
+
+// ensureOwnNodes copies the node array if it is shared with a derived tree,
+// so that writing to it cannot change that tree.
+func (mt *MerkleTree) ensureOwnNodes() {
+	if mt.overlay == nil && mt.nodesShared.Load() {
+		mt.nodes = append([]hash.Digest(nil), mt.nodes...)
+		mt.nodesShared.Store(false)
+	}
+}
+
+// WithUpdatedLeaf returns a new tree equal to this one with the leaf at the
+// given index replaced, leaving this tree unchanged. The new tree shares the
+// node array and the overlay of this one, and allocates only the O(log n)
+// nodes on the changed path, so it is cheap to derive. Readers of this tree
+// are unaffected: WithUpdatedLeaf may run concurrently with any non-mutating
+// method.
+// The new tree has no leaf index, even if this one has.
+func (mt *MerkleTree) WithUpdatedLeaf(index MerkleTreeLeafIndex, leaf hash.Digest) (*MerkleTree, error) {
+	numLeafs := mt.NumLeafs()
+	if index >= numLeafs {
+		return nil, fmt.Errorf("leaf index %d out of range [0, %d)", index, numLeafs)
+	}
+
+	var derived *MerkleTree
+	overlaySize := mt.overlaySize + int(mt.Height()) + 1
+	switch {
+	case overlaySize*overlayCompactionRatio > len(mt.nodes):
+		nodes := make([]hash.Digest, len(mt.nodes))
+		copy(nodes, mt.nodes)
+		if mt.overlay != nil {
+			mt.overlay.writeTo(nodes, RootIndex)
+		}
+		derived = &MerkleTree{nodes: nodes, numLeafs: mt.numLeafs}
+	case mt.overlay == nil:
+		// Start an overlay holding just the root, which any update replaces
+		mt.nodesShared.Store(true)
+		root := &overlayNode{digest: mt.nodes[RootIndex]}
+		derived = &MerkleTree{nodes: mt.nodes, overlay: root, overlaySize: 1, numLeafs: mt.numLeafs}
+	default:
+		derived = &MerkleTree{nodes: mt.nodes, overlay: mt.overlay, overlaySize: mt.overlaySize, numLeafs: mt.numLeafs}
+	}
+
+	if err := derived.UpdateLeaf(index, leaf); err != nil {
+		return nil, err
+	}
+	return derived, nil
+}
@@ -0,0 +1,247 @@
+package merkle
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// sameNodes reports whether two trees have the same digest at every node.
+func sameNodes(a, b *MerkleTree) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	for i := RootIndex; i < uint64(a.Size()); i++ {
+		if a.node(i) != b.node(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWithUpdatedLeafMatchesUpdateLeaf(t *testing.T) {
+	rng := rand.New(rand.NewSource(1177))
+	for _, n := range []int{1, 8, 1024} {
+		leafs := createTestLeafs(n)
+		original, _ := New(leafs)
+		originalRoot := original.Root()
+		mutated, _ := New(leafs)
+
+		snapshot := original
+		for i := 0; i < 300; i++ {
+			index := uint64(rng.Intn(n))
+			leaf := hash.HashVarlen([]field.Element{field.New(uint64(i)), field.New(1177)})
+
+			next, err := snapshot.WithUpdatedLeaf(index, leaf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := mutated.UpdateLeaf(index, leaf); err != nil {
+				t.Fatal(err)
+			}
+			if !sameNodes(next, mutated) {
+				t.Fatalf("%d leafs, update %d: derived tree differs from updated tree", n, i)
+			}
+			snapshot = next
+		}
+
+		if err := snapshot.Validate(true); err != nil {
+			t.Errorf("%d leafs: derived tree invalid: %v", n, err)
+		}
+		if original.Root() != originalRoot || !sameNodes(original, mustNew(t, leafs)) {
+			t.Errorf("%d leafs: deriving changed the original tree", n)
+		}
+	}
+
+	tree, _ := New(createTestLeafs(4))
+	if _, err := tree.WithUpdatedLeaf(4, hash.Digest{}); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+}
+
+func mustNew(t *testing.T, leafs []hash.Digest) *MerkleTree {
+	t.Helper()
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestWithUpdatedLeafSharesNodes(t *testing.T) {
+	tree := mustNew(t, createTestLeafs(1024))
+	pathLength := int(tree.Height()) + 1
+
+	derived, _ := tree.WithUpdatedLeaf(5, hash.Digest{})
+	if &derived.nodes[0] != &tree.nodes[0] {
+		t.Fatal("derived tree does not share the node array")
+	}
+	if derived.overlaySize != pathLength {
+		t.Errorf("overlay has %d nodes, want %d", derived.overlaySize, pathLength)
+	}
+
+	// A chain of updates grows the overlay by at most a path per update,
+	// and is compacted before it reaches a fraction of the tree
+	snapshot := derived
+	for i := 0; i < 100; i++ {
+		previous := snapshot.overlaySize
+		snapshot, _ = snapshot.WithUpdatedLeaf(uint64(i*37%1024), hash.Digest{})
+		if snapshot.overlaySize > previous+pathLength {
+			t.Fatalf("update %d grew the overlay from %d to %d", i, previous, snapshot.overlaySize)
+		}
+		if snapshot.overlaySize*overlayCompactionRatio > len(snapshot.nodes) {
+			t.Fatalf("update %d: overlay of %d nodes not compacted", i, snapshot.overlaySize)
+		}
+	}
+
+	// Deriving copies only the changed path, however large the overlay
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = snapshot.WithUpdatedLeaf(1000, hash.Digest{})
+	})
+	if allocs > 2 {
+		t.Errorf("WithUpdatedLeaf on an overlay of %d nodes made %v allocations, want at most 2", snapshot.overlaySize, allocs)
+	}
+	next, _ := snapshot.WithUpdatedLeaf(1000, hash.Digest{})
+	if next.overlay.children[0] != snapshot.overlay.children[0] {
+		t.Error("derived overlay does not share the unchanged subtree")
+	}
+}
+
+func TestUpdateLeafDoesNotLeakIntoSnapshots(t *testing.T) {
+	leafs := createTestLeafs(1024)
+	tree := mustNew(t, leafs)
+	derived, _ := tree.WithUpdatedLeaf(3, hash.Digest{})
+	derivedRoot := derived.Root()
+
+	// Mutating the original copies the shared nodes first
+	if err := tree.UpdateLeaf(700, hash.Digest{}); err != nil {
+		t.Fatal(err)
+	}
+	if derived.Root() != derivedRoot || derived.Validate(true) != nil {
+		t.Error("UpdateLeaf on the original changed the derived tree")
+	}
+
+	// Mutating the derived tree only touches its overlay
+	fresh := mustNew(t, leafs)
+	second, _ := fresh.WithUpdatedLeaf(3, hash.Digest{})
+	if err := second.UpdateLeaf(9, hash.Digest{}); err != nil {
+		t.Fatal(err)
+	}
+	if !sameNodes(fresh, mustNew(t, leafs)) {
+		t.Error("UpdateLeaf on the derived tree changed the original")
+	}
+
+	// Nor the overlay it shares with the snapshot it was derived from
+	third, _ := second.WithUpdatedLeaf(10, hash.Digest{})
+	secondRoot := second.Root()
+	if err := third.UpdateLeaf(11, hash.Digest{}); err != nil {
+		t.Fatal(err)
+	}
+	if second.Root() != secondRoot || second.Validate(true) != nil {
+		t.Error("UpdateLeaf on a derived tree changed its parent snapshot")
+	}
+}
+
+// Readers verify paths against their own snapshot while a writer derives
+// new ones; run with -race.
+func TestWithUpdatedLeafConcurrentReaders(t *testing.T) {
+	leafs := createTestLeafs(256)
+	tree := mustNew(t, leafs)
+
+	var wg sync.WaitGroup
+	snapshots := make(chan *MerkleTree, 8)
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for snapshot := range snapshots {
+				root := snapshot.Root()
+				for index := uint64(0); index < 256; index += 17 {
+					leaf, _ := snapshot.GetLeaf(index)
+					path, _ := snapshot.AuthenticationPath(index)
+					if !VerifyInclusionProof(root, index, leaf, path) {
+						t.Errorf("snapshot path for leaf %d does not verify", index)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	snapshot := tree
+	for i := 0; i < 200; i++ {
+		snapshots <- snapshot
+		snapshot, _ = snapshot.WithUpdatedLeaf(uint64(i%256), hash.Digest{})
+	}
+	close(snapshots)
+	wg.Wait()
+}
+
+func TestMmrWithAppended(t *testing.T) {
+	leafs := createTestLeafs(40)
+	mutated := NewMmrAccumulatorFromLeafs(nil)
+	snapshot := NewMmrAccumulatorFromLeafs(nil)
+
+	for i, leaf := range leafs {
+		previous := snapshot
+		previousBag := previous.BagPeaks()
+
+		next, proof := snapshot.WithAppended(leaf)
+		wantProof := mutated.Append(leaf)
+
+		if next.BagPeaks() != mutated.BagPeaks() || next.NumLeafs() != uint64(i+1) {
+			t.Fatalf("append %d: WithAppended differs from Append", i)
+		}
+		if proof.LeafIndex != wantProof.LeafIndex || len(proof.AuthPath) != len(wantProof.AuthPath) {
+			t.Fatalf("append %d: proofs differ", i)
+		}
+		if !next.VerifyMembership(leaf, proof) {
+			t.Fatalf("append %d: proof rejected", i)
+		}
+		if previous.BagPeaks() != previousBag || previous.NumLeafs() != uint64(i) {
+			t.Fatalf("append %d: WithAppended changed the original", i)
+		}
+		snapshot = next
+	}
+}
+
+func TestMmrWithAppendedConcurrentReaders(t *testing.T) {
+	snapshot := NewMmrAccumulatorFromLeafs(createTestLeafs(7))
+
+	var wg sync.WaitGroup
+	snapshots := make(chan *MmrAccumulator, 8)
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range snapshots {
+				if err := s.Validate(); err != nil {
+					t.Errorf("snapshot invalid: %v", err)
+					return
+				}
+				_ = s.BagPeaks()
+			}
+		}()
+	}
+
+	for _, leaf := range createTestLeafs(100) {
+		snapshots <- snapshot
+		snapshot, _ = snapshot.WithAppended(leaf)
+	}
+	close(snapshots)
+	wg.Wait()
+}
+
+func BenchmarkWithUpdatedLeaf(b *testing.B) {
+	tree, _ := New(createTestLeafs(1 << 16))
+	leaf := createTestLeafs(1)[0]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = tree.WithUpdatedLeaf(uint64(i)%(1<<16), leaf)
+	}
+}
@@ -359,3 +359,92 @@ func BenchmarkMerkleInclusionProof(b *testing.B) {
 		_, _ = tree.NewInclusionProof(indices)
 	}
 }
+
+func TestMerkleTreeZeroDigestLeaf(t *testing.T) {
+	leafs := createTestLeafs(8)
+	leafs[3] = hash.ZeroDigest()
+
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+	root := tree.Root()
+
+	leaf, err := tree.GetLeaf(3)
+	if err != nil {
+		t.Fatalf("GetLeaf should succeed for a zero-digest leaf: %v", err)
+	}
+	if !leaf.IsZero() {
+		t.Error("Expected the committed zero-digest leaf")
+	}
+
+	path, err := tree.AuthenticationPath(3)
+	if err != nil {
+		t.Fatalf("Failed to get authentication path: %v", err)
+	}
+	if !VerifyInclusionProof(root, 3, hash.ZeroDigest(), path) {
+		t.Error("Zero-digest leaf should verify")
+	}
+
+	proof, err := tree.NewInclusionProof([]MerkleTreeLeafIndex{3, 6})
+	if err != nil {
+		t.Fatalf("Failed to create inclusion proof: %v", err)
+	}
+	if !proof.Verify(root) {
+		t.Error("Inclusion proof containing a zero-digest leaf should verify")
+	}
+}
+
+func TestInclusionProofCorruptedAgainstZeroRoot(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, err := New(leafs)
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+	root := tree.Root()
+	zeroRoot := hash.ZeroDigest()
+
+	proof, err := tree.NewInclusionProof([]MerkleTreeLeafIndex{0, 2, 5})
+	if err != nil {
+		t.Fatalf("Failed to create inclusion proof: %v", err)
+	}
+
+	corruptions := map[string]func(p *MerkleTreeInclusionProof){
+		"missing authentication digest": func(p *MerkleTreeInclusionProof) {
+			p.AuthenticationStructure = p.AuthenticationStructure[:len(p.AuthenticationStructure)-1]
+		},
+		"surplus authentication digest": func(p *MerkleTreeInclusionProof) {
+			p.AuthenticationStructure = append(p.AuthenticationStructure, hash.ZeroDigest())
+		},
+		"empty authentication structure": func(p *MerkleTreeInclusionProof) {
+			p.AuthenticationStructure = nil
+		},
+		"leaf index out of range": func(p *MerkleTreeInclusionProof) {
+			p.IndexedLeafs[0].Index = 8
+		},
+		"conflicting duplicate leaf": func(p *MerkleTreeInclusionProof) {
+			p.IndexedLeafs = append(p.IndexedLeafs, LeafIndexDigestPair{Index: 0, Digest: leafs[1]})
+		},
+		"wrong last leaf": func(p *MerkleTreeInclusionProof) {
+			p.IndexedLeafs[2].Digest = leafs[4]
+		},
+	}
+
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			corrupted := &MerkleTreeInclusionProof{
+				TreeHeight:              proof.TreeHeight,
+				IndexedLeafs:            append([]LeafIndexDigestPair{}, proof.IndexedLeafs...),
+				AuthenticationStructure: append([]hash.Digest{}, proof.AuthenticationStructure...),
+			}
+			corrupt(corrupted)
+
+			if corrupted.Verify(zeroRoot) {
+				t.Error("Corrupted proof must not verify against the zero root")
+			}
+			if corrupted.Verify(root) {
+				t.Error("Corrupted proof must not verify against the real root")
+			}
+		})
+	}
+}
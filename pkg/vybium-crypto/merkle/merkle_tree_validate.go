@@ -0,0 +1,173 @@
+package merkle
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+const (
+	// spotCheckSamplesPerLayer is the number of internal nodes per layer
+	// rehashed by a spot check. Layers narrower than this are checked fully.
+	spotCheckSamplesPerLayer = 4
+
+	// minNodesPerValidationWorker is the smallest range of internal nodes
+	// worth handing to a separate goroutine during full validation.
+	minNodesPerValidationWorker = 1 << 12
+)
+
+// Validate checks the tree for corruption, e.g. after loading it from
+// untrusted or possibly damaged storage.
+//
+// Structural invariants are always checked: the node array has length
+// 2·numLeafs for a power-of-two numLeafs within the maximum height, the
+// unused index 0 holds the zero digest, and the leafs NewPadded added are
+// PaddingDigest.
+//
+// If full is true, every internal node is rehashed from its children, in
+// parallel, so any single corrupted node or leaf is detected. If full is
+// false, only a deterministic sample of spotCheckSamplesPerLayer nodes per
+// layer is rehashed; the sample depends on the root, so it is fixed for a
+// given tree. A spot check always covers the root and catches corruption
+// elsewhere only with some probability.
+func (mt *MerkleTree) Validate(full bool) error {
+	if err := mt.validateStructure(); err != nil {
+		return err
+	}
+
+	if full {
+		return mt.validateAllNodes()
+	}
+	return mt.validateSampledNodes()
+}
+
+// validateStructure checks the shape of the node array.
+func (mt *MerkleTree) validateStructure() error {
+	numNodes := uint64(len(mt.nodes))
+	if numNodes < 2 || numNodes&(numNodes-1) != 0 {
+		return fmt.Errorf("invalid node count %d: must be twice a power of two", numNodes)
+	}
+	if mt.Height() > maxTreeHeight {
+		return fmt.Errorf("tree height %d exceeds maximum %d", mt.Height(), maxTreeHeight)
+	}
+	if mt.node(0) != (hash.Digest{}) {
+		return fmt.Errorf("unused node at index 0 is not the zero digest")
+	}
+	paddedNumLeafs := mt.paddedNumLeafs()
+	if mt.numLeafs > paddedNumLeafs {
+		return fmt.Errorf("%d leafs do not fit in %d leaf nodes", mt.numLeafs, paddedNumLeafs)
+	}
+	if mt.numLeafs != 0 {
+		padding := PaddingDigest()
+		for index := mt.numLeafs; index < paddedNumLeafs; index++ {
+			if mt.node(paddedNumLeafs+index) != padding {
+				return fmt.Errorf("padding leaf %d is not the padding digest", index)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAllNodes rehashes every internal node, splitting the index range
+// across goroutines. The reported error refers to the lowest bad index.
+func (mt *MerkleTree) validateAllNodes() error {
+	numInternal := mt.paddedNumLeafs() // internal nodes occupy indices [1, numLeafs)
+
+	numWorkers := uint64(runtime.GOMAXPROCS(0))
+	if maxWorkers := numInternal / minNodesPerValidationWorker; numWorkers > maxWorkers {
+		numWorkers = maxWorkers
+	}
+	if numWorkers <= 1 {
+		if index, ok := mt.verifyNodeRange(RootIndex, numInternal); !ok {
+			return nodeMismatchError(index)
+		}
+		return nil
+	}
+
+	badIndices := make([]MerkleTreeNodeIndex, numWorkers)
+	chunkSize := (numInternal + numWorkers - 1) / numWorkers
+
+	var wg sync.WaitGroup
+	for w := uint64(0); w < numWorkers; w++ {
+		start := RootIndex + w*chunkSize
+		end := start + chunkSize
+		if end > numInternal {
+			end = numInternal
+		}
+		wg.Add(1)
+		go func(w uint64, start, end MerkleTreeNodeIndex) {
+			defer wg.Done()
+			if index, ok := mt.verifyNodeRange(start, end); !ok {
+				badIndices[w] = index
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, index := range badIndices {
+		if index != 0 {
+			return nodeMismatchError(index)
+		}
+	}
+	return nil
+}
+
+// verifyNodeRange rehashes the internal nodes with indices in [start, end).
+// Returns the first index whose digest differs from the hash of its
+// children, and false, if there is one.
+func (mt *MerkleTree) verifyNodeRange(start, end MerkleTreeNodeIndex) (MerkleTreeNodeIndex, bool) {
+	index := start
+	for ; index+hash.BatchWidth <= end; index += hash.BatchWidth {
+		var left, right [hash.BatchWidth]hash.Digest
+		for lane := uint64(0); lane < hash.BatchWidth; lane++ {
+			left[lane] = mt.node(2 * (index + lane))
+			right[lane] = mt.node(2*(index+lane) + 1)
+		}
+		digests := hash.HashPairs4(left, right)
+		for lane := uint64(0); lane < hash.BatchWidth; lane++ {
+			if digests[lane] != mt.node(index+lane) {
+				return index + lane, false
+			}
+		}
+	}
+	for ; index < end; index++ {
+		if !mt.nodeMatchesChildren(index) {
+			return index, false
+		}
+	}
+	return 0, true
+}
+
+// validateSampledNodes rehashes a deterministic sample of internal nodes
+// from every layer.
+func (mt *MerkleTree) validateSampledNodes() error {
+	offset := mt.node(RootIndex)[0].Value()
+
+	for layerStart := RootIndex; layerStart < mt.paddedNumLeafs(); layerStart *= 2 {
+		layerWidth := layerStart
+		samples := uint64(spotCheckSamplesPerLayer)
+		if samples > layerWidth {
+			samples = layerWidth
+		}
+		stride := layerWidth / samples
+		for s := uint64(0); s < samples; s++ {
+			index := layerStart + (offset+s*stride)%layerWidth
+			if !mt.nodeMatchesChildren(index) {
+				return nodeMismatchError(index)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeMatchesChildren reports whether the internal node at index equals the
+// hash of its two children.
+func (mt *MerkleTree) nodeMatchesChildren(index MerkleTreeNodeIndex) bool {
+	return hash.HashPair(mt.node(2*index), mt.node(2*index+1)) == mt.node(index)
+}
+
+func nodeMismatchError(index MerkleTreeNodeIndex) error {
+	return fmt.Errorf("node %d does not match the hash of its children", index)
+}
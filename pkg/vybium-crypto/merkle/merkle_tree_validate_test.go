@@ -0,0 +1,166 @@
+package merkle
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestMerkleTreeValidateFreshTree(t *testing.T) {
+	for _, numLeafs := range []int{1, 2, 4, 64, 1 << 14} {
+		tree, err := New(createTestLeafs(numLeafs))
+		if err != nil {
+			t.Fatalf("failed to create tree: %v", err)
+		}
+		if err := tree.Validate(true); err != nil {
+			t.Errorf("%d leafs: full validation failed: %v", numLeafs, err)
+		}
+		if err := tree.Validate(false); err != nil {
+			t.Errorf("%d leafs: spot check failed: %v", numLeafs, err)
+		}
+	}
+}
+
+func TestMerkleTreeValidateFullCatchesEveryCorruption(t *testing.T) {
+	// Large enough for full validation to split the work across goroutines
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	tree, err := New(createTestLeafs(1 << 14))
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+
+	corruption := hash.Digest{field.New(101), field.New(102), field.New(103), field.New(104), field.New(105)}
+	indices := []MerkleTreeNodeIndex{1, 2, 3, 17, 1000, 1 << 13, (1 << 14) - 1, 1 << 14, (1 << 15) - 1}
+	for _, index := range indices {
+		original := tree.nodes[index]
+		tree.nodes[index] = corruption
+		if err := tree.Validate(true); err == nil {
+			t.Errorf("full validation missed corruption at node %d", index)
+		}
+		tree.nodes[index] = original
+	}
+
+	// Exhaustively on a small tree, including every leaf
+	small, err := New(createTestLeafs(32))
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+	for index := RootIndex; index < uint64(len(small.nodes)); index++ {
+		original := small.nodes[index]
+		small.nodes[index] = corruption
+		if err := small.Validate(true); err == nil {
+			t.Errorf("full validation missed corruption at node %d", index)
+		}
+		small.nodes[index] = original
+	}
+}
+
+func TestMerkleTreeValidateSpotCheck(t *testing.T) {
+	tree, err := New(createTestLeafs(256))
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+
+	corruption := hash.Digest{field.New(9), field.New(8), field.New(7), field.New(6), field.New(5)}
+
+	// The root is always sampled
+	original := tree.nodes[RootIndex]
+	tree.nodes[RootIndex] = corruption
+	if err := tree.Validate(false); err == nil {
+		t.Error("spot check missed corrupted root")
+	}
+	tree.nodes[RootIndex] = original
+
+	detected := 0
+	total := len(tree.nodes) - 1
+	for index := 1; index < len(tree.nodes); index++ {
+		original := tree.nodes[index]
+		tree.nodes[index] = corruption
+		if tree.Validate(false) != nil {
+			detected++
+		}
+		tree.nodes[index] = original
+	}
+
+	if detected == 0 {
+		t.Error("spot check never detected corruption")
+	}
+	if detected == total {
+		t.Error("spot check unexpectedly rehashed every node")
+	}
+	t.Logf("spot check detected %d of %d single-node corruptions", detected, total)
+}
+
+func TestMerkleTreeValidateStructure(t *testing.T) {
+	tree, err := New(createTestLeafs(8))
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		nodes []hash.Digest
+	}{
+		{"empty", nil},
+		{"single node", tree.nodes[:1]},
+		{"not twice a power of two", tree.nodes[:12]},
+		{"index 0 used", append([]hash.Digest{{field.One}}, tree.nodes[1:]...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrupted := &MerkleTree{nodes: tt.nodes}
+			if err := corrupted.Validate(true); err == nil {
+				t.Error("expected full validation error")
+			}
+			if err := corrupted.Validate(false); err == nil {
+				t.Error("expected spot check error")
+			}
+		})
+	}
+}
+
+func TestMmrValidate(t *testing.T) {
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(13))
+	if err := mmr.Validate(); err != nil {
+		t.Errorf("fresh MMR failed validation: %v", err)
+	}
+
+	inconsistent := NewMmrAccumulator(mmr.Peaks()[:2], 13)
+	if err := inconsistent.Validate(); err == nil {
+		t.Error("expected error for missing peak")
+	}
+	if inconsistent.IsConsistent() {
+		t.Error("IsConsistent should agree with Validate")
+	}
+}
+
+func BenchmarkMerkleTreeValidateFull(b *testing.B) {
+	tree, err := New(createTestLeafs(1 << 20))
+	if err != nil {
+		b.Fatalf("failed to create tree: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tree.Validate(true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMerkleTreeValidateSpotCheck(b *testing.B) {
+	tree, err := New(createTestLeafs(1 << 20))
+	if err != nil {
+		b.Fatalf("failed to create tree: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tree.Validate(false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
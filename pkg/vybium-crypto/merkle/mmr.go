@@ -1,9 +1,11 @@
 package merkle
 
 import (
+	"fmt"
 	"math/bits"
 
-	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
 )
 
@@ -32,11 +34,16 @@ type MMR interface {
 	Append(newLeaf hash.Digest) MmrMembershipProof
 
 	// VerifyMembership verifies a membership proof against the MMR.
-	VerifyMembership(leaf hash.Digest, proof MmrMembershipProof) bool
+	VerifyMembership(leaf hash.Digest, proof MmrMembershipProof, opts ...VerifyOption) bool
 }
 
 // MmrAccumulator is a lightweight representation of an MMR that only stores
 // the peaks and leaf count, not the full tree structure.
+//
+// An MmrAccumulator is immutable after construction except through Append
+// and UnmarshalBinary. All other methods may be called from any number of
+// goroutines concurrently; WithAppended derives a new snapshot without
+// disturbing them.
 type MmrAccumulator struct {
 	leafCount uint64
 	peaks     []hash.Digest
@@ -96,7 +103,7 @@ func peaksFromLeafs(leafs []hash.Digest) []hash.Digest {
 	}
 
 	// Maximum number of peaks is bounded by log2(numLeafs)
-	maxTreeHeight := bits.Len(uint(len(leafs)))
+	maxTreeHeight := bits.Len64(uint64(len(leafs)))
 	peaks := make([]hash.Digest, 0, maxTreeHeight)
 
 	// Process pairs of leafs
@@ -132,37 +139,36 @@ func peaksFromLeafs(leafs []hash.Digest) []hash.Digest {
 	return peaks
 }
 
-// BagPeaks calculates a commitment to the entire MMR by hashing all peaks.
+// BagPeaks calculates a commitment to the entire MMR.
+// It is defined as Hash(), i.e. HashVarlen over the canonical encoding
+// produced by Encode, so the commitment and the serialization format cannot
+// drift apart.
 func (mmr *MmrAccumulator) BagPeaks() hash.Digest {
-	return bagPeaks(mmr.peaks, mmr.leafCount)
+	return mmr.Hash()
 }
 
-// bagPeaks computes a single commitment from the peaks and leaf count.
-// This is done by hashing: Hash(leafCount, peaks[0], peaks[1], ..., peaks[n])
-func bagPeaks(peaks []hash.Digest, leafCount uint64) hash.Digest {
-	if len(peaks) == 0 {
-		return hash.ZeroDigest()
-	}
-
-	// Build input: [leafCount as 5 field elements, peak0, peak1, ..., peakN]
-	input := make([]field.Element, 5+len(peaks)*hash.DigestLen)
-
-	// Encode leaf count as field elements (split across 5 elements for consistency)
-	input[0] = field.New(leafCount)
-	for i := 1; i < 5; i++ {
-		input[i] = field.Zero
-	}
+// BagPeaksCompat calculates a commitment to the entire MMR with
+// twenty-first's bag_peaks algorithm, for commitments that must equal those
+// of Rust implementations. It differs from BagPeaks, which is Hash() and so
+// commits to the Encode serialization instead; callers choose one per
+// protocol.
+//
+// The leaf count is hashed into the initial accumulator, and the peaks are
+// then folded in from right to left:
+//
+//	acc = HashVarlen(EncodeUint64(leafCount))
+//	acc = HashPair(peaks[i], acc) for i = len(peaks)-1, ..., 0
+func (mmr *MmrAccumulator) BagPeaksCompat() hash.Digest {
+	return bagPeaksCompat(mmr.peaks, mmr.leafCount)
+}
 
-	// Add all peaks
-	idx := 5
-	for _, peak := range peaks {
-		for i := 0; i < hash.DigestLen; i++ {
-			input[idx] = peak[i]
-			idx++
-		}
+// bagPeaksCompat implements BagPeaksCompat for the given peaks and leaf count.
+func bagPeaksCompat(peaks []hash.Digest, leafCount uint64) hash.Digest {
+	var acc hash.Digest = hash.HashVarlen(bfieldcodec.EncodeUint64(leafCount))
+	for i := len(peaks) - 1; i >= 0; i-- {
+		acc = hash.HashPair(peaks[i], acc)
 	}
-
-	return hash.HashVarlen(input)
+	return acc
 }
 
 // Peaks returns the peaks of the MMR.
@@ -172,6 +178,61 @@ func (mmr *MmrAccumulator) Peaks() []hash.Digest {
 	return result
 }
 
+// PeakInfo describes a single MMR peak together with its position metadata.
+type PeakInfo struct {
+	// Index is the index of the peak in the list of peaks, left to right.
+	Index int
+
+	// Height is the height of the perfect Merkle tree rooted at the peak.
+	Height uint32
+
+	// Position is the node position of the peak (see NodePositionHeight).
+	Position uint64
+
+	// Digest is the peak's digest.
+	Digest hash.Digest
+}
+
+// ForEachPeak calls fn for every peak in left-to-right order.
+// Iteration stops early if fn returns false.
+func (mmr *MmrAccumulator) ForEachPeak(fn func(PeakInfo) bool) {
+	heights := PeakHeights(mmr.leafCount)
+	positions := PeakPositions(mmr.leafCount)
+	for i := 0; i < len(mmr.peaks) && i < len(heights); i++ {
+		info := PeakInfo{
+			Index:    i,
+			Height:   heights[i],
+			Position: positions[i],
+			Digest:   mmr.peaks[i],
+		}
+		if !fn(info) {
+			return
+		}
+	}
+}
+
+// PeaksPage returns at most limit peaks starting at the given offset, in
+// left-to-right order. The result is empty if offset is past the last peak.
+func (mmr *MmrAccumulator) PeaksPage(offset, limit int) ([]PeakInfo, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("negative peak offset %d", offset)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("peak page limit must be positive, got %d", limit)
+	}
+
+	// The page never holds more than the peaks past offset, whatever the limit
+	page := make([]PeakInfo, 0, min(limit, max(len(mmr.peaks)-offset, 0)))
+	mmr.ForEachPeak(func(info PeakInfo) bool {
+		if info.Index < offset {
+			return true
+		}
+		page = append(page, info)
+		return len(page) < limit
+	})
+	return page, nil
+}
+
 // IsEmpty returns true if the MMR has no leafs.
 func (mmr *MmrAccumulator) IsEmpty() bool {
 	return mmr.leafCount == 0
@@ -190,6 +251,14 @@ func (mmr *MmrAccumulator) Append(newLeaf hash.Digest) MmrMembershipProof {
 	return membershipProof
 }
 
+// WithAppended returns a new accumulator with the leaf appended, and the
+// leaf's membership proof, leaving this accumulator unchanged. It may run
+// concurrently with any non-mutating method.
+func (mmr *MmrAccumulator) WithAppended(newLeaf hash.Digest) (*MmrAccumulator, MmrMembershipProof) {
+	newPeaks, membershipProof := calculateNewPeaksFromAppend(mmr.peaks, newLeaf, mmr.leafCount)
+	return NewMmrAccumulator(newPeaks, mmr.leafCount+1), membershipProof
+}
+
 // calculateNewPeaksFromAppend computes the new peaks after appending a leaf
 // and returns the membership proof for the newly added leaf.
 // This is a direct port of twenty-first's `calculate_new_peaks_from_append`.
@@ -202,9 +271,10 @@ func calculateNewPeaksFromAppend(oldPeaks []hash.Digest, newLeaf hash.Digest, ol
 	// Build authentication path
 	authPath := []hash.Digest{}
 
-	// The number of merges is the number of trailing ones in oldLeafCount
-	// (equivalent to twenty-first's right_lineage_length_from_leaf_index)
-	numMerges := trailingOnes64(oldLeafCount)
+	// The number of merges is the height of the new rightmost peak, which is
+	// the number of trailing ones in oldLeafCount (equivalent to
+	// twenty-first's right_lineage_length_from_leaf_index)
+	numMerges := bits.TrailingZeros64(^oldLeafCount)
 
 	// Perform merges
 	for i := 0; i < numMerges; i++ {
@@ -229,27 +299,61 @@ func calculateNewPeaksFromAppend(oldPeaks []hash.Digest, newLeaf hash.Digest, ol
 }
 
 // VerifyMembership verifies a membership proof for a leaf.
-// This reconstructs the peak from the leaf and authentication path,
-// and checks if it matches one of the MMR's peaks.
-func (mmr *MmrAccumulator) VerifyMembership(leaf hash.Digest, proof MmrMembershipProof) bool {
-	// Recompute the peak for this leaf using the authentication path
-	current := leaf
-	for _, authNode := range proof.AuthPath {
-		current = hash.HashPair(authNode, current)
-	}
-
-	// Check if the computed peak matches any of the MMR's peaks
-	// The peak should be at a specific position determined by leaf_index_to_mt_index_and_peak_index
-	// For simplicity, we check if it matches any peak
-	for _, peak := range mmr.peaks {
-		if current.Equal(peak) {
+// This reconstructs the peak from the leaf and authentication path, taking
+// each sibling's side from the leaf's position in its peak tree, and checks
+// that it matches the peak the proof's leaf index belongs to.
+// With WithDiagnostics, a failure is reported against the leaf's own peak.
+func (mmr *MmrAccumulator) VerifyMembership(leaf hash.Digest, proof MmrMembershipProof, opts ...VerifyOption) bool {
+	config := newVerifyConfig(opts)
+
+	mtIndex, peakIndex, err := LeafIndexToMtIndexAndPeakIndex(proof.LeafIndex, mmr.leafCount)
+	wellFormed := err == nil && uint64(len(proof.AuthPath)) == uint64(bits.Len64(mtIndex)-1) && int(peakIndex) < len(mmr.peaks)
+
+	var current hash.Digest
+	if wellFormed {
+		current = leaf
+		for _, authNode := range proof.AuthPath {
+			if mtIndex&1 == 0 {
+				current = config.hashPair(current, authNode)
+			} else {
+				current = config.hashPair(authNode, current)
+			}
+			mtIndex >>= 1
+		}
+		if current.Equal(mmr.peaks[peakIndex]) {
 			return true
 		}
 	}
 
+	if config.diagnostics != nil {
+		mmr.reportMembershipFailure(proof, current, &config)
+	}
 	return false
 }
 
+// reportMembershipFailure classifies a failed membership proof by locating
+// the peak the proof's leaf index belongs to.
+func (mmr *MmrAccumulator) reportMembershipFailure(proof MmrMembershipProof, computedPeak hash.Digest, config *verifyConfig) {
+	mtIndex, peakIndex, err := LeafIndexToMtIndexAndPeakIndex(proof.LeafIndex, mmr.leafCount)
+	if err != nil {
+		config.reportCounts(diagnostics.ComponentMmr, diagnostics.ReasonLeafIndexOutOfRange, proof.LeafIndex, mmr.leafCount, proof.LeafIndex)
+		return
+	}
+
+	height := uint64(bits.Len64(mtIndex) - 1)
+	if uint64(len(proof.AuthPath)) != height {
+		config.reportCounts(diagnostics.ComponentMmr, diagnostics.ReasonAuthPathLength, proof.LeafIndex, height, uint64(len(proof.AuthPath)))
+		return
+	}
+	if int(peakIndex) >= len(mmr.peaks) {
+		// Inconsistent accumulator, see Validate
+		config.report(diagnostics.ComponentMmr, diagnostics.ReasonPeakMismatch, uint64(peakIndex))
+		return
+	}
+
+	config.reportDigests(diagnostics.ComponentMmr, diagnostics.ReasonPeakMismatch, uint64(peakIndex), mmr.peaks[peakIndex], computedPeak)
+}
+
 // MmrMembershipProof represents a proof that a leaf is a member of an MMR.
 type MmrMembershipProof struct {
 	// LeafIndex is the index of the leaf in the MMR (0-based).
@@ -262,9 +366,19 @@ type MmrMembershipProof struct {
 // IsConsistent checks if the MMR accumulator is self-consistent.
 // The number of peaks should equal the number of 1-bits in the leaf count.
 func (mmr *MmrAccumulator) IsConsistent() bool {
-	numPeaks := len(mmr.peaks)
+	return mmr.Validate() == nil
+}
+
+// Validate checks the structural invariants of the accumulator, e.g. after
+// restoring it from storage. The number of peaks must equal the number of
+// 1-bits in the leaf count, since each peak is a perfect tree whose size is
+// one power of two in the binary expansion of the leaf count.
+func (mmr *MmrAccumulator) Validate() error {
 	expectedPeaks := bits.OnesCount64(mmr.leafCount)
-	return numPeaks == expectedPeaks
+	if len(mmr.peaks) != expectedPeaks {
+		return fmt.Errorf("MMR with %d leafs must have %d peaks, got %d", mmr.leafCount, expectedPeaks, len(mmr.peaks))
+	}
+	return nil
 }
 
 // Clone creates a deep copy of the MMR accumulator.
@@ -276,14 +390,3 @@ func (mmr *MmrAccumulator) Clone() *MmrAccumulator {
 		peaks:     peaks,
 	}
 }
-
-// trailingOnes64 returns the number of trailing one bits in x.
-// Equivalent to Rust's u64::trailing_ones().
-func trailingOnes64(x uint64) int {
-	if x == 0 {
-		return 0
-	}
-	// Count trailing zeros of the bitwise NOT
-	// This is equivalent to counting trailing ones
-	return bits.TrailingZeros64(^x)
-}
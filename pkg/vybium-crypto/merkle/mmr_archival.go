@@ -0,0 +1,165 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// ArchivalMmr is an MMR that stores every node, not only the peaks. Unlike
+// an MmrAccumulator it can prove membership of any leaf at any time and
+// materialize the perfect Merkle tree under a peak.
+//
+// Nodes are stored by node position (see NodePositionHeight): the node at
+// position p is nodes[p-1].
+//
+// An ArchivalMmr is not safe for concurrent use.
+type ArchivalMmr struct {
+	nodes     []hash.Digest
+	leafCount uint64
+}
+
+// NewArchivalMmr creates an archival MMR holding the given leafs.
+func NewArchivalMmr(leafs []hash.Digest) *ArchivalMmr {
+	mmr := &ArchivalMmr{nodes: make([]hash.Digest, 0, NumNodesFromLeafCount(uint64(len(leafs))))}
+	for _, leaf := range leafs {
+		mmr.Append(leaf)
+	}
+	return mmr
+}
+
+// NumLeafs returns the number of leafs in the MMR.
+func (mmr *ArchivalMmr) NumLeafs() uint64 {
+	return mmr.leafCount
+}
+
+// IsEmpty returns true if the MMR has no leafs.
+func (mmr *ArchivalMmr) IsEmpty() bool {
+	return mmr.leafCount == 0
+}
+
+// Peaks returns the peaks of the MMR, ordered left to right.
+func (mmr *ArchivalMmr) Peaks() []hash.Digest {
+	positions := PeakPositions(mmr.leafCount)
+	peaks := make([]hash.Digest, len(positions))
+	for i, position := range positions {
+		peaks[i] = mmr.nodes[position-1]
+	}
+	return peaks
+}
+
+// Accumulator returns the MmrAccumulator of the MMR. It shares no memory
+// with the archival MMR.
+func (mmr *ArchivalMmr) Accumulator() *MmrAccumulator {
+	return NewMmrAccumulator(mmr.Peaks(), mmr.leafCount)
+}
+
+// BagPeaks calculates a commitment to the entire MMR, equal to that of its
+// Accumulator.
+func (mmr *ArchivalMmr) BagPeaks() hash.Digest {
+	return mmr.Accumulator().BagPeaks()
+}
+
+// Append adds a leaf to the MMR and returns its membership proof. The new
+// leaf is merged with the peaks to its left for as long as they have its
+// height, exactly as MmrAccumulator.Append does.
+func (mmr *ArchivalMmr) Append(newLeaf hash.Digest) MmrMembershipProof {
+	proof := MmrMembershipProof{LeafIndex: mmr.leafCount, AuthPath: []hash.Digest{}}
+	mmr.nodes = append(mmr.nodes, newLeaf)
+	mmr.leafCount++
+
+	// The node just stored is a right child whenever the next position is
+	// higher; its left sibling is the root of the subtree before it
+	position := uint64(len(mmr.nodes))
+	for height := uint32(0); NodePositionHeight(position+1) > height; height++ {
+		left := mmr.nodes[position-(uint64(1)<<(height+1))]
+		proof.AuthPath = append(proof.AuthPath, left)
+		mmr.nodes = append(mmr.nodes, hash.HashPair(left, mmr.nodes[position-1]))
+		position++
+	}
+	return proof
+}
+
+// VerifyMembership verifies a membership proof against the MMR, as
+// MmrAccumulator.VerifyMembership does.
+func (mmr *ArchivalMmr) VerifyMembership(leaf hash.Digest, proof MmrMembershipProof, opts ...VerifyOption) bool {
+	return mmr.Accumulator().VerifyMembership(leaf, proof, opts...)
+}
+
+// GetLeaf returns the leaf with the given index.
+func (mmr *ArchivalMmr) GetLeaf(leafIndex uint64) (hash.Digest, error) {
+	if leafIndex >= mmr.leafCount {
+		return hash.Digest{}, fmt.Errorf("leaf index %d out of range [0, %d)", leafIndex, mmr.leafCount)
+	}
+	return mmr.nodes[LeafIndexToNodePosition(leafIndex)-1], nil
+}
+
+// ProveMembership returns the membership proof of the leaf with the given
+// index against the current peaks.
+func (mmr *ArchivalMmr) ProveMembership(leafIndex uint64) (MmrMembershipProof, error) {
+	mtIndex, _, err := LeafIndexToMtIndexAndPeakIndex(leafIndex, mmr.leafCount)
+	if err != nil {
+		return MmrMembershipProof{}, err
+	}
+
+	// Climb from the leaf to its peak, one sibling per level
+	position := LeafIndexToNodePosition(leafIndex)
+	authPath := make([]hash.Digest, 0, bits.Len64(mtIndex)-1)
+	for height := uint32(0); mtIndex > RootIndex; height, mtIndex = height+1, mtIndex/2 {
+		subtreeSize := (uint64(1) << (height + 1)) - 1
+		if mtIndex&1 == 1 {
+			// Right child: the left sibling's subtree precedes it
+			authPath = append(authPath, mmr.nodes[position-subtreeSize-1])
+			position++
+		} else {
+			// Left child: the parent follows the right sibling's subtree
+			authPath = append(authPath, mmr.nodes[position+subtreeSize-1])
+			position += subtreeSize + 1
+		}
+	}
+	return MmrMembershipProof{LeafIndex: leafIndex, AuthPath: authPath}, nil
+}
+
+// PeakAsMerkleTree returns the perfect Merkle tree under the peak with the
+// given index, ordered left to right as in Peaks. Its Root equals the peak
+// and its leafs are the MMR leafs under the peak, so an authentication path
+// from the tree is a proof against the peak (see MembershipProofFromPeakProof)
+// and PeakProof turns the MMR's proofs into paths of the tree. The tree is a
+// copy: appending to the MMR afterwards leaves it unchanged.
+//
+// Returns an error if the peak index is out of range.
+func (mmr *ArchivalMmr) PeakAsMerkleTree(peakIndex int) (*MerkleTree, error) {
+	heights := PeakHeights(mmr.leafCount)
+	if peakIndex < 0 || peakIndex >= len(heights) {
+		return nil, fmt.Errorf("peak index %d out of range [0, %d)", peakIndex, len(heights))
+	}
+
+	height := heights[peakIndex]
+	nodes := make([]hash.Digest, uint64(2)<<height)
+	mmr.copySubtree(nodes, RootIndex, PeakPositions(mmr.leafCount)[peakIndex], height)
+	return &MerkleTree{nodes: nodes}, nil
+}
+
+// AsMerkleTree returns the whole MMR as one Merkle tree, which it is exactly
+// when the number of leafs is a power of two. It is PeakAsMerkleTree(0) for
+// those MMRs.
+//
+// Returns an error if the MMR is empty or has more than one peak.
+func (mmr *ArchivalMmr) AsMerkleTree() (*MerkleTree, error) {
+	if !isPowerOfTwo(mmr.leafCount) {
+		return nil, fmt.Errorf("an MMR of %d leafs is not a single perfect Merkle tree", mmr.leafCount)
+	}
+	return mmr.PeakAsMerkleTree(0)
+}
+
+// copySubtree copies the subtree of the given height rooted at the node
+// position into the MerkleTree node array, rooted at nodeIndex.
+func (mmr *ArchivalMmr) copySubtree(nodes []hash.Digest, nodeIndex MerkleTreeNodeIndex, position uint64, height uint32) {
+	nodes[nodeIndex] = mmr.nodes[position-1]
+	if height == 0 {
+		return
+	}
+	mmr.copySubtree(nodes, 2*nodeIndex, position-(uint64(1)<<height), height-1)
+	mmr.copySubtree(nodes, 2*nodeIndex+1, position-1, height-1)
+}
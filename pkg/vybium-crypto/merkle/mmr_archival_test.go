@@ -0,0 +1,172 @@
+package merkle
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestArchivalMmrMatchesAccumulator(t *testing.T) {
+	for leafCount := 0; leafCount <= 70; leafCount++ {
+		leafs := createTestLeafs(leafCount)
+		archival := NewArchivalMmr(nil)
+		accumulator := NewMmrAccumulatorFromLeafs(nil)
+		for i, leaf := range leafs {
+			archivalProof := archival.Append(leaf)
+			accumulatorProof := accumulator.Append(leaf)
+			if !slices.Equal(archivalProof.AuthPath, accumulatorProof.AuthPath) {
+				t.Fatalf("size %d: append proof of leaf %d differs from the accumulator's", leafCount, i)
+			}
+		}
+
+		if !slices.Equal(archival.Peaks(), accumulator.Peaks()) {
+			t.Fatalf("size %d: peaks differ from the accumulator's", leafCount)
+		}
+		if archival.BagPeaks() != accumulator.BagPeaks() {
+			t.Fatalf("size %d: bagged peaks differ from the accumulator's", leafCount)
+		}
+		for i, leaf := range leafs {
+			proof, err := archival.ProveMembership(uint64(i))
+			if err != nil {
+				t.Fatalf("size %d leaf %d: %v", leafCount, i, err)
+			}
+			if !accumulator.VerifyMembership(leaf, proof) {
+				t.Fatalf("size %d leaf %d: proof does not verify against the accumulator", leafCount, i)
+			}
+		}
+		if _, err := archival.ProveMembership(uint64(leafCount)); err == nil {
+			t.Errorf("size %d: expected error for out-of-range leaf index", leafCount)
+		}
+	}
+}
+
+func TestArchivalMmrPeakAsMerkleTreeRoundTrip(t *testing.T) {
+	for k := 0; k <= 10; k++ {
+		leafs := createTestLeafs(1 << k)
+		mmr := NewArchivalMmr(leafs)
+
+		tree, err := mmr.PeakAsMerkleTree(0)
+		if err != nil {
+			t.Fatalf("2^%d: %v", k, err)
+		}
+		want, err := New(leafs)
+		if err != nil {
+			t.Fatalf("2^%d: failed to build Merkle tree: %v", k, err)
+		}
+		if !slices.Equal(tree.nodes, want.nodes) {
+			t.Fatalf("2^%d: extracted tree differs from the tree built from the leafs", k)
+		}
+		if tree.Root() != mmr.Peaks()[0] {
+			t.Fatalf("2^%d: root is not the peak", k)
+		}
+		if back := MmrFromMerkleTree(tree); back.BagPeaks() != mmr.BagPeaks() || back.NumLeafs() != mmr.NumLeafs() {
+			t.Fatalf("2^%d: MmrFromMerkleTree does not recover the MMR", k)
+		}
+
+		whole, err := mmr.AsMerkleTree()
+		if err != nil {
+			t.Fatalf("2^%d: AsMerkleTree: %v", k, err)
+		}
+		if whole.Root() != tree.Root() {
+			t.Fatalf("2^%d: AsMerkleTree differs from PeakAsMerkleTree(0)", k)
+		}
+	}
+}
+
+func TestArchivalMmrPeakAsMerkleTreeProofInterchange(t *testing.T) {
+	for _, leafCount := range []int{1, 8, 13, 64, 100} {
+		leafs := createTestLeafs(leafCount)
+		mmr := NewArchivalMmr(leafs)
+		peaks := mmr.Peaks()
+
+		offset := 0
+		for peakIndex, height := range PeakHeights(uint64(leafCount)) {
+			tree, err := mmr.PeakAsMerkleTree(peakIndex)
+			if err != nil {
+				t.Fatalf("size %d peak %d: %v", leafCount, peakIndex, err)
+			}
+			if tree.Root() != peaks[peakIndex] {
+				t.Fatalf("size %d peak %d: root is not the peak", leafCount, peakIndex)
+			}
+
+			for i := 0; i < 1<<height; i++ {
+				leafIndex := uint64(offset + i)
+
+				// MMR proof to Merkle tree path
+				proof, err := mmr.ProveMembership(leafIndex)
+				if err != nil {
+					t.Fatalf("size %d leaf %d: %v", leafCount, leafIndex, err)
+				}
+				gotPeak, path, position, err := PeakProof(leafIndex, uint64(leafCount), proof.AuthPath)
+				if err != nil || gotPeak != peakIndex || position != uint64(i) {
+					t.Fatalf("size %d leaf %d: PeakProof gave (%d, %d, %v)", leafCount, leafIndex, gotPeak, position, err)
+				}
+				if !VerifyInclusionProof(tree.Root(), position, leafs[leafIndex], path) {
+					t.Fatalf("size %d leaf %d: MMR proof does not verify against the tree", leafCount, leafIndex)
+				}
+
+				// Merkle tree path to MMR proof
+				treePath, err := tree.AuthenticationPath(uint64(i))
+				if err != nil {
+					t.Fatalf("size %d leaf %d: %v", leafCount, leafIndex, err)
+				}
+				fromTree, err := MembershipProofFromPeakProof(peakIndex, uint64(i), uint64(leafCount), treePath)
+				if err != nil {
+					t.Fatalf("size %d leaf %d: %v", leafCount, leafIndex, err)
+				}
+				if !mmr.VerifyMembership(leafs[leafIndex], fromTree) {
+					t.Fatalf("size %d leaf %d: tree path does not verify against the MMR", leafCount, leafIndex)
+				}
+			}
+			offset += 1 << height
+		}
+	}
+}
+
+func TestArchivalMmrPeakAsMerkleTreeIsASnapshot(t *testing.T) {
+	leafs := createTestLeafs(16)
+	mmr := NewArchivalMmr(leafs[:8])
+	tree, err := mmr.PeakAsMerkleTree(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := append([]hash.Digest(nil), tree.nodes...)
+
+	// The 8 leafs merge into a single peak of 16 leafs
+	for _, leaf := range leafs[8:] {
+		mmr.Append(leaf)
+	}
+	if !slices.Equal(tree.nodes, before) {
+		t.Fatal("appending to the MMR modified the extracted tree")
+	}
+	if mmr.Peaks()[0] == tree.Root() {
+		t.Fatal("expected the MMR's peak to have changed")
+	}
+	for i := 0; i < 8; i++ {
+		path, _ := tree.AuthenticationPath(uint64(i))
+		if !VerifyInclusionProof(tree.Root(), uint64(i), leafs[i], path) {
+			t.Fatalf("leaf %d no longer verifies against the extracted tree", i)
+		}
+	}
+}
+
+func TestArchivalMmrPeakAsMerkleTreeErrors(t *testing.T) {
+	if _, err := NewArchivalMmr(nil).PeakAsMerkleTree(0); err == nil {
+		t.Error("expected error for a peak of an empty MMR")
+	}
+
+	// 13 = 0b1101 has three peaks
+	mmr := NewArchivalMmr(createTestLeafs(13))
+	for _, peakIndex := range []int{-1, 3} {
+		if _, err := mmr.PeakAsMerkleTree(peakIndex); err == nil {
+			t.Errorf("expected error for peak index %d", peakIndex)
+		}
+	}
+
+	for _, leafCount := range []int{0, 3, 5, 12, 13, 1023} {
+		if _, err := NewArchivalMmr(createTestLeafs(leafCount)).AsMerkleTree(); err == nil {
+			t.Errorf("size %d: expected error for a full tree of a partial peak", leafCount)
+		}
+	}
+}
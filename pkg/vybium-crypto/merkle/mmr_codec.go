@@ -0,0 +1,137 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Encode returns the canonical BFieldCodec encoding of the accumulator:
+//
+//	[leafCount as u64 (2 elements, low limb first), numPeaks, peak_0, ..., peak_{n-1}]
+//
+// where each peak contributes its hash.DigestLen elements. This encoding is
+// the input to Hash and therefore to BagPeaks.
+func (mmr *MmrAccumulator) Encode() []field.Element {
+	encoding := make([]field.Element, 0, 3+len(mmr.peaks)*hash.DigestLen)
+	encoding = append(encoding, bfieldcodec.EncodeUint64(mmr.leafCount)...)
+	encoding = append(encoding, field.New(uint64(len(mmr.peaks))))
+	header := len(encoding)
+	encoding = encoding[:header+len(mmr.peaks)*hash.DigestLen]
+	hash.DigestsToElementsInto(encoding[header:], mmr.peaks)
+	return encoding
+}
+
+// DecodeMmrAccumulator decodes an accumulator from its canonical encoding.
+// Returns an error if the sequence is malformed or if the number of peaks is
+// inconsistent with the leaf count.
+func DecodeMmrAccumulator(sequence []field.Element) (*MmrAccumulator, error) {
+	return DecodeMmrAccumulatorWithDiagnostics(sequence, nil)
+}
+
+// DecodeMmrAccumulatorWithDiagnostics is DecodeMmrAccumulator, additionally
+// recording a diagnostics.Event for a failure, located at the offending
+// element offset. A nil collector is ignored.
+func DecodeMmrAccumulatorWithDiagnostics(sequence []field.Element, d *diagnostics.Diagnostics) (*MmrAccumulator, error) {
+	config := verifyConfig{diagnostics: d}
+
+	if len(sequence) < 3 {
+		config.reportCounts(diagnostics.ComponentCodec, diagnostics.ReasonSequenceTooShort, uint64(len(sequence)), 3, uint64(len(sequence)))
+		return nil, fmt.Errorf("MMR accumulator encoding too short: %d elements", len(sequence))
+	}
+
+	leafCount, err := bfieldcodec.DecodeUint64(sequence[:2])
+	if err != nil {
+		limb := uint64(0)
+		if sequence[0].Value() <= math.MaxUint32 {
+			limb = 1
+		}
+		config.reportCounts(diagnostics.ComponentCodec, diagnostics.ReasonElementOutOfRange, limb, math.MaxUint32, sequence[limb].Value())
+		return nil, fmt.Errorf("invalid leaf count: %w", err)
+	}
+
+	numPeaks := sequence[2].Value()
+	expectedPeaks := uint64(bits.OnesCount64(leafCount))
+	if numPeaks != expectedPeaks {
+		config.reportCounts(diagnostics.ComponentCodec, diagnostics.ReasonInvalidLengthIndicator, 2, expectedPeaks, numPeaks)
+		return nil, fmt.Errorf("MMR with %d leafs must have %d peaks, got %d", leafCount, expectedPeaks, numPeaks)
+	}
+
+	body := sequence[3:]
+	if uint64(len(body)) != numPeaks*hash.DigestLen {
+		reason := diagnostics.ReasonSequenceTooShort
+		if uint64(len(body)) > numPeaks*hash.DigestLen {
+			reason = diagnostics.ReasonSequenceTooLong
+		}
+		config.reportCounts(diagnostics.ComponentCodec, reason, 3+min(uint64(len(body)), numPeaks*hash.DigestLen), numPeaks*hash.DigestLen, uint64(len(body)))
+		return nil, fmt.Errorf("peak list has %d elements, expected %d", len(body), numPeaks*hash.DigestLen)
+	}
+
+	peaks, err := hash.ElementsToDigests(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMmrAccumulator(peaks, leafCount), nil
+}
+
+// Hash returns the digest of the accumulator's canonical encoding.
+func (mmr *MmrAccumulator) Hash() hash.Digest {
+	return hash.HashVarlen(mmr.Encode())
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// Each element of the canonical encoding is written as its canonical
+// (non-Montgomery) value in 8 little-endian bytes.
+func (mmr *MmrAccumulator) MarshalBinary() ([]byte, error) {
+	return marshalElements(mmr.Encode()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (mmr *MmrAccumulator) UnmarshalBinary(data []byte) error {
+	sequence, err := unmarshalElements(data)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := DecodeMmrAccumulator(sequence)
+	if err != nil {
+		return err
+	}
+	*mmr = *decoded
+	return nil
+}
+
+// marshalElements writes each element as its canonical (non-Montgomery)
+// value in 8 little-endian bytes.
+func marshalElements(elements []field.Element) []byte {
+	data := make([]byte, 8*len(elements))
+	for i, element := range elements {
+		binary.LittleEndian.PutUint64(data[8*i:], element.Value())
+	}
+	return data
+}
+
+// unmarshalElements is the inverse of marshalElements.
+// Returns an error if the data is not a whole number of canonical elements.
+func unmarshalElements(data []byte) ([]field.Element, error) {
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("invalid data length %d: must be a multiple of 8", len(data))
+	}
+
+	sequence := make([]field.Element, len(data)/8)
+	for i := range sequence {
+		value := binary.LittleEndian.Uint64(data[8*i:])
+		if value >= field.P {
+			return nil, fmt.Errorf("non-canonical field element %d at position %d", value, i)
+		}
+		sequence[i] = field.New(value)
+	}
+	return sequence, nil
+}
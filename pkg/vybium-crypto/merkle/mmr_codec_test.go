@@ -0,0 +1,198 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// legacyMmrSnapshot is the gob layout previously used to persist
+// accumulators, kept here to test migration to the canonical format.
+type legacyMmrSnapshot struct {
+	LeafCount uint64
+	Peaks     [][hash.DigestLen]uint64
+}
+
+func TestMmrEncodeRoundTrip(t *testing.T) {
+	for _, numLeafs := range []int{0, 1, 2, 3, 7, 8, 13, 64, 100} {
+		mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(numLeafs))
+
+		decoded, err := DecodeMmrAccumulator(mmr.Encode())
+		if err != nil {
+			t.Fatalf("%d leafs: decode failed: %v", numLeafs, err)
+		}
+		if decoded.NumLeafs() != mmr.NumLeafs() || decoded.BagPeaks() != mmr.BagPeaks() {
+			t.Errorf("%d leafs: element round trip mismatch", numLeafs)
+		}
+
+		data, err := mmr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%d leafs: marshal failed: %v", numLeafs, err)
+		}
+		var unmarshalled MmrAccumulator
+		if err := unmarshalled.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%d leafs: unmarshal failed: %v", numLeafs, err)
+		}
+		if unmarshalled.NumLeafs() != mmr.NumLeafs() || unmarshalled.BagPeaks() != mmr.BagPeaks() {
+			t.Errorf("%d leafs: binary round trip mismatch", numLeafs)
+		}
+	}
+}
+
+func TestMmrEncodeGolden(t *testing.T) {
+	// Explicit peaks keep the binary golden independent of the hash function
+	peaks := []hash.Digest{
+		{field.New(1), field.New(2), field.New(3), field.New(4), field.New(5)},
+		{field.New(6), field.New(7), field.New(8), field.New(9), field.New(10)},
+	}
+
+	tests := []struct {
+		name      string
+		mmr       *MmrAccumulator
+		binaryHex string
+	}{
+		{
+			name:      "empty",
+			mmr:       NewMmrAccumulator(nil, 0),
+			binaryHex: "000000000000000000000000000000000000000000000000",
+		},
+		{
+			name: "three leafs",
+			mmr:  NewMmrAccumulator(peaks, 3),
+			binaryHex: "0300000000000000" + "0000000000000000" + "0200000000000000" +
+				"01000000000000000200000000000000030000000000000004000000000000000500000000000000" +
+				"06000000000000000700000000000000080000000000000009000000000000000a00000000000000",
+		},
+		{
+			name: "leaf count above 2^32",
+			mmr:  NewMmrAccumulator(peaks, 1<<40|1<<3),
+			binaryHex: "0800000000000000" + "0001000000000000" + "0200000000000000" +
+				"01000000000000000200000000000000030000000000000004000000000000000500000000000000" +
+				"06000000000000000700000000000000080000000000000009000000000000000a00000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.mmr.MarshalBinary()
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if got := hex.EncodeToString(data); got != tt.binaryHex {
+				t.Errorf("binary encoding changed:\n got  %s\n want %s", got, tt.binaryHex)
+			}
+		})
+	}
+}
+
+func TestMmrBagPeaksIsHashOfEncoding(t *testing.T) {
+	for _, numLeafs := range []int{0, 1, 5, 32} {
+		mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(numLeafs))
+		if mmr.BagPeaks() != hash.HashVarlen(mmr.Encode()) {
+			t.Errorf("%d leafs: BagPeaks differs from hash of encoding", numLeafs)
+		}
+	}
+
+	// Same peaks under a different leaf count must not collide
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(4))
+	other := NewMmrAccumulator(mmr.Peaks(), 8)
+	if mmr.BagPeaks() == other.BagPeaks() {
+		t.Error("BagPeaks should commit to the leaf count")
+	}
+}
+
+func TestMmrDecodeErrors(t *testing.T) {
+	valid := NewMmrAccumulatorFromLeafs(createTestLeafs(5)).Encode()
+
+	wrongPeakCount := append([]field.Element{}, valid...)
+	wrongPeakCount[2] = field.New(3)
+
+	tests := []struct {
+		name     string
+		sequence []field.Element
+	}{
+		{"too short", valid[:2]},
+		{"invalid leaf count limb", append([]field.Element{field.New(1 << 32)}, valid[1:]...)},
+		{"peak count mismatch", wrongPeakCount},
+		{"truncated peaks", valid[:len(valid)-1]},
+		{"trailing elements", append(append([]field.Element{}, valid...), field.Zero)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeMmrAccumulator(tt.sequence); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMmrUnmarshalBinaryErrors(t *testing.T) {
+	data, err := NewMmrAccumulatorFromLeafs(createTestLeafs(5)).MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var mmr MmrAccumulator
+	if err := mmr.UnmarshalBinary(data[:len(data)-3]); err == nil {
+		t.Error("expected error for length not a multiple of 8")
+	}
+
+	nonCanonical := append([]byte{}, data...)
+	binary.LittleEndian.PutUint64(nonCanonical[8*3:], field.P)
+	if err := mmr.UnmarshalBinary(nonCanonical); err == nil {
+		t.Error("expected error for non-canonical element")
+	}
+}
+
+func TestMmrMigrateFromGob(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/mmr_accumulator_v0.gob")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var legacy legacyMmrSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(fixture)).Decode(&legacy); err != nil {
+		t.Fatalf("failed to decode gob fixture: %v", err)
+	}
+
+	peaks := make([]hash.Digest, len(legacy.Peaks))
+	for i, values := range legacy.Peaks {
+		for j, value := range values {
+			peaks[i][j] = field.New(value)
+		}
+	}
+
+	migrated := NewMmrAccumulator(peaks, legacy.LeafCount)
+	if err := migrated.Validate(); err != nil {
+		t.Fatalf("migrated accumulator is invalid: %v", err)
+	}
+	if migrated.NumLeafs() != 3 || len(migrated.Peaks()) != 2 {
+		t.Fatalf("unexpected migrated shape: %d leafs, %d peaks", migrated.NumLeafs(), len(migrated.Peaks()))
+	}
+
+	data, err := migrated.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var restored MmrAccumulator
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if restored.NumLeafs() != legacy.LeafCount {
+		t.Errorf("leaf count not preserved: got %d, want %d", restored.NumLeafs(), legacy.LeafCount)
+	}
+	for i, peak := range restored.Peaks() {
+		for j, value := range legacy.Peaks[i] {
+			if peak[j].Value() != value {
+				t.Errorf("peak %d element %d not preserved", i, j)
+			}
+		}
+	}
+}
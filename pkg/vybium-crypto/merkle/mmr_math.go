@@ -0,0 +1,158 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// MMR node positions follow the conventional post-order numbering used by
+// twenty-first: nodes are numbered starting at 1 in the order they are
+// created when leafs are appended left to right. Position 0 is never a valid
+// node position, mirroring the MerkleTreeNodeIndex convention.
+//
+// Example for 7 leafs:
+//
+//	       7
+//	     /   \
+//	    3     6      10
+//	   / \   / \    /  \
+//	  1   2 4   5  8    9   11
+//
+// Leafs live at positions 1, 2, 4, 5, 8, 9, 11 and the peaks at 7, 10, 11.
+
+// NumNodesFromLeafCount returns the total number of nodes (leafs and
+// internal nodes) in an MMR with the given number of leafs.
+func NumNodesFromLeafCount(leafCount uint64) uint64 {
+	return 2*leafCount - uint64(bits.OnesCount64(leafCount))
+}
+
+// LeafIndexToNodePosition returns the node position of the leaf with the
+// given (0-based) leaf index.
+func LeafIndexToNodePosition(leafIndex uint64) uint64 {
+	return NumNodesFromLeafCount(leafIndex) + 1
+}
+
+// NodePositionToLeafIndex returns the leaf index of the node at the given
+// position. The second return value is false if the position is zero or
+// does not hold a leaf.
+func NodePositionToLeafIndex(position uint64) (uint64, bool) {
+	if position == 0 || NodePositionHeight(position) != 0 {
+		return 0, false
+	}
+
+	// NumNodesFromLeafCount(n) = 2n - popcount(n), so the leaf index lies
+	// within 64 of (position-1)/2.
+	nodesBefore := position - 1
+	for leafIndex := nodesBefore / 2; leafIndex <= nodesBefore/2+64; leafIndex++ {
+		if NumNodesFromLeafCount(leafIndex) == nodesBefore {
+			return leafIndex, true
+		}
+	}
+	return 0, false
+}
+
+// NodePositionHeight returns the height of the node at the given position,
+// where leafs have height 0. Returns 0 for the invalid position 0.
+//
+// Algorithm: a position whose binary representation is all ones is the root
+// of a perfect tree of height bitlen-1. Any other position is moved onto the
+// leftmost branch by subtracting the size of the perfect tree to its left,
+// which preserves its height.
+func NodePositionHeight(position uint64) uint32 {
+	if position == 0 {
+		return 0
+	}
+	for !isAllOnes(position) {
+		position -= (uint64(1) << (bits.Len64(position) - 1)) - 1
+	}
+	return uint32(bits.Len64(position) - 1)
+}
+
+// LeftChildPosition returns the position of the left child of the node at
+// the given position. Returns 0 if the node is a leaf.
+func LeftChildPosition(position uint64) uint64 {
+	height := NodePositionHeight(position)
+	if height == 0 {
+		return 0
+	}
+	return position - (uint64(1) << height)
+}
+
+// RightChildPosition returns the position of the right child of the node at
+// the given position. Returns 0 if the node is a leaf.
+func RightChildPosition(position uint64) uint64 {
+	if NodePositionHeight(position) == 0 {
+		return 0
+	}
+	return position - 1
+}
+
+// ParentPosition returns the position of the parent of the node at the given
+// position, assuming the MMR is large enough for the parent to exist.
+// A right child's parent immediately follows it; a left child's parent
+// follows the right sibling's entire subtree. Returns 0 for position 0.
+func ParentPosition(position uint64) uint64 {
+	if position == 0 {
+		return 0
+	}
+	height := NodePositionHeight(position)
+	if NodePositionHeight(position+1) > height {
+		return position + 1
+	}
+	return position + (uint64(1) << (height + 1))
+}
+
+// PeakHeights returns the heights of the peaks of an MMR with the given
+// number of leafs, ordered left to right (strictly decreasing).
+func PeakHeights(leafCount uint64) []uint32 {
+	heights := make([]uint32, 0, bits.OnesCount64(leafCount))
+	for h := 63; h >= 0; h-- {
+		if leafCount&(uint64(1)<<h) != 0 {
+			heights = append(heights, uint32(h))
+		}
+	}
+	return heights
+}
+
+// PeakPositions returns the node positions of the peaks of an MMR with the
+// given number of leafs, ordered left to right.
+func PeakPositions(leafCount uint64) []uint64 {
+	heights := PeakHeights(leafCount)
+	positions := make([]uint64, len(heights))
+	offset := uint64(0)
+	for i, height := range heights {
+		offset += (uint64(1) << (height + 1)) - 1
+		positions[i] = offset
+	}
+	return positions
+}
+
+// LeafIndexToMtIndexAndPeakIndex locates a leaf within the MMR's peaks.
+// It returns the MerkleTreeNodeIndex of the leaf inside the perfect Merkle
+// tree rooted at its peak (using the MerkleTree convention that the root has
+// index 1) together with the index of that peak in left-to-right order.
+// This is a port of twenty-first's `leaf_index_to_mt_index_and_peak_index`.
+//
+// Returns an error if leafIndex >= leafCount.
+func LeafIndexToMtIndexAndPeakIndex(leafIndex, leafCount uint64) (MerkleTreeNodeIndex, uint32, error) {
+	if leafIndex >= leafCount {
+		return 0, 0, fmt.Errorf("leaf index %d out of range [0, %d)", leafIndex, leafCount)
+	}
+
+	offset := uint64(0)
+	for peakIndex, height := range PeakHeights(leafCount) {
+		treeSize := uint64(1) << height
+		if leafIndex < offset+treeSize {
+			return treeSize + (leafIndex - offset), uint32(peakIndex), nil
+		}
+		offset += treeSize
+	}
+
+	// Unreachable: the peaks cover exactly leafCount leafs
+	return 0, 0, fmt.Errorf("leaf index %d not covered by peaks of MMR with %d leafs", leafIndex, leafCount)
+}
+
+// isAllOnes returns true if x is of the form 2^k - 1 for some k > 0.
+func isAllOnes(x uint64) bool {
+	return x != 0 && x&(x+1) == 0
+}
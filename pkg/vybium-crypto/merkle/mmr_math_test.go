@@ -0,0 +1,193 @@
+package merkle
+
+import (
+	"math"
+	"testing"
+)
+
+const mmrMathMaxLeafCount = 100_000
+
+func TestMmrMathSmallMmr(t *testing.T) {
+	// See the 7-leaf example in mmr_math.go.
+	expectedLeafPositions := []uint64{1, 2, 4, 5, 8, 9, 11}
+	for leafIndex, expected := range expectedLeafPositions {
+		if got := LeafIndexToNodePosition(uint64(leafIndex)); got != expected {
+			t.Errorf("leaf %d: expected position %d, got %d", leafIndex, expected, got)
+		}
+	}
+
+	expectedHeights := map[uint64]uint32{1: 0, 2: 0, 3: 1, 4: 0, 5: 0, 6: 1, 7: 2, 8: 0, 9: 0, 10: 1, 11: 0}
+	for position, expected := range expectedHeights {
+		if got := NodePositionHeight(position); got != expected {
+			t.Errorf("position %d: expected height %d, got %d", position, expected, got)
+		}
+	}
+
+	peaks := PeakPositions(7)
+	expectedPeaks := []uint64{7, 10, 11}
+	if len(peaks) != len(expectedPeaks) {
+		t.Fatalf("expected %d peaks, got %d", len(expectedPeaks), len(peaks))
+	}
+	for i := range peaks {
+		if peaks[i] != expectedPeaks[i] {
+			t.Errorf("peak %d: expected position %d, got %d", i, expectedPeaks[i], peaks[i])
+		}
+	}
+
+	if NumNodesFromLeafCount(7) != 11 {
+		t.Errorf("expected 11 nodes for 7 leafs, got %d", NumNodesFromLeafCount(7))
+	}
+}
+
+func TestMmrMathLeafIndexRoundTrip(t *testing.T) {
+	for leafIndex := uint64(0); leafIndex < mmrMathMaxLeafCount; leafIndex++ {
+		position := LeafIndexToNodePosition(leafIndex)
+		if NodePositionHeight(position) != 0 {
+			t.Fatalf("leaf %d maps to non-leaf position %d", leafIndex, position)
+		}
+		got, ok := NodePositionToLeafIndex(position)
+		if !ok || got != leafIndex {
+			t.Fatalf("leaf %d: round trip through position %d gave (%d, %v)", leafIndex, position, got, ok)
+		}
+	}
+}
+
+func TestMmrMathNonLeafPositionsHaveNoLeafIndex(t *testing.T) {
+	if _, ok := NodePositionToLeafIndex(0); ok {
+		t.Error("position 0 should not map to a leaf")
+	}
+	for _, position := range []uint64{3, 6, 7, 10, 14, 15} {
+		if _, ok := NodePositionToLeafIndex(position); ok {
+			t.Errorf("internal position %d should not map to a leaf", position)
+		}
+	}
+}
+
+func TestMmrMathParentChildConsistency(t *testing.T) {
+	numNodes := NumNodesFromLeafCount(mmrMathMaxLeafCount)
+	for position := uint64(1); position <= numNodes; position++ {
+		height := NodePositionHeight(position)
+		if height == 0 {
+			if LeftChildPosition(position) != 0 || RightChildPosition(position) != 0 {
+				t.Fatalf("leaf position %d should have no children", position)
+			}
+			continue
+		}
+
+		left := LeftChildPosition(position)
+		right := RightChildPosition(position)
+		if NodePositionHeight(left) != height-1 || NodePositionHeight(right) != height-1 {
+			t.Fatalf("children of %d have wrong heights", position)
+		}
+		if ParentPosition(left) != position {
+			t.Fatalf("parent of left child %d is %d, expected %d", left, ParentPosition(left), position)
+		}
+		if ParentPosition(right) != position {
+			t.Fatalf("parent of right child %d is %d, expected %d", right, ParentPosition(right), position)
+		}
+	}
+}
+
+func TestMmrMathPeakHeightsStrictlyDecreasing(t *testing.T) {
+	for leafCount := uint64(0); leafCount <= mmrMathMaxLeafCount; leafCount++ {
+		heights := PeakHeights(leafCount)
+		positions := PeakPositions(leafCount)
+
+		if len(heights) != len(positions) {
+			t.Fatalf("leaf count %d: %d heights but %d positions", leafCount, len(heights), len(positions))
+		}
+
+		numLeafs := uint64(0)
+		for i, height := range heights {
+			if i > 0 && height >= heights[i-1] {
+				t.Fatalf("leaf count %d: peak heights not strictly decreasing: %v", leafCount, heights)
+			}
+			if NodePositionHeight(positions[i]) != height {
+				t.Fatalf("leaf count %d: peak %d at position %d has height %d, expected %d",
+					leafCount, i, positions[i], NodePositionHeight(positions[i]), height)
+			}
+			numLeafs += uint64(1) << height
+		}
+
+		if numLeafs != leafCount {
+			t.Fatalf("leaf count %d: peaks cover %d leafs", leafCount, numLeafs)
+		}
+		if leafCount > 0 && positions[len(positions)-1] != NumNodesFromLeafCount(leafCount) {
+			t.Fatalf("leaf count %d: last peak at %d, expected %d",
+				leafCount, positions[len(positions)-1], NumNodesFromLeafCount(leafCount))
+		}
+	}
+}
+
+func TestMmrPeakIteration(t *testing.T) {
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(13))
+	peaks := mmr.Peaks()
+
+	var visited []PeakInfo
+	mmr.ForEachPeak(func(info PeakInfo) bool {
+		visited = append(visited, info)
+		return true
+	})
+
+	if len(visited) != len(peaks) {
+		t.Fatalf("expected %d peaks, visited %d", len(peaks), len(visited))
+	}
+	expectedHeights := []uint32{3, 2, 0}
+	for i, info := range visited {
+		if info.Index != i || info.Height != expectedHeights[i] || !info.Digest.Equal(peaks[i]) {
+			t.Errorf("peak %d: unexpected info %+v", i, info)
+		}
+	}
+
+	// Early termination
+	count := 0
+	mmr.ForEachPeak(func(PeakInfo) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("expected iteration to stop after 1 peak, visited %d", count)
+	}
+}
+
+func TestMmrPeaksPage(t *testing.T) {
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(15))
+
+	page, err := mmr.PeaksPage(1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 2 || page[0].Index != 1 || page[1].Index != 2 {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	page, err = mmr.PeaksPage(3, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 1 || page[0].Index != 3 {
+		t.Errorf("unexpected last page: %+v", page)
+	}
+
+	page, err = mmr.PeaksPage(4, 10)
+	if err != nil || len(page) != 0 {
+		t.Errorf("expected empty page past the end, got %+v, %v", page, err)
+	}
+
+	// A huge limit must not size the page
+	page, err = mmr.PeaksPage(1, math.MaxInt)
+	if err != nil || len(page) != 3 || cap(page) != 3 {
+		t.Errorf("expected a page of exactly 3 peaks, got len %d cap %d, %v", len(page), cap(page), err)
+	}
+	page, err = mmr.PeaksPage(math.MaxInt, math.MaxInt)
+	if err != nil || cap(page) != 0 {
+		t.Errorf("expected an unallocated page past the end, got cap %d, %v", cap(page), err)
+	}
+
+	if _, err := mmr.PeaksPage(-1, 1); err == nil {
+		t.Error("expected error for negative offset")
+	}
+	if _, err := mmr.PeaksPage(0, 0); err == nil {
+		t.Error("expected error for zero limit")
+	}
+}
@@ -0,0 +1,83 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// PeakProof expresses an MMR membership proof as a plain Merkle inclusion proof
+// against one of the MMR's peaks.
+//
+// It returns the index of the peak (matching the ordering of Peaks()), the
+// authentication path to pass to VerifyInclusionProof, and the leaf's index
+// within the perfect Merkle tree rooted at that peak. The leaf then satisfies
+//
+//	VerifyInclusionProof(peaks[peakIndex], leafPositionInPeakTree, leaf, pathForPlainVerify)
+//
+// Returns an error if the leaf index is out of range or if the authentication
+// path length does not match the height of the leaf's peak.
+func PeakProof(leafIndex uint64, leafCount uint64, authPath []hash.Digest) (peakIndex int, pathForPlainVerify []hash.Digest, leafPositionInPeakTree uint64, err error) {
+	mtIndex, peak, err := LeafIndexToMtIndexAndPeakIndex(leafIndex, leafCount)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	// The peak tree's height is the number of levels below its root
+	height := bits.Len64(mtIndex) - 1
+	if len(authPath) != height {
+		return 0, nil, 0, fmt.Errorf("authentication path has length %d, expected %d for leaf %d", len(authPath), height, leafIndex)
+	}
+
+	path := make([]hash.Digest, len(authPath))
+	copy(path, authPath)
+
+	return int(peak), path, mtIndex - (uint64(1) << height), nil
+}
+
+// MembershipProofFromPeakProof is the inverse of PeakProof: it reconstructs an
+// MmrMembershipProof from a plain Merkle inclusion proof against the peak with
+// the given index in an MMR with leafCount leafs.
+//
+// Returns an error if the peak index or leaf position is out of range, or if
+// the path length does not match the height of the peak.
+func MembershipProofFromPeakProof(peakIndex int, leafPositionInPeakTree uint64, leafCount uint64, path []hash.Digest) (MmrMembershipProof, error) {
+	heights := PeakHeights(leafCount)
+	if peakIndex < 0 || peakIndex >= len(heights) {
+		return MmrMembershipProof{}, fmt.Errorf("peak index %d out of range [0, %d)", peakIndex, len(heights))
+	}
+
+	height := heights[peakIndex]
+	if leafPositionInPeakTree >= uint64(1)<<height {
+		return MmrMembershipProof{}, fmt.Errorf("leaf position %d out of range for peak of height %d", leafPositionInPeakTree, height)
+	}
+	if len(path) != int(height) {
+		return MmrMembershipProof{}, fmt.Errorf("path has length %d, expected %d", len(path), height)
+	}
+
+	leafIndex := leafPositionInPeakTree
+	for _, h := range heights[:peakIndex] {
+		leafIndex += uint64(1) << h
+	}
+
+	authPath := make([]hash.Digest, len(path))
+	copy(authPath, path)
+
+	return MmrMembershipProof{
+		LeafIndex: leafIndex,
+		AuthPath:  authPath,
+	}, nil
+}
+
+// MmrFromMerkleTree returns the accumulator of the MMR whose leafs are the
+// tree's leafs. The tree has a power-of-two number of leafs, so the MMR has a
+// single peak, the tree's root, and proofs against the tree are proofs
+// against peak 0. The leafs of a tree built by NewPadded include its
+// padding. An empty tree gives an empty accumulator.
+func MmrFromMerkleTree(tree *MerkleTree) *MmrAccumulator {
+	if tree.paddedNumLeafs() == 0 {
+		return NewMmrAccumulator([]hash.Digest{}, 0)
+	}
+	return NewMmrAccumulator([]hash.Digest{tree.Root()}, tree.paddedNumLeafs())
+}
@@ -0,0 +1,159 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// mmrAuthPaths computes the authentication path of every leaf in the MMR over
+// the given leafs by building the Merkle tree under each peak.
+func mmrAuthPaths(t *testing.T, leafs []hash.Digest) [][]hash.Digest {
+	t.Helper()
+
+	paths := make([][]hash.Digest, 0, len(leafs))
+	offset := 0
+	for _, height := range PeakHeights(uint64(len(leafs))) {
+		size := 1 << height
+		tree, err := New(leafs[offset : offset+size])
+		if err != nil {
+			t.Fatalf("failed to build peak tree: %v", err)
+		}
+		for i := 0; i < size; i++ {
+			path, err := tree.AuthenticationPath(uint64(i))
+			if err != nil {
+				t.Fatalf("failed to get authentication path: %v", err)
+			}
+			paths = append(paths, path)
+		}
+		offset += size
+	}
+	return paths
+}
+
+func TestLeafIndexToMtIndexAndPeakIndex(t *testing.T) {
+	// 13 = 0b1101: peaks of heights 3, 2, 0 covering leafs [0,8), [8,12), [12,13)
+	tests := []struct {
+		leafIndex uint64
+		mtIndex   uint64
+		peakIndex uint32
+	}{
+		{0, 8, 0},
+		{7, 15, 0},
+		{8, 4, 1},
+		{11, 7, 1},
+		{12, 1, 2},
+	}
+
+	for _, tt := range tests {
+		mtIndex, peakIndex, err := LeafIndexToMtIndexAndPeakIndex(tt.leafIndex, 13)
+		if err != nil {
+			t.Fatalf("leaf %d: unexpected error %v", tt.leafIndex, err)
+		}
+		if mtIndex != tt.mtIndex || peakIndex != tt.peakIndex {
+			t.Errorf("leaf %d: got (%d, %d), expected (%d, %d)",
+				tt.leafIndex, mtIndex, peakIndex, tt.mtIndex, tt.peakIndex)
+		}
+	}
+
+	if _, _, err := LeafIndexToMtIndexAndPeakIndex(13, 13); err == nil {
+		t.Error("expected error for out-of-range leaf index")
+	}
+}
+
+func TestPeakProofBridgesToPlainMerkleVerification(t *testing.T) {
+	for leafCount := 1; leafCount <= 300; leafCount++ {
+		leafs := createTestLeafs(leafCount)
+		mmr := NewMmrAccumulatorFromLeafs(leafs)
+		peaks := mmr.Peaks()
+		paths := mmrAuthPaths(t, leafs)
+
+		for leafIndex := 0; leafIndex < leafCount; leafIndex++ {
+			peakIndex, path, position, err := PeakProof(uint64(leafIndex), uint64(leafCount), paths[leafIndex])
+			if err != nil {
+				t.Fatalf("size %d leaf %d: %v", leafCount, leafIndex, err)
+			}
+			if peakIndex < 0 || peakIndex >= len(peaks) {
+				t.Fatalf("size %d leaf %d: peak index %d out of range", leafCount, leafIndex, peakIndex)
+			}
+			if !VerifyInclusionProof(peaks[peakIndex], position, leafs[leafIndex], path) {
+				t.Fatalf("size %d leaf %d: bridged proof does not verify against peak %d", leafCount, leafIndex, peakIndex)
+			}
+
+			proof, err := MembershipProofFromPeakProof(peakIndex, position, uint64(leafCount), path)
+			if err != nil {
+				t.Fatalf("size %d leaf %d: inverse failed: %v", leafCount, leafIndex, err)
+			}
+			if proof.LeafIndex != uint64(leafIndex) || len(proof.AuthPath) != len(paths[leafIndex]) {
+				t.Fatalf("size %d leaf %d: inverse produced %+v", leafCount, leafIndex, proof)
+			}
+		}
+	}
+}
+
+func TestPeakProofErrors(t *testing.T) {
+	leafs := createTestLeafs(13)
+	paths := mmrAuthPaths(t, leafs)
+
+	if _, _, _, err := PeakProof(13, 13, nil); err == nil {
+		t.Error("expected error for out-of-range leaf index")
+	}
+	if _, _, _, err := PeakProof(0, 13, paths[0][:2]); err == nil {
+		t.Error("expected error for short authentication path")
+	}
+
+	if _, err := MembershipProofFromPeakProof(3, 0, 13, nil); err == nil {
+		t.Error("expected error for out-of-range peak index")
+	}
+	if _, err := MembershipProofFromPeakProof(1, 4, 13, paths[8]); err == nil {
+		t.Error("expected error for out-of-range leaf position")
+	}
+	if _, err := MembershipProofFromPeakProof(1, 0, 13, paths[0]); err == nil {
+		t.Error("expected error for path length mismatch")
+	}
+}
+
+func TestMmrFromMerkleTree(t *testing.T) {
+	for k := 0; k <= 10; k++ {
+		leafs := createTestLeafs(1 << k)
+		tree, err := New(leafs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mmr := MmrFromMerkleTree(tree)
+		fromLeafs := NewMmrAccumulatorFromLeafs(leafs)
+		if mmr.NumLeafs() != uint64(len(leafs)) || len(mmr.Peaks()) != 1 {
+			t.Fatalf("2^%d leafs: got %d leafs and %d peaks", k, mmr.NumLeafs(), len(mmr.Peaks()))
+		}
+		if mmr.Peaks()[0] != tree.Root() || mmr.BagPeaks() != fromLeafs.BagPeaks() {
+			t.Fatalf("2^%d leafs: accumulator differs from the one built from the leafs", k)
+		}
+
+		// Tree proofs are MMR proofs against peak 0, and back
+		for _, leafIndex := range []uint64{0, uint64(len(leafs)) / 3, uint64(len(leafs)) - 1} {
+			path, _ := tree.AuthenticationPath(leafIndex)
+			proof, err := MembershipProofFromPeakProof(0, leafIndex, mmr.NumLeafs(), path)
+			if err != nil {
+				t.Fatalf("2^%d leafs, leaf %d: %v", k, leafIndex, err)
+			}
+			peakIndex, peakPath, position, err := PeakProof(proof.LeafIndex, mmr.NumLeafs(), proof.AuthPath)
+			if err != nil || peakIndex != 0 || position != leafIndex {
+				t.Fatalf("2^%d leafs, leaf %d: got peak %d position %d, err %v", k, leafIndex, peakIndex, position, err)
+			}
+			if !VerifyInclusionProof(mmr.Peaks()[peakIndex], position, leafs[leafIndex], peakPath) {
+				t.Fatalf("2^%d leafs, leaf %d: proof does not verify against the peak", k, leafIndex)
+			}
+		}
+
+		// Appending to the accumulator leaves the tree untouched
+		mmr.Append(createTestLeafs(1)[0])
+		if tree.Root() != fromLeafs.Peaks()[0] || tree.NumLeafs() != uint64(len(leafs)) {
+			t.Fatalf("2^%d leafs: append modified the tree", k)
+		}
+	}
+
+	if mmr := MmrFromMerkleTree(&MerkleTree{}); !mmr.IsEmpty() || len(mmr.Peaks()) != 0 {
+		t.Error("empty tree should give an empty accumulator")
+	}
+}
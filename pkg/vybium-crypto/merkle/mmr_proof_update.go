@@ -0,0 +1,96 @@
+package merkle
+
+import (
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Appending a leaf to an MMR with oldLeafCount leafs merges the rightmost
+// m = trailing ones of oldLeafCount peaks, of heights m-1, ..., 1, 0, into
+// one. The appended leaf's membership proof lists exactly those old peaks,
+// lowest first: p_0, ..., p_{m-1}. Writing acc_0 = newLeaf and
+// acc_{j+1} = HashPair(p_j, acc_j), the new peak is acc_m.
+//
+// A leaf under the merged old peak p_h is then h levels below a node whose
+// right sibling is acc_h, and above that the path continues through
+// p_{h+1}, ..., p_{m-1}. Its proof is updated by appending those digests;
+// proofs of leafs under the other peaks are unaffected. This is the update
+// rule of twenty-first's MmrMembershipProof::update_from_append.
+
+// UpdateFromAppend updates the proof after newLeaf was appended to the MMR
+// with oldLeafCount leafs, given the membership proof Append returned for
+// it. It returns whether the proof changed; proofs whose peak was not merged
+// by the append stay valid as they are.
+//
+// ownLeafIndex must equal the proof's LeafIndex. The proof is left unchanged,
+// and false returned, if it does not, if the own leaf is not among the old
+// leafs, or if either proof does not have the shape its leaf index requires.
+func (proof *MmrMembershipProof) UpdateFromAppend(ownLeafIndex uint64, oldLeafCount uint64, newLeaf hash.Digest, appendProof MmrMembershipProof) bool {
+	merges, ok := appendMergeNodes(oldLeafCount, newLeaf, appendProof)
+	if !ok || ownLeafIndex != proof.LeafIndex {
+		return false
+	}
+	return proof.updateFromMerges(oldLeafCount, appendProof, merges)
+}
+
+// UpdateProofsFromAppend applies UpdateFromAppend to every proof, using each
+// proof's LeafIndex as its own leaf index, and returns the indices into
+// proofs of those that changed. Nil proofs are skipped.
+func UpdateProofsFromAppend(proofs []*MmrMembershipProof, oldLeafCount uint64, newLeaf hash.Digest, appendProof MmrMembershipProof) []int {
+	merges, ok := appendMergeNodes(oldLeafCount, newLeaf, appendProof)
+	if !ok {
+		return nil
+	}
+
+	var changed []int
+	for i, proof := range proofs {
+		if proof != nil && proof.updateFromMerges(oldLeafCount, appendProof, merges) {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// appendMergeNodes returns acc_0, ..., acc_{m-1} for the append described by
+// appendProof, or false if the proof is not the appended leaf's proof.
+func appendMergeNodes(oldLeafCount uint64, newLeaf hash.Digest, appendProof MmrMembershipProof) ([]hash.Digest, bool) {
+	numMerges := bits.TrailingZeros64(^oldLeafCount)
+	if appendProof.LeafIndex != oldLeafCount || len(appendProof.AuthPath) != numMerges {
+		return nil, false
+	}
+
+	merges := make([]hash.Digest, numMerges)
+	acc := newLeaf
+	for j, peak := range appendProof.AuthPath {
+		merges[j] = acc
+		acc = hash.HashPair(peak, acc)
+	}
+	return merges, true
+}
+
+// updateFromMerges implements UpdateFromAppend given appendMergeNodes.
+func (proof *MmrMembershipProof) updateFromMerges(oldLeafCount uint64, appendProof MmrMembershipProof, merges []hash.Digest) bool {
+	mtIndex, peakIndex, err := LeafIndexToMtIndexAndPeakIndex(proof.LeafIndex, oldLeafCount)
+	if err != nil {
+		return false
+	}
+	height := bits.Len64(mtIndex) - 1
+	if len(proof.AuthPath) != height {
+		return false
+	}
+
+	// The merged peaks are the rightmost len(merges) ones, and the peak of
+	// height h among them is the h-th from the right
+	numPeaks := bits.OnesCount64(oldLeafCount)
+	if int(peakIndex) < numPeaks-len(merges) {
+		return false
+	}
+
+	authPath := make([]hash.Digest, 0, len(proof.AuthPath)+len(merges))
+	authPath = append(authPath, proof.AuthPath...)
+	authPath = append(authPath, merges[height])
+	authPath = append(authPath, appendProof.AuthPath[height+1:]...)
+	proof.AuthPath = authPath
+	return true
+}
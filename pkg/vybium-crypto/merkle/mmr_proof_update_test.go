@@ -0,0 +1,153 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// TestUpdateProofsFromAppendExhaustive grows an MMR to 200 leafs, keeping a
+// proof for every leaf up to date, and checks all of them after each append.
+func TestUpdateProofsFromAppendExhaustive(t *testing.T) {
+	leafs := createTestLeafs(200)
+	mmr := NewMmrAccumulator([]hash.Digest{}, 0)
+	var proofs []*MmrMembershipProof
+
+	for n, leaf := range leafs {
+		oldLeafCount := mmr.NumLeafs()
+		appendProof := mmr.Append(leaf)
+
+		changed := UpdateProofsFromAppend(proofs, oldLeafCount, leaf, appendProof)
+		own := appendProof
+		proofs = append(proofs, &own)
+
+		// Exactly the proofs under the merged peaks gain a node
+		merged := 0
+		for _, height := range PeakHeights(oldLeafCount) {
+			if int(height) < len(appendProof.AuthPath) {
+				merged += 1 << height
+			}
+		}
+		if len(changed) != merged {
+			t.Fatalf("append %d: %d proofs changed, want %d", n, len(changed), merged)
+		}
+
+		for i, proof := range proofs {
+			if !mmr.VerifyMembership(leafs[i], *proof) {
+				t.Fatalf("after %d leafs: proof of leaf %d does not verify", n+1, i)
+			}
+			peakIndex, path, position, err := PeakProof(proof.LeafIndex, mmr.NumLeafs(), proof.AuthPath)
+			if err != nil || !VerifyInclusionProof(mmr.Peaks()[peakIndex], position, leafs[i], path) {
+				t.Fatalf("after %d leafs: peak proof of leaf %d does not verify", n+1, i)
+			}
+		}
+	}
+}
+
+func TestUpdateFromAppend(t *testing.T) {
+	leafs := createTestLeafs(4)
+	mmr := NewMmrAccumulator([]hash.Digest{}, 0)
+	proof0 := mmr.Append(leafs[0])
+	stale := proof0
+
+	appendProof := mmr.Append(leafs[1])
+	if !proof0.UpdateFromAppend(0, 1, leafs[1], appendProof) {
+		t.Error("proof of leaf 0 under a merged peak did not change")
+	}
+	proof2 := mmr.Append(leafs[2])
+	if proof0.UpdateFromAppend(0, 2, leafs[2], proof2) {
+		t.Error("proof of leaf 0 changed although its peak was not merged")
+	}
+
+	appendProof = mmr.Append(leafs[3])
+	for _, tt := range []struct {
+		index uint64
+		proof *MmrMembershipProof
+	}{{0, &proof0}, {2, &proof2}} {
+		if !tt.proof.UpdateFromAppend(tt.index, 3, leafs[3], appendProof) {
+			t.Errorf("proof of leaf %d under a merged peak did not change", tt.index)
+		}
+		if len(tt.proof.AuthPath) != 2 || !mmr.VerifyMembership(leafs[tt.index], *tt.proof) {
+			t.Errorf("updated proof of leaf %d does not verify", tt.index)
+		}
+	}
+
+	// The stale proof missed the second append, so its path is too short
+	if stale.UpdateFromAppend(0, 3, leafs[3], appendProof) {
+		t.Error("updated a proof whose path does not match the old leaf count")
+	}
+
+	rejected := map[string]func() bool{
+		"wrong own index": func() bool {
+			p := MmrMembershipProof{LeafIndex: 2}
+			return p.UpdateFromAppend(1, 3, leafs[3], appendProof)
+		},
+		"append proof for another leaf": func() bool {
+			p := MmrMembershipProof{LeafIndex: 2}
+			return p.UpdateFromAppend(2, 3, leafs[3], MmrMembershipProof{LeafIndex: 2, AuthPath: appendProof.AuthPath})
+		},
+		"append proof of wrong length": func() bool {
+			p := MmrMembershipProof{LeafIndex: 2}
+			return p.UpdateFromAppend(2, 3, leafs[3], MmrMembershipProof{LeafIndex: 3})
+		},
+		"own leaf not among the old leafs": func() bool {
+			p := MmrMembershipProof{LeafIndex: 3}
+			return p.UpdateFromAppend(3, 3, leafs[3], appendProof)
+		},
+	}
+	for name, update := range rejected {
+		if update() {
+			t.Errorf("%s: proof updated", name)
+		}
+	}
+}
+
+func TestUpdateFromAppendDoesNotAlias(t *testing.T) {
+	leafs := createTestLeafs(4)
+	mmr := NewMmrAccumulator([]hash.Digest{}, 0)
+	mmr.Append(leafs[0])
+	original := mmr.Append(leafs[1])
+	mmr.Append(leafs[2])
+
+	// A path with spare capacity must not be extended in place, or copies
+	// of the proof would see the new node
+	spare := make([]hash.Digest, len(original.AuthPath), 8)
+	copy(spare, original.AuthPath)
+	original.AuthPath = spare
+	updated := original
+
+	appendProof := mmr.Append(leafs[3])
+	if !updated.UpdateFromAppend(1, 3, leafs[3], appendProof) {
+		t.Fatal("proof of leaf 1 did not change")
+	}
+	if len(original.AuthPath) != 1 || !spare[:2][1].IsZero() {
+		t.Error("update wrote into the original path's backing array")
+	}
+}
+
+func BenchmarkUpdateProofsFromAppend(b *testing.B) {
+	leafs := createTestLeafs(1024)
+	mmr := NewMmrAccumulator([]hash.Digest{}, 0)
+	proofs := make([]*MmrMembershipProof, 0, len(leafs)-1)
+	for _, leaf := range leafs[:len(leafs)-1] {
+		oldLeafCount := mmr.NumLeafs()
+		appendProof := mmr.Append(leaf)
+		UpdateProofsFromAppend(proofs, oldLeafCount, leaf, appendProof)
+		proofs = append(proofs, &appendProof)
+	}
+
+	// Appending leaf 1023 merges every peak, updating every proof
+	_, appendProof := mmr.WithAppended(leafs[len(leafs)-1])
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		working := make([]*MmrMembershipProof, len(proofs))
+		for j, p := range proofs {
+			copied := *p
+			working[j] = &copied
+		}
+		b.StartTimer()
+		UpdateProofsFromAppend(working, mmr.NumLeafs(), leafs[len(leafs)-1], appendProof)
+	}
+}
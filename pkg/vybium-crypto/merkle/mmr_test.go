@@ -1,6 +1,8 @@
 package merkle
 
 import (
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -195,6 +197,57 @@ func TestMmrMembershipProof(t *testing.T) {
 	}
 }
 
+func TestMmrMembershipProofAcrossPeaks(t *testing.T) {
+	// 13 = 0b1101: peaks of 8, 4 and 1 leafs
+	leafs := createTestLeafs(13)
+	mmr := NewMmrAccumulatorFromLeafs(leafs)
+
+	offset := 0
+	for peakIndex, height := range PeakHeights(13) {
+		size := 1 << height
+		tree, err := New(leafs[offset : offset+size])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for local := 0; local < size; local++ {
+			path, err := tree.AuthenticationPath(uint64(local))
+			if err != nil {
+				t.Fatal(err)
+			}
+			leafIndex := uint64(offset + local)
+			proof := MmrMembershipProof{LeafIndex: leafIndex, AuthPath: path}
+			if !mmr.VerifyMembership(leafs[leafIndex], proof) {
+				t.Errorf("leaf %d in peak %d does not verify", leafIndex, peakIndex)
+			}
+
+			// The same proof claimed for a leaf of another peak must fail
+			for _, other := range []uint64{0, 8, 12} {
+				if other == leafIndex {
+					continue
+				}
+				if mmr.VerifyMembership(leafs[leafIndex], MmrMembershipProof{LeafIndex: other, AuthPath: path}) {
+					t.Errorf("proof for leaf %d verifies as leaf %d", leafIndex, other)
+				}
+			}
+		}
+		offset += size
+	}
+}
+
+func TestMmrMembershipRejectsUnrelatedPeak(t *testing.T) {
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(13))
+	peaks := mmr.Peaks()
+
+	// A peak with an empty path reconstructs itself. Claimed for leaf 12,
+	// whose own peak is a single leaf, only peaks[2] may match
+	for peakIndex, peak := range peaks {
+		proof := MmrMembershipProof{LeafIndex: 12, AuthPath: nil}
+		if got, want := mmr.VerifyMembership(peak, proof), peakIndex == 2; got != want {
+			t.Errorf("peak %d claimed as leaf 12: got %v, want %v", peakIndex, got, want)
+		}
+	}
+}
+
 func TestMmrConsistency(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -385,3 +438,74 @@ func BenchmarkMmrVerifyMembership(b *testing.B) {
 		_ = mmr.VerifyMembership(newLeaf, proof)
 	}
 }
+
+// TestMmrBagPeaksCompatGolden checks BagPeaksCompat against fixture MMRs
+// whose leaf i is HashVarlen([i]). The fixture was generated with this
+// implementation, not with twenty-first, so it guards against regressions
+// only; it does not establish equality with the Rust bag_peaks. It is to be
+// replaced by vectors produced with twenty-first.
+func TestMmrBagPeaksCompatGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden vectors assume the full Tip5 permutation")
+	}
+
+	data, err := os.ReadFile("testdata/mmr_bag_peaks_compat.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var fixture struct {
+		Cases []struct {
+			NumLeafs uint64   `json:"num_leafs"`
+			Peaks    []string `json:"peaks"`
+			BagPeaks string   `json:"bag_peaks"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	for _, c := range fixture.Cases {
+		leafs := make([]hash.Digest, c.NumLeafs)
+		for i := range leafs {
+			leafs[i] = hash.HashVarlen([]field.Element{field.New(uint64(i))})
+		}
+		mmr := NewMmrAccumulatorFromLeafs(leafs)
+
+		peaks := mmr.Peaks()
+		if len(peaks) != len(c.Peaks) {
+			t.Fatalf("%d leafs: got %d peaks, want %d", c.NumLeafs, len(peaks), len(c.Peaks))
+		}
+		for i, peak := range peaks {
+			if peak.Hex() != c.Peaks[i] {
+				t.Errorf("%d leafs: peak %d is %s, want %s", c.NumLeafs, i, peak.Hex(), c.Peaks[i])
+			}
+		}
+		if got := mmr.BagPeaksCompat().Hex(); got != c.BagPeaks {
+			t.Errorf("%d leafs: got %s, want %s", c.NumLeafs, got, c.BagPeaks)
+		}
+	}
+}
+
+func TestMmrBagPeaksCompatFold(t *testing.T) {
+	leafs := createTestLeafs(7)
+	mmr := NewMmrAccumulatorFromLeafs(leafs)
+	peaks := mmr.Peaks()
+
+	// Three peaks, folded from the right onto the hashed leaf count
+	acc := hash.Digest(hash.HashVarlen([]field.Element{field.New(7), field.Zero}))
+	acc = hash.HashPair(peaks[2], acc)
+	acc = hash.HashPair(peaks[1], acc)
+	acc = hash.HashPair(peaks[0], acc)
+	if mmr.BagPeaksCompat() != acc {
+		t.Error("BagPeaksCompat differs from the explicit fold")
+	}
+
+	// It is a different commitment from BagPeaks, and commits to the leaf count
+	if mmr.BagPeaksCompat() == mmr.BagPeaks() {
+		t.Error("BagPeaksCompat should differ from BagPeaks")
+	}
+	if NewMmrAccumulator(peaks, 8).BagPeaksCompat() == mmr.BagPeaksCompat() {
+		t.Error("BagPeaksCompat should commit to the leaf count")
+	}
+}
@@ -0,0 +1,91 @@
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// SaltedLeafDomain is the domain label under which salted leafs are hashed.
+// It is part of the leaf derivation; changing it changes every salted leaf.
+const SaltedLeafDomain = "vybium/merkle/salted-leaf"
+
+// Salted trees hide their values: each value is hashed with its own salt,
+// so equal values give unrelated leafs and a leaf reveals nothing about a
+// value as long as its salt stays secret. The leaf for value v and salt s is
+//
+//	hash.HashVarlenDomain(SaltedLeafDomain, [s[0..5], v...])
+//
+// The salt has a fixed length, so no length prefix is needed for v.
+
+// SaltedLeaf returns the salted leaf committing to value.
+func SaltedLeaf(value []field.Element, salt hash.Digest) hash.Digest {
+	input := make([]field.Element, 0, hash.DigestLen+len(value))
+	input = append(input, salt[:]...)
+	input = append(input, value...)
+	return hash.HashVarlenDomain(SaltedLeafDomain, input)
+}
+
+// NewSalted builds the Merkle tree whose leaf i is
+// SaltedLeaf(values[i], salts[i]). Any non-zero number of values is
+// accepted: the leafs are padded as by NewPadded, and the tree reports
+// len(values) leafs.
+// Returns an error if the slices differ in length, or if there are no values.
+func NewSalted(values [][]field.Element, salts []hash.Digest) (*MerkleTree, error) {
+	if len(values) != len(salts) {
+		return nil, fmt.Errorf("got %d values and %d salts", len(values), len(salts))
+	}
+
+	leafs := make([]hash.Digest, len(values))
+	for i := range leafs {
+		leafs[i] = SaltedLeaf(values[i], salts[i])
+	}
+	return NewPadded(leafs)
+}
+
+// SaltedInclusionProof opens a salted tree at one leaf. It carries the value
+// and its salt, from which a verifier recomputes the leaf, and the leaf's
+// authentication path.
+type SaltedInclusionProof struct {
+	Index MerkleTreeLeafIndex
+	Value []field.Element
+	Salt  hash.Digest
+	Path  []hash.Digest
+}
+
+// OpenSalted opens a tree built by NewSalted(values, salts) at the given
+// index.
+// Returns an error if the index is out of range, or if the tree's leaf does
+// not match the salted leaf derived from values and salts.
+func OpenSalted(tree *MerkleTree, values [][]field.Element, salts []hash.Digest, index MerkleTreeLeafIndex) (*SaltedInclusionProof, error) {
+	if index >= uint64(len(values)) || index >= uint64(len(salts)) {
+		return nil, fmt.Errorf("leaf index %d out of range for %d values and %d salts", index, len(values), len(salts))
+	}
+
+	leaf, err := tree.GetLeaf(index)
+	if err != nil {
+		return nil, err
+	}
+	if leaf != SaltedLeaf(values[index], salts[index]) {
+		return nil, fmt.Errorf("leaf %d does not commit to the given value and salt", index)
+	}
+
+	path, err := tree.AuthenticationPath(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SaltedInclusionProof{
+		Index: index,
+		Value: append([]field.Element(nil), values[index]...),
+		Salt:  salts[index],
+		Path:  path,
+	}, nil
+}
+
+// Verify reports whether the proof's value and salt are committed to at its
+// index in the salted tree with the given root.
+func (proof *SaltedInclusionProof) Verify(root hash.Digest, opts ...VerifyOption) bool {
+	return VerifyInclusionProof(root, proof.Index, SaltedLeaf(proof.Value, proof.Salt), proof.Path, opts...)
+}
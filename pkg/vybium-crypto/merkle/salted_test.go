@@ -0,0 +1,152 @@
+package merkle
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// testSaltedValues returns n values of varying length, with value 1 an
+// identical copy of value 0.
+func testSaltedValues(n int) [][]field.Element {
+	values := make([][]field.Element, n)
+	for i := range values {
+		values[i] = make([]field.Element, 1+i%3)
+		for j := range values[i] {
+			values[i][j] = field.New(uint64(i*10 + j))
+		}
+	}
+	if n > 1 {
+		values[1] = append([]field.Element(nil), values[0]...)
+	}
+	return values
+}
+
+func TestSaltedEqualValuesDifferentSalts(t *testing.T) {
+	values := testSaltedValues(4)
+	salts := testBlindings(4)
+	tree, err := NewSalted(values, salts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(values[0], values[1]) {
+		t.Fatalf("values 0 and 1 must be equal, got %v and %v", values[0], values[1])
+	}
+	if salts[0] == salts[1] {
+		t.Fatal("salts 0 and 1 must differ")
+	}
+
+	first, _ := tree.GetLeaf(0)
+	second, _ := tree.GetLeaf(1)
+	if first == second {
+		t.Fatal("equal values with different salts give equal leafs")
+	}
+	for _, index := range []MerkleTreeLeafIndex{0, 1} {
+		proof, err := OpenSalted(tree, values, salts, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proof.Verify(tree.Root()) {
+			t.Errorf("proof for leaf %d does not verify", index)
+		}
+	}
+}
+
+func TestSaltedLeafEncoding(t *testing.T) {
+	value := []field.Element{field.New(1), field.New(2)}
+	salt := testBlindings(1)[0]
+
+	input := append(append([]field.Element(nil), salt[:]...), value...)
+	if SaltedLeaf(value, salt) != hash.HashVarlenDomain(SaltedLeafDomain, input) {
+		t.Error("SaltedLeaf does not match the documented encoding")
+	}
+
+	// Every input is bound, including a trailing zero of the value
+	leaf := SaltedLeaf(value, salt)
+	if leaf == SaltedLeaf(value[:1], salt) ||
+		leaf == SaltedLeaf(append(value, field.Zero), salt) ||
+		leaf == SaltedLeaf(value, testBlindings(2)[1]) {
+		t.Error("changing an input did not change the leaf")
+	}
+}
+
+func TestSaltedProofRejectsTampering(t *testing.T) {
+	values := testSaltedValues(8)
+	salts := testBlindings(8)
+	tree, err := NewSalted(values, salts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := OpenSalted(tree, values, salts, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrongSalt := *proof
+	wrongSalt.Salt = salts[4]
+	wrongValue := *proof
+	wrongValue.Value = values[4]
+	wrongIndex := *proof
+	wrongIndex.Index = 4
+	for name, tampered := range map[string]*SaltedInclusionProof{
+		"salt":  &wrongSalt,
+		"value": &wrongValue,
+		"index": &wrongIndex,
+	} {
+		if tampered.Verify(tree.Root()) {
+			t.Errorf("proof with a wrong %s verifies", name)
+		}
+	}
+
+	// The proof owns its value
+	values[5][0] = field.New(999)
+	if !proof.Verify(tree.Root()) {
+		t.Error("changing the caller's value changed the proof")
+	}
+}
+
+func TestSaltedErrors(t *testing.T) {
+	values := testSaltedValues(4)
+	salts := testBlindings(4)
+
+	if _, err := NewSalted(values, salts[:3]); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+	if _, err := NewSalted(nil, nil); err == nil {
+		t.Error("expected error for no values")
+	}
+
+	tree, _ := NewSalted(values, salts)
+	if _, err := OpenSalted(tree, values, salts, 4); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+	if _, err := OpenSalted(tree, values, testBlindings(5)[1:], 2); err == nil {
+		t.Error("expected error for wrong salts")
+	}
+}
+
+func TestSaltedPadded(t *testing.T) {
+	for _, n := range []int{1, 3, 5, 13} {
+		values := testSaltedValues(n)
+		salts := testBlindings(n)
+		tree, err := NewSalted(values, salts)
+		if err != nil {
+			t.Fatalf("%d values: %v", n, err)
+		}
+		if tree.NumLeafs() != uint64(n) {
+			t.Errorf("%d values: tree reports %d leafs", n, tree.NumLeafs())
+		}
+		for index := 0; index < n; index++ {
+			proof, err := OpenSalted(tree, values, salts, MerkleTreeLeafIndex(index))
+			if err != nil {
+				t.Fatalf("%d values, leaf %d: %v", n, index, err)
+			}
+			if !proof.Verify(tree.Root()) {
+				t.Errorf("%d values: proof for leaf %d does not verify", n, index)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package merkle
+
+import (
+	"strconv"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// WithDiagnostics makes verification record a diagnostics.Event for every
+// failure it detects. The boolean result is unaffected. A nil collector is
+// ignored.
+func WithDiagnostics(d *diagnostics.Diagnostics) VerifyOption {
+	return func(c *verifyConfig) {
+		if d != nil {
+			c.diagnostics = d
+		}
+	}
+}
+
+// reportDigests records a failure with digest values. The digests are only
+// rendered if diagnostics were requested.
+func (c *verifyConfig) reportDigests(component diagnostics.Component, reason diagnostics.Reason, location uint64, expected, got hash.Digest) {
+	if c.diagnostics == nil {
+		return
+	}
+	c.diagnostics.Record(diagnostics.Event{
+		Component: component,
+		Reason:    reason,
+		Location:  location,
+		Expected:  expected.Hex(),
+		Got:       got.Hex(),
+	})
+}
+
+// reportCounts records a failure with numeric values.
+func (c *verifyConfig) reportCounts(component diagnostics.Component, reason diagnostics.Reason, location uint64, expected, got uint64) {
+	if c.diagnostics == nil {
+		return
+	}
+	c.diagnostics.Record(diagnostics.Event{
+		Component: component,
+		Reason:    reason,
+		Location:  location,
+		Expected:  strconv.FormatUint(expected, 10),
+		Got:       strconv.FormatUint(got, 10),
+	})
+}
+
+// report records a failure without values.
+func (c *verifyConfig) report(component diagnostics.Component, reason diagnostics.Reason, location uint64) {
+	if c.diagnostics == nil {
+		return
+	}
+	c.diagnostics.Record(diagnostics.Event{
+		Component: component,
+		Reason:    reason,
+		Location:  location,
+	})
+}
@@ -0,0 +1,256 @@
+package merkle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/diagnostics"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// expectEvents checks that d recorded exactly the given events.
+func expectEvents(t *testing.T, d *diagnostics.Diagnostics, want ...diagnostics.Event) {
+	t.Helper()
+	if got := d.Events(); len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+		t.Errorf("got events %v, want %v", got, want)
+	}
+}
+
+func event(component diagnostics.Component, reason diagnostics.Reason, location uint64, expected, got string) diagnostics.Event {
+	return diagnostics.Event{
+		Component: component,
+		Reason:    reason,
+		Location:  location,
+		Expected:  expected,
+		Got:       got,
+	}
+}
+
+func TestVerifyDiagnostics(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, _ := New(leafs)
+	root := tree.Root()
+	wrongRoot := leafs[0]
+
+	validProof := func() *MerkleTreeInclusionProof {
+		proof, _ := tree.NewInclusionProof([]MerkleTreeLeafIndex{2, 5})
+		return proof
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*MerkleTreeInclusionProof)
+		root   hash.Digest
+		want   []diagnostics.Event
+	}{
+		{"valid", func(*MerkleTreeInclusionProof) {}, root, nil},
+		{"root mismatch", func(*MerkleTreeInclusionProof) {}, wrongRoot, []diagnostics.Event{
+			event(diagnostics.ComponentMerkle, diagnostics.ReasonRootMismatch, RootIndex, wrongRoot.Hex(), root.Hex()),
+		}},
+		{"no leafs", func(p *MerkleTreeInclusionProof) { p.IndexedLeafs = nil }, root, []diagnostics.Event{
+			{Component: diagnostics.ComponentMerkle, Reason: diagnostics.ReasonNoLeafs},
+		}},
+		{"tree too high", func(p *MerkleTreeInclusionProof) { p.TreeHeight = 63 }, root, []diagnostics.Event{
+			event(diagnostics.ComponentMerkle, diagnostics.ReasonTreeTooHigh, 0, "62", "63"),
+		}},
+		{"leaf index out of range", func(p *MerkleTreeInclusionProof) { p.IndexedLeafs[1].Index = 9 }, root, []diagnostics.Event{
+			event(diagnostics.ComponentMerkle, diagnostics.ReasonLeafIndexOutOfRange, 9, "8", "9"),
+		}},
+		{"conflicting leaf", func(p *MerkleTreeInclusionProof) {
+			p.IndexedLeafs = append(p.IndexedLeafs, LeafIndexDigestPair{Index: 2, Digest: leafs[3]})
+		}, root, []diagnostics.Event{
+			event(diagnostics.ComponentMerkle, diagnostics.ReasonConflictingLeaf, 10, leafs[2].Hex(), leafs[3].Hex()),
+		}},
+		{"auth structure too short", func(p *MerkleTreeInclusionProof) {
+			p.AuthenticationStructure = p.AuthenticationStructure[1:]
+		}, root, []diagnostics.Event{
+			event(diagnostics.ComponentMerkle, diagnostics.ReasonAuthStructureLength, 0, "4", "3"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := validProof()
+			tt.modify(proof)
+
+			var d diagnostics.Diagnostics
+			if got := proof.Verify(tt.root, WithDiagnostics(&d)); got != (tt.want == nil) {
+				t.Fatalf("Verify returned %v", got)
+			}
+			if got := proof.Verify(tt.root); got != (tt.want == nil) {
+				t.Fatalf("Verify without diagnostics returned %v", got)
+			}
+			expectEvents(t, &d, tt.want...)
+
+			var batch diagnostics.Diagnostics
+			if got := VerifyBatchInclusion(tt.root, proof.TreeHeight, proof.IndexedLeafs, proof.AuthenticationStructure, WithDiagnostics(&batch)); got != (tt.want == nil) {
+				t.Fatalf("VerifyBatchInclusion returned %v", got)
+			}
+			expectEvents(t, &batch, tt.want...)
+		})
+	}
+}
+
+func TestVerifyInclusionProofDiagnostics(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, _ := New(leafs)
+	root := tree.Root()
+	path, _ := tree.AuthenticationPath(5)
+
+	var d diagnostics.Diagnostics
+	if !VerifyInclusionProof(root, 5, leafs[5], path, WithDiagnostics(&d)) {
+		t.Fatal("valid path rejected")
+	}
+	expectEvents(t, &d)
+
+	// Wrong leaf: the recomputed root is reported
+	got := leafs[4]
+	for i, sibling := range path {
+		if (5>>i)%2 == 0 {
+			got = hash.HashPair(got, sibling)
+		} else {
+			got = hash.HashPair(sibling, got)
+		}
+	}
+	d.Reset()
+	VerifyInclusionProof(root, 5, leafs[4], path, WithDiagnostics(&d))
+	expectEvents(t, &d, diagnostics.Event{
+		Component: diagnostics.ComponentMerkle, Reason: diagnostics.ReasonRootMismatch,
+		Location: RootIndex, Expected: root.Hex(), Got: got.Hex(),
+	})
+
+	// Short path: located at the leaf index
+	d.Reset()
+	VerifyInclusionProof(root, 5, leafs[5], path[:2], WithDiagnostics(&d))
+	expectEvents(t, &d, diagnostics.Event{
+		Component: diagnostics.ComponentMerkle, Reason: diagnostics.ReasonAuthPathLength,
+		Location: 5, Expected: "3", Got: "2",
+	})
+}
+
+func TestVerifyMembershipDiagnostics(t *testing.T) {
+	mmr := NewMmrAccumulatorFromLeafs(createTestLeafs(7))
+	newLeaf := createTestLeafs(8)[7]
+	proof := mmr.Append(newLeaf)
+	wrongLeaf := createTestLeafs(1)[0]
+
+	var d diagnostics.Diagnostics
+	if !mmr.VerifyMembership(newLeaf, proof, WithDiagnostics(&d)) {
+		t.Fatal("valid proof rejected")
+	}
+	expectEvents(t, &d)
+
+	// Wrong leaf: the single peak of the 8-leaf MMR mismatches
+	computed := wrongLeaf
+	for _, authNode := range proof.AuthPath {
+		computed = hash.HashPair(authNode, computed)
+	}
+	mmr.VerifyMembership(wrongLeaf, proof, WithDiagnostics(&d))
+	expectEvents(t, &d, diagnostics.Event{
+		Component: diagnostics.ComponentMmr, Reason: diagnostics.ReasonPeakMismatch,
+		Location: 0, Expected: mmr.Peaks()[0].Hex(), Got: computed.Hex(),
+	})
+
+	d.Reset()
+	outOfRange := MmrMembershipProof{LeafIndex: 8, AuthPath: proof.AuthPath}
+	mmr.VerifyMembership(wrongLeaf, outOfRange, WithDiagnostics(&d))
+	expectEvents(t, &d, diagnostics.Event{
+		Component: diagnostics.ComponentMmr, Reason: diagnostics.ReasonLeafIndexOutOfRange,
+		Location: 8, Expected: "8", Got: "8",
+	})
+
+	d.Reset()
+	shortPath := MmrMembershipProof{LeafIndex: proof.LeafIndex, AuthPath: proof.AuthPath[:2]}
+	mmr.VerifyMembership(newLeaf, shortPath, WithDiagnostics(&d))
+	expectEvents(t, &d, diagnostics.Event{
+		Component: diagnostics.ComponentMmr, Reason: diagnostics.ReasonAuthPathLength,
+		Location: 7, Expected: "3", Got: "2",
+	})
+}
+
+func TestDecodeMmrAccumulatorDiagnostics(t *testing.T) {
+	// 7 leafs: 3 peaks, so 3 + 15 elements
+	encoding := NewMmrAccumulatorFromLeafs(createTestLeafs(7)).Encode()
+
+	tests := []struct {
+		name   string
+		modify func([]field.Element) []field.Element
+		want   diagnostics.Event
+	}{
+		{"too short", func(s []field.Element) []field.Element { return s[:2] },
+			event(diagnostics.ComponentCodec, diagnostics.ReasonSequenceTooShort, 2, "3", "2")},
+		{"leaf count limb out of range", func(s []field.Element) []field.Element {
+			s[1] = field.New(1 << 32)
+			return s
+		}, event(diagnostics.ComponentCodec, diagnostics.ReasonElementOutOfRange, 1, "4294967295", "4294967296")},
+		{"wrong peak count", func(s []field.Element) []field.Element {
+			s[2] = field.New(2)
+			return s
+		}, event(diagnostics.ComponentCodec, diagnostics.ReasonInvalidLengthIndicator, 2, "3", "2")},
+		{"truncated peaks", func(s []field.Element) []field.Element { return s[:len(s)-1] },
+			event(diagnostics.ComponentCodec, diagnostics.ReasonSequenceTooShort, 17, "15", "14")},
+		{"trailing element", func(s []field.Element) []field.Element { return append(s, field.One) },
+			event(diagnostics.ComponentCodec, diagnostics.ReasonSequenceTooLong, 18, "15", "16")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sequence := tt.modify(append([]field.Element(nil), encoding...))
+
+			var d diagnostics.Diagnostics
+			if _, err := DecodeMmrAccumulatorWithDiagnostics(sequence, &d); err == nil {
+				t.Fatal("expected error")
+			}
+			expectEvents(t, &d, tt.want)
+		})
+	}
+
+	var d diagnostics.Diagnostics
+	if _, err := DecodeMmrAccumulatorWithDiagnostics(encoding, &d); err != nil {
+		t.Fatalf("valid encoding rejected: %v", err)
+	}
+	expectEvents(t, &d)
+}
+
+// Without diagnostics, rejecting a proof costs no more than accepting it.
+func TestVerifyFailureWithoutDiagnosticsAllocatesNothingExtra(t *testing.T) {
+	leafs := createTestLeafs(8)
+	tree, _ := New(leafs)
+	root := tree.Root()
+	path, _ := tree.AuthenticationPath(5)
+
+	accept := testing.AllocsPerRun(100, func() {
+		VerifyInclusionProof(root, 5, leafs[5], path)
+	})
+	reject := testing.AllocsPerRun(100, func() {
+		VerifyInclusionProof(root, 5, leafs[5], path[:2])
+	})
+	if reject != accept {
+		t.Errorf("rejecting allocated %v times, accepting %v times", reject, accept)
+	}
+}
+
+func benchmarkVerifyFailure(b *testing.B, opts ...VerifyOption) {
+	tree, _ := New(createTestLeafs(1 << 10))
+	proof, _ := tree.NewInclusionProof([]MerkleTreeLeafIndex{3, 300, 700})
+	wrongRoot := proof.IndexedLeafs[0].Digest
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if proof.Verify(wrongRoot, opts...) {
+			b.Fatal("proof accepted")
+		}
+	}
+}
+
+func BenchmarkVerifyFailureNoDiagnostics(b *testing.B) {
+	benchmarkVerifyFailure(b)
+}
+
+func BenchmarkVerifyFailureDiagnostics(b *testing.B) {
+	var d diagnostics.Diagnostics
+	d.OnEvent = func(diagnostics.Event) { d.Reset() }
+	benchmarkVerifyFailure(b, WithDiagnostics(&d))
+}
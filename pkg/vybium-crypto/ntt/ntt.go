@@ -40,7 +40,7 @@ func NTT(x []field.Element) {
 	if n&(n-1) != 0 {
 		panic(fmt.Sprintf("NTT requires power-of-2 length, got %d", n))
 	}
-	if n > (1 << 31) {
+	if uint64(n) > 1<<31 {
 		panic(fmt.Sprintf("NTT length too large: %d", n))
 	}
 
@@ -69,7 +69,7 @@ func INTT(x []field.Element) {
 	if n&(n-1) != 0 {
 		panic(fmt.Sprintf("INTT requires power-of-2 length, got %d", n))
 	}
-	if n > (1 << 31) {
+	if uint64(n) > 1<<31 {
 		panic(fmt.Sprintf("INTT length too large: %d", n))
 	}
 
@@ -163,16 +163,24 @@ func getTwiddleFactors(n uint32, inverse bool) [][]field.Element {
 	}
 
 	// Get primitive root of unity for this domain size
-	omega := field.PrimitiveRootOfUnity(uint64(n))
-	if omega.IsZero() {
-		panic(fmt.Sprintf("no primitive root of unity for n=%d", n))
+	omega, err := field.PrimitiveRootOfUnity(uint64(n))
+	if err != nil {
+		panic(fmt.Sprintf("no primitive root of unity for n=%d: %v", n, err))
 	}
 
 	if inverse {
 		omega = omega.Inverse()
 	}
 
-	// Compute twiddle factors
+	twiddles := computeTwiddleFactors(n, omega)
+	cache[n] = twiddles
+	return twiddles
+}
+
+// computeTwiddleFactors returns the twiddle factors for a transform of
+// length n with the n-th root of unity omega: row i holds the powers
+// wm^0, ..., wm^(2^i - 1) of wm = omega^(n / 2^(i+1)).
+func computeTwiddleFactors(n uint32, omega field.Element) [][]field.Element {
 	log2N := bits.Len32(n) - 1
 	twiddles := make([][]field.Element, log2N)
 
@@ -190,7 +198,6 @@ func getTwiddleFactors(n uint32, inverse bool) [][]field.Element {
 		twiddles[i] = twiddleRow
 	}
 
-	cache[n] = twiddles
 	return twiddles
 }
 
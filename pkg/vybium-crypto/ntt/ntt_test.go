@@ -37,6 +37,37 @@ func TestNTTINTTRoundTrip(t *testing.T) {
 	}
 }
 
+// TestNTTEvaluatesAtRootsOfUnity guards against the transform using
+// twiddles that are not roots of unity: NTT and INTT would still round-trip,
+// but the output would not be the evaluations of the input polynomial.
+func TestNTTEvaluatesAtRootsOfUnity(t *testing.T) {
+	for _, size := range []int{2, 4, 16, 64} {
+		coefficients := make([]field.Element, size)
+		for i := range coefficients {
+			coefficients[i] = field.New(uint64(i*i + 3))
+		}
+		values := make([]field.Element, size)
+		copy(values, coefficients)
+		NTT(values)
+
+		omega, err := field.PrimitiveRootOfUnity(uint64(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		point := field.One
+		for i := 0; i < size; i++ {
+			want := field.Zero
+			for j := size - 1; j >= 0; j-- {
+				want = want.Mul(point).Add(coefficients[j])
+			}
+			if !values[i].Equal(want) {
+				t.Fatalf("size %d: NTT[%d] = %v, want p(ω^%d) = %v", size, i, values[i], i, want)
+			}
+			point = point.Mul(omega)
+		}
+	}
+}
+
 func TestNTTEmptySlice(t *testing.T) {
 	// NTT/INTT on empty slice should not panic
 	var empty []field.Element
@@ -272,3 +303,26 @@ func BenchmarkNTTINTTRoundTrip1024(b *testing.B) {
 		INTT(values)
 	}
 }
+
+// TestFieldNTTMatchesNTT checks that field.NTT with the canonical root
+// computes the transform NTT does with its cached twiddle factors.
+func TestFieldNTTMatchesNTT(t *testing.T) {
+	for _, size := range []int{1, 2, 4, 64} {
+		values := make([]field.Element, size)
+		for i := range values {
+			values[i] = field.New(uint64(i*7 + 13))
+		}
+		want := append([]field.Element(nil), values...)
+		NTT(want)
+		root, err := field.PrimitiveRootOfUnity(uint64(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		field.NTT(values, root)
+		for i := range want {
+			if !values[i].Equal(want[i]) {
+				t.Fatalf("size %d: index %d differs from NTT", size, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package params holds the protocol constants shared by the Vybium crypto
+// packages. It imports nothing, so every package can depend on it.
+//
+// The packages that own each concept re-export these constants under their
+// established names (field.P, hash.DigestLen, hash.Rate, ...); both spellings
+// are interchangeable, and changing a value here changes every digest the
+// library computes.
+package params
+
+// FieldModulus is the Goldilocks prime 2^64 - 2^32 + 1, the modulus of the
+// base field.
+const FieldModulus uint64 = 0xFFFFFFFF00000001
+
+// ExtensionDegree is the degree of the extension field over the base field.
+const ExtensionDegree = 3
+
+// Tip5 sponge parameters, in base field elements.
+const (
+	// SpongeRate is the number of elements absorbed or squeezed per
+	// permutation.
+	SpongeRate = 10
+
+	// SpongeCapacity is the number of state elements never directly
+	// absorbed into or squeezed from.
+	SpongeCapacity = 6
+
+	// SpongeStateSize is the width of the Tip5 permutation.
+	SpongeStateSize = SpongeRate + SpongeCapacity
+
+	// DigestLen is the number of elements in a digest.
+	DigestLen = 5
+)
+
+// Tip5NumRounds is the number of rounds of the standard Tip5 permutation.
+// Builds with the tip5weak tag apply fewer; see hash.NumRounds.
+const Tip5NumRounds = 5
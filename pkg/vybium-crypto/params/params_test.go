@@ -0,0 +1,34 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFieldModulus(t *testing.T) {
+	// 2^64 - 2^32 + 1
+	want := new(big.Int).Lsh(big.NewInt(1), 64)
+	want.Sub(want, new(big.Int).Lsh(big.NewInt(1), 32))
+	want.Add(want, big.NewInt(1))
+	got := new(big.Int).SetUint64(FieldModulus)
+	if got.Cmp(want) != 0 {
+		t.Fatalf("FieldModulus = %v, want %v", got, want)
+	}
+	if !got.ProbablyPrime(20) {
+		t.Fatal("FieldModulus is not prime")
+	}
+}
+
+func TestSpongeParameters(t *testing.T) {
+	if SpongeStateSize != 16 {
+		t.Errorf("SpongeStateSize = %d, want 16", SpongeStateSize)
+	}
+	// A digest is read from the rate and, for domain-labeled sponges,
+	// written into the capacity.
+	if DigestLen > SpongeRate || DigestLen > SpongeCapacity {
+		t.Errorf("DigestLen %d does not fit the rate %d and capacity %d", DigestLen, SpongeRate, SpongeCapacity)
+	}
+	if 2*DigestLen != SpongeRate {
+		t.Errorf("a digest pair (%d elements) does not fill the rate (%d)", 2*DigestLen, SpongeRate)
+	}
+}
@@ -0,0 +1,49 @@
+package vybiumcrypto
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/params"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/sponge"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// TestParamsReExports checks that the constants re-exported by the packages
+// owning each concept are those of package params.
+func TestParamsReExports(t *testing.T) {
+	checks := []struct {
+		name      string
+		got, want uint64
+	}{
+		{"field.P", field.P, params.FieldModulus},
+		{"xfield.ExtensionDegree", xfield.ExtensionDegree, params.ExtensionDegree},
+		{"hash.DigestLen", hash.DigestLen, params.DigestLen},
+		{"hash.Rate", hash.Rate, params.SpongeRate},
+		{"hash.Capacity", hash.Capacity, params.SpongeCapacity},
+		{"hash.StateSize", hash.StateSize, params.SpongeStateSize},
+		{"sponge.Rate", sponge.Rate, params.SpongeRate},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if !hash.WeakHashingEnabled && hash.NumRounds != params.Tip5NumRounds {
+		t.Errorf("hash.NumRounds = %d, want %d", hash.NumRounds, params.Tip5NumRounds)
+	}
+}
+
+// TestParamsArrayTypesAgree checks that arrays sized by the params constants
+// and by their re-exports are the same types.
+func TestParamsArrayTypesAgree(t *testing.T) {
+	var digest hash.Digest = xfield.One.ToDigest()
+	if xfield.FromDigest(digest) == nil {
+		t.Error("digest of an extension field element does not convert back")
+	}
+	var state [params.SpongeRate]field.Element = sponge.NewTip5Sponge(hash.VariableLength).Squeeze()
+	if len(state) != hash.Rate {
+		t.Errorf("squeezed %d elements, want %d", len(state), hash.Rate)
+	}
+}
@@ -0,0 +1,96 @@
+package polynomial
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// ErrDegreeBoundExceeded is returned, wrapped, when evaluations are those of
+// a polynomial of larger degree than allowed.
+var ErrDegreeBoundExceeded = errors.New("degree bound exceeded")
+
+// EstimateDegree returns the degree of the polynomial of degree below the
+// domain's order taking the vector's values, or -1 if all values are zero.
+// Evaluations determine a polynomial only modulo the domain's vanishing
+// polynomial, so a polynomial of degree at least the order is reported as
+// its remainder, typically of degree order-1.
+//
+// On NTT domains this interpolates by an inverse NTT; on other domains it
+// falls back to Lagrange interpolation, whose cost is cubic in the order.
+// It is meant for checking constraint degrees during development.
+func (v *EvaluationVector) EstimateDegree() int {
+	p, err := v.Interpolate()
+	if err != nil {
+		points := make([][2]field.Element, len(v.values))
+		for i, value := range v.values {
+			points[i] = [2]field.Element{v.domain.Element(uint64(i)), value}
+		}
+		p = interpolate(points)
+	}
+	return p.Degree()
+}
+
+// CheckDegreeAtMost returns an error wrapping ErrDegreeBoundExceeded if
+// EstimateDegree exceeds bound.
+func (v *EvaluationVector) CheckDegreeAtMost(bound int) error {
+	if degree := v.EstimateDegree(); degree > bound {
+		return fmt.Errorf("%w: degree %d exceeds bound %d on %s", ErrDegreeBoundExceeded, degree, bound, v.domain)
+	}
+	return nil
+}
+
+// DivideByZerofier returns the pointwise quotient of the dividend by the
+// zerofier's evaluations, the evaluations of dividend/zerofier when the
+// zerofier divides it. dividendDegreeBound is the degree the dividend may
+// have for the quotient to fit its budget.
+//
+// Builds with the fielddebug tag check the bound with CheckDegreeAtMost
+// before dividing; other builds skip the check, which costs an
+// interpolation. Returns an error wrapping ErrDomainMismatch if the vectors
+// are over different domains, or an error if the zerofier vanishes on the
+// domain.
+func DivideByZerofier(dividend, zerofier *EvaluationVector, dividendDegreeBound int) (*EvaluationVector, error) {
+	quotients, err := DivideAllByZerofier([]*EvaluationVector{dividend}, zerofier, []int{dividendDegreeBound})
+	if err != nil {
+		return nil, err
+	}
+	return quotients[0], nil
+}
+
+// DivideAllByZerofier is DivideByZerofier for several dividends sharing a
+// zerofier, whose values are inverted once. dividendDegreeBounds[i] is the
+// bound of dividends[i]; errors name the offending dividend by index.
+func DivideAllByZerofier(dividends []*EvaluationVector, zerofier *EvaluationVector, dividendDegreeBounds []int) ([]*EvaluationVector, error) {
+	if len(dividendDegreeBounds) != len(dividends) {
+		return nil, fmt.Errorf("divide by zerofier: %d degree bounds for %d dividends", len(dividendDegreeBounds), len(dividends))
+	}
+	for i, dividend := range dividends {
+		if err := dividend.checkSameDomain("divide by zerofier", zerofier); err != nil {
+			return nil, fmt.Errorf("dividend %d: %w", i, err)
+		}
+		if checkDividendDegrees {
+			if err := dividend.CheckDegreeAtMost(dividendDegreeBounds[i]); err != nil {
+				return nil, fmt.Errorf("divide by zerofier: dividend %d: %w", i, err)
+			}
+		}
+	}
+
+	for i, value := range zerofier.values {
+		if value.IsZero() {
+			return nil, fmt.Errorf("divide by zerofier: zerofier vanishes at domain point %d", i)
+		}
+	}
+	inverses := field.BatchInverse(zerofier.values)
+
+	quotients := make([]*EvaluationVector, len(dividends))
+	for i, dividend := range dividends {
+		values := make([]field.Element, len(inverses))
+		for j, inverse := range inverses {
+			values[j] = dividend.values[j].Mul(inverse)
+		}
+		quotients[i] = &EvaluationVector{domain: zerofier.domain, values: values}
+	}
+	return quotients, nil
+}
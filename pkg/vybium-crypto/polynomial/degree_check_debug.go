@@ -0,0 +1,7 @@
+//go:build fielddebug
+
+package polynomial
+
+// checkDividendDegrees makes fielddebug builds of DivideByZerofier and
+// DivideAllByZerofier check each dividend's degree bound.
+const checkDividendDegrees = true
@@ -0,0 +1,7 @@
+//go:build !fielddebug
+
+package polynomial
+
+// checkDividendDegrees is false outside fielddebug builds, so the degree
+// check compiles away.
+const checkDividendDegrees = false
@@ -0,0 +1,171 @@
+package polynomial
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// polynomialOfDegree returns a random polynomial of exactly the given
+// degree.
+func polynomialOfDegree(rng *rand.Rand, degree int) *Polynomial {
+	coefficients := randomPolynomial(rng, degree+1).Coefficients()
+	coefficients = append(coefficients, make([]field.Element, degree+1-len(coefficients))...)
+	if degree >= 0 && coefficients[degree].IsZero() {
+		coefficients[degree] = field.One
+	}
+	return New(coefficients)
+}
+
+func TestEstimateDegree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1200))
+	nonNTT, err := NewDomainDescriptorWithGenerator(mustPrimitiveRoot(t, 16).ModPow(3), cosetOffset, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, domain := range []DomainDescriptor{
+		mustDomain(t, 1, field.One),
+		mustDomain(t, 16, field.One),
+		mustDomain(t, 16, cosetOffset),
+		nonNTT,
+	} {
+		// Degrees up to order-1, the largest the evaluations determine.
+		for _, degree := range []int{-1, 0, 1, 7, int(domain.Order()) - 1} {
+			if degree >= int(domain.Order()) {
+				continue
+			}
+			p := polynomialOfDegree(rng, degree)
+			values := make([]field.Element, domain.Order())
+			for i := range values {
+				values[i] = p.Evaluate(domain.Element(uint64(i)))
+			}
+			v, err := NewEvaluationVector(domain, values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.EstimateDegree(); got != degree {
+				t.Errorf("%s: estimated degree %d, want %d", domain, got, degree)
+			}
+		}
+	}
+}
+
+func TestEstimateDegreeAliasesAtDomainOrder(t *testing.T) {
+	// On the coset x^16 = 7^16, so x^16 + x is indistinguishable from the
+	// polynomial 7^16 + x of degree 1.
+	domain := mustDomain(t, 16, cosetOffset)
+	v := mustEvaluate(t, XToThe(16).Add(X()), domain)
+	if got := v.EstimateDegree(); got != 1 {
+		t.Errorf("estimated degree %d, want 1", got)
+	}
+}
+
+func TestCheckDegreeAtMost(t *testing.T) {
+	rng := rand.New(rand.NewSource(1200))
+	domain := mustDomain(t, 32, cosetOffset)
+	v := mustEvaluate(t, polynomialOfDegree(rng, 20), domain)
+
+	for _, bound := range []int{20, 31} {
+		if err := v.CheckDegreeAtMost(bound); err != nil {
+			t.Errorf("bound %d: %v", bound, err)
+		}
+	}
+	err := v.CheckDegreeAtMost(19)
+	if !errors.Is(err, ErrDegreeBoundExceeded) {
+		t.Fatalf("bound 19: got %v, want ErrDegreeBoundExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "degree 20 exceeds bound 19") {
+		t.Errorf("error %q does not name the degree and bound", err)
+	}
+}
+
+// traceZerofier returns x^traceOrder - 1, the zerofier of the subgroup of
+// order traceOrder.
+func traceZerofier(traceOrder int) *Polynomial {
+	return XToThe(traceOrder).Sub(One())
+}
+
+func TestDivideByZerofier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1200))
+	domain := mustDomain(t, 32, cosetOffset)
+	zerofier := traceZerofier(8)
+	quotient := polynomialOfDegree(rng, 10)
+	dividend := quotient.Mul(zerofier)
+
+	got, err := DivideByZerofier(mustEvaluate(t, dividend, domain), mustEvaluate(t, zerofier, domain), dividend.Degree())
+	if err != nil {
+		t.Fatal(err)
+	}
+	interpolated, err := got.Interpolate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !interpolated.Equal(quotient) {
+		t.Errorf("quotient %v, want %v", interpolated, quotient)
+	}
+}
+
+func TestDivideAllByZerofierChecksDegreesInDebugBuilds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1200))
+	domain := mustDomain(t, 32, cosetOffset)
+	zerofier := mustEvaluate(t, traceZerofier(8), domain)
+	dividends := []*EvaluationVector{
+		mustEvaluate(t, polynomialOfDegree(rng, 3).Mul(traceZerofier(8)), domain),
+		mustEvaluate(t, polynomialOfDegree(rng, 9).Mul(traceZerofier(8)), domain),
+	}
+
+	_, err := DivideAllByZerofier(dividends, zerofier, []int{11, 15})
+	if !checkDividendDegrees {
+		if err != nil {
+			t.Fatalf("release build checked degrees: %v", err)
+		}
+		return
+	}
+	if !errors.Is(err, ErrDegreeBoundExceeded) {
+		t.Fatalf("got %v, want ErrDegreeBoundExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "dividend 1: ") || !strings.Contains(err.Error(), "degree 17 exceeds bound 15") {
+		t.Errorf("error %q does not name the dividend, degree and bound", err)
+	}
+}
+
+func TestDivideAllByZerofierErrors(t *testing.T) {
+	domain := mustDomain(t, 16, field.One)
+	other := mustDomain(t, 16, cosetOffset)
+	dividend := mustEvaluate(t, X(), other)
+
+	if _, err := DivideByZerofier(dividend, mustEvaluate(t, One(), domain), 1); !errors.Is(err, ErrDomainMismatch) {
+		t.Errorf("mismatched domains: got %v, want ErrDomainMismatch", err)
+	}
+	// x^4 - 1 vanishes on the subgroup itself.
+	if _, err := DivideByZerofier(mustEvaluate(t, X(), domain), mustEvaluate(t, traceZerofier(4), domain), 1); err == nil {
+		t.Error("vanishing zerofier accepted")
+	}
+	if _, err := DivideAllByZerofier([]*EvaluationVector{dividend}, mustEvaluate(t, One(), other), nil); err == nil {
+		t.Error("missing degree bound accepted")
+	}
+}
+
+func BenchmarkDivideByZerofier(b *testing.B) {
+	rng := rand.New(rand.NewSource(1200))
+	domain := mustDomain(b, 1<<12, cosetOffset)
+	zerofier := traceZerofier(1 << 10)
+	dividend, err := polynomialOfDegree(rng, 1<<10).Mul(zerofier).EvaluateOn(domain)
+	if err != nil {
+		b.Fatal(err)
+	}
+	zerofierValues, err := zerofier.EvaluateOn(domain)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DivideByZerofier(dividend, zerofierValues, 1<<11); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package polynomial
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// ErrDomainMismatch is returned, wrapped, by operations given evaluations
+// over incompatible domains.
+var ErrDomainMismatch = errors.New("evaluation domain mismatch")
+
+// maxDomainOrder is the largest power of two dividing P-1, and hence the
+// largest order of a domain.
+const maxDomainOrder = uint64(1) << 32
+
+// DomainDescriptor describes an evaluation domain: the coset
+//
+//	{offset * generator^i : 0 <= i < order}
+//
+// of the multiplicative subgroup of order order, listed in that order. The
+// order is a power of two and the generator has exactly that order, so the
+// points are distinct. An offset of one makes the domain the subgroup
+// itself.
+//
+// Evaluation vectors carry their domain, so that combining evaluations over
+// different domains, such as a trace domain and its low-degree extension,
+// fails at once instead of producing a proof that fails to verify.
+type DomainDescriptor struct {
+	generator field.Element
+	offset    field.Element
+	order     uint64
+}
+
+// NewDomainDescriptor returns the domain of the given order and offset
+// generated by field.PrimitiveRootOfUnity(order), the generator NTT uses.
+// Returns an error if the order is not a power of two of at most 2^32, or
+// the offset is zero.
+func NewDomainDescriptor(order uint64, offset field.Element) (DomainDescriptor, error) {
+	if order == 0 || order&(order-1) != 0 || order > maxDomainOrder {
+		return DomainDescriptor{}, fmt.Errorf("domain order %d is not a power of two of at most 2^32", order)
+	}
+	generator, err := field.PrimitiveRootOfUnity(order)
+	if err != nil {
+		return DomainDescriptor{}, err
+	}
+	return NewDomainDescriptorWithGenerator(generator, offset, order)
+}
+
+// NewDomainDescriptorWithGenerator returns the domain with the given
+// generator, offset and order. Returns an error if the order is not a power
+// of two of at most 2^32, the generator's multiplicative order is not
+// exactly order, or the offset is zero.
+func NewDomainDescriptorWithGenerator(generator, offset field.Element, order uint64) (DomainDescriptor, error) {
+	if order == 0 || order&(order-1) != 0 || order > maxDomainOrder {
+		return DomainDescriptor{}, fmt.Errorf("domain order %d is not a power of two of at most 2^32", order)
+	}
+	if offset.IsZero() {
+		return DomainDescriptor{}, fmt.Errorf("domain offset must be non-zero")
+	}
+	// An element of order dividing a power of two has exactly that order
+	// unless its square already reaches one a step earlier.
+	if !generator.ModPow(order).IsOne() || (order > 1 && generator.ModPow(order/2).IsOne()) {
+		return DomainDescriptor{}, fmt.Errorf("generator %s does not have order %d", generator, order)
+	}
+	return DomainDescriptor{generator: generator, offset: offset, order: order}, nil
+}
+
+// Generator returns the domain's generator.
+func (d DomainDescriptor) Generator() field.Element {
+	return d.generator
+}
+
+// Offset returns the domain's offset.
+func (d DomainDescriptor) Offset() field.Element {
+	return d.offset
+}
+
+// Order returns the number of points in the domain.
+func (d DomainDescriptor) Order() uint64 {
+	return d.order
+}
+
+// Element returns the i-th point of the domain, offset * generator^i.
+func (d DomainDescriptor) Element(i uint64) field.Element {
+	return d.offset.Mul(d.generator.ModPow(i % d.order))
+}
+
+// String returns a description of the domain for error messages.
+func (d DomainDescriptor) String() string {
+	return fmt.Sprintf("domain(order %d, offset %s, generator %s)", d.order, d.offset, d.generator)
+}
+
+// SameDomain reports whether d and other list the same points in the same
+// order: their generators, offsets and orders agree. Only evaluations over
+// the same domain can be combined pointwise.
+func (d DomainDescriptor) SameDomain(other DomainDescriptor) bool {
+	return d.order == other.order && d.generator.Equal(other.generator) && d.offset.Equal(other.offset)
+}
+
+// Contains reports whether x is a point of the domain.
+func (d DomainDescriptor) Contains(x field.Element) bool {
+	if x.IsZero() {
+		return false
+	}
+	_, ok := discreteLog(x.Mul(d.offset.Inverse()), d.generator, d.order)
+	return ok
+}
+
+// DomainEmbedding maps the points of a domain to their indices in a
+// domain containing it, as returned by IsSubdomainOf: point j of the
+// subdomain is point Index(j) of the containing domain.
+type DomainEmbedding struct {
+	// Start is the index of the subdomain's first point.
+	Start uint64
+	// Stride is the index step between consecutive subdomain points.
+	Stride uint64
+
+	order uint64
+}
+
+// Index returns the index in the containing domain of point j of the
+// subdomain.
+func (e DomainEmbedding) Index(j uint64) uint64 {
+	return (e.Start + j%e.order*e.Stride) % e.order
+}
+
+// IsSubdomainOf reports whether every point of d is a point of other and,
+// if so, returns the mapping from d's indices to other's.
+//
+// With d = o'<h> of order m and other = o<g> of order n, d is contained in
+// other exactly when m <= n, h is a power g^t of g, and o'/o is a power g^s
+// of g; point j of d is then o * g^(s + j*t), point s + j*t mod n of other.
+// The exponents are found by discrete logarithms in the group of order n,
+// which take O(log^2 n) multiplications.
+func (d DomainDescriptor) IsSubdomainOf(other DomainDescriptor) (DomainEmbedding, bool) {
+	if d.order == 0 || other.order == 0 || d.order > other.order {
+		return DomainEmbedding{}, false
+	}
+	start, ok := discreteLog(d.offset.Mul(other.offset.Inverse()), other.generator, other.order)
+	if !ok {
+		return DomainEmbedding{}, false
+	}
+	stride, ok := discreteLog(d.generator, other.generator, other.order)
+	if !ok {
+		return DomainEmbedding{}, false
+	}
+	return DomainEmbedding{Start: start, Stride: stride, order: other.order}, true
+}
+
+// discreteLog returns x with generator^x = y, where generator has order n,
+// a power of two. Returns false if y is not a power of generator.
+//
+// The group of order n is cyclic and the unique subgroup of that order in
+// the multiplicative group, so y is a power of the generator exactly when
+// y^n = 1. The bits of x are then found lowest first (Pohlig-Hellman): with
+// the low i bits matched, y/generator^x lies in the subgroup of order
+// n/2^i, and raising it to n/2^(i+1) gives 1 or -1 according to bit i.
+func discreteLog(y, generator field.Element, n uint64) (uint64, bool) {
+	if !y.ModPow(n).IsOne() {
+		return 0, false
+	}
+	inverse := generator.Inverse()
+	x := uint64(0)
+	for i := 0; i < bits.TrailingZeros64(n); i++ {
+		residue := y.Mul(inverse.ModPow(x))
+		if !residue.ModPow(n >> (i + 1)).IsOne() {
+			x |= 1 << i
+		}
+	}
+	return x, true
+}
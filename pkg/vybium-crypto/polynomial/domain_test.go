@@ -0,0 +1,222 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// mustDomain returns the NTT-generated domain of the given order and offset.
+func mustDomain(t testing.TB, order uint64, offset field.Element) DomainDescriptor {
+	t.Helper()
+	d, err := NewDomainDescriptor(order, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// mustPrimitiveRoot returns field.PrimitiveRootOfUnity(order), failing the
+// test on an error.
+func mustPrimitiveRoot(t testing.TB, order uint64) field.Element {
+	t.Helper()
+	root, err := field.PrimitiveRootOfUnity(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// cosetOffset is the multiplicative generator of the field, which lies in
+// no subgroup of power-of-two order; offsetting by it gives a proper coset.
+var cosetOffset = field.New(7)
+
+func TestNewDomainDescriptorRejectsInvalid(t *testing.T) {
+	for _, order := range []uint64{0, 3, 12, 1 << 33} {
+		if _, err := NewDomainDescriptor(order, field.One); err == nil {
+			t.Errorf("order %d: expected an error", order)
+		}
+	}
+	if _, err := NewDomainDescriptor(8, field.Zero); err == nil {
+		t.Error("zero offset: expected an error")
+	}
+
+	g8 := mustPrimitiveRoot(t, 8)
+	for name, generator := range map[string]field.Element{
+		"order too small":     g8.Mul(g8),
+		"order not dividing":  field.New(3),
+		"one for order eight": field.One,
+	} {
+		if _, err := NewDomainDescriptorWithGenerator(generator, field.One, 8); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := NewDomainDescriptorWithGenerator(g8.ModPow(3), field.One, 8); err != nil {
+		t.Errorf("odd power of a primitive root: %v", err)
+	}
+	if _, err := NewDomainDescriptorWithGenerator(field.One, cosetOffset, 1); err != nil {
+		t.Errorf("single point: %v", err)
+	}
+}
+
+func TestDomainElementsAndContains(t *testing.T) {
+	d := mustDomain(t, 16, cosetOffset)
+	seen := make(map[field.Element]bool)
+	for i := uint64(0); i < d.Order(); i++ {
+		x := d.Element(i)
+		if seen[x] {
+			t.Fatalf("point %d repeats", i)
+		}
+		seen[x] = true
+		if !d.Contains(x) {
+			t.Fatalf("domain does not contain its point %d", i)
+		}
+	}
+	if !d.Element(16).Equal(d.Element(0)) {
+		t.Error("Element does not wrap around")
+	}
+	if d.Contains(field.One) || d.Contains(field.Zero) || d.Contains(cosetOffset.Mul(mustPrimitiveRoot(t, 32))) {
+		t.Error("domain contains a point outside it")
+	}
+}
+
+func TestSameDomain(t *testing.T) {
+	d := mustDomain(t, 8, cosetOffset)
+	if !d.SameDomain(mustDomain(t, 8, field.New(7))) {
+		t.Error("identical domains differ")
+	}
+
+	// The same points listed in another order are a different domain.
+	reordered, err := NewDomainDescriptorWithGenerator(d.Generator().ModPow(3), cosetOffset, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shifted := mustDomain(t, 8, d.Element(1))
+	for name, other := range map[string]DomainDescriptor{
+		"reordered":   reordered,
+		"shifted":     shifted,
+		"subgroup":    mustDomain(t, 8, field.One),
+		"other order": mustDomain(t, 16, cosetOffset),
+	} {
+		if d.SameDomain(other) {
+			t.Errorf("%s: reported the same domain", name)
+		}
+	}
+}
+
+// testDomains is a family of subgroups and cosets of related orders,
+// offsets and generators, on which containment is checked exhaustively.
+func testDomains(t *testing.T) map[string]DomainDescriptor {
+	domains := make(map[string]DomainDescriptor)
+	g64 := mustPrimitiveRoot(t, 64)
+	g128 := mustPrimitiveRoot(t, 128)
+	offsets := map[string]field.Element{
+		"1":            field.One,
+		"7":            cosetOffset,
+		"49":           cosetOffset.Mul(cosetOffset),
+		"g64^5":        g64.ModPow(5),
+		"7*g64^3":      cosetOffset.Mul(g64.ModPow(3)),
+		"7*g128":       cosetOffset.Mul(g128),
+		"g128":         g128,
+		"7*g64^3*g128": cosetOffset.Mul(g64.ModPow(3)).Mul(g128),
+	}
+	for _, order := range []uint64{1, 2, 8, 64} {
+		for name, offset := range offsets {
+			domains[fmt.Sprintf("%s*H%d", name, order)] = mustDomain(t, order, offset)
+		}
+	}
+	// Generators other than the NTT's.
+	g8 := mustPrimitiveRoot(t, 8)
+	for _, power := range []uint64{3, 5} {
+		for name, offset := range map[string]field.Element{"1": field.One, "7": cosetOffset} {
+			d, err := NewDomainDescriptorWithGenerator(g8.ModPow(power), offset, 8)
+			if err != nil {
+				t.Fatal(err)
+			}
+			domains[fmt.Sprintf("%s*<g8^%d>", name, power)] = d
+		}
+	}
+	return domains
+}
+
+func TestIsSubdomainOfExhaustive(t *testing.T) {
+	domains := testDomains(t)
+	positives, negatives := 0, 0
+	for subName, sub := range domains {
+		for superName, super := range domains {
+			indices := make(map[field.Element]uint64)
+			for i := uint64(0); i < super.Order(); i++ {
+				indices[super.Element(i)] = i
+			}
+			contained := true
+			for j := uint64(0); j < sub.Order(); j++ {
+				if _, ok := indices[sub.Element(j)]; !ok {
+					contained = false
+					break
+				}
+			}
+
+			embedding, ok := sub.IsSubdomainOf(super)
+			if ok != contained {
+				t.Fatalf("%s in %s: IsSubdomainOf = %v, brute force %v", subName, superName, ok, contained)
+			}
+			if !ok {
+				negatives++
+				continue
+			}
+			positives++
+			for j := uint64(0); j < sub.Order(); j++ {
+				if got, want := embedding.Index(j), indices[sub.Element(j)]; got != want {
+					t.Fatalf("%s in %s: point %d maps to %d, want %d", subName, superName, j, got, want)
+				}
+			}
+		}
+	}
+	if positives == 0 || negatives == 0 {
+		t.Fatalf("degenerate family: %d positive and %d negative cases", positives, negatives)
+	}
+}
+
+func TestTraceDomainIsNotInLDEDomain(t *testing.T) {
+	const traceOrder, expansion = 1 << 10, 8
+	trace := mustDomain(t, traceOrder, field.One)
+	lde := mustDomain(t, traceOrder*expansion, cosetOffset)
+
+	if _, ok := trace.IsSubdomainOf(lde); ok {
+		t.Error("the trace subgroup lies in the LDE coset")
+	}
+	if _, ok := lde.IsSubdomainOf(trace); ok {
+		t.Error("the LDE domain lies in the smaller trace domain")
+	}
+
+	// The trace-sized coset of the LDE domain is the LDE domain's every
+	// eighth point.
+	traceCoset := mustDomain(t, traceOrder, cosetOffset)
+	embedding, ok := traceCoset.IsSubdomainOf(lde)
+	if !ok || embedding.Start != 0 || embedding.Stride != expansion {
+		t.Fatalf("trace coset in LDE domain: %+v, %v", embedding, ok)
+	}
+}
+
+func TestIsSubdomainOfLargestDomain(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	full := mustDomain(t, 1<<32, cosetOffset)
+	for k := 0; k < 10; k++ {
+		start := rng.Uint64() % full.Order()
+		order := uint64(1) << (rng.Intn(32) + 1)
+		sub := mustDomain(t, order, full.Element(start))
+		embedding, ok := sub.IsSubdomainOf(full)
+		if !ok {
+			t.Fatalf("order %d subdomain at %d not contained", order, start)
+		}
+		if embedding.Start != start || embedding.Stride != full.Order()/order {
+			t.Fatalf("order %d subdomain at %d: embedding %+v", order, start, embedding)
+		}
+		j := rng.Uint64() % order
+		if !sub.Element(j).Equal(full.Element(embedding.Index(j))) {
+			t.Fatalf("point %d maps to the wrong index", j)
+		}
+	}
+}
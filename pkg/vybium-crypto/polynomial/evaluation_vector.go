@@ -0,0 +1,165 @@
+package polynomial
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/ntt"
+)
+
+// maxNTTOrder is the largest domain NTT supports.
+const maxNTTOrder = uint64(1) << 31
+
+// EvaluationVector holds the evaluations of a function on every point of a
+// domain, values[i] being the value at domain.Element(i). Operations
+// combining vectors check, in O(1), that their domains match.
+type EvaluationVector struct {
+	domain DomainDescriptor
+	values []field.Element
+}
+
+// NewEvaluationVector returns the evaluations over the domain. The values
+// are copied. Returns an error unless there is exactly one value per point.
+func NewEvaluationVector(domain DomainDescriptor, values []field.Element) (*EvaluationVector, error) {
+	if domain.order == 0 {
+		return nil, fmt.Errorf("evaluation vector needs a domain")
+	}
+	if uint64(len(values)) != domain.order {
+		return nil, fmt.Errorf("%d values for %s", len(values), domain)
+	}
+	return &EvaluationVector{domain: domain, values: append([]field.Element(nil), values...)}, nil
+}
+
+// Domain returns the domain the values are over.
+func (v *EvaluationVector) Domain() DomainDescriptor {
+	return v.domain
+}
+
+// Values returns a copy of the values.
+func (v *EvaluationVector) Values() []field.Element {
+	return append([]field.Element(nil), v.values...)
+}
+
+// Len returns the number of values, the order of the domain.
+func (v *EvaluationVector) Len() int {
+	return len(v.values)
+}
+
+// At returns the value at domain point i.
+func (v *EvaluationVector) At(i int) field.Element {
+	return v.values[i]
+}
+
+// checkSameDomain returns an error wrapping ErrDomainMismatch unless v and
+// other are over the same domain.
+func (v *EvaluationVector) checkSameDomain(operation string, other *EvaluationVector) error {
+	if !v.domain.SameDomain(other.domain) {
+		return fmt.Errorf("%s: %w: %s and %s", operation, ErrDomainMismatch, v.domain, other.domain)
+	}
+	return nil
+}
+
+// pointwise combines v and other value by value.
+func (v *EvaluationVector) pointwise(operation string, other *EvaluationVector, op func(a, b field.Element) field.Element) (*EvaluationVector, error) {
+	if err := v.checkSameDomain(operation, other); err != nil {
+		return nil, err
+	}
+	values := make([]field.Element, len(v.values))
+	for i := range values {
+		values[i] = op(v.values[i], other.values[i])
+	}
+	return &EvaluationVector{domain: v.domain, values: values}, nil
+}
+
+// Add returns the pointwise sum of v and other. Returns an error wrapping
+// ErrDomainMismatch if they are over different domains.
+func (v *EvaluationVector) Add(other *EvaluationVector) (*EvaluationVector, error) {
+	return v.pointwise("add", other, field.Element.Add)
+}
+
+// Sub returns the pointwise difference of v and other. Returns an error
+// wrapping ErrDomainMismatch if they are over different domains.
+func (v *EvaluationVector) Sub(other *EvaluationVector) (*EvaluationVector, error) {
+	return v.pointwise("sub", other, field.Element.Sub)
+}
+
+// Mul returns the pointwise product of v and other. Returns an error
+// wrapping ErrDomainMismatch if they are over different domains.
+func (v *EvaluationVector) Mul(other *EvaluationVector) (*EvaluationVector, error) {
+	return v.pointwise("mul", other, field.Element.Mul)
+}
+
+// Restrict returns the values on the points of sub, which must be contained
+// in v's domain. Returns an error wrapping ErrDomainMismatch if it is not.
+func (v *EvaluationVector) Restrict(sub DomainDescriptor) (*EvaluationVector, error) {
+	embedding, ok := sub.IsSubdomainOf(v.domain)
+	if !ok {
+		return nil, fmt.Errorf("restrict: %w: %s is not contained in %s", ErrDomainMismatch, sub, v.domain)
+	}
+	values := make([]field.Element, sub.order)
+	for j := range values {
+		values[j] = v.values[embedding.Index(uint64(j))]
+	}
+	return &EvaluationVector{domain: sub, values: values}, nil
+}
+
+// Interpolate returns the unique polynomial of degree below the domain's
+// order taking the vector's values, by an inverse NTT over the coset.
+// Returns an error if the domain does not use the NTT generator
+// field.PrimitiveRootOfUnity(order) or exceeds the NTT's 2^31 points.
+func (v *EvaluationVector) Interpolate() (*Polynomial, error) {
+	if err := checkNTTDomain(v.domain); err != nil {
+		return nil, fmt.Errorf("interpolate: %w", err)
+	}
+	// The values are those of q(x) = p(offset*x) on the subgroup, and the
+	// coefficients of p are those of q divided by offset^i.
+	coefficients := v.Values()
+	ntt.INTT(coefficients)
+	offsetInverse := v.domain.offset.Inverse()
+	scale := field.One
+	for i := range coefficients {
+		coefficients[i] = coefficients[i].Mul(scale)
+		scale = scale.Mul(offsetInverse)
+	}
+	return New(coefficients), nil
+}
+
+// EvaluateOn returns the evaluations of p on every point of the domain,
+// by an NTT over the coset. Returns an error if the domain does not use the
+// NTT generator field.PrimitiveRootOfUnity(order) or exceeds the NTT's 2^31
+// points.
+func (p *Polynomial) EvaluateOn(domain DomainDescriptor) (*EvaluationVector, error) {
+	if err := checkNTTDomain(domain); err != nil {
+		return nil, fmt.Errorf("evaluate: %w", err)
+	}
+	// On the coset x^order = offset^order, so coefficient i of p(offset*x)
+	// contributes to coefficient i mod order of the folded polynomial.
+	values := make([]field.Element, domain.order)
+	scale := field.One
+	for i, c := range p.coefficients {
+		slot := uint64(i) % domain.order
+		values[slot] = values[slot].Add(c.Mul(scale))
+		scale = scale.Mul(domain.offset)
+	}
+	ntt.NTT(values)
+	return &EvaluationVector{domain: domain, values: values}, nil
+}
+
+// checkNTTDomain returns an error unless evaluations over the domain can be
+// computed with NTT.
+func checkNTTDomain(domain DomainDescriptor) error {
+	if domain.order == 0 {
+		return fmt.Errorf("missing domain")
+	}
+	if domain.order > maxNTTOrder {
+		return fmt.Errorf("%s exceeds the largest NTT of %d points", domain, maxNTTOrder)
+	}
+	generator, err := field.PrimitiveRootOfUnity(domain.order)
+	if err != nil {
+		return err
+	}
+	if !domain.generator.Equal(generator) {
+		return fmt.Errorf("%s does not use the NTT generator", domain)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package polynomial
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func mustEvaluate(t *testing.T, p *Polynomial, domain DomainDescriptor) *EvaluationVector {
+	t.Helper()
+	v, err := p.EvaluateOn(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestEvaluateOnMatchesPointwiseEvaluation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	for _, domain := range []DomainDescriptor{
+		mustDomain(t, 1, cosetOffset),
+		mustDomain(t, 16, field.One),
+		mustDomain(t, 16, cosetOffset),
+	} {
+		// Degrees below, at and above the domain order.
+		for _, numCoefficients := range []int{0, 5, 16, 17, 40} {
+			p := randomPolynomial(rng, numCoefficients)
+			v := mustEvaluate(t, p, domain)
+			for i := 0; i < v.Len(); i++ {
+				if !v.At(i).Equal(p.Evaluate(domain.Element(uint64(i)))) {
+					t.Fatalf("%s, %d coefficients: value %d differs", domain, numCoefficients, i)
+				}
+			}
+		}
+	}
+}
+
+func TestInterpolateInvertsEvaluateOn(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	domain := mustDomain(t, 32, cosetOffset)
+	p := randomPolynomial(rng, 32)
+	interpolated, err := mustEvaluate(t, p, domain).Interpolate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !interpolated.Equal(p) {
+		t.Fatal("interpolation over the coset does not recover the polynomial")
+	}
+}
+
+func TestPointwiseOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	domain := mustDomain(t, 64, cosetOffset)
+	p, q := randomPolynomial(rng, 20), randomPolynomial(rng, 30)
+	vp, vq := mustEvaluate(t, p, domain), mustEvaluate(t, q, domain)
+
+	for name, tc := range map[string]struct {
+		op   func(a, b *EvaluationVector) (*EvaluationVector, error)
+		want *Polynomial
+	}{
+		"add": {(*EvaluationVector).Add, p.Add(q)},
+		"sub": {(*EvaluationVector).Sub, p.Sub(q)},
+		"mul": {(*EvaluationVector).Mul, p.Mul(q)},
+	} {
+		got, err := tc.op(vp, vq)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		interpolated, err := got.Interpolate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !interpolated.Equal(tc.want) {
+			t.Errorf("%s: result is not the evaluation of the combined polynomial", name)
+		}
+	}
+}
+
+// TestPointwiseOperationsRejectMismatchedDomains reproduces treating trace
+// domain evaluations as LDE domain evaluations: each combination fails
+// instead of producing garbage.
+func TestPointwiseOperationsRejectMismatchedDomains(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	p := randomPolynomial(rng, 8)
+	lde := mustEvaluate(t, p, mustDomain(t, 64, cosetOffset))
+
+	reordered, err := NewDomainDescriptorWithGenerator(mustPrimitiveRoot(t, 64).ModPow(3), cosetOffset, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	others := map[string]DomainDescriptor{
+		"trace domain":   mustDomain(t, 8, field.One),
+		"lde subgroup":   mustDomain(t, 64, field.One),
+		"reordered":      reordered,
+		"shifted offset": mustDomain(t, 64, cosetOffset.Mul(mustPrimitiveRoot(t, 64))),
+	}
+	for name, domain := range others {
+		other, err := NewEvaluationVector(domain, make([]field.Element, domain.Order()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, op := range []func(*EvaluationVector) (*EvaluationVector, error){lde.Add, lde.Sub, lde.Mul} {
+			if _, err := op(other); !errors.Is(err, ErrDomainMismatch) {
+				t.Errorf("%s: got %v, want ErrDomainMismatch", name, err)
+			}
+		}
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	rng := rand.New(rand.NewSource(1193))
+	p := randomPolynomial(rng, 8)
+	ldeDomain := mustDomain(t, 64, cosetOffset)
+	lde := mustEvaluate(t, p, ldeDomain)
+
+	sub := mustDomain(t, 8, ldeDomain.Element(3))
+	restricted, err := lde.Restrict(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mustEvaluate(t, p, sub)
+	sum, err := restricted.Sub(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < sum.Len(); i++ {
+		if !sum.At(i).IsZero() {
+			t.Fatalf("restricted value %d differs from evaluation on the subdomain", i)
+		}
+	}
+
+	if _, err := lde.Restrict(mustDomain(t, 8, field.One)); !errors.Is(err, ErrDomainMismatch) {
+		t.Errorf("restricting to the trace subgroup: got %v", err)
+	}
+}
+
+func TestEvaluationVectorValidation(t *testing.T) {
+	domain := mustDomain(t, 8, cosetOffset)
+	if _, err := NewEvaluationVector(domain, make([]field.Element, 7)); err == nil {
+		t.Error("accepted too few values")
+	}
+	if _, err := NewEvaluationVector(DomainDescriptor{}, nil); err == nil {
+		t.Error("accepted the zero domain")
+	}
+
+	values := make([]field.Element, 8)
+	v, err := NewEvaluationVector(domain, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values[0] = field.One
+	if !v.At(0).IsZero() {
+		t.Error("NewEvaluationVector does not copy its values")
+	}
+
+	// Interpolation and evaluation need the NTT generator.
+	reordered, err := NewDomainDescriptorWithGenerator(domain.Generator().ModPow(3), cosetOffset, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewEvaluationVector(reordered, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Interpolate(); err == nil {
+		t.Error("interpolated over a non-NTT generator")
+	}
+	if _, err := One().EvaluateOn(reordered); err == nil {
+		t.Error("evaluated over a non-NTT generator")
+	}
+}
@@ -0,0 +1,43 @@
+package polynomial
+
+import (
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// RandomLinearCombination computes Σ αⁱ·polys[i] = polys[0] + α·polys[1] + … + α^{k-1}·polys[k-1].
+//
+// This batches many polynomials into one, e.g. before a single divisibility
+// check. The result is accumulated in place into a single coefficient slice
+// sized to the largest input, so no intermediate polynomials are allocated.
+// Each input contributes only as many multiplications as it has
+// coefficients, which keeps the cost low when degrees differ widely.
+//
+// Returns Zero for empty input and a clone for a single polynomial.
+func RandomLinearCombination(polys []*Polynomial, alpha field.Element) *Polynomial {
+	if len(polys) == 0 {
+		return Zero()
+	}
+	if len(polys) == 1 {
+		return polys[0].Clone()
+	}
+
+	maxLen := 0
+	for _, p := range polys {
+		if n := p.Degree() + 1; n > maxLen {
+			maxLen = n
+		}
+	}
+
+	coeffs := make([]field.Element, maxLen)
+	weight := field.One
+	for _, p := range polys {
+		for i, c := range p.unsafeCoefficients() {
+			coeffs[i] = coeffs[i].Add(c.Mul(weight))
+		}
+		weight = weight.Mul(alpha)
+	}
+
+	result := &Polynomial{coefficients: coeffs}
+	result.normalize()
+	return result
+}
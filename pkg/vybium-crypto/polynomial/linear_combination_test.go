@@ -0,0 +1,112 @@
+package polynomial
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func randomPolynomial(rng *rand.Rand, numCoefficients int) *Polynomial {
+	coeffs := make([]field.Element, numCoefficients)
+	for i := range coeffs {
+		coeffs[i] = field.New(rng.Uint64())
+	}
+	return New(coeffs)
+}
+
+// naiveLinearCombination is the ScalarMul + Add loop RandomLinearCombination replaces.
+func naiveLinearCombination(polys []*Polynomial, alpha field.Element) *Polynomial {
+	result := Zero()
+	weight := field.One
+	for _, p := range polys {
+		result = result.Add(p.ScalarMul(weight))
+		weight = weight.Mul(alpha)
+	}
+	return result
+}
+
+func TestRandomLinearCombination(t *testing.T) {
+	rng := rand.New(rand.NewSource(1152))
+
+	tests := []struct {
+		name    string
+		lengths []int
+	}{
+		{"empty", nil},
+		{"single", []int{7}},
+		{"equal degrees", []int{16, 16, 16, 16}},
+		{"wildly different degrees", []int{1, 1000, 0, 3, 257, 2}},
+		{"all zero polynomials", []int{0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polys := make([]*Polynomial, len(tt.lengths))
+			for i, n := range tt.lengths {
+				polys[i] = randomPolynomial(rng, n)
+			}
+			alpha := field.New(rng.Uint64())
+
+			got := RandomLinearCombination(polys, alpha)
+			want := naiveLinearCombination(polys, alpha)
+			if !got.Equal(want) {
+				t.Errorf("combination differs from naive loop")
+			}
+		})
+	}
+}
+
+func TestRandomLinearCombinationSingleIsClone(t *testing.T) {
+	p := New([]field.Element{field.New(1), field.New(2)})
+	result := RandomLinearCombination([]*Polynomial{p}, field.New(5))
+
+	if !result.Equal(p) {
+		t.Fatal("single polynomial should be returned unchanged")
+	}
+	result.coefficients[0] = field.New(99)
+	if !p.coefficients[0].Equal(field.New(1)) {
+		t.Error("result should not alias the input")
+	}
+}
+
+func TestRandomLinearCombinationCancellation(t *testing.T) {
+	// p + α·q with q = -p/α cancels to zero; the result must be normalized
+	alpha := field.New(3)
+	p := New([]field.Element{field.New(4), field.New(8)})
+	q := p.ScalarMul(alpha.Inverse()).Neg()
+
+	result := RandomLinearCombination([]*Polynomial{p, q}, alpha)
+	if !result.IsZero() || result.Degree() != -1 {
+		t.Errorf("expected zero polynomial, got degree %d", result.Degree())
+	}
+}
+
+func benchmarkLinearCombinationInputs() ([]*Polynomial, field.Element) {
+	rng := rand.New(rand.NewSource(1))
+	polys := make([]*Polynomial, 100)
+	for i := range polys {
+		polys[i] = randomPolynomial(rng, 1<<12)
+	}
+	return polys, field.New(rng.Uint64())
+}
+
+func BenchmarkRandomLinearCombination(b *testing.B) {
+	polys, alpha := benchmarkLinearCombinationInputs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = RandomLinearCombination(polys, alpha)
+	}
+}
+
+func BenchmarkRandomLinearCombinationNaive(b *testing.B) {
+	polys, alpha := benchmarkLinearCombinationInputs()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = naiveLinearCombination(polys, alpha)
+	}
+}
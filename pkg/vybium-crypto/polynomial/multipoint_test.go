@@ -0,0 +1,161 @@
+package polynomial
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// evaluateWithTree runs the remainder tree whatever the size, bypassing
+// EvaluateDomain's fallback to BatchEvaluate.
+func evaluateWithTree(p *Polynomial, points []field.Element) []field.Element {
+	values := make([]field.Element, len(points))
+	newSubproductTree(points).evaluate(p, values)
+	return values
+}
+
+func TestEvaluateDomainMatchesBatchEvaluate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1263))
+	for _, n := range []int{1, 2, 17, 64, 300, 1024} {
+		points := randomFieldElements(rng, n)
+		for _, numCoefficients := range []int{0, 1, n / 2, n, 3*n + 5} {
+			p := randomPolynomial(rng, numCoefficients)
+			want := p.BatchEvaluate(points)
+			for name, got := range map[string][]field.Element{
+				"EvaluateDomain": p.EvaluateDomain(points),
+				"tree":           evaluateWithTree(p, points),
+			} {
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("%s, %d points, degree %d: value %d is %v, want %v", name, n, p.Degree(), i, got[i], want[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEvaluateDomainRepeatedPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(1263))
+	points := make([]field.Element, 2*multipointMinPoints)
+	for i := range points {
+		points[i] = field.New(uint64(i % 7))
+	}
+	p := randomPolynomial(rng, len(points))
+	got, want := p.EvaluateDomain(points), p.BatchEvaluate(points)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("value %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDivideNTTMatchesDivide(t *testing.T) {
+	rng := rand.New(rand.NewSource(1263))
+	for trial := 0; trial < 20; trial++ {
+		dividend := randomPolynomial(rng, 1+rng.Intn(2000))
+		divisor := randomPolynomial(rng, 1+rng.Intn(1000))
+		quotient, remainder := dividend.DivideNTT(divisor)
+		wantQuotient, wantRemainder := dividend.Divide(divisor)
+		if !quotient.Equal(wantQuotient) || !remainder.Equal(wantRemainder) {
+			t.Fatalf("degree %d by degree %d: DivideNTT differs from Divide", dividend.Degree(), divisor.Degree())
+		}
+	}
+}
+
+func TestFastInterpolateMatchesInterpolate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1264))
+	for _, n := range []int{1, 2, 3, 16, 17, 33, 100, 256} {
+		xs := randomFieldElements(rng, n)
+		ys := randomFieldElements(rng, n)
+		points := make([][2]field.Element, n)
+		for i := range points {
+			points[i] = [2]field.Element{xs[i], ys[i]}
+		}
+
+		got := FastInterpolate(xs, ys)
+		if want := Interpolate(points); !got.Equal(want) {
+			t.Fatalf("%d points: FastInterpolate differs from Interpolate", n)
+		}
+		if got.Degree() >= n {
+			t.Errorf("%d points: degree %d", n, got.Degree())
+		}
+	}
+}
+
+func TestFastInterpolateRecoversPolynomial(t *testing.T) {
+	rng := rand.New(rand.NewSource(1264))
+	p := randomPolynomial(rng, 1000)
+	xs := randomFieldElements(rng, 1000)
+	if got := FastInterpolate(xs, p.EvaluateDomain(xs)); !got.Equal(p) {
+		t.Error("interpolating 1000 evaluations did not recover the polynomial")
+	}
+}
+
+func TestFastInterpolatePanics(t *testing.T) {
+	duplicate := []field.Element{field.New(1), field.New(2), field.New(1)}
+	tests := []struct {
+		name   string
+		xs, ys []field.Element
+		want   any
+	}{
+		{"empty", nil, nil, "cannot interpolate through zero points"},
+		{"duplicate", duplicate, duplicate, "duplicate x-coordinates in interpolation points"},
+		{"length mismatch", duplicate, duplicate[:2], "cannot interpolate 2 values at 3 points"},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("%s: panic %v, want %v", tt.name, got, tt.want)
+				}
+			}()
+			FastInterpolate(tt.xs, tt.ys)
+		}()
+	}
+
+	// Interpolate panics with the same message
+	defer func() {
+		if got := recover(); got != tests[1].want {
+			t.Errorf("Interpolate: panic %v, want %v", got, tests[1].want)
+		}
+	}()
+	Interpolate([][2]field.Element{{duplicate[0]}, {duplicate[2]}})
+}
+
+func BenchmarkEvaluateDomain(b *testing.B) {
+	rng := rand.New(rand.NewSource(1263))
+	p := randomPolynomial(rng, 4096)
+	points := randomFieldElements(rng, 4096)
+	b.Run("EvaluateDomain", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.EvaluateDomain(points)
+		}
+	})
+	b.Run("BatchEvaluate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.BatchEvaluate(points)
+		}
+	})
+}
+
+func BenchmarkFastInterpolate(b *testing.B) {
+	rng := rand.New(rand.NewSource(1264))
+	xs := randomFieldElements(rng, 256)
+	ys := randomFieldElements(rng, 256)
+	points := make([][2]field.Element, len(xs))
+	for i := range points {
+		points[i] = [2]field.Element{xs[i], ys[i]}
+	}
+	b.Run("FastInterpolate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			FastInterpolate(xs, ys)
+		}
+	})
+	b.Run("Interpolate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Interpolate(points)
+		}
+	})
+}
@@ -4,10 +4,16 @@
 // evaluation, interpolation, and division. Polynomials are represented as coefficient vectors
 // with coefficients stored in order of increasing degree. The implementation supports operations
 // commonly needed in zero-knowledge proof systems such as vanishing polynomials and quotient computation.
+//
+// A Polynomial is immutable once constructed: no exported method modifies its
+// receiver, and accessors return copies. A *Polynomial can therefore be
+// shared and cached, as zerofier trees and the Shah polynomial are, without
+// callers being able to corrupt it.
 package polynomial
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
 )
@@ -47,6 +53,7 @@ func X() *Polynomial {
 }
 
 // XToThe returns x^n.
+// Panics if n is negative; see XToTheChecked.
 func XToThe(n int) *Polynomial {
 	if n < 0 {
 		panic("negative exponent")
@@ -62,6 +69,14 @@ func XToThe(n int) *Polynomial {
 	return &Polynomial{coefficients: coeffs}
 }
 
+// XToTheChecked returns x^n, or an error if n is negative.
+func XToTheChecked(n int) (*Polynomial, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative exponent %d", n)
+	}
+	return XToThe(n), nil
+}
+
 // Degree returns the degree of the polynomial.
 // Returns -1 for the zero polynomial.
 func (p *Polynomial) Degree() int {
@@ -72,14 +87,27 @@ func (p *Polynomial) Degree() int {
 	return deg
 }
 
-// Coefficients returns the polynomial's coefficients in order of increasing degree.
+// Coefficients returns a copy of the polynomial's coefficients in order of increasing degree.
 // The leading coefficient is guaranteed to be non-zero (except for the zero polynomial).
+// Callers may modify the result freely; use Coefficient to read single
+// coefficients without copying.
 func (p *Polynomial) Coefficients() []field.Element {
-	deg := p.Degree()
-	if deg < 0 {
-		return []field.Element{}
+	return append([]field.Element{}, p.unsafeCoefficients()...)
+}
+
+// unsafeCoefficients returns the coefficients up to the degree without
+// copying. The result aliases the polynomial and must not be modified.
+func (p *Polynomial) unsafeCoefficients() []field.Element {
+	return p.coefficients[:p.Degree()+1]
+}
+
+// Coefficient returns the coefficient of x^i, or zero if i exceeds the
+// degree. Panics if i is negative.
+func (p *Polynomial) Coefficient(i int) field.Element {
+	if i < len(p.coefficients) {
+		return p.coefficients[i]
 	}
-	return p.coefficients[:deg+1]
+	return field.Zero
 }
 
 // LeadingCoefficient returns the leading coefficient (coefficient of highest degree term).
@@ -292,8 +320,9 @@ func (p *Polynomial) Shift(offset field.Element) *Polynomial {
 }
 
 // Scale scales the polynomial: returns p(alpha * x) for a scalar alpha.
+// Scaling by zero gives the constant polynomial p(0).
 func (p *Polynomial) Scale(alpha field.Element) *Polynomial {
-	if p.IsZero() || alpha.IsZero() {
+	if p.IsZero() {
 		return Zero()
 	}
 
@@ -306,8 +335,117 @@ func (p *Polynomial) Scale(alpha field.Element) *Polynomial {
 	return New(coeffs)
 }
 
+// Compose returns the composition p(q(x)), computed by Horner's method over
+// polynomials, so that p.Compose(q).Evaluate(x) equals
+// p.Evaluate(q.Evaluate(x)). The result has degree deg(p)·deg(q).
+//
+// A zero or constant p is its own composition and is returned as is,
+// without allocating; for a constant q the result is the constant p(q).
+func (p *Polynomial) Compose(q *Polynomial) *Polynomial {
+	degP := p.Degree()
+	if degP <= 0 {
+		return p
+	}
+	if q.Degree() <= 0 {
+		return New([]field.Element{p.Evaluate(q.Coefficient(0))})
+	}
+
+	result := New(p.coefficients[degP : degP+1])
+	for i := degP - 1; i >= 0; i-- {
+		result = result.MulNTT(q)
+		result.coefficients[0] = result.coefficients[0].Add(p.coefficients[i])
+	}
+	return result
+}
+
+// Pow returns p raised to the given power by binary exponentiation over
+// MulNTT, which itself multiplies directly below MulNTTThreshold. Any
+// polynomial to the power 0, the zero polynomial included, is One().
+//
+// The result has degree deg(p)·exponent and is held in memory in full, so
+// callers must bound the exponent by the degree they can afford.
+func (p *Polynomial) Pow(exponent uint64) *Polynomial {
+	if exponent == 0 {
+		return One()
+	}
+	if p.Degree() <= 0 {
+		if p.IsZero() {
+			return Zero()
+		}
+		return New([]field.Element{p.coefficients[0].ModPow(exponent)})
+	}
+
+	// Binary exponentiation from the most significant bit, as field ModPow
+	result := p
+	for i := bits.Len64(exponent) - 2; i >= 0; i-- {
+		result = result.MulNTT(result)
+		if exponent&(1<<i) != 0 {
+			result = result.MulNTT(p)
+		}
+	}
+	return result
+}
+
+// RootsIn returns the candidates at which p evaluates to zero, in the order
+// given. Every candidate is a root of the zero polynomial.
+func (p *Polynomial) RootsIn(candidates []field.Element) []field.Element {
+	var roots []field.Element
+	for _, candidate := range candidates {
+		if p.Evaluate(candidate).IsZero() {
+			roots = append(roots, candidate)
+		}
+	}
+	return roots
+}
+
+// QuadraticRoots solves p(x) = 0 directly for a polynomial of degree at most
+// 2, returning its distinct roots in ascending canonical order and true. A
+// non-zero constant has no roots; a linear polynomial has exactly one.
+//
+// It returns nil and false when the roots cannot be listed: for the zero
+// polynomial, for degree above 2, and when the discriminant of a quadratic
+// is a quadratic non-residue, so that it has no roots in the base field.
+func (p *Polynomial) QuadraticRoots() ([]field.Element, bool) {
+	switch p.Degree() {
+	case 0:
+		return nil, true
+	case 1:
+		return []field.Element{p.coefficients[0].Neg().Div(p.coefficients[1])}, true
+	case 2:
+	default:
+		return nil, false
+	}
+
+	// x = (-b ± sqrt(b² - 4ac)) / 2a
+	c, b, a := p.coefficients[0], p.coefficients[1], p.coefficients[2]
+	discriminant := b.Square().Sub(field.New(4).Mul(a).Mul(c))
+	s, ok := discriminant.Sqrt()
+	if !ok {
+		return nil, false
+	}
+	twoAInv := a.Add(a).Inverse()
+	first := b.Neg().Sub(s).Mul(twoAInv)
+	if s.IsZero() {
+		return []field.Element{first}, true
+	}
+	second := b.Neg().Add(s).Mul(twoAInv)
+	if second.Less(first) {
+		first, second = second, first
+	}
+	return []field.Element{first, second}, true
+}
+
+// MonicChecked returns a monic version of the polynomial, or an error if the
+// polynomial is zero.
+func (p *Polynomial) MonicChecked() (*Polynomial, error) {
+	if p.IsZero() {
+		return nil, fmt.Errorf("cannot make zero polynomial monic")
+	}
+	return p.Monic(), nil
+}
+
 // Monic returns a monic version of the polynomial (leading coefficient = 1).
-// Panics if the polynomial is zero.
+// Panics if the polynomial is zero; see MonicChecked.
 func (p *Polynomial) Monic() *Polynomial {
 	if p.IsZero() {
 		panic("cannot make zero polynomial monic")
@@ -322,42 +460,11 @@ func (p *Polynomial) Monic() *Polynomial {
 	return p.ScalarMul(inv)
 }
 
-// String returns a string representation of the polynomial.
+// String returns a string representation of the polynomial, highest degree
+// first, as in "3x^2 + 2x + 1". Polynomials with more than DefaultVerbosity
+// nonzero terms are elided; see Format. Parse accepts the unelided form.
 func (p *Polynomial) String() string {
-	if p.IsZero() {
-		return "0"
-	}
-
-	result := ""
-	deg := p.Degree()
-	for i := deg; i >= 0; i-- {
-		coeff := p.coefficients[i]
-		if coeff.IsZero() {
-			continue
-		}
-
-		if result != "" {
-			result += " + "
-		}
-
-		if !coeff.IsOne() || i == 0 {
-			result += fmt.Sprintf("%v", coeff.Value())
-		}
-
-		switch i {
-		case 0:
-			// Just the coefficient
-		case 1:
-			result += "x"
-		default:
-			result += fmt.Sprintf("x^%d", i)
-		}
-	}
-
-	if result == "" {
-		return "0"
-	}
-	return result
+	return p.Format(DefaultVerbosity)
 }
 
 // Interpolate performs Lagrange interpolation through the given points.
@@ -367,51 +474,89 @@ func (p *Polynomial) String() string {
 // Panics if:
 // - points is empty
 // - any two points have the same x-coordinate
+//
+// See InterpolateChecked for untrusted points.
 func Interpolate(points [][2]field.Element) *Polynomial {
-	if len(points) == 0 {
-		panic("cannot interpolate through zero points")
-	}
+	validateInterpolationPoints(points)
+	return interpolate(points)
+}
 
-	// Check for duplicate x-coordinates
-	for i := 0; i < len(points); i++ {
-		for j := i + 1; j < len(points); j++ {
-			if points[i][0].Equal(points[j][0]) {
-				panic("duplicate x-coordinates in interpolation points")
-			}
-		}
+// InterpolateChecked is Interpolate, returning an error instead of panicking
+// if points is empty or two points share an x-coordinate.
+func InterpolateChecked(points [][2]field.Element) (*Polynomial, error) {
+	if err := checkInterpolationPoints(points); err != nil {
+		return nil, err
 	}
+	return interpolate(points), nil
+}
 
+func interpolate(points [][2]field.Element) *Polynomial {
 	result := Zero()
+	for i := range points {
+		result = result.Add(lagrangeTerm(points, i))
+	}
+	return result
+}
 
-	// Lagrange interpolation
+// validateInterpolationPoints panics if points is empty or contains
+// duplicate x-coordinates.
+func validateInterpolationPoints(points [][2]field.Element) {
+	if err := checkInterpolationPoints(points); err != nil {
+		panic(err.Error())
+	}
+}
+
+// checkInterpolationPoints returns an error if points is empty or contains
+// duplicate x-coordinates.
+func checkInterpolationPoints(points [][2]field.Element) error {
+	xs := make([]field.Element, len(points))
 	for i, point := range points {
-		xi, yi := point[0], point[1]
+		xs[i] = point[0]
+	}
+	return checkDistinctXs(xs)
+}
 
-		// Build Lagrange basis polynomial L_i(x)
-		basis := One()
-		denominator := field.One
+// checkDistinctXs returns an error if xs is empty or contains duplicates.
+func checkDistinctXs(xs []field.Element) error {
+	if len(xs) == 0 {
+		return fmt.Errorf("cannot interpolate through zero points")
+	}
 
-		for j, otherPoint := range points {
-			if i == j {
-				continue
-			}
-			xj := otherPoint[0]
+	seen := make(map[field.Element]struct{}, len(xs))
+	for _, x := range xs {
+		if _, ok := seen[x]; ok {
+			return fmt.Errorf("duplicate x-coordinates in interpolation points")
+		}
+		seen[x] = struct{}{}
+	}
+	return nil
+}
+
+// lagrangeTerm returns y_i * L_i(x), the i-th term of the Lagrange
+// interpolant through points.
+func lagrangeTerm(points [][2]field.Element, i int) *Polynomial {
+	xi, yi := points[i][0], points[i][1]
 
-			// basis *= (x - xj)
-			xMinusXj := New([]field.Element{xj.Neg(), field.One})
-			basis = basis.Mul(xMinusXj)
+	// Build Lagrange basis polynomial L_i(x)
+	basis := One()
+	denominator := field.One
 
-			// denominator *= (xi - xj)
-			denominator = denominator.Mul(xi.Sub(xj))
+	for j, otherPoint := range points {
+		if i == j {
+			continue
 		}
+		xj := otherPoint[0]
 
-		// basis *= yi / denominator
-		basis = basis.ScalarMul(yi.Mul(denominator.Inverse()))
+		// basis *= (x - xj)
+		xMinusXj := New([]field.Element{xj.Neg(), field.One})
+		basis = basis.Mul(xMinusXj)
 
-		result = result.Add(basis)
+		// denominator *= (xi - xj)
+		denominator = denominator.Mul(xi.Sub(xj))
 	}
 
-	return result
+	// basis *= yi / denominator
+	return basis.ScalarMul(yi.Mul(denominator.Inverse()))
 }
 
 // Zerofier returns the polynomial that has zeros at all given points.
@@ -478,3 +623,16 @@ func XGCD(x, y *Polynomial) (*Polynomial, *Polynomial, *Polynomial) {
 
 	return gcd, aResult, bResult
 }
+
+// GCD returns the monic greatest common divisor of a and b. It runs the
+// Euclidean loop of XGCD without tracking the Bézout coefficients.
+// GCD(p, 0) is p.Monic() and GCD(0, 0) is the zero polynomial.
+func GCD(a, b *Polynomial) *Polynomial {
+	for !b.IsZero() {
+		a, b = b, a.Mod(b)
+	}
+	if a.IsZero() {
+		return Zero()
+	}
+	return a.Monic()
+}
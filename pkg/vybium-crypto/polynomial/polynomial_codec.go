@@ -0,0 +1,78 @@
+package polynomial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/codec"
+)
+
+// Encode returns the BFieldCodec encoding of the polynomial: the number of
+// coefficients n = Degree()+1, followed by the coefficients in order of
+// increasing degree:
+//
+//	[n, c_0, c_1, ..., c_{n-1}]
+//
+// The leading coefficient c_{n-1} is nonzero, so every polynomial has exactly
+// one encoding; the zero polynomial encodes as [0].
+func (p *Polynomial) Encode() []field.Element {
+	coefficients := p.unsafeCoefficients()
+	encoding := make([]field.Element, 0, 1+len(coefficients))
+	encoding = append(encoding, field.New(uint64(len(coefficients))))
+	return append(encoding, coefficients...)
+}
+
+// Decode implements bfieldcodec.BFieldCodec. The result is a *Polynomial.
+func (p *Polynomial) Decode(sequence []field.Element) (codec.BFieldCodec, error) {
+	decoded, err := DecodePolynomial(sequence)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// StaticLength implements bfieldcodec.BFieldCodec. Polynomials have dynamic
+// length.
+func (p *Polynomial) StaticLength() *int {
+	return nil
+}
+
+// DecodePolynomial decodes a polynomial from its BFieldCodec encoding.
+// Returns an error if the coefficient count does not match the sequence
+// length or the encoding has a zero leading coefficient.
+func DecodePolynomial(sequence []field.Element) (*Polynomial, error) {
+	return decodePolynomial(sequence, math.MaxUint64)
+}
+
+// DecodePolynomialWithMaxDegree is DecodePolynomial, additionally rejecting
+// polynomials of degree above maxDegree. The bound is checked against the
+// coefficient count before anything else is read, so a hostile count costs
+// nothing. A maxDegree of -1 admits only the zero polynomial.
+func DecodePolynomialWithMaxDegree(sequence []field.Element, maxDegree int) (*Polynomial, error) {
+	if maxDegree < -1 {
+		return nil, fmt.Errorf("invalid degree bound %d", maxDegree)
+	}
+	return decodePolynomial(sequence, uint64(maxDegree+1))
+}
+
+func decodePolynomial(sequence []field.Element, maxCoefficients uint64) (*Polynomial, error) {
+	if len(sequence) == 0 {
+		return nil, fmt.Errorf("polynomial encoding is empty")
+	}
+	count := sequence[0].Value()
+	if count > maxCoefficients {
+		return nil, fmt.Errorf("polynomial of degree %d exceeds degree bound %d", count-1, int64(maxCoefficients)-1)
+	}
+	body := sequence[1:]
+	if uint64(len(body)) != count {
+		return nil, fmt.Errorf("polynomial has %d coefficients, encoding holds %d", count, len(body))
+	}
+	if count > 0 && body[count-1].IsZero() {
+		return nil, fmt.Errorf("non-canonical polynomial encoding: leading coefficient is zero")
+	}
+
+	coefficients := make([]field.Element, count)
+	copy(coefficients, body)
+	return &Polynomial{coefficients: coefficients}, nil
+}
@@ -0,0 +1,145 @@
+package polynomial
+
+import (
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/codec"
+)
+
+var _ codec.BFieldCodec = (*Polynomial)(nil)
+
+func TestPolynomialCodecRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1190))
+	for n := 0; n < 200; n++ {
+		coeffs := make([]field.Element, rng.Intn(40))
+		for i := range coeffs {
+			coeffs[i] = field.New(rng.Uint64())
+		}
+		// Trailing zeros are dropped by the encoding.
+		if n%3 == 0 {
+			coeffs = append(coeffs, field.Zero, field.Zero)
+		}
+		p := New(coeffs)
+
+		encoding := p.Encode()
+		if len(encoding) != p.Degree()+2 {
+			t.Fatalf("degree %d: encoding has %d elements", p.Degree(), len(encoding))
+		}
+		decoded, err := DecodePolynomial(encoding)
+		if err != nil {
+			t.Fatalf("degree %d: %v", p.Degree(), err)
+		}
+		if !decoded.Equal(p) {
+			t.Fatalf("degree %d: round trip changed the polynomial", p.Degree())
+		}
+
+		bounded, err := DecodePolynomialWithMaxDegree(encoding, p.Degree())
+		if err != nil || !bounded.Equal(p) {
+			t.Fatalf("degree %d: decoding at the exact bound failed: %v", p.Degree(), err)
+		}
+
+		viaInterface, err := (&Polynomial{}).Decode(encoding)
+		if err != nil || !viaInterface.(*Polynomial).Equal(p) {
+			t.Fatalf("degree %d: Decode failed: %v", p.Degree(), err)
+		}
+	}
+}
+
+func TestPolynomialCodecZero(t *testing.T) {
+	encoding := Zero().Encode()
+	if len(encoding) != 1 || !encoding[0].IsZero() {
+		t.Fatalf("zero polynomial encodes as %v", encoding)
+	}
+	decoded, err := DecodePolynomialWithMaxDegree(encoding, -1)
+	if err != nil || !decoded.IsZero() {
+		t.Fatalf("decoding zero polynomial: %v", err)
+	}
+	if Zero().StaticLength() != nil {
+		t.Error("polynomials have no static length")
+	}
+}
+
+func TestDecodePolynomialRejectsMalformed(t *testing.T) {
+	p := New([]field.Element{field.New(1), field.New(2), field.New(3)})
+	valid := p.Encode()
+
+	tests := []struct {
+		name     string
+		sequence []field.Element
+		errHas   string
+	}{
+		{"empty", nil, "empty"},
+		{"too short", valid[:len(valid)-1], "encoding holds 2"},
+		{"too long", append(append([]field.Element(nil), valid...), field.One), "encoding holds 4"},
+		{"trailing zero", []field.Element{field.New(3), field.One, field.One, field.Zero}, "non-canonical"},
+		{"zero as one coefficient", []field.Element{field.One, field.Zero}, "non-canonical"},
+	}
+	for _, tt := range tests {
+		_, err := DecodePolynomial(tt.sequence)
+		if err == nil || !strings.Contains(err.Error(), tt.errHas) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.errHas)
+		}
+		if decoded, err := (&Polynomial{}).Decode(tt.sequence); err == nil || decoded != nil {
+			t.Errorf("%s: Decode returned %v, %v", tt.name, decoded, err)
+		}
+	}
+}
+
+func TestDecodePolynomialWithMaxDegreeBounds(t *testing.T) {
+	encoding := New([]field.Element{field.New(1), field.New(2), field.New(3)}).Encode()
+	if _, err := DecodePolynomialWithMaxDegree(encoding, 1); err == nil || !strings.Contains(err.Error(), "degree 2 exceeds degree bound 1") {
+		t.Errorf("degree above bound: got %v", err)
+	}
+	if _, err := DecodePolynomialWithMaxDegree(encoding, -2); err == nil {
+		t.Error("accepted degree bound -2")
+	}
+	if _, err := DecodePolynomialWithMaxDegree(One().Encode(), -1); err == nil {
+		t.Error("bound -1 admitted a constant")
+	}
+}
+
+// TestDecodePolynomialRejectsHostileDegree checks that an oversized degree
+// claim is rejected without allocating or copying the coefficients, whether
+// the sequence actually carries them or not.
+func TestDecodePolynomialRejectsHostileDegree(t *testing.T) {
+	const hostile = 1_000_000
+	carried := make([]field.Element, 1+hostile)
+	carried[0] = field.New(hostile)
+	carried[hostile] = field.One
+	claimed := []field.Element{field.New(hostile)}
+	huge := []field.Element{field.Max}
+
+	for name, sequence := range map[string][]field.Element{"carried": carried, "claimed": claimed, "huge": huge} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		for i := 0; i < 10; i++ {
+			if _, err := DecodePolynomialWithMaxDegree(sequence, 255); err == nil || !strings.Contains(err.Error(), "exceeds degree bound 255") {
+				t.Fatalf("%s: got %v", name, err)
+			}
+		}
+		runtime.ReadMemStats(&after)
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 10*1024 {
+			t.Errorf("%s: rejecting allocated %d bytes", name, allocated)
+		}
+	}
+}
+
+func BenchmarkPolynomialEncodeDecode(b *testing.B) {
+	rng := rand.New(rand.NewSource(1190))
+	coeffs := make([]field.Element, 1<<12)
+	for i := range coeffs {
+		coeffs[i] = field.New(rng.Uint64())
+	}
+	coeffs[len(coeffs)-1] = field.One
+	p := New(coeffs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodePolynomial(p.Encode()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
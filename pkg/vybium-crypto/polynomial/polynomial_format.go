@@ -0,0 +1,239 @@
+package polynomial
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Verbosity bounds the number of nonzero terms Format prints.
+type Verbosity int
+
+const (
+	// Full prints every term.
+	Full Verbosity = 0
+
+	// DefaultVerbosity is the verbosity of String, which keeps log lines of
+	// large polynomials short.
+	DefaultVerbosity Verbosity = 8
+)
+
+// MaxParseDegree is the largest exponent Parse accepts, bounding the memory
+// a parsed string can demand.
+const MaxParseDegree = 1 << 24
+
+// Format renders the polynomial highest degree first, as in
+// "3x^2 + 2x + 1", with coefficients as canonical field values. Coefficients
+// of 1 are omitted, except in the constant term.
+//
+// If the polynomial has more nonzero terms than v allows, the middle terms
+// are replaced by a summary, keeping the v/2 highest and the v - v/2 lowest
+// terms:
+//
+//	3x^65536 + … [degree 65536, 65537 nonzero terms] … + 1
+//
+// A non-positive v prints every term, like Full.
+func (p *Polynomial) Format(v Verbosity) string {
+	var degrees []int
+	for i := p.Degree(); i >= 0; i-- {
+		if !p.coefficients[i].IsZero() {
+			degrees = append(degrees, i)
+		}
+	}
+	if len(degrees) == 0 {
+		return "0"
+	}
+
+	terms := make([]string, 0, len(degrees))
+	if v <= 0 || len(degrees) <= int(v) {
+		for _, i := range degrees {
+			terms = append(terms, p.formatTerm(i))
+		}
+		return strings.Join(terms, " + ")
+	}
+
+	high := int(v) / 2
+	for _, i := range degrees[:high] {
+		terms = append(terms, p.formatTerm(i))
+	}
+	terms = append(terms, fmt.Sprintf("… [degree %d, %d nonzero terms] …", p.Degree(), len(degrees)))
+	for _, i := range degrees[len(degrees)-(int(v)-high):] {
+		terms = append(terms, p.formatTerm(i))
+	}
+	return strings.Join(terms, " + ")
+}
+
+// formatTerm renders the nonzero term of degree i.
+func (p *Polynomial) formatTerm(i int) string {
+	coeff := p.coefficients[i]
+	result := ""
+	if !coeff.IsOne() || i == 0 {
+		result = strconv.FormatUint(coeff.Value(), 10)
+	}
+	switch i {
+	case 0:
+		return result
+	case 1:
+		return result + "x"
+	default:
+		return result + "x^" + strconv.Itoa(i)
+	}
+}
+
+// Parse parses a polynomial in the variable x from the output of String or
+// Format(Full), or a variant of it:
+//
+//   - terms in any order, with repeated degrees summed and missing ones zero
+//   - an optional '*' between coefficient and x, as in "3*x^2"
+//   - a sign before the first term
+//   - '-' before a term, which negates it in the field, so "x - 1" and
+//     "x + 18446744069414584320" are the same polynomial
+//   - whitespace anywhere between tokens
+//
+// Coefficients are decimal and must be canonical, i.e. below field.P, and
+// exponents must not exceed MaxParseDegree. Elided output cannot be parsed.
+// Errors name the byte offset of the offending input.
+func Parse(s string) (*Polynomial, error) {
+	parser := polynomialParser{input: s}
+	coefficients := make(map[int]field.Element)
+	maxDegree := -1
+
+	parser.skipSpace()
+	if parser.done() {
+		return nil, parser.errorf("empty input")
+	}
+	for first := true; ; first = false {
+		negate := false
+		parser.skipSpace()
+		switch {
+		case parser.accept('+'):
+		case parser.accept('-'):
+			negate = true
+		case !first:
+			return nil, parser.errorf("expected '+' or '-'")
+		}
+
+		parser.skipSpace()
+		coeff, degree, err := parser.term()
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			coeff = coeff.Neg()
+		}
+		coefficients[degree] = coefficients[degree].Add(coeff)
+		maxDegree = max(maxDegree, degree)
+
+		parser.skipSpace()
+		if parser.done() {
+			break
+		}
+	}
+
+	coeffs := make([]field.Element, maxDegree+1)
+	for degree, coeff := range coefficients {
+		coeffs[degree] = coeff
+	}
+	return New(coeffs), nil
+}
+
+// polynomialParser scans the input of Parse.
+type polynomialParser struct {
+	input string
+	pos   int
+}
+
+func (p *polynomialParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("parse polynomial: offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *polynomialParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *polynomialParser) skipSpace() {
+	for !p.done() && strings.IndexByte(" \t\n\r", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// accept consumes c if it is the next byte.
+func (p *polynomialParser) accept(c byte) bool {
+	if !p.done() && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// number consumes a decimal number; ok is false if there is none.
+func (p *polynomialParser) number() (value uint64, start int, ok bool, err error) {
+	start = p.pos
+	for !p.done() && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, start, false, nil
+	}
+	digits := p.input[start:p.pos]
+	value, err = strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		p.pos = start
+		return 0, start, true, p.errorf("number %s out of range", digits)
+	}
+	return value, start, true, nil
+}
+
+// term consumes one term: a coefficient, x with an optional exponent, or a
+// coefficient followed by x.
+func (p *polynomialParser) term() (field.Element, int, error) {
+	coeff := field.One
+	value, start, hasCoeff, err := p.number()
+	if err != nil {
+		return field.Zero, 0, err
+	}
+	if hasCoeff {
+		if value >= field.P {
+			p.pos = start
+			return field.Zero, 0, p.errorf("coefficient %d is not below P", value)
+		}
+		coeff = field.New(value)
+		p.skipSpace()
+		if p.accept('*') {
+			p.skipSpace()
+			if p.done() || p.input[p.pos] != 'x' {
+				return field.Zero, 0, p.errorf("expected 'x' after '*'")
+			}
+		}
+	}
+
+	if !p.accept('x') {
+		if !hasCoeff {
+			if !p.done() && strings.HasPrefix(p.input[p.pos:], "…") {
+				return field.Zero, 0, p.errorf("elided polynomial cannot be parsed")
+			}
+			return field.Zero, 0, p.errorf("expected coefficient or 'x'")
+		}
+		return coeff, 0, nil
+	}
+
+	p.skipSpace()
+	if !p.accept('^') {
+		return coeff, 1, nil
+	}
+	p.skipSpace()
+	exponent, start, ok, err := p.number()
+	if err != nil {
+		return field.Zero, 0, err
+	}
+	if !ok {
+		return field.Zero, 0, p.errorf("expected exponent after '^'")
+	}
+	if exponent > MaxParseDegree {
+		p.pos = start
+		return field.Zero, 0, p.errorf("exponent %d exceeds %d", exponent, MaxParseDegree)
+	}
+	return coeff, int(exponent), nil
+}
@@ -0,0 +1,150 @@
+package polynomial
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestStringParseRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1184))
+	for n := 0; n < 500; n++ {
+		coeffs := make([]field.Element, rng.Intn(int(DefaultVerbosity)+1))
+		for i := range coeffs {
+			switch rng.Intn(4) {
+			case 0:
+				coeffs[i] = field.Zero
+			case 1:
+				coeffs[i] = field.One
+			case 2:
+				coeffs[i] = field.Max
+			default:
+				coeffs[i] = field.New(rng.Uint64())
+			}
+		}
+		p := New(coeffs)
+
+		parsed, err := Parse(p.String())
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", p.String(), err)
+		}
+		if !parsed.Equal(p) {
+			t.Fatalf("Parse(%q) = %v", p.String(), parsed)
+		}
+	}
+
+	// Full output round-trips beyond the elision limit
+	coeffs := make([]field.Element, 40)
+	for i := range coeffs {
+		coeffs[i] = field.New(uint64(i * i))
+	}
+	p := New(coeffs)
+	parsed, err := Parse(p.Format(Full))
+	if err != nil || !parsed.Equal(p) {
+		t.Errorf("Parse(Format(Full)) = %v, %v", parsed, err)
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	want := New([]field.Element{field.New(1), field.New(2), field.New(3)})
+	minusOne := field.One.Neg()
+
+	tests := []struct {
+		input string
+		want  *Polynomial
+	}{
+		{"3x^2 + 2x + 1", want},
+		{"3*x^2 + 2*x + 1", want},
+		{"1 + 2x + 3x^2", want},
+		{"x^2 + 2x + 1 + 2x^2", want},
+		{"  3 x ^ 2+2x+1\n", want},
+		{"3x^2 + 2x^1 + 1x^0", want},
+		{"0", Zero()},
+		{"x - x", Zero()},
+		{"x", X()},
+		{"+x", X()},
+		{"-1", New([]field.Element{minusOne})},
+		{"x - 1", New([]field.Element{minusOne, field.One})},
+		{"x + 18446744069414584320", New([]field.Element{minusOne, field.One})},
+		{"-x^3", New([]field.Element{field.Zero, field.Zero, field.Zero, minusOne})},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset string
+	}{
+		{"", "offset 0:"},
+		{"   ", "offset 3:"},
+		{"x + + x", "offset 4:"},
+		{"x +", "offset 3:"},
+		{"3 4", "offset 2:"},
+		{"x^", "offset 2:"},
+		{"2*", "offset 2:"},
+		{"2*y", "offset 2:"},
+		{"x y", "offset 2:"},
+		{"x^16777217", "offset 2:"},
+		{"18446744069414584321x", "offset 0:"},
+		{"x + 99999999999999999999", "offset 4:"},
+		{"x^2 + … [degree 2, 2 nonzero terms] … + 1", "offset 6:"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.offset) {
+			t.Errorf("Parse(%q): error %q does not name %s", tt.input, err, tt.offset)
+		}
+	}
+}
+
+func TestFormatElision(t *testing.T) {
+	coeffs := make([]field.Element, 20)
+	for i := range coeffs {
+		coeffs[i] = field.New(uint64(i + 1))
+	}
+	p := New(coeffs)
+
+	full := p.Format(Full)
+	if strings.Contains(full, "…") || strings.Count(full, "+") != 19 {
+		t.Errorf("Format(Full) = %q", full)
+	}
+
+	if got, want := p.Format(4), "20x^19 + 19x^18 + … [degree 19, 20 nonzero terms] … + 2x + 1"; got != want {
+		t.Errorf("Format(4) = %q, want %q", got, want)
+	}
+	if got := p.Format(20); got != full {
+		t.Errorf("Format(20) = %q, want the full output", got)
+	}
+}
+
+// TestStringBoundedForLargePolynomial guards against large polynomials
+// flooding logs.
+func TestStringBoundedForLargePolynomial(t *testing.T) {
+	coeffs := make([]field.Element, 1<<16+1)
+	for i := range coeffs {
+		coeffs[i] = field.Max
+	}
+	s := New(coeffs).String()
+	if len(s) > 512 {
+		t.Errorf("String of a degree 2^16 polynomial has %d bytes", len(s))
+	}
+	if !strings.Contains(s, "degree 65536, 65537 nonzero terms") {
+		t.Errorf("String lacks the elision summary: %q", s)
+	}
+}
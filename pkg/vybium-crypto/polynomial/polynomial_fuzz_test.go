@@ -0,0 +1,80 @@
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// fuzzElements maps each byte to a small field element, so that zeros and
+// repeated values, the inputs the checked functions reject, are common.
+func fuzzElements(data []byte, limit int) []field.Element {
+	if len(data) > limit {
+		data = data[:limit]
+	}
+	elements := make([]field.Element, len(data))
+	for i, b := range data {
+		elements[i] = field.New(uint64(b % 16))
+	}
+	return elements
+}
+
+// FuzzCheckedOperations feeds arbitrary input to the checked entry points,
+// which must report malformed input as an error and never panic.
+func FuzzCheckedOperations(f *testing.F) {
+	f.Add([]byte{}, int8(0))
+	f.Add([]byte{0}, int8(-1))
+	f.Add([]byte{1, 2, 1, 3}, int8(5))
+	f.Add([]byte{0, 0, 0, 0, 7, 0}, int8(-128))
+
+	f.Fuzz(func(t *testing.T, data []byte, n int8) {
+		elements := fuzzElements(data, 64)
+
+		for _, e := range elements {
+			inv, err := e.InverseChecked()
+			if (err != nil) != e.IsZero() {
+				t.Fatalf("InverseChecked(%v): error %v", e, err)
+			}
+			if err == nil && !e.Mul(inv).IsOne() {
+				t.Fatalf("InverseChecked(%v) is not an inverse", e)
+			}
+		}
+
+		if _, err := XToTheChecked(int(n)); (err != nil) != (n < 0) {
+			t.Fatalf("XToTheChecked(%d): error %v", n, err)
+		}
+
+		p := New(elements)
+		monic, err := p.MonicChecked()
+		if (err != nil) != p.IsZero() {
+			t.Fatalf("MonicChecked: error %v", err)
+		}
+		if err == nil && !monic.LeadingCoefficient().IsOne() {
+			t.Fatal("MonicChecked result is not monic")
+		}
+
+		half := len(elements) / 2
+		a, b := New(elements[:half]), New(elements[half:])
+		quotient, remainder, err := a.DivideChecked(b)
+		if (err != nil) != b.IsZero() {
+			t.Fatalf("DivideChecked: error %v", err)
+		}
+		if err == nil && !quotient.Mul(b).Add(remainder).Equal(a) {
+			t.Fatal("DivideChecked: quotient * divisor + remainder != dividend")
+		}
+
+		points := make([][2]field.Element, half)
+		for i := range points {
+			points[i] = [2]field.Element{elements[2*i], elements[2*i+1]}
+		}
+		interpolant, err := InterpolateChecked(points)
+		if err != nil {
+			return
+		}
+		for _, point := range points {
+			if interpolant.Evaluate(point[0]) != point[1] {
+				t.Fatalf("InterpolateChecked result misses point %v", point)
+			}
+		}
+	})
+}
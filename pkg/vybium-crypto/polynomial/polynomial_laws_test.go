@@ -0,0 +1,132 @@
+package polynomial_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest/lawgen"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+)
+
+// randomLawPolynomial returns a polynomial of degree at most 8, including the
+// zero polynomial and constants.
+func randomLawPolynomial(rng *rand.Rand) *polynomial.Polynomial {
+	coeffs := make([]field.Element, rng.Intn(10))
+	for i := range coeffs {
+		coeffs[i] = lawgen.RandomBFE(rng)
+	}
+	return polynomial.New(coeffs)
+}
+
+var lawRing = lawtest.Ring[*polynomial.Polynomial]{
+	Name:   "polynomial",
+	Zero:   polynomial.Zero(),
+	One:    polynomial.One(),
+	Add:    (*polynomial.Polynomial).Add,
+	Sub:    (*polynomial.Polynomial).Sub,
+	Mul:    (*polynomial.Polynomial).Mul,
+	Neg:    (*polynomial.Polynomial).Neg,
+	Equal:  (*polynomial.Polynomial).Equal,
+	Random: randomLawPolynomial,
+}
+
+var lawBaseRing = lawtest.Ring[field.Element]{
+	Name:   "field",
+	Zero:   field.Zero,
+	One:    field.One,
+	Add:    field.Element.Add,
+	Sub:    field.Element.Sub,
+	Mul:    field.Element.Mul,
+	Neg:    field.Element.Neg,
+	Equal:  field.Element.Equal,
+	Random: lawgen.RandomBFE,
+}
+
+func TestPolynomialLaws(t *testing.T) {
+	lawtest.CheckRing(t, lawRing, lawtest.Config{Seed: 1173})
+}
+
+// Evaluation at any point is a ring homomorphism, as is lifting constants.
+func TestPolynomialHomomorphisms(t *testing.T) {
+	for _, x := range []field.Element{field.Zero, field.One, field.Max, field.New(0x1234567890abcdef)} {
+		evaluate := func(p *polynomial.Polynomial) field.Element { return p.Evaluate(x) }
+		lawtest.CheckHomomorphism(t, "Evaluate("+x.String()+")", lawRing, lawBaseRing, evaluate, lawtest.Config{Seed: 1173, Cases: 500})
+	}
+
+	constant := func(c field.Element) *polynomial.Polynomial { return polynomial.New([]field.Element{c}) }
+	lawtest.CheckHomomorphism(t, "constant", lawBaseRing, lawRing, constant, lawtest.Config{Seed: 1173})
+}
+
+// Shift and Scale are compositions with x - offset and alpha·x, so they
+// commute with evaluation, including for zero offsets and scalars.
+func TestShiftScaleEvaluation(t *testing.T) {
+	lawtest.ForEachCase(t, lawtest.Config{Seed: 1173}, func(rng *rand.Rand, seed int64) bool {
+		p, x, c := randomLawPolynomial(rng), lawgen.RandomBFE(rng), lawgen.RandomBFE(rng)
+		if got, want := p.Shift(c).Evaluate(x), p.Evaluate(x.Sub(c)); !got.Equal(want) {
+			t.Errorf("seed %d: Shift(%v) of %v evaluates to %v at %v, want %v", seed, c, p, got, x, want)
+			return false
+		}
+		if got, want := p.Scale(c).Evaluate(x), p.Evaluate(c.Mul(x)); !got.Equal(want) {
+			t.Errorf("seed %d: Scale(%v) of %v evaluates to %v at %v, want %v", seed, c, p, got, x, want)
+			return false
+		}
+		return true
+	})
+}
+
+// XGCD returns Bézout coefficients: a·x + b·y = gcd, and gcd divides both.
+func TestXGCDBezout(t *testing.T) {
+	lawtest.ForEachCase(t, lawtest.Config{Seed: 1173}, func(rng *rand.Rand, seed int64) bool {
+		// A common factor makes non-trivial gcds likely
+		common := randomLawPolynomial(rng)
+		if common.IsZero() {
+			common = polynomial.One()
+		}
+		x, y := randomLawPolynomial(rng).Mul(common), randomLawPolynomial(rng).Mul(common)
+
+		gcd, a, b := polynomial.XGCD(x, y)
+		if !a.Mul(x).Add(b.Mul(y)).Equal(gcd) {
+			t.Errorf("seed %d: a·x + b·y ≠ gcd for x=%v y=%v", seed, x, y)
+			return false
+		}
+		if !gcd.IsZero() && (!x.Mod(gcd).IsZero() || !y.Mod(gcd).IsZero()) {
+			t.Errorf("seed %d: gcd %v does not divide x=%v and y=%v", seed, gcd, x, y)
+			return false
+		}
+		return true
+	})
+}
+
+// The zerofier of n distinct points is monic of degree n and vanishes
+// exactly on them.
+func TestZerofierRoots(t *testing.T) {
+	lawtest.ForEachCase(t, lawtest.Config{Seed: 1173, Cases: 500}, func(rng *rand.Rand, seed int64) bool {
+		seen := make(map[field.Element]bool)
+		var points []field.Element
+		for n := rng.Intn(12); len(points) < n; {
+			if p := lawgen.RandomBFE(rng); !seen[p] {
+				seen[p] = true
+				points = append(points, p)
+			}
+		}
+
+		z := polynomial.Zerofier(points)
+		if z.Degree() != len(points) || !z.LeadingCoefficient().IsOne() {
+			t.Errorf("seed %d: zerofier of %d points has degree %d and leading coefficient %v", seed, len(points), z.Degree(), z.LeadingCoefficient())
+			return false
+		}
+		for _, p := range points {
+			if !z.Evaluate(p).IsZero() {
+				t.Errorf("seed %d: zerofier does not vanish at %v", seed, p)
+				return false
+			}
+		}
+		if x := lawgen.RandomBFE(rng); !seen[x] && z.Evaluate(x).IsZero() {
+			t.Errorf("seed %d: zerofier vanishes at %v, which is not a point", seed, x)
+			return false
+		}
+		return true
+	})
+}
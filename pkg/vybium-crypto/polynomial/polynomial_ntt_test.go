@@ -1,6 +1,8 @@
 package polynomial
 
 import (
+	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -20,6 +22,33 @@ func TestMulNTT(t *testing.T) {
 	}
 }
 
+func TestMulNTTMatchesMulRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1262))
+
+	// Threshold 0 takes the transform path for every product
+	for _, threshold := range []int{0, MulNTTThreshold} {
+		for trial := 0; trial < 20; trial++ {
+			p := randomPolynomial(rng, 1+rng.Intn(1001))
+			q := randomPolynomial(rng, 1+rng.Intn(1001))
+			if !p.MulNTTWithThreshold(q, threshold).Equal(p.Mul(q)) {
+				t.Fatalf("threshold %d: MulNTT differs from Mul for degrees %d and %d", threshold, p.Degree(), q.Degree())
+			}
+		}
+	}
+}
+
+func TestMulNTTThresholdBoundary(t *testing.T) {
+	rng := rand.New(rand.NewSource(1262))
+
+	for _, degrees := range [][2]int{{0, 15}, {7, 8}, {8, 8}, {1, 15}, {16, 0}} {
+		p := randomPolynomial(rng, degrees[0]+1)
+		q := randomPolynomial(rng, degrees[1]+1)
+		if !p.MulNTTWithThreshold(q, 16).Equal(p.Mul(q)) {
+			t.Errorf("degrees %v: MulNTT differs from Mul", degrees)
+		}
+	}
+}
+
 // TestEvaluateNTT tests NTT-based polynomial evaluation
 func TestEvaluateNTT(t *testing.T) {
 	// Create a polynomial
@@ -207,9 +236,9 @@ func TestNTTEdgeCases(t *testing.T) {
 	t.Run("Scale by zero", func(t *testing.T) {
 		p := New([]field.Element{field.New(1), field.New(2), field.New(3)})
 		scaled := p.Scale(field.Zero)
-		// Scaling by zero returns zero polynomial
-		if !scaled.IsZero() {
-			t.Error("Scale by zero should return zero polynomial")
+		// p(0·x) is the constant p(0)
+		if !scaled.Equal(New([]field.Element{field.New(1)})) {
+			t.Errorf("Scale by zero should return the constant term, got %v", scaled)
 		}
 	})
 
@@ -252,3 +281,81 @@ func BenchmarkEvaluateNTT(b *testing.B) {
 		_ = p.EvaluateNTT(128)
 	}
 }
+
+// cosetFixture returns a coset of the given size whose generator is a
+// primitive root other than the NTT default.
+func cosetFixture(t testing.TB, rng *rand.Rand, size int) (offset, generator field.Element) {
+	t.Helper()
+	offset = field.New(1 + rng.Uint64()%(field.P-1))
+	generator = mustPrimitiveRoot(t, uint64(size))
+	if size > 2 {
+		generator = generator.ModPow(3)
+	}
+	return offset, generator
+}
+
+func TestCosetRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1266))
+	for _, size := range []int{1, 2, 4, 64, 512} {
+		offset, generator := cosetFixture(t, rng, size)
+		for _, numCoefficients := range []int{0, 1, size / 2, size} {
+			p := randomPolynomial(rng, numCoefficients)
+			values := p.CosetEvaluate(offset, generator, size)
+			if got := CosetInterpolate(values, offset, generator); !got.Equal(p) {
+				t.Fatalf("size %d, degree %d: round trip gave %v", size, p.Degree(), got)
+			}
+		}
+	}
+}
+
+func TestCosetEvaluateMatchesPointwise(t *testing.T) {
+	rng := rand.New(rand.NewSource(1266))
+	const size = 16
+	offset, generator := cosetFixture(t, rng, size)
+	// Degree above the size exercises the folding
+	p := randomPolynomial(rng, 3*size+5)
+	values := p.CosetEvaluate(offset, generator, size)
+	point := offset
+	for i, value := range values {
+		if want := p.Evaluate(point); value != want {
+			t.Errorf("value %d is %v, want %v", i, value, want)
+		}
+		point = point.Mul(generator)
+	}
+
+	// With the NTT generator the coset is that of EvaluateOn
+	domain, err := NewDomainDescriptor(size, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vector, err := p.EvaluateOn(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.CosetEvaluate(offset, domain.Generator(), size); !reflect.DeepEqual(got, vector.Values()) {
+		t.Error("CosetEvaluate differs from EvaluateOn")
+	}
+}
+
+func TestCosetPanics(t *testing.T) {
+	p := New([]field.Element{field.One, field.New(2)})
+	root8 := mustPrimitiveRoot(t, 8)
+	tests := map[string]func(){
+		"evaluate zero offset":     func() { p.CosetEvaluate(field.Zero, root8, 8) },
+		"evaluate size":            func() { p.CosetEvaluate(field.One, root8, 6) },
+		"evaluate generator order": func() { p.CosetEvaluate(field.One, root8.Square(), 8) },
+		"interpolate zero offset":  func() { CosetInterpolate(make([]field.Element, 8), field.Zero, root8) },
+		"interpolate size":         func() { CosetInterpolate(make([]field.Element, 6), field.One, root8) },
+		"interpolate generator":    func() { CosetInterpolate(make([]field.Element, 4), field.One, root8) },
+	}
+	for name, call := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			call()
+		}()
+	}
+}
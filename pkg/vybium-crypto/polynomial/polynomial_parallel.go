@@ -0,0 +1,95 @@
+package polynomial
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// numWorkers returns the number of goroutines to split n independent work
+// items across: parallelism, or GOMAXPROCS if parallelism <= 0, but at most n.
+func numWorkers(parallelism, n int) int {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if parallelism > n {
+		parallelism = n
+	}
+	return parallelism
+}
+
+// ParallelBatchEvaluate evaluates the polynomial at multiple points, splitting
+// the points across parallelism goroutines (GOMAXPROCS if parallelism <= 0).
+// The result equals BatchEvaluate(points). All goroutines have returned when
+// ParallelBatchEvaluate returns.
+func (p *Polynomial) ParallelBatchEvaluate(points []field.Element, parallelism int) []field.Element {
+	workers := numWorkers(parallelism, len(points))
+	if workers <= 1 {
+		return p.BatchEvaluate(points)
+	}
+
+	results := make([]field.Element, len(points))
+	chunkSize := (len(points) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(points); start += chunkSize {
+		end := start + chunkSize
+		if end > len(points) {
+			end = len(points)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				results[i] = p.Evaluate(points[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// ParallelInterpolate is Interpolate with the Lagrange terms computed by
+// parallelism goroutines (GOMAXPROCS if parallelism <= 0). Each goroutine sums
+// the terms of a contiguous range of points; the partial sums are then added
+// in order. The result equals Interpolate(points).
+//
+// Panics under the same conditions as Interpolate.
+func ParallelInterpolate(points [][2]field.Element, parallelism int) *Polynomial {
+	validateInterpolationPoints(points)
+
+	workers := numWorkers(parallelism, len(points))
+	if workers <= 1 {
+		return Interpolate(points)
+	}
+
+	partials := make([]*Polynomial, workers)
+	chunkSize := (len(points) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		end := start + chunkSize
+		if end > len(points) {
+			end = len(points)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			partial := Zero()
+			for i := start; i < end; i++ {
+				partial = partial.Add(lagrangeTerm(points, i))
+			}
+			partials[w] = partial
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	result := Zero()
+	for _, partial := range partials {
+		result = result.Add(partial)
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func randomFieldElements(rng *rand.Rand, n int) []field.Element {
+	elements := make([]field.Element, n)
+	for i := range elements {
+		elements[i] = field.New(rng.Uint64())
+	}
+	return elements
+}
+
+// randomInterpolationPoints returns n points with distinct x-coordinates.
+func randomInterpolationPoints(rng *rand.Rand, n int) [][2]field.Element {
+	points := make([][2]field.Element, n)
+	for i := range points {
+		points[i] = [2]field.Element{field.New(uint64(i)*7919 + 1), field.New(rng.Uint64())}
+	}
+	return points
+}
+
+var testParallelisms = []int{-1, 0, 1, 2, 3, 7, 64}
+
+func TestParallelBatchEvaluateMatchesSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(1167))
+	p := New(randomFieldElements(rng, 50))
+
+	for _, n := range []int{0, 1, 5, 100, 1000} {
+		points := randomFieldElements(rng, n)
+		want := p.BatchEvaluate(points)
+		for _, parallelism := range testParallelisms {
+			got := p.ParallelBatchEvaluate(points, parallelism)
+			if len(got) != len(want) {
+				t.Fatalf("n=%d, parallelism=%d: got %d values", n, parallelism, len(got))
+			}
+			for i := range want {
+				if !got[i].Equal(want[i]) {
+					t.Fatalf("n=%d, parallelism=%d: value %d differs", n, parallelism, i)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelInterpolateMatchesSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(1167))
+
+	for _, n := range []int{1, 2, 5, 33} {
+		points := randomInterpolationPoints(rng, n)
+		want := Interpolate(points)
+		for _, parallelism := range testParallelisms {
+			got := ParallelInterpolate(points, parallelism)
+			if !got.Equal(want) {
+				t.Fatalf("n=%d, parallelism=%d: result differs from Interpolate", n, parallelism)
+			}
+		}
+
+		// And the result actually interpolates
+		for _, point := range points {
+			if !want.Evaluate(point[0]).Equal(point[1]) {
+				t.Fatalf("n=%d: interpolant misses a point", n)
+			}
+		}
+	}
+}
+
+func TestParallelInterpolatePanics(t *testing.T) {
+	tests := map[string][][2]field.Element{
+		"empty":     nil,
+		"duplicate": {{field.One, field.One}, {field.One, field.Zero}},
+	}
+	for name, points := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			ParallelInterpolate(points, 4)
+		})
+	}
+}
+
+func TestParallelNoGoroutineLeak(t *testing.T) {
+	rng := rand.New(rand.NewSource(1167))
+	p := New(randomFieldElements(rng, 20))
+	points := randomFieldElements(rng, 500)
+	interpolationPoints := randomInterpolationPoints(rng, 20)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		p.ParallelBatchEvaluate(points, 8)
+		ParallelInterpolate(interpolationPoints, 8)
+	}
+
+	// Goroutines have returned, but may not yet have been reaped
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines before, %d after", before, after)
+	}
+}
+
+func BenchmarkParallelBatchEvaluate(b *testing.B) {
+	rng := rand.New(rand.NewSource(1167))
+	p := New(randomFieldElements(rng, 1<<10))
+	points := randomFieldElements(rng, 1<<16)
+
+	for _, parallelism := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = p.ParallelBatchEvaluate(points, parallelism)
+			}
+		})
+	}
+}
+
+func BenchmarkParallelInterpolate(b *testing.B) {
+	rng := rand.New(rand.NewSource(1167))
+	points := randomInterpolationPoints(rng, 256)
+
+	for _, parallelism := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = ParallelInterpolate(points, parallelism)
+			}
+		})
+	}
+}
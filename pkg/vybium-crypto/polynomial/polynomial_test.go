@@ -1,6 +1,7 @@
 package polynomial
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
@@ -82,6 +83,16 @@ func TestXToThe(t *testing.T) {
 	}
 }
 
+func TestXToTheChecked(t *testing.T) {
+	p, err := XToTheChecked(3)
+	if err != nil || !p.Equal(XToThe(3)) {
+		t.Errorf("XToTheChecked(3) = %v, %v", p, err)
+	}
+	if _, err := XToTheChecked(-1); err == nil {
+		t.Error("XToTheChecked(-1) returned no error")
+	}
+}
+
 func TestPolynomialAddition(t *testing.T) {
 	// (1 + 2x) + (3 + 4x) = 4 + 6x
 	p1 := New([]field.Element{field.New(1), field.New(2)})
@@ -189,6 +200,17 @@ func TestPolynomialMonic(t *testing.T) {
 	}
 }
 
+func TestPolynomialMonicChecked(t *testing.T) {
+	p := New([]field.Element{field.New(2), field.New(4), field.New(6)})
+	monic, err := p.MonicChecked()
+	if err != nil || !monic.Equal(p.Monic()) {
+		t.Errorf("MonicChecked = %v, %v", monic, err)
+	}
+	if _, err := Zero().MonicChecked(); err == nil {
+		t.Error("MonicChecked on zero returned no error")
+	}
+}
+
 func TestInterpolation(t *testing.T) {
 	// Interpolate through (0,1), (1,3), (2,7)
 	// Unique polynomial of degree ≤ 2: 1 + x + x^2
@@ -211,6 +233,32 @@ func TestInterpolation(t *testing.T) {
 	}
 }
 
+func TestInterpolateChecked(t *testing.T) {
+	points := [][2]field.Element{
+		{field.New(0), field.New(1)},
+		{field.New(1), field.New(3)},
+		{field.New(2), field.New(7)},
+	}
+	p, err := InterpolateChecked(points)
+	if err != nil || !p.Equal(Interpolate(points)) {
+		t.Errorf("InterpolateChecked = %v, %v", p, err)
+	}
+
+	invalid := map[string][][2]field.Element{
+		"empty": nil,
+		"duplicate x": {
+			{field.New(5), field.New(1)},
+			{field.New(6), field.New(2)},
+			{field.New(5), field.New(3)},
+		},
+	}
+	for name, points := range invalid {
+		if _, err := InterpolateChecked(points); err == nil {
+			t.Errorf("%s: InterpolateChecked returned no error", name)
+		}
+	}
+}
+
 func TestZerofier(t *testing.T) {
 	// Zerofier of {1, 2, 3} should be (x-1)(x-2)(x-3)
 	points := []field.Element{field.New(1), field.New(2), field.New(3)}
@@ -264,6 +312,287 @@ func TestPolynomialDivisionWithRemainder(t *testing.T) {
 	}
 }
 
+func TestDivideLowerDegreeByHigher(t *testing.T) {
+	dividend := New([]field.Element{field.New(3), field.New(1)})
+	divisor := New([]field.Element{field.New(1), field.New(0), field.New(2)})
+
+	quotient, remainder := dividend.Divide(divisor)
+	if !quotient.IsZero() {
+		t.Errorf("quotient %v, want zero", quotient)
+	}
+	if !remainder.Equal(dividend) {
+		t.Errorf("remainder %v, want the dividend %v", remainder, dividend)
+	}
+	remainder.coefficients[0] = field.New(7)
+	if dividend.Coefficient(0) != field.New(3) {
+		t.Error("remainder shares coefficients with the dividend")
+	}
+
+	quotient, remainder = Zero().Divide(divisor)
+	if !quotient.IsZero() || !remainder.IsZero() {
+		t.Errorf("0 / divisor = %v rem %v, want zero", quotient, remainder)
+	}
+}
+
+func TestDivideExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1265))
+	for trial := 0; trial < 20; trial++ {
+		wantQuotient := randomPolynomial(rng, 1+rng.Intn(30))
+		divisor := randomPolynomial(rng, 1+rng.Intn(30))
+		quotient, remainder := wantQuotient.Mul(divisor).Divide(divisor)
+		if !quotient.Equal(wantQuotient) || !remainder.IsZero() {
+			t.Fatalf("(q·d)/d = %v rem %v, want %v rem 0", quotient, remainder, wantQuotient)
+		}
+	}
+}
+
+func TestDivideRemainderDegree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1265))
+	for trial := 0; trial < 50; trial++ {
+		dividend := randomPolynomial(rng, rng.Intn(40))
+		divisor := randomPolynomial(rng, 1+rng.Intn(20))
+		quotient, remainder := dividend.Divide(divisor)
+		if remainder.Degree() >= divisor.Degree() {
+			t.Fatalf("remainder degree %d, divisor degree %d", remainder.Degree(), divisor.Degree())
+		}
+		if !quotient.Mul(divisor).Add(remainder).Equal(dividend) {
+			t.Fatal("quotient * divisor + remainder != dividend")
+		}
+	}
+
+	// A constant divisor leaves no remainder
+	_, remainder := randomPolynomial(rng, 10).Divide(New([]field.Element{field.New(5)}))
+	if !remainder.IsZero() {
+		t.Errorf("remainder %v after division by a constant", remainder)
+	}
+}
+
+func TestDivideByZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("division by the zero polynomial did not panic")
+		}
+	}()
+	One().Divide(Zero())
+}
+
+func TestDivideChecked(t *testing.T) {
+	p := New([]field.Element{field.New(1), field.New(2), field.New(3)})
+	d := New([]field.Element{field.New(5), field.New(1)})
+	quotient, remainder, err := p.DivideChecked(d)
+	if err != nil {
+		t.Fatalf("DivideChecked: %v", err)
+	}
+	if !quotient.Mul(d).Add(remainder).Equal(p) {
+		t.Error("quotient * divisor + remainder != p")
+	}
+
+	if _, _, err := p.DivideChecked(Zero()); err == nil {
+		t.Error("DivideChecked by zero returned no error")
+	}
+	// A divisor with only zero coefficients is the zero polynomial too
+	if _, _, err := p.DivideChecked(&Polynomial{coefficients: []field.Element{field.Zero, field.Zero}}); err == nil {
+		t.Error("DivideChecked by unnormalized zero returned no error")
+	}
+}
+
+func TestComposeEvaluationIdentity(t *testing.T) {
+	rng := rand.New(rand.NewSource(1268))
+	for _, sizes := range [][2]int{{2, 2}, {5, 3}, {4, 40}, {30, 10}} {
+		p := randomPolynomial(rng, sizes[0])
+		q := randomPolynomial(rng, sizes[1])
+		composed := p.Compose(q)
+		if composed.Degree() != p.Degree()*q.Degree() {
+			t.Errorf("degree %d, want %d·%d", composed.Degree(), p.Degree(), q.Degree())
+		}
+		for i := 0; i < 5; i++ {
+			x := field.New(rng.Uint64())
+			if got, want := composed.Evaluate(x), p.Evaluate(q.Evaluate(x)); got != want {
+				t.Errorf("p(q(%v)) = %v, want %v", x, got, want)
+			}
+		}
+	}
+}
+
+func TestComposeSpecialCases(t *testing.T) {
+	rng := rand.New(rand.NewSource(1268))
+	p := randomPolynomial(rng, 10)
+	if !p.Compose(X()).Equal(p) {
+		t.Error("p(x) differs from p")
+	}
+	if !X().Compose(p).Equal(p) {
+		t.Error("x composed with p differs from p")
+	}
+
+	constant := New([]field.Element{field.New(1268)})
+	if got := p.Compose(constant); !got.Equal(New([]field.Element{p.Evaluate(field.New(1268))})) {
+		t.Errorf("p(1268) = %v", got)
+	}
+	if got := p.Compose(Zero()); !got.Equal(New([]field.Element{p.Coefficient(0)})) {
+		t.Errorf("p(0) = %v", got)
+	}
+
+	zero := Zero()
+	for name, outer := range map[string]*Polynomial{"zero": zero, "constant": constant} {
+		if got := outer.Compose(p); got != outer {
+			t.Errorf("%s composed with p is %v, want the %s itself", name, got, name)
+		}
+		if allocs := testing.AllocsPerRun(10, func() { outer.Compose(p) }); allocs != 0 {
+			t.Errorf("composing the %s allocates %v times", name, allocs)
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	rng := rand.New(rand.NewSource(1270))
+	for _, numCoeffs := range []int{1, 2, 5, 60} {
+		p := randomPolynomial(rng, numCoeffs)
+		if !p.Pow(0).Equal(One()) {
+			t.Errorf("p^0 = %v, want 1", p.Pow(0))
+		}
+		if !p.Pow(1).Equal(p) {
+			t.Errorf("p^1 = %v, want %v", p.Pow(1), p)
+		}
+		if got, want := p.Pow(3), p.Mul(p).Mul(p); !got.Equal(want) {
+			t.Errorf("p^3 = %v, want %v", got, want)
+		}
+
+		want := One()
+		for exponent := uint64(1); exponent <= 9; exponent++ {
+			want = want.Mul(p)
+			got := p.Pow(exponent)
+			if !got.Equal(want) {
+				t.Errorf("degree %d: p^%d differs from repeated multiplication", p.Degree(), exponent)
+			}
+			if got.Degree() != p.Degree()*int(exponent) {
+				t.Errorf("p^%d has degree %d, want %d", exponent, got.Degree(), p.Degree()*int(exponent))
+			}
+		}
+	}
+}
+
+func TestPowConstants(t *testing.T) {
+	if !Zero().Pow(0).Equal(One()) {
+		t.Error("0^0 is not 1")
+	}
+	if !Zero().Pow(5).IsZero() {
+		t.Error("0^5 is not 0")
+	}
+	three := New([]field.Element{field.New(3)})
+	if got := three.Pow(1 << 40); !got.Equal(New([]field.Element{field.New(3).ModPow(1 << 40)})) {
+		t.Errorf("3^(2^40) = %v", got)
+	}
+	x := X()
+	if got, want := x.Pow(70).Coefficients(), 71; len(got) != want || !got[70].IsOne() {
+		t.Errorf("x^70 = %v", x.Pow(70))
+	}
+}
+
+func TestRootsIn(t *testing.T) {
+	roots := []field.Element{field.New(7), field.New(3), field.New(1 << 40)}
+	z := Zerofier(roots).ScalarMul(field.New(9))
+	candidates := []field.Element{field.New(1), field.New(1 << 40), field.New(2), field.New(7), field.New(3), field.New(8)}
+	got := z.RootsIn(candidates)
+	want := []field.Element{field.New(1 << 40), field.New(7), field.New(3)}
+	if len(got) != len(want) {
+		t.Fatalf("RootsIn = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("RootsIn[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := One().RootsIn(candidates); len(got) != 0 {
+		t.Errorf("roots of 1 = %v, want none", got)
+	}
+	if got := Zero().RootsIn(candidates); len(got) != len(candidates) {
+		t.Errorf("zero polynomial has %d roots among %d candidates", len(got), len(candidates))
+	}
+}
+
+func TestQuadraticRoots(t *testing.T) {
+	rng := rand.New(rand.NewSource(1269))
+	for trial := 0; trial < 50; trial++ {
+		r1, r2 := field.New(rng.Uint64()), field.New(rng.Uint64())
+		if r2.Less(r1) {
+			r1, r2 = r2, r1
+		}
+		z := Zerofier([]field.Element{r1, r2}).ScalarMul(field.New(1 + rng.Uint64()%1000))
+		got, ok := z.QuadraticRoots()
+		if !ok || len(got) != 2 || !got[0].Equal(r1) || !got[1].Equal(r2) {
+			t.Fatalf("QuadraticRoots of zerofier of %v, %v = %v, %v", r1, r2, got, ok)
+		}
+	}
+
+	// A double root is reported once.
+	r := field.New(12345)
+	got, ok := Zerofier([]field.Element{r, r}).QuadraticRoots()
+	if !ok || len(got) != 1 || !got[0].Equal(r) {
+		t.Errorf("QuadraticRoots of (x-r)^2 = %v, %v, want [%v]", got, ok, r)
+	}
+
+	// x^2 - n has no roots for a non-residue n.
+	nonResidue := field.Generator()
+	irreducible := New([]field.Element{nonResidue.Neg(), field.Zero, field.One})
+	if got, ok := irreducible.QuadraticRoots(); ok || got != nil {
+		t.Errorf("QuadraticRoots of x^2 - g = %v, %v, want nil, false", got, ok)
+	}
+
+	got, ok = Zerofier([]field.Element{r}).ScalarMul(field.New(3)).QuadraticRoots()
+	if !ok || len(got) != 1 || !got[0].Equal(r) {
+		t.Errorf("QuadraticRoots of 3(x-r) = %v, %v, want [%v]", got, ok, r)
+	}
+	if got, ok := One().QuadraticRoots(); !ok || len(got) != 0 {
+		t.Errorf("QuadraticRoots of 1 = %v, %v, want none", got, ok)
+	}
+	if _, ok := Zero().QuadraticRoots(); ok {
+		t.Error("QuadraticRoots of the zero polynomial should not succeed")
+	}
+	if _, ok := Zerofier([]field.Element{field.One, field.New(2), r}).QuadraticRoots(); ok {
+		t.Error("QuadraticRoots of a cubic should not succeed")
+	}
+}
+
+func TestGCD(t *testing.T) {
+	one, two, three := field.New(1), field.New(2), field.New(3)
+	a := Zerofier([]field.Element{one, two}).ScalarMul(field.New(5))
+	b := Zerofier([]field.Element{two, three})
+	if got, want := GCD(a, b), Zerofier([]field.Element{two}); !got.Equal(want) {
+		t.Errorf("gcd((x-1)(x-2), (x-2)(x-3)) = %v, want %v", got, want)
+	}
+	if got := GCD(Zerofier([]field.Element{one}), Zerofier([]field.Element{two})); !got.Equal(One()) {
+		t.Errorf("gcd of coprime polynomials = %v, want 1", got)
+	}
+	if got := GCD(a, Zero()); !got.Equal(a.Monic()) {
+		t.Errorf("gcd(p, 0) = %v, want %v", got, a.Monic())
+	}
+	if got := GCD(Zero(), a); !got.Equal(a.Monic()) {
+		t.Errorf("gcd(0, p) = %v, want %v", got, a.Monic())
+	}
+	if got := GCD(Zero(), Zero()); !got.IsZero() {
+		t.Errorf("gcd(0, 0) = %v, want 0", got)
+	}
+
+	rng := rand.New(rand.NewSource(1271))
+	for trial := 0; trial < 20; trial++ {
+		common := randomPolynomial(rng, 1+rng.Intn(6))
+		x := randomPolynomial(rng, 1+rng.Intn(8)).Mul(common)
+		y := randomPolynomial(rng, 1+rng.Intn(8)).Mul(common)
+		want, _, _ := XGCD(x, y)
+		got := GCD(x, y)
+		if !got.Equal(want) {
+			t.Errorf("GCD(%v, %v) = %v, XGCD gives %v", x, y, got, want)
+		}
+		if !x.Mod(got).IsZero() || !y.Mod(got).IsZero() || !x.Mod(common).IsZero() {
+			t.Errorf("gcd %v does not divide %v and %v", got, x, y)
+		}
+		if !got.Mod(common.Monic()).IsZero() {
+			t.Errorf("gcd %v is not a multiple of the common factor %v", got, common)
+		}
+	}
+}
+
 func TestPolynomialNormalization(t *testing.T) {
 	// Polynomial with trailing zeros should be normalized
 	coeffs := []field.Element{
@@ -289,6 +618,46 @@ func TestPolynomialClone(t *testing.T) {
 	}
 }
 
+func TestCoefficientsReturnsCopy(t *testing.T) {
+	// Trailing zeros leave spare capacity in the backing array.
+	p := New([]field.Element{field.New(3), field.New(1), field.New(2), field.Zero})
+	want := p.Clone()
+	encoding := p.Encode()
+
+	coeffs := p.Coefficients()
+	coeffs[0] = field.New(999)
+	for i := range coeffs {
+		// Normalize in place, as a caller making the polynomial monic would.
+		coeffs[i] = coeffs[i].Mul(field.New(2).Inverse())
+	}
+	_ = append(coeffs, field.New(5))
+
+	if !p.Equal(want) {
+		t.Fatal("mutating Coefficients() changed the polynomial")
+	}
+	if !p.Evaluate(field.New(10)).Equal(want.Evaluate(field.New(10))) {
+		t.Error("mutating Coefficients() changed later evaluations")
+	}
+	if got := p.Encode(); len(got) != len(encoding) || !got[len(got)-1].Equal(encoding[len(encoding)-1]) {
+		t.Error("mutating Coefficients() changed the encoding")
+	}
+	if !p.Mul(One()).Equal(want) {
+		t.Error("mutating Coefficients() changed later products")
+	}
+}
+
+func TestCoefficient(t *testing.T) {
+	p := New([]field.Element{field.New(3), field.New(1), field.New(2)})
+	for i, want := range []uint64{3, 1, 2, 0, 0} {
+		if got := p.Coefficient(i).Value(); got != want {
+			t.Errorf("Coefficient(%d) = %d, want %d", i, got, want)
+		}
+	}
+	if !Zero().Coefficient(0).IsZero() {
+		t.Error("zero polynomial has a nonzero constant term")
+	}
+}
+
 func TestPolynomialEqual(t *testing.T) {
 	p1 := New([]field.Element{field.New(1), field.New(2)})
 	p2 := New([]field.Element{field.New(1), field.New(2)})
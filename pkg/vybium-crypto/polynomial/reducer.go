@@ -0,0 +1,76 @@
+package polynomial
+
+import (
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Reducer reduces polynomials modulo a fixed modulus. It precomputes, by
+// Newton iteration, the inverse of the reversed modulus that fast division
+// needs, so repeated reductions by the same modulus, as in quotient ring or
+// extension field arithmetic, pay for it once.
+//
+// A Reducer is immutable and safe for concurrent use.
+type Reducer struct {
+	modulus *Polynomial
+	// reversedInverse is rev(modulus)^-1 mod x^deg(modulus), or nil for a
+	// constant modulus.
+	reversedInverse *Polynomial
+}
+
+// NewReducer returns a Reducer for the modulus.
+//
+// Panics if modulus is zero.
+func NewReducer(modulus *Polynomial) *Reducer {
+	if modulus.IsZero() {
+		panic("reduction modulo the zero polynomial")
+	}
+	r := &Reducer{modulus: New(modulus.coefficients)}
+	if m := r.modulus.Degree(); m > 0 {
+		r.reversedInverse = inverseModXToThe(reversed(r.modulus.coefficients, m), m)
+	}
+	return r
+}
+
+// Modulus returns the reducer's modulus.
+func (r *Reducer) Modulus() *Polynomial {
+	return r.modulus.Clone()
+}
+
+// Reduce returns p mod the modulus, equal to p.Mod(modulus).
+//
+// With m the modulus' degree, p is split into blocks of m coefficients and
+// folded from the top as remainder = (remainder·x^m + block) mod modulus.
+// Each step divides a polynomial of degree below 2m, whose quotient has at
+// most m coefficients, which the precomputed inverse covers.
+func (r *Reducer) Reduce(p *Polynomial) *Polynomial {
+	m := r.modulus.Degree()
+	degP := p.Degree()
+	if degP < m {
+		return p.Clone()
+	}
+	if m == 0 {
+		return Zero()
+	}
+
+	coeffs := p.coefficients[:degP+1]
+	top := (len(coeffs) - 1) / m * m
+	remainder := New(coeffs[top:])
+	step := make([]field.Element, 2*m)
+	for start := top - m; start >= 0; start -= m {
+		clear(step)
+		copy(step, coeffs[start:start+m])
+		copy(step[m:], remainder.coefficients)
+		remainder = r.reduceBelowTwiceDegree(New(step))
+	}
+	return remainder
+}
+
+// reduceBelowTwiceDegree returns f mod the modulus for f of degree below
+// twice the modulus' degree.
+func (r *Reducer) reduceBelowTwiceDegree(f *Polynomial) *Polynomial {
+	if f.Degree() < r.modulus.Degree() {
+		return f
+	}
+	quotient := newtonQuotient(f, r.modulus, r.reversedInverse)
+	return f.Sub(quotient.MulNTT(r.modulus))
+}
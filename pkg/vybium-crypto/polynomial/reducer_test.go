@@ -0,0 +1,94 @@
+package polynomial
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestModMatchesDivide(t *testing.T) {
+	rng := rand.New(rand.NewSource(1267))
+	// The larger sizes take DivideNTT's Newton path
+	for _, sizes := range [][2]int{{0, 1}, {5, 1}, {3, 8}, {40, 9}, {900, 300}, {1500, 400}} {
+		p := randomPolynomial(rng, sizes[0])
+		modulus := randomPolynomial(rng, sizes[1])
+		_, want := p.Divide(modulus)
+		if got := p.Mod(modulus); !got.Equal(want) {
+			t.Errorf("degree %d mod degree %d: Mod differs from Divide", p.Degree(), modulus.Degree())
+		}
+	}
+}
+
+func TestReducerMatchesMod(t *testing.T) {
+	rng := rand.New(rand.NewSource(1267))
+	for _, numModulusCoeffs := range []int{1, 2, 3, 4, 17, 300} {
+		modulus := randomPolynomial(rng, numModulusCoeffs)
+		reducer := NewReducer(modulus)
+		for trial := 0; trial < 20; trial++ {
+			p := randomPolynomial(rng, rng.Intn(5*numModulusCoeffs+10))
+			if got, want := reducer.Reduce(p), p.Mod(modulus); !got.Equal(want) {
+				t.Fatalf("degree %d mod degree %d: Reduce %v, Mod %v", p.Degree(), modulus.Degree(), got, want)
+			}
+		}
+	}
+}
+
+func TestReducerEdgeCases(t *testing.T) {
+	// x^3 - 2 over the field, as used for an extension field
+	modulus := New([]field.Element{field.New(2).Neg(), field.Zero, field.Zero, field.One})
+	reducer := NewReducer(modulus)
+
+	if got := reducer.Reduce(XToThe(3)); !got.Equal(New([]field.Element{field.New(2)})) {
+		t.Errorf("x^3 mod (x^3 - 2) = %v, want 2", got)
+	}
+	if got := reducer.Reduce(modulus.Mul(X())); !got.IsZero() {
+		t.Errorf("multiple of the modulus reduced to %v", got)
+	}
+	if got := reducer.Reduce(Zero()); !got.IsZero() {
+		t.Errorf("zero reduced to %v", got)
+	}
+
+	p := X()
+	reduced := reducer.Reduce(p)
+	reduced.coefficients[1] = field.New(9)
+	if !p.IsX() {
+		t.Error("Reduce returned the input polynomial")
+	}
+
+	if !reducer.Modulus().Equal(modulus) {
+		t.Error("Modulus differs from the reducer's modulus")
+	}
+	if got := NewReducer(New([]field.Element{field.New(7)})).Reduce(X()); !got.IsZero() {
+		t.Errorf("reduction by a constant gave %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewReducer accepted the zero polynomial")
+		}
+	}()
+	NewReducer(Zero())
+}
+
+func BenchmarkReducer(b *testing.B) {
+	rng := rand.New(rand.NewSource(1267))
+	modulus := randomPolynomial(rng, 513)
+	p := randomPolynomial(rng, 1024)
+	reducer := NewReducer(modulus)
+	b.Run("Reduce", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reducer.Reduce(p)
+		}
+	})
+	b.Run("Mod", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.Mod(modulus)
+		}
+	})
+	b.Run("Divide", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.Divide(modulus)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+// Package vybiumcrypto is the top-level entry point of the Vybium crypto
+// library. The primitives themselves live in the subpackages; this package
+// holds a small, stable facade over the most common of them, hashing and
+// Merkle membership proofs, and checks that span several of them.
+package vybiumcrypto
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/merkle"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// SelfTest runs a fixed set of known-answer checks against the field,
+// extension field, Tip5, Merkle tree, MMR and codec implementations and
+// returns an error naming the first check whose output differs from the
+// embedded expected value. It takes a few milliseconds, touches no files and
+// shares no mutable state, so it may be called concurrently and repeatedly,
+// typically once at process start to catch miscompiled or misbehaving
+// platform arithmetic.
+//
+// The hash-based expected values are those of the full Tip5 permutation;
+// binaries built with the tip5weak tag always fail the self-test.
+func SelfTest() error {
+	if hash.WeakHashingEnabled {
+		return fmt.Errorf("self-test: built with reduced-round Tip5 (tip5weak)")
+	}
+	return selfTest(knownAnswers)
+}
+
+// knownAnswer is a single self-test check: run maps the input to an output
+// that must equal want. Inputs and outputs are canonical field element
+// values.
+type knownAnswer struct {
+	name  string
+	input []uint64
+	want  []uint64
+	run   func(input []field.Element) ([]field.Element, error)
+}
+
+func selfTest(checks []knownAnswer) error {
+	for _, check := range checks {
+		if err := check.verify(); err != nil {
+			return fmt.Errorf("self-test %q failed: %w", check.name, err)
+		}
+	}
+	return nil
+}
+
+func (check knownAnswer) verify() error {
+	input := make([]field.Element, len(check.input))
+	for i, v := range check.input {
+		input[i] = field.New(v)
+	}
+	got, err := check.run(input)
+	if err != nil {
+		return err
+	}
+	if len(got) != len(check.want) {
+		return fmt.Errorf("got %d output elements, expected %d", len(got), len(check.want))
+	}
+	for i := range got {
+		if got[i].Value() != check.want[i] {
+			return fmt.Errorf("output element %d is %#016x, expected %#016x", i, got[i].Value(), check.want[i])
+		}
+	}
+	return nil
+}
+
+// knownAnswers are the self-test vectors. The hash, Merkle and MMR inputs
+// are arbitrary fixed field elements; the field operands sit next to the
+// reduction boundaries, where a broken 128-bit multiply or carry chain
+// shows first.
+var knownAnswers = []knownAnswer{
+	{
+		name: "field arithmetic",
+		input: []uint64{
+			0xFFFFFFFF00000000, 0xFFFFFFFF00000000,
+			0x0000000100000000, 0x0000000100000000,
+			0xFFFFFFFEFFFFFFFF, 0xFFFFFFFF00000000,
+			0x00000000FFFFFFFF, 0x0000000100000001,
+			0x8000000000000000, 0x7FFFFFFF80000001,
+			0xFFFFFFFF00000000, 0x0000000000000002,
+		},
+		want: []uint64{
+			0xfffffffeffffffff, 0x0000000000000000, 0x0000000000000001,
+			0x0000000200000000, 0x0000000000000000, 0x00000000ffffffff,
+			0xfffffffefffffffe, 0xffffffff00000000, 0x0000000000000002,
+			0x0000000200000000, 0xfffffffeffffffff, 0x00000000fffffffe,
+			0x0000000080000000, 0x000000007fffffff, 0x4000000000000000,
+			0x0000000000000001, 0xfffffffefffffffe, 0xfffffffeffffffff,
+		},
+		run: fieldArithmetic,
+	},
+	{
+		name: "tip5 hash_varlen",
+		input: []uint64{
+			0xb1d5672bd124f3c5, 0xe76b6c54c6bbc974, 0x20e65b4e75ea7981,
+			0xb4db27c06046e731, 0x24a321ea8769b2dc, 0x82b247abf62c8bdf,
+			0x54e1d138db030bdd,
+		},
+		want: []uint64{
+			0xd35c1b09b9442c3f, 0xcf81c4b3791a48ce, 0xdd54de87ee8b4387,
+			0xf8d3a555837b8cd3, 0xd0d22a07d6aaf881,
+		},
+		run: tip5HashVarlen,
+	},
+	{
+		name: "tip5 hash_pair",
+		input: []uint64{
+			0x1b674e4cfec46392, 0x6136fb656388d033, 0xecace6735e0d2b08,
+			0x485b02cbe7905bc1, 0xfe05929846a1f4d4, 0x20c24b2257668e36,
+			0xc298eb5841577d9a, 0x68fc53031ef53ffc, 0x056e802760ac47cd,
+			0xe7d9e9848138b2ce,
+		},
+		want: []uint64{
+			0x4c015d02d622db62, 0xaa40ff667580d841, 0x33b8327f77a795ad,
+			0x2fc90713d2e3083d, 0xc527027a79932dc1,
+		},
+		run: tip5HashPair,
+	},
+	{
+		name: "merkle root of 8 leafs",
+		input: []uint64{
+			0x00944cfa927ea473, 0x84f678f5278cd3a8, 0xd919242451c80010,
+			0x1a6418ff9ffceca9, 0x25f3289775c0fa5f, 0xe4a20429648d2b8f,
+			0x48deed8f8ede93fd, 0x67d5ad92277874b2, 0x20a8dfefa719959c,
+			0x3ecde5dea977da6b, 0x5595597745e8ed0f, 0x2ba611eed135d4a6,
+			0x9eef36f2bfbb1839, 0x9b20759e4446aad6, 0xebbef6a716f21bb4,
+			0x01c2b722623e3e27, 0xcd52278505db29f6, 0xb43fb98dad0ab306,
+			0x2bda12ae6f62317a, 0x5bb2e2361bcb7c35, 0x173ef3d7be9c5635,
+			0x692b786584de496d, 0xa93179b2ebb07d5d, 0x6ecccbd03552ad26,
+			0xfe3ac3a41aaba5f9, 0x748b26164216f884, 0x2b3a8cc312ba16ad,
+			0x0257ee5c7364c060, 0xd78d4e0b4defb5da, 0x2ea4174d44aae41e,
+			0xee93b145e87d47dc, 0x4f5f23b64655bf89, 0x4489349820275a5c,
+			0xc8f3c88e0b33a976, 0xc9acbce467bd20b6, 0x0d5b2e5becb1c626,
+			0xb463918530408788, 0x92863e7e4afbd858, 0x596ecbd2ebdd0f89,
+			0x81118fdf88db6076,
+		},
+		want: []uint64{
+			0x4dddcf7573a41f64, 0x2b824ebee66edaf9, 0x49c75f0e10607474,
+			0x536c811a7e3997aa, 0xff024454e65d2792,
+		},
+		run: merkleRoot,
+	},
+	{
+		name: "mmr bagged peaks of 13 leafs",
+		input: []uint64{
+			0x02fdc6e0e9c8ff1c, 0x25124c476a4bc56c, 0x051fdfc0cca77b74,
+			0x0322e928e31ffde7, 0x560a338fe7926332, 0x96f1c8374d635c03,
+			0x7e41fca004d1c86e, 0x617318a35e0e138a, 0x09cdb23b20f60216,
+			0xe239bd2ef8fd5213, 0xf1f1eaf6fb424946, 0x82e72651bf078fdc,
+			0xebd56aef0af2f221, 0x81e888a7bf5a60c1, 0x0541310c86eb9eff,
+			0x4324207a4cb2807e, 0xebf8cbb97ccafba7, 0x8ba849193b0dd23a,
+			0x588e0d28013ccf4a, 0x4e35beda32843c76, 0x22013f2dfb7c1f03,
+			0x8dea3f37f325609c, 0x536b762c8b6d53d3, 0x4cf55bbbe91534c9,
+			0x548d84d3bc902fa6, 0xeb725525783624a4, 0x0a1942d4b0f57bf0,
+			0xb4d9ebf1daee4641, 0x1cdb4c0800a02210, 0x99b4985415536201,
+			0xf423dc07c985ad98, 0xdebde048021ecf35, 0x661dd05dd3b65ec0,
+			0x85cdba3c7ca2b4e8, 0xc339589c9ba69633, 0x25a832e57df5d078,
+			0x7d1f5b151d882e14, 0x575e3494a647fdf5, 0x617e0ba79541482f,
+			0x5f0722cf414b99c9, 0x21c032befe740993, 0xb94cf8e531d9f6f7,
+			0x2ebbcc1a2e135fa4, 0x1d609dec7175282b, 0x669224706cda5d32,
+			0xa7f5cea2171f27a8, 0x33bd9c5281d1c419, 0x71c43a36627ed1d6,
+			0xb46a4bfdc8aa5bfe, 0x7e0612534fbf83a1, 0x22615eeaef0c83d2,
+			0xa926b6a8d93d6a0b, 0x23d911c7a2a86ada, 0x7fde7d007410f1e0,
+			0xbbd79d57f87a379a, 0xa06248ff8a82c4e0, 0x1c47e713522c17b5,
+			0x4fa0bd2e1b7f477e, 0x7ca33b6753427ec1, 0xaa0563988a562862,
+			0x0fdf2ddeba90e9d3, 0x70b70d09bcc700d3, 0x0a2010eb82c5250f,
+			0xf1eb080bd5291187, 0x4badd55624bf05d6,
+		},
+		want: []uint64{
+			0x7ab23f9e5038e473, 0xc54a64e7fba547a7, 0xb294e850306f3e01,
+			0xb23ae2c97de4d5c8, 0x7fd10f996ae364bd,
+		},
+		run: mmrBaggedPeaks,
+	},
+	{
+		name:  "xfield inverse",
+		input: []uint64{0xFFFFFFFF00000000, 0x0000000100000000, 0x00000000FFFFFFFF},
+		want:  []uint64{0xaf286bc886bca1b0, 0x5e50d7940d79435f, 0x1af286bd286bca1b},
+		run:   xfieldInverse,
+	},
+	{
+		name:  "codec round-trip",
+		input: []uint64{0xFFFFFFFF00000000, 0x00000000FFFFFFFF, 0x0000000000000001, 0xFFFFFFFEFFFFFFFF},
+		want:  []uint64{0x0000000000000000, 0x00000000ffffffff, 0x00000000ffffffff, 0x0000000000000001, 0xfffffffeffffffff},
+		run:   codecRoundTrip,
+	},
+}
+
+// fieldArithmetic returns the sum, difference and product of each operand
+// pair and checks that every nonzero operand times its inverse is one.
+func fieldArithmetic(input []field.Element) ([]field.Element, error) {
+	if len(input)%2 != 0 {
+		return nil, fmt.Errorf("got %d operands, expected pairs", len(input))
+	}
+	out := make([]field.Element, 0, 3*len(input)/2)
+	for i := 0; i < len(input); i += 2 {
+		a, b := input[i], input[i+1]
+		out = append(out, a.Add(b), a.Sub(b), a.Mul(b))
+	}
+	for i, a := range input {
+		if a.IsZero() {
+			continue
+		}
+		if !a.Mul(a.Inverse()).IsOne() {
+			return nil, fmt.Errorf("operand %d times its inverse is not one", i)
+		}
+	}
+	return out, nil
+}
+
+func tip5HashVarlen(input []field.Element) ([]field.Element, error) {
+	digest := hash.HashVarlen(input)
+	return digest[:], nil
+}
+
+func tip5HashPair(input []field.Element) ([]field.Element, error) {
+	pair, err := hash.ElementsToDigests(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(pair) != 2 {
+		return nil, fmt.Errorf("got %d digests, expected 2", len(pair))
+	}
+	digest := hash.HashPair(pair[0], pair[1])
+	return digest[:], nil
+}
+
+func merkleRoot(input []field.Element) ([]field.Element, error) {
+	leafs, err := hash.ElementsToDigests(input)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := merkle.New(leafs)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.Root()
+	return root[:], nil
+}
+
+func mmrBaggedPeaks(input []field.Element) ([]field.Element, error) {
+	leafs, err := hash.ElementsToDigests(input)
+	if err != nil {
+		return nil, err
+	}
+	bagged := merkle.NewMmrAccumulatorFromLeafs(leafs).BagPeaks()
+	return bagged[:], nil
+}
+
+// xfieldInverse returns the inverse of the input and checks that it is both
+// a right and a left inverse.
+func xfieldInverse(input []field.Element) ([]field.Element, error) {
+	element, err := xfield.FromBFieldSlice(input)
+	if err != nil {
+		return nil, err
+	}
+	x := *element
+	inverse, err := x.InverseChecked()
+	if err != nil {
+		return nil, err
+	}
+	if !x.Mul(inverse).IsOne() || !inverse.Mul(x).IsOne() {
+		return nil, fmt.Errorf("%v times its inverse %v is not one", x, inverse)
+	}
+	return bfieldcodec.EncodeXFieldElement(inverse), nil
+}
+
+// codecRoundTrip encodes the first input element as a uint64 and the
+// remaining three as an extension field element, decodes the encoding and
+// checks that it yields the input.
+func codecRoundTrip(input []field.Element) ([]field.Element, error) {
+	if len(input) != 1+xfield.ExtensionDegree {
+		return nil, fmt.Errorf("got %d input elements, expected %d", len(input), 1+xfield.ExtensionDegree)
+	}
+	value := input[0].Value()
+	x := xfield.New([xfield.ExtensionDegree]field.Element{input[1], input[2], input[3]})
+	encoded := append(bfieldcodec.EncodeUint64(value), bfieldcodec.EncodeXFieldElement(x)...)
+
+	decodedValue, err := bfieldcodec.DecodeUint64(encoded[:2])
+	if err != nil {
+		return nil, err
+	}
+	decodedX, err := bfieldcodec.DecodeXFieldElement(encoded[2:])
+	if err != nil {
+		return nil, err
+	}
+	if decodedValue != value || !decodedX.Equal(x) {
+		return nil, fmt.Errorf("decoded (%d, %v), expected (%d, %v)", decodedValue, decodedX, value, x)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,100 @@
+package vybiumcrypto
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func TestSelfTest(t *testing.T) {
+	err := SelfTest()
+	if hash.WeakHashingEnabled {
+		if err == nil {
+			t.Fatal("SelfTest passed with reduced-round Tip5")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelfTestConcurrent(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("known answers require full-round Tip5")
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 4; j++ {
+				if err := SelfTest(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestSelfTestDetectsTampering flips the low bit of every embedded input and
+// expected value in turn and checks that the self-test then fails, naming
+// the tampered check.
+func TestSelfTestDetectsTampering(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("known answers require full-round Tip5")
+	}
+	tampered := func(checkIndex int, tamper func(*knownAnswer)) []knownAnswer {
+		checks := make([]knownAnswer, len(knownAnswers))
+		copy(checks, knownAnswers)
+		check := checks[checkIndex]
+		check.input = append([]uint64(nil), check.input...)
+		check.want = append([]uint64(nil), check.want...)
+		tamper(&check)
+		checks[checkIndex] = check
+		return checks
+	}
+	expectFailure := func(t *testing.T, checks []knownAnswer, name string) {
+		t.Helper()
+		err := selfTest(checks)
+		if err == nil {
+			t.Fatal("self-test passed")
+		}
+		if !strings.Contains(err.Error(), `"`+name+`"`) {
+			t.Fatalf("error %q does not name check %q", err, name)
+		}
+	}
+
+	for c, check := range knownAnswers {
+		for i := range check.input {
+			checks := tampered(c, func(k *knownAnswer) { k.input[i] ^= 1 })
+			expectFailure(t, checks, check.name)
+		}
+		for i := range check.want {
+			checks := tampered(c, func(k *knownAnswer) { k.want[i] ^= 1 })
+			expectFailure(t, checks, check.name)
+		}
+		checks := tampered(c, func(k *knownAnswer) { k.want = k.want[:len(k.want)-1] })
+		expectFailure(t, checks, check.name)
+	}
+
+	// The shared vectors are untouched.
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkSelfTest(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = SelfTest()
+	}
+}
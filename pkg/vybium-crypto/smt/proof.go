@@ -0,0 +1,208 @@
+package smt
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// bitmapWords is the number of 64-bit words needed for one bit per level.
+const bitmapWords = (Depth + 63) / 64
+
+// Proof is a compact inclusion or absence proof for one key.
+//
+// The path from the leaf to the root is determined by the key itself (see
+// KeyPath), so the proof only carries the siblings along that path. Siblings
+// equal to the default digest of their height are omitted; Bitmap has bit h
+// set if the sibling at height h is present in Siblings.
+type Proof struct {
+	// Bitmap marks the heights with a non-default sibling. Bit h of the
+	// bitmap is bit h%64 of word h/64.
+	Bitmap [bitmapWords]uint64
+
+	// Siblings are the non-default siblings, ordered from the leaf upwards.
+	Siblings []hash.Digest
+}
+
+// hasSibling returns true if the sibling at the given height is non-default.
+func (p *Proof) hasSibling(height int) bool {
+	return p.Bitmap[height/64]>>(height%64)&1 == 1
+}
+
+// ProveInclusion returns a proof that key is present in the tree.
+// Returns an error if the key is absent.
+func (t *SparseMerkleTree) ProveInclusion(key hash.Digest) (*Proof, error) {
+	proof, found, err := t.prove(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("key %s is not in the tree", key)
+	}
+	return proof, nil
+}
+
+// ProveAbsence returns a proof that key is not present in the tree.
+// Returns an error if the key is present, or if a different key occupies its
+// leaf position, in which case absence cannot be shown with an empty leaf.
+func (t *SparseMerkleTree) ProveAbsence(key hash.Digest) (*Proof, error) {
+	proof, found, err := t.prove(key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return nil, fmt.Errorf("key %s is in the tree", key)
+	}
+	return proof, nil
+}
+
+// prove walks down to key's leaf position, collecting the non-default
+// siblings, and reports whether the key is present.
+func (t *SparseMerkleTree) prove(key hash.Digest) (*Proof, bool, error) {
+	path := KeyPath(key)
+	proof := &Proof{}
+	var siblings []hash.Digest // top-down; reversed at the end
+
+	addSibling := func(height int, digest hash.Digest) {
+		proof.Bitmap[height/64] |= 1 << (height % 64)
+		siblings = append(siblings, digest)
+	}
+
+	found := false
+	for n := t.root; n != nil; {
+		if d := firstDifference(n.path, path, Depth-n.height); d >= 0 {
+			// The key's subtree at the divergence is empty; n is its sibling
+			height := Depth - 1 - d
+			addSibling(height, climb(n.digest, n.height, height, n.path))
+			break
+		}
+		if n.height == 0 {
+			if n.key != key {
+				return nil, false, fmt.Errorf("leaf position of key %s is occupied by key %s", key, n.key)
+			}
+			found = true
+			break
+		}
+		side := path.bit(Depth - n.height)
+		addSibling(n.height-1, n.tops[1-side])
+		n = n.children[side]
+	}
+
+	proof.Siblings = make([]hash.Digest, len(siblings))
+	for i, sibling := range siblings {
+		proof.Siblings[len(siblings)-1-i] = sibling
+	}
+	return proof, found, nil
+}
+
+// VerifyProof checks a proof against a root. If value is nil, the proof must
+// show that key is absent; otherwise it must show that key maps to *value.
+func VerifyProof(root, key hash.Digest, value *hash.Digest, proof *Proof) bool {
+	if proof == nil || proof.validate() != nil {
+		return false
+	}
+
+	defaults := defaultDigests()
+	path := KeyPath(key)
+
+	current := defaults[0]
+	if value != nil {
+		current = leafDigest(key, *value)
+	}
+
+	next := 0
+	for h := 0; h < Depth; h++ {
+		sibling := defaults[h]
+		if proof.hasSibling(h) {
+			sibling = proof.Siblings[next]
+			next++
+		}
+		if path.bit(Depth-1-h) == 0 {
+			current = hash.HashPair(current, sibling)
+		} else {
+			current = hash.HashPair(sibling, current)
+		}
+	}
+
+	return current == root
+}
+
+// validate checks that the bitmap only refers to existing heights and
+// matches the number of siblings.
+func (p *Proof) validate() error {
+	if unused := Depth % 64; unused != 0 && p.Bitmap[bitmapWords-1]>>unused != 0 {
+		return fmt.Errorf("bitmap refers to heights beyond %d", Depth)
+	}
+	count := 0
+	for _, word := range p.Bitmap {
+		count += bits.OnesCount64(word)
+	}
+	if count != len(p.Siblings) {
+		return fmt.Errorf("bitmap marks %d siblings, proof has %d", count, len(p.Siblings))
+	}
+	return nil
+}
+
+// Encode returns the BFieldCodec encoding of the proof: the bitmap words as
+// uint64s, followed by the length-prefixed list of siblings.
+func (p *Proof) Encode() []field.Element {
+	encoding := make([]field.Element, 0, 2*bitmapWords+1+len(p.Siblings)*hash.DigestLen)
+	for _, word := range p.Bitmap {
+		encoding = append(encoding, bfieldcodec.EncodeUint64(word)...)
+	}
+	encoding = append(encoding, field.New(uint64(len(p.Siblings))))
+	header := len(encoding)
+	encoding = encoding[:header+len(p.Siblings)*hash.DigestLen]
+	hash.DigestsToElementsInto(encoding[header:], p.Siblings)
+	return encoding
+}
+
+// Decode implements bfieldcodec.BFieldCodec. The result is a *Proof.
+func (p *Proof) Decode(sequence []field.Element) (bfieldcodec.BFieldCodec, error) {
+	return DecodeProof(sequence)
+}
+
+// StaticLength implements bfieldcodec.BFieldCodec. Proofs have dynamic length.
+func (p *Proof) StaticLength() *int {
+	return nil
+}
+
+// DecodeProof decodes a proof from its BFieldCodec encoding.
+func DecodeProof(sequence []field.Element) (*Proof, error) {
+	headerLen := 2*bitmapWords + 1
+	if len(sequence) < headerLen {
+		return nil, bfieldcodec.BFieldCodecError{
+			Type:    bfieldcodec.ErrorSequenceTooShort,
+			Message: fmt.Sprintf("need at least %d elements for proof header", headerLen),
+		}
+	}
+
+	proof := &Proof{}
+	for i := range proof.Bitmap {
+		word, err := bfieldcodec.DecodeUint64(sequence[2*i : 2*i+2])
+		if err != nil {
+			return nil, err
+		}
+		proof.Bitmap[i] = word
+	}
+
+	numSiblings := sequence[2*bitmapWords].Value()
+	body := sequence[headerLen:]
+	if numSiblings > Depth || uint64(len(body)) != numSiblings*hash.DigestLen {
+		return nil, bfieldcodec.BFieldCodecError{
+			Type:    bfieldcodec.ErrorInvalidLengthIndicator,
+			Message: fmt.Sprintf("sibling count %d does not match %d remaining elements", numSiblings, len(body)),
+		}
+	}
+
+	proof.Siblings = make([]hash.Digest, numSiblings)
+	hash.ElementsToDigestsInto(proof.Siblings, body)
+
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
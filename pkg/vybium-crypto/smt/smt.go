@@ -0,0 +1,261 @@
+// Package smt provides a sparse Merkle tree: a key-value commitment keyed by
+// digests in which both presence and absence of a key can be proven.
+//
+// The tree is a perfect binary Merkle tree of fixed height Depth. A key's
+// leaf position is given by the first Depth bits of the key (see KeyPath).
+// Empty subtrees have precomputed default digests, so the tree only stores
+// the non-empty part: internally it is a compressed trie with one node per
+// leaf and one per branching point, and runs of levels whose siblings are
+// all default are hashed without being stored.
+package smt
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// Depth is the height of the tree, i.e. the number of key bits that
+// determine a leaf's position.
+const Depth = 160
+
+// Path holds the Depth bits of a key that select its leaf, most significant
+// bit first. Bits beyond Depth are zero.
+type Path [3]uint64
+
+// KeyPath returns the leaf position of a key: the first Depth bits of the
+// canonical values of the key's elements, most significant bit first.
+func KeyPath(key hash.Digest) Path {
+	return Path{key[0].Value(), key[1].Value(), key[2].Value() &^ 0xFFFFFFFF}
+}
+
+// bit returns bit i of the path, where bit 0 selects the root's child.
+func (p Path) bit(i int) int {
+	return int(p[i/64]>>(63-i%64)) & 1
+}
+
+// firstDifference returns the index of the first of the first n bits in
+// which a and b differ, or -1 if they agree on all of them.
+func firstDifference(a, b Path, n int) int {
+	for word := 0; word*64 < n; word++ {
+		if diff := a[word] ^ b[word]; diff != 0 {
+			index := word*64 + bits.LeadingZeros64(diff)
+			if index < n {
+				return index
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+var (
+	defaultDigestsOnce sync.Once
+	defaultDigestsList [Depth + 1]hash.Digest
+)
+
+// defaultDigests returns the digests of empty subtrees indexed by height:
+// the empty leaf is the zero digest and each level up hashes two copies of
+// the level below. The ladder is computed once.
+func defaultDigests() *[Depth + 1]hash.Digest {
+	defaultDigestsOnce.Do(func() {
+		for h := 1; h <= Depth; h++ {
+			defaultDigestsList[h] = hash.HashPair(defaultDigestsList[h-1], defaultDigestsList[h-1])
+		}
+	})
+	return &defaultDigestsList
+}
+
+// leafDigest returns the digest of a non-empty leaf. It binds the full key,
+// not just its path, and uses the variable-length domain to stay separate
+// from internal nodes, which are hashed with HashPair.
+func leafDigest(key, value hash.Digest) hash.Digest {
+	input := make([]field.Element, 0, 2*hash.DigestLen)
+	input = append(input, key[:]...)
+	input = append(input, value[:]...)
+	return hash.HashVarlen(input)
+}
+
+// climb hashes digest, the digest of the subtree at height from on the given
+// path, up to height to, using default digests as siblings.
+func climb(digest hash.Digest, from, to int, path Path) hash.Digest {
+	defaults := defaultDigests()
+	for h := from; h < to; h++ {
+		if path.bit(Depth-1-h) == 0 {
+			digest = hash.HashPair(digest, defaults[h])
+		} else {
+			digest = hash.HashPair(defaults[h], digest)
+		}
+	}
+	return digest
+}
+
+// node is a leaf (height 0) or a branching point of the compressed trie.
+// A branch at height h has two non-nil children at heights below h; the
+// levels between a child and its parent have default siblings only.
+type node struct {
+	height int
+	path   Path        // path of any leaf in the subtree
+	digest hash.Digest // digest of the subtree rooted at this node
+
+	// Leaf data
+	key   hash.Digest
+	value hash.Digest
+
+	// Branch data: children and their digests climbed to height-1
+	children [2]*node
+	tops     [2]hash.Digest
+}
+
+func newLeaf(key, value hash.Digest) *node {
+	return &node{
+		path:   KeyPath(key),
+		digest: leafDigest(key, value),
+		key:    key,
+		value:  value,
+	}
+}
+
+// newBranch creates the branch at the given height joining two subtrees
+// whose paths first differ at bit Depth-height.
+func newBranch(height int, a, b *node) *node {
+	branch := &node{height: height, path: a.path}
+	if a.path.bit(Depth-height) == 0 {
+		branch.children = [2]*node{a, b}
+	} else {
+		branch.children = [2]*node{b, a}
+	}
+	branch.update(0)
+	branch.update(1)
+	return branch
+}
+
+// update recomputes the cached top of child side and the branch digest.
+func (n *node) update(side int) {
+	child := n.children[side]
+	n.tops[side] = climb(child.digest, child.height, n.height-1, child.path)
+	n.digest = hash.HashPair(n.tops[0], n.tops[1])
+}
+
+// SparseMerkleTree is a fixed-depth sparse Merkle tree mapping digests to
+// digests. The zero value is an empty tree ready for use.
+type SparseMerkleTree struct {
+	root *node
+	size int
+}
+
+// New creates an empty sparse Merkle tree.
+func New() *SparseMerkleTree {
+	return &SparseMerkleTree{}
+}
+
+// Len returns the number of keys in the tree.
+func (t *SparseMerkleTree) Len() int {
+	return t.size
+}
+
+// Root returns the root digest of the tree. The root depends only on the
+// stored key-value pairs, not on the order in which they were inserted.
+func (t *SparseMerkleTree) Root() hash.Digest {
+	if t.root == nil {
+		return defaultDigests()[Depth]
+	}
+	return climb(t.root.digest, t.root.height, Depth, t.root.path)
+}
+
+// Get returns the value stored under key and whether the key is present.
+func (t *SparseMerkleTree) Get(key hash.Digest) (hash.Digest, bool) {
+	path := KeyPath(key)
+	n := t.root
+	for n != nil && n.height > 0 {
+		n = n.children[path.bit(Depth-n.height)]
+	}
+	if n == nil || n.key != key {
+		return hash.Digest{}, false
+	}
+	return n.value, true
+}
+
+// Put stores value under key, replacing any previous value.
+// Returns an error if a different key already occupies the same leaf
+// position, i.e. the two keys agree on their first Depth bits.
+func (t *SparseMerkleTree) Put(key, value hash.Digest) error {
+	leaf := newLeaf(key, value)
+	root, inserted, err := insert(t.root, leaf)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	if inserted {
+		t.size++
+	}
+	return nil
+}
+
+// insert adds leaf to the subtree rooted at n and returns the new subtree
+// root and whether a new key was added (as opposed to a value replaced).
+func insert(n, leaf *node) (*node, bool, error) {
+	if n == nil {
+		return leaf, true, nil
+	}
+
+	if d := firstDifference(n.path, leaf.path, Depth-n.height); d >= 0 {
+		return newBranch(Depth-d, n, leaf), true, nil
+	}
+
+	if n.height == 0 {
+		if n.key != leaf.key {
+			return nil, false, fmt.Errorf("key %s collides with existing key %s in the first %d bits", leaf.key, n.key, Depth)
+		}
+		return leaf, false, nil
+	}
+
+	side := leaf.path.bit(Depth - n.height)
+	child, inserted, err := insert(n.children[side], leaf)
+	if err != nil {
+		return nil, false, err
+	}
+	n.children[side] = child
+	n.update(side)
+	return n, inserted, nil
+}
+
+// Delete removes key from the tree. Returns false if the key was not present.
+func (t *SparseMerkleTree) Delete(key hash.Digest) bool {
+	root, deleted := remove(t.root, key, KeyPath(key))
+	if deleted {
+		t.root = root
+		t.size--
+	}
+	return deleted
+}
+
+// remove deletes key from the subtree rooted at n. A branch left with a
+// single child is replaced by that child.
+func remove(n *node, key hash.Digest, path Path) (*node, bool) {
+	if n == nil || firstDifference(n.path, path, Depth-n.height) >= 0 {
+		return n, false
+	}
+
+	if n.height == 0 {
+		if n.key != key {
+			return n, false
+		}
+		return nil, true
+	}
+
+	side := path.bit(Depth - n.height)
+	child, deleted := remove(n.children[side], key, path)
+	if !deleted {
+		return n, false
+	}
+	if child == nil {
+		return n.children[1-side], true
+	}
+	n.children[side] = child
+	n.update(side)
+	return n, true
+}
@@ -0,0 +1,398 @@
+package smt
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+func randomDigest(rng *rand.Rand) hash.Digest {
+	var d hash.Digest
+	for i := range d {
+		d[i] = field.New(rng.Uint64())
+	}
+	return d
+}
+
+type entry struct {
+	key, value hash.Digest
+}
+
+func randomEntries(seed int64, n int) []entry {
+	rng := rand.New(rand.NewSource(seed))
+	entries := make([]entry, n)
+	for i := range entries {
+		entries[i] = entry{randomDigest(rng), randomDigest(rng)}
+	}
+	return entries
+}
+
+// naiveRoot computes the root of the full fixed-depth tree directly from
+// the definition, without the compressed representation.
+func naiveRoot(entries []entry) hash.Digest {
+	var subtree func(height int, entries []entry) hash.Digest
+	subtree = func(height int, entries []entry) hash.Digest {
+		if len(entries) == 0 {
+			return defaultDigests()[height]
+		}
+		if height == 0 {
+			return leafDigest(entries[0].key, entries[0].value)
+		}
+		var left, right []entry
+		for _, e := range entries {
+			if KeyPath(e.key).bit(Depth-height) == 0 {
+				left = append(left, e)
+			} else {
+				right = append(right, e)
+			}
+		}
+		return hash.HashPair(subtree(height-1, left), subtree(height-1, right))
+	}
+	return subtree(Depth, entries)
+}
+
+func TestSparseMerkleTreeEmpty(t *testing.T) {
+	tree := New()
+	if tree.Root() != naiveRoot(nil) {
+		t.Error("empty root should be the default digest of height Depth")
+	}
+	if _, ok := tree.Get(hash.Digest{}); ok {
+		t.Error("empty tree should not contain any key")
+	}
+	if tree.Delete(hash.Digest{}) {
+		t.Error("deleting from an empty tree should report absence")
+	}
+}
+
+func TestSparseMerkleTreeMatchesNaiveRoot(t *testing.T) {
+	entries := randomEntries(1, 20)
+	tree := New()
+	for i, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+		if tree.Root() != naiveRoot(entries[:i+1]) {
+			t.Fatalf("root differs from naive computation after %d insertions", i+1)
+		}
+	}
+}
+
+func TestSparseMerkleTreeSharedPrefixes(t *testing.T) {
+	// Keys agreeing on long prefixes exercise deep branches and long edges
+	base := randomDigest(rand.New(rand.NewSource(2)))
+	var entries []entry
+	for _, flip := range []uint{0, 1, 63, 64, 100, 127, 128, 159} {
+		key := base
+		word := flip / 64
+		key[word] = field.New(key[word].Value() ^ (1 << (63 - flip%64)))
+		entries = append(entries, entry{key, hash.Digest{field.New(uint64(flip))}})
+	}
+
+	tree := New()
+	for _, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	if tree.Root() != naiveRoot(entries) {
+		t.Error("root differs from naive computation")
+	}
+	for _, e := range entries {
+		proof, err := tree.ProveInclusion(e.key)
+		if err != nil {
+			t.Fatalf("prove failed: %v", err)
+		}
+		if !VerifyProof(tree.Root(), e.key, &e.value, proof) {
+			t.Error("inclusion proof failed for shared-prefix key")
+		}
+	}
+}
+
+func TestSparseMerkleTreeOrderIndependence(t *testing.T) {
+	entries := randomEntries(3, 200)
+
+	reference := New()
+	for _, e := range entries {
+		if err := reference.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 5; trial++ {
+		shuffled := append([]entry{}, entries...)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		tree := New()
+		for _, e := range shuffled {
+			if err := tree.Put(e.key, e.value); err != nil {
+				t.Fatalf("put failed: %v", err)
+			}
+		}
+		if tree.Root() != reference.Root() {
+			t.Fatalf("trial %d: root depends on insertion order", trial)
+		}
+	}
+}
+
+func TestSparseMerkleTreePutGetDelete(t *testing.T) {
+	entries := randomEntries(5, 50)
+	tree := New()
+	emptyRoot := tree.Root()
+
+	for _, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	if tree.Len() != len(entries) {
+		t.Errorf("expected %d keys, got %d", len(entries), tree.Len())
+	}
+
+	for _, e := range entries {
+		value, ok := tree.Get(e.key)
+		if !ok || value != e.value {
+			t.Fatal("get returned wrong result")
+		}
+	}
+
+	// Overwriting keeps the size and changes the root
+	before := tree.Root()
+	newValue := hash.Digest{field.New(42)}
+	if err := tree.Put(entries[0].key, newValue); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if tree.Len() != len(entries) || tree.Root() == before {
+		t.Error("overwrite should keep the size and change the root")
+	}
+	if value, _ := tree.Get(entries[0].key); value != newValue {
+		t.Error("overwrite did not replace the value")
+	}
+	entries[0].value = newValue
+
+	// Deleting half the keys yields the root of the remaining half
+	for _, e := range entries[:25] {
+		if !tree.Delete(e.key) {
+			t.Fatal("delete reported missing key")
+		}
+		if tree.Delete(e.key) {
+			t.Fatal("second delete should report missing key")
+		}
+	}
+	if tree.Root() != naiveRoot(entries[25:]) {
+		t.Error("root after deletions differs from naive computation")
+	}
+
+	for _, e := range entries[25:] {
+		tree.Delete(e.key)
+	}
+	if tree.Root() != emptyRoot || tree.Len() != 0 {
+		t.Error("deleting every key should restore the empty root")
+	}
+}
+
+func TestSparseMerkleTreeProofs(t *testing.T) {
+	entries := randomEntries(6, 100)
+	absent := randomEntries(7, 20)
+
+	tree := New()
+	for _, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	root := tree.Root()
+
+	for _, e := range entries {
+		proof, err := tree.ProveInclusion(e.key)
+		if err != nil {
+			t.Fatalf("prove inclusion failed: %v", err)
+		}
+		if !VerifyProof(root, e.key, &e.value, proof) {
+			t.Fatal("valid inclusion proof rejected")
+		}
+		if VerifyProof(root, e.key, nil, proof) {
+			t.Fatal("inclusion proof accepted as absence proof")
+		}
+		wrong := e.value
+		wrong[0] = wrong[0].Add(field.One)
+		if VerifyProof(root, e.key, &wrong, proof) {
+			t.Fatal("inclusion proof accepted for wrong value")
+		}
+		// Compact: only the branching levels carry siblings
+		if len(proof.Siblings) > 40 {
+			t.Errorf("proof has %d siblings, expected a compact proof", len(proof.Siblings))
+		}
+	}
+
+	for _, e := range absent {
+		proof, err := tree.ProveAbsence(e.key)
+		if err != nil {
+			t.Fatalf("prove absence failed: %v", err)
+		}
+		if !VerifyProof(root, e.key, nil, proof) {
+			t.Fatal("valid absence proof rejected")
+		}
+		if VerifyProof(root, e.key, &e.value, proof) {
+			t.Fatal("absence proof accepted as inclusion proof")
+		}
+	}
+
+	if _, err := tree.ProveInclusion(absent[0].key); err == nil {
+		t.Error("expected error proving inclusion of absent key")
+	}
+	if _, err := tree.ProveAbsence(entries[0].key); err == nil {
+		t.Error("expected error proving absence of present key")
+	}
+}
+
+func TestSparseMerkleTreeAbsenceAfterDelete(t *testing.T) {
+	entries := randomEntries(8, 30)
+	tree := New()
+	for _, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	oldRoot := tree.Root()
+	oldProof, err := tree.ProveInclusion(entries[3].key)
+	if err != nil {
+		t.Fatalf("prove failed: %v", err)
+	}
+
+	tree.Delete(entries[3].key)
+	proof, err := tree.ProveAbsence(entries[3].key)
+	if err != nil {
+		t.Fatalf("prove absence failed: %v", err)
+	}
+	if !VerifyProof(tree.Root(), entries[3].key, nil, proof) {
+		t.Error("absence proof after delete rejected")
+	}
+	if VerifyProof(tree.Root(), entries[3].key, &entries[3].value, oldProof) {
+		t.Error("stale inclusion proof accepted against new root")
+	}
+	if !VerifyProof(oldRoot, entries[3].key, &entries[3].value, oldProof) {
+		t.Error("inclusion proof should still verify against the old root")
+	}
+}
+
+func TestSparseMerkleTreeEmptyTreeAbsence(t *testing.T) {
+	tree := New()
+	key := randomDigest(rand.New(rand.NewSource(9)))
+	proof, err := tree.ProveAbsence(key)
+	if err != nil {
+		t.Fatalf("prove absence failed: %v", err)
+	}
+	if len(proof.Siblings) != 0 {
+		t.Error("absence proof in empty tree should have no siblings")
+	}
+	if !VerifyProof(tree.Root(), key, nil, proof) {
+		t.Error("absence proof in empty tree rejected")
+	}
+}
+
+func TestSparseMerkleTreeKeyCollision(t *testing.T) {
+	tree := New()
+	key := randomDigest(rand.New(rand.NewSource(10)))
+	if err := tree.Put(key, hash.Digest{}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	// Same first Depth bits, different key
+	colliding := key
+	colliding[4] = colliding[4].Add(field.One)
+	if err := tree.Put(colliding, hash.Digest{}); err == nil {
+		t.Error("expected error for colliding key")
+	}
+	if _, err := tree.ProveAbsence(colliding); err == nil {
+		t.Error("expected error proving absence at an occupied position")
+	}
+	if tree.Len() != 1 {
+		t.Error("failed put should not change the tree")
+	}
+}
+
+func TestProofCodecRoundTrip(t *testing.T) {
+	entries := randomEntries(11, 64)
+	tree := New()
+	for _, e := range entries {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+
+	for _, e := range entries[:10] {
+		proof, err := tree.ProveInclusion(e.key)
+		if err != nil {
+			t.Fatalf("prove failed: %v", err)
+		}
+
+		decoded, err := (&Proof{}).Decode(proof.Encode())
+		if err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		roundTripped := decoded.(*Proof)
+		if roundTripped.Bitmap != proof.Bitmap || len(roundTripped.Siblings) != len(proof.Siblings) {
+			t.Fatal("round trip changed the proof")
+		}
+		if !VerifyProof(tree.Root(), e.key, &e.value, roundTripped) {
+			t.Fatal("round-tripped proof rejected")
+		}
+	}
+}
+
+func TestDecodeProofErrors(t *testing.T) {
+	tree := New()
+	for _, e := range randomEntries(12, 8) {
+		if err := tree.Put(e.key, e.value); err != nil {
+			t.Fatalf("put failed: %v", err)
+		}
+	}
+	e := randomEntries(12, 1)[0]
+	proof, err := tree.ProveInclusion(e.key)
+	if err != nil {
+		t.Fatalf("prove failed: %v", err)
+	}
+	valid := proof.Encode()
+
+	wrongCount := append([]field.Element{}, valid...)
+	wrongCount[2*bitmapWords] = field.New(uint64(len(proof.Siblings) + 1))
+
+	bitBeyondDepth := append([]field.Element{}, valid...)
+	bitBeyondDepth[2*bitmapWords-1] = field.New(0x80000000)
+
+	tests := []struct {
+		name     string
+		sequence []field.Element
+	}{
+		{"too short", valid[:3]},
+		{"truncated siblings", valid[:len(valid)-1]},
+		{"sibling count mismatch", wrongCount},
+		{"bitmap beyond depth", bitBeyondDepth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeProof(tt.sequence); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func BenchmarkSparseMerkleTreePut100k(b *testing.B) {
+	entries := randomEntries(13, 100000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := New()
+		for _, e := range entries {
+			if err := tree.Put(e.key, e.value); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package sponge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// SampleOpKind is the kind of a replayed sponge operation.
+type SampleOpKind int
+
+const (
+	// OpAbsorb absorbs SampleOp.Elements with hash.Tip5.PadAndAbsorbAll.
+	OpAbsorb SampleOpKind = iota
+
+	// OpSampleScalars samples SampleOp.Count scalars as
+	// hash.Tip5.SampleScalars does.
+	OpSampleScalars
+
+	// OpSampleIndices samples SampleOp.Count indices below
+	// SampleOp.UpperBound as hash.Tip5.SampleIndices does.
+	OpSampleIndices
+)
+
+func (k SampleOpKind) String() string {
+	switch k {
+	case OpAbsorb:
+		return "absorb"
+	case OpSampleScalars:
+		return "sample-scalars"
+	case OpSampleIndices:
+		return "sample-indices"
+	default:
+		return "unknown"
+	}
+}
+
+// SampleOp is one step of a replay script.
+type SampleOp struct {
+	Kind SampleOpKind
+
+	// Elements is the input of OpAbsorb.
+	Elements []field.Element
+
+	// Count is the number of values OpSampleScalars or OpSampleIndices
+	// produces.
+	Count int
+
+	// UpperBound is the exclusive bound of OpSampleIndices, a power of two.
+	UpperBound uint32
+}
+
+// ReplaySqueeze records one squeeze: its position among all squeezes of the
+// replay, and the rate elements it returned as canonical values.
+type ReplaySqueeze struct {
+	Number   int
+	Elements [Rate]uint64
+}
+
+// ReplayScalar records a sampled scalar and where its first coefficient was
+// taken from: element Offset of squeeze Squeeze. A scalar's coefficients are
+// consecutive elements and may span two squeezes.
+type ReplayScalar struct {
+	Squeeze      int
+	Offset       int
+	Coefficients [xfield.ExtensionDegree]uint64
+}
+
+// ReplayIndex records one element consumed while sampling indices, taken
+// from element Offset of squeeze Squeeze. Elements equal to P-1 are rejected
+// and produce no index.
+type ReplayIndex struct {
+	Squeeze  int
+	Offset   int
+	Element  uint64
+	Index    uint32
+	Rejected bool
+}
+
+// ReplayStep records the effect of one script operation.
+type ReplayStep struct {
+	Op SampleOp
+
+	// Squeezes are the squeezes the operation performed.
+	Squeezes []ReplaySqueeze
+
+	Scalars []ReplayScalar
+	Indices []ReplayIndex
+
+	// StateAfter is the sponge state once the operation completed.
+	StateAfter [hash.StateSize]uint64
+}
+
+// ReplayLog is the audit record of a replay: the starting state and every
+// squeeze and derived value of each operation.
+type ReplayLog struct {
+	InitialState [hash.StateSize]uint64
+	Steps        []ReplayStep
+}
+
+// Replay runs a script of absorb and sample operations on a Tip5 sponge
+// restored from a state exported with hash.Tip5.ExportState, and records
+// every squeeze and derived value. The sampled values are exactly those the
+// sponge's SampleScalars and SampleIndices would produce, so two parties
+// holding the same checkpoint and script obtain identical logs.
+// Returns an error if the state is not canonical or an operation is invalid.
+func Replay(state [hash.StateSize]uint64, script []SampleOp) (*ReplayLog, error) {
+	sponge, err := hash.ImportTip5State(state)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &ReplayLog{InitialState: state}
+	squeezes := 0
+	squeeze := func(step *ReplayStep) [Rate]field.Element {
+		output := sponge.Squeeze()
+		record := ReplaySqueeze{Number: squeezes}
+		for i, element := range output {
+			record.Elements[i] = element.Value()
+		}
+		step.Squeezes = append(step.Squeezes, record)
+		squeezes++
+		return output
+	}
+
+	for i, op := range script {
+		step := ReplayStep{Op: op}
+		switch op.Kind {
+		case OpAbsorb:
+			sponge.PadAndAbsorbAll(op.Elements)
+
+		case OpSampleScalars:
+			if op.Count < 0 {
+				return nil, fmt.Errorf("operation %d: cannot sample %d scalars", i, op.Count)
+			}
+			// As SampleScalars: squeeze enough rate elements up front and
+			// take consecutive triples, dropping the rest
+			numSqueezes := (op.Count*xfield.ExtensionDegree + Rate - 1) / Rate
+			var elements []field.Element
+			for s := 0; s < numSqueezes; s++ {
+				output := squeeze(&step)
+				elements = append(elements, output[:]...)
+			}
+			first := squeezes - numSqueezes
+			for j := 0; j < op.Count; j++ {
+				start := j * xfield.ExtensionDegree
+				scalar := ReplayScalar{Squeeze: first + start/Rate, Offset: start % Rate}
+				for k := range scalar.Coefficients {
+					scalar.Coefficients[k] = elements[start+k].Value()
+				}
+				step.Scalars = append(step.Scalars, scalar)
+			}
+
+		case OpSampleIndices:
+			if op.Count < 0 {
+				return nil, fmt.Errorf("operation %d: cannot sample %d indices", i, op.Count)
+			}
+			if op.UpperBound == 0 || op.UpperBound&(op.UpperBound-1) != 0 {
+				return nil, fmt.Errorf("operation %d: upper bound %d is not a power of two", i, op.UpperBound)
+			}
+			// As SampleIndices: consume squeezed elements in order, rejecting
+			// P-1, and drop what is left of the last squeeze
+			sampled := 0
+			for sampled < op.Count {
+				output := squeeze(&step)
+				for offset := 0; offset < Rate && sampled < op.Count; offset++ {
+					record := ReplayIndex{
+						Squeeze: squeezes - 1,
+						Offset:  offset,
+						Element: output[offset].Value(),
+					}
+					if output[offset] == field.Max {
+						record.Rejected = true
+					} else {
+						record.Index = uint32(record.Element) % op.UpperBound
+						sampled++
+					}
+					step.Indices = append(step.Indices, record)
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("operation %d: unknown kind %d", i, op.Kind)
+		}
+
+		step.StateAfter = sponge.ExportState()
+		log.Steps = append(log.Steps, step)
+	}
+
+	return log, nil
+}
+
+// String renders the log as a line-oriented text record. Values are
+// canonical and in decimal, so the record can be checked by hand or diffed
+// between parties.
+func (l *ReplayLog) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "initial state %s\n", formatValues(l.InitialState[:]))
+	for i, step := range l.Steps {
+		switch step.Op.Kind {
+		case OpAbsorb:
+			values := make([]uint64, len(step.Op.Elements))
+			for j, element := range step.Op.Elements {
+				values[j] = element.Value()
+			}
+			fmt.Fprintf(&b, "step %d %s %d elements %s\n", i, step.Op.Kind, len(values), formatValues(values))
+		case OpSampleScalars:
+			fmt.Fprintf(&b, "step %d %s count %d\n", i, step.Op.Kind, step.Op.Count)
+		case OpSampleIndices:
+			fmt.Fprintf(&b, "step %d %s count %d bound %d\n", i, step.Op.Kind, step.Op.Count, step.Op.UpperBound)
+		}
+		for _, squeeze := range step.Squeezes {
+			fmt.Fprintf(&b, "  squeeze %d %s\n", squeeze.Number, formatValues(squeeze.Elements[:]))
+		}
+		for j, scalar := range step.Scalars {
+			fmt.Fprintf(&b, "  scalar %d squeeze %d offset %d %s\n", j, scalar.Squeeze, scalar.Offset, formatValues(scalar.Coefficients[:]))
+		}
+		for _, index := range step.Indices {
+			if index.Rejected {
+				fmt.Fprintf(&b, "  element squeeze %d offset %d %d rejected\n", index.Squeeze, index.Offset, index.Element)
+				continue
+			}
+			fmt.Fprintf(&b, "  element squeeze %d offset %d %d index %d\n", index.Squeeze, index.Offset, index.Element, index.Index)
+		}
+		fmt.Fprintf(&b, "  state %s\n", formatValues(step.StateAfter[:]))
+	}
+	return b.String()
+}
+
+func formatValues(values []uint64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,144 @@
+package sponge
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// replayFixture returns a checkpoint state and a script exercising every
+// operation. State element 2 is P-1, so the first squeeze yields an element
+// that index sampling rejects.
+func replayFixture() ([hash.StateSize]uint64, []SampleOp) {
+	var state [hash.StateSize]uint64
+	for i := range state {
+		state[i] = uint64(i)*0x9e3779b97f4a7c15%field.P + 1
+	}
+	state[2] = field.P - 1
+
+	script := []SampleOp{
+		{Kind: OpSampleIndices, Count: 4, UpperBound: 64},
+		{Kind: OpAbsorb, Elements: []field.Element{field.New(1), field.New(2), field.New(3)}},
+		{Kind: OpSampleScalars, Count: 5},
+		{Kind: OpSampleIndices, Count: 12, UpperBound: 1024},
+	}
+	return state, script
+}
+
+func TestReplayMatchesSponge(t *testing.T) {
+	state, script := replayFixture()
+	log, err := Replay(state, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sponge, err := hash.ImportTip5State(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, op := range script {
+		step := log.Steps[i]
+		switch op.Kind {
+		case OpAbsorb:
+			sponge.PadAndAbsorbAll(op.Elements)
+		case OpSampleScalars:
+			scalars, err := sponge.SampleScalars(op.Count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(step.Scalars) != len(scalars) {
+				t.Fatalf("step %d: got %d scalars, want %d", i, len(step.Scalars), len(scalars))
+			}
+			for j, scalar := range scalars {
+				for k, c := range scalar.Coefficients {
+					if step.Scalars[j].Coefficients[k] != c.Value() {
+						t.Errorf("step %d: scalar %d differs", i, j)
+					}
+				}
+			}
+		case OpSampleIndices:
+			var got []uint32
+			for _, index := range step.Indices {
+				if !index.Rejected {
+					got = append(got, index.Index)
+				}
+			}
+			if want := sponge.SampleIndices(op.UpperBound, op.Count); !reflect.DeepEqual(got, want) {
+				t.Errorf("step %d: got indices %v, want %v", i, got, want)
+			}
+		}
+		if step.StateAfter != sponge.ExportState() {
+			t.Fatalf("step %d: state differs from the sponge's", i)
+		}
+	}
+
+	rejected := false
+	for _, index := range log.Steps[0].Indices {
+		rejected = rejected || index.Rejected
+	}
+	if !rejected {
+		t.Error("fixture did not exercise rejection")
+	}
+}
+
+func TestReplayDeterministic(t *testing.T) {
+	state, script := replayFixture()
+	first, err := Replay(state, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Replay(state, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) || first.String() != second.String() {
+		t.Error("replaying the same state and script gave different logs")
+	}
+}
+
+// TestReplayGolden pins the rendered log of the fixture, so that two parties
+// can compare records produced by independent builds.
+func TestReplayGolden(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden log assumes the full Tip5 permutation")
+	}
+	want, err := os.ReadFile("testdata/replay_golden.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, script := replayFixture()
+	log, err := Replay(state, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := log.String(); got != string(want) {
+		t.Errorf("log differs from testdata/replay_golden.txt:\n%s", got)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	state, _ := replayFixture()
+
+	nonCanonical := state
+	nonCanonical[5] = field.P
+	if _, err := Replay(nonCanonical, nil); err == nil {
+		t.Error("accepted a non-canonical state")
+	}
+
+	scripts := map[string][]SampleOp{
+		"negative scalar count": {{Kind: OpSampleScalars, Count: -1}},
+		"negative index count":  {{Kind: OpSampleIndices, Count: -1, UpperBound: 8}},
+		"zero bound":            {{Kind: OpSampleIndices, Count: 1}},
+		"bound not power of 2":  {{Kind: OpSampleIndices, Count: 1, UpperBound: 12}},
+		"unknown kind":          {{Kind: SampleOpKind(7)}},
+	}
+	for name, script := range scripts {
+		if _, err := Replay(state, script); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
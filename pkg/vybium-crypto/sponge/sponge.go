@@ -2,7 +2,7 @@
 //
 // A cryptographic sponge is a construction that can absorb arbitrary-length input
 // and squeeze arbitrary-length output using a fixed-width permutation function.
-// It's the foundation for hash functions like Tip5 and Poseidon.
+// It's the foundation for hash functions like Tip5, Poseidon and Arion.
 //
 // Key Features:
 // - Absorb arbitrary-length input in fixed-size chunks
@@ -12,44 +12,41 @@
 package sponge
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
 )
 
 const (
-	// Rate is the number of field elements that can be absorbed in one permutation.
-	// This Production implementation.
-	Rate = 10
+	// Rate is the number of field elements that can be absorbed in one
+	// permutation. It equals hash.Rate and params.SpongeRate.
+	Rate = hash.Rate
 )
 
 // Domain represents the hashing domain for collision prevention.
 // Different domains ensure that hashing different types of data
 // produces different outputs even if the raw data is identical.
-type Domain int
+//
+// Deprecated: Domain is an alias of hash.Domain; use that instead.
+type Domain = hash.Domain
 
 const (
 	// VariableLength domain is used for hashing objects that potentially
 	// serialize to more than RATE field elements.
-	VariableLength Domain = iota
+	//
+	// Deprecated: Use hash.VariableLength.
+	VariableLength = hash.VariableLength
 
 	// FixedLength domain is used for hashing objects that always fit
 	// within RATE field elements, e.g. a pair of Digests.
-	FixedLength
+	//
+	// Deprecated: Use hash.FixedLength.
+	FixedLength = hash.FixedLength
 )
 
-func (d Domain) String() string {
-	switch d {
-	case VariableLength:
-		return "VariableLength"
-	case FixedLength:
-		return "FixedLength"
-	default:
-		return "Unknown"
-	}
-}
-
 // Sponge defines the interface for cryptographic sponge constructions.
 // A sponge can absorb arbitrary-length input and squeeze arbitrary-length output
 // using a fixed-width permutation function.
@@ -78,17 +75,21 @@ type Sponge interface {
 
 // Tip5Sponge implements the Sponge interface using the Tip5 permutation.
 // This is the primary sponge implementation used in STARK proofs.
+//
+// It keeps the full Tip5 state of hash.StateSize elements: input overwrites
+// the first Rate elements and the capacity is only changed by the
+// permutation, so HashVarlen over a VariableLength sponge agrees with
+// hash.HashVarlen.
 type Tip5Sponge struct {
-	state  [Rate]field.Element
-	domain Domain
+	state  [hash.StateSize]field.Element
+	domain hash.Domain
 }
 
 // NewTip5Sponge creates a new Tip5 sponge with the specified domain.
-func NewTip5Sponge(domain Domain) *Tip5Sponge {
-	return &Tip5Sponge{
-		state:  [Rate]field.Element{},
-		domain: domain,
-	}
+func NewTip5Sponge(domain hash.Domain) *Tip5Sponge {
+	s := &Tip5Sponge{domain: domain}
+	s.Reset()
+	return s
 }
 
 // Init creates a new Tip5 sponge instance.
@@ -96,58 +97,38 @@ func (s *Tip5Sponge) Init() Sponge {
 	return NewTip5Sponge(s.domain)
 }
 
-// Absorb absorbs a chunk of RATE field elements into the sponge state.
-// This uses the Tip5 permutation to update the internal state.
+// Absorb overwrites the first RATE elements of the state with input and
+// applies the Tip5 permutation.
 func (s *Tip5Sponge) Absorb(input [Rate]field.Element) {
-	// XOR the input with the current state
-	for i := 0; i < Rate; i++ {
-		s.state[i] = s.state[i].Add(input[i])
-	}
-
-	// Apply Tip5 permutation to the state
-	s.applyTip5Permutation()
+	copy(s.state[:Rate], input[:])
+	s.state = hash.Tip5PermuteState(s.state)
 }
 
-// Squeeze squeezes a chunk of RATE field elements from the sponge state.
-// This extracts output from the current state and applies permutation.
+// Squeeze returns the first RATE elements of the state and applies the Tip5
+// permutation to prepare for the next squeeze.
 func (s *Tip5Sponge) Squeeze() [Rate]field.Element {
-	// Extract the current state as output
-	output := s.state
-
-	// Apply permutation to prepare for next squeeze
-	s.applyTip5Permutation()
-
+	var output [Rate]field.Element
+	copy(output[:], s.state[:Rate])
+	s.state = hash.Tip5PermuteState(s.state)
 	return output
 }
 
 // PadAndAbsorbAll absorbs arbitrary-length input with proper padding.
-// This is the main method for hashing variable-length data.
+// The input is always followed by a one and then zeros up to a multiple of
+// RATE, so empty input and input of a multiple of RATE absorb a padding
+// chunk of their own, as in hash.Tip5.PadAndAbsorbAll.
 func (s *Tip5Sponge) PadAndAbsorbAll(input []field.Element) {
-	// Process input in chunks of RATE
-	for i := 0; i < len(input); i += Rate {
+	for len(input) >= Rate {
 		var chunk [Rate]field.Element
-
-		// Copy available elements
-		copyCount := Rate
-		if i+Rate > len(input) {
-			copyCount = len(input) - i
-		}
-
-		for j := 0; j < copyCount; j++ {
-			chunk[j] = input[i+j]
-		}
-
-		// If this is the last chunk and it's not full, apply padding
-		if i+Rate >= len(input) && copyCount < Rate {
-			// Pad with [1, 0, 0, ...] - at least one element of padding
-			chunk[copyCount] = field.One
-			for j := copyCount + 1; j < Rate; j++ {
-				chunk[j] = field.Zero
-			}
-		}
-
+		copy(chunk[:], input[:Rate])
 		s.Absorb(chunk)
+		input = input[Rate:]
 	}
+
+	var lastChunk [Rate]field.Element
+	copy(lastChunk[:], input)
+	lastChunk[len(input)] = field.One
+	s.Absorb(lastChunk)
 }
 
 // Clone creates a copy of the sponge state.
@@ -159,33 +140,26 @@ func (s *Tip5Sponge) Clone() Sponge {
 	return clone
 }
 
-// Reset resets the sponge to its initial state.
+// Reset resets the sponge to the initial state of its domain: all zeros,
+// with the capacity set to ones for FixedLength.
 func (s *Tip5Sponge) Reset() {
-	s.state = [Rate]field.Element{}
-}
-
-// applyTip5Permutation applies the Tip5 permutation to the sponge state.
-func (s *Tip5Sponge) applyTip5Permutation() {
-	// Convert state to digest format for Tip5
-	var digest [5]field.Element
-	copy(digest[:], s.state[:])
-
-	// Apply Tip5 permutation
-	permuted := hash.Tip5Permutation(digest)
-
-	// Copy result back to state
-	copy(s.state[:], permuted[:])
+	s.state = [hash.StateSize]field.Element{}
+	if s.domain == hash.FixedLength {
+		for i := Rate; i < hash.StateSize; i++ {
+			s.state[i] = field.One
+		}
+	}
 }
 
 // PoseidonSponge implements the Sponge interface using the Poseidon permutation.
 // This provides an alternative sponge construction using Poseidon hash.
 type PoseidonSponge struct {
 	state  [Rate]field.Element
-	domain Domain
+	domain hash.Domain
 }
 
 // NewPoseidonSponge creates a new Poseidon sponge with the specified domain.
-func NewPoseidonSponge(domain Domain) *PoseidonSponge {
+func NewPoseidonSponge(domain hash.Domain) *PoseidonSponge {
 	return &PoseidonSponge{
 		state:  [Rate]field.Element{},
 		domain: domain,
@@ -275,8 +249,80 @@ func (s *PoseidonSponge) applyPoseidonPermutation() {
 	copy(s.state[:], permuted[:])
 }
 
+// ArionSponge implements the Sponge interface using the Arion permutation.
+//
+// Arion absorbs hash.ArionRate elements per permutation, so each chunk of
+// RATE elements is absorbed, and each chunk squeezed, in RATE/hash.ArionRate
+// steps of the underlying hash.Arion sponge.
+type ArionSponge struct {
+	arion  *hash.Arion
+	domain hash.Domain
+}
+
+// NewArionSponge creates a new Arion sponge with the specified domain.
+func NewArionSponge(domain hash.Domain) *ArionSponge {
+	return &ArionSponge{
+		arion:  hash.NewArion(domain),
+		domain: domain,
+	}
+}
+
+// Init creates a new Arion sponge instance.
+func (s *ArionSponge) Init() Sponge {
+	return NewArionSponge(s.domain)
+}
+
+// Absorb adds a chunk of RATE field elements to the state, hash.ArionRate
+// elements per permutation.
+func (s *ArionSponge) Absorb(input [Rate]field.Element) {
+	for i := 0; i < Rate; i += hash.ArionRate {
+		s.arion.Absorb(input[i : i+hash.ArionRate])
+	}
+}
+
+// Squeeze returns RATE field elements, hash.ArionRate elements per
+// permutation.
+func (s *ArionSponge) Squeeze() [Rate]field.Element {
+	var output [Rate]field.Element
+	for i := 0; i < Rate; i += hash.ArionRate {
+		copy(output[i:], s.arion.SqueezeRate())
+	}
+	return output
+}
+
+// PadAndAbsorbAll absorbs arbitrary-length input with proper padding.
+// The input is always followed by a one and then zeros up to a multiple of
+// hash.ArionRate, so empty input and input of a multiple of the rate absorb
+// a padding block of their own.
+func (s *ArionSponge) PadAndAbsorbAll(input []field.Element) {
+	for len(input) >= hash.ArionRate {
+		s.arion.Absorb(input[:hash.ArionRate])
+		input = input[hash.ArionRate:]
+	}
+
+	var lastBlock [hash.ArionRate]field.Element
+	copy(lastBlock[:], input)
+	lastBlock[len(input)] = field.One
+	s.arion.Absorb(lastBlock[:])
+}
+
+// Clone creates a copy of the sponge state.
+func (s *ArionSponge) Clone() Sponge {
+	return &ArionSponge{
+		arion:  s.arion.Clone(),
+		domain: s.domain,
+	}
+}
+
+// Reset resets the sponge to the initial state of its domain.
+func (s *ArionSponge) Reset() {
+	s.arion.Reset(s.domain)
+}
+
 // HashVarlen hashes variable-length input using the specified sponge.
 // This is a convenience function that handles the full sponge protocol.
+// The input length is not bounded; use HashVarlenLimited for input whose
+// length is controlled by an untrusted party.
 func HashVarlen(sponge Sponge, input []field.Element) []field.Element {
 	// Reset sponge to initial state
 	sponge.Reset()
@@ -289,6 +335,17 @@ func HashVarlen(sponge Sponge, input []field.Element) []field.Element {
 	return output[:]
 }
 
+// HashVarlenLimited is HashVarlen for untrusted input. Empty input is
+// accepted; input longer than DefaultMaxInputLength (or the length set with
+// WithMaxInputLength) is rejected with ErrInputTooLarge before any hashing.
+func HashVarlenLimited(sponge Sponge, input []field.Element, opts ...ValidationOption) ([]field.Element, error) {
+	opts = append([]ValidationOption{AllowEmptyInput()}, opts...)
+	if err := ValidateSpongeInput(input, opts...); err != nil {
+		return nil, err
+	}
+	return HashVarlen(sponge, input), nil
+}
+
 // HashFixed hashes fixed-length input using the specified sponge.
 // This is optimized for inputs that fit within RATE elements.
 func HashFixed(sponge Sponge, input []field.Element) []field.Element {
@@ -319,57 +376,124 @@ func HashFixed(sponge Sponge, input []field.Element) []field.Element {
 	return output[:]
 }
 
-// SampleIndices samples random indices from a range using the sponge.
-// This is used for FRI queries and other randomized sampling.
+// SampleIndices samples numIndices indices in [0, upperBound) from the
+// sponge, as hash.Tip5.SampleIndices does. upperBound must be a power of two
+// no larger than 2^32; SampleIndices panics otherwise. Indices may repeat.
+//
+// Squeezed elements are consumed in order. An element whose top 32 bits are
+// all ones, which for a canonical value means field.Max, is rejected, since
+// the low 32 bits of such elements are not uniform; every other element
+// yields its low 32 bits modulo upperBound. The indices are thus uniform, as
+// FRI query positions must be. Returns an empty slice if numIndices is not
+// positive.
 func SampleIndices(sponge Sponge, upperBound int, numIndices int) []int {
-	if upperBound <= 0 || numIndices <= 0 {
+	if numIndices <= 0 {
 		return []int{}
 	}
-
-	// Limit numIndices to upperBound to prevent infinite loops
-	if numIndices > upperBound {
-		numIndices = upperBound
+	if upperBound <= 0 || upperBound&(upperBound-1) != 0 || uint64(upperBound) > 1<<32 {
+		panic(fmt.Sprintf("upperBound %d is not a power of 2 no larger than 2^32", upperBound))
 	}
 
 	indices := make([]int, 0, numIndices)
-	used := make(map[int]bool)
-
-	// Generate random field elements and convert to indices
 	for len(indices) < numIndices {
-		// Squeeze random elements from sponge
-		random := sponge.Squeeze()
-
-		for _, element := range random {
-			// Convert field element to index using proper modulo
-			val := element.Value()
-			// Ensure positive result by adding upperBound before modulo
-			index := int((val + uint64(upperBound)) % uint64(upperBound))
-
-			// Avoid duplicates
-			if !used[index] {
-				indices = append(indices, index)
-				used[index] = true
-
-				if len(indices) >= numIndices {
-					break
-				}
+		block := sponge.Squeeze()
+		for _, element := range block {
+			if len(indices) == numIndices {
+				break
+			}
+			if element == field.Max {
+				continue
 			}
+			indices = append(indices, int(uint64(uint32(element.Value()))%uint64(upperBound)))
 		}
 	}
-
 	return indices
 }
 
+// SampleScalars samples n extension field elements from the sponge, as
+// hash.Tip5.SampleScalars does: it squeezes ceil(3n/RATE) blocks and reads
+// their concatenation as consecutive coefficient triples, so a triple may
+// spill across two squeezes. Elements left over in the last squeeze are
+// discarded. Returns an empty slice if n is not positive.
+func SampleScalars(sponge Sponge, n int) []xfield.XFieldElement {
+	if n <= 0 {
+		return []xfield.XFieldElement{}
+	}
+
+	numSqueezes := (n*xfield.ExtensionDegree + Rate - 1) / Rate // Ceiling division
+	squeezed := make([]field.Element, 0, numSqueezes*Rate)
+	for i := 0; i < numSqueezes; i++ {
+		block := sponge.Squeeze()
+		squeezed = append(squeezed, block[:]...)
+	}
+
+	scalars := make([]xfield.XFieldElement, n)
+	for i := range scalars {
+		copy(scalars[i].Coefficients[:], squeezed[i*xfield.ExtensionDegree:])
+	}
+	return scalars
+}
+
+// DefaultMaxInputLength is the maximum number of field elements accepted by
+// ValidateSpongeInput and HashVarlenLimited unless WithMaxInputLength says
+// otherwise. It is large enough for execution-trace hashing (2^24 elements,
+// i.e. 128 MiB of field elements).
+const DefaultMaxInputLength = 1 << 24
+
+// ErrEmptyInput is returned by ValidateSpongeInput for empty input unless
+// AllowEmptyInput is given.
+var ErrEmptyInput = errors.New("input cannot be empty")
+
+// ErrInputTooLarge is returned when an input exceeds the allowed length.
+type ErrInputTooLarge struct {
+	// Length is the number of field elements in the rejected input.
+	Length int
+	// MaxLength is the maximum number of field elements allowed.
+	MaxLength int
+}
+
+func (e ErrInputTooLarge) Error() string {
+	return fmt.Sprintf("input too long: %d elements (max %d)", e.Length, e.MaxLength)
+}
+
+// validationConfig holds the limits applied by ValidateSpongeInput.
+type validationConfig struct {
+	maxInputLength int
+	allowEmpty     bool
+}
+
+// ValidationOption configures ValidateSpongeInput and HashVarlenLimited.
+type ValidationOption func(*validationConfig)
+
+// WithMaxInputLength sets the maximum accepted input length in field elements.
+func WithMaxInputLength(maxLength int) ValidationOption {
+	return func(c *validationConfig) {
+		c.maxInputLength = maxLength
+	}
+}
+
+// AllowEmptyInput accepts empty input. The empty sequence is a legitimate
+// hash preimage with a well-defined digest.
+func AllowEmptyInput() ValidationOption {
+	return func(c *validationConfig) {
+		c.allowEmpty = true
+	}
+}
+
 // ValidateSpongeInput validates that input is appropriate for the sponge.
-func ValidateSpongeInput(input []field.Element) error {
-	if len(input) == 0 {
-		return fmt.Errorf("input cannot be empty")
+// By default it rejects empty input with ErrEmptyInput and input longer than
+// DefaultMaxInputLength with ErrInputTooLarge; both can be changed with options.
+func ValidateSpongeInput(input []field.Element, opts ...ValidationOption) error {
+	config := validationConfig{maxInputLength: DefaultMaxInputLength}
+	for _, opt := range opts {
+		opt(&config)
 	}
 
-	// Check for reasonable input length (prevent DoS)
-	maxLength := 1024 * 1024 // 1MB worth of field elements
-	if len(input) > maxLength {
-		return fmt.Errorf("input too long: %d elements (max %d)", len(input), maxLength)
+	if len(input) == 0 && !config.allowEmpty {
+		return ErrEmptyInput
+	}
+	if len(input) > config.maxInputLength {
+		return ErrInputTooLarge{Length: len(input), MaxLength: config.maxInputLength}
 	}
 
 	return nil
@@ -381,6 +505,6 @@ func GetSpongeRate() int {
 }
 
 // IsValidDomain checks if a domain value is valid.
-func IsValidDomain(domain Domain) bool {
-	return domain == VariableLength || domain == FixedLength
+func IsValidDomain(domain hash.Domain) bool {
+	return domain == hash.VariableLength || domain == hash.FixedLength
 }
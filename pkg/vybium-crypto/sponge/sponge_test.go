@@ -1,9 +1,13 @@
 package sponge
 
 import (
+	"errors"
+	"math/rand"
+	"reflect"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
 )
 
 func TestTip5SpongeInit(t *testing.T) {
@@ -184,6 +188,186 @@ func TestPoseidonSpongeBasicOperations(t *testing.T) {
 	sponge.Reset()
 }
 
+func TestTip5SpongeHashVarlenMatchesHash(t *testing.T) {
+	// Lengths 0, Rate and 2*Rate absorb a chunk of padding only
+	for n := 0; n <= 2*Rate+3; n++ {
+		input := make([]field.Element, n)
+		for i := range input {
+			input[i] = field.New(uint64(1258*i + 1))
+		}
+		got := HashVarlen(NewTip5Sponge(VariableLength), input)
+		want := hash.HashVarlen(input)
+		if !reflect.DeepEqual(got[:hash.DigestLen], want[:]) {
+			t.Errorf("length %d: sponge digest %v, hash.HashVarlen %v", n, got[:hash.DigestLen], want)
+		}
+	}
+}
+
+func TestTip5SpongeMatchesHashTip5(t *testing.T) {
+	input := make([]field.Element, 2*Rate+7)
+	for i := range input {
+		input[i] = field.New(uint64(i) * 0x9E3779B97F4A7C15)
+	}
+	for _, domain := range []Domain{VariableLength, FixedLength} {
+		sponge := NewTip5Sponge(domain)
+		reference := hash.New(domain)
+		sponge.PadAndAbsorbAll(input)
+		reference.PadAndAbsorbAll(input)
+		for i := 0; i < 3; i++ {
+			if got, want := sponge.Squeeze(), reference.Squeeze(); got != want {
+				t.Fatalf("%s: squeeze %d = %v, want %v", domain, i, got, want)
+			}
+		}
+	}
+}
+
+func TestTip5SpongeResetRestoresDomainState(t *testing.T) {
+	var chunk [Rate]field.Element
+	chunk[0] = field.One
+	for _, domain := range []Domain{VariableLength, FixedLength} {
+		fresh := NewTip5Sponge(domain)
+		sponge := NewTip5Sponge(domain)
+		sponge.Absorb(chunk)
+		sponge.Reset()
+		if sponge.state != fresh.state {
+			t.Errorf("%s: reset state %v, want %v", domain, sponge.state, fresh.state)
+		}
+	}
+	if NewTip5Sponge(VariableLength).state == NewTip5Sponge(FixedLength).state {
+		t.Error("domains share an initial state")
+	}
+}
+
+func randomElements(rng *rand.Rand, n int) []field.Element {
+	elements := make([]field.Element, n)
+	for i := range elements {
+		elements[i] = field.New(rng.Uint64())
+	}
+	return elements
+}
+
+func TestArionSpongeInit(t *testing.T) {
+	sponge := NewArionSponge(VariableLength)
+	newSponge := sponge.Init()
+	if newSponge == nil {
+		t.Fatal("Init() returned nil")
+	}
+	if newSponge == Sponge(sponge) {
+		t.Error("Init() returned the same instance")
+	}
+	if _, ok := newSponge.(*ArionSponge); !ok {
+		t.Error("Init() returned wrong type")
+	}
+}
+
+func TestArionSpongeMatchesHashArion(t *testing.T) {
+	rng := rand.New(rand.NewSource(1285))
+	input := randomElements(rng, 2*Rate+7)
+	for _, domain := range []Domain{VariableLength, FixedLength} {
+		sponge := NewArionSponge(domain)
+		reference := hash.NewArion(domain)
+
+		var chunk [Rate]field.Element
+		copy(chunk[:], input)
+		sponge.Absorb(chunk)
+		for i := 0; i < Rate; i += hash.ArionRate {
+			reference.Absorb(input[i : i+hash.ArionRate])
+		}
+
+		squeezed := sponge.Squeeze()
+		for i := 0; i < Rate; i += hash.ArionRate {
+			want := reference.SqueezeRate()
+			if !reflect.DeepEqual(squeezed[i:i+hash.ArionRate], want) {
+				t.Fatalf("%s: squeeze[%d:] = %v, want %v", domain, i, squeezed[i:i+hash.ArionRate], want)
+			}
+		}
+	}
+}
+
+func TestArionSpongePadAndAbsorbAll(t *testing.T) {
+	rng := rand.New(rand.NewSource(1285))
+	input := randomElements(rng, 2*Rate+3)
+	seen := make(map[[Rate]field.Element]int)
+	for n := 0; n <= len(input); n++ {
+		sponge := NewArionSponge(VariableLength)
+		sponge.PadAndAbsorbAll(input[:n])
+
+		// Padding by hand and absorbing the blocks gives the same state
+		padded := append(append([]field.Element(nil), input[:n]...), field.One)
+		for len(padded)%hash.ArionRate != 0 {
+			padded = append(padded, field.Zero)
+		}
+		reference := hash.NewArion(VariableLength)
+		for i := 0; i < len(padded); i += hash.ArionRate {
+			reference.Absorb(padded[i : i+hash.ArionRate])
+		}
+
+		got := sponge.Squeeze()
+		if !reflect.DeepEqual(got[:hash.ArionRate], reference.SqueezeRate()) {
+			t.Errorf("length %d: sponge disagrees with padded absorption", n)
+		}
+		if m, ok := seen[got]; ok {
+			t.Errorf("lengths %d and %d squeeze the same output", m, n)
+		}
+		seen[got] = n
+	}
+}
+
+func TestArionSpongeClone(t *testing.T) {
+	var chunk [Rate]field.Element
+	for i := range chunk {
+		chunk[i] = field.New(uint64(i + 1))
+	}
+	sponge := NewArionSponge(VariableLength)
+	sponge.Absorb(chunk)
+
+	clone := sponge.Clone()
+	if _, ok := clone.(*ArionSponge); !ok {
+		t.Fatal("Clone() returned wrong type")
+	}
+
+	// Absorbing into the original must not disturb the clone
+	sponge.Absorb(chunk)
+	want := sponge.Init()
+	want.Absorb(chunk)
+	if got, want := clone.Squeeze(), want.Squeeze(); got != want {
+		t.Errorf("clone squeezed %v, want %v", got, want)
+	}
+}
+
+func TestArionSpongeResetRestoresDomainState(t *testing.T) {
+	var chunk [Rate]field.Element
+	chunk[0] = field.One
+	for _, domain := range []Domain{VariableLength, FixedLength} {
+		fresh := NewArionSponge(domain)
+		sponge := NewArionSponge(domain)
+		sponge.Absorb(chunk)
+		sponge.Reset()
+		if got, want := sponge.Squeeze(), fresh.Squeeze(); got != want {
+			t.Errorf("%s: reset sponge squeezed %v, want %v", domain, got, want)
+		}
+	}
+	if NewArionSponge(VariableLength).Squeeze() == NewArionSponge(FixedLength).Squeeze() {
+		t.Error("domains share an initial state")
+	}
+}
+
+func TestArionSpongeHashVarlen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1285))
+	input := randomElements(rng, 3*Rate+1)
+	first := HashVarlen(NewArionSponge(VariableLength), input)
+	second := HashVarlen(NewArionSponge(VariableLength), input)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("HashVarlen is not deterministic: %v, %v", first, second)
+	}
+	if other := HashVarlen(NewArionSponge(VariableLength), input[1:]); reflect.DeepEqual(first, other) {
+		t.Error("different inputs give the same output")
+	}
+	if tip5 := HashVarlen(NewTip5Sponge(VariableLength), input); reflect.DeepEqual(first, tip5) {
+		t.Error("Arion and Tip5 sponges give the same output")
+	}
+}
+
 func TestHashVarlen(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -300,82 +484,177 @@ func TestHashFixedPanic(t *testing.T) {
 
 func TestSampleIndices(t *testing.T) {
 	tests := []struct {
-		name        string
-		upperBound  int
-		numIndices  int
-		expectEmpty bool
+		name       string
+		upperBound int
+		numIndices int
 	}{
-		{
-			name:        "Zero upper bound",
-			upperBound:  0,
-			numIndices:  5,
-			expectEmpty: true,
-		},
-		{
-			name:        "Zero num indices",
-			upperBound:  10,
-			numIndices:  0,
-			expectEmpty: true,
-		},
-		{
-			name:        "Normal case",
-			upperBound:  10,
-			numIndices:  5,
-			expectEmpty: false,
-		},
-		{
-			name:        "All indices",
-			upperBound:  5,
-			numIndices:  5,
-			expectEmpty: false,
-		},
-		{
-			name:        "More indices than upper bound",
-			upperBound:  3,
-			numIndices:  10,
-			expectEmpty: false,
-		},
+		{"Zero num indices", 16, 0},
+		{"Negative num indices", 16, -1},
+		{"Bound one", 1, 5},
+		{"Normal case", 16, 5},
+		{"More indices than upper bound", 4, 25},
+		{"Bound 2^30", 1 << 30, 12},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sponge := NewTip5Sponge(VariableLength)
-
 			indices := SampleIndices(sponge, tt.upperBound, tt.numIndices)
 
-			if tt.expectEmpty {
-				if len(indices) != 0 {
-					t.Errorf("SampleIndices() returned %d indices, want 0", len(indices))
-				}
-				return
-			}
-
-			// Verify correct number of indices
 			expectedCount := tt.numIndices
-			if tt.upperBound < tt.numIndices {
-				expectedCount = tt.upperBound
+			if expectedCount < 0 {
+				expectedCount = 0
 			}
-
 			if len(indices) != expectedCount {
 				t.Errorf("SampleIndices() returned %d indices, want %d", len(indices), expectedCount)
 			}
-
-			// Verify all indices are in range
 			for _, index := range indices {
 				if index < 0 || index >= tt.upperBound {
 					t.Errorf("SampleIndices() returned index %d, out of range [0, %d)", index, tt.upperBound)
 				}
 			}
+		})
+	}
+}
 
-			// Verify no duplicates
-			seen := make(map[int]bool)
-			for _, index := range indices {
-				if seen[index] {
-					t.Errorf("SampleIndices() returned duplicate index %d", index)
+func TestSampleIndicesRejectsInvalidBounds(t *testing.T) {
+	for _, upperBound := range []int{0, -4, 3, 10, 1000, 1<<30 + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("upper bound %d accepted", upperBound)
 				}
-				seen[index] = true
+			}()
+			SampleIndices(NewTip5Sponge(VariableLength), upperBound, 1)
+		}()
+	}
+}
+
+func TestSampleIndicesMatchesTip5(t *testing.T) {
+	input := []field.Element{field.New(1261)}
+	for _, n := range []int{1, 9, 10, 11, 37} {
+		sponge := NewTip5Sponge(VariableLength)
+		sponge.PadAndAbsorbAll(input)
+		reference := hash.New(VariableLength)
+		reference.PadAndAbsorbAll(input)
+
+		got := SampleIndices(sponge, 1<<20, n)
+		for i, want := range reference.SampleIndices(1<<20, n) {
+			if got[i] != int(want) {
+				t.Errorf("n = %d: index %d is %d, want %d", n, i, got[i], want)
 			}
-		})
+		}
+	}
+}
+
+// biasedSponge squeezes field.Max with probability 1/4 and a uniform
+// element otherwise. The low 32 bits of field.Max are zero, so without
+// rejection index 0 would be drawn far more often than the others.
+type biasedSponge struct {
+	Sponge
+	rng      *rand.Rand
+	maxCount int
+}
+
+func (s *biasedSponge) Squeeze() [Rate]field.Element {
+	var block [Rate]field.Element
+	for i := range block {
+		if s.rng.Intn(4) == 0 {
+			block[i] = field.Max
+			s.maxCount++
+		} else {
+			block[i] = field.New(s.rng.Uint64() % field.P)
+		}
+	}
+	return block
+}
+
+func TestSampleIndicesRejectsMax(t *testing.T) {
+	const (
+		upperBound = 16
+		samples    = 8000
+	)
+	sponge := &biasedSponge{rng: rand.New(rand.NewSource(1261))}
+	indices := SampleIndices(sponge, upperBound, samples)
+
+	var buckets [upperBound]int
+	for _, index := range indices {
+		buckets[index]++
+	}
+	if sponge.maxCount == 0 {
+		t.Fatal("the sponge squeezed no field.Max")
+	}
+	// Uniform buckets hold 500 samples; accepting field.Max as index 0 would
+	// put about 2375 samples in bucket 0.
+	for i, count := range buckets {
+		if count < 380 || count > 620 {
+			t.Errorf("bucket %d holds %d of %d samples", i, count, samples)
+		}
+	}
+}
+
+// countingSponge counts the squeezes of the wrapped sponge.
+type countingSponge struct {
+	Sponge
+	squeezes int
+}
+
+func (s *countingSponge) Squeeze() [Rate]field.Element {
+	s.squeezes++
+	return s.Sponge.Squeeze()
+}
+
+func TestSampleScalarsSqueezeCount(t *testing.T) {
+	for n := -1; n <= 25; n++ {
+		sponge := &countingSponge{Sponge: NewTip5Sponge(VariableLength)}
+		scalars := SampleScalars(sponge, n)
+
+		wantLen, wantSqueezes := 0, 0
+		if n > 0 {
+			wantLen, wantSqueezes = n, (3*n+Rate-1)/Rate
+		}
+		if len(scalars) != wantLen {
+			t.Errorf("n = %d: got %d scalars", n, len(scalars))
+		}
+		if sponge.squeezes != wantSqueezes {
+			t.Errorf("n = %d: %d squeezes, want %d", n, sponge.squeezes, wantSqueezes)
+		}
+	}
+}
+
+func TestSampleScalarsMatchesTip5(t *testing.T) {
+	input := []field.Element{field.New(1260), field.New(2), field.New(3)}
+	for _, n := range []int{1, 3, 4, 10, 17} {
+		sponge := NewTip5Sponge(VariableLength)
+		sponge.PadAndAbsorbAll(input)
+		reference := hash.New(VariableLength)
+		reference.PadAndAbsorbAll(input)
+
+		want, err := reference.SampleScalars(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := SampleScalars(sponge, n); !reflect.DeepEqual(got, want) {
+			t.Errorf("n = %d: got %v, want %v", n, got, want)
+		}
+		// Both sponges discard the same leftover elements
+		if sponge.Squeeze() != reference.Squeeze() {
+			t.Errorf("n = %d: sponges differ after sampling", n)
+		}
+	}
+}
+
+func TestSampleScalarsSpillsAcrossSqueezes(t *testing.T) {
+	sponge := NewTip5Sponge(VariableLength)
+	blocks := sponge.Clone()
+	first, second := blocks.Squeeze(), blocks.Squeeze()
+
+	// Scalar 3 takes the last element of the first block and the first two
+	// of the second
+	scalars := SampleScalars(sponge, 4)
+	want := [3]field.Element{first[Rate-1], second[0], second[1]}
+	if scalars[3].Coefficients != want {
+		t.Errorf("scalar 3 = %v, want coefficients %v", scalars[3], want)
 	}
 }
 
@@ -383,46 +662,102 @@ func TestValidateSpongeInput(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   []field.Element
-		wantErr bool
+		opts    []ValidationOption
+		wantErr error
 	}{
 		{
 			name:    "Empty input",
 			input:   []field.Element{},
-			wantErr: true,
+			wantErr: ErrEmptyInput,
+		},
+		{
+			name:  "Empty input allowed",
+			input: []field.Element{},
+			opts:  []ValidationOption{AllowEmptyInput()},
+		},
+		{
+			name:  "Valid input",
+			input: []field.Element{field.One, field.New(2)},
 		},
 		{
-			name:    "Valid input",
-			input:   []field.Element{field.One, field.New(2)},
-			wantErr: false,
+			name:  "Above the former 1M cap",
+			input: make([]field.Element, 1024*1024+1),
 		},
 		{
 			name:    "Large input",
-			input:   make([]field.Element, 1024*1024+1),
-			wantErr: true,
+			input:   make([]field.Element, 101),
+			opts:    []ValidationOption{WithMaxInputLength(100)},
+			wantErr: ErrInputTooLarge{Length: 101, MaxLength: 100},
 		},
 		{
-			name:    "Maximum valid input",
-			input:   make([]field.Element, 1024*1024),
-			wantErr: false,
+			name:  "Maximum valid input",
+			input: make([]field.Element, 100),
+			opts:  []ValidationOption{WithMaxInputLength(100)},
+		},
+		{
+			name:    "Zero maximum",
+			input:   make([]field.Element, 1),
+			opts:    []ValidationOption{WithMaxInputLength(0), AllowEmptyInput()},
+			wantErr: ErrInputTooLarge{Length: 1, MaxLength: 0},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateSpongeInput(tt.input)
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("ValidateSpongeInput() expected error, got nil")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("ValidateSpongeInput() error = %v", err)
-				}
+			err := ValidateSpongeInput(tt.input, tt.opts...)
+			if err != tt.wantErr {
+				t.Errorf("ValidateSpongeInput() error = %v, want %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestValidateSpongeInputDefaultLimit(t *testing.T) {
+	if err := ValidateSpongeInput(make([]field.Element, DefaultMaxInputLength)); err != nil {
+		t.Errorf("input at the default limit rejected: %v", err)
+	}
+
+	err := ValidateSpongeInput(make([]field.Element, DefaultMaxInputLength+1))
+	var tooLarge ErrInputTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected ErrInputTooLarge, got %v", err)
+	}
+	if tooLarge.Length != DefaultMaxInputLength+1 || tooLarge.MaxLength != DefaultMaxInputLength {
+		t.Errorf("unexpected error sizes: %+v", tooLarge)
+	}
+}
+
+func TestHashVarlenLimited(t *testing.T) {
+	sizes := []int{0, 1, Rate - 1, Rate, Rate + 1, 63, 64, 65}
+	const maxLength = 64
+
+	for _, size := range sizes {
+		input := make([]field.Element, size)
+		for i := range input {
+			input[i] = field.New(uint64(i + 1))
+		}
+
+		validateErr := ValidateSpongeInput(input, AllowEmptyInput(), WithMaxInputLength(maxLength))
+		output, hashErr := HashVarlenLimited(NewTip5Sponge(VariableLength), input, WithMaxInputLength(maxLength))
+
+		if (validateErr == nil) != (hashErr == nil) {
+			t.Errorf("size %d: validator error %v, hash error %v", size, validateErr, hashErr)
+			continue
+		}
+		if hashErr != nil {
+			continue
+		}
+
+		expected := HashVarlen(NewTip5Sponge(VariableLength), input)
+		for i := range expected {
+			if !output[i].Equal(expected[i]) {
+				t.Errorf("size %d: limited hash differs from HashVarlen", size)
+				break
+			}
+		}
+	}
+}
+
 func TestGetSpongeRate(t *testing.T) {
 	rate := GetSpongeRate()
 	if rate != Rate {
@@ -496,6 +831,28 @@ func TestDomainString(t *testing.T) {
 	}
 }
 
+// TestDomainIsHashDomain checks that Domain is an alias of hash.Domain, not
+// a distinct type: functions taking either spelling are interchangeable.
+func TestDomainIsHashDomain(t *testing.T) {
+	var newSponge func(Domain) *Tip5Sponge = NewTip5Sponge
+	var isValid func(hash.Domain) bool = IsValidDomain
+
+	var d Domain = hash.FixedLength
+	var h hash.Domain = d
+	if !isValid(h) || newSponge(h).domain != hash.FixedLength {
+		t.Error("hash.Domain value not accepted as Domain")
+	}
+	if reflect.TypeOf(d) != reflect.TypeOf(h) {
+		t.Errorf("Domain is %v, hash.Domain is %v", reflect.TypeOf(d), reflect.TypeOf(h))
+	}
+	if VariableLength != hash.VariableLength || FixedLength != hash.FixedLength {
+		t.Error("domain constants differ from hash's")
+	}
+	if Rate != hash.Rate {
+		t.Errorf("Rate = %d, hash.Rate = %d", Rate, hash.Rate)
+	}
+}
+
 // Benchmark tests
 func BenchmarkTip5SpongeAbsorb(b *testing.B) {
 	sponge := NewTip5Sponge(VariableLength)
@@ -555,6 +912,6 @@ func BenchmarkSampleIndices(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = SampleIndices(sponge, 1000, 10)
+		_ = SampleIndices(sponge, 1024, 10)
 	}
 }
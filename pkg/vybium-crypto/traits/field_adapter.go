@@ -116,8 +116,8 @@ func (b *BFieldElementAdapter) InverseOrZero() FiniteField {
 
 // PrimitiveRootOfUnity interface implementation
 func (b *BFieldElementAdapter) PrimitiveRootOfUnity(n uint64) (FiniteField, bool) {
-	root := field.PrimitiveRootOfUnity(n)
-	if root.IsZero() {
+	root, err := field.PrimitiveRootOfUnity(n)
+	if err != nil {
 		return nil, false
 	}
 	return &BFieldElementAdapter{Element: root}, true
@@ -155,7 +155,7 @@ func NewBFieldElement(val uint64) FiniteField {
 	return NewBFieldElementAdapter(field.New(val))
 }
 
-func NewXFieldElement(coeffs [3]field.Element) FiniteField {
+func NewXFieldElement(coeffs [xfield.ExtensionDegree]field.Element) FiniteField {
 	return NewXFieldElementAdapter(xfield.New(coeffs))
 }
 
@@ -63,7 +63,7 @@ func (x *XFieldElementAdapter) IsZero() bool {
 }
 
 func (x *XFieldElementAdapter) IsOne() bool {
-	one := xfield.New([3]field.Element{field.One, field.Zero, field.Zero})
+	one := xfield.New([xfield.ExtensionDegree]field.Element{field.One, field.Zero, field.Zero})
 	return x.Element.Equal(one)
 }
 
@@ -95,7 +95,7 @@ func (x *XFieldElementAdapter) ToBigInt() *big.Int {
 func (x *XFieldElementAdapter) FromBigInt(val *big.Int) FiniteField {
 	// Convert big.Int to XFieldElement by treating as constant polynomial
 	// This creates a + 0x + 0x² where a is the big.Int value
-	coeffs := [3]field.Element{
+	coeffs := [xfield.ExtensionDegree]field.Element{
 		field.NewFromBigInt(val),
 		field.Zero,
 		field.Zero,
@@ -117,15 +117,15 @@ func (x *XFieldElementAdapter) ToUint64() uint64 {
 
 func (x *XFieldElementAdapter) FromUint64(val uint64) FiniteField {
 	if val == 0 {
-		zero := xfield.New([3]field.Element{field.Zero, field.Zero, field.Zero})
+		zero := xfield.New([xfield.ExtensionDegree]field.Element{field.Zero, field.Zero, field.Zero})
 		return &XFieldElementAdapter{Element: zero}
 	}
 	if val == 1 {
-		one := xfield.New([3]field.Element{field.One, field.Zero, field.Zero})
+		one := xfield.New([xfield.ExtensionDegree]field.Element{field.One, field.Zero, field.Zero})
 		return &XFieldElementAdapter{Element: one}
 	}
 	// For other values, create constant polynomial
-	coeffs := [3]field.Element{field.New(val), field.Zero, field.Zero}
+	coeffs := [xfield.ExtensionDegree]field.Element{field.New(val), field.Zero, field.Zero}
 	return &XFieldElementAdapter{Element: xfield.New(coeffs)}
 }
 
@@ -144,7 +144,7 @@ func (x *XFieldElementAdapter) UnmarshalJSON(data []byte) error {
 // Inverse interface implementation
 func (x *XFieldElementAdapter) InverseOrZero() FiniteField {
 	if x.IsZero() {
-		zero := xfield.New([3]field.Element{field.Zero, field.Zero, field.Zero})
+		zero := xfield.New([xfield.ExtensionDegree]field.Element{field.Zero, field.Zero, field.Zero})
 		return &XFieldElementAdapter{Element: zero}
 	}
 	return x.Inverse()
@@ -168,7 +168,7 @@ func (x *XFieldElementAdapter) GetCyclicGroupElements(max *uint64) []FiniteField
 	}
 
 	for i := uint64(1); i <= limit; i++ {
-		coeffs := [3]field.Element{field.New(i), field.Zero, field.Zero}
+		coeffs := [xfield.ExtensionDegree]field.Element{field.New(i), field.Zero, field.Zero}
 		elements = append(elements, &XFieldElementAdapter{Element: xfield.New(coeffs)})
 	}
 
@@ -0,0 +1,132 @@
+// Package vrflite provides a lightweight verifiable random function built
+// from Tip5 and Merkle trees, for leader election and similar lotteries
+// where a SNARK-based VRF is unnecessary.
+//
+// A seed holder draws N secret shares, N a power of two, commits to them in
+// a Merkle tree and publishes its root. In round r it reveals share
+// i = r mod N together with its authentication path; the round's random
+// output is
+//
+//	hash.HashVarlenDomain(OutputDomain, share ‖ bfieldcodec.EncodeUint64(r))
+//
+// Anyone holding the root checks the index, the path and the output with
+// Verify. The seed holder cannot choose outputs after committing, and
+// outputs of rounds whose shares are unrevealed stay unpredictable.
+//
+// Leaf i of the tree is ShareLeaf(share i), a domain-separated hash of the
+// share, rather than the share itself, so that the sibling digests in an
+// authentication path do not reveal the neighbouring shares.
+//
+// Shares are reused: round r+N reveals the share of round r. Once share i is
+// revealed, the outputs of all later rounds congruent to i modulo N are
+// public knowledge, so a commitment must be replaced before round r reaches
+// N if outputs are to stay unpredictable.
+package vrflite
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/merkle"
+)
+
+// Domain labels. They are part of the construction; changing either
+// changes every root or output.
+const (
+	// ShareLeafDomain is the label under which shares are hashed into
+	// Merkle leafs.
+	ShareLeafDomain = "vybium/vrflite/share-leaf"
+
+	// OutputDomain is the label under which round outputs are derived.
+	OutputDomain = "vybium/vrflite/output"
+)
+
+// SeedTree is a seed holder's commitment: the shares and the Merkle tree
+// over their leafs. It must be kept secret; only its root is published.
+type SeedTree struct {
+	shares []hash.Digest
+	tree   *merkle.MerkleTree
+}
+
+// VrfProof shows that an output was derived from a committed share.
+type VrfProof struct {
+	// Share is the revealed share.
+	Share hash.Digest
+	// Index is the share's leaf index, the round modulo the number of shares.
+	Index uint64
+	// AuthPath is the authentication path of the share's leaf.
+	AuthPath []hash.Digest
+}
+
+// ShareLeaf returns the Merkle leaf committing to a share.
+func ShareLeaf(share hash.Digest) hash.Digest {
+	return hash.HashVarlenDomain(ShareLeafDomain, share[:])
+}
+
+// Commit commits to the shares, returning the seed tree and its root.
+// Returns an error unless the number of shares is a power of two.
+func Commit(shares []hash.Digest) (*SeedTree, hash.Digest, error) {
+	leafs := make([]hash.Digest, len(shares))
+	for i, share := range shares {
+		leafs[i] = ShareLeaf(share)
+	}
+	tree, err := merkle.New(leafs)
+	if err != nil {
+		return nil, hash.Digest{}, fmt.Errorf("committing to %d shares: %w", len(shares), err)
+	}
+	return &SeedTree{shares: append([]hash.Digest(nil), shares...), tree: tree}, tree.Root(), nil
+}
+
+// Root returns the published commitment.
+func (s *SeedTree) Root() hash.Digest {
+	return s.tree.Root()
+}
+
+// NumShares returns the number of committed shares.
+func (s *SeedTree) NumShares() uint64 {
+	return uint64(len(s.shares))
+}
+
+// Evaluate returns the output of the given round for the share revealed in
+// it.
+func Evaluate(share hash.Digest, round uint64) hash.Digest {
+	input := make([]field.Element, 0, hash.DigestLen+2)
+	input = append(input, share[:]...)
+	input = append(input, bfieldcodec.EncodeUint64(round)...)
+	return hash.HashVarlenDomain(OutputDomain, input)
+}
+
+// Prove returns the output of the given round and the proof revealing its
+// share.
+func Prove(tree *SeedTree, round uint64) (hash.Digest, *VrfProof, error) {
+	index := round % tree.NumShares()
+	path, err := tree.tree.AuthenticationPath(index)
+	if err != nil {
+		return hash.Digest{}, nil, err
+	}
+	share := tree.shares[index]
+	return Evaluate(share, round), &VrfProof{Share: share, Index: index, AuthPath: path}, nil
+}
+
+// Verify reports whether output is the output of the given round under the
+// commitment root to numShares shares: the proof's index is round mod
+// numShares, its share's leaf is at that index under root, and the output
+// is Evaluate(share, round).
+func Verify(root hash.Digest, numShares uint64, round uint64, output hash.Digest, proof *VrfProof) bool {
+	if proof == nil || numShares == 0 || numShares&(numShares-1) != 0 {
+		return false
+	}
+	if proof.Index != round%numShares {
+		return false
+	}
+	if len(proof.AuthPath) != bits.TrailingZeros64(numShares) {
+		return false
+	}
+	if !merkle.VerifyInclusionProof(root, proof.Index, ShareLeaf(proof.Share), proof.AuthPath) {
+		return false
+	}
+	return Evaluate(proof.Share, round).Equal(output)
+}
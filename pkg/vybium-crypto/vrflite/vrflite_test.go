@@ -0,0 +1,247 @@
+package vrflite
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/hash"
+)
+
+// testShares returns n shares with fixed, distinct elements.
+func testShares(n int) []hash.Digest {
+	shares := make([]hash.Digest, n)
+	for i := range shares {
+		for j := range shares[i] {
+			shares[i][j] = field.New(uint64(1000*i + j + 1))
+		}
+	}
+	return shares
+}
+
+func randomShares(rng *rand.Rand, n int) []hash.Digest {
+	shares := make([]hash.Digest, n)
+	for i := range shares {
+		for j := range shares[i] {
+			shares[i][j] = field.New(rng.Uint64())
+		}
+	}
+	return shares
+}
+
+func mustCommit(t *testing.T, shares []hash.Digest) (*SeedTree, hash.Digest) {
+	t.Helper()
+	tree, root, err := Commit(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree, root
+}
+
+func mustProve(t *testing.T, tree *SeedTree, round uint64) (hash.Digest, *VrfProof) {
+	t.Helper()
+	output, proof, err := Prove(tree, round)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output, proof
+}
+
+func TestProveVerify(t *testing.T) {
+	rng := rand.New(rand.NewSource(1194))
+	for _, n := range []int{1, 2, 8, 64} {
+		tree, root := mustCommit(t, randomShares(rng, n))
+		for k := 0; k < 20; k++ {
+			round := rng.Uint64()
+			output, proof := mustProve(t, tree, round)
+			if !Verify(root, uint64(n), round, output, proof) {
+				t.Fatalf("%d shares, round %d: valid proof rejected", n, round)
+			}
+		}
+	}
+}
+
+func TestCommitRejectsInvalidShareCounts(t *testing.T) {
+	for _, n := range []int{0, 3, 12} {
+		if _, _, err := Commit(testShares(n)); err == nil {
+			t.Errorf("%d shares: expected an error", n)
+		}
+	}
+}
+
+func TestCommitCopiesShares(t *testing.T) {
+	shares := testShares(4)
+	tree, root := mustCommit(t, shares)
+	shares[1] = hash.ZeroDigest()
+	output, proof := mustProve(t, tree, 1)
+	if !Verify(root, 4, 1, output, proof) {
+		t.Fatal("modifying the caller's shares changed the seed tree")
+	}
+}
+
+func TestAuthPathHidesSiblingShares(t *testing.T) {
+	shares := testShares(2)
+	tree, _ := mustCommit(t, shares)
+	_, proof := mustProve(t, tree, 0)
+	if proof.AuthPath[0].Equal(shares[1]) {
+		t.Fatal("authentication path reveals the sibling share")
+	}
+	if !proof.AuthPath[0].Equal(ShareLeaf(shares[1])) {
+		t.Fatal("sibling is not the hashed share")
+	}
+}
+
+// TestWrapAround checks that rounds beyond the number of shares reuse the
+// share of their residue, with outputs still bound to the round.
+func TestWrapAround(t *testing.T) {
+	const n = 8
+	tree, root := mustCommit(t, testShares(n))
+	for _, round := range []uint64{3, 3 + n, 3 + 5*n, math.MaxUint64} {
+		output, proof := mustProve(t, tree, round)
+		if proof.Index != round%n {
+			t.Fatalf("round %d: index %d", round, proof.Index)
+		}
+		if !Verify(root, n, round, output, proof) {
+			t.Fatalf("round %d: valid proof rejected", round)
+		}
+	}
+
+	first, firstProof := mustProve(t, tree, 3)
+	later, laterProof := mustProve(t, tree, 3+n)
+	if firstProof.Share != laterProof.Share {
+		t.Fatal("rounds congruent modulo the share count reveal different shares")
+	}
+	if first.Equal(later) {
+		t.Fatal("rounds sharing a share gave the same output")
+	}
+	// Anyone who has seen the share of round 3 can compute round 3+n.
+	if !Evaluate(firstProof.Share, 3+n).Equal(later) {
+		t.Fatal("later output is not predictable from the revealed share")
+	}
+	// A proof for one round does not carry over to the next wrap.
+	if Verify(root, n, 3+n, first, firstProof) {
+		t.Fatal("proof of round 3 accepted for round 3+n")
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	const n, round = 8, 13
+	tree, root := mustCommit(t, testShares(n))
+	output, proof := mustProve(t, tree, round)
+
+	clone := func() *VrfProof {
+		return &VrfProof{Share: proof.Share, Index: proof.Index, AuthPath: append([]hash.Digest(nil), proof.AuthPath...)}
+	}
+	tampered := map[string]*VrfProof{}
+	for i := range proof.Share {
+		p := clone()
+		p.Share[i] = p.Share[i].Add(field.One)
+		tampered[fmt.Sprintf("share element %d", i)] = p
+	}
+	// The unreduced round and both neighbouring indices.
+	for _, index := range []uint64{round, round%n + 1, round%n - 1} {
+		p := clone()
+		p.Index = index
+		tampered[fmt.Sprintf("index %d", index)] = p
+	}
+	for i := range proof.AuthPath {
+		p := clone()
+		p.AuthPath[i][0] = p.AuthPath[i][0].Add(field.One)
+		tampered[fmt.Sprintf("path node %d", i)] = p
+	}
+	short := clone()
+	short.AuthPath = short.AuthPath[:len(short.AuthPath)-1]
+	tampered["short path"] = short
+	long := clone()
+	long.AuthPath = append(long.AuthPath, hash.ZeroDigest())
+	tampered["long path"] = long
+	swapped := clone()
+	swapped.AuthPath[0], swapped.AuthPath[1] = swapped.AuthPath[1], swapped.AuthPath[0]
+	tampered["swapped path nodes"] = swapped
+	neighbour := clone()
+	_, other := mustProve(t, tree, round+1)
+	neighbour.Share = other.Share
+	tampered["neighbouring share"] = neighbour
+
+	for name, p := range tampered {
+		if Verify(root, n, round, output, p) {
+			t.Errorf("%s: tampered proof accepted", name)
+		}
+	}
+
+	otherOutput := output
+	otherOutput[2] = otherOutput[2].Add(field.One)
+	otherRoot := root
+	otherRoot[0] = otherRoot[0].Add(field.One)
+	for name, ok := range map[string]bool{
+		"output":           Verify(root, n, round, otherOutput, proof),
+		"round":            Verify(root, n, round+1, output, proof),
+		"wrapped round":    Verify(root, n, round+n, output, proof),
+		"root":             Verify(otherRoot, n, round, output, proof),
+		"smaller N":        Verify(root, n/2, round, output, proof),
+		"larger N":         Verify(root, 2*n, round, output, proof),
+		"N not power of 2": Verify(root, n+1, round, output, proof),
+		"zero N":           Verify(root, 0, round, output, proof),
+		"nil proof":        Verify(root, n, round, output, nil),
+	} {
+		if ok {
+			t.Errorf("tampered %s accepted", name)
+		}
+	}
+}
+
+type vector struct {
+	Round  uint64 `json:"round"`
+	Index  uint64 `json:"index"`
+	Output string `json:"output"`
+}
+
+type vectorFile struct {
+	NumShares int      `json:"num_shares"`
+	Shares    []string `json:"shares"`
+	Root      string   `json:"root"`
+	Vectors   []vector `json:"vectors"`
+}
+
+func TestVectors(t *testing.T) {
+	if hash.WeakHashingEnabled {
+		t.Skip("golden digests require full-round Tip5")
+	}
+	data, err := os.ReadFile("testdata/vrflite_vectors.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var fixture vectorFile
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	shares := testShares(fixture.NumShares)
+	for i, want := range fixture.Shares {
+		if got := shares[i].Hex(); got != want {
+			t.Fatalf("share %d: got %s, want %s", i, got, want)
+		}
+	}
+	tree, root := mustCommit(t, shares)
+	if got := root.Hex(); got != fixture.Root {
+		t.Fatalf("root: got %s, want %s", got, fixture.Root)
+	}
+	if len(fixture.Vectors) == 0 {
+		t.Fatal("fixture has no vectors")
+	}
+	for _, v := range fixture.Vectors {
+		output, proof := mustProve(t, tree, v.Round)
+		if proof.Index != v.Index {
+			t.Errorf("round %d: index %d, want %d", v.Round, proof.Index, v.Index)
+		}
+		if got := output.Hex(); got != v.Output {
+			t.Errorf("round %d: output %s, want %s", v.Round, got, v.Output)
+		}
+		if !Verify(root, uint64(fixture.NumShares), v.Round, output, proof) {
+			t.Errorf("round %d: proof rejected", v.Round)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package xfield
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// BatchAdd returns the element-wise sums a[i] + b[i] in a new slice.
+// Panics if a and b differ in length.
+func BatchAdd(a, b []XFieldElement) []XFieldElement {
+	checkBatchLengths("add", a, b)
+	sums := make([]XFieldElement, len(a))
+	for i := range a {
+		sums[i] = a[i].Add(b[i])
+	}
+	return sums
+}
+
+// BatchMul returns the element-wise products a[i] · b[i] in a new slice.
+// Panics if a and b differ in length.
+func BatchMul(a, b []XFieldElement) []XFieldElement {
+	checkBatchLengths("multiply", a, b)
+	products := make([]XFieldElement, len(a))
+	for i := range a {
+		products[i] = a[i].Mul(b[i])
+	}
+	return products
+}
+
+// BatchInverse returns the inverses of the elements in a new slice, leaving
+// the input unchanged. As with field.BatchInverse, zero elements have no
+// inverse and their outputs are Zero.
+//
+// Each inverse is a cofactor x^p·x^{p²} divided by the base field norm, as in
+// Inverse; the norms are inverted together by field.BatchInverse, leaving one
+// base field multiplication per coefficient.
+func BatchInverse(elements []XFieldElement) []XFieldElement {
+	cofactors := make([]XFieldElement, len(elements))
+	norms := make([]field.Element, len(elements))
+	for i, x := range elements {
+		conjugates := x.Conjugates()
+		cofactors[i] = conjugates[1].Mul(conjugates[2])
+		norms[i] = x.Mul(cofactors[i]).Coefficients[0]
+	}
+
+	normInverses := field.BatchInverse(norms)
+	for i := range cofactors {
+		cofactors[i] = cofactors[i].MulConst(normInverses[i])
+	}
+	return cofactors
+}
+
+func checkBatchLengths(operation string, a, b []XFieldElement) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("cannot %s batches of %d and %d elements", operation, len(a), len(b)))
+	}
+}
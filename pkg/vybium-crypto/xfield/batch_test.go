@@ -0,0 +1,91 @@
+package xfield
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomXFieldElements(rng *rand.Rand, n int) []XFieldElement {
+	elements := make([]XFieldElement, n)
+	for i := range elements {
+		elements[i] = randomXFieldElement(rng)
+	}
+	return elements
+}
+
+func TestBatchOperationsMatchElementwise(t *testing.T) {
+	rng := rand.New(rand.NewSource(1273))
+	for _, n := range []int{0, 1, 2, 17, 256} {
+		a, b := randomXFieldElements(rng, n), randomXFieldElements(rng, n)
+		sums, products, inverses := BatchAdd(a, b), BatchMul(a, b), BatchInverse(a)
+		if len(sums) != n || len(products) != n || len(inverses) != n {
+			t.Fatalf("n=%d: got lengths %d, %d, %d", n, len(sums), len(products), len(inverses))
+		}
+		for i := range a {
+			if !sums[i].Equal(a[i].Add(b[i])) {
+				t.Errorf("n=%d: sum %d is %v, want %v", n, i, sums[i], a[i].Add(b[i]))
+			}
+			if !products[i].Equal(a[i].Mul(b[i])) {
+				t.Errorf("n=%d: product %d is %v, want %v", n, i, products[i], a[i].Mul(b[i]))
+			}
+			if !inverses[i].Equal(a[i].Inverse()) {
+				t.Errorf("n=%d: inverse %d is %v, want %v", n, i, inverses[i], a[i].Inverse())
+			}
+		}
+	}
+}
+
+func TestBatchInverseZeros(t *testing.T) {
+	rng := rand.New(rand.NewSource(1273))
+	elements := randomXFieldElements(rng, 8)
+	elements[0], elements[5] = Zero, Zero
+	input := append([]XFieldElement(nil), elements...)
+
+	inverses := BatchInverse(elements)
+	for i, x := range elements {
+		if !x.Equal(input[i]) {
+			t.Fatalf("BatchInverse modified its input at %d", i)
+		}
+		if x.IsZero() {
+			if !inverses[i].IsZero() {
+				t.Errorf("inverse %d of zero is %v, want zero", i, inverses[i])
+			}
+		} else if !x.Mul(inverses[i]).IsOne() {
+			t.Errorf("x · x⁻¹ ≠ 1 at %d", i)
+		}
+	}
+}
+
+func TestBatchLengthMismatchPanics(t *testing.T) {
+	a, b := []XFieldElement{One, One}, []XFieldElement{One}
+	for name, batch := range map[string]func(a, b []XFieldElement) []XFieldElement{"BatchAdd": BatchAdd, "BatchMul": BatchMul} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s of 2 and 1 elements did not panic", name)
+				}
+			}()
+			batch(a, b)
+		}()
+	}
+}
+
+func BenchmarkBatchInverse(b *testing.B) {
+	elements := randomXFieldElements(rand.New(rand.NewSource(1273)), 1024)
+
+	b.Run("Batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = BatchInverse(elements)
+		}
+	})
+	b.Run("Elementwise", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			inverses := make([]XFieldElement, len(elements))
+			for j, x := range elements {
+				inverses[j] = x.Inverse()
+			}
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package xfield
+
+import (
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+)
+
+// RandomLinearCombination computes Σ αⁱ·polys[i] for base-field polynomials
+// and an extension-field challenge α, as used for DEEP-style batching.
+//
+// The combination is a polynomial over F_p^3. It is returned as three
+// base-field polynomials [q₀, q₁, q₂] such that the combination equals
+// q₀ + q₁·x + q₂·x², where x is the extension generator. Coefficient j of the
+// combination is therefore XFieldElement{q₀[j], q₁[j], q₂[j]}.
+//
+// As with polynomial.RandomLinearCombination, the three outputs are the only
+// allocations and each input costs one pass over its own coefficients.
+//
+// Production implementation.
+func RandomLinearCombination(polys []*polynomial.Polynomial, alpha XFieldElement) [ExtensionDegree]*polynomial.Polynomial {
+	maxLen := 0
+	for _, p := range polys {
+		if n := p.Degree() + 1; n > maxLen {
+			maxLen = n
+		}
+	}
+
+	var coeffs [ExtensionDegree][]field.Element
+	for m := range coeffs {
+		coeffs[m] = make([]field.Element, maxLen)
+	}
+
+	weight := One
+	for _, p := range polys {
+		w0, w1, w2 := weight.Coefficients[0], weight.Coefficients[1], weight.Coefficients[2]
+		for i, n := 0, p.Degree()+1; i < n; i++ {
+			c := p.Coefficient(i)
+			coeffs[0][i] = coeffs[0][i].Add(w0.Mul(c))
+			coeffs[1][i] = coeffs[1][i].Add(w1.Mul(c))
+			coeffs[2][i] = coeffs[2][i].Add(w2.Mul(c))
+		}
+		weight = weight.Mul(alpha)
+	}
+
+	var result [ExtensionDegree]*polynomial.Polynomial
+	for m := range result {
+		result[m] = polynomial.New(coeffs[m])
+	}
+	return result
+}
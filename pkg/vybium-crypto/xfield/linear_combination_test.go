@@ -0,0 +1,82 @@
+package xfield
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+)
+
+func TestRandomLinearCombination(t *testing.T) {
+	rng := rand.New(rand.NewSource(1152))
+
+	tests := []struct {
+		name    string
+		lengths []int
+	}{
+		{"empty", nil},
+		{"single", []int{9}},
+		{"wildly different degrees", []int{3, 500, 0, 1, 64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polys := make([]*polynomial.Polynomial, len(tt.lengths))
+			for i, n := range tt.lengths {
+				coeffs := make([]field.Element, n)
+				for j := range coeffs {
+					coeffs[j] = field.New(rng.Uint64())
+				}
+				polys[i] = polynomial.New(coeffs)
+			}
+			alpha := New([ExtensionDegree]field.Element{
+				field.New(rng.Uint64()), field.New(rng.Uint64()), field.New(rng.Uint64()),
+			})
+
+			result := RandomLinearCombination(polys, alpha)
+
+			// Compare coefficient-wise against the naive extension-field sum
+			maxLen := 0
+			for _, p := range polys {
+				if n := p.Degree() + 1; n > maxLen {
+					maxLen = n
+				}
+			}
+			for j := 0; j < maxLen+1; j++ {
+				want := Zero
+				weight := One
+				for _, p := range polys {
+					if j <= p.Degree() {
+						want = want.Add(weight.MulConst(p.Coefficients()[j]))
+					}
+					weight = weight.Mul(alpha)
+				}
+
+				var got XFieldElement
+				for m := 0; m < ExtensionDegree; m++ {
+					if j <= result[m].Degree() {
+						got.Coefficients[m] = result[m].Coefficients()[j]
+					}
+				}
+				if !got.Equal(want) {
+					t.Fatalf("coefficient %d: got %v, want %v", j, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomLinearCombinationBaseFieldChallenge(t *testing.T) {
+	// With α in the base field the result must match the base-field helper
+	p := polynomial.New([]field.Element{field.New(1), field.New(2), field.New(3)})
+	q := polynomial.New([]field.Element{field.New(4)})
+	alpha := field.New(7)
+
+	result := RandomLinearCombination([]*polynomial.Polynomial{p, q}, NewConst(alpha))
+	want := polynomial.RandomLinearCombination([]*polynomial.Polynomial{p, q}, alpha)
+
+	if !result[0].Equal(want) || !result[1].IsZero() || !result[2].IsZero() {
+		t.Error("base-field challenge should yield a base-field combination")
+	}
+}
@@ -0,0 +1,38 @@
+package xfield
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+// Random returns a uniformly distributed element, reading its three
+// coefficients from r with field.RandomSlice, or from crypto/rand.Reader if
+// r is nil. Returns an error if field.RandomSlice fails.
+func Random(r io.Reader) (XFieldElement, error) {
+	coefficients, err := field.RandomSlice(r, ExtensionDegree)
+	if err != nil {
+		return Zero, fmt.Errorf("random extension field element: %w", err)
+	}
+	return New([ExtensionDegree]field.Element(coefficients)), nil
+}
+
+// RandomSlice returns n uniformly distributed elements, reading their 3n
+// coefficients from r at once as for field.RandomSlice, or from
+// crypto/rand.Reader if r is nil. Returns an error if n is negative or
+// field.RandomSlice fails.
+func RandomSlice(r io.Reader, n int) ([]XFieldElement, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("random extension field elements: negative count %d", n)
+	}
+	coefficients, err := field.RandomSlice(r, ExtensionDegree*n)
+	if err != nil {
+		return nil, fmt.Errorf("random extension field elements: %w", err)
+	}
+	elements := make([]XFieldElement, n)
+	for i := range elements {
+		elements[i] = New([ExtensionDegree]field.Element(coefficients[ExtensionDegree*i:]))
+	}
+	return elements, nil
+}
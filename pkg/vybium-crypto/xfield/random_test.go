@@ -0,0 +1,78 @@
+package xfield
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+)
+
+func TestRandomCoefficientsAreCanonical(t *testing.T) {
+	elements, err := RandomSlice(rand.New(rand.NewSource(1274)), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[XFieldElement]bool)
+	for _, x := range elements {
+		for j, c := range x.Coefficients {
+			if c.Value() >= field.P {
+				t.Fatalf("coefficient %d of %v is not below P", j, x)
+			}
+		}
+		seen[x] = true
+	}
+	if len(seen) != len(elements) {
+		t.Errorf("%d random elements contain only %d distinct values", len(elements), len(seen))
+	}
+}
+
+func TestRandomSliceMatchesFieldRandomSlice(t *testing.T) {
+	got, err := RandomSlice(rand.New(rand.NewSource(1274)), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coefficients, err := field.RandomSlice(rand.New(rand.NewSource(1274)), 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range got {
+		want := New([ExtensionDegree]field.Element(coefficients[3*i : 3*i+3]))
+		if !x.Equal(want) {
+			t.Errorf("element %d = %v, want %v", i, x, want)
+		}
+	}
+
+	single, err := Random(rand.New(rand.NewSource(1274)))
+	if err != nil || !single.Equal(got[0]) {
+		t.Errorf("Random = %v, %v; want the first element of RandomSlice, %v", single, err, got[0])
+	}
+}
+
+func TestRandomSuccessiveCallsDiffer(t *testing.T) {
+	first, err := Random(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Random(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Equal(second) {
+		t.Errorf("two draws from crypto/rand were both %v", first)
+	}
+}
+
+func TestRandomErrors(t *testing.T) {
+	if _, err := Random(bytes.NewReader(make([]byte, 20))); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short read: got %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := RandomSlice(nil, -1); err == nil {
+		t.Error("negative count accepted")
+	}
+	if got, err := RandomSlice(bytes.NewReader(nil), 0); err != nil || len(got) != 0 {
+		t.Errorf("n = 0: got %v, %v", got, err)
+	}
+}
@@ -13,14 +13,16 @@ package xfield
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/params"
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
 )
 
 const (
 	// ExtensionDegree is the degree of the field extension
-	ExtensionDegree = 3
+	ExtensionDegree = params.ExtensionDegree
 )
 
 // XFieldElement represents an element in the extension field F_p^3.
@@ -226,78 +228,71 @@ func (x XFieldElement) MulConst(scalar field.Element) XFieldElement {
 	}
 }
 
+// shahPolynomial is the shared instance of the Shah polynomial. Polynomials
+// are immutable, so it is safe to hand out and to use concurrently.
+var shahPolynomial = polynomial.New([]field.Element{
+	// x³ - x + 1 = 1 + (-1)x + 0x² + 1x³
+	field.One,
+	field.One.Neg(),
+	field.Zero,
+	field.One,
+})
+
 // ShahPolynomial returns the irreducible polynomial defining the extension: x³ - x + 1
 //
 // Production implementation.
 func ShahPolynomial() *polynomial.Polynomial {
-	// x³ - x + 1 = 1 + (-1)x + 0x² + 1x³
-	return polynomial.New([]field.Element{
-		field.One,
-		field.One.Neg(),
-		field.Zero,
-		field.One,
-	})
+	return shahPolynomial
 }
 
-// Inverse computes the multiplicative inverse of the extension field element.
-//
-// Production implementation.
-func (x XFieldElement) Inverse() XFieldElement {
+// InverseChecked computes the multiplicative inverse like Inverse, but
+// returns an error instead of panicking when x is zero. Use it where x may
+// come from untrusted input.
+func (x XFieldElement) InverseChecked() (XFieldElement, error) {
 	if x.IsZero() {
-		panic("cannot invert the zero element in the extension field")
+		return Zero, fmt.Errorf("cannot invert the zero element in the extension field")
 	}
+	return x.Inverse(), nil
+}
 
-	a, b, c := x.Coefficients[0], x.Coefficients[1], x.Coefficients[2]
+// frobeniusX and frobeniusX2 are the images x^p and x^{2p} of the basis
+// elements x and x² under the Frobenius endomorphism.
+var (
+	frobeniusX  = New([ExtensionDegree]field.Element{field.Zero, field.One, field.Zero}).Pow(field.P)
+	frobeniusX2 = frobeniusX.Mul(frobeniusX)
+)
 
-	// Handle constant elements (b=0, c=0) correctly
-	if b.IsZero() && c.IsZero() {
-		return NewConst(a.Inverse())
-	}
+// Frobenius returns x^p, the image of x under the Frobenius endomorphism.
+// The map fixes the base field and is additive, so for x = c₀ + c₁·x + c₂·x²
+// it is c₀ + c₁·x^p + c₂·x^{2p}, with x^p and x^{2p} computed once.
+func (x XFieldElement) Frobenius() XFieldElement {
+	return NewConst(x.Coefficients[0]).
+		Add(frobeniusX.MulConst(x.Coefficients[1])).
+		Add(frobeniusX2.MulConst(x.Coefficients[2]))
+}
 
-	// For non-constant elements, use direct formula based on the multiplication structure
-	// We need to find [d, e, f] such that (a + bx + cx²) * (d + ex + fx²) = 1 (mod x³ - x + 1)
-	//
-	// Using the multiplication formula:
-	// r0 = c*d - a*e - b*f = 1
-	// r1 = b*d + c*e - a*f + a*e + b*f = 0
-	// r2 = a*d + b*e + c*f + a*f = 0
-	//
-	// Simplifying:
-	// r0 = cd - ae - bf = 1
-	// r1 = bd + ce + ae + bf = 0
-	// r2 = ad + be + cf + af = 0
-	//
-	// This can be solved using the norm form. In extension fields,
-	// we use: inv(x) = conj(x) / norm(x)
-	// where norm(x) = x * conj(x) * conj²(x)
-	//
-	// For the extension field defined by x³ - x + 1,
-	// we can compute the inverse using the formula:
-	// inv(a + bx + cx²) = (a² + bc - ac*x - b²*x² + (ab-c²)*1) / norm
-	//
-	// Following twenty-first's approach with XGCD:
-	xPoly := polynomial.New([]field.Element{a, b, c})
-	shahPoly := ShahPolynomial()
-
-	// Compute XGCD: gcd = aResult*xPoly + bResult*shahPoly
-	_, aResult, _ := polynomial.XGCD(xPoly, shahPoly)
-
-	// Reduce modulo Shah polynomial
-	_, remainder := aResult.Divide(shahPoly)
-
-	// Convert remainder to XFieldElement
-	coeffs := remainder.Coefficients()
-
-	var resultCoeffs [ExtensionDegree]field.Element
-	for i := 0; i < ExtensionDegree; i++ {
-		if i < len(coeffs) {
-			resultCoeffs[i] = coeffs[i]
-		} else {
-			resultCoeffs[i] = field.Zero
-		}
+// Conjugates returns the Galois conjugates x, x^p and x^{p²}. Their product
+// is the norm of x, which lies in the base field.
+func (x XFieldElement) Conjugates() [ExtensionDegree]XFieldElement {
+	xp := x.Frobenius()
+	return [ExtensionDegree]XFieldElement{x, xp, xp.Frobenius()}
+}
+
+// Inverse computes the multiplicative inverse of the extension field element.
+// Panics if x is zero; see InverseChecked.
+//
+// With conjugates x, x^p and x^{p²}, the norm x·x^p·x^{p²} is a non-zero
+// base field element, so x⁻¹ = x^p·x^{p²} / norm takes one base field
+// inversion.
+func (x XFieldElement) Inverse() XFieldElement {
+	if x.IsZero() {
+		panic("cannot invert the zero element in the extension field")
 	}
 
-	return XFieldElement{Coefficients: resultCoeffs}
+	conjugates := x.Conjugates()
+	cofactor := conjugates[1].Mul(conjugates[2])
+	norm := x.Mul(cofactor).Coefficients[0]
+	return cofactor.MulConst(norm.Inverse())
 }
 
 // Div performs extension field division: x / y = x * y⁻¹
@@ -325,6 +320,25 @@ func (x XFieldElement) Pow(exponent uint64) XFieldElement {
 	return result
 }
 
+// PowBig computes x^exponent for an exponent of any size, such as the
+// group order p³ - 1, by square-and-multiply over the exponent's bits from
+// the most significant. A negative exponent raises x⁻¹ to its absolute value
+// and so panics if x is zero.
+func (x XFieldElement) PowBig(exponent *big.Int) XFieldElement {
+	if exponent.Sign() < 0 {
+		return x.Inverse().PowBig(new(big.Int).Neg(exponent))
+	}
+
+	result := One
+	for i := exponent.BitLen() - 1; i >= 0; i-- {
+		result = result.Mul(result)
+		if exponent.Bit(i) == 1 {
+			result = result.Mul(x)
+		}
+	}
+	return result
+}
+
 // MarshalJSON implements json.Marshaler.
 // Extension field elements are serialized as arrays of 3 base field elements.
 func (x XFieldElement) MarshalJSON() ([]byte, error) {
@@ -353,14 +367,65 @@ func (x *XFieldElement) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ToBytes returns the canonical big-endian bytes of the coefficients c₀,
+// c₁ and c₂ in that order, each as by field.Element.ToCanonicalBytesBE.
+func (x XFieldElement) ToBytes() [ExtensionDegree * 8]byte {
+	var bytes [ExtensionDegree * 8]byte
+	for i, c := range x.Coefficients {
+		coefficient := c.ToCanonicalBytesBE()
+		copy(bytes[8*i:], coefficient[:])
+	}
+	return bytes
+}
+
+// FromBytes creates an element from the bytes written by ToBytes.
+// Coefficients of at least P are reduced mod P, as by
+// field.FromCanonicalBytesBE; UnmarshalBinary rejects them instead.
+func FromBytes(bytes [ExtensionDegree * 8]byte) XFieldElement {
+	var x XFieldElement
+	for i := range x.Coefficients {
+		x.Coefficients[i] = field.FromCanonicalBytesBE([8]byte(bytes[8*i:]))
+	}
+	return x
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, writing the 24 bytes
+// of ToBytes.
+func (x XFieldElement) MarshalBinary() ([]byte, error) {
+	bytes := x.ToBytes()
+	return bytes[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Unlike FromBytes it
+// returns an error for a coefficient of at least P, so that every element
+// has exactly one accepted encoding.
+func (x *XFieldElement) UnmarshalBinary(data []byte) error {
+	if len(data) != ExtensionDegree*8 {
+		return fmt.Errorf("invalid data length: expected %d bytes, got %d", ExtensionDegree*8, len(data))
+	}
+
+	var coefficients [ExtensionDegree]field.Element
+	for i := range coefficients {
+		c, err := field.FromCanonicalBytesBEChecked([8]byte(data[8*i:]))
+		if err != nil {
+			return fmt.Errorf("coefficient %d: %w", i, err)
+		}
+		coefficients[i] = c
+	}
+	x.Coefficients = coefficients
+	return nil
+}
+
 // ToDigest converts an extension field element to a 5-element digest.
 // The three coefficients become the first three digest elements, with zeros padding.
 //
 // This is used for Merkle tree construction from extension field elements.
+// It agrees with hash.DigestFromXFieldElement with zero spare limbs; see
+// package hash for the canonical packing of several digests.
 //
 // Production implementation.
-func (x XFieldElement) ToDigest() [5]field.Element {
-	return [5]field.Element{
+func (x XFieldElement) ToDigest() [params.DigestLen]field.Element {
+	return [params.DigestLen]field.Element{
 		x.Coefficients[0],
 		x.Coefficients[1],
 		x.Coefficients[2],
@@ -371,9 +436,10 @@ func (x XFieldElement) ToDigest() [5]field.Element {
 
 // FromDigest creates an extension field element from a digest.
 // Returns nil if the last two elements of the digest are not zero.
+// Otherwise it agrees with hash.Digest.ToXFieldElement.
 //
 // Production implementation.
-func FromDigest(digest [5]field.Element) *XFieldElement {
+func FromDigest(digest [params.DigestLen]field.Element) *XFieldElement {
 	// Check that the last two elements are zero
 	if !digest[3].IsZero() || !digest[4].IsZero() {
 		return nil
@@ -1,10 +1,16 @@
 package xfield
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
 )
 
 func TestXFieldElementCreation(t *testing.T) {
@@ -310,6 +316,73 @@ func TestXFieldElementInverse(t *testing.T) {
 	}
 }
 
+// inverseXGCD inverts x through the extended Euclidean algorithm with the
+// Shah polynomial, as a reference for the norm-based Inverse.
+func inverseXGCD(x XFieldElement) XFieldElement {
+	xPoly := polynomial.New(x.Coefficients[:])
+	_, aResult, _ := polynomial.XGCD(xPoly, ShahPolynomial())
+	_, remainder := aResult.Divide(ShahPolynomial())
+	var coeffs [ExtensionDegree]field.Element
+	for i := range coeffs {
+		coeffs[i] = remainder.Coefficient(i)
+	}
+	return New(coeffs)
+}
+
+func randomXFieldElement(rng *rand.Rand) XFieldElement {
+	var coeffs [ExtensionDegree]field.Element
+	for i := range coeffs {
+		coeffs[i] = field.New(rng.Uint64())
+	}
+	return New(coeffs)
+}
+
+func TestInverseMatchesXGCD(t *testing.T) {
+	rng := rand.New(rand.NewSource(1272))
+	cases := []XFieldElement{
+		One,
+		NewConst(field.New(7)),
+		New([3]field.Element{field.Zero, field.One, field.Zero}),
+		New([3]field.Element{field.Zero, field.Zero, field.One}),
+		New([3]field.Element{field.One.Neg(), field.One.Neg(), field.One.Neg()}),
+	}
+	for i := 0; i < 200; i++ {
+		cases = append(cases, randomXFieldElement(rng))
+	}
+	for _, x := range cases {
+		if got, want := x.Inverse(), inverseXGCD(x); !got.Equal(want) {
+			t.Errorf("Inverse(%v) = %v, XGCD gives %v", x, got, want)
+		}
+	}
+}
+
+func TestFrobenius(t *testing.T) {
+	rng := rand.New(rand.NewSource(1272))
+	for i := 0; i < 100; i++ {
+		x, y := randomXFieldElement(rng), randomXFieldElement(rng)
+		if got, want := x.Frobenius(), x.Pow(field.P); !got.Equal(want) {
+			t.Errorf("Frobenius(%v) = %v, want x^p = %v", x, got, want)
+		}
+		if !x.Mul(y).Frobenius().Equal(x.Frobenius().Mul(y.Frobenius())) {
+			t.Errorf("Frobenius is not multiplicative at %v, %v", x, y)
+		}
+
+		conjugates := x.Conjugates()
+		if !conjugates[0].Equal(x) || !conjugates[2].Frobenius().Equal(x) {
+			t.Errorf("conjugates %v of %v are not an orbit of length 3", conjugates, x)
+		}
+		norm := conjugates[0].Mul(conjugates[1]).Mul(conjugates[2])
+		if norm.Unlift() == nil {
+			t.Errorf("norm %v of %v is not in the base field", norm, x)
+		}
+	}
+
+	c := field.New(1272)
+	if got := NewConst(c).Frobenius(); !got.Equal(NewConst(c)) {
+		t.Errorf("Frobenius(%v) = %v, want it fixed", c, got)
+	}
+}
+
 func TestXFieldElementInverseZeroPanics(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -319,6 +392,21 @@ func TestXFieldElementInverseZeroPanics(t *testing.T) {
 	_ = Zero.Inverse()
 }
 
+func TestXFieldElementInverseChecked(t *testing.T) {
+	x := New([3]field.Element{field.New(3), field.New(1), field.New(4)})
+	inv, err := x.InverseChecked()
+	if err != nil {
+		t.Fatalf("InverseChecked: %v", err)
+	}
+	if !x.Mul(inv).IsOne() {
+		t.Errorf("x * InverseChecked(x) = %v, want One", x.Mul(inv))
+	}
+
+	if _, err := Zero.InverseChecked(); err == nil {
+		t.Error("InverseChecked(Zero) returned no error")
+	}
+}
+
 func TestXFieldElementDiv(t *testing.T) {
 	tests := []struct {
 		name string
@@ -389,6 +477,40 @@ func TestXFieldElementPow(t *testing.T) {
 	}
 }
 
+func TestXFieldElementPowBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(1276))
+	p := new(big.Int).SetUint64(field.P)
+	groupOrder := new(big.Int).Sub(new(big.Int).Exp(p, big.NewInt(3), nil), big.NewInt(1))
+	// x^((p³ - 1)/(p - 1)) is the norm x·x^p·x^{p²}
+	normExponent := new(big.Int).Div(groupOrder, new(big.Int).Sub(p, big.NewInt(1)))
+
+	for i := 0; i < 20; i++ {
+		x := randomXFieldElement(rng)
+		for _, exponent := range []uint64{0, 1, 2, 3, 7, 1 << 20, rng.Uint64()} {
+			if got, want := x.PowBig(new(big.Int).SetUint64(exponent)), x.Pow(exponent); !got.Equal(want) {
+				t.Errorf("%v^%d: PowBig gives %v, Pow gives %v", x, exponent, got, want)
+			}
+		}
+		if got := x.PowBig(groupOrder); !got.IsOne() {
+			t.Errorf("%v^(p³ - 1) = %v, want 1", x, got)
+		}
+		conjugates := x.Conjugates()
+		if got, want := x.PowBig(normExponent), conjugates[0].Mul(conjugates[1]).Mul(conjugates[2]); !got.Equal(want) || got.Unlift() == nil {
+			t.Errorf("%v^(p² + p + 1) = %v, want the norm %v", x, got, want)
+		}
+		if got := x.PowBig(big.NewInt(-3)); !got.Equal(x.Inverse().Pow(3)) {
+			t.Errorf("%v^-3 = %v, want %v", x, got, x.Inverse().Pow(3))
+		}
+	}
+
+	if got := Zero.PowBig(big.NewInt(0)); !got.IsOne() {
+		t.Errorf("0^0 = %v, want 1", got)
+	}
+	if got := Zero.PowBig(groupOrder); !got.IsZero() {
+		t.Errorf("0^(p³ - 1) = %v, want 0", got)
+	}
+}
+
 func TestXFieldElementUnlift(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -486,6 +608,48 @@ func TestXFieldElementJSONSerialization(t *testing.T) {
 	}
 }
 
+func TestXFieldElementBytesRoundTrip(t *testing.T) {
+	general := New([3]field.Element{field.New(1), field.New(field.P - 1), field.New(0x0123456789abcdef)})
+	for _, x := range []XFieldElement{Zero, One, general} {
+		bytes := x.ToBytes()
+		if got := FromBytes(bytes); !got.Equal(x) {
+			t.Errorf("FromBytes(ToBytes(%v)) = %v", x, got)
+		}
+
+		data, err := x.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(%v): %v", x, err)
+		}
+		var got XFieldElement
+		if err := got.UnmarshalBinary(data); err != nil || !got.Equal(x) {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, %v", x, got, err)
+		}
+	}
+
+	// Three big-endian coefficients, lowest degree first
+	want := "0000000000000001" + "ffffffff00000000" + "0123456789abcdef"
+	if bytes := general.ToBytes(); hex.EncodeToString(bytes[:]) != want {
+		t.Errorf("ToBytes = %x, want %s", bytes, want)
+	}
+}
+
+func TestXFieldElementUnmarshalBinaryInvalid(t *testing.T) {
+	var x XFieldElement
+	if err := x.UnmarshalBinary(make([]byte, 23)); err == nil {
+		t.Error("23 bytes accepted")
+	}
+
+	// P in the top coefficient: FromBytes reduces it, UnmarshalBinary rejects
+	var bytes [24]byte
+	binary.BigEndian.PutUint64(bytes[16:], field.P)
+	if got := FromBytes(bytes); !got.IsZero() {
+		t.Errorf("FromBytes with a coefficient of P = %v, want zero", got)
+	}
+	if err := x.UnmarshalBinary(bytes[:]); err == nil {
+		t.Error("coefficient of P accepted")
+	}
+}
+
 func TestXFieldElementDigestConversion(t *testing.T) {
 	tests := []struct {
 		name string
@@ -629,6 +793,41 @@ func TestShahPolynomial(t *testing.T) {
 	}
 }
 
+func TestShahPolynomialIsACopy(t *testing.T) {
+	x := New([3]field.Element{field.New(1), field.New(2), field.New(3)})
+	want := x.Inverse()
+
+	coeffs := ShahPolynomial().Coefficients()
+	for i := range coeffs {
+		coeffs[i] = field.New(7)
+	}
+
+	if got := x.Inverse(); !got.Equal(want) {
+		t.Errorf("Inverse changed after mutating ShahPolynomial: got %v, want %v", got, want)
+	}
+	if ShahPolynomial().Coefficients()[0].Value() != 1 {
+		t.Error("mutation leaked into a later ShahPolynomial")
+	}
+}
+
+func TestInverseConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				x := New([3]field.Element{field.New(uint64(g)), field.New(uint64(i + 1)), field.New(3)})
+				if !x.Mul(x.Inverse()).IsOne() {
+					t.Errorf("x * x⁻¹ != 1 for %v", x)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
 // Benchmark tests
 func BenchmarkXFieldElementAdd(b *testing.B) {
 	x := New([3]field.Element{field.New(1), field.New(2), field.New(3)})
@@ -644,6 +843,7 @@ func BenchmarkXFieldElementMul(b *testing.B) {
 	x := New([3]field.Element{field.New(1), field.New(2), field.New(3)})
 	y := New([3]field.Element{field.New(4), field.New(5), field.New(6)})
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = x.Mul(y)
@@ -653,6 +853,7 @@ func BenchmarkXFieldElementMul(b *testing.B) {
 func BenchmarkXFieldElementInverse(b *testing.B) {
 	x := New([3]field.Element{field.New(1), field.New(2), field.New(3)})
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = x.Inverse()
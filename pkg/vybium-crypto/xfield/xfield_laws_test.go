@@ -0,0 +1,55 @@
+package xfield_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest/lawgen"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+var lawRing = lawtest.Ring[xfield.XFieldElement]{
+	Name:   "xfield",
+	Zero:   xfield.Zero,
+	One:    xfield.One,
+	Add:    xfield.XFieldElement.Add,
+	Sub:    xfield.XFieldElement.Sub,
+	Mul:    xfield.XFieldElement.Mul,
+	Neg:    xfield.XFieldElement.Neg,
+	Equal:  xfield.XFieldElement.Equal,
+	Random: lawgen.RandomXFE,
+}
+
+var lawBaseRing = lawtest.Ring[field.Element]{
+	Name:   "field",
+	Zero:   field.Zero,
+	One:    field.One,
+	Add:    field.Element.Add,
+	Sub:    field.Element.Sub,
+	Mul:    field.Element.Mul,
+	Neg:    field.Element.Neg,
+	Equal:  field.Element.Equal,
+	Random: lawgen.RandomBFE,
+}
+
+func TestXFieldLaws(t *testing.T) {
+	lawtest.CheckField(t, lawRing, xfield.XFieldElement.Inverse, lawtest.Config{Seed: 1173})
+}
+
+func TestNewConstIsHomomorphism(t *testing.T) {
+	lawtest.CheckHomomorphism(t, "NewConst", lawBaseRing, lawRing, xfield.NewConst, lawtest.Config{Seed: 1173})
+}
+
+// MulConst and AddConst agree with lifting the scalar first.
+func TestConstOperationsAgreeWithLifting(t *testing.T) {
+	lawtest.ForEachCase(t, lawtest.Config{Seed: 1173}, func(rng *rand.Rand, seed int64) bool {
+		x, c := lawgen.RandomXFE(rng), lawgen.RandomBFE(rng)
+		if !x.MulConst(c).Equal(x.Mul(xfield.NewConst(c))) || !x.AddConst(c).Equal(x.Add(xfield.NewConst(c))) {
+			t.Errorf("seed %d: constant operations disagree with lifting for x=%v c=%v", seed, x, c)
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,466 @@
+// Package xpolynomial provides univariate polynomial operations over the
+// extension field F_p^3.
+//
+// It mirrors package polynomial for polynomials whose coefficients are
+// XFieldElements, as needed for DEEP composition polynomials and
+// out-of-domain quotients. Polynomials are represented as coefficient vectors
+// in order of increasing degree. DivideByLinear implements synthetic division
+// by (x - z), the dominant operation when computing DEEP quotients.
+//
+// As in package polynomial, an XPolynomial is immutable once constructed and
+// accessors return copies.
+package xpolynomial
+
+import (
+	"fmt"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// XPolynomial represents a univariate polynomial with coefficients in F_p^3.
+// Coefficients are stored in order of increasing degree (coefficients[0] is the constant term).
+// The zero polynomial is represented as an empty coefficient slice.
+type XPolynomial struct {
+	// coefficients in order of increasing degree
+	coefficients []xfield.XFieldElement
+}
+
+// New creates a new polynomial from coefficients.
+// Coefficients are in order of increasing degree: [c0, c1, c2, ...] represents c0 + c1*x + c2*x^2 + ...
+func New(coefficients []xfield.XFieldElement) *XPolynomial {
+	p := &XPolynomial{
+		coefficients: make([]xfield.XFieldElement, len(coefficients)),
+	}
+	copy(p.coefficients, coefficients)
+	p.normalize()
+	return p
+}
+
+// Lift embeds a base-field polynomial into the extension field.
+func Lift(p *polynomial.Polynomial) *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, p.Degree()+1)
+	for i := range coeffs {
+		coeffs[i] = xfield.NewConst(p.Coefficient(i))
+	}
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// Zero returns the zero polynomial.
+func Zero() *XPolynomial {
+	return &XPolynomial{coefficients: []xfield.XFieldElement{}}
+}
+
+// One returns the constant polynomial 1.
+func One() *XPolynomial {
+	return &XPolynomial{coefficients: []xfield.XFieldElement{xfield.One}}
+}
+
+// X returns the polynomial x (identity polynomial).
+func X() *XPolynomial {
+	return &XPolynomial{coefficients: []xfield.XFieldElement{xfield.Zero, xfield.One}}
+}
+
+// Degree returns the degree of the polynomial.
+// Returns -1 for the zero polynomial.
+func (p *XPolynomial) Degree() int {
+	deg := len(p.coefficients) - 1
+	for deg >= 0 && p.coefficients[deg].IsZero() {
+		deg--
+	}
+	return deg
+}
+
+// Coefficients returns a copy of the polynomial's coefficients in order of increasing degree.
+// The leading coefficient is guaranteed to be non-zero (except for the zero polynomial).
+func (p *XPolynomial) Coefficients() []xfield.XFieldElement {
+	return append([]xfield.XFieldElement{}, p.unsafeCoefficients()...)
+}
+
+// unsafeCoefficients returns the coefficients up to the degree without
+// copying. The result aliases the polynomial and must not be modified.
+func (p *XPolynomial) unsafeCoefficients() []xfield.XFieldElement {
+	return p.coefficients[:p.Degree()+1]
+}
+
+// LeadingCoefficient returns the leading coefficient (coefficient of highest degree term).
+// Returns Zero for the zero polynomial.
+func (p *XPolynomial) LeadingCoefficient() xfield.XFieldElement {
+	deg := p.Degree()
+	if deg < 0 {
+		return xfield.Zero
+	}
+	return p.coefficients[deg]
+}
+
+// IsZero returns true if this is the zero polynomial.
+func (p *XPolynomial) IsZero() bool {
+	return p.Degree() < 0
+}
+
+// IsOne returns true if this is the constant polynomial 1.
+func (p *XPolynomial) IsOne() bool {
+	return p.Degree() == 0 && p.coefficients[0].IsOne()
+}
+
+// Equal returns true if two polynomials are equal.
+func (p *XPolynomial) Equal(other *XPolynomial) bool {
+	if p.Degree() != other.Degree() {
+		return false
+	}
+
+	for i := 0; i <= p.Degree(); i++ {
+		if !p.coefficients[i].Equal(other.coefficients[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone creates a deep copy of the polynomial.
+func (p *XPolynomial) Clone() *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, len(p.coefficients))
+	copy(coeffs, p.coefficients)
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// normalize removes leading zero coefficients.
+func (p *XPolynomial) normalize() {
+	for len(p.coefficients) > 0 && p.coefficients[len(p.coefficients)-1].IsZero() {
+		p.coefficients = p.coefficients[:len(p.coefficients)-1]
+	}
+}
+
+// coefficient returns the coefficient of x^i, or zero if i is out of range.
+func (p *XPolynomial) coefficient(i int) xfield.XFieldElement {
+	if i < len(p.coefficients) {
+		return p.coefficients[i]
+	}
+	return xfield.Zero
+}
+
+// Add adds two polynomials.
+func (p *XPolynomial) Add(other *XPolynomial) *XPolynomial {
+	maxLen := len(p.coefficients)
+	if len(other.coefficients) > maxLen {
+		maxLen = len(other.coefficients)
+	}
+
+	coeffs := make([]xfield.XFieldElement, maxLen)
+	for i := range coeffs {
+		coeffs[i] = p.coefficient(i).Add(other.coefficient(i))
+	}
+
+	return New(coeffs)
+}
+
+// Sub subtracts another polynomial from this one.
+func (p *XPolynomial) Sub(other *XPolynomial) *XPolynomial {
+	maxLen := len(p.coefficients)
+	if len(other.coefficients) > maxLen {
+		maxLen = len(other.coefficients)
+	}
+
+	coeffs := make([]xfield.XFieldElement, maxLen)
+	for i := range coeffs {
+		coeffs[i] = p.coefficient(i).Sub(other.coefficient(i))
+	}
+
+	return New(coeffs)
+}
+
+// Neg returns the negation of the polynomial.
+func (p *XPolynomial) Neg() *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, len(p.coefficients))
+	for i, c := range p.coefficients {
+		coeffs[i] = c.Neg()
+	}
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// Mul multiplies two polynomials using naive O(n²) algorithm.
+func (p *XPolynomial) Mul(other *XPolynomial) *XPolynomial {
+	if p.IsZero() || other.IsZero() {
+		return Zero()
+	}
+
+	degP := p.Degree()
+	degQ := other.Degree()
+
+	coeffs := make([]xfield.XFieldElement, degP+degQ+1)
+	for i := range coeffs {
+		coeffs[i] = xfield.Zero
+	}
+
+	for i := 0; i <= degP; i++ {
+		for j := 0; j <= degQ; j++ {
+			product := p.coefficients[i].Mul(other.coefficients[j])
+			coeffs[i+j] = coeffs[i+j].Add(product)
+		}
+	}
+
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// ScalarMul multiplies the polynomial by an extension field scalar.
+func (p *XPolynomial) ScalarMul(scalar xfield.XFieldElement) *XPolynomial {
+	if scalar.IsZero() {
+		return Zero()
+	}
+
+	coeffs := make([]xfield.XFieldElement, len(p.coefficients))
+	for i, c := range p.coefficients {
+		coeffs[i] = c.Mul(scalar)
+	}
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// ScalarMulConst multiplies the polynomial by a base field scalar.
+func (p *XPolynomial) ScalarMulConst(scalar field.Element) *XPolynomial {
+	if scalar.IsZero() {
+		return Zero()
+	}
+
+	coeffs := make([]xfield.XFieldElement, len(p.coefficients))
+	for i, c := range p.coefficients {
+		coeffs[i] = c.MulConst(scalar)
+	}
+	return &XPolynomial{coefficients: coeffs}
+}
+
+// Evaluate evaluates the polynomial at a given point using Horner's method.
+func (p *XPolynomial) Evaluate(x xfield.XFieldElement) xfield.XFieldElement {
+	if p.IsZero() {
+		return xfield.Zero
+	}
+
+	result := p.coefficients[len(p.coefficients)-1]
+	for i := len(p.coefficients) - 2; i >= 0; i-- {
+		result = result.Mul(x).Add(p.coefficients[i])
+	}
+	return result
+}
+
+// BatchEvaluate evaluates the polynomial at multiple points.
+func (p *XPolynomial) BatchEvaluate(points []xfield.XFieldElement) []xfield.XFieldElement {
+	results := make([]xfield.XFieldElement, len(points))
+	for i, point := range points {
+		results[i] = p.Evaluate(point)
+	}
+	return results
+}
+
+// MonicChecked returns a monic version of the polynomial, or an error if the
+// polynomial is zero.
+func (p *XPolynomial) MonicChecked() (*XPolynomial, error) {
+	if p.IsZero() {
+		return nil, fmt.Errorf("cannot make zero polynomial monic")
+	}
+	return p.Monic(), nil
+}
+
+// Monic returns a monic version of the polynomial (leading coefficient = 1).
+// Panics if the polynomial is zero; see MonicChecked.
+func (p *XPolynomial) Monic() *XPolynomial {
+	if p.IsZero() {
+		panic("cannot make zero polynomial monic")
+	}
+
+	leadingCoeff := p.LeadingCoefficient()
+	if leadingCoeff.IsOne() {
+		return p.Clone()
+	}
+	return p.ScalarMul(leadingCoeff.Inverse())
+}
+
+// DivideChecked is Divide, returning an error instead of panicking if other
+// is zero.
+func (p *XPolynomial) DivideChecked(other *XPolynomial) (quotient, remainder *XPolynomial, err error) {
+	if other.IsZero() {
+		return nil, nil, fmt.Errorf("division by zero polynomial")
+	}
+	quotient, remainder = p.Divide(other)
+	return quotient, remainder, nil
+}
+
+// Divide performs naive polynomial division.
+// Returns (quotient, remainder) such that p = quotient * other + remainder.
+//
+// Panics if other is zero; see DivideChecked.
+func (p *XPolynomial) Divide(other *XPolynomial) (quotient, remainder *XPolynomial) {
+	if other.IsZero() {
+		panic("division by zero polynomial")
+	}
+
+	degP := p.Degree()
+	degQ := other.Degree()
+
+	if degP < degQ {
+		return Zero(), p.Clone()
+	}
+
+	remainderCoeffs := make([]xfield.XFieldElement, degP+1)
+	copy(remainderCoeffs, p.coefficients[:degP+1])
+	quotientCoeffs := make([]xfield.XFieldElement, degP-degQ+1)
+
+	leadingCoeffInv := other.LeadingCoefficient().Inverse()
+
+	// Eliminate the remainder's terms from the top down; the coefficient at
+	// degree degQ + i determines the quotient coefficient of x^i
+	for i := degP - degQ; i >= 0; i-- {
+		quotCoeff := remainderCoeffs[degQ+i].Mul(leadingCoeffInv)
+		quotientCoeffs[i] = quotCoeff
+		if quotCoeff.IsZero() {
+			continue
+		}
+		for j := 0; j <= degQ; j++ {
+			sub := other.coefficients[j].Mul(quotCoeff)
+			remainderCoeffs[i+j] = remainderCoeffs[i+j].Sub(sub)
+		}
+	}
+
+	quotient = &XPolynomial{coefficients: quotientCoeffs}
+	quotient.normalize()
+	remainder = &XPolynomial{coefficients: remainderCoeffs[:degQ]}
+	remainder.normalize()
+
+	return quotient, remainder
+}
+
+// Mod returns p mod other (the remainder of division).
+//
+// Panics if other is zero.
+func (p *XPolynomial) Mod(other *XPolynomial) *XPolynomial {
+	_, remainder := p.Divide(other)
+	return remainder
+}
+
+// DivideByLinear divides the polynomial by (x - z) using synthetic division.
+// Returns (quotient, remainder) such that p = quotient * (x - z) + remainder.
+// The remainder is the constant p(z), so the division is exact if and only
+// if z is a root of p. Runs in O(n) extension field operations.
+func (p *XPolynomial) DivideByLinear(z xfield.XFieldElement) (quotient *XPolynomial, remainder xfield.XFieldElement) {
+	deg := p.Degree()
+	if deg < 1 {
+		return Zero(), p.coefficient(0)
+	}
+
+	// q_{i-1} = a_i + z * q_i, with q_{deg-1} = a_deg
+	quotientCoeffs := make([]xfield.XFieldElement, deg)
+	carry := xfield.Zero
+	for i := deg; i >= 1; i-- {
+		carry = p.coefficients[i].Add(carry.Mul(z))
+		quotientCoeffs[i-1] = carry
+	}
+	remainder = p.coefficients[0].Add(carry.Mul(z))
+
+	return &XPolynomial{coefficients: quotientCoeffs}, remainder
+}
+
+// String returns a string representation of the polynomial.
+func (p *XPolynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+
+	result := ""
+	for i := p.Degree(); i >= 0; i-- {
+		coeff := p.coefficients[i]
+		if coeff.IsZero() {
+			continue
+		}
+
+		if result != "" {
+			result += " + "
+		}
+
+		if !coeff.IsOne() || i == 0 {
+			result += fmt.Sprintf("(%v)", coeff)
+		}
+
+		switch i {
+		case 0:
+			// Just the coefficient
+		case 1:
+			result += "x"
+		default:
+			result += fmt.Sprintf("x^%d", i)
+		}
+	}
+	return result
+}
+
+// Interpolate performs Lagrange interpolation through the given points.
+// Points are (x, y) pairs where y = p(x) for some polynomial p.
+// Returns the unique polynomial of degree at most n-1 that passes through all n points.
+//
+// Panics if:
+// - points is empty
+// - any two points have the same x-coordinate
+//
+// See InterpolateChecked for untrusted points.
+func Interpolate(points [][2]xfield.XFieldElement) *XPolynomial {
+	p, err := InterpolateChecked(points)
+	if err != nil {
+		panic(err.Error())
+	}
+	return p
+}
+
+// InterpolateChecked is Interpolate, returning an error instead of panicking
+// if points is empty or two points share an x-coordinate.
+func InterpolateChecked(points [][2]xfield.XFieldElement) (*XPolynomial, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("cannot interpolate through zero points")
+	}
+
+	xs := make([]xfield.XFieldElement, len(points))
+	for i, point := range points {
+		for j := 0; j < i; j++ {
+			if point[0].Equal(xs[j]) {
+				return nil, fmt.Errorf("duplicate x-coordinates in interpolation points")
+			}
+		}
+		xs[i] = point[0]
+	}
+
+	// L_i(x) = Z(x) / (x - x_i) / Z'(x_i), where Z is the zerofier of all
+	// x-coordinates, so each basis polynomial costs one synthetic division
+	zerofier := Zerofier(xs)
+	coeffs := make([]xfield.XFieldElement, len(points))
+	for i := range coeffs {
+		coeffs[i] = xfield.Zero
+	}
+
+	for _, point := range points {
+		basis, _ := zerofier.DivideByLinear(point[0])
+		denominator := basis.Evaluate(point[0])
+		scale := point[1].Mul(denominator.Inverse())
+		for j, c := range basis.coefficients {
+			coeffs[j] = coeffs[j].Add(c.Mul(scale))
+		}
+	}
+
+	return New(coeffs), nil
+}
+
+// Zerofier returns the polynomial that has zeros at all given points.
+// That is, returns (x - points[0]) * (x - points[1]) * ... * (x - points[n-1]).
+func Zerofier(points []xfield.XFieldElement) *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, len(points)+1)
+	coeffs[0] = xfield.One
+	for i := 1; i < len(coeffs); i++ {
+		coeffs[i] = xfield.Zero
+	}
+
+	// Multiply the running product of degree k by (x - point) in place
+	for k, point := range points {
+		for j := k + 1; j >= 1; j-- {
+			coeffs[j] = coeffs[j-1].Sub(coeffs[j].Mul(point))
+		}
+		coeffs[0] = coeffs[0].Mul(point).Neg()
+	}
+
+	return &XPolynomial{coefficients: coeffs}
+}
@@ -0,0 +1,85 @@
+package xpolynomial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// Encode returns the BFieldCodec encoding of the polynomial: the number of
+// coefficients n = Degree()+1, followed by the coefficients in order of
+// increasing degree, each as its xfield.ExtensionDegree base field
+// coefficients:
+//
+//	[n, c_0, c_1, ..., c_{n-1}]
+//
+// The leading coefficient c_{n-1} is nonzero, so every polynomial has exactly
+// one encoding; the zero polynomial encodes as [0].
+func (p *XPolynomial) Encode() []field.Element {
+	coefficients := p.unsafeCoefficients()
+	encoding := make([]field.Element, 0, 1+xfield.ExtensionDegree*len(coefficients))
+	encoding = append(encoding, field.New(uint64(len(coefficients))))
+	for _, c := range coefficients {
+		encoding = append(encoding, c.Coefficients[:]...)
+	}
+	return encoding
+}
+
+// Decode implements bfieldcodec.BFieldCodec. The result is an *XPolynomial.
+func (p *XPolynomial) Decode(sequence []field.Element) (bfieldcodec.BFieldCodec, error) {
+	decoded, err := DecodeXPolynomial(sequence)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// StaticLength implements bfieldcodec.BFieldCodec. Polynomials have dynamic
+// length.
+func (p *XPolynomial) StaticLength() *int {
+	return nil
+}
+
+// DecodeXPolynomial decodes a polynomial from its BFieldCodec encoding.
+// Returns an error if the coefficient count does not match the sequence
+// length or the encoding has a zero leading coefficient.
+func DecodeXPolynomial(sequence []field.Element) (*XPolynomial, error) {
+	return decodeXPolynomial(sequence, math.MaxUint64)
+}
+
+// DecodeXPolynomialWithMaxDegree is DecodeXPolynomial, additionally
+// rejecting polynomials of degree above maxDegree. The bound is checked
+// against the coefficient count before anything else is read, so a hostile
+// count costs nothing. A maxDegree of -1 admits only the zero polynomial.
+func DecodeXPolynomialWithMaxDegree(sequence []field.Element, maxDegree int) (*XPolynomial, error) {
+	if maxDegree < -1 {
+		return nil, fmt.Errorf("invalid degree bound %d", maxDegree)
+	}
+	return decodeXPolynomial(sequence, uint64(maxDegree+1))
+}
+
+func decodeXPolynomial(sequence []field.Element, maxCoefficients uint64) (*XPolynomial, error) {
+	if len(sequence) == 0 {
+		return nil, fmt.Errorf("polynomial encoding is empty")
+	}
+	count := sequence[0].Value()
+	if count > maxCoefficients {
+		return nil, fmt.Errorf("polynomial of degree %d exceeds degree bound %d", count-1, int64(maxCoefficients)-1)
+	}
+	body := sequence[1:]
+	if len(body)%xfield.ExtensionDegree != 0 || uint64(len(body)/xfield.ExtensionDegree) != count {
+		return nil, fmt.Errorf("polynomial has %d coefficients, encoding holds %d elements", count, len(body))
+	}
+
+	coefficients := make([]xfield.XFieldElement, count)
+	for i := range coefficients {
+		copy(coefficients[i].Coefficients[:], body[i*xfield.ExtensionDegree:])
+	}
+	if count > 0 && coefficients[count-1].IsZero() {
+		return nil, fmt.Errorf("non-canonical polynomial encoding: leading coefficient is zero")
+	}
+	return &XPolynomial{coefficients: coefficients}, nil
+}
@@ -0,0 +1,95 @@
+package xpolynomial
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/bfieldcodec"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+var _ bfieldcodec.BFieldCodec = (*XPolynomial)(nil)
+
+func TestXPolynomialCodecRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1190))
+	for n := 0; n < 200; n++ {
+		coeffs := make([]xfield.XFieldElement, rng.Intn(40))
+		for i := range coeffs {
+			coeffs[i] = xfield.New([xfield.ExtensionDegree]field.Element{
+				field.New(rng.Uint64()), field.New(rng.Uint64()), field.New(rng.Uint64()),
+			})
+		}
+		if n%3 == 0 {
+			coeffs = append(coeffs, xfield.Zero)
+		}
+		p := New(coeffs)
+
+		encoding := p.Encode()
+		if len(encoding) != 1+xfield.ExtensionDegree*(p.Degree()+1) {
+			t.Fatalf("degree %d: encoding has %d elements", p.Degree(), len(encoding))
+		}
+		decoded, err := DecodeXPolynomialWithMaxDegree(encoding, p.Degree())
+		if err != nil || !decoded.Equal(p) {
+			t.Fatalf("degree %d: round trip failed: %v", p.Degree(), err)
+		}
+		viaInterface, err := (&XPolynomial{}).Decode(encoding)
+		if err != nil || !viaInterface.(*XPolynomial).Equal(p) {
+			t.Fatalf("degree %d: Decode failed: %v", p.Degree(), err)
+		}
+	}
+	if Zero().StaticLength() != nil {
+		t.Error("polynomials have no static length")
+	}
+}
+
+// TestXPolynomialCodecLayout checks the count prefix and the coefficient
+// order, interior zero coefficients included.
+func TestXPolynomialCodecLayout(t *testing.T) {
+	base := New([]xfield.XFieldElement{xfield.NewU64(7), xfield.NewU64(0), xfield.NewU64(9)})
+	encoding := base.Encode()
+	if encoding[0].Value() != 3 {
+		t.Fatalf("count prefix %d, want 3", encoding[0].Value())
+	}
+	if encoding[1].Value() != 7 || encoding[7].Value() != 9 {
+		t.Errorf("coefficients not laid out in order: %v", encoding)
+	}
+}
+
+func TestDecodeXPolynomialRejectsMalformed(t *testing.T) {
+	valid := New([]xfield.XFieldElement{xfield.NewU64(1), xfield.NewU64(2)}).Encode()
+
+	tests := []struct {
+		name     string
+		sequence []field.Element
+		errHas   string
+	}{
+		{"empty", nil, "empty"},
+		{"partial coefficient", valid[:len(valid)-1], "holds 5 elements"},
+		{"missing coefficient", valid[:len(valid)-xfield.ExtensionDegree], "holds 3 elements"},
+		{"trailing zero", append(append([]field.Element{field.New(3)}, valid[1:]...), field.Zero, field.Zero, field.Zero), "non-canonical"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeXPolynomial(tt.sequence)
+		if err == nil || !strings.Contains(err.Error(), tt.errHas) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.errHas)
+		}
+	}
+}
+
+func TestDecodeXPolynomialRejectsHostileDegree(t *testing.T) {
+	for _, count := range []uint64{257, 1_000_000, field.P - 1} {
+		sequence := []field.Element{field.New(count)}
+		_, err := DecodeXPolynomialWithMaxDegree(sequence, 255)
+		if err == nil || !strings.Contains(err.Error(), "exceeds degree bound 255") {
+			t.Errorf("count %d: got %v", count, err)
+		}
+	}
+	if _, err := DecodeXPolynomialWithMaxDegree(One().Encode(), -1); err == nil {
+		t.Error("bound -1 admitted a constant")
+	}
+	if _, err := DecodeXPolynomialWithMaxDegree(Zero().Encode(), -2); err == nil {
+		t.Error("accepted degree bound -2")
+	}
+}
@@ -0,0 +1,81 @@
+package xpolynomial
+
+import (
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// fuzzXElements maps each group of three bytes to an extension field element
+// with small coefficients, so that zeros and repeated values are common.
+func fuzzXElements(data []byte, limit int) []xfield.XFieldElement {
+	n := len(data) / xfield.ExtensionDegree
+	if n > limit {
+		n = limit
+	}
+	elements := make([]xfield.XFieldElement, n)
+	for i := range elements {
+		var coefficients [xfield.ExtensionDegree]field.Element
+		for j := range coefficients {
+			coefficients[j] = field.New(uint64(data[i*xfield.ExtensionDegree+j] % 4))
+		}
+		elements[i] = xfield.New(coefficients)
+	}
+	return elements
+}
+
+// FuzzCheckedOperations feeds arbitrary input to the checked entry points,
+// which must report malformed input as an error and never panic.
+func FuzzCheckedOperations(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0})
+	f.Add([]byte{1, 2, 3, 1, 2, 3, 0, 0, 1, 1, 0, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		elements := fuzzXElements(data, 32)
+
+		for _, e := range elements {
+			inv, err := e.InverseChecked()
+			if (err != nil) != e.IsZero() {
+				t.Fatalf("InverseChecked(%v): error %v", e, err)
+			}
+			if err == nil && !e.Mul(inv).IsOne() {
+				t.Fatalf("InverseChecked(%v) is not an inverse", e)
+			}
+		}
+
+		p := New(elements)
+		monic, err := p.MonicChecked()
+		if (err != nil) != p.IsZero() {
+			t.Fatalf("MonicChecked: error %v", err)
+		}
+		if err == nil && !monic.LeadingCoefficient().IsOne() {
+			t.Fatal("MonicChecked result is not monic")
+		}
+
+		half := len(elements) / 2
+		a, b := New(elements[:half]), New(elements[half:])
+		quotient, remainder, err := a.DivideChecked(b)
+		if (err != nil) != b.IsZero() {
+			t.Fatalf("DivideChecked: error %v", err)
+		}
+		if err == nil && !quotient.Mul(b).Add(remainder).Equal(a) {
+			t.Fatal("DivideChecked: quotient * divisor + remainder != dividend")
+		}
+
+		points := make([][2]xfield.XFieldElement, half)
+		for i := range points {
+			points[i] = [2]xfield.XFieldElement{elements[2*i], elements[2*i+1]}
+		}
+		interpolant, err := InterpolateChecked(points)
+		if err != nil {
+			return
+		}
+		for _, point := range points {
+			if interpolant.Evaluate(point[0]) != point[1] {
+				t.Fatalf("InterpolateChecked result misses point %v", point)
+			}
+		}
+	})
+}
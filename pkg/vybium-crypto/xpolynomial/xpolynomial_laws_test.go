@@ -0,0 +1,67 @@
+package xpolynomial
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/internal/lawtest/lawgen"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+// randomLawXPolynomial returns a polynomial of degree at most 6, including
+// the zero polynomial and constants.
+func randomLawXPolynomial(rng *rand.Rand) *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, rng.Intn(8))
+	for i := range coeffs {
+		coeffs[i] = lawgen.RandomXFE(rng)
+	}
+	return New(coeffs)
+}
+
+// randomLawPolynomial returns a base field polynomial of degree at most 6.
+func randomLawPolynomial(rng *rand.Rand) *polynomial.Polynomial {
+	coeffs := make([]field.Element, rng.Intn(8))
+	for i := range coeffs {
+		coeffs[i] = lawgen.RandomBFE(rng)
+	}
+	return polynomial.New(coeffs)
+}
+
+var lawRing = lawtest.Ring[*XPolynomial]{
+	Name:   "xpolynomial",
+	Zero:   Zero(),
+	One:    One(),
+	Add:    (*XPolynomial).Add,
+	Sub:    (*XPolynomial).Sub,
+	Mul:    (*XPolynomial).Mul,
+	Neg:    (*XPolynomial).Neg,
+	Equal:  (*XPolynomial).Equal,
+	Random: randomLawXPolynomial,
+}
+
+func TestXPolynomialLaws(t *testing.T) {
+	lawtest.CheckRing(t, lawRing, lawtest.Config{Seed: 1173})
+}
+
+func TestXPolynomialHomomorphisms(t *testing.T) {
+	xfieldRing := lawtest.Ring[xfield.XFieldElement]{
+		Name: "xfield", Zero: xfield.Zero, One: xfield.One,
+		Add: xfield.XFieldElement.Add, Sub: xfield.XFieldElement.Sub,
+		Mul: xfield.XFieldElement.Mul, Neg: xfield.XFieldElement.Neg,
+		Equal: xfield.XFieldElement.Equal, Random: lawgen.RandomXFE,
+	}
+	x := xfield.New([xfield.ExtensionDegree]field.Element{field.New(3), field.New(5), field.New(7)})
+	evaluate := func(p *XPolynomial) xfield.XFieldElement { return p.Evaluate(x) }
+	lawtest.CheckHomomorphism(t, "Evaluate", lawRing, xfieldRing, evaluate, lawtest.Config{Seed: 1173, Cases: 500})
+
+	polynomialRing := lawtest.Ring[*polynomial.Polynomial]{
+		Name: "polynomial", Zero: polynomial.Zero(), One: polynomial.One(),
+		Add: (*polynomial.Polynomial).Add, Sub: (*polynomial.Polynomial).Sub,
+		Mul: (*polynomial.Polynomial).Mul, Neg: (*polynomial.Polynomial).Neg,
+		Equal: (*polynomial.Polynomial).Equal, Random: randomLawPolynomial,
+	}
+	lawtest.CheckHomomorphism(t, "Lift", polynomialRing, lawRing, Lift, lawtest.Config{Seed: 1173, Cases: 500})
+}
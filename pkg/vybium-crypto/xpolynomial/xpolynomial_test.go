@@ -0,0 +1,423 @@
+package xpolynomial
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/xfield"
+)
+
+func xfes(values ...uint64) []xfield.XFieldElement {
+	result := make([]xfield.XFieldElement, len(values))
+	for i, v := range values {
+		result[i] = xfield.NewU64(v)
+	}
+	return result
+}
+
+func randomXFieldElement(rng *rand.Rand) xfield.XFieldElement {
+	return xfield.New([xfield.ExtensionDegree]field.Element{
+		field.New(rng.Uint64()), field.New(rng.Uint64()), field.New(rng.Uint64()),
+	})
+}
+
+func randomXPolynomial(rng *rand.Rand, numCoefficients int) *XPolynomial {
+	coeffs := make([]xfield.XFieldElement, numCoefficients)
+	for i := range coeffs {
+		coeffs[i] = randomXFieldElement(rng)
+	}
+	return New(coeffs)
+}
+
+func randomPolynomial(rng *rand.Rand, numCoefficients int) *polynomial.Polynomial {
+	coeffs := make([]field.Element, numCoefficients)
+	for i := range coeffs {
+		coeffs[i] = field.New(rng.Uint64())
+	}
+	return polynomial.New(coeffs)
+}
+
+func TestXPolynomialCreation(t *testing.T) {
+	p := New(xfes(1, 2, 3))
+
+	if p.Degree() != 2 {
+		t.Errorf("Expected degree 2, got %d", p.Degree())
+	}
+
+	if len(p.Coefficients()) != 3 {
+		t.Errorf("Expected 3 coefficients, got %d", len(p.Coefficients()))
+	}
+}
+
+func TestCoefficientsReturnsCopy(t *testing.T) {
+	p := New(xfes(3, 1, 2, 0))
+	want := New(xfes(3, 1, 2))
+
+	coeffs := p.Coefficients()
+	for i := range coeffs {
+		coeffs[i] = xfield.NewU64(999)
+	}
+	_ = append(coeffs, xfield.One)
+
+	if !p.Equal(want) {
+		t.Fatal("mutating Coefficients() changed the polynomial")
+	}
+	if got := p.Encode(); len(got) != len(want.Encode()) || !got[1].Equal(field.New(3)) {
+		t.Error("mutating Coefficients() changed the encoding")
+	}
+}
+
+func TestZeroOneX(t *testing.T) {
+	if !Zero().IsZero() || Zero().Degree() != -1 {
+		t.Error("Zero() should create zero polynomial of degree -1")
+	}
+	if !One().IsOne() || One().Degree() != 0 {
+		t.Error("One() should create constant polynomial 1")
+	}
+	if X().Degree() != 1 || !X().LeadingCoefficient().IsOne() {
+		t.Error("X() should create polynomial x")
+	}
+}
+
+func TestXPolynomialNormalization(t *testing.T) {
+	p := New(append(xfes(1, 2), xfield.Zero, xfield.Zero))
+
+	if p.Degree() != 1 {
+		t.Errorf("Polynomial with trailing zeros should have degree 1, got %d", p.Degree())
+	}
+}
+
+func TestXPolynomialAddSub(t *testing.T) {
+	// (1 + 2x) + (3 + 4x) = 4 + 6x
+	p1 := New(xfes(1, 2))
+	p2 := New(xfes(3, 4))
+
+	if !p1.Add(p2).Equal(New(xfes(4, 6))) {
+		t.Error("Polynomial addition failed")
+	}
+	if !p1.Add(p2).Sub(p2).Equal(p1) {
+		t.Error("(p1 + p2) - p2 should equal p1")
+	}
+	if !p1.Add(p1.Neg()).IsZero() {
+		t.Error("p + (-p) should be zero")
+	}
+}
+
+func TestXPolynomialMultiplication(t *testing.T) {
+	// (1 + x) * (1 + x) = 1 + 2x + x^2
+	p := New(xfes(1, 1))
+
+	if !p.Mul(p).Equal(New(xfes(1, 2, 1))) {
+		t.Errorf("Polynomial multiplication failed: got %v", p.Mul(p))
+	}
+	if !p.Mul(Zero()).IsZero() {
+		t.Error("p * 0 should be zero")
+	}
+}
+
+func TestXPolynomialScalarMultiplication(t *testing.T) {
+	// 3 * (1 + 2x) = 3 + 6x
+	p := New(xfes(1, 2))
+
+	if !p.ScalarMul(xfield.NewU64(3)).Equal(New(xfes(3, 6))) {
+		t.Error("Scalar multiplication failed")
+	}
+	if !p.ScalarMulConst(field.New(3)).Equal(New(xfes(3, 6))) {
+		t.Error("Base field scalar multiplication failed")
+	}
+}
+
+func TestXPolynomialEvaluation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+	p := randomXPolynomial(rng, 5)
+	x := randomXFieldElement(rng)
+
+	// Compare Horner's method against the explicit power sum
+	expected := xfield.Zero
+	power := xfield.One
+	for _, c := range p.Coefficients() {
+		expected = expected.Add(c.Mul(power))
+		power = power.Mul(x)
+	}
+	if !p.Evaluate(x).Equal(expected) {
+		t.Error("Evaluate does not match power sum")
+	}
+
+	results := p.BatchEvaluate([]xfield.XFieldElement{x, xfield.Zero})
+	if !results[0].Equal(expected) || !results[1].Equal(p.Coefficients()[0]) {
+		t.Error("BatchEvaluate mismatch")
+	}
+}
+
+func TestXPolynomialMonic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+	p := randomXPolynomial(rng, 4)
+	monic := p.Monic()
+
+	if !monic.LeadingCoefficient().IsOne() {
+		t.Error("Monic polynomial should have leading coefficient 1")
+	}
+	if monic.Degree() != p.Degree() {
+		t.Error("Monic polynomial should have same degree")
+	}
+}
+
+func TestXPolynomialMonicChecked(t *testing.T) {
+	p := New([]xfield.XFieldElement{xfield.NewU64(2), xfield.NewU64(4)})
+	monic, err := p.MonicChecked()
+	if err != nil || !monic.Equal(p.Monic()) {
+		t.Errorf("MonicChecked = %v, %v", monic, err)
+	}
+	if _, err := Zero().MonicChecked(); err == nil {
+		t.Error("MonicChecked on zero returned no error")
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+
+	for n := 1; n <= 10; n++ {
+		points := make([][2]xfield.XFieldElement, n)
+		for i := range points {
+			points[i] = [2]xfield.XFieldElement{randomXFieldElement(rng), randomXFieldElement(rng)}
+		}
+
+		p := Interpolate(points)
+		if p.Degree() >= n {
+			t.Errorf("Interpolant through %d points has degree %d", n, p.Degree())
+		}
+		for _, point := range points {
+			if !p.Evaluate(point[0]).Equal(point[1]) {
+				t.Fatalf("Interpolated polynomial doesn't pass through %v", point)
+			}
+		}
+	}
+}
+
+func TestInterpolationPanics(t *testing.T) {
+	tests := []struct {
+		name   string
+		points [][2]xfield.XFieldElement
+	}{
+		{"empty", nil},
+		{"duplicate x", [][2]xfield.XFieldElement{
+			{xfield.NewU64(1), xfield.NewU64(2)},
+			{xfield.NewU64(1), xfield.NewU64(3)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			Interpolate(tt.points)
+		})
+	}
+}
+
+func TestInterpolateChecked(t *testing.T) {
+	points := [][2]xfield.XFieldElement{
+		{xfield.NewU64(1), xfield.NewU64(2)},
+		{xfield.NewU64(3), xfield.NewU64(5)},
+	}
+	p, err := InterpolateChecked(points)
+	if err != nil || !p.Equal(Interpolate(points)) {
+		t.Errorf("InterpolateChecked = %v, %v", p, err)
+	}
+
+	invalid := map[string][][2]xfield.XFieldElement{
+		"empty": nil,
+		"duplicate x": {
+			{xfield.NewU64(1), xfield.NewU64(2)},
+			{xfield.NewU64(1), xfield.NewU64(3)},
+		},
+	}
+	for name, points := range invalid {
+		if _, err := InterpolateChecked(points); err == nil {
+			t.Errorf("%s: InterpolateChecked returned no error", name)
+		}
+	}
+}
+
+func TestZerofier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+	points := []xfield.XFieldElement{randomXFieldElement(rng), randomXFieldElement(rng), randomXFieldElement(rng)}
+	z := Zerofier(points)
+
+	for _, point := range points {
+		if !z.Evaluate(point).IsZero() {
+			t.Errorf("Zerofier should be zero at %v", point)
+		}
+	}
+	if z.Degree() != len(points) {
+		t.Errorf("Zerofier degree should be %d, got %d", len(points), z.Degree())
+	}
+	if !z.LeadingCoefficient().IsOne() {
+		t.Error("Zerofier should be monic")
+	}
+
+	expected := One()
+	for _, point := range points {
+		expected = expected.Mul(New([]xfield.XFieldElement{point.Neg(), xfield.One}))
+	}
+	if !z.Equal(expected) {
+		t.Error("Zerofier does not match product of linear factors")
+	}
+
+	if !Zerofier(nil).IsOne() {
+		t.Error("Zerofier of no points should be 1")
+	}
+}
+
+func TestXPolynomialDivision(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+
+	for trial := 0; trial < 20; trial++ {
+		dividend := randomXPolynomial(rng, 1+rng.Intn(20))
+		divisor := randomXPolynomial(rng, 1+rng.Intn(10))
+
+		quotient, remainder := dividend.Divide(divisor)
+
+		if !quotient.Mul(divisor).Add(remainder).Equal(dividend) {
+			t.Fatal("Division check failed: dividend != quotient * divisor + remainder")
+		}
+		if remainder.Degree() >= divisor.Degree() {
+			t.Fatalf("remainder degree %d not below divisor degree %d", remainder.Degree(), divisor.Degree())
+		}
+	}
+
+	// (x^2 + 2x + 1) / (x + 1) = (x + 1) with remainder 0
+	quotient, remainder := New(xfes(1, 2, 1)).Divide(New(xfes(1, 1)))
+	if !quotient.Equal(New(xfes(1, 1))) || !remainder.IsZero() {
+		t.Error("Expected exact division")
+	}
+}
+
+func TestXPolynomialDivisionByZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	One().Divide(Zero())
+}
+
+func TestXPolynomialDivideChecked(t *testing.T) {
+	p := New([]xfield.XFieldElement{xfield.NewU64(1), xfield.NewU64(2), xfield.NewU64(3)})
+	d := New([]xfield.XFieldElement{xfield.NewU64(5), xfield.NewU64(1)})
+	quotient, remainder, err := p.DivideChecked(d)
+	if err != nil {
+		t.Fatalf("DivideChecked: %v", err)
+	}
+	if !quotient.Mul(d).Add(remainder).Equal(p) {
+		t.Error("quotient * divisor + remainder != p")
+	}
+
+	if _, _, err := p.DivideChecked(Zero()); err == nil {
+		t.Error("DivideChecked by zero returned no error")
+	}
+}
+
+func TestDivideByLinear(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+
+	for numCoefficients := 0; numCoefficients <= 20; numCoefficients++ {
+		p := randomXPolynomial(rng, numCoefficients)
+		z := randomXFieldElement(rng)
+
+		quotient, remainder := p.DivideByLinear(z)
+		if !remainder.Equal(p.Evaluate(z)) {
+			t.Fatal("remainder of division by (x - z) should be p(z)")
+		}
+
+		linear := New([]xfield.XFieldElement{z.Neg(), xfield.One})
+		expectedQuotient, _ := p.Divide(linear)
+		if !quotient.Equal(expectedQuotient) {
+			t.Fatalf("synthetic division disagrees with long division for %d coefficients", numCoefficients)
+		}
+	}
+
+	// A DEEP quotient (p(x) - p(z)) / (x - z) is exact
+	p := randomXPolynomial(rng, 8)
+	z := randomXFieldElement(rng)
+	shifted := p.Sub(New([]xfield.XFieldElement{p.Evaluate(z)}))
+	if _, remainder := shifted.DivideByLinear(z); !remainder.IsZero() {
+		t.Error("expected exact division")
+	}
+}
+
+func TestLiftCommutesWithBaseFieldOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1158))
+
+	for trial := 0; trial < 20; trial++ {
+		a := randomPolynomial(rng, 1+rng.Intn(12))
+		b := randomPolynomial(rng, 1+rng.Intn(8))
+		x := field.New(rng.Uint64())
+
+		if !Lift(a).Add(Lift(b)).Equal(Lift(a.Add(b))) {
+			t.Fatal("lift does not commute with Add")
+		}
+		if !Lift(a).Sub(Lift(b)).Equal(Lift(a.Sub(b))) {
+			t.Fatal("lift does not commute with Sub")
+		}
+		if !Lift(a).Mul(Lift(b)).Equal(Lift(a.Mul(b))) {
+			t.Fatal("lift does not commute with Mul")
+		}
+		if !Lift(a).ScalarMulConst(x).Equal(Lift(a.ScalarMul(x))) {
+			t.Fatal("lift does not commute with ScalarMul")
+		}
+		if !Lift(a).Evaluate(xfield.NewConst(x)).Equal(xfield.NewConst(a.Evaluate(x))) {
+			t.Fatal("lift does not commute with Evaluate")
+		}
+
+		quotient, remainder := Lift(a).Divide(Lift(b))
+		baseQuotient, baseRemainder := a.Divide(b)
+		if !quotient.Equal(Lift(baseQuotient)) || !remainder.Equal(Lift(baseRemainder)) {
+			t.Fatal("lift does not commute with Divide")
+		}
+
+		baseXs := []field.Element{field.New(rng.Uint64()), field.New(rng.Uint64()), field.New(rng.Uint64())}
+		xs := make([]xfield.XFieldElement, len(baseXs))
+		basePoints := make([][2]field.Element, len(baseXs))
+		points := make([][2]xfield.XFieldElement, len(baseXs))
+		for i, bx := range baseXs {
+			xs[i] = xfield.NewConst(bx)
+			basePoints[i] = [2]field.Element{bx, a.Evaluate(bx)}
+			points[i] = [2]xfield.XFieldElement{xs[i], xfield.NewConst(a.Evaluate(bx))}
+		}
+		if !Zerofier(xs).Equal(Lift(polynomial.Zerofier(baseXs))) {
+			t.Fatal("lift does not commute with Zerofier")
+		}
+		if !Interpolate(points).Equal(Lift(polynomial.Interpolate(basePoints))) {
+			t.Fatal("lift does not commute with Interpolate")
+		}
+	}
+}
+
+// Benchmarks
+func BenchmarkDivideByLinear(b *testing.B) {
+	rng := rand.New(rand.NewSource(1158))
+	p := randomXPolynomial(rng, 1<<16+1)
+	z := randomXFieldElement(rng)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = p.DivideByLinear(z)
+	}
+}
+
+func BenchmarkXPolynomialEvaluate(b *testing.B) {
+	rng := rand.New(rand.NewSource(1158))
+	p := randomXPolynomial(rng, 1<<10)
+	z := randomXFieldElement(rng)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.Evaluate(z)
+	}
+}
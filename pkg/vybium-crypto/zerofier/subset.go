@@ -0,0 +1,177 @@
+package zerofier
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+)
+
+// ZerofierScratch is reusable working memory for BuildSmallZerofier. The
+// zero value is ready to use. A ZerofierScratch is not safe for concurrent
+// use.
+type ZerofierScratch struct {
+	coefficients []field.Element
+}
+
+// scratchPool supplies scratch space to callers that pass none.
+var scratchPool = sync.Pool{New: func() any { return new(ZerofierScratch) }}
+
+// BuildSmallZerofier returns the zerofier of the points, equal to
+// polynomial.Zerofier(points). It multiplies the linear factors in place in
+// the scratch space, so the only allocations are those of the result. A
+// nil scratch borrows one from an internal pool.
+//
+// The cost is quadratic in the number of points; for large point sets use a
+// ZerofierTree.
+func BuildSmallZerofier(points []field.Element, scratch *ZerofierScratch) *polynomial.Polynomial {
+	if scratch == nil {
+		scratch = scratchPool.Get().(*ZerofierScratch)
+		defer scratchPool.Put(scratch)
+	}
+	return polynomial.New(scratch.zerofierCoefficients(points))
+}
+
+// zerofierCoefficients returns the coefficients of the zerofier of the
+// points, in the scratch space.
+func (s *ZerofierScratch) zerofierCoefficients(points []field.Element) []field.Element {
+	n := len(points)
+	if cap(s.coefficients) < n+1 {
+		s.coefficients = make([]field.Element, n+1)
+	}
+	c := s.coefficients[:n+1]
+	c[0] = field.One
+	// After k factors, c[0..k] holds their product; multiplying by (x - p)
+	// shifts it up one degree and subtracts p times it.
+	for k, p := range points {
+		c[k+1] = c[k]
+		for j := k; j > 0; j-- {
+			c[j] = c[j-1].Sub(p.Mul(c[j]))
+		}
+		c[0] = p.Mul(c[0]).Neg()
+	}
+	return c
+}
+
+// SubsetZerofier returns the zerofier of the points of the tree's domain at
+// the given indices, equal to polynomial.Zerofier of those points. Only
+// subtrees containing selected points are visited. Leafs whose points are
+// all selected contribute their stored zerofiers, which are multiplied
+// pairwise in a balanced product; the selected points of the other leafs
+// contribute their linear factors, multiplied in place as by
+// BuildSmallZerofier.
+//
+// The tree must have been built by NewZerofierTree, whose domain index i is
+// point i mod RecursionCutoffThreshold of leaf i / RecursionCutoffThreshold.
+// Returns an error if an index is out of range or repeated, or if the tree
+// does not have that shape.
+func SubsetZerofier(tree *ZerofierTree, subsetIndices []int) (*polynomial.Polynomial, error) {
+	if len(subsetIndices) == 0 {
+		return polynomial.One(), nil
+	}
+	if tree == nil {
+		return nil, fmt.Errorf("subset zerofier needs a tree")
+	}
+
+	indices := slices.Clone(subsetIndices)
+	slices.Sort(indices)
+	for i, index := range indices {
+		if index < 0 {
+			return nil, fmt.Errorf("subset index %d out of range", index)
+		}
+		if i > 0 && index == indices[i-1] {
+			return nil, fmt.Errorf("subset index %d repeated", index)
+		}
+	}
+
+	// The tree's leaf slots all sit at the depth of its leftmost leaf.
+	height := 0
+	for node := tree; node.Type == Branch; node = node.Left {
+		height++
+	}
+	if last := indices[len(indices)-1]; last >= RecursionCutoffThreshold<<height {
+		return nil, fmt.Errorf("subset index %d out of range", last)
+	}
+
+	parts := subsetParts{points: make([]field.Element, 0, len(indices))}
+	if err := parts.collect(tree, height, 0, indices); err != nil {
+		return nil, err
+	}
+
+	zerofier := balancedProduct(parts.whole)
+	if len(parts.points) == 0 {
+		return zerofier, nil
+	}
+	scratch := scratchPool.Get().(*ZerofierScratch)
+	defer scratchPool.Put(scratch)
+	small := BuildSmallZerofier(parts.points, scratch)
+	if zerofier == nil {
+		return small, nil
+	}
+	return zerofier.Mul(small), nil
+}
+
+// subsetParts accumulates the factors of a subset zerofier: the zerofiers
+// of wholly selected leafs and the selected points of the other leafs.
+type subsetParts struct {
+	whole  []*polynomial.Polynomial
+	points []field.Element
+}
+
+// collect gathers the factors for the selected points of the subtree of the
+// given height whose first domain index is offset. The indices are sorted,
+// lie in the subtree's range and are not empty.
+func (parts *subsetParts) collect(node *ZerofierTree, height, offset int, indices []int) error {
+	switch {
+	case node == nil || node.Type == Padding:
+		return fmt.Errorf("subset index %d out of range", indices[0])
+	case node.Type == Branch && height > 0:
+		mid := offset + RecursionCutoffThreshold<<(height-1)
+		split, _ := slices.BinarySearch(indices, mid)
+		if split > 0 {
+			if err := parts.collect(node.Left, height-1, offset, indices[:split]); err != nil {
+				return err
+			}
+		}
+		if split < len(indices) {
+			return parts.collect(node.Right, height-1, mid, indices[split:])
+		}
+		return nil
+	case node.Type == Leaf && height == 0:
+		if last := indices[len(indices)-1]; last-offset >= len(node.Points) {
+			return fmt.Errorf("subset index %d out of range", last)
+		}
+		if len(indices) == len(node.Points) {
+			parts.whole = append(parts.whole, node.Zerofier)
+			return nil
+		}
+		for _, index := range indices {
+			parts.points = append(parts.points, node.Points[index-offset])
+		}
+		return nil
+	default:
+		return fmt.Errorf("zerofier tree is not balanced: %v node at height %d", node.Type, height)
+	}
+}
+
+// balancedProduct multiplies the polynomials pairwise, level by level.
+// Returns nil for no polynomials.
+func balancedProduct(polys []*polynomial.Polynomial) *polynomial.Polynomial {
+	if len(polys) == 0 {
+		return nil
+	}
+	level := slices.Clone(polys)
+	for len(level) > 1 {
+		next := level[:0]
+		for i := 0; i+1 < len(level); i += 2 {
+			next = append(next, level[i].Mul(level[i+1]))
+		}
+		if len(level)%2 == 1 {
+			next = append(next, level[len(level)-1])
+		}
+		level = next
+	}
+	return level[0]
+}
@@ -0,0 +1,179 @@
+package zerofier
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/field"
+	"github.com/vybium/vybium-crypto/pkg/vybium-crypto/polynomial"
+)
+
+func randomPoints(rng *rand.Rand, n int) []field.Element {
+	points := make([]field.Element, n)
+	for i := range points {
+		points[i] = field.New(rng.Uint64())
+	}
+	return points
+}
+
+func pointsAt(domain []field.Element, indices []int) []field.Element {
+	points := make([]field.Element, len(indices))
+	for i, index := range indices {
+		points[i] = domain[index]
+	}
+	return points
+}
+
+func TestBuildSmallZerofierMatchesZerofier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1198))
+	var scratch ZerofierScratch
+	// Shrinking and growing sizes exercise reuse of the scratch space.
+	for _, n := range []int{0, 1, 2, 80, 3, 17, 200, 5} {
+		points := randomPoints(rng, n)
+		want := polynomial.Zerofier(points)
+		if got := BuildSmallZerofier(points, &scratch); !got.Equal(want) {
+			t.Fatalf("%d points: differs from polynomial.Zerofier", n)
+		}
+		if got := BuildSmallZerofier(points, nil); !got.Equal(want) {
+			t.Fatalf("%d points, pooled scratch: differs from polynomial.Zerofier", n)
+		}
+	}
+}
+
+func TestBuildSmallZerofierAllocatesOnlyResult(t *testing.T) {
+	points := randomPoints(rand.New(rand.NewSource(1198)), 80)
+	var scratch ZerofierScratch
+	BuildSmallZerofier(points, &scratch)
+	// polynomial.New allocates the polynomial and its coefficients.
+	if allocs := testing.AllocsPerRun(100, func() { BuildSmallZerofier(points, &scratch) }); allocs > 2 {
+		t.Errorf("%v allocations per zerofier, want at most 2", allocs)
+	}
+}
+
+func TestSubsetZerofierMatchesZerofier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1198))
+	for _, size := range []int{1, 16, 17, 100, 1024, 1000} {
+		domain := randomPoints(rng, size)
+		tree := NewZerofierTree(domain)
+		subsets := [][]int{
+			nil,
+			{0},
+			{size - 1},
+			rng.Perm(size)[:min(80, size)],
+			rng.Perm(size),
+		}
+		// Whole leafs, plus a partial one.
+		var whole []int
+		for i := 0; i < min(size, 2*RecursionCutoffThreshold+3); i++ {
+			whole = append(whole, i)
+		}
+		subsets = append(subsets, whole)
+
+		for _, subset := range subsets {
+			got, err := SubsetZerofier(tree, subset)
+			if err != nil {
+				t.Fatalf("domain of %d, subset of %d: %v", size, len(subset), err)
+			}
+			if !got.Equal(polynomial.Zerofier(pointsAt(domain, subset))) {
+				t.Fatalf("domain of %d, subset of %d: differs from polynomial.Zerofier", size, len(subset))
+			}
+		}
+	}
+}
+
+func TestSubsetZerofierDoesNotModifyIndices(t *testing.T) {
+	tree := NewZerofierTree(randomPoints(rand.New(rand.NewSource(1198)), 64))
+	indices := []int{40, 3, 17}
+	if _, err := SubsetZerofier(tree, indices); err != nil {
+		t.Fatal(err)
+	}
+	if indices[0] != 40 || indices[1] != 3 || indices[2] != 17 {
+		t.Errorf("indices reordered: %v", indices)
+	}
+}
+
+func TestSubsetZerofierRejectsInvalidIndices(t *testing.T) {
+	rng := rand.New(rand.NewSource(1198))
+	// 40 points: two full leafs, a leaf of 8 points and a padding slot.
+	tree := NewZerofierTree(randomPoints(rng, 40))
+	for name, indices := range map[string][]int{
+		"negative":           {-1},
+		"past the last leaf": {40},
+		"in the padding":     {50},
+		"past the tree":      {64},
+		"repeated":           {3, 7, 3},
+	} {
+		if _, err := SubsetZerofier(tree, indices); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := SubsetZerofier(NewZerofierTree(nil), []int{0}); err == nil {
+		t.Error("empty tree: expected an error")
+	}
+	if _, err := SubsetZerofier(nil, []int{0}); err == nil {
+		t.Error("nil tree: expected an error")
+	}
+
+	// A hand-built tree whose leafs sit at different depths.
+	leaf := NewLeaf(randomPoints(rng, RecursionCutoffThreshold))
+	unbalanced := NewBranch(NewBranch(leaf, leaf), leaf)
+	if _, err := SubsetZerofier(unbalanced, []int{2 * RecursionCutoffThreshold}); err == nil {
+		t.Error("unbalanced tree: expected an error")
+	}
+}
+
+// The FRI query workload: 1000 zerofiers of 80 points drawn from a domain.
+const (
+	benchZerofiers  = 1000
+	benchSubsetSize = 80
+	benchDomainSize = 1 << 12
+)
+
+func benchSubsets() ([]field.Element, [][]int) {
+	rng := rand.New(rand.NewSource(1198))
+	domain := randomPoints(rng, benchDomainSize)
+	subsets := make([][]int, benchZerofiers)
+	for i := range subsets {
+		subsets[i] = rng.Perm(benchDomainSize)[:benchSubsetSize]
+	}
+	return domain, subsets
+}
+
+func BenchmarkQueryZerofiers(b *testing.B) {
+	domain, subsets := benchSubsets()
+	points := make([][]field.Element, len(subsets))
+	for i, subset := range subsets {
+		points[i] = pointsAt(domain, subset)
+	}
+
+	b.Run("polynomial.Zerofier", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, p := range points {
+				polynomial.Zerofier(p)
+			}
+		}
+	})
+	b.Run("BuildSmallZerofier", func(b *testing.B) {
+		b.ReportAllocs()
+		var scratch ZerofierScratch
+		for i := 0; i < b.N; i++ {
+			for _, p := range points {
+				BuildSmallZerofier(p, &scratch)
+			}
+		}
+	})
+	b.Run("SubsetZerofier", func(b *testing.B) {
+		tree := NewZerofierTree(domain)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, subset := range subsets {
+				if _, err := SubsetZerofier(tree, subset); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
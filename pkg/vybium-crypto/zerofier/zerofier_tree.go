@@ -143,7 +143,9 @@ func NewBranch(left, right *ZerofierTree) *ZerofierTree {
 	}
 }
 
-// GetZerofier returns the zerofier polynomial for this tree node.
+// GetZerofier returns the zerofier polynomial for this tree node. The
+// polynomial is shared with the tree; polynomials are immutable, so callers
+// cannot corrupt the tree through it.
 // Production implementation.
 func (zt *ZerofierTree) GetZerofier() *polynomial.Polynomial {
 	switch zt.Type {
@@ -171,12 +173,12 @@ func (zt *ZerofierTree) IsPadding() bool {
 	return zt.Type == Padding
 }
 
-// GetPoints returns the points contained in this node (only valid for leaf nodes).
+// GetPoints returns a copy of the points contained in this node (only valid for leaf nodes).
 func (zt *ZerofierTree) GetPoints() []field.Element {
 	if zt.Type != Leaf {
 		return nil
 	}
-	return zt.Points
+	return append([]field.Element{}, zt.Points...)
 }
 
 // GetLeft returns the left child (only valid for branch nodes).
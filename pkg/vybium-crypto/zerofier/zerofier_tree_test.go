@@ -532,6 +532,50 @@ func TestZerofierTreeValidate(t *testing.T) {
 	}
 }
 
+// TestAccessorsDoNotExposeTreeState mutates everything the public accessors
+// hand out and checks that the tree, and trees built from it, still vanish
+// on exactly their points.
+func TestAccessorsDoNotExposeTreeState(t *testing.T) {
+	domain := make([]field.Element, 3*RecursionCutoffThreshold)
+	for i := range domain {
+		domain[i] = field.New(uint64(i + 1))
+	}
+	tree := NewZerofierTree(domain)
+	leaf := tree.GetLeft().GetLeft()
+	if !leaf.IsLeaf() {
+		t.Fatalf("expected a leaf, got %v", leaf.Type)
+	}
+
+	points := leaf.GetPoints()
+	for i := range points {
+		points[i] = field.Zero
+	}
+	for _, zerofier := range []*ZerofierTree{leaf, tree} {
+		coeffs := zerofier.GetZerofier().Coefficients()
+		// Normalize in place, as the offending caller did.
+		inverse := coeffs[0].Inverse()
+		for i := range coeffs {
+			coeffs[i] = coeffs[i].Mul(inverse)
+		}
+	}
+
+	if err := tree.Validate(); err != nil {
+		t.Fatalf("tree no longer valid: %v", err)
+	}
+	if !leaf.GetPoints()[0].Equal(domain[0]) {
+		t.Fatal("mutating GetPoints() changed the leaf's points")
+	}
+	extended := NewBranch(tree, NewLeaf([]field.Element{field.New(1000)}))
+	for _, x := range append(domain, field.New(1000)) {
+		if !extended.GetZerofier().Evaluate(x).IsZero() {
+			t.Fatalf("zerofier built on the shared tree does not vanish at %d", x.Value())
+		}
+	}
+	if extended.GetZerofier().Evaluate(field.Zero).IsZero() {
+		t.Fatal("zerofier vanishes at a point outside the domain")
+	}
+}
+
 func TestHelperFunctions(t *testing.T) {
 	// Test nextPowerOfTwo
 	tests := []struct {